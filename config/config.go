@@ -1,10 +1,18 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
@@ -15,14 +23,62 @@ type Config struct {
 	Paper   PaperConfig   `yaml:"paper"`
 	Live    LiveConfig    `yaml:"live"`
 	API     APIConfig     `yaml:"api"`
+	RPC     RPCConfig     `yaml:"rpc"`
+	Oracle  OracleConfig  `yaml:"oracle"`
 	Storage StorageConfig `yaml:"storage"`
 	Log     LogConfig     `yaml:"log"`
+	Health  HealthConfig  `yaml:"health"`
 }
 
 // PaperConfig controla el engine de paper trading.
 type PaperConfig struct {
 	MaxMarkets     int     `yaml:"max_markets"`
 	InitialCapital float64 `yaml:"initial_capital"`
+
+	// Umbrales del engine (los mismos nombres que en live para poder comparar).
+	MinOrderSize      float64 `yaml:"min_order_size"`
+	NearEndHours      float64 `yaml:"near_end_hours"`
+	MaxBidTickUp      float64 `yaml:"max_bid_tick_up"`
+	StaleHours        float64 `yaml:"stale_hours"`
+	CompetitionMult   float64 `yaml:"competition_mult"`
+	PartialAlertHours float64 `yaml:"partial_alert_hours"`
+	MergeGasCost      float64 `yaml:"merge_gas_cost"`
+
+	// Minutos sin volver a entrar en un mercado tras rotar o cancelar un par;
+	// los expirados por near_end quedan excluidos para siempre.
+	RotationCooldownMins int `yaml:"rotation_cooldown_mins"`
+
+	// Horas de histórico de trades que se paginan para simular fills: desde
+	// la orden abierta más antigua, pero nunca más atrás que esto.
+	TradeLookbackHours float64 `yaml:"trade_lookback_hours"`
+
+	// Impacto en el book: una orden mayor que size_dominant_fraction × el
+	// depth ya puesto en su nivel de precio se marca size_dominant y solo se
+	// llena con esa proporción del volumen vendido tras la cola.
+	// max_level_multiple limita cada pata a ese múltiplo del depth del nivel
+	// antes de colocar (0 = sin tope).
+	SizeDominantFraction float64 `yaml:"size_dominant_fraction"`
+	MaxLevelMultiple     float64 `yaml:"max_level_multiple"`
+
+	// Tamaño Kelly del capital desplegable.
+	Kelly KellyConfig `yaml:"kelly"`
+
+	// Al salir, expirar las órdenes virtuales abiertas para empezar limpio.
+	// Sin la clave, true (setDefaults); expire_on_exit: false las conserva.
+	ExpireOnExit *bool `yaml:"expire_on_exit"`
+
+	// Variantes de --sweep: cada una corre su propio paper engine sobre los
+	// mismos scans, con su propio archivo SQLite (ver StorageConfig.SweepDSN).
+	Sweep []SweepVariantConfig `yaml:"sweep"`
+}
+
+// SweepVariantConfig es una variante de --sweep. Los campos a 0 heredan el
+// valor de paper (order_size, el del scanner).
+type SweepVariantConfig struct {
+	Name       string  `yaml:"name"`
+	OrderSize  float64 `yaml:"order_size"` // USDC por lado
+	MaxMarkets int     `yaml:"max_markets"`
+	StaleHours float64 `yaml:"stale_hours"`
 }
 
 // LiveConfig controla el engine de live trading.
@@ -34,17 +90,154 @@ type LiveConfig struct {
 	MinMergeProfit float64 `yaml:"min_merge_profit"`
 	PolygonRPC     string  `yaml:"polygon_rpc"`
 
+	// Tope por mercado como fracción del capital desplegable: órdenes abiertas
+	// más inventario sin mergear. Un par completo siempre cabe.
+	MaxMarketConcentration float64 `yaml:"max_market_concentration"`
+
+	// Gas del merge: margen de seguridad sobre el gas estimado al decidir si un
+	// merge es rentable, gas price (gwei) a usar cuando el RPC no responde y
+	// techo (gwei) por encima del cual los merges se aplazan (0 = sin techo).
+	GasBufferPct         float64 `yaml:"gas_buffer_pct"`
+	GasPriceFallbackGwei float64 `yaml:"gas_price_fallback_gwei"`
+	MaxGasPriceGwei      float64 `yaml:"max_gas_price_gwei"`
+
+	// Reintentos de merge: intentos por ciclo y multiplicador de gas por reintento.
+	MergeMaxAttempts int     `yaml:"merge_max_attempts"`
+	MergeGasBump     float64 `yaml:"merge_gas_bump"`
+
+	// Runway de gas: merges que el POL de la wallet debe poder pagar al gas
+	// actual; por debajo se avisa y no se colocan órdenes nuevas.
+	MinGasRunwayMerges float64 `yaml:"min_gas_runway_merges"`
+
+	// Saldo mínimo (USDC) entre todas las wallets: por debajo, o por debajo
+	// de lo que cuesta un par mínimo, se avisa con lo que falta y no se
+	// colocan órdenes nuevas. Con pause_on_low_balance además se pausa el
+	// circuit breaker durante su cooldown. 0 = solo el chequeo del par mínimo.
+	MinBalanceUSDC    float64 `yaml:"min_balance_usdc"`
+	PauseOnLowBalance bool    `yaml:"pause_on_low_balance"`
+
+	// Límite de pérdida diaria (USDC): si el P&L realizado desde las 00:00 UTC
+	// (merges netos de gas + unwinds) cae a -daily_loss_limit, no se colocan
+	// órdenes nuevas hasta el día siguiente. 0 = desactivado.
+	DailyLossLimit float64 `yaml:"daily_loss_limit"`
+
+	// Merge parcial: sets mínimos para mergear el solapamiento de un par que
+	// aún se está llenando (evita gastar gas en polvo).
+	MinPartialMergeSets float64 `yaml:"min_partial_merge_sets"`
+
+	// Stop-loss: horas que un parcial puede seguir abierto antes de vender el lado lleno.
+	MaxPartialHours float64 `yaml:"max_partial_hours"`
+	UnwindLossTicks int     `yaml:"unwind_loss_ticks"` // ticks bajo entrada para la primera orden SELL
+	UnwindFloorPct  float64 `yaml:"unwind_floor_pct"`  // suelo duro como fracción del precio de entrada
+
+	// Completar parciales como taker: comprar el lado que falta al ask (FOK)
+	// si el merge sigue siendo rentable tras fees y gas al peor nivel del book.
+	AllowTakerCompletion bool    `yaml:"allow_taker_completion"`
+	TakerAfterHours      float64 `yaml:"taker_after_hours"` // horas de parcial antes de tomar el ask
+
+	// NegRisk: permite operar mercados NegRisk, mergeando vía el NegRisk
+	// adapter. El engine activa los merges NegRisk del MergeClient de cada
+	// wallet (EnableNegRisk); sin él esos mercados se siguen saltando.
+	AllowNegRisk bool `yaml:"allow_neg_risk"`
+
+	// Tamaño Kelly del capital desplegable.
+	Kelly KellyConfig `yaml:"kelly"`
+
+	// Al salir (Ctrl+C / SIGTERM) se cancelan TODAS las órdenes abiertas del
+	// CLOB, parciales incluidos, para que nada se llene sin seguimiento.
+	// no_cancel_on_exit: true las deja en el book (ciclos de prueba rápidos).
+	NoCancelOnExit bool `yaml:"no_cancel_on_exit"`
+
+	// Con no_cancel_on_exit, cancelar al salir solo los pares sin ningún fill.
+	// Sin no_cancel_on_exit no tiene efecto (Validate avisa).
+	CancelOnExit bool `yaml:"cancel_on_exit"`
+
+	// Al arrancar, cancelar las órdenes del CLOB que no están en live_orders
+	// (huérfanas). false = solo avisar en el log.
+	CancelOrphans bool `yaml:"cancel_orphans"`
+
+	// Modo shadow (dry-run): el pipeline live completo contra books reales,
+	// pero las órdenes y merges solo se registran (shadow=1), nunca se envían.
+	ShadowMode bool `yaml:"shadow_mode"`
+
+	// Dry-run de colocación: modo shadow (shadow=1) dimensionado con el saldo
+	// real de las wallets. Las órdenes llevan un CLOB ID "DRY-..." y se
+	// loguean con [DRY-RUN], nunca se envían ni se llenan y se rotan tras un
+	// ciclo. Implica shadow_mode.
+	DryRunPlacement bool `yaml:"dry_run_placement"`
+
+	// Canal user del CLOB (WebSocket): fills y cancelaciones en tiempo real.
+	// El binario arranca polymarket.UserStream de la wallet principal y lo
+	// pasa como live.Config.OrderEvents; el polling REST de cada ciclo sigue
+	// como respaldo.
+	UserStream bool `yaml:"user_stream"`
+
+	// Canal market del CLOB (WebSocket): books en tiempo real de los mercados
+	// escaneados para muestrear la estabilidad del spread entre ciclos. El
+	// binario arranca polymarket.BookSubscriber y lo pasa como
+	// live.Config.BookStream.
+	BookStream bool `yaml:"book_stream"`
+
+	// Multi-wallet: cuentas adicionales para repartir capital (los pools de
+	// reward tienen tope por wallet). Vacío = una sola wallet (POLY_PRIVATE_KEY).
+	Wallets []WalletConfig `yaml:"wallets"`
+
 	// Filtros de entrada para el live engine (sobreescribe scanner filter).
 	MaxSpreadTotal float64 `yaml:"max_spread_total"`
 	MaxCompetition float64 `yaml:"max_competition"`
 	OnlyFillsProfit bool   `yaml:"only_fills_profit"`
+
+	// Umbrales de entrada (gate checks).
+	MinVolume24h      float64 `yaml:"min_volume_24h"`
+	MinAskDepthShares float64 `yaml:"min_ask_depth_shares"`
+	MaxSpreadPct      float64 `yaml:"max_spread_pct"`      // spread bid/ask máximo como fracción del midpoint
+	SpreadVarianceMax float64 `yaml:"spread_variance_max"` // coeficiente de variación máximo del spread reciente
+	// Multiplicador de la cola visible delante de un bid nuevo (tamaño oculto
+	// y órdenes que llegan después). Ver --calibrate.
+	QueueConservativeMult float64 `yaml:"queue_conservative_mult"`
+	NearEndHours          float64 `yaml:"near_end_hours"`
+	MaxBidTickUp          float64 `yaml:"max_bid_tick_up"` // cuánto puede subir optimizeBid sobre el mejor bid
+
+	// Rotación y alertas.
+	StaleHours           float64 `yaml:"stale_hours"`
+	CompetitionMult      float64 `yaml:"competition_mult"`
+	PartialAlertHours    float64 `yaml:"partial_alert_hours"`
+	RotationCooldownMins int     `yaml:"rotation_cooldown_mins"` // no volver a entrar en un mercado rotado durante este tiempo
+
+	// Re-pricing de bids que se quedaron atrás en el book (ver live/reprice.go).
+	Reprice          bool    `yaml:"reprice"`
+	RepriceTicks     int     `yaml:"reprice_ticks"`      // mover si el bid está a ≥ N ticks del mejor bid
+	RepriceQueueMult float64 `yaml:"reprice_queue_mult"` // mover si la cola delante crece ×N desde la colocación
+
+	// Circuit breaker.
+	CircuitBreakerLosses       int     `yaml:"circuit_breaker_losses"`
+	CircuitBreakerCooldownMins int     `yaml:"circuit_breaker_cooldown_mins"`
+	CircuitBreakerDrawdownPct  float64 `yaml:"circuit_breaker_drawdown_pct"` // fracción de initial_capital
+}
+
+// WalletConfig describe una wallet adicional del live engine.
+// La clave privada nunca va en el YAML: se lee de la variable de entorno indicada.
+type WalletConfig struct {
+	Name          string  `yaml:"name"`
+	PrivateKeyEnv string  `yaml:"private_key_env"` // p.ej. POLY_PRIVATE_KEY_2
+	MaxExposure   float64 `yaml:"max_exposure"`    // tope de capital desplegado en esta wallet (0 = solo balance)
+}
+
+// PrivateKey devuelve la clave privada de la wallet desde el entorno (sin 0x).
+func (w WalletConfig) PrivateKey() string {
+	return strings.TrimPrefix(os.Getenv(w.PrivateKeyEnv), "0x")
 }
 
 // ScannerConfig controla el comportamiento del scanner.
 type ScannerConfig struct {
+	// Preset de filtros: conservative | balanced | aggressive (vacío = sin preset).
+	// Los campos de filtro definidos explícitamente en el YAML tienen prioridad.
+	Preset string `yaml:"preset"`
+
 	IntervalSeconds      int     `yaml:"interval_seconds"`
 	OrderSizeUSDC        float64 `yaml:"order_size_usdc"`
 	FeeRateDefault       float64 `yaml:"fee_rate_default"`        // default conservador si la API no devuelve fee
+	FeeRateRebated       float64 `yaml:"fee_rate_rebated"`        // fee maker de las órdenes que califican para rewards (0 = sin fee)
 	MinYourDailyReward   float64 `yaml:"min_your_daily_reward"`   // mínimo tu $/día para pasar el filtro
 	MinRewardScore       float64 `yaml:"min_reward_score"`
 	MaxSpreadTotal       float64 `yaml:"max_spread_total"`
@@ -52,6 +245,14 @@ type ScannerConfig struct {
 	RequireQualifies     bool    `yaml:"require_qualifies"`
 	MinHoursToResolution float64 `yaml:"min_hours_to_resolution"` // filtrar mercados que se resuelven pronto
 
+	// Orden de las oportunidades: velocity | reward | fill-cost | breakeven
+	// (vacío = velocity). El flag --rank-by lo sobreescribe.
+	RankBy string `yaml:"rank_by"`
+
+	// Spread máximo por tipo de mercado (categoría de Gamma o prefijo de slug);
+	// sustituye a max_spread_total y al umbral de reward. Gana la más específica.
+	SpreadOverrides map[string]float64 `yaml:"spread_overrides"`
+
 	// Filtro de seguridad
 	OnlyFillsProfit bool `yaml:"only_fills_profit"` // true = descartar mercados donde un fill te cuesta dinero
 
@@ -59,28 +260,172 @@ type ScannerConfig struct {
 	ArbFillsPerDay  float64 `yaml:"arb_fills_per_day"`   // fills estimados/día para cálculo de arb profit
 	GoldMinReward   float64 `yaml:"gold_min_reward"`     // mínimo YourDailyReward para categoría Gold
 	AnalysisWorkers int     `yaml:"analysis_workers"`    // goroutines para análisis paralelo (0 = NumCPU*2)
+
+	// Intervalo adaptativo de scanner, paper y live (adaptive_min_seconds = 0 lo desactiva)
+	AdaptiveMinSeconds  int     `yaml:"adaptive_min_seconds"`   // intervalo con fills recientes
+	AdaptiveMaxSeconds  int     `yaml:"adaptive_max_seconds"`   // techo sin actividad
+	AdaptiveFilledBoost float64 `yaml:"adaptive_filled_boost"`  // divisor con fills (≤ 1 = directo al mínimo)
+	AdaptiveIdleScaleUp float64 `yaml:"adaptive_idle_scale_up"` // multiplicador tras 3 ciclos sin fills
+	AdaptiveGoldBusy    int     `yaml:"adaptive_gold_busy"`     // Gold en el último scan que acortan el intervalo (0 = ignorar)
+	// Caché de metadata de mercados: solo los orderbooks se piden cada ciclo
+	MarketCacheMinutes int `yaml:"market_cache_minutes"` // 0 = refrescar la lista en cada ciclo
+
+	// Lista negra/blanca de mercados: la respetan el scanner y ambos engines
+	ExcludeMarkets MarketMatchConfig `yaml:"exclude_markets"`
+	IncludeMarkets MarketMatchConfig `yaml:"include_markets"`  // vacío = todos los no excluidos
+	MarketListFile string            `yaml:"market_list_file"` // YAML con exclude/include; se relee al cambiar
+
+	// Filtro por palabras clave en la pregunta (substring, sin distinguir mayúsculas)
+	IncludeKeywords []string `yaml:"include_keywords"` // vacío = todos
+	ExcludeKeywords []string `yaml:"exclude_keywords"` // gana sobre include_keywords
+
+	// Ladder de órdenes por mercado (paper y live)
+	Ladder LadderConfig `yaml:"ladder"`
+
+	// Directorio donde grabar cada ciclo como JSON para --replay
+	// (scanner.Recorder). Vacío = no grabar.
+	RecordDir string `yaml:"record_dir"`
+}
+
+// RankByNames son los valores aceptados en scanner.rank_by (vacío = velocity);
+// el binario los traduce con scanner.ParseRankBy.
+var RankByNames = []string{"", "velocity", "reward", "fill-cost", "breakeven"}
+
+// LadderConfig reparte el tamaño de orden de cada mercado en varios pares a
+// distintos ticks por debajo del bid optimizado.
+type LadderConfig struct {
+	NumLevels        int       `yaml:"num_levels"`        // ≤ 1 = un solo par, sin ladder
+	TickSpacing      float64   `yaml:"tick_spacing"`      // distancia entre niveles (0.01 = 1 tick)
+	SizeDistribution []float64 `yaml:"size_distribution"` // fracción por nivel; vacío = a partes iguales
+}
+
+// KellyConfig ajusta cómo un engine pasa del Kelly completo de su histórico
+// de merges a la fracción del bankroll que despliega: se multiplica por
+// multiplier (0.5 = half-Kelly, 0.25 = quarter-Kelly) y se acota a [min, max].
+// Hasta warmup_merges merges se usa la fracción por defecto del engine.
+type KellyConfig struct {
+	Multiplier   float64 `yaml:"multiplier"`
+	Min          float64 `yaml:"min"`
+	Max          float64 `yaml:"max"`
+	WarmupMerges int     `yaml:"warmup_merges"`
+}
+
+// withDefaults rellena con def los campos a cero.
+func (k KellyConfig) withDefaults(def KellyConfig) KellyConfig {
+	if k.Multiplier == 0 {
+		k.Multiplier = def.Multiplier
+	}
+	if k.Min == 0 {
+		k.Min = def.Min
+	}
+	if k.Max == 0 {
+		k.Max = def.Max
+	}
+	if k.WarmupMerges == 0 {
+		k.WarmupMerges = def.WarmupMerges
+	}
+	return k
+}
+
+// MarketMatchConfig selecciona mercados por slug, condition ID o regex sobre
+// la pregunta (sin distinguir mayúsculas).
+type MarketMatchConfig struct {
+	Slugs        []string `yaml:"slugs"`
+	ConditionIDs []string `yaml:"condition_ids"`
+	Questions    []string `yaml:"questions"`
 }
 
 // APIConfig contiene los base URLs de las APIs.
 type APIConfig struct {
 	CLOBBase  string `yaml:"clob_base"`
 	GammaBase string `yaml:"gamma_base"`
+
+	// Peticiones/segundo al CLOB (Client.SetCLOBRate). 0 = límite por defecto
+	// del cliente, al 60% del documentado.
+	CLOBRatePerSec float64 `yaml:"clob_rate_per_sec"`
+
+	// Pool de FetchOrderBooks (Client.SetBookFetch): batches de /books en
+	// paralelo y timeout de cada uno. 0 = por defecto (8 y 5000 ms).
+	BookFetchWorkers   int `yaml:"book_fetch_workers"`
+	BookFetchTimeoutMs int `yaml:"book_fetch_timeout_ms"`
+
+	// Dirección de la API JSON de solo lectura (httpapi), p. ej.
+	// "127.0.0.1:8080". Vacío = no se levanta el servidor.
+	ListenAddr string `yaml:"listen_addr"`
 }
 
+// RPCConfig contiene los RPC de Polygon de respaldo. El primario es
+// live.polygon_rpc; los fallbacks se prueban en orden cuando falla.
+type RPCConfig struct {
+	Fallbacks []string `yaml:"fallbacks"`
+}
+
+// PolygonRPCs devuelve los RPC de Polygon en orden de preferencia: el
+// primario y después los fallbacks.
+func (c *Config) PolygonRPCs() []string {
+	return append([]string{c.Live.PolygonRPC}, c.RPC.Fallbacks...)
+}
+
+// OracleConfig elige las fuentes del precio POL/USD con el que se pasa el
+// gas a USD. Se prueban en orden; la API key de CoinMarketCap se lee del
+// entorno, nunca del YAML.
+type OracleConfig struct {
+	Providers           []string `yaml:"providers"`             // chainlink | coingecko | coinmarketcap | uniswap
+	CoinMarketCapKeyEnv string   `yaml:"coinmarketcap_key_env"` // p.ej. CMC_API_KEY
+	UniswapPool         string   `yaml:"uniswap_pool"`          // pool POL/USDC (vacío = WPOL/USDC.e 0.3%)
+	TWAPMinutes         int      `yaml:"twap_minutes"`          // ventana del TWAP de Uniswap
+	ChainlinkFeed       string   `yaml:"chainlink_feed"`        // agregador POL/USD (vacío = el de Polygon)
+}
+
+// OracleProviderNames son los proveedores aceptados en oracle.providers, los
+// mismos que construye onchain.NewPriceOracle.
+var OracleProviderNames = []string{"chainlink", "coingecko", "coinmarketcap", "uniswap"}
+
 // StorageConfig controla dónde se persisten los datos.
 type StorageConfig struct {
 	DSN string `yaml:"dsn"` // ruta al archivo SQLite, o ":memory:"
 }
 
+// SweepDSN devuelve el archivo SQLite de una variante de --sweep junto al
+// principal: data/polybot.db → data/polybot.sweep-<name>.db.
+func (s StorageConfig) SweepDSN(name string) string {
+	if s.DSN == ":memory:" {
+		return s.DSN
+	}
+	ext := filepath.Ext(s.DSN)
+	return strings.TrimSuffix(s.DSN, ext) + ".sweep-" + name + ext
+}
+
 // LogConfig controla el formato y nivel de logging.
 type LogConfig struct {
 	Level  string `yaml:"level"`  // debug | info | warn | error
 	Format string `yaml:"format"` // text | json
 }
 
+// HealthConfig fija cuánto puede pasar sin éxito cada componente antes de
+// que /health responda 503 (segundos; 0 = no se comprueba).
+type HealthConfig struct {
+	MaxScanAgeSeconds  int `yaml:"max_scan_age_seconds"`
+	MaxCycleAgeSeconds int `yaml:"max_cycle_age_seconds"` // ciclo paper o live, según el modo
+	MaxCLOBAgeSeconds  int `yaml:"max_clob_age_seconds"`
+	MaxRPCAgeSeconds   int `yaml:"max_rpc_age_seconds"`
+}
+
 // Load carga la configuración desde el archivo YAML y el archivo .env si existe.
 // Los valores del .env sobreescriben los del YAML para las keys que correspondan.
 func Load(path string) (*Config, error) {
+	cfg, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config.Load: invalid config %q:\n%w", path, err)
+	}
+	return cfg, nil
+}
+
+// load lee el YAML, aplica preset, entorno y defaults, sin validar.
+func load(path string) (*Config, error) {
 	// Cargar .env si existe (silencia error si no hay archivo)
 	_ = godotenv.Load()
 
@@ -94,17 +439,254 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("config.Load: parse YAML: %w", err)
 	}
 
+	if err := applyPreset(&cfg, data); err != nil {
+		return nil, fmt.Errorf("config.Load: %w", err)
+	}
+
 	applyEnvOverrides(&cfg)
 	setDefaults(&cfg)
 
 	return &cfg, nil
 }
 
+// Validate comprueba los invariantes de la configuración ya con defaults
+// aplicados. Devuelve todos los problemas a la vez (uno por línea), no solo
+// el primero, para poder corregir el YAML de una pasada.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	sc := c.Scanner
+	check(sc.IntervalSeconds > 0, "scanner.interval_seconds must be > 0 (got %d)", sc.IntervalSeconds)
+	check(sc.OrderSizeUSDC > 0, "scanner.order_size_usdc must be > 0 (got %g)", sc.OrderSizeUSDC)
+	check(sc.FeeRateDefault >= 0 && sc.FeeRateDefault < 1, "scanner.fee_rate_default must be in [0, 1) (got %g)", sc.FeeRateDefault)
+	check(sc.FeeRateRebated >= 0 && sc.FeeRateRebated < 1, "scanner.fee_rate_rebated must be in [0, 1) (got %g)", sc.FeeRateRebated)
+	check(sc.MaxSpreadTotal >= 0 && sc.MaxSpreadTotal <= 1, "scanner.max_spread_total must be in [0, 1] (got %g)", sc.MaxSpreadTotal)
+	check(slices.Contains(RankByNames, sc.RankBy),
+		"scanner.rank_by must be velocity|reward|fill-cost|breakeven (got %q)", sc.RankBy)
+	for key, v := range sc.SpreadOverrides {
+		check(v > 0 && v <= 1, "scanner.spread_overrides[%q] must be in (0, 1] (got %g)", key, v)
+	}
+	check(sc.MaxCompetition >= 0, "scanner.max_competition must be >= 0 (got %g)", sc.MaxCompetition)
+	check(sc.MinHoursToResolution >= 0, "scanner.min_hours_to_resolution must be >= 0 (got %g)", sc.MinHoursToResolution)
+	check(sc.AnalysisWorkers >= 0, "scanner.analysis_workers must be >= 0 (got %d)", sc.AnalysisWorkers)
+	check(sc.MarketCacheMinutes >= 0, "scanner.market_cache_minutes must be >= 0 (got %d)", sc.MarketCacheMinutes)
+	check(sc.AdaptiveMinSeconds >= 0, "scanner.adaptive_min_seconds must be >= 0 (got %d)", sc.AdaptiveMinSeconds)
+	check(sc.AdaptiveMinSeconds == 0 || sc.AdaptiveMaxSeconds >= sc.AdaptiveMinSeconds,
+		"scanner.adaptive_max_seconds must be >= adaptive_min_seconds (got %d < %d)", sc.AdaptiveMaxSeconds, sc.AdaptiveMinSeconds)
+	check(sc.AdaptiveGoldBusy >= 0, "scanner.adaptive_gold_busy must be >= 0 (got %d)", sc.AdaptiveGoldBusy)
+	for _, m := range []struct {
+		key   string
+		match MarketMatchConfig
+	}{{"exclude_markets", sc.ExcludeMarkets}, {"include_markets", sc.IncludeMarkets}} {
+		for i, q := range m.match.Questions {
+			_, err := regexp.Compile("(?i)" + q)
+			check(err == nil, "scanner.%s.questions[%d] must be a valid regex (got %q)", m.key, i, q)
+		}
+	}
+	if sc.MarketListFile != "" {
+		_, err := os.Stat(sc.MarketListFile)
+		check(err == nil, "scanner.market_list_file: %v", err)
+	}
+	if ld := sc.Ladder; ld.NumLevels > 1 {
+		check(ld.TickSpacing > 0 && ld.TickSpacing < 1, "scanner.ladder.tick_spacing must be in (0, 1) (got %g)", ld.TickSpacing)
+		check(len(ld.SizeDistribution) == 0 || len(ld.SizeDistribution) == ld.NumLevels,
+			"scanner.ladder.size_distribution must have num_levels entries (got %d, want %d)", len(ld.SizeDistribution), ld.NumLevels)
+		for i, f := range ld.SizeDistribution {
+			check(f > 0, "scanner.ladder.size_distribution[%d] must be > 0 (got %g)", i, f)
+		}
+	}
+
+	lc := c.Live
+	check(lc.OrderSize > 0, "live.order_size must be > 0 (got %g)", lc.OrderSize)
+	check(lc.MinMergeProfit > 0, "live.min_merge_profit must be > 0 (got %g)", lc.MinMergeProfit)
+	check(lc.GasBufferPct >= 0 && lc.GasBufferPct <= 1, "live.gas_buffer_pct must be in [0, 1] (got %g)", lc.GasBufferPct)
+	check(lc.MaxMarketConcentration >= 0 && lc.MaxMarketConcentration <= 1, "live.max_market_concentration must be in [0, 1] (got %g)", lc.MaxMarketConcentration)
+	check(lc.MinGasRunwayMerges >= 0, "live.min_gas_runway_merges must be >= 0 (got %g)", lc.MinGasRunwayMerges)
+	check(lc.DailyLossLimit >= 0, "live.daily_loss_limit must be >= 0 (got %g)", lc.DailyLossLimit)
+	check(lc.MinBalanceUSDC >= 0, "live.min_balance_usdc must be >= 0 (got %g)", lc.MinBalanceUSDC)
+	check(lc.GasPriceFallbackGwei > 0, "live.gas_price_fallback_gwei must be > 0 (got %g)", lc.GasPriceFallbackGwei)
+	check(lc.MaxGasPriceGwei >= 0, "live.max_gas_price_gwei must be >= 0 (got %g)", lc.MaxGasPriceGwei)
+	check(lc.MaxSpreadTotal > 0 && lc.MaxSpreadTotal <= 1, "live.max_spread_total must be in (0, 1] (got %g)", lc.MaxSpreadTotal)
+	check(lc.MaxCompetition > 0, "live.max_competition must be > 0 (got %g)", lc.MaxCompetition)
+	check(lc.MaxSpreadPct <= 1, "live.max_spread_pct must be <= 1 (got %g)", lc.MaxSpreadPct)
+	check(lc.MaxBidTickUp < 1, "live.max_bid_tick_up must be < 1.0 (got %g)", lc.MaxBidTickUp)
+	check(lc.CompetitionMult > 1, "live.competition_mult must be > 1 (got %g)", lc.CompetitionMult)
+	check(lc.StaleHours > 0, "live.stale_hours must be > 0 (got %g)", lc.StaleHours)
+	check(lc.RepriceTicks >= 0, "live.reprice_ticks must be >= 0 (got %d)", lc.RepriceTicks)
+	check(lc.RepriceQueueMult == 0 || lc.RepriceQueueMult > 1, "live.reprice_queue_mult must be > 1 (got %g)", lc.RepriceQueueMult)
+	check(lc.CircuitBreakerDrawdownPct < 1, "live.circuit_breaker_drawdown_pct must be < 1 (got %g)", lc.CircuitBreakerDrawdownPct)
+	check(!lc.AllowTakerCompletion || lc.TakerAfterHours < lc.MaxPartialHours,
+		"live.taker_after_hours must be < max_partial_hours (got %g >= %g)", lc.TakerAfterHours, lc.MaxPartialHours)
+	for i, w := range lc.Wallets {
+		check(w.PrivateKeyEnv != "", "live.wallets[%d]: private_key_env is required", i)
+		check(w.MaxExposure >= 0, "live.wallets[%d]: max_exposure must be >= 0 (got %g)", i, w.MaxExposure)
+	}
+
+	pc := c.Paper
+	check(pc.MaxBidTickUp < 1, "paper.max_bid_tick_up must be < 1.0 (got %g)", pc.MaxBidTickUp)
+	check(pc.CompetitionMult > 1, "paper.competition_mult must be > 1 (got %g)", pc.CompetitionMult)
+	check(pc.StaleHours > 0, "paper.stale_hours must be > 0 (got %g)", pc.StaleHours)
+	check(pc.TradeLookbackHours > 0, "paper.trade_lookback_hours must be > 0 (got %g)", pc.TradeLookbackHours)
+	check(pc.SizeDominantFraction > 0, "paper.size_dominant_fraction must be > 0 (got %g)", pc.SizeDominantFraction)
+	check(pc.MaxLevelMultiple >= 0, "paper.max_level_multiple must be >= 0 (got %g)", pc.MaxLevelMultiple)
+	sweepNames := make(map[string]bool, len(pc.Sweep))
+	for i, sv := range pc.Sweep {
+		check(sv.Name != "", "paper.sweep[%d]: name is required", i)
+		check(!sweepNames[sv.Name], "paper.sweep[%d]: duplicate name %q", i, sv.Name)
+		check(sv.OrderSize >= 0, "paper.sweep[%d]: order_size must be >= 0 (got %g)", i, sv.OrderSize)
+		check(sv.MaxMarkets >= 0, "paper.sweep[%d]: max_markets must be >= 0 (got %d)", i, sv.MaxMarkets)
+		check(sv.StaleHours >= 0, "paper.sweep[%d]: stale_hours must be >= 0 (got %g)", i, sv.StaleHours)
+		sweepNames[sv.Name] = true
+	}
+
+	for _, kc := range []struct {
+		section string
+		k       KellyConfig
+	}{{"paper", pc.Kelly}, {"live", lc.Kelly}} {
+		k := kc.k
+		check(k.Multiplier > 0 && k.Multiplier <= 1, "%s.kelly.multiplier must be in (0, 1] (got %g)", kc.section, k.Multiplier)
+		check(k.Min > 0, "%s.kelly.min must be > 0 (got %g)", kc.section, k.Min)
+		check(k.Min <= k.Max, "%s.kelly.min must be <= max (got %g > %g)", kc.section, k.Min, k.Max)
+		check(k.WarmupMerges > 0, "%s.kelly.warmup_merges must be > 0 (got %d)", kc.section, k.WarmupMerges)
+	}
+
+	if err := validateBaseURL(c.API.CLOBBase); err != nil {
+		errs = append(errs, fmt.Errorf("api.clob_base: %w", err))
+	}
+	if err := validateBaseURL(c.API.GammaBase); err != nil {
+		errs = append(errs, fmt.Errorf("api.gamma_base: %w", err))
+	}
+	check(c.API.CLOBRatePerSec >= 0, "api.clob_rate_per_sec must be >= 0 (got %g)", c.API.CLOBRatePerSec)
+	check(c.API.BookFetchWorkers >= 0, "api.book_fetch_workers must be >= 0 (got %d)", c.API.BookFetchWorkers)
+	check(c.API.BookFetchTimeoutMs >= 0, "api.book_fetch_timeout_ms must be >= 0 (got %d)", c.API.BookFetchTimeoutMs)
+	if c.API.ListenAddr != "" {
+		_, _, err := net.SplitHostPort(c.API.ListenAddr)
+		check(err == nil, "api.listen_addr must be host:port (got %q)", c.API.ListenAddr)
+	}
+	if err := validateBaseURL(c.Live.PolygonRPC); err != nil {
+		errs = append(errs, fmt.Errorf("live.polygon_rpc: %w", err))
+	}
+	for i, u := range c.RPC.Fallbacks {
+		if err := validateBaseURL(u); err != nil {
+			errs = append(errs, fmt.Errorf("rpc.fallbacks[%d]: %w", i, err))
+		}
+	}
+
+	oc := c.Oracle
+	check(len(oc.Providers) > 0, "oracle.providers must not be empty")
+	for i, p := range oc.Providers {
+		check(slices.Contains(OracleProviderNames, p),
+			"oracle.providers[%d] must be chainlink|coingecko|coinmarketcap|uniswap (got %q)", i, p)
+		if p == "coinmarketcap" {
+			check(oc.CoinMarketCapKeyEnv != "", "oracle.coinmarketcap_key_env is required by provider coinmarketcap")
+		}
+	}
+	check(oc.TWAPMinutes > 0, "oracle.twap_minutes must be > 0 (got %d)", oc.TWAPMinutes)
+	if oc.UniswapPool != "" {
+		check(common.IsHexAddress(oc.UniswapPool), "oracle.uniswap_pool must be a hex address (got %q)", oc.UniswapPool)
+	}
+	if oc.ChainlinkFeed != "" {
+		check(common.IsHexAddress(oc.ChainlinkFeed), "oracle.chainlink_feed must be a hex address (got %q)", oc.ChainlinkFeed)
+	}
+
+	check(strings.TrimSpace(c.Storage.DSN) != "", "storage.dsn must not be empty")
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be debug|info|warn|error (got %q)", c.Log.Level))
+	}
+	hc := c.Health
+	check(hc.MaxScanAgeSeconds >= 0 && hc.MaxCycleAgeSeconds >= 0 && hc.MaxCLOBAgeSeconds >= 0 && hc.MaxRPCAgeSeconds >= 0,
+		"health.max_*_age_seconds must be >= 0")
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log.format must be text|json (got %q)", c.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateBaseURL exige una URL absoluta con esquema y host.
+func validateBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: scheme and host required", raw)
+	}
+	return nil
+}
+
 // ScanInterval devuelve el intervalo de escaneo como time.Duration.
 func (c *Config) ScanInterval() time.Duration {
 	return time.Duration(c.Scanner.IntervalSeconds) * time.Second
 }
 
+// MarketCacheTTL devuelve cuánto se reutiliza la lista de mercados entre ciclos.
+func (c *Config) MarketCacheTTL() time.Duration {
+	return time.Duration(c.Scanner.MarketCacheMinutes) * time.Minute
+}
+
+// BookFetchTimeout devuelve el timeout de cada batch de /books (0 = por defecto).
+func (c *Config) BookFetchTimeout() time.Duration {
+	return time.Duration(c.API.BookFetchTimeoutMs) * time.Millisecond
+}
+
+// applyPreset rellena los filtros del scanner con los valores del preset,
+// excepto los que el YAML define explícitamente.
+func applyPreset(cfg *Config, data []byte) error {
+	if cfg.Scanner.Preset == "" {
+		return nil
+	}
+	preset, ok := scannerPresets[cfg.Scanner.Preset]
+	if !ok {
+		return fmt.Errorf("unknown scanner.preset %q (conservative|balanced|aggressive)", cfg.Scanner.Preset)
+	}
+
+	var raw struct {
+		Scanner map[string]any `yaml:"scanner"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parse YAML: %w", err)
+	}
+	isSet := func(key string) bool {
+		_, ok := raw.Scanner[key]
+		return ok
+	}
+
+	sc := &cfg.Scanner
+	if !isSet("min_your_daily_reward") {
+		sc.MinYourDailyReward = preset.MinYourDailyReward
+	}
+	if !isSet("min_reward_score") {
+		sc.MinRewardScore = preset.MinRewardScore
+	}
+	if !isSet("max_spread_total") {
+		sc.MaxSpreadTotal = preset.MaxSpreadTotal
+	}
+	if !isSet("max_competition") {
+		sc.MaxCompetition = preset.MaxCompetition
+	}
+	if !isSet("require_qualifies") {
+		sc.RequireQualifies = preset.RequireQualifies
+	}
+	if !isSet("min_hours_to_resolution") {
+		sc.MinHoursToResolution = preset.MinHoursToResolution
+	}
+	if !isSet("only_fills_profit") {
+		sc.OnlyFillsProfit = preset.OnlyFillsProfit
+	}
+	return nil
+}
+
 // applyEnvOverrides sobreescribe valores con variables de entorno si están presentes.
 func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("LOG_LEVEL"); v != "" {
@@ -135,9 +717,45 @@ func setDefaults(cfg *Config) {
 	if cfg.Paper.MaxMarkets <= 0 {
 		cfg.Paper.MaxMarkets = 10
 	}
+	if cfg.Paper.ExpireOnExit == nil {
+		expire := true
+		cfg.Paper.ExpireOnExit = &expire
+	}
 	if cfg.Paper.InitialCapital <= 0 {
 		cfg.Paper.InitialCapital = 1000
 	}
+	if cfg.Paper.MinOrderSize <= 0 {
+		cfg.Paper.MinOrderSize = 10
+	}
+	if cfg.Paper.NearEndHours <= 0 {
+		cfg.Paper.NearEndHours = 24
+	}
+	if cfg.Paper.MaxBidTickUp <= 0 {
+		cfg.Paper.MaxBidTickUp = 0.03
+	}
+	if cfg.Paper.StaleHours <= 0 {
+		cfg.Paper.StaleHours = 4
+	}
+	if cfg.Paper.CompetitionMult <= 0 {
+		cfg.Paper.CompetitionMult = 3
+	}
+	if cfg.Paper.PartialAlertHours <= 0 {
+		cfg.Paper.PartialAlertHours = 6
+	}
+	if cfg.Paper.MergeGasCost <= 0 {
+		cfg.Paper.MergeGasCost = 0.02
+	}
+	if cfg.Paper.RotationCooldownMins <= 0 {
+		cfg.Paper.RotationCooldownMins = 120
+	}
+	if cfg.Paper.TradeLookbackHours <= 0 {
+		cfg.Paper.TradeLookbackHours = 24
+	}
+	if cfg.Paper.SizeDominantFraction <= 0 {
+		cfg.Paper.SizeDominantFraction = 1.0
+	}
+	cfg.Paper.Kelly = cfg.Paper.Kelly.withDefaults(KellyConfig{Multiplier: 0.5, Min: 0.25, Max: 1.0, WarmupMerges: 5})
+	cfg.Live.Kelly = cfg.Live.Kelly.withDefaults(KellyConfig{Multiplier: 0.5, Min: 0.1, Max: 0.8, WarmupMerges: 3})
 	if cfg.Live.OrderSize <= 0 {
 		cfg.Live.OrderSize = 5
 	}
@@ -153,6 +771,36 @@ func setDefaults(cfg *Config) {
 	if cfg.Live.MinMergeProfit <= 0 {
 		cfg.Live.MinMergeProfit = 0.05
 	}
+	if cfg.Live.MaxMarketConcentration <= 0 {
+		cfg.Live.MaxMarketConcentration = 0.15
+	}
+	if cfg.Live.GasBufferPct <= 0 {
+		cfg.Live.GasBufferPct = 0.10
+	}
+	if cfg.Live.MinGasRunwayMerges <= 0 {
+		cfg.Live.MinGasRunwayMerges = 10
+	}
+	if cfg.Live.GasPriceFallbackGwei <= 0 {
+		cfg.Live.GasPriceFallbackGwei = 100
+	}
+	if cfg.Live.MinPartialMergeSets <= 0 {
+		cfg.Live.MinPartialMergeSets = 5
+	}
+	if cfg.Live.MergeMaxAttempts <= 0 {
+		cfg.Live.MergeMaxAttempts = 3
+	}
+	if cfg.Live.MergeGasBump < 1.1 {
+		cfg.Live.MergeGasBump = 1.25
+	}
+	if cfg.Live.MaxPartialHours <= 0 {
+		cfg.Live.MaxPartialHours = 12
+	}
+	if cfg.Live.UnwindLossTicks <= 0 {
+		cfg.Live.UnwindLossTicks = 2
+	}
+	if cfg.Live.UnwindFloorPct <= 0 || cfg.Live.UnwindFloorPct >= 1 {
+		cfg.Live.UnwindFloorPct = 0.50
+	}
 	if cfg.Live.PolygonRPC == "" {
 		cfg.Live.PolygonRPC = "https://polygon-rpc.com"
 	}
@@ -162,12 +810,63 @@ func setDefaults(cfg *Config) {
 	if cfg.Live.MaxCompetition <= 0 {
 		cfg.Live.MaxCompetition = 100_000
 	}
+	if cfg.Live.MinVolume24h <= 0 {
+		cfg.Live.MinVolume24h = 5000
+	}
+	if cfg.Live.MinAskDepthShares <= 0 {
+		cfg.Live.MinAskDepthShares = 10
+	}
+	if cfg.Live.MaxSpreadPct <= 0 {
+		cfg.Live.MaxSpreadPct = 0.60
+	}
+	if cfg.Live.SpreadVarianceMax <= 0 {
+		cfg.Live.SpreadVarianceMax = 0.10
+	}
+	if cfg.Live.QueueConservativeMult <= 0 {
+		cfg.Live.QueueConservativeMult = 1.5
+	}
+	if cfg.Live.NearEndHours <= 0 {
+		cfg.Live.NearEndHours = 24
+	}
+	if cfg.Live.MaxBidTickUp <= 0 {
+		cfg.Live.MaxBidTickUp = 0.45
+	}
+	if cfg.Live.StaleHours <= 0 {
+		cfg.Live.StaleHours = 4
+	}
+	if cfg.Live.CompetitionMult <= 0 {
+		cfg.Live.CompetitionMult = 3
+	}
+	if cfg.Live.PartialAlertHours <= 0 {
+		cfg.Live.PartialAlertHours = 6
+	}
+	if cfg.Live.CircuitBreakerLosses <= 0 {
+		cfg.Live.CircuitBreakerLosses = 3
+	}
+	if cfg.Live.RotationCooldownMins <= 0 {
+		cfg.Live.RotationCooldownMins = 120
+	}
+	if cfg.Live.CircuitBreakerCooldownMins <= 0 {
+		cfg.Live.CircuitBreakerCooldownMins = 30
+	}
+	if cfg.Live.CircuitBreakerDrawdownPct <= 0 {
+		cfg.Live.CircuitBreakerDrawdownPct = 0.05
+	}
 	if cfg.API.CLOBBase == "" {
 		cfg.API.CLOBBase = "https://clob.polymarket.com"
 	}
 	if cfg.API.GammaBase == "" {
 		cfg.API.GammaBase = "https://gamma-api.polymarket.com"
 	}
+	if len(cfg.Oracle.Providers) == 0 {
+		cfg.Oracle.Providers = []string{"chainlink", "coingecko", "uniswap"}
+	}
+	if cfg.Oracle.CoinMarketCapKeyEnv == "" {
+		cfg.Oracle.CoinMarketCapKeyEnv = "CMC_API_KEY"
+	}
+	if cfg.Oracle.TWAPMinutes <= 0 {
+		cfg.Oracle.TWAPMinutes = 30
+	}
 	if cfg.Storage.DSN == "" {
 		cfg.Storage.DSN = "polybot.db"
 	}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_ExitCleanupOnByDefault(t *testing.T) {
+	load := func(t *testing.T, yaml string) *Config {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+		cfg, err := load(path)
+		require.NoError(t, err)
+		return cfg
+	}
+
+	cfg := load(t, "scanner:\n  interval_seconds: 60\n")
+	require.NotNil(t, cfg.Paper.ExpireOnExit)
+	assert.True(t, *cfg.Paper.ExpireOnExit, "a config without the key expires paper orders")
+	assert.False(t, cfg.Live.NoCancelOnExit, "live orders are cancelled unless opted out")
+
+	cfg = load(t, "paper:\n  expire_on_exit: false\nlive:\n  no_cancel_on_exit: true\n")
+	assert.False(t, *cfg.Paper.ExpireOnExit)
+	assert.True(t, cfg.Live.NoCancelOnExit)
+}
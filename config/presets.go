@@ -0,0 +1,58 @@
+package config
+
+// scannerPreset son los filtros del scanner que fija scanner.preset; el YAML
+// puede sobreescribir cualquiera de ellos (ver applyPreset).
+type scannerPreset struct {
+	MinYourDailyReward   float64
+	MinRewardScore       float64
+	MaxSpreadTotal       float64
+	MaxCompetition       float64
+	RequireQualifies     bool
+	MinHoursToResolution float64
+	OnlyFillsProfit      bool
+}
+
+// scannerPresets son los presets de filtrado disponibles en config.yaml
+// (scanner.preset).
+var scannerPresets = map[string]scannerPreset{
+	// conservative: para empezar con capital real. Solo mercados donde cada
+	// fill deja dinero (YES+NO < $1) y el spread es tan estrecho que el par se
+	// completa rápido. 7 días hasta resolución dan margen para rotar varias
+	// veces antes de que el mercado quede cerca del final y se cancelen las
+	// órdenes.
+	"conservative": {
+		MinYourDailyReward:   0.05,  // al menos $0.05/día para justificar el capital bloqueado
+		MinRewardScore:       0.0,   // el reward propio ya filtra; el score de pool es legacy
+		MaxSpreadTotal:       0.02,  // 2¢ de spread total → fill cost mínimo si solo entra un lado
+		MaxCompetition:       5_000, // colas cortas: probabilidad de fill ~ size/(size+cola)
+		RequireQualifies:     true,  // sin reward no hay colchón contra parciales
+		MinHoursToResolution: 168,   // 7 días
+		OnlyFillsProfit:      true,  // FillCostUSDC > 0 significa perder dinero en cada fill
+	},
+
+	// balanced: término medio. Acepta spreads algo mayores y más competencia
+	// a cambio de más mercados candidatos, sin renunciar a fills rentables.
+	"balanced": {
+		MinYourDailyReward:   0.01,
+		MinRewardScore:       0.0,
+		MaxSpreadTotal:       0.05, // 5¢: sigue dentro del max_spread típico de rewards (3-5¢)
+		MaxCompetition:       20_000,
+		RequireQualifies:     true,
+		MinHoursToResolution: 48, // 2 días: suficiente para un ciclo fill → merge
+		OnlyFillsProfit:      true,
+	},
+
+	// aggressive: maximiza el número de mercados. Admite mercados donde un
+	// fill cuesta dinero si el reward diario lo compensa (ver BreakEvenFills)
+	// y mercados que no califican, confiando en la rotación para salir a
+	// tiempo.
+	"aggressive": {
+		MinYourDailyReward:   0.0,
+		MinRewardScore:       0.0,
+		MaxSpreadTotal:       0.10, // límite del DefaultFilterConfig del scanner
+		MaxCompetition:       100_000,
+		RequireQualifies:     false,
+		MinHoursToResolution: 24, // coincide con nearEndHours del live engine
+		OnlyFillsProfit:      false,
+	},
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScannerPresets_AggressiveRelaxes(t *testing.T) {
+	cons := scannerPresets["conservative"]
+	aggr := scannerPresets["aggressive"]
+
+	assert.True(t, cons.RequireQualifies)
+	assert.True(t, cons.OnlyFillsProfit)
+	assert.False(t, aggr.RequireQualifies)
+	assert.False(t, aggr.OnlyFillsProfit)
+	assert.Less(t, aggr.MinHoursToResolution, cons.MinHoursToResolution)
+	assert.Greater(t, aggr.MaxSpreadTotal, cons.MaxSpreadTotal)
+	assert.Greater(t, aggr.MaxCompetition, cons.MaxCompetition)
+}
+
+func TestLoad_PresetKeepsExplicitKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "scanner:\n  preset: conservative\n  max_spread_total: 0.04\n"
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+
+	cfg, err := load(path)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.04, cfg.Scanner.MaxSpreadTotal, 1e-9, "the YAML wins over the preset")
+	assert.InDelta(t, 168, cfg.Scanner.MinHoursToResolution, 1e-9)
+	assert.True(t, cfg.Scanner.RequireQualifies)
+}
+
+func TestLoad_UnknownPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("scanner:\n  preset: yolo\n"), 0o600))
+
+	_, err := load(path)
+	assert.ErrorContains(t, err, `unknown scanner.preset "yolo"`)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// restartOnly son las partes de la config que no se pueden cambiar en
+// caliente: credenciales, conexiones, stores y todo lo que se usa solo al
+// construir los componentes. pin copia en next el valor en uso.
+var restartOnly = []struct {
+	field string
+	pin   func(next, running *Config)
+}{
+	{"storage.dsn", func(n, r *Config) { n.Storage = r.Storage }},
+	{"api", func(n, r *Config) { n.API = r.API }},
+	{"rpc / live.polygon_rpc", func(n, r *Config) { n.RPC, n.Live.PolygonRPC = r.RPC, r.Live.PolygonRPC }},
+	{"oracle", func(n, r *Config) { n.Oracle = r.Oracle }},
+	{"log", func(n, r *Config) { n.Log = r.Log }},
+	{"health", func(n, r *Config) { n.Health = r.Health }},
+	{"live.wallets", func(n, r *Config) { n.Live.Wallets = r.Live.Wallets }},
+	{"live.initial_capital", func(n, r *Config) { n.Live.InitialCapital = r.Live.InitialCapital }},
+	{"live.shadow_mode", func(n, r *Config) { n.Live.ShadowMode = r.Live.ShadowMode }},
+	{"live.dry_run_placement", func(n, r *Config) { n.Live.DryRunPlacement = r.Live.DryRunPlacement }},
+	{"live.user_stream / book_stream", func(n, r *Config) { n.Live.UserStream, n.Live.BookStream = r.Live.UserStream, r.Live.BookStream }},
+	{"paper.initial_capital", func(n, r *Config) { n.Paper.InitialCapital = r.Paper.InitialCapital }},
+	{"paper.sweep", func(n, r *Config) { n.Paper.Sweep = r.Paper.Sweep }},
+	{"scanner.interval_seconds / adaptive_*", func(n, r *Config) {
+		n.Scanner.IntervalSeconds = r.Scanner.IntervalSeconds
+		n.Scanner.AdaptiveMinSeconds = r.Scanner.AdaptiveMinSeconds
+		n.Scanner.AdaptiveMaxSeconds = r.Scanner.AdaptiveMaxSeconds
+		n.Scanner.AdaptiveFilledBoost = r.Scanner.AdaptiveFilledBoost
+		n.Scanner.AdaptiveIdleScaleUp = r.Scanner.AdaptiveIdleScaleUp
+		n.Scanner.AdaptiveGoldBusy = r.Scanner.AdaptiveGoldBusy
+	}},
+	{"scanner.analysis_workers", func(n, r *Config) { n.Scanner.AnalysisWorkers = r.Scanner.AnalysisWorkers }},
+	{"scanner.market_cache_minutes", func(n, r *Config) { n.Scanner.MarketCacheMinutes = r.Scanner.MarketCacheMinutes }},
+	{"scanner.rank_by", func(n, r *Config) { n.Scanner.RankBy = r.Scanner.RankBy }},
+	{"scanner.record_dir", func(n, r *Config) { n.Scanner.RecordDir = r.Scanner.RecordDir }},
+	{"scanner.arb_fills_per_day / gold_min_reward", func(n, r *Config) {
+		n.Scanner.ArbFillsPerDay = r.Scanner.ArbFillsPerDay
+		n.Scanner.GoldMinReward = r.Scanner.GoldMinReward
+	}},
+	{"scanner.exclude_markets / include_markets / market_list_file", func(n, r *Config) {
+		n.Scanner.ExcludeMarkets = r.Scanner.ExcludeMarkets
+		n.Scanner.IncludeMarkets = r.Scanner.IncludeMarkets
+		n.Scanner.MarketListFile = r.Scanner.MarketListFile
+	}},
+}
+
+// Reloader relee el archivo de configuración (SIGHUP) y entrega la parte
+// recargable — filtros del scanner, tamaño de orden y umbrales de los
+// engines — a los hooks registrados, que la aplican en el siguiente ciclo.
+// Una config inválida se rechaza con un log y se sigue con la anterior; los
+// cambios en campos de restartOnly se avisan y se ignoran hasta reiniciar.
+//
+//	rl := config.NewReloader(path, cfg)
+//	rl.OnReload(func(c *config.Config) {
+//		s.SetFilter(scanner.NewFilter(wiring.FilterConfig(c.Scanner)))
+//		le.Reload(wiring.LiveEngineConfig(c.Live, c.Scanner.FeeRateDefault, c.Scanner.FeeRateRebated))
+//	})
+//	go rl.Watch(ctx)
+type Reloader struct {
+	path string
+
+	mu      sync.Mutex
+	current *Config
+	hooks   []func(*Config)
+}
+
+// NewReloader crea un Reloader para el archivo path, con current como la
+// config en uso.
+func NewReloader(path string, current *Config) *Reloader {
+	return &Reloader{path: path, current: current}
+}
+
+// OnReload registra fn, llamada con cada config recargada y validada.
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, fn)
+}
+
+// Current devuelve la config en uso.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload relee y valida el archivo y, si es correcto, lo pasa a los hooks
+// con los campos de restartOnly fijados a su valor en uso.
+func (r *Reloader) Reload() error {
+	next, err := Load(r.path)
+	if err != nil {
+		slog.Error("config: reload rejected, keeping the running config", "path", r.path, "err", err)
+		return fmt.Errorf("config.Reload: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ro := range restartOnly {
+		pinned := *next
+		ro.pin(&pinned, r.current)
+		if !reflect.DeepEqual(pinned, *next) {
+			slog.Warn("config: change requires restart, keeping the running value", "field", ro.field)
+			ro.pin(next, r.current)
+		}
+	}
+	r.current = next
+	for _, fn := range r.hooks {
+		fn(next)
+	}
+	slog.Info("config: reloaded", "path", r.path)
+	return nil
+}
+
+// Watch recarga la config con cada SIGHUP hasta que ctx se cancele.
+func (r *Reloader) Watch(ctx context.Context) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			_ = r.Reload()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prefijos de los problemas que devuelve Validate.
+const (
+	issueError   = "ERROR: "
+	issueWarning = "WARNING: "
+)
+
+// Validate revisa la semántica de la configuración y devuelve una lista de
+// problemas legibles. Los que empiezan por "ERROR: " son los invariantes de
+// (*Config).Validate e impiden arrancar; los "WARNING: " son valores válidos
+// pero sospechosos (p. ej. un fee diez veces por encima del real).
+func Validate(cfg Config) []string {
+	var issues []string
+
+	if err := cfg.Validate(); err != nil {
+		for _, e := range unjoin(err) {
+			issues = append(issues, issueError+e.Error())
+		}
+	}
+
+	warn := func(bad bool, format string, args ...any) {
+		if bad {
+			issues = append(issues, issueWarning+fmt.Sprintf(format, args...))
+		}
+	}
+
+	sc := cfg.Scanner
+	warn(sc.OrderSizeUSDC > 0 && sc.OrderSizeUSDC < 10,
+		"scanner.order_size_usdc = %g is below $10; most reward programs require a larger minimum size", sc.OrderSizeUSDC)
+	warn(sc.FeeRateDefault > 0.005,
+		"scanner.fee_rate_default = %g is above 0.5%%; Polymarket makers pay ~0%% (0 falls back to the 0.02 default, set e.g. 0.001)", sc.FeeRateDefault)
+	warn(sc.MaxSpreadTotal > 0.10,
+		"scanner.max_spread_total = %g is above 0.10; wide spreads rarely qualify for rewards", sc.MaxSpreadTotal)
+	warn(sc.MinHoursToResolution < 24,
+		"scanner.min_hours_to_resolution = %g is below 24h; positions may not have time to fill and merge", sc.MinHoursToResolution)
+	warn(sc.IntervalSeconds > 0 && cfg.ScanInterval() < 30*time.Second,
+		"scanner.interval_seconds = %d is below 30s; risk of API rate limiting", sc.IntervalSeconds)
+
+	lc := cfg.Live
+	warn(lc.CancelOnExit && !lc.NoCancelOnExit,
+		"live.cancel_on_exit has no effect: every open order is cancelled on exit unless live.no_cancel_on_exit is set")
+
+	return issues
+}
+
+// ValidateFile carga path sin rechazar los errores de validación y devuelve
+// los problemas de Validate. El error solo indica que el archivo no se pudo
+// leer o parsear.
+func ValidateFile(path string) ([]string, error) {
+	cfg, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return Validate(*cfg), nil
+}
+
+// HasErrors indica si algún problema de Validate es un error (y no un aviso).
+// Sirve para decidir el código de salida de --validate-config.
+func HasErrors(issues []string) bool {
+	for _, s := range issues {
+		if strings.HasPrefix(s, issueError) {
+			return true
+		}
+	}
+	return false
+}
+
+// unjoin separa un error de errors.Join en sus componentes.
+func unjoin(err error) []error {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanConfig es la configuración por defecto ajustada para no dar avisos.
+func cleanConfig(t *testing.T) Config {
+	t.Helper()
+	var cfg Config
+	setDefaults(&cfg)
+	cfg.Scanner.FeeRateDefault = 0.001
+	cfg.Scanner.MinHoursToResolution = 48
+	require.Empty(t, Validate(cfg))
+	return cfg
+}
+
+func TestValidate_Warnings(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"small order size", func(c *Config) { c.Scanner.OrderSizeUSDC = 5 }, "scanner.order_size_usdc = 5"},
+		{"taker fee rate", func(c *Config) { c.Scanner.FeeRateDefault = 0.02 }, "scanner.fee_rate_default = 0.02"},
+		{"wide spread", func(c *Config) { c.Scanner.MaxSpreadTotal = 0.2 }, "scanner.max_spread_total = 0.2"},
+		{"near resolution", func(c *Config) { c.Scanner.MinHoursToResolution = 12 }, "scanner.min_hours_to_resolution = 12"},
+		{"fast scan", func(c *Config) { c.Scanner.IntervalSeconds = 10 }, "scanner.interval_seconds = 10"},
+		{"cancel_on_exit without opt-out", func(c *Config) { c.Live.CancelOnExit = true }, "live.cancel_on_exit has no effect"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := cleanConfig(t)
+			tc.mutate(&cfg)
+			issues := Validate(cfg)
+			require.Len(t, issues, 1)
+			assert.True(t, strings.HasPrefix(issues[0], issueWarning), issues[0])
+			assert.Contains(t, issues[0], tc.want)
+			assert.False(t, HasErrors(issues))
+		})
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"fee rate out of range", func(c *Config) { c.Scanner.FeeRateRebated = 1.5 }, "scanner.fee_rate_rebated must be in [0, 1)"},
+		{"bad rank_by", func(c *Config) { c.Scanner.RankBy = "luck" }, "scanner.rank_by"},
+		{"ladder sizes", func(c *Config) {
+			c.Scanner.Ladder = LadderConfig{NumLevels: 2, TickSpacing: 0.01, SizeDistribution: []float64{1}}
+		}, "scanner.ladder.size_distribution must have num_levels entries"},
+		{"bad question regex", func(c *Config) { c.Scanner.ExcludeMarkets.Questions = []string{"(btc"} }, "scanner.exclude_markets.questions[0] must be a valid regex"},
+		{"missing market list file", func(c *Config) { c.Scanner.MarketListFile = "no/such/markets.yaml" }, "scanner.market_list_file"},
+		{"tick up of a whole dollar", func(c *Config) { c.Live.MaxBidTickUp = 1 }, "live.max_bid_tick_up must be < 1.0"},
+		{"taker after the flatten", func(c *Config) {
+			c.Live.AllowTakerCompletion, c.Live.TakerAfterHours = true, c.Live.MaxPartialHours
+		}, "live.taker_after_hours must be < max_partial_hours"},
+		{"wallet without key", func(c *Config) { c.Live.Wallets = []WalletConfig{{}} }, "live.wallets[0]: private_key_env is required"},
+		{"kelly min above max", func(c *Config) { c.Live.Kelly.Min = 0.9 }, "live.kelly.min must be <= max"},
+		{"bad rpc url", func(c *Config) { c.Live.PolygonRPC = "polygon-rpc.com" }, "live.polygon_rpc"},
+		{"unknown oracle", func(c *Config) { c.Oracle.Providers = []string{"pyth"} }, "oracle.providers[0] must be"},
+		{"empty dsn", func(c *Config) { c.Storage.DSN = " " }, "storage.dsn must not be empty"},
+		{"log level", func(c *Config) { c.Log.Level = "verbose" }, "log.level must be"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := cleanConfig(t)
+			tc.mutate(&cfg)
+			issues := Validate(cfg)
+			require.Len(t, issues, 1, "one broken rule, one issue: %v", issues)
+			assert.True(t, strings.HasPrefix(issues[0], issueError), issues[0])
+			assert.Contains(t, issues[0], tc.want)
+			assert.True(t, HasErrors(issues))
+		})
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := cleanConfig(t)
+	cfg.Log.Level = "verbose"
+	cfg.Storage.DSN = ""
+	cfg.Scanner.OrderSizeUSDC = 5
+
+	issues := Validate(cfg)
+	assert.Len(t, issues, 3)
+	assert.True(t, HasErrors(issues))
+}
+
+func TestHasErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		issues []string
+		want   bool
+	}{
+		{"none", nil, false},
+		{"warnings only", []string{issueWarning + "a", issueWarning + "b"}, false},
+		{"one error", []string{issueWarning + "a", issueError + "b"}, true},
+		{"prefix must lead", []string{issueWarning + "ERROR: quoted"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, HasErrors(tc.issues))
+		})
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	write := func(t *testing.T, yaml string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+		return path
+	}
+
+	t.Run("sample config has no errors", func(t *testing.T) {
+		issues, err := ValidateFile("config.yaml")
+		require.NoError(t, err)
+		assert.False(t, HasErrors(issues), "%v", issues)
+	})
+
+	t.Run("defaults fill what the file leaves out", func(t *testing.T) {
+		issues, err := ValidateFile(write(t, "scanner:\n  fee_rate_default: 0.001\n  min_hours_to_resolution: 48\n"))
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("invalid values are issues, not errors", func(t *testing.T) {
+		issues, err := ValidateFile(write(t, "log:\n  level: verbose\n"))
+		require.NoError(t, err)
+		assert.True(t, HasErrors(issues))
+	})
+
+	t.Run("unparseable YAML", func(t *testing.T) {
+		_, err := ValidateFile(write(t, "scanner: [unclosed\n"))
+		assert.ErrorContains(t, err, "parse YAML")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := ValidateFile(filepath.Join(t.TempDir(), "nope.yaml"))
+		assert.ErrorContains(t, err, "read")
+	})
+}
@@ -0,0 +1,212 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+const (
+	// healthProbeTimeout bounds the DB and RPC checks run on each request.
+	healthProbeTimeout = 3 * time.Second
+	// rpcProbeInterval caches the block number so frequent liveness probes
+	// do not hammer the RPC provider.
+	rpcProbeInterval = 30 * time.Second
+)
+
+// WritableChecker reports whether the database still accepts writes.
+type WritableChecker interface {
+	CheckWritable(ctx context.Context) error
+}
+
+// HealthConfig configures the /health endpoint.
+type HealthConfig struct {
+	// MaxAge is how long each component (ports.Health*) may go without a
+	// successful beat before /health answers 503. Before its first success
+	// the age counts from NewHealth. Components without an entry are shown
+	// but never fail the check.
+	MaxAge map[string]time.Duration
+	// DB is written to on each request when set; a failure answers 503.
+	DB WritableChecker
+	// BlockNumber fetches the latest Polygon block (ports.HealthRPC); nil
+	// skips the RPC check.
+	BlockNumber func(ctx context.Context) (uint64, error)
+}
+
+// Health is the status registry behind /health. The scanner, the engines and
+// the CLOB client push heartbeats into it through ports.HealthReporter.
+type Health struct {
+	cfg     HealthConfig
+	started time.Time
+
+	mu          sync.Mutex
+	beats       map[string]heartbeat
+	block       uint64
+	rpcProbedAt time.Time
+}
+
+type heartbeat struct {
+	at      time.Time // last beat, successful or not
+	ok      time.Time // last successful beat
+	lastErr string    // error of the last beat, "" if it succeeded
+}
+
+// NewHealth creates an empty registry.
+func NewHealth(cfg HealthConfig) *Health {
+	return &Health{cfg: cfg, started: time.Now(), beats: make(map[string]heartbeat)}
+}
+
+// Beat implements ports.HealthReporter.
+func (h *Health) Beat(component string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beatLocked(component, err, time.Now())
+}
+
+func (h *Health) beatLocked(component string, err error, now time.Time) {
+	b := h.beats[component]
+	b.at = now
+	b.lastErr = ""
+	if err != nil {
+		b.lastErr = err.Error()
+	} else {
+		b.ok = now
+	}
+	h.beats[component] = b
+}
+
+type componentHealth struct {
+	LastBeat      *time.Time `json:"last_beat,omitempty"`
+	LastSuccess   *time.Time `json:"last_success,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	MaxAgeSeconds float64    `json:"max_age_seconds,omitempty"`
+	Stale         bool       `json:"stale"`
+}
+
+type healthResponse struct {
+	Status         string                     `json:"status"` // ok | unhealthy
+	Problems       []string                   `json:"problems,omitempty"`
+	UptimeSeconds  float64                    `json:"uptime_seconds"`
+	Components     map[string]componentHealth `json:"components"`
+	RPCBlock       uint64                     `json:"rpc_block,omitempty"`
+	DBWritable     *bool                      `json:"db_writable,omitempty"`
+	DBError        string                     `json:"db_error,omitempty"`
+	CircuitBreaker *circuitBreakerResponse    `json:"circuit_breaker,omitempty"`
+}
+
+// probeRPC refreshes the block number at most every rpcProbeInterval.
+func (h *Health) probeRPC(ctx context.Context, now time.Time) {
+	if h.cfg.BlockNumber == nil {
+		return
+	}
+	h.mu.Lock()
+	fresh := now.Sub(h.rpcProbedAt) < rpcProbeInterval
+	h.mu.Unlock()
+	if fresh {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	block, err := h.cfg.BlockNumber(ctx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rpcProbedAt = now
+	h.beatLocked(ports.HealthRPC, err, now)
+	if err == nil {
+		h.block = block
+	}
+}
+
+// report builds the /health body; ok is false when any check failed.
+func (h *Health) report(ctx context.Context, now time.Time) (resp healthResponse, ok bool) {
+	h.probeRPC(ctx, now)
+
+	h.mu.Lock()
+	resp = healthResponse{
+		UptimeSeconds: now.Sub(h.started).Seconds(),
+		Components:    make(map[string]componentHealth, len(h.beats)+len(h.cfg.MaxAge)),
+		RPCBlock:      h.block,
+	}
+	names := make(map[string]bool, len(h.beats)+len(h.cfg.MaxAge))
+	for name := range h.beats {
+		names[name] = true
+	}
+	for name := range h.cfg.MaxAge {
+		names[name] = true
+	}
+	for name := range names {
+		b := h.beats[name]
+		c := componentHealth{LastError: b.lastErr}
+		if !b.at.IsZero() {
+			c.LastBeat = &b.at
+		}
+		if !b.ok.IsZero() {
+			c.LastSuccess = &b.ok
+		}
+		if maxAge := h.cfg.MaxAge[name]; maxAge > 0 {
+			c.MaxAgeSeconds = maxAge.Seconds()
+			since := b.ok
+			if since.IsZero() {
+				since = h.started
+			}
+			if age := now.Sub(since); age > maxAge {
+				c.Stale = true
+				resp.Problems = append(resp.Problems, fmt.Sprintf("%s: no success for %s (max %s)",
+					name, age.Truncate(time.Second), maxAge))
+			}
+		}
+		resp.Components[name] = c
+	}
+	h.mu.Unlock()
+	sort.Strings(resp.Problems)
+
+	if h.cfg.DB != nil {
+		dbCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+		err := h.cfg.DB.CheckWritable(dbCtx)
+		cancel()
+		writable := err == nil
+		resp.DBWritable = &writable
+		if err != nil {
+			resp.DBError = err.Error()
+			resp.Problems = append(resp.Problems, "db: not writable")
+		}
+	}
+
+	resp.Status = "ok"
+	if len(resp.Problems) > 0 {
+		resp.Status = "unhealthy"
+	}
+	return resp, len(resp.Problems) == 0
+}
+
+// handleHealth answers 200 when every check passes and 503 otherwise. A
+// tripped circuit breaker is reported but is not a failure: it is the bot
+// working as intended.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if s.health == nil {
+		http.Error(w, "health not configured", http.StatusNotFound)
+		return
+	}
+	resp, ok := s.health.report(r.Context(), time.Now())
+	if s.live != nil {
+		cb, err := s.live.LoadCircuitBreaker(r.Context())
+		if err != nil {
+			slog.Warn("httpapi: health: load circuit breaker", "err", err)
+		} else {
+			resp.CircuitBreaker = &circuitBreakerResponse{CircuitBreaker: cb, TradingAllowed: cb.IsOpen()}
+		}
+	}
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(w, resp)
+}
@@ -0,0 +1,103 @@
+package httpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/httpapi"
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+type healthBody struct {
+	Status     string   `json:"status"`
+	Problems   []string `json:"problems"`
+	RPCBlock   uint64   `json:"rpc_block"`
+	DBWritable *bool    `json:"db_writable"`
+	Components map[string]struct {
+		LastError string `json:"last_error"`
+		Stale     bool   `json:"stale"`
+	} `json:"components"`
+	CircuitBreaker map[string]any `json:"circuit_breaker"`
+}
+
+func newHealthAPI(t *testing.T, cfg httpapi.HealthConfig) (*httptest.Server, *httpapi.Health) {
+	t.Helper()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(context.Background()))
+
+	cfg.DB = db
+	h := httpapi.NewHealth(cfg)
+	api := httpapi.New(db, nil)
+	api.SetHealth(h)
+	srv := httptest.NewServer(api.Handler())
+	t.Cleanup(srv.Close)
+	return srv, h
+}
+
+func getHealth(t *testing.T, url string) (int, healthBody) {
+	t.Helper()
+	resp, err := http.Get(url + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body healthBody
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestHealth_OKWhenFresh(t *testing.T) {
+	srv, h := newHealthAPI(t, httpapi.HealthConfig{
+		MaxAge:      map[string]time.Duration{ports.HealthScan: time.Minute, ports.HealthRPC: time.Minute},
+		BlockNumber: func(context.Context) (uint64, error) { return 123, nil },
+	})
+	h.Beat(ports.HealthScan, nil)
+	h.Beat(ports.HealthCLOB, errors.New("timeout"))
+
+	code, body := getHealth(t, srv.URL)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+	assert.Equal(t, uint64(123), body.RPCBlock)
+	require.NotNil(t, body.DBWritable)
+	assert.True(t, *body.DBWritable)
+	assert.Equal(t, "timeout", body.Components[ports.HealthCLOB].LastError, "components without a threshold are reported only")
+	assert.NotNil(t, body.CircuitBreaker)
+}
+
+func TestHealth_UnavailableWhenStale(t *testing.T) {
+	srv, h := newHealthAPI(t, httpapi.HealthConfig{
+		MaxAge: map[string]time.Duration{
+			ports.HealthScan:      time.Nanosecond,
+			ports.HealthLiveCycle: time.Hour,
+			ports.HealthRPC:       time.Nanosecond,
+		},
+		BlockNumber: func(context.Context) (uint64, error) { return 0, errors.New("rpc down") },
+	})
+	h.Beat(ports.HealthLiveCycle, nil)
+	time.Sleep(time.Millisecond)
+
+	code, body := getHealth(t, srv.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", body.Status)
+	assert.True(t, body.Components[ports.HealthScan].Stale, "never beaten counts from startup")
+	assert.False(t, body.Components[ports.HealthLiveCycle].Stale)
+	assert.Equal(t, "rpc down", body.Components[ports.HealthRPC].LastError)
+	assert.Len(t, body.Problems, 2)
+}
+
+func TestHealth_NotConfigured(t *testing.T) {
+	srv, _ := newAPI(t)
+	resp, err := http.Get(srv.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
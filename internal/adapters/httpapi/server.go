@@ -0,0 +1,395 @@
+// Package httpapi serves a read-only JSON view of the bot's state for
+// dashboards. Every endpoint except /opportunities reads straight from
+// storage, so it answers between engine cycles and while the engine is idle.
+// Nothing here can place, cancel or modify orders. /health additionally
+// reports the heartbeats the scanner, engines and clients push into a Health
+// registry.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// Version is the schema version of the /opportunities, /positions and /stats
+// responses, sent in their "version" field. It is bumped whenever a field is
+// renamed, removed or changes meaning; new fields do not bump it.
+const Version = 1
+
+// OpportunitySource holds the opportunities of the latest scan cycle and when
+// it started. scanner.Scanner implements it.
+type OpportunitySource interface {
+	LastOpportunities() ([]domain.Opportunity, time.Time)
+}
+
+// Server exposes the live and paper stores over HTTP. Either store may be nil
+// when that mode is not in use; its section is then omitted.
+type Server struct {
+	live   ports.LiveStorage
+	paper  ports.PaperStorage
+	opps   OpportunitySource
+	health *Health
+	mux    *http.ServeMux
+}
+
+// New creates a read-only API server.
+func New(live ports.LiveStorage, paper ports.PaperStorage) *Server {
+	s := &Server{live: live, paper: paper, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /opportunities", s.handleOpportunities)
+	s.mux.HandleFunc("GET /positions", s.handlePositions)
+	s.mux.HandleFunc("GET /stats", s.handleStats)
+	s.mux.HandleFunc("GET /merges", s.handleMerges)
+	s.mux.HandleFunc("GET /circuit-breaker", s.handleCircuitBreaker)
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	return s
+}
+
+// SetHealth enables /health, backed by h. Without it /health answers 404.
+func (s *Server) SetHealth(h *Health) {
+	s.health = h
+}
+
+// SetOpportunities enables /opportunities, backed by src. Without it
+// /opportunities answers 404.
+func (s *Server) SetOpportunities(src OpportunitySource) {
+	s.opps = src
+}
+
+// Handler returns the HTTP handler, for embedding or tests.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves on addr until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("httpapi: listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+type opportunitiesResponse struct {
+	Version       int               `json:"version"`
+	ScannedAt     *time.Time        `json:"scanned_at"` // null before the first scan
+	Opportunities []opportunityJSON `json:"opportunities"`
+}
+
+// opportunityJSON is a domain.Opportunity with the category spelled out and
+// BreakEvenFills null instead of +Inf, which JSON cannot encode.
+type opportunityJSON struct {
+	domain.Opportunity
+	CategoryName   string
+	BreakEvenFills *float64 // null = fills cost nothing
+}
+
+func (s *Server) handleOpportunities(w http.ResponseWriter, _ *http.Request) {
+	if s.opps == nil {
+		http.Error(w, "scanner not configured", http.StatusNotFound)
+		return
+	}
+	opps, at := s.opps.LastOpportunities()
+	resp := opportunitiesResponse{Version: Version, Opportunities: make([]opportunityJSON, 0, len(opps))}
+	if !at.IsZero() {
+		resp.ScannedAt = &at
+	}
+	for _, o := range opps {
+		oj := opportunityJSON{Opportunity: o, CategoryName: o.Category.String()}
+		if !math.IsInf(o.BreakEvenFills, 0) && !math.IsNaN(o.BreakEvenFills) {
+			oj.BreakEvenFills = &o.BreakEvenFills
+		}
+		resp.Opportunities = append(resp.Opportunities, oj)
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := map[string]any{"version": Version}
+	if s.live != nil {
+		positions, err := livePositions(ctx, s.live, rng)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp["live"] = positions
+	}
+	if s.paper != nil {
+		positions, err := paperPositions(ctx, s.paper, rng)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp["paper"] = positions
+	}
+	writeJSON(w, resp)
+}
+
+type statsResponse struct {
+	Version int                `json:"version"`
+	Live    *domain.LiveStats  `json:"live,omitempty"`
+	Paper   *domain.PaperStats `json:"paper,omitempty"`
+}
+
+// handleStats serves the stats of the whole run. ?from/?to only trim the
+// Dailies series; the aggregates keep covering every day.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := statsResponse{Version: Version}
+	if s.live != nil {
+		stats, err := s.live.GetLiveStats(ctx)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		stats.Dailies = slices.DeleteFunc(stats.Dailies, func(d domain.LiveDailySummary) bool { return !rng.contains(d.Date) })
+		resp.Live = &stats
+	}
+	if s.paper != nil {
+		stats, err := s.paper.GetPaperStats(ctx)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		stats.Dailies = slices.DeleteFunc(stats.Dailies, func(d domain.PaperDailySummary) bool { return !rng.contains(d.Date) })
+		resp.Paper = &stats
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) handleMerges(w http.ResponseWriter, r *http.Request) {
+	if s.live == nil {
+		http.Error(w, "live storage not configured", http.StatusNotFound)
+		return
+	}
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	merges, err := s.live.GetMergeResults(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	merges = slices.DeleteFunc(merges, func(m domain.MergeResult) bool { return !rng.contains(m.ExecutedAt) })
+	if merges == nil {
+		merges = []domain.MergeResult{}
+	}
+	writeJSON(w, merges)
+}
+
+// timeRange is the optional ?from/?to window of the storage-backed endpoints:
+// from inclusive, to exclusive. A zero bound is open.
+type timeRange struct {
+	from, to time.Time
+}
+
+func (tr timeRange) contains(t time.Time) bool {
+	return (tr.from.IsZero() || !t.Before(tr.from)) && (tr.to.IsZero() || t.Before(tr.to))
+}
+
+// parseRange reads ?from and ?to as RFC3339 timestamps or YYYY-MM-DD dates
+// (midnight UTC).
+func parseRange(r *http.Request) (timeRange, error) {
+	var tr timeRange
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &tr.from}, {"to", &tr.to}} {
+		raw := r.URL.Query().Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, raw)
+		}
+		if err != nil {
+			return tr, fmt.Errorf("%s: want RFC3339 or YYYY-MM-DD, got %q", p.name, raw)
+		}
+		*p.dst = t
+	}
+	if !tr.from.IsZero() && !tr.to.IsZero() && !tr.from.Before(tr.to) {
+		return tr, errors.New("from must be before to")
+	}
+	return tr, nil
+}
+
+type circuitBreakerResponse struct {
+	domain.CircuitBreaker
+	TradingAllowed bool
+}
+
+func (s *Server) handleCircuitBreaker(w http.ResponseWriter, r *http.Request) {
+	if s.live == nil {
+		http.Error(w, "live storage not configured", http.StatusNotFound)
+		return
+	}
+	cb, err := s.live.LoadCircuitBreaker(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, circuitBreakerResponse{CircuitBreaker: cb, TradingAllowed: cb.IsOpen()})
+}
+
+// livePositions groups the open entry orders by pair. Each pair is reloaded in
+// full so a leg that already filled shows next to the one still resting.
+// Pairs whose first leg was placed outside rng are left out.
+func livePositions(ctx context.Context, store ports.LiveStorage, rng timeRange) ([]domain.LivePosition, error) {
+	open, err := store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := []domain.LivePosition{}
+	seen := make(map[string]bool)
+	for _, o := range open {
+		if o.IsSell() || seen[o.PairID] {
+			continue
+		}
+		seen[o.PairID] = true
+		orders, err := store.GetLiveOrdersByPair(ctx, o.PairID)
+		if err != nil {
+			return nil, err
+		}
+
+		pos := domain.LivePosition{PairID: o.PairID, ConditionID: o.ConditionID, Question: o.Question}
+		var placed time.Time
+		for i := range orders {
+			leg := &orders[i]
+			if leg.IsSell() {
+				continue
+			}
+			filled := leg.Status == domain.LiveStatusFilled || leg.Status == domain.LiveStatusMerged
+			switch leg.Side {
+			case "YES":
+				pos.YesOrder, pos.YesFilled = leg, filled
+			case "NO":
+				pos.NoOrder, pos.NoFilled = leg, filled
+			default:
+				continue
+			}
+			if placed.IsZero() || leg.PlacedAt.Before(placed) {
+				placed = leg.PlacedAt
+			}
+			pos.CapitalDeployed += leg.Size
+			pos.DailyReward = max(pos.DailyReward, leg.DailyReward)
+			pos.HoursToEnd = max(time.Until(leg.EndDate).Hours(), 0)
+			if filled && leg.FilledAt != nil && (pos.PartialSince == nil || leg.FilledAt.Before(*pos.PartialSince)) {
+				pos.PartialSince = leg.FilledAt
+			}
+		}
+		pos.IsComplete = pos.YesFilled && pos.NoFilled
+		if pos.IsComplete || !(pos.YesFilled || pos.NoFilled) {
+			pos.PartialSince = nil
+		}
+		if !rng.contains(placed) {
+			continue
+		}
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Question < positions[j].Question })
+	return positions, nil
+}
+
+// paperPositions is livePositions for virtual orders.
+func paperPositions(ctx context.Context, store ports.PaperStorage, rng timeRange) ([]domain.PaperPosition, error) {
+	open, err := store.GetOpenPaperOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := []domain.PaperPosition{}
+	seen := make(map[string]bool)
+	for _, o := range open {
+		if seen[o.PairID] {
+			continue
+		}
+		seen[o.PairID] = true
+		orders, err := store.GetPaperOrdersByPair(ctx, o.PairID)
+		if err != nil {
+			return nil, err
+		}
+
+		pos := domain.PaperPosition{PairID: o.PairID, ConditionID: o.ConditionID, Question: o.Question}
+		var placed time.Time
+		for i := range orders {
+			leg := &orders[i]
+			filled := leg.Status == domain.PaperStatusFilled || leg.Status == domain.PaperStatusMerged
+			switch leg.Side {
+			case "YES":
+				pos.YesOrder, pos.YesFilled = leg, filled
+			case "NO":
+				pos.NoOrder, pos.NoFilled = leg, filled
+			default:
+				continue
+			}
+			if placed.IsZero() || leg.PlacedAt.Before(placed) {
+				placed = leg.PlacedAt
+			}
+			pos.CapitalDeployed += leg.Size
+			pos.DailyReward = max(pos.DailyReward, leg.DailyReward)
+			pos.HoursToEnd = max(time.Until(leg.EndDate).Hours(), 0)
+			if filled && leg.FilledAt != nil && (pos.PartialSince == nil || leg.FilledAt.Before(*pos.PartialSince)) {
+				pos.PartialSince = leg.FilledAt
+			}
+		}
+		pos.IsComplete = pos.YesFilled && pos.NoFilled
+		if pos.IsComplete || !(pos.YesFilled || pos.NoFilled) {
+			pos.PartialSince = nil
+		}
+		if !rng.contains(placed) {
+			continue
+		}
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Question < positions[j].Question })
+	return positions, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("httpapi: encode response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	slog.Warn("httpapi: storage error", "err", err)
+	http.Error(w, "storage error", http.StatusInternalServerError)
+}
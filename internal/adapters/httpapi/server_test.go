@@ -0,0 +1,193 @@
+package httpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/httpapi"
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAPI(t *testing.T) (*httptest.Server, *storage.SQLiteStorage) {
+	t.Helper()
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(ctx))
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	srv := httptest.NewServer(httpapi.New(db, db).Handler())
+	t.Cleanup(srv.Close)
+	return srv, db
+}
+
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func liveLeg(id, side string, status domain.LiveOrderStatus) domain.LiveOrder {
+	return domain.LiveOrder{
+		ID:          id,
+		CLOBOrderID: "0x" + id,
+		ConditionID: "0xcond",
+		TokenID:     "tok-" + side,
+		Side:        side,
+		BidPrice:    0.45,
+		Size:        5,
+		PairID:      "pair-1",
+		PlacedAt:    time.Now().UTC(),
+		Status:      status,
+		Question:    "Will it rain?",
+		EndDate:     time.Now().Add(48 * time.Hour),
+	}
+}
+
+func TestPositions_IncludesFilledLegOfOpenPair(t *testing.T) {
+	srv, db := newAPI(t)
+	ctx := context.Background()
+	require.NoError(t, db.SaveLiveOrder(ctx, liveLeg("y1", "YES", domain.LiveStatusFilled)))
+	require.NoError(t, db.SaveLiveOrder(ctx, liveLeg("n1", "NO", domain.LiveStatusOpen)))
+
+	var resp struct {
+		Live  []domain.LivePosition
+		Paper []domain.PaperPosition
+	}
+	getJSON(t, srv.URL+"/positions", &resp)
+
+	require.Len(t, resp.Live, 1)
+	pos := resp.Live[0]
+	assert.True(t, pos.YesFilled)
+	assert.False(t, pos.NoFilled)
+	assert.InDelta(t, 10.0, pos.CapitalDeployed, 1e-9)
+	assert.NotNil(t, resp.Paper, "empty paper section is still present")
+}
+
+func TestCircuitBreaker_ReportsTradingAllowed(t *testing.T) {
+	srv, db := newAPI(t)
+	require.NoError(t, db.SaveCircuitBreaker(context.Background(), domain.CircuitBreaker{
+		MaxLosses: 3, Triggered: true, TriggeredReason: "drawdown",
+	}))
+
+	var resp map[string]any
+	getJSON(t, srv.URL+"/circuit-breaker", &resp)
+	assert.Equal(t, false, resp["TradingAllowed"])
+	assert.Equal(t, "drawdown", resp["TriggeredReason"])
+}
+
+func TestStatsAndMerges(t *testing.T) {
+	srv, _ := newAPI(t)
+
+	var stats map[string]json.RawMessage
+	getJSON(t, srv.URL+"/stats", &stats)
+	assert.Contains(t, stats, "live")
+	assert.Contains(t, stats, "paper")
+	assert.JSONEq(t, `1`, string(stats["version"]))
+
+	var merges []domain.MergeResult
+	getJSON(t, srv.URL+"/merges", &merges)
+	assert.Empty(t, merges)
+}
+
+type fixedOpps struct {
+	opps []domain.Opportunity
+	at   time.Time
+}
+
+func (f fixedOpps) LastOpportunities() ([]domain.Opportunity, time.Time) {
+	return f.opps, f.at
+}
+
+func TestOpportunities_ServesLastScan(t *testing.T) {
+	api := httpapi.New(nil, nil)
+	scanned := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	api.SetOpportunities(fixedOpps{at: scanned, opps: []domain.Opportunity{
+		{Market: domain.Market{ConditionID: "0xgold"}, Category: domain.CategoryGold, FillCostUSDC: -0.5, BreakEvenFills: math.Inf(1),
+			Arbitrage: domain.ArbitrageResult{MaxFillable: 42}},
+		{Market: domain.Market{ConditionID: "0xbronze"}, Category: domain.CategoryBronze, BreakEvenFills: 3},
+	}})
+	srv := httptest.NewServer(api.Handler())
+	t.Cleanup(srv.Close)
+
+	var resp struct {
+		Version       int
+		ScannedAt     *time.Time `json:"scanned_at"`
+		Opportunities []map[string]json.RawMessage
+	}
+	getJSON(t, srv.URL+"/opportunities", &resp)
+
+	assert.Equal(t, httpapi.Version, resp.Version)
+	require.NotNil(t, resp.ScannedAt)
+	assert.True(t, scanned.Equal(*resp.ScannedAt))
+	require.Len(t, resp.Opportunities, 2)
+	gold := resp.Opportunities[0]
+	assert.JSONEq(t, `"GOLD"`, string(gold["CategoryName"]))
+	assert.JSONEq(t, `null`, string(gold["BreakEvenFills"]), "+Inf is sent as null")
+	assert.JSONEq(t, `-0.5`, string(gold["FillCostUSDC"]))
+	assert.Contains(t, string(gold["Arbitrage"]), `"MaxFillable":42`)
+	assert.JSONEq(t, `3`, string(resp.Opportunities[1]["BreakEvenFills"]))
+}
+
+func TestOpportunities_NotFoundWithoutScanner(t *testing.T) {
+	srv, _ := newAPI(t)
+	resp, err := http.Get(srv.URL + "/opportunities")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestPositions_FilteredByRange(t *testing.T) {
+	srv, db := newAPI(t)
+	ctx := context.Background()
+	old := liveLeg("y1", "YES", domain.LiveStatusOpen)
+	old.PlacedAt = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	recent := liveLeg("y2", "YES", domain.LiveStatusOpen)
+	recent.PairID = "pair-2"
+	recent.PlacedAt = time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveLiveOrder(ctx, old))
+	require.NoError(t, db.SaveLiveOrder(ctx, recent))
+
+	var resp struct {
+		Version int
+		Live    []domain.LivePosition
+	}
+	getJSON(t, srv.URL+"/positions?from=2026-03-02", &resp)
+	assert.Equal(t, httpapi.Version, resp.Version)
+	require.Len(t, resp.Live, 1)
+	assert.Equal(t, "pair-2", resp.Live[0].PairID)
+
+	getJSON(t, srv.URL+"/positions?to=2026-03-02T00:00:00Z", &resp)
+	require.Len(t, resp.Live, 1)
+	assert.Equal(t, "pair-1", resp.Live[0].PairID)
+}
+
+func TestRange_RejectsBadBounds(t *testing.T) {
+	srv, _ := newAPI(t)
+	for _, q := range []string{"from=yesterday", "from=2026-03-05&to=2026-03-01"} {
+		resp, err := http.Get(srv.URL + "/stats?" + q)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, q)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	srv, _ := newAPI(t)
+	resp, err := http.Post(srv.URL+"/positions", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
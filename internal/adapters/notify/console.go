@@ -221,8 +221,19 @@ func (c *Console) printValidation(opps []domain.Opportunity) {
 		fmt.Fprintf(c.out, "\n  2. REWARD INCOME:\n")
 		fmt.Fprintf(c.out, "     pool: $%.2f/day  max_spread: %.4f\n",
 			m.Rewards.DailyRate, m.Rewards.MaxSpread)
-		fmt.Fprintf(c.out, "     your_share: %.4f%% ($%.0f / $%.0f)\n",
-			opp.YourShare*100, c.orderSize, opp.Competition+c.orderSize)
+		fmt.Fprintf(c.out, "     %-4s %-7s %-7s %10s %7s %10s\n", "side", "price", "dist", "size", "S", "score")
+		for _, l := range opp.RewardLevels {
+			mark := ""
+			if l.Ours {
+				mark = "  ← tu bid"
+			}
+			fmt.Fprintf(c.out, "     %-4s %-7.4f %-7.4f %10.0f %7.4f %10.1f%s\n",
+				l.Side, l.Price, l.Distance, l.Size, l.Weight, l.Score, mark)
+		}
+		fmt.Fprintf(c.out, "     competition_score: %.1f\n", opp.CompetitionScore)
+		fmt.Fprintf(c.out, "     your_share: %.4f%% (scoring por nivel)\n", opp.YourShare*100)
+		fmt.Fprintf(c.out, "     legacy_share: %.4f%% ($%.0f / $%.0f)  legacy_reward: $%.4f/day\n",
+			opp.LegacyYourShare*100, c.orderSize, opp.Competition+c.orderSize, opp.LegacyYourDailyReward)
 		fmt.Fprintf(c.out, "     spread_score: %.4f\n", opp.SpreadScore)
 		fmt.Fprintf(c.out, "     >>> YOUR REWARD: $%.4f/day\n", opp.YourDailyReward)
 
@@ -359,3 +370,27 @@ func sumFillCosts(opps []domain.Opportunity) float64 {
 	}
 	return total
 }
+
+// printProjection imprime el balance compuesto proyectado a 30/90/365 días.
+func (c *Console) printProjection(balance, dailyPnL float64) {
+	projections := domain.CompoundProjection(balance, dailyPnL, domain.ProjectionHorizons)
+	if len(projections) == 0 {
+		fmt.Fprintf(c.out, "  (no projection: balance unknown or losses exceed it)\n")
+		return
+	}
+	fmt.Fprintf(c.out, "  From $%.2f at $%.4f/day (%.3f%%/day reinvested):\n",
+		balance, dailyPnL, dailyPnL/balance*100)
+	for _, p := range projections {
+		fmt.Fprintf(c.out, "  %4dd  $%12.2f  APR %+8.1f%%\n", p.Days, p.ProjectedBalance, p.ProjectedAPR*100)
+	}
+	fmt.Fprintf(c.out, "  Assumes constant fill rates and rewards; not a forecast.\n")
+}
+
+// projectionBase es el balance desde el que se compone: el balance compuesto
+// si se conoce, el capital inicial si no.
+func projectionBase(compoundBalance, initialCapital float64) float64 {
+	if compoundBalance > 0 {
+		return compoundBalance
+	}
+	return initialCapital
+}
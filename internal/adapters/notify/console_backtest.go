@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintBacktest prints the daily breakdown of a windowed backtest followed by
+// per-market totals and an aggregate verdict over the whole window.
+func (c *Console) PrintBacktest(r *domain.BacktestResult) {
+	fmt.Fprintf(c.out, "\n═══ BACKTEST %s → %s (%.1f days, $%.0f/side) ═══\n",
+		r.From.Format(time.DateOnly), r.To.Format(time.DateOnly), r.WindowDays(), r.OrderSize)
+
+	if len(r.Days) == 0 {
+		if r.Historical {
+			fmt.Fprintf(c.out, "  No historical markets in window.\n")
+		} else {
+			fmt.Fprintf(c.out, "  No trade data in window.\n")
+		}
+		return
+	}
+
+	fmt.Fprintf(c.out, "\n── DAILY BREAKDOWN ──\n")
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Date", "Mkts", "FillY", "FillN", "Pairs", "Reward", "FillCost", "Net")
+	for _, d := range r.Days {
+		tbl.Append(
+			d.Date.Format("01-02"),
+			fmt.Sprintf("%d", d.Markets),
+			fmt.Sprintf("%d", d.FillsYES),
+			fmt.Sprintf("%d", d.FillsNO),
+			fmt.Sprintf("%d", d.CompletePairs),
+			fmt.Sprintf("$%.4f", d.Reward),
+			fmt.Sprintf("$%.4f", d.FillCost),
+			fmt.Sprintf("$%.4f", d.PnL),
+		)
+	}
+	tbl.Render()
+
+	fmt.Fprintf(c.out, "\n── MARKETS ──\n")
+	mt := tablewriter.NewWriter(c.out)
+	mt.Header("Market", "Trades", "FillY", "FillN", "Net", "Resolved")
+	for _, m := range r.Markets {
+		resolved := "-"
+		if m.ResolvedAt != nil {
+			resolved = m.ResolvedAt.Format("01-02 15:04")
+		}
+		mt.Append(
+			compactName(m.Question, 40),
+			fmt.Sprintf("%d", m.Trades),
+			fmt.Sprintf("%d", m.FillsYES),
+			fmt.Sprintf("%d", m.FillsNO),
+			fmt.Sprintf("$%.4f", m.PnL),
+			resolved,
+		)
+	}
+	mt.Render()
+
+	if r.RewardDays > 0 {
+		c.printRewardCheck(r)
+	}
+	if !r.Historical && (r.FillsBothPerDay > 0 || r.PredictedFillsPerDay > 0) {
+		c.printQueueModelCheck(r)
+	}
+
+	fmt.Fprintf(c.out, "\n── VERDICT ──\n")
+	if r.Historical {
+		fmt.Fprintf(c.out, "  Source:           Gamma price snapshots, no trade data (fill cost assumes 1 fill/day)\n")
+	} else {
+		fmt.Fprintf(c.out, "  Fills:            %d (%d complete pairs)\n", r.TotalFills, r.CompletePairs)
+	}
+	fmt.Fprintf(c.out, "  Reward:           $%.4f\n", r.TotalReward)
+	fmt.Fprintf(c.out, "  Fill cost:        $%.4f\n", r.TotalFillCost)
+	fmt.Fprintf(c.out, "  Net P&L:          $%.4f ($%.4f/day)\n", r.NetPnL, r.NetPnL/float64(len(r.Days)))
+	fmt.Fprintf(c.out, "  Profitable days:  %d/%d\n", r.ProfitableDays(), len(r.Days))
+	fmt.Fprintf(c.out, "  %s\n\n", r.Verdict())
+}
+
+// printRewardCheck compares the estimated reward with what Polymarket actually
+// paid over the days whose payout was fetched.
+func (c *Console) printRewardCheck(r *domain.BacktestResult) {
+	fmt.Fprintf(c.out, "\n── REWARD CHECK (%d paid days) ──\n", r.RewardDays)
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Market", "Estimated", "Actual", "Diff")
+	for _, m := range r.Markets {
+		tbl.Append(
+			compactName(m.Question, 40),
+			fmt.Sprintf("$%.4f", m.EstimatedReward),
+			fmt.Sprintf("$%.4f", m.ActualReward),
+			fmt.Sprintf("%+.4f", m.ActualReward-m.EstimatedReward),
+		)
+	}
+	tbl.Render()
+
+	fmt.Fprintf(c.out, "  Estimated:        $%.4f\n", r.EstimatedReward)
+	fmt.Fprintf(c.out, "  Actually paid:    $%.4f\n", r.ActualReward)
+	if r.EstimatedReward > 0 {
+		fmt.Fprintf(c.out, "  Discrepancy:      %+.4f (%+.0f%%)\n", r.RewardDiscrepancy(), 100*r.RewardDiscrepancy()/r.EstimatedReward)
+	} else {
+		fmt.Fprintf(c.out, "  Discrepancy:      %+.4f\n", r.RewardDiscrepancy())
+	}
+}
+
+// queueModelTolerance is how far the queue model may miss the replayed fills
+// before the calibration summary calls it biased.
+const queueModelTolerance = 0.10
+
+// printQueueModelCheck compares the fills per day the queue model predicts
+// with the ones replayed from the trade history, per market and overall.
+func (c *Console) printQueueModelCheck(r *domain.BacktestResult) {
+	fmt.Fprintf(c.out, "\n── QUEUE MODEL CHECK ──\n")
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Market", "Pred/day", "Real/day", "Error")
+	for _, m := range r.Markets {
+		errCol := "-"
+		if m.FillsBothPerDay > 0 {
+			errCol = fmt.Sprintf("%+.0f%%", 100*m.PredictionError)
+		}
+		tbl.Append(
+			compactName(m.Question, 40),
+			fmt.Sprintf("%.2f", m.PredictedFillsPerDay),
+			fmt.Sprintf("%.2f", m.FillsBothPerDay),
+			errCol,
+		)
+	}
+	tbl.Render()
+
+	fmt.Fprintf(c.out, "  Predicted:        %.2f fills/day\n", r.PredictedFillsPerDay)
+	fmt.Fprintf(c.out, "  Replayed:         %.2f fills/day\n", r.FillsBothPerDay)
+	switch {
+	case r.FillsBothPerDay <= 0:
+		fmt.Fprintf(c.out, "  Calibration:      no replayed fills to compare against\n")
+	case r.OverallQueueModelError > queueModelTolerance:
+		fmt.Fprintf(c.out, "  Calibration:      %+.0f%% — model OVER-estimates fills (queue multiplier too low)\n", 100*r.OverallQueueModelError)
+	case r.OverallQueueModelError < -queueModelTolerance:
+		fmt.Fprintf(c.out, "  Calibration:      %+.0f%% — model UNDER-estimates fills (queue multiplier too high)\n", 100*r.OverallQueueModelError)
+	default:
+		fmt.Fprintf(c.out, "  Calibration:      %+.0f%% — within ±%.0f%%\n", 100*r.OverallQueueModelError, 100*queueModelTolerance)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/olekukonko/tablewriter"
 )
 
 // LiveReportInput agrupa los datos necesarios para imprimir el reporte live.
@@ -14,12 +15,19 @@ type LiveReportInput struct {
 	PartialPairs []string
 	PairOrders   map[string][]domain.LiveOrder // pairID → órdenes
 	CircuitBreaker domain.CircuitBreaker
+	Paper        *domain.PaperStats // opcional: proyección de paper trading para el VERDICT
+	Shadow       *domain.LiveStats  // opcional: stats del modo shadow (dry-run), separadas de las reales
+	Exposures    []domain.ConditionExposure // opcional: capital por mercado (CycleResult.Exposures), mayor primero
 }
 
 // PrintLiveReport imprime el informe completo de live trading.
 func (c *Console) PrintLiveReport(in LiveReportInput) {
 	fmt.Fprintf(c.out, "\n╔══════════════════════════════════════════════════════════════╗\n")
-	fmt.Fprintf(c.out, "║                    LIVE TRADING REPORT                       ║\n")
+	if in.Stats.Shadow {
+		fmt.Fprintf(c.out, "║               SHADOW (DRY-RUN) TRADING REPORT                ║\n")
+	} else {
+		fmt.Fprintf(c.out, "║                    LIVE TRADING REPORT                       ║\n")
+	}
 	fmt.Fprintf(c.out, "╚══════════════════════════════════════════════════════════════╝\n\n")
 
 	stats := in.Stats
@@ -32,8 +40,32 @@ func (c *Console) PrintLiveReport(in LiveReportInput) {
 	fmt.Fprintf(c.out, "  Merges:       %d completed\n", stats.CompletePairs)
 	fmt.Fprintf(c.out, "  Merge Profit: $%.4f\n", stats.TotalMergeProfit)
 	fmt.Fprintf(c.out, "  Gas Cost:     $%.4f\n", stats.TotalGasCostUSD)
+	if !stats.Shadow && stats.POLBalance > 0 {
+		runway := "no merges yet to estimate runway"
+		if merges, days, ok := stats.GasRunway(); ok {
+			runway = fmt.Sprintf("~%.0f merges, ~%.0f days", merges, days)
+		}
+		fmt.Fprintf(c.out, "  Gas (POL):    %.4f used | %.4f left (%s)\n", stats.TotalGasUsedPOL, stats.POLBalance, runway)
+	}
+	if stats.StrandedShares > 0 {
+		fmt.Fprintf(c.out, "  Stranded:     %.2f shares ($%.2f) left unhedged by merges, unwinding\n", stats.StrandedShares, stats.StrandedUSDC)
+	}
+	if !stats.Shadow {
+		realized := "not synced yet"
+		if stats.RealizedReward > 0 {
+			realized = fmt.Sprintf("$%.4f", stats.RealizedReward)
+		}
+		// El neto solo incluye lo que Polymarket ha pagado de verdad.
+		fmt.Fprintf(c.out, "  Rewards:      $%.4f est. | %s realized\n", stats.TotalReward, realized)
+	}
+	if stats.Redemptions > 0 {
+		fmt.Fprintf(c.out, "  Redeemed:     %d resolved market(s), $%.4f realized\n", stats.Redemptions, stats.RedemptionPnL)
+	}
 	fmt.Fprintf(c.out, "  Net P&L:      $%.4f (avg $%.4f/day)\n", stats.NetPnL, stats.DailyAvgPnL)
 	fmt.Fprintf(c.out, "  Rotations:    %d\n", stats.TotalRotations)
+	if reasons := domain.FormatCloseReasons(stats.CloseReasons); reasons != "" {
+		fmt.Fprintf(c.out, "  By reason:    %s\n", reasons)
+	}
 
 	fmt.Fprintf(c.out, "\n── OPEN ORDERS (%d) ──\n", len(in.OpenOrders))
 	if len(in.OpenOrders) > 0 {
@@ -73,22 +105,272 @@ func (c *Console) PrintLiveReport(in LiveReportInput) {
 	fmt.Fprintf(c.out, "\n── SUMMARY ──\n")
 	fmt.Fprintf(c.out, "  Open orders:        %d\n", len(in.OpenOrders))
 	fmt.Fprintf(c.out, "  Partial fill pairs: %d (RISK: directional exposure)\n", len(in.PartialPairs))
-	fmt.Fprintf(c.out, "  Circuit breaker:    ")
-	cb := in.CircuitBreaker
-	if cb.Triggered {
-		fmt.Fprintf(c.out, "TRIGGERED (reason: %s)\n", cb.TriggeredReason)
+	c.printTopExposures(in.Exposures)
+
+	c.printLiveDailies(stats.Dailies)
+	c.printLiveReturns(stats)
+	fmt.Fprintf(c.out, "\n── P&L BY MARKET ──\n")
+	c.printMarketAttribution(stats.Markets)
+	c.printCircuitBreaker(in.CircuitBreaker)
+	c.printLiveVerdict(stats, in.Paper)
+	if stats.DaysRunning >= domain.MinProjectionDays {
+		fmt.Fprintf(c.out, "\n── PROJECTION ──\n")
+		c.printProjection(projectionBase(stats.CompoundBalance, stats.InitialCapital), stats.DailyAvgPnL)
+	}
+	if in.Shadow != nil {
+		c.printShadowStats(*in.Shadow, in.Paper)
+	}
+
+	fmt.Fprintln(c.out)
+}
+
+// printTopExposures imprime los 3 mercados con más capital comprometido.
+func (c *Console) printTopExposures(exps []domain.ConditionExposure) {
+	if len(exps) == 0 {
+		return
+	}
+	fmt.Fprintf(c.out, "  Top concentration:\n")
+	for _, e := range exps[:min(3, len(exps))] {
+		q := domain.TruncateQuestion(e.Question, e.ConditionID, 35)
+		fmt.Fprintf(c.out, "    $%7.2f %5.1f%%  %s\n", e.USDC, e.Pct*100, q)
+	}
+}
+
+// printShadowStats prints the shadow run next to the paper projection. Shadow
+// orders never fill, so the comparison is on orders placed and reward earned.
+func (c *Console) printShadowStats(shadow domain.LiveStats, paper *domain.PaperStats) {
+	fmt.Fprintf(c.out, "\n── SHADOW (dry-run, not real money) ──\n")
+	if shadow.DaysRunning == 0 && shadow.TotalOrders == 0 {
+		fmt.Fprintf(c.out, "  (no shadow data)\n")
+		return
+	}
+	fmt.Fprintf(c.out, "  Period:             %s → %s (%d days)\n",
+		shadow.StartDate.Format("2006-01-02"), shadow.EndDate.Format("2006-01-02"), shadow.DaysRunning)
+	fmt.Fprintf(c.out, "  Orders placed:      %d\n", shadow.TotalOrders)
+	fmt.Fprintf(c.out, "  Est. reward:        $%.4f\n", shadow.TotalReward)
+	fmt.Fprintf(c.out, "  Net P&L:            $%.4f (avg $%.4f/day)\n", shadow.NetPnL, shadow.DailyAvgPnL)
+	if paper != nil && paper.DaysRunning > 0 {
+		fmt.Fprintf(c.out, "  Paper projection:   $%.4f/day avg over %d days\n", paper.DailyAvgPnL, paper.DaysRunning)
+	}
+}
+
+// printLiveDailies prints the daily P&L table, mirroring PrintPaperReport.
+func (c *Console) printLiveDailies(dailies []domain.LiveDailySummary) {
+	if len(dailies) == 0 {
+		return
+	}
+	fmt.Fprintf(c.out, "\n── DAILY BREAKDOWN ──\n")
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Date", "Pos", "Orders", "FillY", "FillN", "Merges", "MrgPnL", "Gas$", "Net", "Cap$", "Rot", "Bal$")
+	for _, d := range dailies {
+		balLabel := "-"
+		if d.CompoundBalance > 0 {
+			balLabel = fmt.Sprintf("$%.2f", d.CompoundBalance)
+		}
+		tbl.Append(
+			d.Date.Format("01-02"),
+			fmt.Sprintf("%d", d.ActivePositions),
+			fmt.Sprintf("%d", d.OrdersPlaced),
+			fmt.Sprintf("%d", d.FillsYes),
+			fmt.Sprintf("%d", d.FillsNo),
+			fmt.Sprintf("%d", d.Merges),
+			fmt.Sprintf("$%.4f", d.MergeProfit),
+			fmt.Sprintf("$%.4f", d.GasCostUSD),
+			fmt.Sprintf("$%.4f", d.NetPnL),
+			fmt.Sprintf("$%.0f", d.CapitalDeployed),
+			fmt.Sprintf("%d", d.Rotations),
+			balLabel,
+		)
+	}
+	tbl.Render()
+}
+
+// printLiveReturns prints merge aggregates, gas and return on initial capital.
+func (c *Console) printLiveReturns(stats domain.LiveStats) {
+	fmt.Fprintf(c.out, "\n── RETURNS ──\n")
+	fmt.Fprintf(c.out, "  Merges completed:   %d\n", stats.CompletePairs)
+	if stats.CompletePairs > 0 {
+		fmt.Fprintf(c.out, "  Profit/merge:       $%.4f\n", stats.TotalMergeProfit/float64(stats.CompletePairs))
+	}
+	fmt.Fprintf(c.out, "  Total gas cost:     $%.4f\n", stats.TotalGasCostUSD)
+	if stats.InitialCapital > 0 {
+		ret := stats.NetPnL / stats.InitialCapital * 100
+		fmt.Fprintf(c.out, "  Return on capital:  %+.2f%% (on $%.2f initial)\n", ret, stats.InitialCapital)
+	}
+	if stats.AvgCycleHours > 0 {
+		fmt.Fprintf(c.out, "  Avg cycle time:     %.1f hours\n", stats.AvgCycleHours)
+	} else {
+		fmt.Fprintf(c.out, "  Avg cycle time:     n/a (no merges yet)\n")
+	}
+	fmt.Fprintf(c.out, "  Fill rate:          %.0f%% of orders\n", stats.FillRateReal*100)
+
+	fmt.Fprintf(c.out, "\n── FILL LATENCY (placed → filled) ──\n")
+	c.printFillLatency(stats.FillLatency)
+}
+
+// printCircuitBreaker prints the current circuit breaker state.
+func (c *Console) printCircuitBreaker(cb domain.CircuitBreaker) {
+	fmt.Fprintf(c.out, "\n── CIRCUIT BREAKER ──\n")
+	switch {
+	case cb.Triggered:
+		fmt.Fprintf(c.out, "  State:              TRIGGERED (reason: %s)\n", cb.TriggeredReason)
+	case time.Now().Before(cb.CooldownUntil):
+		fmt.Fprintf(c.out, "  State:              COOLDOWN until %s (%s)\n",
+			cb.CooldownUntil.Format("2006-01-02 15:04"), cb.TriggeredReason)
+	default:
+		fmt.Fprintf(c.out, "  State:              OK\n")
+	}
+	fmt.Fprintf(c.out, "  Consecutive losses: %d\n", cb.ConsecutiveLosses)
+	fmt.Fprintf(c.out, "  Tracked P&L:        $%.4f\n", cb.TotalPnL)
+}
+
+// printLiveVerdict compares realized live results against the paper projection.
+// Returns are compared as daily % of initial capital since both runs use
+// different bankrolls.
+func (c *Console) printLiveVerdict(stats domain.LiveStats, paper *domain.PaperStats) {
+	fmt.Fprintf(c.out, "\n── VERDICT ──\n")
+	if stats.DaysRunning < 3 {
+		fmt.Fprintf(c.out, "  Need at least 3 days of live data. Currently %d days.\n", stats.DaysRunning)
+		return
+	}
+	if stats.InitialCapital <= 0 {
+		fmt.Fprintf(c.out, "  Initial capital unknown — cannot compute returns.\n")
+		return
+	}
+
+	liveDaily := stats.DailyAvgPnL / stats.InitialCapital * 100
+	fmt.Fprintf(c.out, "  Live daily return:  %+.3f%%\n", liveDaily)
+
+	if paper == nil || paper.DaysRunning == 0 || paper.InitialCapital <= 0 {
+		fmt.Fprintf(c.out, "  No paper projection available for comparison.\n")
 	} else {
-		fmt.Fprintf(c.out, "OK\n")
+		paperDaily := paper.DailyAvgPnL / paper.InitialCapital * 100
+		fmt.Fprintf(c.out, "  Paper projection:   %+.3f%%/day\n", paperDaily)
+		if paperDaily > 0 {
+			fmt.Fprintf(c.out, "  Realization:        %.0f%% of paper\n", liveDaily/paperDaily*100)
+		}
+	}
+
+	switch {
+	case stats.NetPnL <= 0:
+		fmt.Fprintf(c.out, "  NEGATIVE: Live trading is losing money. Reduce size or stop.\n")
+	case paper != nil && paper.InitialCapital > 0 && paper.DailyAvgPnL > 0 &&
+		liveDaily < paper.DailyAvgPnL/paper.InitialCapital*100*0.5:
+		fmt.Fprintf(c.out, "  UNDERPERFORMING: Live is profitable but below half of the paper projection.\n")
+	default:
+		fmt.Fprintf(c.out, "  POSITIVE: Live trading is tracking the projection.\n")
 	}
+}
 
-	if len(stats.Dailies) > 0 {
-		fmt.Fprintf(c.out, "\n── DAILY BREAKDOWN ──\n")
-		fmt.Fprintf(c.out, "  %-12s %8s %8s %8s %8s\n", "Date", "Orders", "Fills", "Merges", "NetPnL")
-		for _, d := range stats.Dailies {
-			fmt.Fprintf(c.out, "  %-12s %8d %8d %8d $%7.4f\n",
-				d.Date.Format("2006-01-02"),
-				d.OrdersPlaced, d.FillsYes+d.FillsNo, d.Merges, d.NetPnL)
+// LiveStatusInput agrupa el resultado de un ciclo live (live.CycleResult) para
+// el estado por ciclo, en consola o en la TUI.
+type LiveStatusInput struct {
+	Positions       []domain.LivePosition
+	NewOrders       int
+	NewFills        int
+	Merges          int
+	MergeProfit     float64
+	PartialAlerts   []string
+	Warnings        []string
+	CapitalDeployed float64
+	CompoundBalance float64
+	KellyFraction   float64
+	CircuitOpen     bool // true = se opera; false = breaker disparado
+}
+
+// LiveStatusPrinter muestra el estado de cada ciclo live. Console imprime una
+// línea compacta; TUI redibuja el panel completo.
+type LiveStatusPrinter interface {
+	PrintLiveStatus(in LiveStatusInput)
+}
+
+// PrintLiveStatus imprime una línea compacta con el estado del ciclo live.
+func (c *Console) PrintLiveStatus(in LiveStatusInput) {
+	active, complete := 0, 0
+	for _, pos := range in.Positions {
+		if pos.IsMerged {
+			continue
+		}
+		active++
+		if pos.IsComplete {
+			complete++
 		}
 	}
+	breaker := "OK"
+	if !in.CircuitOpen {
+		breaker = "TRIPPED"
+	}
+	fmt.Fprintf(c.out, "[%s][LIVE] %d pos | %d pairs | +%d orders | +%d fills | cap $%.0f/$%.0f | K%.0f%% | breaker %s",
+		time.Now().Format("15:04:05"), active, complete, in.NewOrders, in.NewFills,
+		in.CapitalDeployed, in.CompoundBalance, in.KellyFraction*100, breaker)
+	if in.Merges > 0 {
+		fmt.Fprintf(c.out, " | +%d merge $%.4f", in.Merges, in.MergeProfit)
+	}
 	fmt.Fprintln(c.out)
+	for _, a := range in.PartialAlerts {
+		fmt.Fprintf(c.out, "  !! %s\n", a)
+	}
+	for _, w := range in.Warnings {
+		fmt.Fprintf(c.out, "  >> %s\n", w)
+	}
+}
+
+// PrintBalanceReconciliation imprime el balance real de las wallets frente al
+// que implican los libros, con el desglose que explica la diferencia.
+func (c *Console) PrintBalanceReconciliation(r domain.BalanceReconciliation) {
+	fmt.Fprintf(c.out, "\n═══ BALANCE RECONCILIATION ═══\n")
+	fmt.Fprintf(c.out, "  Wallet USDC:        $%.2f", r.WalletBalance)
+	if r.SkippedWallets > 0 {
+		fmt.Fprintf(c.out, " (%d wallet(s) unreadable, not included)", r.SkippedWallets)
+	}
+	fmt.Fprintln(c.out)
+	fmt.Fprintf(c.out, "  Expected:           $%.2f\n", r.Expected())
+	fmt.Fprintf(c.out, "  Delta:              %+.2f\n", r.Delta())
+
+	fmt.Fprintf(c.out, "\n── BREAKDOWN ──\n")
+	fmt.Fprintf(c.out, "  Initial capital:    $%.2f\n", r.InitialCapital)
+	fmt.Fprintf(c.out, "  Merge profit:       %+.4f (%d merges, $%.2f received)\n", r.MergeProfit, r.Merges, r.MergeReceipts)
+	fmt.Fprintf(c.out, "  Gas spent:          $%.4f (paid in POL; %d failed merge tx)\n", r.GasSpent, r.FailedMerges)
+	fmt.Fprintf(c.out, "  Realized unwinds:   %+.4f\n", r.RealizedPnL)
+	fmt.Fprintf(c.out, "  Rewards paid:       %+.4f\n", r.RewardsPaid)
+	fmt.Fprintf(c.out, "  Locked partial:     $%.2f\n", r.LockedPartial)
+	fmt.Fprintf(c.out, "  Locked filled:      $%.2f\n", r.LockedFilled)
+	fmt.Fprintf(c.out, "  Resting bids:       $%.2f (still in the wallet)\n", r.RestingBids)
+
+	fmt.Fprintf(c.out, "\n  Unexplained:        %+.2f", r.Unexplained())
+	if r.Flagged() {
+		fmt.Fprintf(c.out, "  !! exceeds $%.2f — check for missed fills or failed txs", r.Tolerance)
+	} else {
+		fmt.Fprintf(c.out, "  (within $%.2f)", r.Tolerance)
+	}
+	fmt.Fprintln(c.out)
+}
+
+// PrintCalibration imprime el resultado de --calibrate junto a los valores
+// actuales y el bloque de config sugerido. No cambia nada.
+func (c *Console) PrintCalibration(cal domain.LiveCalibration, currentQueueMult, currentVarianceMax float64) {
+	m := cal.FillRateModel
+	fmt.Fprintf(c.out, "\n═══ LIVE CALIBRATION (last 30 days) ═══\n")
+	fmt.Fprintf(c.out, "  Filled orders:      %d\n", m.Samples)
+	fmt.Fprintf(c.out, "  Fill rate:          $%.2f/hour through the queue\n", m.USDCPerHour)
+	fmt.Fprintf(c.out, "  Queue weight:       %.2f× stored QueueAhead (R² %.2f)\n", m.QueueScale, m.R2)
+
+	fmt.Fprintf(c.out, "\n── SUGGESTED ──\n")
+	fmt.Fprintf(c.out, "  queue_conservative_mult:  %.2f → %.2f\n", currentQueueMult, cal.OptimalQueueMult)
+	if cal.OptimalVarianceMax > 0 {
+		fmt.Fprintf(c.out, "  spread_variance_max:      %.3f → %.3f (%d markets)\n",
+			currentVarianceMax, cal.OptimalVarianceMax, cal.VarianceMarkets)
+	} else {
+		fmt.Fprintf(c.out, "  spread_variance_max:      %.3f (kept: %d markets with 3+ pairs, need clean and one-sided ones)\n",
+			currentVarianceMax, cal.VarianceMarkets)
+	}
+
+	variance := currentVarianceMax
+	if cal.OptimalVarianceMax > 0 {
+		variance = cal.OptimalVarianceMax
+	}
+	fmt.Fprintf(c.out, "\n  Not applied. To use them, set in config.yaml:\n\n")
+	fmt.Fprintf(c.out, "    live:\n")
+	fmt.Fprintf(c.out, "      queue_conservative_mult: %.2f\n", cal.OptimalQueueMult)
+	fmt.Fprintf(c.out, "      spread_variance_max: %.3f\n\n", variance)
 }
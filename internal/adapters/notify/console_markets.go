@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// marketAttributionTop es cuántos mercados muestra cada tabla de ganadores/perdedores.
+const marketAttributionTop = 5
+
+// printMarketAttribution imprime los 5 mercados que más pierden y los 5 que
+// más ganan. markets viene ordenado de peor a mejor (Stats.Markets).
+func (c *Console) printMarketAttribution(markets []domain.MarketPnL) {
+	if len(markets) == 0 {
+		fmt.Fprintln(c.out, "  (no markets traded yet)")
+		return
+	}
+	var losers, winners []domain.MarketPnL
+	for _, m := range markets {
+		if m.NetPnL < 0 && len(losers) < marketAttributionTop {
+			losers = append(losers, m)
+		}
+	}
+	for i := len(markets) - 1; i >= 0 && len(winners) < marketAttributionTop; i-- {
+		if markets[i].NetPnL > 0 {
+			winners = append(winners, markets[i])
+		}
+	}
+
+	fmt.Fprintf(c.out, "  Top %d losers:\n", marketAttributionTop)
+	c.printMarketPnLTable(losers)
+	fmt.Fprintf(c.out, "  Top %d winners:\n", marketAttributionTop)
+	c.printMarketPnLTable(winners)
+}
+
+func (c *Console) printMarketPnLTable(markets []domain.MarketPnL) {
+	if len(markets) == 0 {
+		fmt.Fprintln(c.out, "    (none)")
+		return
+	}
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Market", "Orders", "Fills", "Merges", "Rot", "Merge$", "Reward$", "Time", "Net$")
+	for _, m := range markets {
+		tbl.Append(
+			domain.TruncateQuestion(m.Question, m.ConditionID, 35),
+			fmt.Sprintf("%d", m.Orders),
+			fmt.Sprintf("%d", m.Fills),
+			fmt.Sprintf("%d", m.Merges),
+			fmt.Sprintf("%d", m.Rotations),
+			fmt.Sprintf("$%.4f", m.MergeProfit),
+			fmt.Sprintf("$%.4f", m.RewardAccrued),
+			m.TimeInMarket.Truncate(time.Minute).String(),
+			fmt.Sprintf("$%.4f", m.NetPnL),
+		)
+	}
+	tbl.Render()
+}
+
+// PrintMarketTimeline imprime todas las órdenes, fills, merges y rewards de
+// un mercado (--report-market) para depurarlo.
+func (c *Console) PrintMarketTimeline(conditionID string, pnl *domain.MarketPnL, events []domain.MarketEvent) {
+	fmt.Fprintf(c.out, "\n── MARKET TIMELINE %s ──\n", conditionID)
+	if pnl != nil {
+		fmt.Fprintf(c.out, "  %s\n", pnl.Question)
+		fmt.Fprintf(c.out, "  Orders %d | Fills %d | Merges %d | Rotations %d | Time %v\n",
+			pnl.Orders, pnl.Fills, pnl.Merges, pnl.Rotations, pnl.TimeInMarket.Truncate(time.Minute))
+		fmt.Fprintf(c.out, "  Merge $%.4f + Reward $%.4f + Realized $%.4f = Net $%.4f\n",
+			pnl.MergeProfit, pnl.RewardAccrued, pnl.RealizedPnL, pnl.NetPnL)
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(c.out, "  (no orders for this market)")
+		return
+	}
+
+	fmt.Fprintf(c.out, "\n  %-19s %-12s %-4s %6s %8s  %-10s %s\n", "TIME", "EVENT", "SIDE", "PRICE", "SIZE", "ORDER", "DETAIL")
+	for _, e := range events {
+		at := "-"
+		if !e.At.IsZero() {
+			at = e.At.UTC().Format("2006-01-02 15:04:05")
+		}
+		price, size := "", ""
+		if e.Price > 0 {
+			price = fmt.Sprintf("%.2f", e.Price)
+		}
+		if e.Size > 0 {
+			size = fmt.Sprintf("%.2f", e.Size)
+		}
+		fmt.Fprintf(c.out, "  %-19s %-12s %-4s %6s %8s  %-10s %s\n",
+			at, e.Kind, e.Side, price, size, e.OrderID[:min(10, len(e.OrderID))], e.Detail)
+	}
+}
+
+// printFillLatency imprime la distribución de placement → fill de las
+// órdenes de entrada llenas, total y por lado.
+func (c *Console) printFillLatency(l domain.FillLatencyStats) {
+	if l.All.Count == 0 {
+		fmt.Fprintln(c.out, "  (no filled orders yet)")
+		return
+	}
+	fmt.Fprintf(c.out, "  %-5s %6s %9s %9s %9s %9s\n", "SIDE", "FILLS", "MIN", "MEDIAN", "P90", "MAX")
+	for _, row := range []struct {
+		side string
+		fl   domain.FillLatency
+	}{{"all", l.All}, {"YES", l.Yes}, {"NO", l.No}} {
+		if row.fl.Count == 0 {
+			fmt.Fprintf(c.out, "  %-5s %6d %9s %9s %9s %9s\n", row.side, 0, "-", "-", "-", "-")
+			continue
+		}
+		fmt.Fprintf(c.out, "  %-5s %6d %9s %9s %9s %9s\n", row.side, row.fl.Count,
+			latencyString(row.fl.Min), latencyString(row.fl.Median),
+			latencyString(row.fl.P90), latencyString(row.fl.Max))
+	}
+}
+
+// latencyString redondea a minutos, o a segundos por debajo del minuto.
+func latencyString(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
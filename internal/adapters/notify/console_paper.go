@@ -32,7 +32,7 @@ type PaperStatusInput struct {
 func (c *Console) PrintPaperStatus(result PaperStatusInput) {
 	now := time.Now().Format("15:04:05")
 
-	active, complete, partial := 0, 0, 0
+	active, complete, partial, dominant := 0, 0, 0, 0
 	var rewardAccrued float64
 	for _, pos := range result.Positions {
 		if pos.YesOrder == nil && pos.NoOrder == nil {
@@ -48,6 +48,9 @@ func (c *Console) PrintPaperStatus(result PaperStatusInput) {
 		if pos.PartialSince != nil && !pos.IsComplete {
 			partial++
 		}
+		if pos.SizeDominant {
+			dominant++
+		}
 		rewardAccrued += pos.RewardAccrued
 	}
 
@@ -55,6 +58,9 @@ func (c *Console) PrintPaperStatus(result PaperStatusInput) {
 	fmt.Fprintf(&sb, "[%s][PAPER] %d pos | %d pairs | %d partial | +%d orders | +%d fills | rwd $%.4f | cap $%.0f",
 		now, active, complete, partial, result.NewOrders, result.NewFills,
 		rewardAccrued, result.CapitalDeployed)
+	if dominant > 0 {
+		fmt.Fprintf(&sb, " | %d size-dominant", dominant)
+	}
 
 	if result.CompoundBalance > 0 || result.TotalRotations > 0 {
 		growth := 0.0
@@ -135,6 +141,11 @@ func (c *Console) PrintPaperReport(stats domain.PaperStats) {
 	fmt.Fprintf(c.out, "  Complete pairs:        %d\n", stats.CompletePairs)
 	fmt.Fprintf(c.out, "  Partial fills:         %d\n", stats.PartialFills)
 	fmt.Fprintf(c.out, "  Fill rate (real):      %.1f fills/day\n", stats.FillRateReal)
+	if stats.DominantOrders > 0 {
+		fmt.Fprintf(c.out, "  Orders filled:         normal %s | size-dominant %s\n",
+			filledRatio(stats.NormalFilled, stats.NormalOrders),
+			filledRatio(stats.DominantFilled, stats.DominantOrders))
+	}
 	fmt.Fprintf(c.out, "  Max capital deployed:  $%.0f\n", stats.MaxCapital)
 
 	fmt.Fprintf(c.out, "\n  --- PARTIAL FILL RISK ---\n")
@@ -144,6 +155,9 @@ func (c *Console) PrintPaperReport(stats domain.PaperStats) {
 		fmt.Fprintf(c.out, "  Partial rate:          %.1f%% of all fills\n", partialPct)
 	}
 
+	fmt.Fprintf(c.out, "\n  --- FILL LATENCY (placed → filled) ---\n")
+	c.printFillLatency(stats.FillLatency)
+
 	fmt.Fprintf(c.out, "\n  --- P&L (with 0%% maker fee) ---\n")
 	fmt.Fprintf(c.out, "  Reward income:         $%.4f\n", stats.TotalReward)
 	fmt.Fprintf(c.out, "  Fill PnL:              $%.4f\n", stats.TotalFillPnL)
@@ -162,6 +176,9 @@ func (c *Console) PrintPaperReport(stats domain.PaperStats) {
 	fmt.Fprintf(c.out, "\n  --- COMPOUND ROTATION ---\n")
 	fmt.Fprintf(c.out, "  Initial capital:       $%.0f\n", stats.InitialCapital)
 	fmt.Fprintf(c.out, "  Total rotations:       %d\n", stats.TotalRotations)
+	if reasons := domain.FormatCloseReasons(stats.CloseReasons); reasons != "" {
+		fmt.Fprintf(c.out, "  Expired by reason:     %s\n", reasons)
+	}
 	fmt.Fprintf(c.out, "  Total merge profit:    $%.4f\n", stats.TotalMergeProfit)
 	if stats.InitialCapital > 0 {
 		effectiveCap := stats.InitialCapital + stats.TotalMergeProfit
@@ -190,6 +207,9 @@ func (c *Console) PrintPaperReport(stats domain.PaperStats) {
 		}
 	}
 
+	fmt.Fprintf(c.out, "\n  --- P&L BY MARKET ---\n")
+	c.printMarketAttribution(stats.Markets)
+
 	fmt.Fprintf(c.out, "\n  --- VERDICT ---\n")
 	if stats.DaysRunning < 3 {
 		fmt.Fprintf(c.out, "  Need at least 3 days of data. Currently %d days.\n", stats.DaysRunning)
@@ -214,5 +234,108 @@ func (c *Console) PrintPaperReport(stats domain.PaperStats) {
 		fmt.Fprintf(c.out, "  Do NOT use real money. Review strategy.\n")
 	}
 
+	if stats.DaysRunning >= domain.MinProjectionDays {
+		fmt.Fprintf(c.out, "\n  --- PROJECTION ---\n")
+		c.printProjection(projectionBase(stats.CompoundBalance, stats.InitialCapital), stats.DailyAvgPnL)
+	}
+
 	fmt.Fprintln(c.out)
 }
+
+// filledRatio formats filled of orders as "42% (21/50)".
+func filledRatio(filled, orders int) string {
+	if orders == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%% (%d/%d)", 100*float64(filled)/float64(orders), filled, orders)
+}
+
+// PrintPaperCompare prints, per paper order of a condition (--paper-compare),
+// the fill the engine recorded next to the one replayed from the full trade
+// history, and how close the two are.
+func (c *Console) PrintPaperCompare(conditionID string, rows []domain.PaperFillComparison) {
+	fmt.Fprintf(c.out, "\n── PAPER COMPARE %s ──\n", conditionID)
+	if len(rows) == 0 {
+		fmt.Fprintln(c.out, "  (no paper orders for this market)")
+		return
+	}
+	fmt.Fprintf(c.out, "  %s\n\n", rows[0].Order.Question)
+
+	fmt.Fprintf(c.out, "  %-10s %-4s %5s %7s %7s  %-19s %-19s %8s\n",
+		"ORDER", "SIDE", "BID", "SIZE", "QUEUE", "PREDICTED_FILL_TIME", "ACTUAL_FILL_TIME", "ACCURACY")
+	var sumAcc float64
+	for _, r := range rows {
+		o := r.Order
+		acc := r.QueueModelAccuracyPct()
+		sumAcc += acc
+		fmt.Fprintf(c.out, "  %-10s %-4s %5.2f %7.2f %7.0f  %-19s %-19s %7.0f%%\n",
+			o.ID[:min(10, len(o.ID))], o.Side, o.BidPrice, o.Size, o.QueueAhead,
+			compareFillTime(r.PredictedFillTime, r.PredictedFilled),
+			compareFillTime(r.ActualFillTime, r.ActualFilled), acc)
+	}
+	fmt.Fprintf(c.out, "\n  Queue model accuracy: %.0f%% average over %d orders\n", sumAcc/float64(len(rows)), len(rows))
+}
+
+// compareFillTime renders a fill time, "partial $X" when the order only
+// partly filled and "-" when it never did.
+func compareFillTime(at *time.Time, filled float64) string {
+	switch {
+	case at != nil:
+		return at.UTC().Format("2006-01-02 15:04:05")
+	case filled > 0:
+		return fmt.Sprintf("partial $%.2f", filled)
+	default:
+		return "-"
+	}
+}
+
+// PrintSweepReport prints the variants of a parameter sweep (--sweep) side
+// by side, in the order they were configured.
+func (c *Console) PrintSweepReport(results []domain.SweepResult) {
+	fmt.Fprintln(c.out, "\n── PAPER SWEEP ──")
+	if len(results) == 0 {
+		fmt.Fprintln(c.out, "  (no sweep variants configured)")
+		return
+	}
+	fmt.Fprintf(c.out, "  %-12s %6s %4s %6s %5s %10s %7s %10s %10s\n",
+		"VARIANT", "SIZE", "MKTS", "STALE", "DAYS", "FILLS/DAY", "MERGES", "NET_PNL", "MAX_DD")
+	for _, r := range results {
+		s := r.Stats
+		fmt.Fprintf(c.out, "  %-12s %6.0f %4d %5.0fh %5d %10.1f %7d %10s %10s\n",
+			r.Name[:min(12, len(r.Name))], r.OrderSize, r.MaxMarkets, r.StaleHours, s.DaysRunning,
+			s.FillRateReal, s.TotalRotations, fmt.Sprintf("$%.2f", s.NetPnL), fmt.Sprintf("$%.2f", s.MaxDrawdown()))
+	}
+}
+
+// heatmapBlocks shade an hour by its fills relative to the busiest hour.
+var heatmapBlocks = []rune{'░', '▒', '▓', '█'}
+
+// PrintFillHeatmap prints paper fills per UTC hour of the day (--fill-heatmap)
+// as one block per hour, shaded by count; hours without fills are blank.
+// Below it, the busiest and quietest hours as a recommendation.
+func (c *Console) PrintFillHeatmap(days int, hours []domain.HourlyFillStats) {
+	fmt.Fprintf(c.out, "\n── FILL HEATMAP (UTC, last %d days) ──\n", days)
+	peak, quiet, ok := domain.PeakAndQuietHours(hours)
+	if !ok {
+		fmt.Fprintln(c.out, "  (no paper fills in this window)")
+		return
+	}
+	maxFills := peak.TotalFills
+
+	var tens, units, cells strings.Builder
+	for _, h := range hours {
+		tens.WriteByte(byte('0' + h.Hour/10))
+		units.WriteByte(byte('0' + h.Hour%10))
+		if h.TotalFills == 0 {
+			cells.WriteRune(' ')
+			continue
+		}
+		level := int(math.Ceil(float64(h.TotalFills)/float64(maxFills)*float64(len(heatmapBlocks)))) - 1
+		cells.WriteRune(heatmapBlocks[max(0, level)])
+	}
+	fmt.Fprintf(c.out, "  %s\n  %s\n  %s\n", tens.String(), units.String(), cells.String())
+	fmt.Fprintf(c.out, "  %s = %d fills\n\n", string(heatmapBlocks[len(heatmapBlocks)-1]), maxFills)
+
+	fmt.Fprintf(c.out, "  Peak hour:   %02d:00 UTC (%.1f fills/day) — keep the bot running\n", peak.Hour, peak.AvgFills)
+	fmt.Fprintf(c.out, "  Quiet hour:  %02d:00 UTC (%.1f fills/day) — best window for maintenance\n", quiet.Hour, quiet.AvgFills)
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintOpportunityStats imprime el resumen de tendencias del scanner
+// (--scan-stats) a partir de lo guardado, sin lanzar un scan.
+func (c *Console) PrintOpportunityStats(stats domain.OpportunityStats) {
+	fmt.Fprintf(c.out, "\n═══ OPPORTUNITY STATS ═══\n")
+	fmt.Fprintf(c.out, "  Gold markets (7d):     %d\n", stats.GoldAppearances)
+	fmt.Fprintf(c.out, "  Avg Gold lifetime:     %.1fh\n", stats.AvgGoldLifetimeHours)
+	fmt.Fprintf(c.out, "  New this week:         %d\n", stats.NewThisWeek)
+	fmt.Fprintf(c.out, "  Resolved this week:    %d\n", stats.ResolvedThisWeek)
+
+	fmt.Fprintf(c.out, "\n── LONGEST-LIVED GOLD ──\n")
+	if len(stats.TopByLongevity) == 0 {
+		fmt.Fprintln(c.out, "  (none)")
+		return
+	}
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("#", "Market", "Lifetime", "First seen", "Last seen", "Peak")
+	for i, m := range stats.TopByLongevity {
+		tbl.Append(
+			fmt.Sprintf("%d", i+1),
+			domain.TruncateQuestion(m.Question, m.ConditionID, 40),
+			fmt.Sprintf("%.1fh", m.LifetimeHours),
+			m.FirstSeen.Format("01-02 15:04"),
+			m.LastSeen.Format("01-02 15:04"),
+			fmt.Sprintf("$%.4f", m.PeakCombined),
+		)
+	}
+	tbl.Render()
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/olekukonko/tablewriter"
+)
+
+// PrintScoreHistory imprime la evolución de los componentes del score de un
+// mercado (--score-history). Las filas donde cambió la categoría se marcan
+// con «←» para ver de un vistazo cuándo y por qué bajó o subió.
+func (c *Console) PrintScoreHistory(conditionID string, audits []domain.ScoreAudit) {
+	fmt.Fprintf(c.out, "\n═══ SCORE HISTORY %s ═══\n", conditionID)
+	if len(audits) == 0 {
+		fmt.Fprintf(c.out, "  No score audits for this market (only Gold/Silver scans are recorded).\n")
+		return
+	}
+
+	tbl := tablewriter.NewWriter(c.out)
+	tbl.Header("Scanned", "Cat", "Score", "SpreadScore", "Share", "BE fills", "Qual", "Spread", "Comp", "$/day", "")
+	for i, a := range audits {
+		changed := ""
+		if i > 0 && audits[i-1].Category != a.Category {
+			changed = "←"
+		}
+		breakEven := "∞"
+		if !math.IsInf(a.BreakEvenFills, 0) {
+			breakEven = fmt.Sprintf("%.1f", a.BreakEvenFills)
+		}
+		qualifies := "no"
+		if a.QualifiesReward {
+			qualifies = "yes"
+		}
+		tbl.Append(
+			a.ScannedAt.Format("01-02 15:04"),
+			a.Category.String(),
+			fmt.Sprintf("%.4f", a.CombinedScore),
+			fmt.Sprintf("%.3f", a.SpreadScore),
+			fmt.Sprintf("%.1f%%", a.YourShare*100),
+			breakEven,
+			qualifies,
+			fmt.Sprintf("%.3f", a.SpreadTotal),
+			fmt.Sprintf("$%.0f", a.Competition),
+			fmt.Sprintf("$%.4f", a.YourDailyReward),
+			changed,
+		)
+	}
+	tbl.Render()
+
+	first, last := audits[0], audits[len(audits)-1]
+	fmt.Fprintf(c.out, "  %d scans, %s → %s: score %.4f → %.4f, share %.1f%% → %.1f%%\n",
+		len(audits), first.Category, last.Category,
+		first.CombinedScore, last.CombinedScore, first.YourShare*100, last.YourShare*100)
+}
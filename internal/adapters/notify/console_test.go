@@ -3,6 +3,7 @@ package notify_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -89,3 +90,297 @@ func TestConsole_Compact_LongNameTruncated(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotContains(t, buf.String(), strings.Repeat("A", 50))
 }
+
+func TestConsole_LiveReport_VerdictVsPaper(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintLiveReport(notify.LiveReportInput{
+		Stats: domain.LiveStats{
+			DaysRunning:    5,
+			NetPnL:         0.50,
+			DailyAvgPnL:    0.10,
+			InitialCapital: 100,
+			Dailies:        []domain.LiveDailySummary{{Date: time.Now(), NetPnL: 0.10}},
+		},
+		CircuitBreaker: domain.CircuitBreaker{Triggered: true, TriggeredReason: "max drawdown exceeded"},
+		Paper:          &domain.PaperStats{DaysRunning: 7, DailyAvgPnL: 10, InitialCapital: 1000},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "TRIGGERED (reason: max drawdown exceeded)")
+	assert.Contains(t, out, "Return on capital:  +0.50%")
+	assert.Contains(t, out, "Realization:        10% of paper")
+	assert.Contains(t, out, "UNDERPERFORMING")
+}
+
+func TestConsole_LiveReport_FillLatency(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintLiveReport(notify.LiveReportInput{Stats: domain.LiveStats{
+		FillLatency: domain.FillLatencyStats{
+			All: domain.FillLatency{Count: 3, Min: 30 * time.Second, Median: 42 * time.Minute, P90: 3 * time.Hour, Max: 3 * time.Hour},
+			Yes: domain.FillLatency{Count: 3, Min: 30 * time.Second, Median: 42 * time.Minute, P90: 3 * time.Hour, Max: 3 * time.Hour},
+		},
+	}})
+
+	out := buf.String()
+	assert.Contains(t, out, "FILL LATENCY")
+	assert.Contains(t, out, "  all        3       30s     42m0s    3h0m0s    3h0m0s")
+	assert.Contains(t, out, "  NO         0         -")
+}
+
+func TestConsole_LiveReport_ShadowSeparate(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintLiveReport(notify.LiveReportInput{
+		Stats:  domain.LiveStats{TotalOrders: 4},
+		Shadow: &domain.LiveStats{Shadow: true, DaysRunning: 2, TotalOrders: 12, NetPnL: 0.30},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "LIVE TRADING REPORT")
+	assert.Contains(t, out, "── SHADOW (dry-run, not real money) ──")
+	assert.Contains(t, out, "Orders placed:      12")
+	assert.Contains(t, out, "Total Orders: 4 |")
+}
+
+func TestConsole_LiveReport_TopThreeExposures(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintLiveReport(notify.LiveReportInput{
+		Exposures: []domain.ConditionExposure{
+			{ConditionID: "0x1", Question: "Market A", USDC: 10, Pct: 0.20},
+			{ConditionID: "0x2", Question: "Market B", USDC: 8, Pct: 0.16},
+			{ConditionID: "0x3", Question: "Market C", USDC: 5, Pct: 0.10},
+			{ConditionID: "0x4", Question: "Market D", USDC: 1, Pct: 0.02},
+		},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "Top concentration:")
+	assert.Contains(t, out, "$  10.00  20.0%  Market A")
+	assert.Contains(t, out, "Market C")
+	assert.NotContains(t, out, "Market D")
+}
+
+func TestConsole_LiveReport_GasRunway(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	// 4 merges burned 0.2 POL over 2 days: 0.05 POL/merge, 0.1 POL/day.
+	n.PrintLiveReport(notify.LiveReportInput{
+		Stats: domain.LiveStats{DaysRunning: 2, CompletePairs: 4, TotalGasUsedPOL: 0.2, POLBalance: 1.5},
+	})
+
+	assert.Contains(t, buf.String(), "Gas (POL):    0.2000 used | 1.5000 left (~30 merges, ~15 days)")
+}
+
+func TestConsole_LiveReport_RotationsByReason(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintLiveReport(notify.LiveReportInput{
+		Stats: domain.LiveStats{TotalRotations: 27, CloseReasons: map[domain.CloseReason]int{
+			domain.CloseSpread: 5, domain.CloseStale: 12, domain.CloseNearEnd: 7, domain.CloseCompetition: 3,
+		}},
+	})
+
+	assert.Contains(t, buf.String(), "By reason:    stale 12, near_end 7, spread 5, competition 3")
+}
+
+func TestConsole_PaperReport_ProjectionAfterAWeek(t *testing.T) {
+	stats := domain.PaperStats{
+		DaysRunning:     7,
+		NetPnL:          70,
+		DailyAvgPnL:     10,
+		InitialCapital:  1000,
+		CompoundBalance: 1000,
+	}
+
+	var buf bytes.Buffer
+	notify.NewConsoleWriter(&buf, false, false).PrintPaperReport(stats)
+	out := buf.String()
+	assert.Contains(t, out, "--- PROJECTION ---")
+	assert.Greater(t, strings.Index(out, "--- PROJECTION ---"), strings.Index(out, "--- VERDICT ---"))
+	assert.Contains(t, out, "  30d  $     1347.85")
+	assert.Contains(t, out, "Assumes constant fill rates")
+
+	stats.DaysRunning = 6
+	buf.Reset()
+	notify.NewConsoleWriter(&buf, false, false).PrintPaperReport(stats)
+	assert.NotContains(t, buf.String(), "PROJECTION", "too little data to project")
+}
+
+func TestConsole_PaperReport_WinnersAndLosers(t *testing.T) {
+	var markets []domain.MarketPnL
+	for i, pnl := range []float64{-3, -2, -1, -0.5, -0.25, -0.1, 0.5, 1, 2} {
+		markets = append(markets, domain.MarketPnL{
+			ConditionID: fmt.Sprintf("0x%d", i), Question: fmt.Sprintf("Market %d", i), NetPnL: pnl,
+		})
+	}
+
+	var buf bytes.Buffer
+	notify.NewConsoleWriter(&buf, false, false).PrintPaperReport(domain.PaperStats{DaysRunning: 1, Markets: markets})
+	out := buf.String()
+
+	losers := out[strings.Index(out, "Top 5 losers"):strings.Index(out, "Top 5 winners")]
+	assert.Contains(t, losers, "Market 0")
+	assert.Contains(t, losers, "Market 4")
+	assert.NotContains(t, losers, "Market 5", "only the five worst")
+	winners := out[strings.Index(out, "Top 5 winners"):]
+	assert.Less(t, strings.Index(winners, "Market 8"), strings.Index(winners, "Market 6"), "best first")
+	assert.NotContains(t, winners, "Market 5")
+}
+
+func TestConsole_MarketTimeline(t *testing.T) {
+	var buf bytes.Buffer
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	notify.NewConsoleWriter(&buf, false, false).PrintMarketTimeline("0xabc",
+		&domain.MarketPnL{Question: "Will it rain?", Merges: 1, NetPnL: 0.25},
+		[]domain.MarketEvent{
+			{At: at, Kind: "PLACED", OrderID: "order-123456789", Side: "YES", Price: 0.45, Size: 10},
+			{Kind: "CANCELLED", OrderID: "order-2", Side: "NO"},
+		})
+	out := buf.String()
+	assert.Contains(t, out, "MARKET TIMELINE 0xabc")
+	assert.Contains(t, out, "Net $0.2500")
+	assert.Contains(t, out, "2026-03-01 10:00:00 PLACED")
+	assert.Contains(t, out, "order-1234 ")
+	assert.Contains(t, out, "CANCELLED")
+}
+
+func TestConsole_PaperCompare(t *testing.T) {
+	var buf bytes.Buffer
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	notify.NewConsoleWriter(&buf, false, false).PrintPaperCompare("0xabc", []domain.PaperFillComparison{
+		{
+			Order:           domain.VirtualOrder{ID: "order-123456789", Side: "YES", BidPrice: 0.45, Size: 100, Question: "Will it rain?"},
+			PredictedFilled: 30, ActualFilled: 100, ActualFillTime: &at,
+		},
+		{Order: domain.VirtualOrder{ID: "order-2", Side: "NO", BidPrice: 0.5, Size: 100}},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "PAPER COMPARE 0xabc")
+	assert.Contains(t, out, "partial $30.00")
+	assert.Contains(t, out, "2026-03-01 10:00:00")
+	assert.Contains(t, out, "     30%")
+	assert.Contains(t, out, "accuracy: 65% average over 2 orders")
+}
+
+func TestConsole_SweepReport(t *testing.T) {
+	var buf bytes.Buffer
+	notify.NewConsoleWriter(&buf, false, false).PrintSweepReport([]domain.SweepResult{
+		{Name: "small", OrderSize: 25, MaxMarkets: 3, StaleHours: 4, Stats: domain.PaperStats{
+			DaysRunning: 2, FillRateReal: 7.5, TotalRotations: 4, NetPnL: 3.2,
+			Dailies: []domain.PaperDailySummary{{NetPnL: 2}, {NetPnL: -1.5}},
+		}},
+		{Name: "large", OrderSize: 100, MaxMarkets: 8, StaleHours: 8},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "PAPER SWEEP")
+	assert.Contains(t, out, "small")
+	assert.Contains(t, out, "7.5")
+	assert.Contains(t, out, "$3.20")
+	assert.Contains(t, out, "$1.50", "max drawdown")
+	assert.Contains(t, out, "large")
+}
+
+func TestConsole_FillHeatmap(t *testing.T) {
+	hours := make([]domain.HourlyFillStats, 24)
+	for h := range hours {
+		hours[h].Hour = h
+	}
+	hours[2] = domain.HourlyFillStats{Hour: 2, TotalFills: 1, AvgFills: 0.5}
+	hours[3] = domain.HourlyFillStats{Hour: 3, TotalFills: 8, AvgFills: 4}
+	hours[0] = domain.HourlyFillStats{Hour: 0, TotalFills: 4, AvgFills: 2}
+
+	var buf bytes.Buffer
+	notify.NewConsoleWriter(&buf, false, false).PrintFillHeatmap(2, hours)
+	out := buf.String()
+	assert.Contains(t, out, "FILL HEATMAP (UTC, last 2 days)")
+	assert.Contains(t, out, "  000000000011111111112222\n  012345678901234567890123\n  ▒ ░█    ")
+	assert.Contains(t, out, "Peak hour:   03:00 UTC (4.0 fills/day)")
+	assert.Contains(t, out, "Quiet hour:  01:00 UTC (0.0 fills/day)")
+
+	buf.Reset()
+	notify.NewConsoleWriter(&buf, false, false).PrintFillHeatmap(2, make([]domain.HourlyFillStats, 24))
+	assert.Contains(t, buf.String(), "no paper fills")
+}
+
+func TestConsole_OpportunityStats(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewConsoleWriter(&buf, false, false)
+
+	n.PrintOpportunityStats(domain.OpportunityStats{
+		GoldAppearances:      4,
+		AvgGoldLifetimeHours: 12.5,
+		TopByLongevity: []domain.MarketSummary{
+			{ConditionID: "0x1", Question: "Long runner", LifetimeHours: 30},
+		},
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "Gold markets (7d):     4")
+	assert.Contains(t, out, "Avg Gold lifetime:     12.5h")
+	assert.Contains(t, out, "Long runner")
+	assert.Contains(t, out, "30.0h")
+}
+
+func TestConsole_Backtest_RewardDiscrepancy(t *testing.T) {
+	var buf bytes.Buffer
+	c := notify.NewConsoleWriter(&buf, false, false)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	c.PrintBacktest(&domain.BacktestResult{
+		From: from, To: from.Add(48 * time.Hour), OrderSize: 100,
+		Days: []domain.BacktestDay{{Date: from, Markets: 1, Reward: 2, PnL: 2}},
+		Markets: []domain.BacktestMarket{{
+			ConditionID: "0xc1", Question: "Will it rain?", EstimatedReward: 2, ActualReward: 0.5,
+		}},
+		RewardDays: 1, EstimatedReward: 2, ActualReward: 0.5,
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "REWARD CHECK (1 paid days)")
+	assert.Contains(t, out, "Actually paid:    $0.5000")
+	assert.Contains(t, out, "-1.5000 (-75%)")
+}
+
+func TestConsole_Backtest_QueueModelCheck(t *testing.T) {
+	var buf bytes.Buffer
+	c := notify.NewConsoleWriter(&buf, false, false)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	c.PrintBacktest(&domain.BacktestResult{
+		From: from, To: from.Add(48 * time.Hour), OrderSize: 100,
+		Days: []domain.BacktestDay{{Date: from, Markets: 1, FillsYES: 4}},
+		Markets: []domain.BacktestMarket{{
+			ConditionID: "0xc1", Question: "Will it rain?", FillsYES: 4,
+			PredictedFillsPerDay: 3, FillsBothPerDay: 2, PredictionError: 0.5,
+		}},
+		PredictedFillsPerDay: 3, FillsBothPerDay: 2, OverallQueueModelError: 0.5,
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "QUEUE MODEL CHECK")
+	assert.Contains(t, out, "+50%")
+	assert.Contains(t, out, "OVER-estimates fills")
+}
+
+func TestConsole_BalanceReconciliation(t *testing.T) {
+	var buf bytes.Buffer
+	r := domain.BalanceReconciliation{
+		WalletBalance: 90, InitialCapital: 100, MergeProfit: 0.9, GasSpent: 0.1,
+		Merges: 1, RestingBids: 5, LockedFilled: 4.5, Tolerance: 1,
+	}
+	notify.NewConsoleWriter(&buf, false, false).PrintBalanceReconciliation(r)
+
+	out := buf.String()
+	assert.Contains(t, out, "Expected:           $96.40")
+	assert.Contains(t, out, "Unexplained:        -6.50")
+	assert.Contains(t, out, "!! exceeds $1.00")
+}
@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// JSON implementa ports.Notifier escribiendo una oportunidad por línea (NDJSON).
+// Cada registro es autocontenido: incluye mercado, books, arbitraje y scores,
+// de modo que puede procesarse downstream sin estado adicional.
+type JSON struct {
+	out io.Writer
+}
+
+// NewJSON crea un notificador JSON que escribe a stdout.
+func NewJSON() *JSON {
+	return &JSON{out: os.Stdout}
+}
+
+// NewJSONWriter crea un notificador JSON sobre un writer arbitrario (fichero, tests).
+func NewJSONWriter(w io.Writer) *JSON {
+	return &JSON{out: w}
+}
+
+// Notify serializa cada oportunidad como una línea JSON independiente.
+func (j *JSON) Notify(_ context.Context, opportunities []domain.Opportunity) error {
+	enc := json.NewEncoder(j.out)
+	for _, opp := range opportunities {
+		if err := enc.Encode(toJSONOpportunity(opp)); err != nil {
+			return fmt.Errorf("notify.JSON: encode %s: %w", opp.Market.ConditionID, err)
+		}
+	}
+	return nil
+}
+
+// jsonOpportunity es el formato estable de salida. Los nombres de campo forman
+// parte del contrato con los consumidores: no renombrar sin versionar.
+type jsonOpportunity struct {
+	ScannedAt time.Time  `json:"scanned_at"`
+	Market    jsonMarket `json:"market"`
+	YesBook   jsonBook   `json:"yes_book"`
+	NoBook    jsonBook   `json:"no_book"`
+
+	SpreadTotal     float64 `json:"spread_total"`
+	QualifiesReward bool    `json:"qualifies_reward"`
+
+	Arbitrage jsonArbitrage `json:"arbitrage"`
+
+	Competition      float64 `json:"competition"`
+	CompetitionScore float64 `json:"competition_score"`
+	YourShare        float64 `json:"your_share"`
+	SpreadScore      float64 `json:"spread_score"`
+	YourDailyReward  float64 `json:"your_daily_reward"`
+
+	LegacyYourShare       float64 `json:"legacy_your_share"`
+	LegacyYourDailyReward float64 `json:"legacy_your_daily_reward"`
+
+	FillCostPerPair float64  `json:"fill_cost_per_pair"`
+	FillCostUSDC    float64  `json:"fill_cost_usdc"`
+	BreakEvenFills  *float64 `json:"break_even_fills"` // null = fills gratis (∞)
+
+	PnLNoFills float64 `json:"pnl_no_fills"`
+	PnL1Fill   float64 `json:"pnl_1_fill"`
+	PnL3Fills  float64 `json:"pnl_3_fills"`
+
+	CombinedScore float64 `json:"combined_score"`
+	Category      string  `json:"category"`
+	Verdict       string  `json:"verdict"`
+}
+
+type jsonMarket struct {
+	ConditionID  string      `json:"condition_id"`
+	QuestionID   string      `json:"question_id"`
+	Question     string      `json:"question"`
+	Slug         string      `json:"slug"`
+	EndDate      *time.Time  `json:"end_date"`
+	Volume24h    float64     `json:"volume_24h"`
+	MakerBaseFee float64     `json:"maker_base_fee"`
+	Tokens       []jsonToken `json:"tokens"`
+	RewardDaily  float64     `json:"reward_daily_rate"`
+	RewardMin    float64     `json:"reward_min_size"`
+	RewardSpread float64     `json:"reward_max_spread"`
+	Active       bool        `json:"active"`
+	Closed       bool        `json:"closed"`
+}
+
+type jsonToken struct {
+	TokenID string  `json:"token_id"`
+	Outcome string  `json:"outcome"`
+	Price   float64 `json:"price"`
+}
+
+type jsonBook struct {
+	TokenID string      `json:"token_id"`
+	Bids    []jsonLevel `json:"bids"`
+	Asks    []jsonLevel `json:"asks"`
+}
+
+type jsonLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+type jsonArbitrage struct {
+	BestAskYES   float64          `json:"best_ask_yes"`
+	BestAskNO    float64          `json:"best_ask_no"`
+	DepthYES     float64          `json:"depth_yes"`
+	DepthNO      float64          `json:"depth_no"`
+	MaxFillable  float64          `json:"max_fillable"`
+	SumBestAsk   float64          `json:"sum_best_ask"`
+	FeesTotal    float64          `json:"fees_total"`
+	ArbitrageGap float64          `json:"arbitrage_gap"`
+	HasArbitrage bool             `json:"has_arbitrage"`
+	AtDepth      []jsonDepthLevel `json:"at_depth"`
+}
+
+type jsonDepthLevel struct {
+	DepthUSDC    float64 `json:"depth_usdc"`
+	AvgPriceYES  float64 `json:"avg_price_yes"`
+	AvgPriceNO   float64 `json:"avg_price_no"`
+	Sum          float64 `json:"sum"`
+	GapAfterFees float64 `json:"gap_after_fees"`
+	Profitable   bool    `json:"profitable"`
+}
+
+func toJSONOpportunity(o domain.Opportunity) jsonOpportunity {
+	return jsonOpportunity{
+		ScannedAt:       o.ScannedAt.UTC(),
+		Market:          toJSONMarket(o.Market),
+		YesBook:         toJSONBook(o.YesBook),
+		NoBook:          toJSONBook(o.NoBook),
+		SpreadTotal:     finite(o.SpreadTotal),
+		QualifiesReward: o.QualifiesReward,
+		Arbitrage:       toJSONArbitrage(o.Arbitrage),
+		Competition:     finite(o.Competition),
+		YourShare:       finite(o.YourShare),
+		SpreadScore:     finite(o.SpreadScore),
+		YourDailyReward: finite(o.YourDailyReward),
+		FillCostPerPair: finite(o.FillCostPerPair),
+		FillCostUSDC:    finite(o.FillCostUSDC),
+		BreakEvenFills:  nullableFloat(o.BreakEvenFills),
+		PnLNoFills:      finite(o.PnLNoFills),
+		PnL1Fill:        finite(o.PnL1Fill),
+		PnL3Fills:       finite(o.PnL3Fills),
+		CombinedScore:   finite(o.CombinedScore),
+		Category:        o.Category.String(),
+		Verdict:         o.Verdict(),
+
+		CompetitionScore:      finite(o.CompetitionScore),
+		LegacyYourShare:       finite(o.LegacyYourShare),
+		LegacyYourDailyReward: finite(o.LegacyYourDailyReward),
+	}
+}
+
+func toJSONMarket(m domain.Market) jsonMarket {
+	jm := jsonMarket{
+		ConditionID:  m.ConditionID,
+		QuestionID:   m.QuestionID,
+		Question:     m.Question,
+		Slug:         m.Slug,
+		Volume24h:    m.Volume24h,
+		MakerBaseFee: m.MakerBaseFee,
+		Tokens:       make([]jsonToken, 0, len(m.Tokens)),
+		RewardDaily:  m.Rewards.DailyRate,
+		RewardMin:    m.Rewards.MinSize,
+		RewardSpread: m.Rewards.MaxSpread,
+		Active:       m.Active,
+		Closed:       m.Closed,
+	}
+	if !m.EndDate.IsZero() {
+		end := m.EndDate.UTC()
+		jm.EndDate = &end
+	}
+	for _, t := range m.Tokens {
+		jm.Tokens = append(jm.Tokens, jsonToken{TokenID: t.TokenID, Outcome: t.Outcome, Price: t.Price})
+	}
+	return jm
+}
+
+func toJSONBook(b domain.OrderBook) jsonBook {
+	return jsonBook{TokenID: b.TokenID, Bids: toJSONLevels(b.Bids), Asks: toJSONLevels(b.Asks)}
+}
+
+func toJSONLevels(entries []domain.BookEntry) []jsonLevel {
+	levels := make([]jsonLevel, 0, len(entries))
+	for _, e := range entries {
+		levels = append(levels, jsonLevel{Price: e.Price, Size: e.Size})
+	}
+	return levels
+}
+
+func toJSONArbitrage(a domain.ArbitrageResult) jsonArbitrage {
+	ja := jsonArbitrage{
+		BestAskYES:   finite(a.BestAskYES),
+		BestAskNO:    finite(a.BestAskNO),
+		DepthYES:     finite(a.DepthYES),
+		DepthNO:      finite(a.DepthNO),
+		MaxFillable:  finite(a.MaxFillable),
+		SumBestAsk:   finite(a.SumBestAsk),
+		FeesTotal:    finite(a.FeesTotal),
+		ArbitrageGap: finite(a.ArbitrageGap),
+		HasArbitrage: a.HasArbitrage,
+		AtDepth:      make([]jsonDepthLevel, 0, len(a.AtDepth)),
+	}
+	for _, d := range a.AtDepth {
+		ja.AtDepth = append(ja.AtDepth, jsonDepthLevel{
+			DepthUSDC:    finite(d.DepthUSDC),
+			AvgPriceYES:  finite(d.AvgPriceYES),
+			AvgPriceNO:   finite(d.AvgPriceNO),
+			Sum:          finite(d.Sum),
+			GapAfterFees: finite(d.GapAfterFees),
+			Profitable:   d.Profitable,
+		})
+	}
+	return ja
+}
+
+// nullableFloat convierte ±Inf/NaN en nil para que encoding/json no falle.
+func nullableFloat(v float64) *float64 {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+// finite sustituye valores no finitos por 0 en campos que no admiten null.
+func finite(v float64) float64 {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,50 @@
+package notify_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/notify"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON_OneRecordPerLine_InfAsNull(t *testing.T) {
+	var buf bytes.Buffer
+	n := notify.NewJSONWriter(&buf)
+
+	free := makeOpp("Free fills", 0.50, 0.10)
+	free.BreakEvenFills = math.Inf(1)
+	free.YesBook = domain.OrderBook{
+		TokenID: "yes",
+		Bids:    []domain.BookEntry{{Price: 0.48, Size: 100}},
+		Asks:    []domain.BookEntry{{Price: 0.50, Size: 80}},
+	}
+	free.Arbitrage = domain.ArbitrageResult{
+		AtDepth: []domain.DepthLevel{{DepthUSDC: 50, Sum: 0.99, GapAfterFees: 0.01, Profitable: true}},
+	}
+
+	opps := []domain.Opportunity{free, makeOpp("Normal", 0.30, 0.10)}
+	require.NoError(t, n.Notify(context.Background(), opps))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Nil(t, rec["break_even_fills"])
+	assert.Equal(t, "SILV", rec["category"])
+
+	yes := rec["yes_book"].(map[string]any)
+	assert.Len(t, yes["bids"], 1)
+	arb := rec["arbitrage"].(map[string]any)
+	assert.Len(t, arb["at_depth"], 1)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.InDelta(t, 3.0, rec["break_even_fills"], 1e-9)
+}
@@ -0,0 +1,229 @@
+package notify
+
+// tui.go — Panel de terminal para live trading.
+//
+// En vez de una línea por ciclo, la TUI redibuja la pantalla completa con el
+// estado del portfolio: posiciones, circuit breaker, fracción Kelly, capital
+// desplegado y los últimos eventos. Se dibuja con secuencias ANSI y
+// tablewriter; si stdout no es un terminal, NewLiveDisplay devuelve la consola.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+const (
+	// tuiMaxEvents es cuántos eventos recientes muestra el panel.
+	tuiMaxEvents = 10
+	// tuiBarWidth es el ancho en celdas de las barras Kelly y capital.
+	tuiBarWidth = 20
+
+	ansiClear = "\x1b[H\x1b[2J"
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+// LiveDisplay es lo que el modo live necesita para mostrar su estado:
+// oportunidades del scan y el resultado de cada ciclo.
+type LiveDisplay interface {
+	ports.Notifier
+	LiveStatusPrinter
+}
+
+// NewLiveDisplay devuelve la TUI si se pidió y f es un terminal; en otro caso
+// (pipe, fichero, CI) devuelve console, que imprime línea a línea.
+func NewLiveDisplay(f *os.File, console *Console, tui bool) LiveDisplay {
+	if tui && isTerminal(f) {
+		return NewTUIWriter(f)
+	}
+	return console
+}
+
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// TUI implementa ports.Notifier y LiveStatusPrinter redibujando un panel.
+type TUI struct {
+	out io.Writer
+
+	mu     sync.Mutex
+	status LiveStatusInput
+	cycles int
+	events []string // ring de los últimos tuiMaxEvents, el más antiguo primero
+}
+
+// NewTUIWriter crea una TUI sobre un writer arbitrario (terminal, tests).
+func NewTUIWriter(w io.Writer) *TUI {
+	return &TUI{out: w}
+}
+
+// Notify registra el resultado del scan como evento y redibuja.
+func (t *TUI) Notify(_ context.Context, opportunities []domain.Opportunity) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(opportunities) == 0 {
+		t.addEvent("scan: no opportunities found")
+	} else {
+		best := opportunities[0]
+		t.addEvent(fmt.Sprintf("scan: %d opportunities (top %s, $%.2f/day)",
+			len(opportunities), truncate(marketLabel(best.Market), 30), best.YourDailyReward))
+	}
+	t.render()
+	return nil
+}
+
+// PrintLiveStatus guarda el resultado del ciclo, añade sus eventos y redibuja.
+func (t *TUI) PrintLiveStatus(in LiveStatusInput) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status = in
+	t.cycles++
+
+	if in.NewOrders > 0 {
+		t.addEvent(fmt.Sprintf("+%d orders placed", in.NewOrders))
+	}
+	if in.NewFills > 0 {
+		t.addEvent(fmt.Sprintf("+%d fills", in.NewFills))
+	}
+	if in.Merges > 0 {
+		t.addEvent(fmt.Sprintf("+%d merges ($%.4f)", in.Merges, in.MergeProfit))
+	}
+	for _, a := range in.PartialAlerts {
+		t.addEvent("!! " + a)
+	}
+	for _, w := range in.Warnings {
+		t.addEvent(">> " + w)
+	}
+	t.render()
+}
+
+// Event añade un evento libre al log del panel (p. ej. desde el bucle live).
+func (t *TUI) Event(msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addEvent(msg)
+	t.render()
+}
+
+func (t *TUI) addEvent(msg string) {
+	t.events = append(t.events, time.Now().Format("15:04:05")+" "+msg)
+	if len(t.events) > tuiMaxEvents {
+		t.events = t.events[len(t.events)-tuiMaxEvents:]
+	}
+}
+
+// render redibuja la pantalla completa. Se llama con t.mu tomado.
+func (t *TUI) render() {
+	var sb strings.Builder
+	in := t.status
+
+	sb.WriteString(ansiClear)
+	fmt.Fprintf(&sb, " POLYBOT LIVE  %s  cycle %d   breaker %s\n\n",
+		time.Now().Format("15:04:05"), t.cycles, breakerLabel(in.CircuitOpen))
+	fmt.Fprintf(&sb, " Kelly    %s %5.1f%%\n", bar(in.KellyFraction), in.KellyFraction*100)
+	deployedPct := 0.0
+	if in.CompoundBalance > 0 {
+		deployedPct = in.CapitalDeployed / in.CompoundBalance
+	}
+	fmt.Fprintf(&sb, " Capital  %s %5.1f%%  $%.2f / $%.2f\n\n",
+		bar(deployedPct), deployedPct*100, in.CapitalDeployed, in.CompoundBalance)
+
+	open := openPositions(in.Positions)
+	fmt.Fprintf(&sb, " POSITIONS (%d)\n", len(open))
+	if len(open) == 0 {
+		sb.WriteString("  (none)\n")
+	} else {
+		tbl := tablewriter.NewWriter(&sb)
+		tbl.Header("Market", "YES", "NO", "Age", "PnL")
+		now := time.Now()
+		for _, pos := range open {
+			tbl.Append(
+				domain.TruncateQuestion(pos.Question, pos.ConditionID, 40),
+				legStatus(pos.YesOrder),
+				legStatus(pos.NoOrder),
+				positionAge(pos, now).String(),
+				fmt.Sprintf("$%.4f", pos.MergeProfit+pos.RewardAccrued),
+			)
+		}
+		tbl.Render()
+	}
+
+	sb.WriteString("\n EVENTS\n")
+	if len(t.events) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	for _, e := range t.events {
+		fmt.Fprintf(&sb, "  %s\n", e)
+	}
+	fmt.Fprint(t.out, sb.String())
+}
+
+// openPositions descarta las posiciones ya mergeadas o sin órdenes.
+func openPositions(positions []domain.LivePosition) []domain.LivePosition {
+	out := make([]domain.LivePosition, 0, len(positions))
+	for _, pos := range positions {
+		if pos.IsMerged || (pos.YesOrder == nil && pos.NoOrder == nil) {
+			continue
+		}
+		out = append(out, pos)
+	}
+	return out
+}
+
+// legStatus resume el estado de fill de una pata.
+func legStatus(o *domain.LiveOrder) string {
+	switch {
+	case o == nil:
+		return "-"
+	case o.Status == domain.LiveStatusFilled:
+		return "FILLED"
+	case o.FilledSize > 0:
+		return fmt.Sprintf("PART %.0f%%", o.FilledSize/o.Size*100)
+	default:
+		return string(o.Status)
+	}
+}
+
+// positionAge es el tiempo desde la pata colocada primero.
+func positionAge(pos domain.LivePosition, now time.Time) time.Duration {
+	var placed time.Time
+	for _, o := range []*domain.LiveOrder{pos.YesOrder, pos.NoOrder} {
+		if o != nil && !o.PlacedAt.IsZero() && (placed.IsZero() || o.PlacedAt.Before(placed)) {
+			placed = o.PlacedAt
+		}
+	}
+	if placed.IsZero() {
+		return 0
+	}
+	return now.Sub(placed).Truncate(time.Minute)
+}
+
+func breakerLabel(open bool) string {
+	if open {
+		return ansiGreen + "● OK" + ansiReset
+	}
+	return ansiRed + "● TRIPPED" + ansiReset
+}
+
+// bar dibuja una barra de tuiBarWidth celdas para una fracción en [0, 1].
+func bar(frac float64) string {
+	filled := int(math.Round(math.Max(0, math.Min(frac, 1)) * tuiBarWidth))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", tuiBarWidth-filled) + "]"
+}
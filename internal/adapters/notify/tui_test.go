@@ -0,0 +1,74 @@
+package notify_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/notify"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTUI_RendersPanel(t *testing.T) {
+	var buf bytes.Buffer
+	tui := notify.NewTUIWriter(&buf)
+
+	placed := time.Now().Add(-2 * time.Hour)
+	tui.PrintLiveStatus(notify.LiveStatusInput{
+		Positions: []domain.LivePosition{{
+			ConditionID:   "0xabc",
+			Question:      "Will it rain tomorrow?",
+			YesOrder:      &domain.LiveOrder{Status: domain.LiveStatusFilled, Size: 10, FilledSize: 10, PlacedAt: placed},
+			NoOrder:       &domain.LiveOrder{Status: domain.LiveStatusPartial, Size: 10, FilledSize: 5, PlacedAt: placed},
+			RewardAccrued: 0.25,
+		}},
+		NewFills:        2,
+		CapitalDeployed: 50,
+		CompoundBalance: 200,
+		KellyFraction:   0.5,
+		CircuitOpen:     false,
+	})
+
+	out := buf.String()
+	assert.Contains(t, out, "Will it rain tomorrow?")
+	assert.Contains(t, out, "FILLED")
+	assert.Contains(t, out, "PART 50%")
+	assert.Contains(t, out, "2h0m0s")
+	assert.Contains(t, out, "$0.2500")
+	assert.Contains(t, out, "TRIPPED")
+	assert.Contains(t, out, " 50.0%")
+	assert.Contains(t, out, "$50.00 / $200.00")
+	assert.Contains(t, out, "+2 fills")
+}
+
+func TestTUI_KeepsLastTenEvents(t *testing.T) {
+	var buf bytes.Buffer
+	tui := notify.NewTUIWriter(&buf)
+	for i := range 12 {
+		tui.Event(fmt.Sprintf("event-%02d", i))
+	}
+	buf.Reset()
+	require.NoError(t, tui.Notify(context.Background(), nil))
+
+	out := buf.String()
+	assert.NotContains(t, out, "event-00")
+	assert.NotContains(t, out, "event-02")
+	assert.Contains(t, out, "event-03")
+	assert.Contains(t, out, "event-11")
+	assert.Contains(t, out, "no opportunities found")
+}
+
+func TestNewLiveDisplay_FallsBackToConsoleWhenNotTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	console := notify.NewConsoleWriter(f, false, false)
+	assert.Same(t, console, notify.NewLiveDisplay(f, console, true))
+}
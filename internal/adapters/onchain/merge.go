@@ -7,16 +7,14 @@ package onchain
 //   100 YES tokens + 100 NO tokens → $100 USDC.e
 //
 // This file handles:
-//   - Dynamic gas estimation
+//   - Dynamic gas estimation (EIP-1559 type-2 fees), priced via a PriceFeed
 //   - ERC1155 approval checks/setup
 //   - Atomic on-chain merge transactions
 
 import (
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"math/big"
 	"net/http"
@@ -29,7 +27,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
@@ -51,13 +48,25 @@ const (
 	mergeGasLimit    = uint64(200_000)
 	approvalGasLimit = uint64(80_000)
 
-	// POL price fallback (USD) — used when no oracle available
-	polPriceFallbackUSD = 0.12
-
 	// Gas price update interval
 	gasPriceUpdateInterval = 5 * time.Minute
+
+	// Gas price assumed when the node cannot quote one (see SetGasPriceFallback)
+	defaultGasPriceFallbackGwei = 100
+
+	// Priority fee used when the node cannot suggest one
+	fallbackTipWei = int64(30_000_000_000) // 30 gwei
+
+	// Merge retry defaults (see MergeRetryPolicy)
+	defaultMergeAttempts   = 3
+	defaultMergeGasBump    = 1.25
+	defaultMergeBackoff    = 2 * time.Second
+	defaultMergePendingMax = 60 * time.Second
 )
 
+// receiptPollInterval is how often waitForReceipt asks the node for a receipt.
+var receiptPollInterval = 3 * time.Second
+
 // Contract ABIs
 var (
 	ctfABI     abi.ABI
@@ -137,22 +146,29 @@ func init() {
 
 // MergeClient implements ports.MergeExecutor.
 type MergeClient struct {
-	client     *ethclient.Client
+	client     *RPCPool
 	privateKey []byte
 	address    common.Address
-	rpcURL     string
 	httpClient *http.Client
 
-	mu             sync.RWMutex
-	cachedGasWei   *big.Int
-	gasUpdatedAt   time.Time
-	cachedPOLPrice float64
-	polPriceAt     time.Time
+	mu            sync.RWMutex
+	cachedGasWei  *big.Int
+	gasUpdatedAt  time.Time
+	cachedTipWei  *big.Int // EIP-1559 priority fee
+	cachedFeeCap  *big.Int // EIP-1559 maxFeePerGas
+	cachedBaseFee *big.Int // base fee of the block the fees were read at
+	feesUpdatedAt time.Time
+
+	prices         PriceFeed // POL/USD, for gas costs in USD
+	retry          MergeRetryPolicy
+	negRisk        bool     // NegRisk merges via the adapter (EnableNegRisk)
+	fallbackGasWei *big.Int // gas price when the node cannot quote one
+	maxGasWei      *big.Int // no merges above this gas price (nil = no ceiling)
 }
 
-// NewMergeClient creates a merge executor connected to the given Polygon RPC.
+// NewMergeClient creates a merge executor on the given Polygon RPC pool.
 // privateKeyHex is without 0x prefix.
-func NewMergeClient(rpcURL, privateKeyHex string) (*MergeClient, error) {
+func NewMergeClient(rpc *RPCPool, privateKeyHex string) (*MergeClient, error) {
 	pkBytes, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("merge: decode private key: %w", err)
@@ -165,95 +181,89 @@ func NewMergeClient(rpcURL, privateKeyHex string) (*MergeClient, error) {
 
 	addr := crypto.PubkeyToAddress(privKey.PublicKey)
 
-	client, err := ethclient.Dial(rpcURL)
+	prices, err := NewPriceOracle(rpc, DefaultOracleConfig())
 	if err != nil {
-		return nil, fmt.Errorf("merge: dial rpc %s: %w", rpcURL, err)
+		return nil, fmt.Errorf("merge: %w", err)
 	}
 
 	return &MergeClient{
-		client:     client,
-		privateKey: pkBytes,
-		address:    addr,
-		rpcURL:     rpcURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		client:         rpc,
+		privateKey:     pkBytes,
+		address:        addr,
+		httpClient:     &http.Client{Timeout: 10 * time.Second},
+		prices:         prices,
+		retry:          DefaultMergeRetryPolicy(),
+		fallbackGasWei: gweiToWei(defaultGasPriceFallbackGwei),
 	}, nil
 }
 
-// EstimateGasCostUSD returns the estimated gas cost in USD for a merge transaction.
-func (mc *MergeClient) EstimateGasCostUSD(ctx context.Context) (float64, error) {
-	gasPrice, err := mc.getGasPrice(ctx)
-	if err != nil {
-		return mc.polPriceUSD() * float64(mergeGasLimit) * 100e-9, nil
+// SetGasPriceFallback sets the gas price (gwei) used when the node cannot
+// quote one, both to estimate merge costs and to price transactions.
+// Values <= 0 keep the default of 100 gwei.
+func (mc *MergeClient) SetGasPriceFallback(gwei float64) {
+	if gwei <= 0 {
+		gwei = defaultGasPriceFallbackGwei
 	}
-
-	gasCostPOL := new(big.Float).SetInt(new(big.Int).Mul(gasPrice, big.NewInt(int64(mergeGasLimit))))
-	gasCostPOL.Quo(gasCostPOL, new(big.Float).SetFloat64(1e18))
-
-	gasCostPOLf, _ := gasCostPOL.Float64()
-	return gasCostPOLf * mc.polPriceUSD(), nil
+	mc.fallbackGasWei = gweiToWei(gwei)
 }
 
-// polPriceUSD returns the cached POL price, refreshing from CoinGecko if stale.
-func (mc *MergeClient) polPriceUSD() float64 {
-	mc.mu.RLock()
-	price := mc.cachedPOLPrice
-	updatedAt := mc.polPriceAt
-	mc.mu.RUnlock()
-
-	if price > 0 && time.Since(updatedAt) < 15*time.Minute {
-		return price
+// SetMaxGasPrice sets the gas price (gwei) above which merges are refused
+// with a *domain.GasCeilingError instead of sent, deferring them until gas
+// normalizes. Values <= 0 remove the ceiling.
+func (mc *MergeClient) SetMaxGasPrice(gwei float64) {
+	if gwei <= 0 {
+		mc.maxGasWei = nil
+		return
 	}
+	mc.maxGasWei = gweiToWei(gwei)
+}
 
-	fetched, err := mc.fetchPOLPrice()
+// EstimateGasCostUSD returns the estimated gas cost in USD for a merge transaction,
+// priced at the EIP-1559 effective gas price min(maxFee, baseFee+tip).
+func (mc *MergeClient) EstimateGasCostUSD(ctx context.Context) (float64, error) {
+	polUSD, err := mc.prices.POLPriceUSD(ctx)
 	if err != nil {
-		slog.Warn("merge: failed to fetch POL price, using fallback", "err", err)
-		if price > 0 {
-			return price
-		}
-		return polPriceFallbackUSD
+		return 0, fmt.Errorf("merge: gas cost: %w", err)
 	}
-
-	mc.mu.Lock()
-	mc.cachedPOLPrice = fetched
-	mc.polPriceAt = time.Now()
-	mc.mu.Unlock()
-
-	return fetched
-}
-
-// fetchPOLPrice queries CoinGecko for the current POL/USD price.
-func (mc *MergeClient) fetchPOLPrice() (float64, error) {
-	const url = "https://api.coingecko.com/api/v3/simple/price?ids=polygon-ecosystem-token&vs_currencies=usd"
-
-	resp, err := mc.httpClient.Get(url)
+	gasCostPOL, err := mc.EstimateGasCostPOL(ctx)
 	if err != nil {
 		return 0, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("coingecko status %d: %s", resp.StatusCode, body)
-	}
+	return gasCostPOL * polUSD, nil
+}
 
-	var data map[string]map[string]float64
-	if err := json.Unmarshal(body, &data); err != nil {
-		return 0, err
-	}
+// EstimateGasCostPOL returns the estimated gas cost in POL for a merge
+// transaction, at the fallback gas price when the node cannot quote fees.
+func (mc *MergeClient) EstimateGasCostPOL(ctx context.Context) (float64, error) {
+	gasPrice := mc.fallbackGasWei
+	if tip, maxFee, err := mc.getEIP1559Fees(ctx); err == nil {
+		mc.mu.RLock()
+		baseFee := mc.cachedBaseFee
+		mc.mu.RUnlock()
+		gasPrice = effectiveGasPrice(baseFee, tip, maxFee)
+	}
+	return weiToPOL(new(big.Int).Mul(gasPrice, big.NewInt(int64(mergeGasLimit)))), nil
+}
 
-	price, ok := data["polygon-ecosystem-token"]["usd"]
-	if !ok || price <= 0 {
-		return 0, fmt.Errorf("POL price not found in response")
+// GetPOLBalance returns the native POL balance of the wallet, which pays
+// for merge gas.
+func (mc *MergeClient) GetPOLBalance(ctx context.Context) (float64, error) {
+	wei, err := mc.client.BalanceAt(ctx, mc.address, nil)
+	if err != nil {
+		return 0, fmt.Errorf("merge: POL balance: %w", err)
 	}
+	return weiToPOL(wei), nil
+}
 
-	slog.Debug("merge: fetched POL price", "usd", price)
-	return price, nil
+// SetPriceFeed replaces the POL/USD source used to price gas in USD.
+func (mc *MergeClient) SetPriceFeed(f PriceFeed) {
+	mc.prices = f
 }
 
 // MergePositions executes an on-chain merge for the given condition.
 // amount is in USDC units (e.g., 10.0 = 10 USDC worth of tokens).
-// NegRisk markets are skipped for safety — they require the NegRisk adapter
-// with a market-specific parentCollectionId that we don't have.
+// NegRisk markets are routed to MergeNegRiskPositions and rejected unless
+// EnableNegRisk(true) was called.
 func (mc *MergeClient) MergePositions(ctx context.Context, conditionID string, amount float64, negRisk bool) (domain.MergeResult, error) {
 	result := domain.MergeResult{
 		ConditionID: conditionID,
@@ -261,14 +271,23 @@ func (mc *MergeClient) MergePositions(ctx context.Context, conditionID string, a
 	}
 
 	if negRisk {
-		result.Error = "NegRisk merges not yet supported — requires NegRisk adapter with parentCollectionId"
-		return result, fmt.Errorf("merge: %s", result.Error)
+		return mc.MergeNegRiskPositions(ctx, conditionID, amount)
 	}
 
+	to, callData, err := ctfMergeCall(conditionID, amount)
+	if err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("merge: %w", err)
+	}
+	return mc.submitMerge(ctx, to, callData, amount, result)
+}
+
+// ctfMergeCall builds the CTF mergePositions call for amount YES+NO sets of a
+// binary condition: USDC.e collateral, no parent collection, partition [1, 2].
+func ctfMergeCall(conditionID string, amount float64) (common.Address, []byte, error) {
 	condBytes, err := hexToBytes32(conditionID)
 	if err != nil {
-		result.Error = fmt.Sprintf("invalid conditionID: %v", err)
-		return result, err
+		return common.Address{}, nil, fmt.Errorf("invalid conditionID: %w", err)
 	}
 
 	amountInt := new(big.Int).SetInt64(int64(amount * 1_000_000))
@@ -282,94 +301,35 @@ func (mc *MergeClient) MergePositions(ctx context.Context, conditionID string, a
 		amountInt,
 	)
 	if err != nil {
-		result.Error = fmt.Sprintf("pack calldata: %v", err)
-		return result, fmt.Errorf("merge: pack: %w", err)
-	}
-
-	privKey, err := crypto.ToECDSA(mc.privateKey)
-	if err != nil {
-		result.Error = "invalid private key"
-		return result, fmt.Errorf("merge: private key: %w", err)
-	}
-
-	nonce, err := mc.client.PendingNonceAt(ctx, mc.address)
-	if err != nil {
-		result.Error = fmt.Sprintf("get nonce: %v", err)
-		return result, fmt.Errorf("merge: nonce: %w", err)
-	}
-
-	gasPrice, err := mc.getGasPrice(ctx)
-	if err != nil {
-		result.Error = fmt.Sprintf("get gas price: %v", err)
-		return result, fmt.Errorf("merge: gas price: %w", err)
+		return common.Address{}, nil, fmt.Errorf("pack calldata: %w", err)
 	}
+	return common.HexToAddress(ctfAddress), callData, nil
+}
 
-	ctfAddr := common.HexToAddress(ctfAddress)
-
-	// Estimate actual gas
-	gasEstimate, err := mc.client.EstimateGas(ctx, ethereum.CallMsg{
-		From:     mc.address,
-		To:       &ctfAddr,
-		GasPrice: gasPrice,
-		Data:     callData,
-	})
-	if err != nil {
-		// Fall back to conservative limit
-		gasEstimate = mergeGasLimit
-		slog.Warn("merge: gas estimate failed, using default", "err", err, "limit", mergeGasLimit)
+// submitMerge sends merge calldata to the CTF contract or NegRisk adapter and
+// fills in the gas cost and proceeds on result.
+func (mc *MergeClient) submitMerge(ctx context.Context, to common.Address, callData []byte, amount float64, result domain.MergeResult) (domain.MergeResult, error) {
+	conditionID := result.ConditionID
+	if err := mc.checkGasCeiling(ctx); err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("merge: %w", err)
 	}
-	// Add 20% buffer
-	gasEstimate = gasEstimate * 12 / 10
+	gasEstimate := mc.estimateMergeGas(ctx, to, callData)
 
-	tx := types.NewTransaction(
-		nonce,
-		ctfAddr,
-		big.NewInt(0), // no ETH value
-		gasEstimate,
-		gasPrice,
-		callData,
-	)
-
-	chainID := big.NewInt(polygonChainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privKey)
+	receipt, maxFee, err := mc.sendWithRetry(ctx, to, gasEstimate, callData, &result)
 	if err != nil {
-		result.Error = fmt.Sprintf("sign tx: %v", err)
-		return result, fmt.Errorf("merge: sign tx: %w", err)
+		result.Error = err.Error()
+		return result, fmt.Errorf("merge: %w", err)
 	}
-
-	if err := mc.client.SendTransaction(ctx, signedTx); err != nil {
-		result.Error = fmt.Sprintf("send tx: %v", err)
-		return result, fmt.Errorf("merge: send tx: %w", err)
-	}
-
-	txHash := signedTx.Hash().Hex()
-	result.TxHash = txHash
-	slog.Info("merge: transaction sent", "condition", conditionID[:12]+"...", "amount", amount, "tx", txHash)
-
-	// Wait for receipt (up to 60s)
-	receiptCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
-
-	receipt, err := mc.waitForReceipt(receiptCtx, signedTx.Hash())
-	if err != nil {
+	if receipt == nil {
 		// TX sent but we couldn't confirm — mark as potentially succeeded
-		slog.Warn("merge: could not confirm receipt, tx may still succeed", "tx", txHash, "err", err)
+		slog.Warn("merge: could not confirm receipt, tx may still succeed", "tx", result.TxHash)
 		result.Success = true // optimistic
 		result.USDCReceived = amount
 		return result, nil
 	}
 
-	if receipt.Status != types.ReceiptStatusSuccessful {
-		result.Error = "transaction reverted on-chain"
-		return result, fmt.Errorf("merge: tx reverted: %s", txHash)
-	}
-
-	// Calculate gas cost
-	gasUsedPOL := new(big.Float).SetUint64(receipt.GasUsed)
-	gasPriceF := new(big.Float).SetInt(gasPrice)
-	gasCostWei := new(big.Float).Mul(gasUsedPOL, gasPriceF)
-	gasCostPOL, _ := new(big.Float).Quo(gasCostWei, new(big.Float).SetFloat64(1e18)).Float64()
-	gasCostUSD := gasCostPOL * mc.polPriceUSD()
+	gasCostPOL, gasCostUSD := mc.receiptGasCost(ctx, receipt, maxFee)
 
 	result.Success = true
 	result.GasUsedPOL = gasCostPOL
@@ -379,7 +339,7 @@ func (mc *MergeClient) MergePositions(ctx context.Context, conditionID string, a
 
 	slog.Info("merge: confirmed",
 		"condition", conditionID[:12]+"...",
-		"tx", txHash,
+		"tx", result.TxHash,
 		"gas_usdc", fmt.Sprintf("$%.4f", gasCostUSD),
 		"usdc_received", amount,
 	)
@@ -387,6 +347,39 @@ func (mc *MergeClient) MergePositions(ctx context.Context, conditionID string, a
 	return result, nil
 }
 
+// receiptGasCost prices a mined transaction's gas in POL and USD at the
+// price actually paid (never above maxFee).
+func (mc *MergeClient) receiptGasCost(ctx context.Context, receipt *types.Receipt, maxFee *big.Int) (float64, float64) {
+	gasPrice := maxFee
+	if receipt.EffectiveGasPrice != nil && receipt.EffectiveGasPrice.Sign() > 0 {
+		gasPrice = receipt.EffectiveGasPrice
+	}
+	gasUsedPOL := new(big.Float).SetUint64(receipt.GasUsed)
+	gasPriceF := new(big.Float).SetInt(gasPrice)
+	gasCostWei := new(big.Float).Mul(gasUsedPOL, gasPriceF)
+	gasCostPOL, _ := new(big.Float).Quo(gasCostWei, new(big.Float).SetFloat64(1e18)).Float64()
+	polUSD, err := mc.prices.POLPriceUSD(ctx)
+	if err != nil {
+		slog.Warn("merge: POL price unavailable, gas cost not priced", "err", err)
+	}
+	return gasCostPOL, gasCostPOL * polUSD
+}
+
+// estimateMergeGas estimates gas for a merge call with a 20% buffer, falling
+// back to mergeGasLimit when the node cannot simulate it.
+func (mc *MergeClient) estimateMergeGas(ctx context.Context, to common.Address, callData []byte) uint64 {
+	gasEstimate, err := mc.client.EstimateGas(ctx, ethereum.CallMsg{
+		From: mc.address,
+		To:   &to,
+		Data: callData,
+	})
+	if err != nil {
+		gasEstimate = mergeGasLimit
+		slog.Warn("merge: gas estimate failed, using default", "err", err, "limit", mergeGasLimit)
+	}
+	return gasEstimate * 12 / 10
+}
+
 // EnsureApprovals checks and sets both:
 //   - ERC1155 setApprovalForAll on the three exchange contracts (for token transfers)
 //   - ERC20 USDC.e approve for both exchange contracts (for BUY collateral)
@@ -474,16 +467,16 @@ func (mc *MergeClient) setApprovalForAll(ctx context.Context, operator common.Ad
 		return fmt.Errorf("nonce: %w", err)
 	}
 
-	gasPrice, err := mc.getGasPrice(ctx)
+	tip, maxFee, err := mc.getEIP1559Fees(ctx)
 	if err != nil {
-		return fmt.Errorf("gas price: %w", err)
+		return fmt.Errorf("gas fees: %w", err)
 	}
 
 	ctfAddr := common.HexToAddress(ctfAddress)
-	tx := types.NewTransaction(nonce, ctfAddr, big.NewInt(0), approvalGasLimit, gasPrice, callData)
+	tx := newDynamicFeeTx(nonce, ctfAddr, approvalGasLimit, tip, maxFee, callData)
 
 	chainID := big.NewInt(polygonChainID)
-	signed, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privKey)
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privKey)
 	if err != nil {
 		return err
 	}
@@ -544,15 +537,15 @@ func (mc *MergeClient) erc20Approve(ctx context.Context, token, spender common.A
 		return fmt.Errorf("nonce: %w", err)
 	}
 
-	gasPrice, err := mc.getGasPrice(ctx)
+	tip, maxFee, err := mc.getEIP1559Fees(ctx)
 	if err != nil {
-		return fmt.Errorf("gas price: %w", err)
+		return fmt.Errorf("gas fees: %w", err)
 	}
 
-	tx := types.NewTransaction(nonce, token, big.NewInt(0), approvalGasLimit, gasPrice, callData)
+	tx := newDynamicFeeTx(nonce, token, approvalGasLimit, tip, maxFee, callData)
 
 	chainID := big.NewInt(polygonChainID)
-	signed, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privKey)
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privKey)
 	if err != nil {
 		return err
 	}
@@ -590,7 +583,7 @@ func (mc *MergeClient) getGasPrice(ctx context.Context) (*big.Int, error) {
 		if cached != nil {
 			return cached, nil
 		}
-		return big.NewInt(30_000_000_000), nil // 30 gwei fallback
+		return new(big.Int).Set(mc.fallbackGasWei), nil
 	}
 
 	// Add 10% buffer for faster inclusion (copy to avoid mutating SuggestGasPrice return)
@@ -606,9 +599,105 @@ func (mc *MergeClient) getGasPrice(ctx context.Context) (*big.Int, error) {
 	return price, nil
 }
 
+// checkGasCeiling returns a *domain.GasCeilingError when the price a merge
+// would pay now, min(maxFee, baseFee+tip), is above the configured ceiling.
+func (mc *MergeClient) checkGasCeiling(ctx context.Context) error {
+	if mc.maxGasWei == nil {
+		return nil
+	}
+	tip, maxFee, err := mc.getEIP1559Fees(ctx)
+	if err != nil {
+		return fmt.Errorf("gas fees: %w", err)
+	}
+	mc.mu.RLock()
+	baseFee := mc.cachedBaseFee
+	mc.mu.RUnlock()
+	if price := effectiveGasPrice(baseFee, tip, maxFee); price.Cmp(mc.maxGasWei) > 0 {
+		return &domain.GasCeilingError{PriceGwei: weiToGwei(price), CeilingGwei: weiToGwei(mc.maxGasWei)}
+	}
+	return nil
+}
+
+// getEIP1559Fees returns the priority fee (tip) and maxFeePerGas for a type-2
+// transaction: maxFee = 2*baseFee + tip, which stays valid through several
+// full blocks of base-fee increases. Cached like the legacy gas price.
+func (mc *MergeClient) getEIP1559Fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	mc.mu.RLock()
+	tip, maxFee := mc.cachedTipWei, mc.cachedFeeCap
+	updatedAt := mc.feesUpdatedAt
+	mc.mu.RUnlock()
+
+	if maxFee != nil && time.Since(updatedAt) < gasPriceUpdateInterval {
+		return tip, maxFee, nil
+	}
+
+	suggested, err := mc.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		slog.Warn("merge: tip cap suggestion failed, using fallback", "err", err, "tip_gwei", weiToGwei(big.NewInt(fallbackTipWei)))
+		suggested = big.NewInt(fallbackTipWei)
+	}
+
+	var baseFee *big.Int
+	header, err := mc.client.HeaderByNumber(ctx, nil)
+	switch {
+	case err == nil && header.BaseFee != nil:
+		baseFee = header.BaseFee
+	case maxFee != nil:
+		// Keep the stale fees rather than guessing.
+		return tip, maxFee, nil
+	default:
+		// No base fee available: the legacy gas price is a close upper bound.
+		legacy, lerr := mc.getGasPrice(ctx)
+		if lerr != nil {
+			return nil, nil, fmt.Errorf("base fee: %w", lerr)
+		}
+		baseFee = legacy
+	}
+
+	tip = new(big.Int).Set(suggested)
+	maxFee = new(big.Int).Mul(baseFee, big.NewInt(2))
+	maxFee.Add(maxFee, tip)
+
+	mc.mu.Lock()
+	mc.cachedTipWei = tip
+	mc.cachedFeeCap = maxFee
+	mc.cachedBaseFee = new(big.Int).Set(baseFee)
+	mc.feesUpdatedAt = time.Now()
+	mc.mu.Unlock()
+
+	return tip, maxFee, nil
+}
+
+// effectiveGasPrice is what a type-2 transaction pays per gas:
+// min(maxFee, baseFee+tip). With no known base fee it assumes the cap.
+func effectiveGasPrice(baseFee, tip, maxFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return maxFee
+	}
+	price := new(big.Int).Add(baseFee, tip)
+	if price.Cmp(maxFee) > 0 {
+		return maxFee
+	}
+	return price
+}
+
+// newDynamicFeeTx builds an unsigned EIP-1559 transaction on Polygon.
+func newDynamicFeeTx(nonce uint64, to common.Address, gasLimit uint64, tip, maxFee *big.Int, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(polygonChainID),
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: maxFee,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      data,
+	})
+}
+
 // waitForReceipt polls for a transaction receipt until confirmed or timeout.
 func (mc *MergeClient) waitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
-	ticker := time.NewTicker(3 * time.Second)
+	ticker := time.NewTicker(receiptPollInterval)
 	defer ticker.Stop()
 
 	for {
@@ -0,0 +1,209 @@
+package onchain
+
+// merge_retry.go — Bounded retry for merge transactions.
+//
+// A merge that reverts or fails to send is retried with bumped fees.
+// A merge that stays pending past PendingTimeout is replaced: the same nonce
+// is re-signed with a higher tip and fee cap so the stuck transaction is
+// superseded (nodes require both to rise for a type-2 replacement).
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// MergeRetryPolicy controls how merge transactions are retried within a cycle.
+type MergeRetryPolicy struct {
+	MaxAttempts    int           // total submissions, including the first
+	GasBump        float64       // tip and fee cap multiplier applied per retry (e.g. 1.25)
+	Backoff        time.Duration // wait before the first retry, doubled each time
+	PendingTimeout time.Duration // how long to wait for a receipt before replacing
+}
+
+// DefaultMergeRetryPolicy returns the policy used when none is configured.
+func DefaultMergeRetryPolicy() MergeRetryPolicy {
+	return MergeRetryPolicy{
+		MaxAttempts:    defaultMergeAttempts,
+		GasBump:        defaultMergeGasBump,
+		Backoff:        defaultMergeBackoff,
+		PendingTimeout: defaultMergePendingMax,
+	}
+}
+
+// SetRetryPolicy overrides the merge retry policy. Zero fields keep their defaults.
+func (mc *MergeClient) SetRetryPolicy(p MergeRetryPolicy) {
+	def := DefaultMergeRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.GasBump < 1.1 {
+		// Nodes reject replacements priced below +10%.
+		p.GasBump = def.GasBump
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = def.Backoff
+	}
+	if p.PendingTimeout <= 0 {
+		p.PendingTimeout = def.PendingTimeout
+	}
+	mc.retry = p
+}
+
+// sendWithRetry signs and sends a transaction, retrying per the retry policy.
+// Submissions that fail to send or revert are appended to
+// result.FailedAttempts; a pending one that gets replaced is not. Returns a nil
+// receipt (and nil error) when the last submission is still pending. The
+// returned price is the maxFeePerGas of the last submission.
+func (mc *MergeClient) sendWithRetry(
+	ctx context.Context,
+	to common.Address,
+	gasLimit uint64,
+	callData []byte,
+	result *domain.MergeResult,
+) (*types.Receipt, *big.Int, error) {
+	privKey, err := crypto.ToECDSA(mc.privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("private key: %w", err)
+	}
+
+	nonce, err := mc.client.PendingNonceAt(ctx, mc.address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("nonce: %w", err)
+	}
+
+	tip, gasPrice, err := mc.getEIP1559Fees(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gas fees: %w", err)
+	}
+
+	policy := mc.retry
+	signer := types.LatestSignerForChainID(big.NewInt(polygonChainID))
+	var pending common.Hash
+	var lastErr error
+
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			wait := policy.Backoff << (attempt - 1)
+			select {
+			case <-ctx.Done():
+				return nil, gasPrice, ctx.Err()
+			case <-time.After(wait):
+			}
+			tip = bumpGasPrice(tip, policy.GasBump)
+			gasPrice = bumpGasPrice(gasPrice, policy.GasBump)
+		}
+
+		tx := newDynamicFeeTx(nonce, to, gasLimit, tip, gasPrice, callData)
+		signed, err := types.SignTx(tx, signer, privKey)
+		if err != nil {
+			return nil, gasPrice, fmt.Errorf("sign tx: %w", err)
+		}
+
+		if err := mc.client.SendTransaction(ctx, signed); err != nil {
+			// A replaced tx may have been mined in the meantime.
+			if pending != (common.Hash{}) && strings.Contains(err.Error(), "nonce too low") {
+				if receipt, rerr := mc.client.TransactionReceipt(ctx, pending); rerr == nil {
+					if receipt.Status == types.ReceiptStatusSuccessful {
+						return receipt, gasPrice, nil
+					}
+				}
+				nonce, _ = mc.client.PendingNonceAt(ctx, mc.address)
+				pending = common.Hash{}
+			}
+			lastErr = fmt.Errorf("send tx: %w", err)
+			mc.recordAttempt(result, "", gasPrice, lastErr)
+			continue
+		}
+
+		result.TxHash = signed.Hash().Hex()
+		slog.Info("merge: transaction sent",
+			"condition", result.ConditionID[:12]+"...",
+			"tx", result.TxHash,
+			"attempt", attempt+1,
+			"max_fee_gwei", fmt.Sprintf("%.1f", weiToGwei(gasPrice)),
+			"tip_gwei", fmt.Sprintf("%.1f", weiToGwei(tip)),
+		)
+
+		receiptCtx, cancel := context.WithTimeout(ctx, policy.PendingTimeout)
+		receipt, err := mc.waitForReceipt(receiptCtx, signed.Hash())
+		cancel()
+
+		if err != nil {
+			// Still pending: resubmit with the same nonce and a higher price.
+			// Not a failed attempt, the replacement may yet confirm it.
+			pending = signed.Hash()
+			lastErr = nil
+			slog.Info("merge: transaction pending, replacing",
+				"condition", result.ConditionID[:12]+"...",
+				"tx", result.TxHash,
+				"after", policy.PendingTimeout,
+			)
+			continue
+		}
+
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			lastErr = fmt.Errorf("tx reverted: %s", result.TxHash)
+			mc.recordAttempt(result, result.TxHash, gasPrice, lastErr)
+			// A reverted tx consumes its nonce.
+			if next, err := mc.client.PendingNonceAt(ctx, mc.address); err == nil {
+				nonce = next
+			}
+			pending = common.Hash{}
+			continue
+		}
+
+		return receipt, gasPrice, nil
+	}
+
+	if lastErr == nil && pending != (common.Hash{}) {
+		return nil, gasPrice, nil
+	}
+	return nil, gasPrice, fmt.Errorf("gave up after %d attempts: %w", policy.MaxAttempts, lastErr)
+}
+
+// recordAttempt logs a failed submission and appends it to the merge result.
+func (mc *MergeClient) recordAttempt(result *domain.MergeResult, txHash string, gasPrice *big.Int, err error) {
+	slog.Warn("merge: attempt failed",
+		"condition", result.ConditionID[:12]+"...",
+		"tx", txHash,
+		"gas_gwei", fmt.Sprintf("%.1f", weiToGwei(gasPrice)),
+		"err", err,
+	)
+	result.FailedAttempts = append(result.FailedAttempts, domain.MergeAttempt{
+		TxHash:       txHash,
+		GasPriceGwei: weiToGwei(gasPrice),
+		Error:        err.Error(),
+		At:           time.Now().UTC(),
+	})
+}
+
+// bumpGasPrice multiplies a gas price (or tip) by factor without mutating the input.
+func bumpGasPrice(price *big.Int, factor float64) *big.Int {
+	bumped := new(big.Int).Mul(price, big.NewInt(int64(factor*100)))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func weiToGwei(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return f
+}
+
+func weiToPOL(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return f
+}
+
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
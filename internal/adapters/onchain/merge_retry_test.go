@@ -0,0 +1,190 @@
+package onchain
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// fakeChain is a JSON-RPC node that accepts or rejects each raw transaction
+// in turn and mines the accepted ones listed in mined.
+type fakeChain struct {
+	mu       sync.Mutex
+	sendErrs []string       // error for the n-th send; "" or missing accepts it
+	mined    map[int]uint64 // accepted tx index → receipt status
+	sends    int            // sends seen, accepted or not
+	accepted []*types.Transaction
+}
+
+func (fc *fakeChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, rpcErr := fc.handle(req.Method, req.Params)
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if rpcErr != "" {
+		resp["error"] = map[string]any{"code": -32000, "message": rpcErr}
+	} else {
+		resp["result"] = result
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (fc *fakeChain) handle(method string, params []json.RawMessage) (any, string) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	switch method {
+	case "eth_getTransactionCount":
+		return "0x7", ""
+	case "eth_sendRawTransaction":
+		n := fc.sends
+		fc.sends++
+		if n < len(fc.sendErrs) && fc.sendErrs[n] != "" {
+			return nil, fc.sendErrs[n]
+		}
+		var raw hexutil.Bytes
+		_ = json.Unmarshal(params[0], &raw)
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, err.Error()
+		}
+		fc.accepted = append(fc.accepted, tx)
+		return tx.Hash(), ""
+	case "eth_getTransactionReceipt":
+		var hash common.Hash
+		_ = json.Unmarshal(params[0], &hash)
+		for i, tx := range fc.accepted {
+			status, ok := fc.mined[i]
+			if tx.Hash() != hash || !ok {
+				continue
+			}
+			return map[string]any{
+				"status":            hexutil.Uint64(status),
+				"cumulativeGasUsed": "0x5208",
+				"gasUsed":           "0x5208",
+				"logsBloom":         hexutil.Bytes(make([]byte, types.BloomByteLength)),
+				"logs":              []any{},
+				"transactionHash":   hash,
+				"blockNumber":       "0x1",
+				"transactionIndex":  "0x0",
+				"blockHash":         common.Hash{},
+			}, ""
+		}
+		return nil, ""
+	}
+	return nil, "unsupported method " + method
+}
+
+func newRetryClient(t *testing.T, chain *fakeChain, policy MergeRetryPolicy) *MergeClient {
+	t.Helper()
+	srv := httptest.NewServer(chain)
+	t.Cleanup(srv.Close)
+	pool, err := DialRPCPool("merge-retry-test", srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	mc := &MergeClient{
+		client:        pool,
+		privateKey:    crypto.FromECDSA(key),
+		address:       crypto.PubkeyToAddress(key.PublicKey),
+		cachedTipWei:  gweiToWei(30),
+		cachedFeeCap:  gweiToWei(100),
+		feesUpdatedAt: time.Now(),
+	}
+	mc.SetRetryPolicy(policy)
+
+	poll := receiptPollInterval
+	receiptPollInterval = time.Millisecond
+	t.Cleanup(func() { receiptPollInterval = poll })
+	return mc
+}
+
+func sendTestMerge(t *testing.T, mc *MergeClient) (*types.Receipt, domain.MergeResult, error) {
+	t.Helper()
+	result := domain.MergeResult{ConditionID: "0x" + strings.Repeat("ab", 32)}
+	receipt, _, err := mc.sendWithRetry(context.Background(), common.HexToAddress(ctfAddress), mergeGasLimit, []byte{0x01}, &result)
+	return receipt, result, err
+}
+
+func TestSendWithRetry_RetriesFailedSendWithBumpedFees(t *testing.T) {
+	chain := &fakeChain{sendErrs: []string{"insufficient funds for gas"}, mined: map[int]uint64{0: 1}}
+	mc := newRetryClient(t, chain, MergeRetryPolicy{MaxAttempts: 3, GasBump: 1.25, Backoff: time.Millisecond, PendingTimeout: time.Second})
+
+	receipt, result, err := sendTestMerge(t, mc)
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	assert.Equal(t, 2, chain.sends)
+	require.Len(t, result.FailedAttempts, 1)
+	assert.Contains(t, result.FailedAttempts[0].Error, "insufficient funds")
+
+	tx := chain.accepted[0]
+	assert.Zero(t, tx.GasFeeCap().Cmp(gweiToWei(125)), "fee cap bumped once: %s", tx.GasFeeCap())
+	assert.Zero(t, tx.GasTipCap().Cmp(bumpGasPrice(gweiToWei(30), 1.25)))
+}
+
+func TestSendWithRetry_ReplacesPendingTxOnSameNonce(t *testing.T) {
+	// Neither submission is ever mined: both time out pending.
+	chain := &fakeChain{}
+	mc := newRetryClient(t, chain, MergeRetryPolicy{MaxAttempts: 2, GasBump: 1.25, Backoff: time.Millisecond, PendingTimeout: 20 * time.Millisecond})
+
+	receipt, result, err := sendTestMerge(t, mc)
+	require.NoError(t, err, "a still pending merge is not an error")
+	assert.Nil(t, receipt)
+	assert.Empty(t, result.FailedAttempts, "a replaced submission is not a failed merge")
+
+	require.Len(t, chain.accepted, 2)
+	first, second := chain.accepted[0], chain.accepted[1]
+	assert.Equal(t, first.Nonce(), second.Nonce(), "the replacement reuses the nonce")
+	assert.Equal(t, 1, second.GasFeeCap().Cmp(first.GasFeeCap()))
+	assert.Equal(t, 1, second.GasTipCap().Cmp(first.GasTipCap()))
+	assert.Equal(t, second.Hash().Hex(), result.TxHash)
+}
+
+func TestSendWithRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	chain := &fakeChain{mined: map[int]uint64{0: 0, 1: 0}}
+	mc := newRetryClient(t, chain, MergeRetryPolicy{MaxAttempts: 2, GasBump: 1.25, Backoff: time.Millisecond, PendingTimeout: time.Second})
+
+	_, result, err := sendTestMerge(t, mc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after 2 attempts")
+	assert.Len(t, result.FailedAttempts, 2, "each revert is recorded")
+}
+
+func TestSetRetryPolicy_KeepsDefaultsForUnsetFields(t *testing.T) {
+	mc := &MergeClient{}
+	mc.SetRetryPolicy(MergeRetryPolicy{MaxAttempts: 5, GasBump: 1.05})
+	def := DefaultMergeRetryPolicy()
+	assert.Equal(t, 5, mc.retry.MaxAttempts)
+	assert.Equal(t, def.GasBump, mc.retry.GasBump, "a bump under +10% is not a valid replacement")
+	assert.Equal(t, def.Backoff, mc.retry.Backoff)
+	assert.Equal(t, def.PendingTimeout, mc.retry.PendingTimeout)
+}
+
+func TestBumpGasPrice(t *testing.T) {
+	price := big.NewInt(100_000_000_000)
+	assert.Equal(t, big.NewInt(125_000_000_000), bumpGasPrice(price, 1.25))
+	assert.Equal(t, big.NewInt(100_000_000_000), price, "input left untouched")
+}
@@ -0,0 +1,147 @@
+package onchain
+
+// negrisk.go — Merges for NegRisk markets.
+//
+// NegRisk outcome tokens are minted by the NegRisk adapter against its own
+// wrapped collateral, so CTF.mergePositions with USDC.e and a zero parent
+// collection does not match them. The adapter exposes
+// mergePositions(conditionId, amount), which merges the YES/NO set, unwraps
+// the collateral and pays out USDC.e. The adapter must hold ERC1155 approval
+// on the CTF contract, which EnsureApprovals already sets.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+const gammaMarketsURL = "https://gamma-api.polymarket.com/markets"
+
+var negRiskAdapterABI abi.ABI
+
+func init() {
+	var err error
+	negRiskAdapterABI, err = abi.JSON(strings.NewReader(`[
+		{
+			"name": "mergePositions",
+			"type": "function",
+			"inputs": [
+				{"name": "_conditionId", "type": "bytes32"},
+				{"name": "_amount", "type": "uint256"}
+			],
+			"outputs": []
+		}
+	]`))
+	if err != nil {
+		panic("neg risk adapter abi parse: " + err.Error())
+	}
+}
+
+// negRiskMarket is the NegRisk metadata Gamma exposes for a condition.
+type negRiskMarket struct {
+	ConditionID     string `json:"conditionId"`
+	NegRisk         bool   `json:"negRisk"`
+	NegRiskMarketID string `json:"negRiskMarketID"`
+}
+
+// EnableNegRisk turns on NegRisk merges through the adapter. Off by default.
+func (mc *MergeClient) EnableNegRisk(enabled bool) {
+	mc.negRisk = enabled
+}
+
+// SupportsNegRisk reports whether MergePositions accepts NegRisk markets.
+func (mc *MergeClient) SupportsNegRisk() bool {
+	return mc.negRisk
+}
+
+// MergeNegRiskPositions merges amount YES+NO sets of a NegRisk market through
+// the NegRisk adapter. The market's negRiskMarketID is looked up on Gamma
+// first so conditions that are not actually NegRisk never reach the adapter.
+func (mc *MergeClient) MergeNegRiskPositions(ctx context.Context, conditionID string, amount float64) (domain.MergeResult, error) {
+	result := domain.MergeResult{
+		ConditionID: conditionID,
+		ExecutedAt:  time.Now().UTC(),
+	}
+
+	if !mc.negRisk {
+		result.Error = "NegRisk merges disabled (live.allow_neg_risk=false)"
+		return result, fmt.Errorf("merge: %s", result.Error)
+	}
+
+	meta, err := mc.fetchNegRiskMarket(ctx, conditionID)
+	if err != nil {
+		result.Error = fmt.Sprintf("negRisk metadata: %v", err)
+		return result, fmt.Errorf("merge: negRisk metadata: %w", err)
+	}
+	if !meta.NegRisk || meta.NegRiskMarketID == "" {
+		result.Error = "market is not registered on the NegRisk adapter"
+		return result, fmt.Errorf("merge: %s", result.Error)
+	}
+
+	to, callData, err := negRiskMergeCall(conditionID, amount)
+	if err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("merge: %w", err)
+	}
+	return mc.submitMerge(ctx, to, callData, amount, result)
+}
+
+// negRiskMergeCall builds the NegRisk adapter's mergePositions call for amount
+// YES+NO sets of conditionID.
+func negRiskMergeCall(conditionID string, amount float64) (common.Address, []byte, error) {
+	condBytes, err := hexToBytes32(conditionID)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("invalid conditionID: %w", err)
+	}
+
+	amountInt := new(big.Int).SetInt64(int64(amount * 1_000_000))
+	callData, err := negRiskAdapterABI.Pack("mergePositions", condBytes, amountInt)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("pack calldata: %w", err)
+	}
+	return common.HexToAddress(negRiskAdapter), callData, nil
+}
+
+// fetchNegRiskMarket queries Gamma for the NegRisk fields of a condition.
+func (mc *MergeClient) fetchNegRiskMarket(ctx context.Context, conditionID string) (negRiskMarket, error) {
+	u := fmt.Sprintf("%s?condition_ids=%s&limit=1", gammaMarketsURL, url.QueryEscape(conditionID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return negRiskMarket{}, err
+	}
+
+	resp, err := mc.httpClient.Do(req)
+	if err != nil {
+		return negRiskMarket{}, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return negRiskMarket{}, fmt.Errorf("gamma status %d: %s", resp.StatusCode, body)
+	}
+
+	var markets []negRiskMarket
+	if err := json.Unmarshal(body, &markets); err != nil {
+		return negRiskMarket{}, err
+	}
+	for _, m := range markets {
+		if strings.EqualFold(m.ConditionID, conditionID) {
+			slog.Debug("merge: negRisk metadata", "condition", conditionID, "neg_risk_market_id", m.NegRiskMarketID)
+			return m, nil
+		}
+	}
+	return negRiskMarket{}, fmt.Errorf("condition %s not found on gamma", conditionID)
+}
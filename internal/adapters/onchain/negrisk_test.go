@@ -0,0 +1,65 @@
+package onchain
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConditionID = "0x" + strings.Repeat("ab", 32)
+
+func TestNegRiskMergeCall_TargetsAdapter(t *testing.T) {
+	to, data, err := negRiskMergeCall(testConditionID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(negRiskAdapter), to)
+
+	method := negRiskAdapterABI.Methods["mergePositions"]
+	require.Equal(t, method.ID, data[:4])
+	args, err := method.Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	cond, err := hexToBytes32(testConditionID)
+	require.NoError(t, err)
+	assert.Equal(t, cond, args[0])
+	assert.Equal(t, big.NewInt(10_000_000), args[1])
+}
+
+func TestCTFMergeCall_TargetsCTF(t *testing.T) {
+	to, data, err := ctfMergeCall(testConditionID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(ctfAddress), to)
+
+	method := ctfABI.Methods["mergePositions"]
+	require.Equal(t, method.ID, data[:4])
+	assert.NotEqual(t, negRiskAdapterABI.Methods["mergePositions"].ID, method.ID)
+	args, err := method.Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Len(t, args, 5)
+	assert.Equal(t, common.HexToAddress(usdcEAddress), args[0])
+	assert.Equal(t, [32]byte{}, args[1])
+	assert.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(2)}, args[3])
+	assert.Equal(t, big.NewInt(10_000_000), args[4])
+}
+
+func TestMergeCall_RejectsBadConditionID(t *testing.T) {
+	_, _, err := negRiskMergeCall("0x1234", 10)
+	assert.ErrorContains(t, err, "invalid conditionID")
+	_, _, err = ctfMergeCall("0x1234", 10)
+	assert.ErrorContains(t, err, "invalid conditionID")
+}
+
+func TestMergePositions_NegRiskNeedsEnabling(t *testing.T) {
+	mc := &MergeClient{}
+	assert.False(t, mc.SupportsNegRisk())
+	res, err := mc.MergePositions(context.Background(), testConditionID, 10, true)
+	require.Error(t, err)
+	assert.Contains(t, res.Error, "NegRisk merges disabled")
+
+	mc.EnableNegRisk(true)
+	assert.True(t, mc.SupportsNegRisk())
+}
@@ -0,0 +1,503 @@
+package onchain
+
+// price.go — POL/USD price oracle for gas cost accounting.
+//
+// Gas is paid in POL but merges are judged in USDC, so every gas estimate
+// needs a POL price. PriceOracle tries its providers in priority order and
+// caches the first answer; when all of them fail it keeps serving the last
+// known price. Providers:
+//   - coingecko:     public simple/price endpoint (rate-limits aggressively)
+//   - coinmarketcap: quotes API, needs a free API key
+//   - uniswap:       on-chain TWAP of the WPOL/USDC.e 0.3% Uniswap V3 pool
+//   - chainlink:     on-chain POL/USD aggregator, no external HTTP
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	coinGeckoPriceURL = "https://api.coingecko.com/api/v3/simple/price?ids=polygon-ecosystem-token&vs_currencies=usd"
+	coinMarketCapURL  = "https://pro-api.coinmarketcap.com/v2/cryptocurrency/quotes/latest?slug=polygon-ecosystem-token"
+
+	// Uniswap V3 on Polygon: the factory resolves the WPOL/USDC.e pool.
+	uniswapV3Factory = "0x1F98431c8aD98523631AE4a59f267346ea31F984"
+	wpolAddress      = "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"
+	uniswapPoolFee   = 3000 // 0.3%
+
+	// Chainlink POL/USD (formerly MATIC/USD) aggregator proxy on Polygon.
+	chainlinkPOLUSDFeed = "0xAB594600376Ec9fD91F8e885dADF0CE036862dE0"
+
+	defaultTWAPWindow      = 30 * time.Minute
+	defaultPriceCacheTTL   = 15 * time.Minute
+	defaultChainlinkMaxAge = time.Hour
+)
+
+// Price provider names accepted by OracleConfig.Providers.
+const (
+	ProviderCoinGecko     = "coingecko"
+	ProviderCoinMarketCap = "coinmarketcap"
+	ProviderUniswap       = "uniswap"
+	ProviderChainlink     = "chainlink"
+)
+
+// PriceFeed returns the current POL/USD price.
+type PriceFeed interface {
+	POLPriceUSD(ctx context.Context) (float64, error)
+}
+
+// OracleConfig selects and orders the providers of a PriceOracle.
+type OracleConfig struct {
+	Providers        []string      // tried in this order
+	CoinMarketCapKey string        // required by "coinmarketcap"
+	UniswapPool      string        // POL/USDC pool; "" = WPOL/USDC.e 0.3% from the factory
+	ChainlinkFeed    string        // aggregator address; "" = the POL/USD proxy
+	TWAPWindow       time.Duration // 0 = 30 minutes
+	CacheTTL         time.Duration // 0 = 15 minutes
+}
+
+// DefaultOracleConfig uses the providers that need no API key, on-chain
+// Chainlink first so CoinGecko's rate limit only matters as a fallback.
+func DefaultOracleConfig() OracleConfig {
+	return OracleConfig{Providers: []string{ProviderChainlink, ProviderCoinGecko, ProviderUniswap}}
+}
+
+type namedFeed struct {
+	name string
+	feed PriceFeed
+}
+
+// PriceOracle is a PriceFeed that falls through several providers and
+// caches the result.
+type PriceOracle struct {
+	feeds []namedFeed
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	price     float64
+	fetchedAt time.Time
+}
+
+// NewPriceOracle builds an oracle over cfg.Providers. rpc is only needed by
+// the on-chain providers (uniswap, chainlink).
+func NewPriceOracle(rpc *RPCPool, cfg OracleConfig) (*PriceOracle, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("price oracle: no providers")
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	o := &PriceOracle{ttl: cfg.CacheTTL}
+	if o.ttl <= 0 {
+		o.ttl = defaultPriceCacheTTL
+	}
+	for _, name := range cfg.Providers {
+		var feed PriceFeed
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ProviderCoinGecko:
+			feed = &CoinGeckoFeed{httpClient: httpClient}
+		case ProviderCoinMarketCap:
+			if cfg.CoinMarketCapKey == "" {
+				return nil, fmt.Errorf("price oracle: coinmarketcap needs an API key")
+			}
+			feed = &CoinMarketCapFeed{apiKey: cfg.CoinMarketCapKey, httpClient: httpClient}
+		case ProviderUniswap:
+			if rpc == nil {
+				return nil, fmt.Errorf("price oracle: uniswap needs an RPC pool")
+			}
+			feed = NewUniswapTWAPFeed(rpc, cfg.UniswapPool, cfg.TWAPWindow)
+		case ProviderChainlink:
+			if rpc == nil {
+				return nil, fmt.Errorf("price oracle: chainlink needs an RPC pool")
+			}
+			feed = NewChainlinkFeed(rpc, cfg.ChainlinkFeed)
+		default:
+			return nil, fmt.Errorf("price oracle: unknown provider %q", name)
+		}
+		o.feeds = append(o.feeds, namedFeed{name: name, feed: feed})
+	}
+	return o, nil
+}
+
+// POLPriceUSD returns the cached price, or asks the providers in order once
+// it is older than the cache TTL. If every provider fails, the last known
+// price is returned; an error only when there has never been one.
+func (o *PriceOracle) POLPriceUSD(ctx context.Context) (float64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.price > 0 && time.Since(o.fetchedAt) < o.ttl {
+		return o.price, nil
+	}
+
+	var errs []error
+	for _, f := range o.feeds {
+		price, err := f.feed.POLPriceUSD(ctx)
+		if err == nil && price <= 0 {
+			err = fmt.Errorf("non-positive price %g", price)
+		}
+		if err != nil {
+			slog.Debug("price: provider failed", "provider", f.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+			continue
+		}
+		slog.Debug("price: POL/USD", "provider", f.name, "usd", price)
+		o.price, o.fetchedAt = price, time.Now()
+		return price, nil
+	}
+
+	err := errors.Join(errs...)
+	if o.price > 0 {
+		slog.Warn("price: all providers failed, using last known POL price",
+			"usd", o.price, "age", time.Since(o.fetchedAt).Round(time.Minute), "err", err)
+		return o.price, nil
+	}
+	return 0, fmt.Errorf("price oracle: %w", err)
+}
+
+// CoinGeckoFeed reads POL/USD from CoinGecko's public API.
+type CoinGeckoFeed struct {
+	httpClient *http.Client
+}
+
+func (f *CoinGeckoFeed) POLPriceUSD(ctx context.Context) (float64, error) {
+	var data map[string]map[string]float64
+	if err := getPriceJSON(ctx, f.httpClient, coinGeckoPriceURL, nil, &data); err != nil {
+		return 0, fmt.Errorf("coingecko: %w", err)
+	}
+	price, ok := data["polygon-ecosystem-token"]["usd"]
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("coingecko: POL price not found in response")
+	}
+	return price, nil
+}
+
+// CoinMarketCapFeed reads POL/USD from the CoinMarketCap quotes API.
+type CoinMarketCapFeed struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+func (f *CoinMarketCapFeed) POLPriceUSD(ctx context.Context) (float64, error) {
+	var resp struct {
+		Data map[string]struct {
+			Quote struct {
+				USD struct {
+					Price float64 `json:"price"`
+				} `json:"USD"`
+			} `json:"quote"`
+		} `json:"data"`
+	}
+	header := http.Header{"X-CMC_PRO_API_KEY": {f.apiKey}}
+	if err := getPriceJSON(ctx, f.httpClient, coinMarketCapURL, header, &resp); err != nil {
+		return 0, fmt.Errorf("coinmarketcap: %w", err)
+	}
+	for _, asset := range resp.Data {
+		if asset.Quote.USD.Price > 0 {
+			return asset.Quote.USD.Price, nil
+		}
+	}
+	return 0, fmt.Errorf("coinmarketcap: POL price not found in response")
+}
+
+func getPriceJSON(ctx context.Context, client *http.Client, url string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+var uniswapABI abi.ABI
+
+func init() {
+	var err error
+	uniswapABI, err = abi.JSON(strings.NewReader(`[
+		{
+			"name": "getPool",
+			"type": "function",
+			"inputs": [
+				{"name": "tokenA", "type": "address"},
+				{"name": "tokenB", "type": "address"},
+				{"name": "fee", "type": "uint24"}
+			],
+			"outputs": [{"name": "pool", "type": "address"}]
+		},
+		{
+			"name": "token0",
+			"type": "function",
+			"inputs": [],
+			"outputs": [{"name": "", "type": "address"}]
+		},
+		{
+			"name": "observe",
+			"type": "function",
+			"inputs": [{"name": "secondsAgos", "type": "uint32[]"}],
+			"outputs": [
+				{"name": "tickCumulatives", "type": "int56[]"},
+				{"name": "secondsPerLiquidityCumulativeX128s", "type": "uint160[]"}
+			]
+		}
+	]`))
+	if err != nil {
+		panic("uniswap abi parse: " + err.Error())
+	}
+}
+
+// UniswapTWAPFeed prices POL from the time-weighted average tick of a
+// WPOL/USDC.e Uniswap V3 pool. It needs no API key and cannot be
+// rate-limited off-chain, but lags the market by up to the TWAP window.
+type UniswapTWAPFeed struct {
+	rpc    *RPCPool
+	window time.Duration
+
+	mu       sync.Mutex
+	pool     common.Address // zero until resolved on the factory
+	wpolIs0  bool           // WPOL is token0 of the pool
+	resolved bool
+}
+
+// NewUniswapTWAPFeed reads the TWAP of pool ("" = the WPOL/USDC.e 0.3% pool)
+// over window (0 = 30 minutes).
+func NewUniswapTWAPFeed(rpc *RPCPool, pool string, window time.Duration) *UniswapTWAPFeed {
+	if window <= 0 {
+		window = defaultTWAPWindow
+	}
+	f := &UniswapTWAPFeed{rpc: rpc, window: window}
+	if pool != "" {
+		f.pool = common.HexToAddress(pool)
+	}
+	return f
+}
+
+func (f *UniswapTWAPFeed) POLPriceUSD(ctx context.Context) (float64, error) {
+	pool, wpolIs0, err := f.resolve(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("uniswap: %w", err)
+	}
+
+	secs := uint32(f.window / time.Second)
+	out, err := f.call(ctx, pool, "observe", []uint32{secs, 0})
+	if err != nil {
+		return 0, fmt.Errorf("uniswap: observe: %w", err)
+	}
+	ticks, ok := out[0].([]*big.Int)
+	if !ok || len(ticks) != 2 {
+		return 0, fmt.Errorf("uniswap: unexpected observe result")
+	}
+	delta, _ := new(big.Float).SetInt(new(big.Int).Sub(ticks[1], ticks[0])).Float64()
+	avgTick := delta / float64(secs)
+
+	// 1.0001^tick is token1 per token0 in raw units; WPOL has 18 decimals
+	// and USDC.e 6.
+	raw := math.Pow(1.0001, avgTick)
+	if wpolIs0 {
+		return raw * 1e12, nil
+	}
+	return 1e12 / raw, nil
+}
+
+// resolve finds the pool on the factory (unless configured) and which of
+// its tokens is WPOL. The result is cached.
+func (f *UniswapTWAPFeed) resolve(ctx context.Context) (common.Address, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.resolved {
+		return f.pool, f.wpolIs0, nil
+	}
+
+	wpol := common.HexToAddress(wpolAddress)
+	if f.pool == (common.Address{}) {
+		out, err := f.call(ctx, common.HexToAddress(uniswapV3Factory), "getPool",
+			wpol, common.HexToAddress(usdcEAddress), big.NewInt(uniswapPoolFee))
+		if err != nil {
+			return common.Address{}, false, fmt.Errorf("getPool: %w", err)
+		}
+		pool, _ := out[0].(common.Address)
+		if pool == (common.Address{}) {
+			return common.Address{}, false, fmt.Errorf("getPool: no WPOL/USDC.e pool")
+		}
+		f.pool = pool
+	}
+
+	out, err := f.call(ctx, f.pool, "token0")
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("token0: %w", err)
+	}
+	token0, _ := out[0].(common.Address)
+	f.wpolIs0 = token0 == wpol
+	f.resolved = true
+	return f.pool, f.wpolIs0, nil
+}
+
+func (f *UniswapTWAPFeed) call(ctx context.Context, to common.Address, method string, args ...any) ([]any, error) {
+	data, err := uniswapABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := f.rpc.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	out, err := uniswapABI.Unpack(method, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: empty result", method)
+	}
+	return out, nil
+}
+
+var chainlinkABI abi.ABI
+
+func init() {
+	var err error
+	chainlinkABI, err = abi.JSON(strings.NewReader(`[
+		{
+			"name": "decimals",
+			"type": "function",
+			"inputs": [],
+			"outputs": [{"name": "", "type": "uint8"}]
+		},
+		{
+			"name": "latestRoundData",
+			"type": "function",
+			"inputs": [],
+			"outputs": [
+				{"name": "roundId", "type": "uint80"},
+				{"name": "answer", "type": "int256"},
+				{"name": "startedAt", "type": "uint256"},
+				{"name": "updatedAt", "type": "uint256"},
+				{"name": "answeredInRound", "type": "uint80"}
+			]
+		}
+	]`))
+	if err != nil {
+		panic("chainlink abi parse: " + err.Error())
+	}
+}
+
+// ChainlinkFeed reads POL/USD from a Chainlink aggregator through the RPC
+// pool. Answers older than maxAge are rejected so a stalled feed falls
+// through to the next provider.
+type ChainlinkFeed struct {
+	rpc    *RPCPool
+	feed   common.Address
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	decimals int // -1 until read from the aggregator
+}
+
+// NewChainlinkFeed reads the aggregator at feed ("" = the POL/USD proxy).
+func NewChainlinkFeed(rpc *RPCPool, feed string) *ChainlinkFeed {
+	if feed == "" {
+		feed = chainlinkPOLUSDFeed
+	}
+	return &ChainlinkFeed{
+		rpc:      rpc,
+		feed:     common.HexToAddress(feed),
+		maxAge:   defaultChainlinkMaxAge,
+		decimals: -1,
+	}
+}
+
+func (f *ChainlinkFeed) POLPriceUSD(ctx context.Context) (float64, error) {
+	decimals, err := f.feedDecimals(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("chainlink: decimals: %w", err)
+	}
+	out, err := f.call(ctx, "latestRoundData")
+	if err != nil {
+		return 0, fmt.Errorf("chainlink: latestRoundData: %w", err)
+	}
+	answer, ok1 := out[1].(*big.Int)
+	updatedAt, ok2 := out[3].(*big.Int)
+	if !ok1 || !ok2 {
+		return 0, fmt.Errorf("chainlink: unexpected latestRoundData result")
+	}
+	price, err := chainlinkAnswer(answer, decimals, time.Unix(updatedAt.Int64(), 0), f.maxAge, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("chainlink: %w", err)
+	}
+	return price, nil
+}
+
+// chainlinkAnswer scales a round answer to USD, rejecting non-positive or
+// stale rounds.
+func chainlinkAnswer(answer *big.Int, decimals int, updatedAt time.Time, maxAge time.Duration, now time.Time) (float64, error) {
+	if answer.Sign() <= 0 {
+		return 0, fmt.Errorf("non-positive answer %s", answer)
+	}
+	if age := now.Sub(updatedAt); age > maxAge {
+		return 0, fmt.Errorf("stale round: updated %s ago", age.Round(time.Second))
+	}
+	v, _ := new(big.Float).SetInt(answer).Float64()
+	return v / math.Pow10(decimals), nil
+}
+
+func (f *ChainlinkFeed) feedDecimals(ctx context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.decimals >= 0 {
+		return f.decimals, nil
+	}
+	out, err := f.call(ctx, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	d, ok := out[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected decimals result")
+	}
+	f.decimals = int(d)
+	return f.decimals, nil
+}
+
+func (f *ChainlinkFeed) call(ctx context.Context, method string) ([]any, error) {
+	data, err := chainlinkABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	result, err := f.rpc.CallContract(ctx, ethereum.CallMsg{To: &f.feed, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	out, err := chainlinkABI.Unpack(method, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: empty result", method)
+	}
+	return out, nil
+}
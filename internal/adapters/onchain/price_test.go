@@ -0,0 +1,109 @@
+package onchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFeed returns prices in sequence (the last one repeats) or fails.
+type stubFeed struct {
+	prices []float64
+	err    error
+	calls  int
+}
+
+func (f *stubFeed) POLPriceUSD(context.Context) (float64, error) {
+	f.calls++
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.prices[min(f.calls, len(f.prices))-1], nil
+}
+
+func stubOracle(feeds ...*stubFeed) *PriceOracle {
+	o := &PriceOracle{ttl: time.Minute}
+	for i, f := range feeds {
+		o.feeds = append(o.feeds, namedFeed{name: string(rune('a' + i)), feed: f})
+	}
+	return o
+}
+
+func TestPriceOracle_RefreshesStaleCache(t *testing.T) {
+	ctx := context.Background()
+	feed := &stubFeed{prices: []float64{0.40, 0.45}}
+	o := stubOracle(feed)
+
+	price, err := o.POLPriceUSD(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0.40, price)
+
+	price, err = o.POLPriceUSD(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0.40, price, "fresh cache must not hit the provider")
+	assert.Equal(t, 1, feed.calls)
+
+	o.fetchedAt = time.Now().Add(-2 * o.ttl)
+	price, err = o.POLPriceUSD(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0.45, price)
+	assert.Equal(t, 2, feed.calls)
+}
+
+func TestPriceOracle_FallsThroughInOrder(t *testing.T) {
+	ctx := context.Background()
+	first := &stubFeed{err: errors.New("rate limited")}
+	second := &stubFeed{prices: []float64{0}} // non-positive answers count as failures
+	third := &stubFeed{prices: []float64{0.38}}
+	fourth := &stubFeed{prices: []float64{0.99}}
+
+	price, err := stubOracle(first, second, third, fourth).POLPriceUSD(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0.38, price)
+	assert.Equal(t, []int{1, 1, 1, 0}, []int{first.calls, second.calls, third.calls, fourth.calls})
+}
+
+func TestPriceOracle_KeepsLastPriceWhenAllFail(t *testing.T) {
+	ctx := context.Background()
+	feed := &stubFeed{prices: []float64{0.41}}
+	o := stubOracle(feed)
+
+	_, err := o.POLPriceUSD(ctx)
+	require.NoError(t, err)
+
+	feed.err = errors.New("down")
+	o.fetchedAt = time.Now().Add(-2 * o.ttl)
+	price, err := o.POLPriceUSD(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0.41, price)
+
+	_, err = stubOracle(&stubFeed{err: errors.New("down")}).POLPriceUSD(ctx)
+	assert.Error(t, err, "no price has ever been known")
+}
+
+func TestChainlinkAnswer(t *testing.T) {
+	now := time.Now()
+
+	price, err := chainlinkAnswer(big.NewInt(41_250_000), 8, now.Add(-time.Minute), time.Hour, now)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4125, price, 1e-12)
+
+	_, err = chainlinkAnswer(big.NewInt(41_250_000), 8, now.Add(-2*time.Hour), time.Hour, now)
+	assert.ErrorContains(t, err, "stale round")
+
+	_, err = chainlinkAnswer(big.NewInt(-1), 8, now, time.Hour, now)
+	assert.Error(t, err)
+}
+
+func TestNewPriceOracle_OnChainProvidersNeedRPC(t *testing.T) {
+	_, err := NewPriceOracle(nil, OracleConfig{Providers: []string{ProviderChainlink}})
+	assert.ErrorContains(t, err, "chainlink needs an RPC pool")
+
+	_, err = NewPriceOracle(nil, OracleConfig{Providers: []string{"pyth"}})
+	assert.ErrorContains(t, err, "unknown provider")
+}
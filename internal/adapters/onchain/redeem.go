@@ -0,0 +1,208 @@
+package onchain
+
+// redeem.go — Redemption of resolved markets.
+//
+// Once the oracle reports, CTF.redeemPositions burns the wallet's outcome
+// tokens of a condition and pays each out at its payout fraction (the winner
+// $1, the loser $0, or a split for ambiguous markets). The payout is read from
+// payoutNumerators / payoutDenominator; the denominator stays 0 until the
+// condition resolves. NegRisk tokens are redeemed through the adapter's
+// redeemPositions(conditionId, amounts), which unwraps the collateral.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+var (
+	ctfRedeemABI     abi.ABI
+	negRiskRedeemABI abi.ABI
+)
+
+func init() {
+	var err error
+	ctfRedeemABI, err = abi.JSON(strings.NewReader(`[
+		{
+			"name": "redeemPositions",
+			"type": "function",
+			"inputs": [
+				{"name": "collateralToken", "type": "address"},
+				{"name": "parentCollectionId", "type": "bytes32"},
+				{"name": "conditionId", "type": "bytes32"},
+				{"name": "indexSets", "type": "uint256[]"}
+			],
+			"outputs": []
+		},
+		{
+			"name": "payoutDenominator",
+			"type": "function",
+			"inputs": [{"name": "conditionId", "type": "bytes32"}],
+			"outputs": [{"name": "", "type": "uint256"}]
+		},
+		{
+			"name": "payoutNumerators",
+			"type": "function",
+			"inputs": [
+				{"name": "conditionId", "type": "bytes32"},
+				{"name": "index", "type": "uint256"}
+			],
+			"outputs": [{"name": "", "type": "uint256"}]
+		}
+	]`))
+	if err != nil {
+		panic("ctf redeem abi parse: " + err.Error())
+	}
+
+	negRiskRedeemABI, err = abi.JSON(strings.NewReader(`[
+		{
+			"name": "redeemPositions",
+			"type": "function",
+			"inputs": [
+				{"name": "_conditionId", "type": "bytes32"},
+				{"name": "_amounts", "type": "uint256[]"}
+			],
+			"outputs": []
+		}
+	]`))
+	if err != nil {
+		panic("neg risk redeem abi parse: " + err.Error())
+	}
+}
+
+// ConditionResolved reports whether the condition has resolved: the CTF sets
+// payoutDenominator when the oracle reports, and it stays 0 until then.
+func (mc *MergeClient) ConditionResolved(ctx context.Context, conditionID string) (bool, error) {
+	condBytes, err := hexToBytes32(conditionID)
+	if err != nil {
+		return false, fmt.Errorf("redeem: invalid conditionID: %w", err)
+	}
+	den, err := mc.callCTFUint(ctx, "payoutDenominator", condBytes)
+	if err != nil {
+		return false, fmt.Errorf("redeem: payout denominator: %w", err)
+	}
+	return den.Sign() != 0, nil
+}
+
+// ConditionPayout reads the payout fractions of a condition from the CTF
+// contract. Outcome slot 0 is YES and slot 1 is NO.
+func (mc *MergeClient) ConditionPayout(ctx context.Context, conditionID string) (domain.ConditionPayout, error) {
+	condBytes, err := hexToBytes32(conditionID)
+	if err != nil {
+		return domain.ConditionPayout{}, fmt.Errorf("redeem: invalid conditionID: %w", err)
+	}
+
+	den, err := mc.callCTFUint(ctx, "payoutDenominator", condBytes)
+	if err != nil {
+		return domain.ConditionPayout{}, fmt.Errorf("redeem: payout denominator: %w", err)
+	}
+	if den.Sign() == 0 {
+		return domain.ConditionPayout{}, nil
+	}
+
+	payout := domain.ConditionPayout{Resolved: true}
+	for i, frac := range []*float64{&payout.Yes, &payout.No} {
+		num, err := mc.callCTFUint(ctx, "payoutNumerators", condBytes, big.NewInt(int64(i)))
+		if err != nil {
+			return domain.ConditionPayout{}, fmt.Errorf("redeem: payout numerator %d: %w", i, err)
+		}
+		*frac, _ = new(big.Rat).SetFrac(num, den).Float64()
+	}
+	return payout, nil
+}
+
+// RedeemPositions redeems a resolved condition's tokens for USDC.e. A plain
+// CTF redemption burns the wallet's whole balance of both outcomes; the
+// NegRisk adapter needs the amounts, so yesShares and noShares are passed to
+// it. Like merges, it is refused while gas is above the ceiling.
+func (mc *MergeClient) RedeemPositions(ctx context.Context, conditionID string, yesShares, noShares float64, negRisk bool) (domain.Redemption, error) {
+	red := domain.Redemption{
+		ConditionID: conditionID,
+		YesShares:   yesShares,
+		NoShares:    noShares,
+		RedeemedAt:  time.Now().UTC(),
+	}
+
+	condBytes, err := hexToBytes32(conditionID)
+	if err != nil {
+		return red, fmt.Errorf("redeem: invalid conditionID: %w", err)
+	}
+
+	to := common.HexToAddress(ctfAddress)
+	var callData []byte
+	if negRisk {
+		if !mc.negRisk {
+			return red, fmt.Errorf("redeem: NegRisk disabled (live.allow_neg_risk=false)")
+		}
+		to = common.HexToAddress(negRiskAdapter)
+		amounts := []*big.Int{sharesToUnits(yesShares), sharesToUnits(noShares)}
+		callData, err = negRiskRedeemABI.Pack("redeemPositions", condBytes, amounts)
+	} else {
+		indexSets := []*big.Int{big.NewInt(1), big.NewInt(2)}
+		callData, err = ctfRedeemABI.Pack("redeemPositions",
+			common.HexToAddress(usdcEAddress), [32]byte{}, condBytes, indexSets)
+	}
+	if err != nil {
+		return red, fmt.Errorf("redeem: pack: %w", err)
+	}
+
+	if err := mc.checkGasCeiling(ctx); err != nil {
+		return red, fmt.Errorf("redeem: %w", err)
+	}
+	gasEstimate := mc.estimateMergeGas(ctx, to, callData)
+
+	attempts := domain.MergeResult{ConditionID: conditionID}
+	receipt, maxFee, err := mc.sendWithRetry(ctx, to, gasEstimate, callData, &attempts)
+	red.TxHash = attempts.TxHash
+	if err != nil {
+		return red, fmt.Errorf("redeem: %w", err)
+	}
+	if receipt == nil {
+		return red, fmt.Errorf("redeem: tx %s not confirmed", red.TxHash)
+	}
+
+	_, red.GasCostUSD = mc.receiptGasCost(ctx, receipt, maxFee)
+	slog.Info("redeem: confirmed",
+		"condition", conditionID[:min(12, len(conditionID))]+"...",
+		"tx", red.TxHash,
+		"yes_shares", yesShares,
+		"no_shares", noShares,
+		"gas_usdc", fmt.Sprintf("$%.4f", red.GasCostUSD),
+	)
+	return red, nil
+}
+
+// callCTFUint calls a CTF view method returning a single uint256.
+func (mc *MergeClient) callCTFUint(ctx context.Context, method string, args ...any) (*big.Int, error) {
+	callData, err := ctfRedeemABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	ctfAddr := common.HexToAddress(ctfAddress)
+	result, err := mc.client.CallContract(ctx, ethereum.CallMsg{To: &ctfAddr, Data: callData}, nil)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := ctfRedeemABI.Unpack(method, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("%s: empty result", method)
+	}
+	return vals[0].(*big.Int), nil
+}
+
+// sharesToUnits converts outcome token shares to their 6-decimal base units.
+func sharesToUnits(shares float64) *big.Int {
+	return big.NewInt(int64(shares * 1_000_000))
+}
@@ -0,0 +1,184 @@
+package onchain
+
+// rpc.go — Polygon RPC pool with provider fallback.
+//
+// Free-tier RPC providers go down or rate-limit often. RPCPool keeps an
+// ordered list of endpoints (primary first) and sends each call to the active
+// one; on a connection error, a 429 or a 5xx it rotates to the next endpoint
+// and retries the call once. The active index stays on the provider that
+// worked, so a dead primary is not retried on every call.
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcActive publishes the active provider index of every pool, by pool name
+// (expvar "onchain_rpc_active", 0 = primary).
+var rpcActive = expvar.NewMap("onchain_rpc_active")
+
+// RPCPool is a set of Polygon RPC clients tried in order.
+type RPCPool struct {
+	name    string
+	urls    []string
+	clients []*ethclient.Client
+
+	mu     sync.Mutex
+	active int
+	gauge  *expvar.Int
+}
+
+// DialRPCPool dials every URL (primary first, then fallbacks). Empty and
+// duplicate URLs are skipped. name identifies the pool in logs and metrics.
+func DialRPCPool(name string, urls ...string) (*RPCPool, error) {
+	p := &RPCPool{name: name, gauge: new(expvar.Int)}
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		u = strings.TrimSpace(u)
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		c, err := ethclient.Dial(u)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("onchain.DialRPCPool: dial %s: %w", u, err)
+		}
+		p.urls = append(p.urls, u)
+		p.clients = append(p.clients, c)
+	}
+	if len(p.clients) == 0 {
+		return nil, fmt.Errorf("onchain.DialRPCPool: no rpc urls")
+	}
+	rpcActive.Set(name, p.gauge)
+	return p, nil
+}
+
+// Active returns the URL of the provider currently in use.
+func (p *RPCPool) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.urls[p.active]
+}
+
+// Close closes every client.
+func (p *RPCPool) Close() {
+	for _, c := range p.clients {
+		c.Close()
+	}
+}
+
+func (p *RPCPool) current() (int, *ethclient.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active, p.clients[p.active]
+}
+
+// rotate moves past the provider at idx. A concurrent call may already have
+// rotated; then the pool is left as is.
+func (p *RPCPool) rotate(idx int, cause error) (int, *ethclient.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active == idx {
+		p.active = (idx + 1) % len(p.clients)
+		p.gauge.Set(int64(p.active))
+		slog.Warn("onchain: rpc provider failed, switching",
+			"pool", p.name,
+			"from", p.urls[idx],
+			"to", p.urls[p.active],
+			"err", cause,
+		)
+	}
+	return p.active, p.clients[p.active]
+}
+
+// call runs fn on the active client, rotating and retrying once when the
+// provider is unreachable or rate-limiting.
+func call[T any](ctx context.Context, p *RPCPool, fn func(*ethclient.Client) (T, error)) (T, error) {
+	idx, c := p.current()
+	v, err := fn(c)
+	if err == nil || len(p.clients) == 1 || ctx.Err() != nil || !isProviderError(err) {
+		return v, err
+	}
+	_, c = p.rotate(idx, err)
+	return fn(c)
+}
+
+// isProviderError reports whether err means the provider itself failed
+// (connection refused/reset, timeout, 429, 5xx) rather than the request.
+func isProviderError(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "rate limit")
+}
+
+func (p *RPCPool) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	return call(ctx, p, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, msg, block) })
+}
+
+func (p *RPCPool) BalanceAt(ctx context.Context, account common.Address, block *big.Int) (*big.Int, error) {
+	return call(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.BalanceAt(ctx, account, block) })
+}
+
+func (p *RPCPool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, msg) })
+}
+
+func (p *RPCPool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+// SendTransaction broadcasts a signed tx. Resending the same signed tx to a
+// fallback is safe: it has the same hash and nonce.
+func (p *RPCPool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := call(ctx, p, func(c *ethclient.Client) (struct{}, error) { return struct{}{}, c.SendTransaction(ctx, tx) })
+	return err
+}
+
+func (p *RPCPool) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+func (p *RPCPool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(ctx, p, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+func (p *RPCPool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, p, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+func (p *RPCPool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(ctx, p, func(c *ethclient.Client) (*types.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+func (p *RPCPool) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, p, func(c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
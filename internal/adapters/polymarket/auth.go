@@ -212,6 +212,9 @@ func (ac *AuthClient) l2Headers(method, path, body string) (map[string]string, e
 
 // doL2 executes an authenticated L2 HTTP request with rate limiting.
 // HMAC headers are regenerated on every attempt so the timestamp stays fresh.
+// POSTs (order placement) are only retried on 429 here: after a network
+// error or a 5xx the order may already be on the book. PlaceOrder resends
+// the same signed order itself on the statuses where that is safe.
 func (ac *AuthClient) doL2(ctx context.Context, method, path string, reqBody, out any) error {
 	var bodyStr string
 
@@ -224,15 +227,14 @@ func (ac *AuthClient) doL2(ctx context.Context, method, path string, reqBody, ou
 	}
 
 	fullURL := ac.clobBase + path
+	// The HMAC covers the path only, not the query string.
+	signPath, _, _ := strings.Cut(path, "?")
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if err := ac.clobLimiter.Wait(ctx); err != nil {
-			return fmt.Errorf("rate limiter: %w", err)
-		}
-
-		headers, err := ac.l2Headers(method, path, bodyStr)
+	idempotent := method != http.MethodPost
+	return ac.doWithRetry(ctx, ac.clobLimiter, idempotent, func() (*http.Response, error) {
+		headers, err := ac.l2Headers(method, signPath, bodyStr)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		var bodyReader io.Reader
@@ -242,66 +244,41 @@ func (ac *AuthClient) doL2(ctx context.Context, method, path string, reqBody, ou
 
 		req, err := http.NewRequestWithContext(ctx, method, fullURL, bodyReader)
 		if err != nil {
-			return fmt.Errorf("new request: %w", err)
+			return nil, fmt.Errorf("new request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
 		for k, v := range headers {
 			req.Header.Set(k, v)
 		}
-
-		resp, err := ac.http.Do(req)
-		if err != nil {
-			if attempt == maxRetries {
-				return fmt.Errorf("request failed after %d retries: %w", maxRetries, err)
-			}
-			ac.sleep(ctx, attempt)
-			continue
-		}
-
-		respBody, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-
-		if resp.StatusCode == http.StatusTooManyRequests {
-			ac.sleep(ctx, attempt)
-			continue
-		}
-		if resp.StatusCode >= 500 {
-			if attempt == maxRetries {
-				return fmt.Errorf("server error %d: %s", resp.StatusCode, respBody)
-			}
-			ac.sleep(ctx, attempt)
-			continue
-		}
-		if resp.StatusCode >= 400 {
-			return fmt.Errorf("client error %d: %s", resp.StatusCode, respBody)
-		}
-
-		if out != nil {
-			if err := json.Unmarshal(respBody, out); err != nil {
-				return fmt.Errorf("decode response: %w", err)
-			}
-		}
-		return nil
-	}
-	return fmt.Errorf("exhausted %d retries", maxRetries)
+		return ac.http.Do(req)
+	}, out)
 }
 
 // buildSignedOrder creates an EIP-712 signed order for the given parameters.
-// price and size are in USDC units (e.g., 0.80 and 10.0).
+// price is in USDC per share (e.g., 0.80) and shares is the token amount,
+// already on the CLOB's 0.01 increment (domain.SharesAt).
 // Uses integer arithmetic to avoid floating-point precision errors that the
 // CLOB API rejects. The API verifies: makerAmount == price * takerAmount exactly.
-func (ac *AuthClient) buildSignedOrder(tokenID string, price, size float64, negRisk bool) (*gomodel.SignedOrder, error) {
+// For SELL orders the amounts are swapped: the maker gives shares and takes USDC.
+// expiration is a unix timestamp for GTD orders, 0 for orders that never expire.
+func (ac *AuthClient) buildSignedOrder(tokenID, side string, price, shares float64, negRisk bool, expiration int64) (*gomodel.SignedOrder, error) {
 	pricePrecision := detectPricePrecision(price)
 	priceInt := int64(math.Round(price * float64(pricePrecision)))
-	sharesCents := int64(math.Floor(size / price * 100))
+	sharesCents := int64(math.Round(shares * 100))
 
 	amountFactor := int64(1_000_000) / (100 * pricePrecision)
 	makerAmount := sharesCents * priceInt * amountFactor
 	takerAmount := sharesCents * 10000
 
+	orderSide := gomodel.BUY
+	if side == "SELL" {
+		orderSide = gomodel.SELL
+		makerAmount, takerAmount = takerAmount, makerAmount
+	}
+
 	if makerAmount <= 0 || takerAmount <= 0 {
-		return nil, fmt.Errorf("invalid amounts: maker=%d taker=%d (price=%.4f size=%.4f)", makerAmount, takerAmount, price, size)
+		return nil, fmt.Errorf("invalid amounts: maker=%d taker=%d (price=%.4f shares=%.2f)", makerAmount, takerAmount, price, shares)
 	}
 
 	var verifyingContract gomodel.VerifyingContract
@@ -320,8 +297,8 @@ func (ac *AuthClient) buildSignedOrder(tokenID string, price, size float64, negR
 		FeeRateBps:    "0",
 		Nonce:         "0",
 		Signer:        ac.address.Hex(),
-		Expiration:    "0",
-		Side:          gomodel.BUY,
+		Expiration:    strconv.FormatInt(expiration, 10),
+		Side:          orderSide,
 		SignatureType: gomodel.EOA,
 	}
 
@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"github.com/alejandrodnm/polybot/internal/ports"
 )
 
 const (
@@ -26,8 +31,13 @@ const (
 	// CLOB general (sampling-markets, etc.): 9000/10s → 5400/10s → 540/s
 	generalRatePerSec = 540
 
+	// Pool de FetchOrderBooks: batches en vuelo y timeout de cada uno.
+	defaultBookWorkers = 8
+	defaultBookTimeout = 5 * time.Second
+
 	maxRetries    = 3
 	baseRetryWait = 500 * time.Millisecond
+	maxRetryAfter = 30 * time.Second // tope a la espera pedida por Retry-After
 )
 
 // Client es el HTTP client de Polymarket con rate limiting y retries.
@@ -35,9 +45,14 @@ type Client struct {
 	http         *http.Client
 	clobBase     string
 	gammaBase    string
+	dataBase     string // Data API (trades); SetDataAPIBase en tests
 	clobLimiter  *rate.Limiter
 	gammaLimiter *rate.Limiter
 	booksLimiter *rate.Limiter
+	bookWorkers  int           // batches de /books en paralelo (SetBookFetch)
+	bookTimeout  time.Duration // timeout de cada batch de /books
+	marketCache  marketCache
+	health       ports.HealthReporter // nil = sin endpoint de salud
 }
 
 // NewClient crea un Client con los base URLs dados.
@@ -53,15 +68,81 @@ func NewClient(clobBase, gammaBase string) *Client {
 		http:         &http.Client{Timeout: 10 * time.Second},
 		clobBase:     clobBase,
 		gammaBase:    gammaBase,
+		dataBase:     dataAPIBase,
 		clobLimiter:  rate.NewLimiter(generalRatePerSec, 50),
 		gammaLimiter: rate.NewLimiter(gammaRatePerSec, 10),
 		booksLimiter: rate.NewLimiter(booksRatePerSec, 5),
+		bookWorkers:  defaultBookWorkers,
+		bookTimeout:  defaultBookTimeout,
+	}
+}
+
+// ErrRateLimited indica que la API siguió respondiendo 429 tras agotar los
+// reintentos. Los callers lo distinguen con errors.Is.
+var ErrRateLimited = errors.New("rate limited by API (429)")
+
+// errClientStatus marca las respuestas 4xx: el CLOB respondió, así que no
+// cuentan como fallo de conectividad para el endpoint de salud.
+var errClientStatus = errors.New("client error")
+
+// statusError es una respuesta HTTP de error tras agotar los reintentos. Los
+// 4xx envuelven errClientStatus; PlaceOrder mira Code para decidir si repite.
+type statusError struct {
+	Code int
+	Body string
+}
+
+func (e *statusError) Error() string {
+	if e.Code < 500 {
+		return fmt.Sprintf("%s %d: %s", errClientStatus, e.Code, e.Body)
+	}
+	return fmt.Sprintf("server error %d: %s", e.Code, e.Body)
+}
+
+func (e *statusError) Unwrap() error {
+	if e.Code < 500 {
+		return errClientStatus
+	}
+	return nil
+}
+
+// SetHealth hace que cada llamada al CLOB (no a Gamma) reporte su resultado
+// como latido ports.HealthCLOB.
+func (c *Client) SetHealth(h ports.HealthReporter) {
+	c.health = h
+}
+
+// SetDataAPIBase cambia el base URL de la Data API (por defecto producción).
+func (c *Client) SetDataAPIBase(base string) {
+	if base != "" {
+		c.dataBase = base
+	}
+}
+
+// SetCLOBRate cambia el límite de peticiones por segundo al CLOB (por defecto
+// generalRatePerSec). Valores <= 0 se ignoran.
+func (c *Client) SetCLOBRate(perSec float64) {
+	if perSec <= 0 {
+		return
+	}
+	c.clobLimiter.SetLimit(rate.Limit(perSec))
+}
+
+// SetBookFetch cambia cuántos batches de /books pide FetchOrderBooks en
+// paralelo y cuánto espera a cada uno (por defecto 8 y 5s). Valores <= 0
+// dejan el actual.
+func (c *Client) SetBookFetch(workers int, timeout time.Duration) {
+	if workers > 0 {
+		c.bookWorkers = workers
+	}
+	if timeout > 0 {
+		c.bookTimeout = timeout
 	}
 }
 
 // get hace un GET con rate limiting y retries.
 func (c *Client) get(ctx context.Context, limiter *rate.Limiter, url string, out any) error {
-	return c.doWithRetry(ctx, limiter, func() (*http.Response, error) {
+	return c.doWithRetry(ctx, limiter, true, func() (*http.Response, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
 			return nil, err
@@ -71,9 +152,10 @@ func (c *Client) get(ctx context.Context, limiter *rate.Limiter, url string, out
 	}, out)
 }
 
-// post hace un POST JSON con rate limiting y retries.
+// post hace un POST JSON con rate limiting y retries. Solo se usa para
+// endpoints de lectura (/books), por eso se reintenta como un GET.
 func (c *Client) post(ctx context.Context, limiter *rate.Limiter, url string, body, out any) error {
-	return c.doWithRetry(ctx, limiter, func() (*http.Response, error) {
+	return c.doWithRetry(ctx, limiter, true, func() (*http.Response, error) {
 		b, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal body: %w", err)
@@ -88,58 +170,92 @@ func (c *Client) post(ctx context.Context, limiter *rate.Limiter, url string, bo
 	}, out)
 }
 
-// doWithRetry ejecuta la función con backoff exponencial y jitter.
-func (c *Client) doWithRetry(ctx context.Context, limiter *rate.Limiter, fn func() (*http.Response, error), out any) error {
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+// doWithRetry ejecuta fn con rate limiting y backoff exponencial con jitter.
+// Los 429 se reintentan siempre (el servidor no procesó la petición),
+// esperando al menos lo que pida Retry-After. Los errores de red y los 5xx
+// solo se reintentan si idempotent: un POST de orden que falla a medias puede
+// haber llegado al CLOB y repetirlo duplicaría la orden. Si los 429 agotan los
+// reintentos, el error envuelve ErrRateLimited.
+func (c *Client) doWithRetry(ctx context.Context, limiter *rate.Limiter, idempotent bool, fn func() (*http.Response, error), out any) error {
+	err := c.retry(ctx, limiter, idempotent, fn, out)
+	if c.health != nil && limiter != c.gammaLimiter && ctx.Err() == nil {
+		if errors.Is(err, errClientStatus) {
+			err = nil
+		}
+		c.health.Beat(ports.HealthCLOB, err)
+	}
+	return err
+}
+
+// retry es el bucle de reintentos de doWithRetry.
+func (c *Client) retry(ctx context.Context, limiter *rate.Limiter, idempotent bool, fn func() (*http.Response, error), out any) error {
+	for attempt := 0; ; attempt++ {
 		if err := limiter.Wait(ctx); err != nil {
 			return fmt.Errorf("rate limiter: %w", err)
 		}
 
 		resp, err := fn()
 		if err != nil {
-			if attempt == maxRetries {
-				return fmt.Errorf("request failed after %d retries: %w", maxRetries, err)
+			if !idempotent || attempt == maxRetries {
+				return fmt.Errorf("request failed after %d attempts: %w", attempt+1, err)
 			}
-			c.sleep(ctx, attempt)
+			c.sleep(ctx, attempt, 0)
 			continue
 		}
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			resp.Body.Close()
-			slog.Warn("rate limited by API", "attempt", attempt+1)
-			c.sleep(ctx, attempt)
-			continue
-		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
 
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
 			if attempt == maxRetries {
-				return fmt.Errorf("server error %d after %d retries", resp.StatusCode, maxRetries)
+				return fmt.Errorf("%w after %d attempts", ErrRateLimited, attempt+1)
 			}
-			c.sleep(ctx, attempt)
+			wait := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+			slog.Warn("rate limited by API", "attempt", attempt+1, "retry_after", wait)
+			c.sleep(ctx, attempt, wait)
 			continue
+		case resp.StatusCode >= 500:
+			if !idempotent || attempt == maxRetries {
+				return &statusError{Code: resp.StatusCode, Body: string(body)}
+			}
+			c.sleep(ctx, attempt, 0)
+			continue
+		case resp.StatusCode >= 400:
+			return &statusError{Code: resp.StatusCode, Body: string(body)}
 		}
 
-		if resp.StatusCode >= 400 {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			return fmt.Errorf("client error %d: %s", resp.StatusCode, string(body))
-		}
-
-		defer resp.Body.Close()
-		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-			return fmt.Errorf("decode response: %w", err)
+		if out != nil {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
 		}
 		return nil
 	}
-	return fmt.Errorf("exhausted %d retries", maxRetries)
 }
 
-// sleep espera con backoff exponencial y jitter, respetando el contexto.
-func (c *Client) sleep(ctx context.Context, attempt int) {
-	wait := time.Duration(math.Pow(2, float64(attempt))) * baseRetryWait
+// sleep espera con backoff exponencial y jitter (±50%), y al menos atLeast,
+// respetando el contexto.
+func (c *Client) sleep(ctx context.Context, attempt int, atLeast time.Duration) {
+	backoff := time.Duration(math.Pow(2, float64(attempt))) * baseRetryWait
+	wait := max(backoff/2+rand.N(backoff), atLeast)
 	select {
 	case <-time.After(wait):
 	case <-ctx.Done():
 	}
 }
+
+// retryAfter interpreta la cabecera Retry-After (segundos o fecha HTTP),
+// acotada a maxRetryAfter. Devuelve 0 si falta o no es válida.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	var wait time.Duration
+	if secs, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(header); err == nil {
+		wait = t.Sub(now)
+	}
+	return max(0, min(wait, maxRetryAfter))
+}
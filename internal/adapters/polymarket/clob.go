@@ -2,9 +2,11 @@ package polymarket
 
 // clob.go — Polymarket CLOB API adapter.
 //
-// FetchOrderBooks usa goroutines concurrentes para disparar múltiples batch requests
-// en paralelo. El rate limiter (token bucket) en doWithRetry controla el ritmo
-// automáticamente — las goroutines se "autolimitan" sin necesidad de semáforo explícito.
+// FetchOrderBooks reparte los batch requests entre un pool acotado de workers
+// (SetBookFetch). El rate limiter (token bucket) en doWithRetry sigue marcando
+// el ritmo; el pool solo limita cuántas peticiones hay en vuelo. Cada batch
+// tiene su propio timeout y uno lento o fallido no tumba el ciclo: se
+// devuelven los books que llegaron.
 // Resultado: reducción del tiempo de fetch de books de ~15s a ~3s en producción.
 
 import (
@@ -12,6 +14,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
@@ -20,16 +23,41 @@ const (
 	samplingMarketsPath = "/sampling-markets"
 	booksPath           = "/books"
 	pageSize            = 100
-	batchSize           = 20 // máx token_ids por request a /books
+	batchSize           = 20  // máx token_ids por request a /books
+	samplingMaxPages    = 100 // tope de páginas de /sampling-markets por refresco
 )
 
 // FetchSamplingMarkets devuelve todos los mercados con rewards activos.
-// Pagina automáticamente usando next_cursor hasta agotar los resultados.
+// Con la caché activa (SetMarketCacheTTL) reutiliza la última lista mientras
+// no caduque ni se invalide; los orderbooks se piden frescos en cada ciclo.
 func (c *Client) FetchSamplingMarkets(ctx context.Context) ([]domain.Market, error) {
+	if cached, ok := c.marketCache.get(time.Now()); ok {
+		slog.Debug("sampling markets served from cache", "total", len(cached))
+		return cached, nil
+	}
+
+	all, err := c.fetchSamplingMarkets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.marketCache.put(all, time.Now())
+	return all, nil
+}
+
+// fetchSamplingMarkets pagina /sampling-markets con next_cursor y enriquece
+// el resultado con Gamma. Un cursor repetido o más de samplingMaxPages
+// páginas cortan la paginación con lo obtenido hasta ahí.
+func (c *Client) fetchSamplingMarkets(ctx context.Context) ([]domain.Market, error) {
+	start := time.Now()
 	var all []domain.Market
 	cursor := ""
+	seen := make(map[string]bool)
 
-	for {
+	for page := 0; ; page++ {
+		if page == samplingMaxPages {
+			slog.Warn("sampling markets: page limit reached, markets truncated", "pages", page)
+			break
+		}
 		url := fmt.Sprintf("%s%s?limit=%d", c.clobBase, samplingMarketsPath, pageSize)
 		if cursor != "" {
 			url += "&next_cursor=" + cursor
@@ -46,13 +74,18 @@ func (c *Client) FetchSamplingMarkets(ctx context.Context) ([]domain.Market, err
 		slog.Debug("fetched sampling markets page",
 			"count", len(resp.Data),
 			"total", len(all),
-			"has_more", resp.NextCursor != "" && resp.NextCursor != "LTE=",
+			"has_more", resp.NextCursor != "" && resp.NextCursor != endCursor,
 		)
 
 		// "LTE=" es el cursor vacío codificado en base64 que indica última página
-		if resp.NextCursor == "" || resp.NextCursor == "LTE=" {
+		if resp.NextCursor == "" || resp.NextCursor == endCursor {
 			break
 		}
+		if seen[resp.NextCursor] {
+			slog.Warn("sampling markets: repeated cursor, stopping pagination", "cursor", resp.NextCursor, "pages", page+1)
+			break
+		}
+		seen[resp.NextCursor] = true
 		cursor = resp.NextCursor
 	}
 
@@ -67,12 +100,15 @@ func (c *Client) FetchSamplingMarkets(ctx context.Context) ([]domain.Market, err
 		all = enriched
 	}
 
+	slog.Info("market metadata refreshed", "total", len(all), "duration", time.Since(start).Round(time.Millisecond))
 	return all, nil
 }
 
 // FetchOrderBooks obtiene los orderbooks para los token_ids dados usando el endpoint batch.
-// Lanza un goroutine por batch (máx batchSize tokens cada uno) y los ejecuta
-// concurrentemente. El rate limiter en fetchBooksBatch controla el ritmo automáticamente.
+// Los batches (máx batchSize tokens cada uno) se reparten entre bookWorkers
+// goroutines y cada uno tiene bookTimeout para responder. Resultado parcial:
+// si algún batch falla, expira o ctx vence, devuelve los books que sí
+// llegaron; solo es error si no llegó ninguno.
 func (c *Client) FetchOrderBooks(ctx context.Context, tokenIDs []string) (map[string]domain.OrderBook, error) {
 	if len(tokenIDs) == 0 {
 		return map[string]domain.OrderBook{}, nil
@@ -87,30 +123,52 @@ func (c *Client) FetchOrderBooks(ctx context.Context, tokenIDs []string) (map[st
 		idx   int
 	}
 
+	jobs := make(chan int)
 	resultCh := make(chan batchResult, len(batches))
 	var wg sync.WaitGroup
 
-	for i, batch := range batches {
-		i, batch := i, batch
+	workers := min(c.bookWorkers, len(batches))
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			books, err := c.fetchBooksBatch(ctx, batch)
-			resultCh <- batchResult{books: books, err: err, idx: i}
+			for i := range jobs {
+				bctx, cancel := context.WithTimeout(ctx, c.bookTimeout)
+				books, err := c.fetchBooksBatch(bctx, batches[i])
+				cancel()
+				resultCh <- batchResult{books: books, err: err, idx: i}
+			}
 		}()
 	}
 
-	// Cerrar el canal cuando todos los goroutines terminen
+	// Repartir batches hasta agotarlos o hasta que venza ctx; luego cerrar
+	// el canal de resultados cuando todos los workers terminen.
 	go func() {
-		wg.Wait()
-		close(resultCh)
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(resultCh)
+		}()
+		for i := range batches {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
 	}()
 
 	result := make(map[string]domain.OrderBook, len(tokenIDs))
-	var firstErr error
+	var (
+		firstErr error
+		failed   int
+		done     int
+	)
 
 	for r := range resultCh {
+		done++
 		if r.err != nil {
+			failed++
 			if firstErr == nil {
 				firstErr = fmt.Errorf("clob.FetchOrderBooks batch %d: %w", r.idx, r.err)
 			}
@@ -121,8 +179,18 @@ func (c *Client) FetchOrderBooks(ctx context.Context, tokenIDs []string) (map[st
 		}
 	}
 
-	if firstErr != nil {
-		return nil, firstErr
+	if skipped := len(batches) - done; skipped > 0 {
+		failed += skipped
+		if firstErr == nil {
+			firstErr = fmt.Errorf("clob.FetchOrderBooks: %w", ctx.Err())
+		}
+	}
+	if failed > 0 {
+		if len(result) == 0 {
+			return nil, firstErr
+		}
+		slog.Warn("order books: partial result",
+			"batches", len(batches), "failed", failed, "books", len(result), "err", firstErr)
 	}
 
 	slog.Debug("order books fetched", "tokens", len(tokenIDs), "books", len(result))
@@ -3,10 +3,12 @@ package polymarket_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/adapters/polymarket"
 	"github.com/stretchr/testify/assert"
@@ -67,6 +69,59 @@ func TestFetchSamplingMarkets_ServerError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFetchSamplingMarkets_CacheAndInvalidate(t *testing.T) {
+	data, err := os.ReadFile("../../../testdata/fixtures/clob_sampling_markets.json")
+	require.NoError(t, err)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	defer srv.Close()
+	gamma := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer gamma.Close()
+
+	client := newTestClient(srv, gamma)
+	client.SetMarketCacheTTL(time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		markets, err := client.FetchSamplingMarkets(ctx)
+		require.NoError(t, err)
+		require.Len(t, markets, 2)
+	}
+	assert.Equal(t, 1, calls, "metadata served from cache within the TTL")
+
+	client.InvalidateMarkets([]string{"0xabc123"})
+	_, err = client.FetchSamplingMarkets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a newly missing market forces a refresh")
+
+	client.InvalidateMarkets([]string{"0xabc123"})
+	_, err = client.FetchSamplingMarkets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a market still missing after a refresh does not force another")
+}
+
+func TestFetchSamplingMarkets_RepeatedCursorStops(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"data": []any{}, "next_cursor": "MTAw"})
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv, nil)
+	_, err := client.FetchSamplingMarkets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "stops once the API hands back a cursor it already returned")
+}
+
 func TestFetchOrderBooks_Batch(t *testing.T) {
 	data, err := os.ReadFile("../../../testdata/fixtures/clob_orderbooks_batch.json")
 	require.NoError(t, err)
@@ -120,3 +175,94 @@ func TestFetchOrderBooks_BatchSplitting(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 2, callCount, "debe hacer 2 requests batch para 25 tokens")
 }
+
+// booksServer responde POST /books con un book vacío por token. Un batch con
+// "bad" devuelve 400 y uno con "slow" tarda 2s; todos esperan delay.
+func booksServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req []struct {
+			TokenID string `json:"token_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		time.Sleep(delay)
+		resp := make([]map[string]any, 0, len(req))
+		for _, t := range req {
+			switch t.TokenID {
+			case "bad":
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			case "slow":
+				select {
+				case <-time.After(2 * time.Second):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			resp = append(resp, map[string]any{"asset_id": t.TokenID, "bids": []any{}, "asks": []any{}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func batchTokens(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s_%03d", prefix, i)
+	}
+	return ids
+}
+
+func TestFetchOrderBooks_PartialResultOnFailedAndSlowBatches(t *testing.T) {
+	srv := booksServer(0)
+	defer srv.Close()
+
+	client := newTestClient(srv, nil)
+	client.SetBookFetch(4, 200*time.Millisecond)
+
+	// 3 batches: uno sano, uno con un token que da 400 y otro que no responde a tiempo.
+	ok := batchTokens("ok", 20)
+	failing := append(batchTokens("f", 19), "bad")
+	slow := append(batchTokens("s", 19), "slow")
+	tokens := append(append(append([]string{}, ok...), failing...), slow...)
+
+	start := time.Now()
+	books, err := client.FetchOrderBooks(context.Background(), tokens)
+	require.NoError(t, err, "one healthy batch is enough")
+	assert.Less(t, time.Since(start), time.Second, "the slow batch is cut by its timeout")
+	assert.Len(t, books, 20)
+	for _, id := range ok {
+		assert.Contains(t, books, id)
+	}
+}
+
+func TestFetchOrderBooks_ErrorWhenNothingArrives(t *testing.T) {
+	srv := booksServer(0)
+	defer srv.Close()
+
+	client := newTestClient(srv, nil)
+	_, err := client.FetchOrderBooks(context.Background(), []string{"bad"})
+	assert.Error(t, err)
+}
+
+// BenchmarkFetchOrderBooks compara 1 worker con el pool por defecto sobre
+// 300 mercados (600 tokens, 30 batches) con 50ms de latencia por batch. Con
+// 8 workers el techo lo pone el rate limiter de /books, no la latencia.
+func BenchmarkFetchOrderBooks(b *testing.B) {
+	srv := booksServer(50 * time.Millisecond)
+	defer srv.Close()
+	tokens := batchTokens("tok", 600)
+
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			client := newTestClient(srv, nil)
+			client.SetBookFetch(workers, 0)
+			for i := 0; i < b.N; i++ {
+				books, err := client.FetchOrderBooks(context.Background(), tokens)
+				if err != nil || len(books) != len(tokens) {
+					b.Fatalf("got %d books, err %v", len(books), err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package polymarket
+
+// ParseUserMessages exposes parseUserMessages to the external test package.
+var ParseUserMessages = parseUserMessages
+
+// SetURL points the subscriber at a test server.
+func (bs *BookSubscriber) SetURL(url string) { bs.url = url }
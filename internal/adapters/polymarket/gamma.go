@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
@@ -12,6 +15,10 @@ import (
 const (
 	gammaMarketsPath  = "/markets"
 	gammaConditionMax = 20
+
+	// Paginación del histórico de mercados (backtest por fechas).
+	gammaHistoryPerPage  = 100
+	gammaHistoryMaxPages = 50
 )
 
 // EnrichWithGamma obtiene metadata de Gamma (question, slug, endDate, volume24h, fee)
@@ -76,3 +83,46 @@ func (c *Client) fetchGammaMetadata(ctx context.Context, conditionIDs []string)
 
 	return result, nil
 }
+
+// FetchHistoricalMarkets devuelve los mercados binarios con rewards que
+// estuvieron abiertos en algún momento de [from, to): abiertos antes de to y
+// con resolución a partir de from. Los precios son los que Gamma tiene
+// guardados (bestBid/bestAsk del token YES), no el book actual.
+func (c *Client) FetchHistoricalMarkets(ctx context.Context, from, to time.Time) ([]domain.Market, error) {
+	q := url.Values{}
+	q.Set("start_date_max", to.UTC().Format(time.RFC3339))
+	q.Set("end_date_min", from.UTC().Format(time.RFC3339))
+	q.Set("order", "endDate")
+	q.Set("ascending", "true")
+	q.Set("limit", strconv.Itoa(gammaHistoryPerPage))
+
+	var markets []domain.Market
+	for page := 0; page < gammaHistoryMaxPages; page++ {
+		q.Set("offset", strconv.Itoa(page*gammaHistoryPerPage))
+		var resp []gammaHistoricalMarket
+		if err := c.get(ctx, c.gammaLimiter, c.gammaBase+gammaMarketsPath+"?"+q.Encode(), &resp); err != nil {
+			return nil, fmt.Errorf("gamma.FetchHistoricalMarkets: %w", err)
+		}
+
+		for _, gm := range resp {
+			m, ok := mapGammaHistoricalMarket(gm)
+			if !ok || !m.HasRewards() {
+				continue
+			}
+			markets = append(markets, m)
+		}
+
+		slog.Debug("fetched gamma history page",
+			"page", page,
+			"count", len(resp),
+			"total", len(markets),
+		)
+
+		if len(resp) < gammaHistoryPerPage {
+			return markets, nil
+		}
+	}
+
+	slog.Warn("gamma history: page limit reached, markets truncated", "pages", gammaHistoryMaxPages)
+	return markets, nil
+}
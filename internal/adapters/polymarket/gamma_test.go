@@ -0,0 +1,58 @@
+package polymarket_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchHistoricalMarkets_Paginates(t *testing.T) {
+	var offsets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/markets", r.URL.Path)
+		assert.Equal(t, "2024-01-31T00:00:00Z", r.URL.Query().Get("start_date_max"))
+		assert.Equal(t, "2024-01-01T00:00:00Z", r.URL.Query().Get("end_date_min"))
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("offset") == "0" {
+			// Página llena: 99 mercados sin rewards + 1 válido.
+			fmt.Fprint(w, "[")
+			for i := 0; i < 99; i++ {
+				fmt.Fprintf(w, `{"conditionId": "0xnorew%d", "clobTokenIds": "[\"a\",\"b\"]"},`, i)
+			}
+			fmt.Fprint(w, `{"conditionId": "0xabc", "question": "Will it rain?", "endDateIso": "2024-01-15",
+				"startDateIso": "2023-12-01", "clobTokenIds": "[\"tok_yes\",\"tok_no\"]", "outcomes": "[\"Yes\",\"No\"]",
+				"bestBid": 0.45, "bestAsk": "0.47", "liquidityClob": 2500, "rewardsMinSize": 50, "rewardsMaxSpread": 3.5,
+				"clobRewards": [{"rewardsDailyRate": 10}, {"rewardsDailyRate": 2.5}]}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"conditionId": "0xsingle", "clobTokenIds": "[\"only\"]", "rewardsMaxSpread": 3, "clobRewards": [{"rewardsDailyRate": 1}]}]`)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(nil, srv)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	markets, err := client.FetchHistoricalMarkets(context.Background(), from, from.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "100"}, offsets)
+
+	require.Len(t, markets, 1, "markets without rewards or two tokens are skipped")
+	m := markets[0]
+	assert.Equal(t, "Will it rain?", m.Question)
+	assert.Equal(t, "tok_yes", m.YesToken().TokenID)
+	assert.Equal(t, "tok_no", m.NoToken().TokenID)
+	assert.InDelta(t, 0.45, m.BestBid, 1e-9)
+	assert.InDelta(t, 0.47, m.BestAsk, 1e-9)
+	assert.InDelta(t, 2500, m.Liquidity, 1e-9)
+	assert.InDelta(t, 12.5, m.Rewards.DailyRate, 1e-9)
+	assert.InDelta(t, 0.035, m.Rewards.MaxSpread, 1e-9)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), m.EndDate)
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), m.StartDate)
+}
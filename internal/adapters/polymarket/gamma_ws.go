@@ -0,0 +1,243 @@
+package polymarket
+
+// gamma_ws.go — Gamma market-state stream (WebSocket).
+//
+// Pushes resolutions, closes and reward-rate changes as they happen, so the
+// engines can stop quoting a resolved market without waiting for the next
+// REST scan to notice it.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+const (
+	defaultGammaStreamURL = "wss://ws-live-data.polymarket.com"
+
+	gammaStreamMaxBackoff = 60 * time.Second
+	gammaStreamBuffer     = 128
+)
+
+type gammaSubscribe struct {
+	Action        string              `json:"action"`
+	Subscriptions []gammaSubscription `json:"subscriptions"`
+}
+
+type gammaSubscription struct {
+	Topic string `json:"topic"`
+	Type  string `json:"type"`
+}
+
+// gammaMessage covers both the enveloped form ({"topic","type","payload"})
+// and flat events that carry their fields at the top level.
+type gammaMessage struct {
+	Topic     string          `json:"topic"`
+	Type      string          `json:"type"`
+	EventType string          `json:"event_type"`
+	Timestamp json.RawMessage `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+	gammaMarketFields
+}
+
+type gammaMarketFields struct {
+	Market           string  `json:"market"`
+	ConditionID      string  `json:"condition_id"`
+	ConditionIDCamel string  `json:"conditionId"`
+	WinningOutcome   string  `json:"winning_outcome"`
+	RewardsDailyRate float64 `json:"rewards_daily_rate"`
+	RewardsRateCamel float64 `json:"rewardsDailyRate"`
+}
+
+func (f gammaMarketFields) conditionID() string {
+	for _, id := range []string{f.ConditionID, f.ConditionIDCamel, f.Market} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// GammaSubscriber streams market-state changes from the Gamma WebSocket.
+type GammaSubscriber struct {
+	url     string
+	updates chan domain.MarketUpdate
+}
+
+// NewGammaSubscriber creates a Gamma stream subscriber. Call Run to connect.
+func NewGammaSubscriber() *GammaSubscriber {
+	return &GammaSubscriber{
+		url:     defaultGammaStreamURL,
+		updates: make(chan domain.MarketUpdate, gammaStreamBuffer),
+	}
+}
+
+// Updates returns the channel of market updates. It is closed when Run
+// returns. Implements ports.MarketUpdateStream.
+func (gs *GammaSubscriber) Updates() <-chan domain.MarketUpdate {
+	return gs.updates
+}
+
+// Run keeps the stream connected until ctx is cancelled, reconnecting with
+// exponential backoff.
+func (gs *GammaSubscriber) Run(ctx context.Context) error {
+	defer close(gs.updates)
+
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := gs.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if time.Since(start) > gammaStreamMaxBackoff {
+			backoff = time.Second
+		}
+		slog.Warn("gamma stream: disconnected, reconnecting", "err", err, "wait", backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, gammaStreamMaxBackoff)
+	}
+}
+
+// runOnce holds a single connection until it fails or ctx is cancelled.
+func (gs *GammaSubscriber) runOnce(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, gs.url, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	sub := gammaSubscribe{Action: "subscribe", Subscriptions: []gammaSubscription{
+		{Topic: "clob_market", Type: "market_resolved"},
+		{Topic: "clob_market", Type: "market_closed"},
+		{Topic: "clob_market", Type: "market_rewards"},
+	}}
+	if err := conn.WriteJSON(sub); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	slog.Info("gamma stream: connected")
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(userStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				_ = conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteMessage(websocket.TextMessage, []byte("PING")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		for _, u := range handleGammaFrame(data, time.Now()) {
+			select {
+			case gs.updates <- u:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// handleGammaFrame decodes a stream frame (single object or array) into
+// market updates. Unknown event types and frames without a condition ID are
+// dropped.
+func handleGammaFrame(data []byte, now time.Time) []domain.MarketUpdate {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "PONG" {
+		return nil
+	}
+
+	var msgs []gammaMessage
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			slog.Debug("gamma stream: bad frame", "err", err)
+			return nil
+		}
+	} else {
+		var m gammaMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			slog.Debug("gamma stream: bad frame", "err", err)
+			return nil
+		}
+		msgs = []gammaMessage{m}
+	}
+
+	var out []domain.MarketUpdate
+	for _, m := range msgs {
+		fields := m.gammaMarketFields
+		if len(m.Payload) > 0 && string(m.Payload) != "null" {
+			if err := json.Unmarshal(m.Payload, &fields); err != nil {
+				slog.Debug("gamma stream: bad payload", "type", m.Type, "err", err)
+				continue
+			}
+		}
+		kind, ok := gammaUpdateKind(m.EventType, m.Type)
+		if !ok {
+			continue
+		}
+		cid := fields.conditionID()
+		if cid == "" {
+			continue
+		}
+
+		at := parseTimestamp(strings.Trim(string(m.Timestamp), `"`))
+		if at.IsZero() {
+			at = now
+		}
+		u := domain.MarketUpdate{ConditionID: cid, Kind: kind, At: at.UTC()}
+		switch kind {
+		case domain.MarketUpdateResolved:
+			u.WinningOutcome = fields.WinningOutcome
+		case domain.MarketUpdateReward:
+			u.DailyRate = fields.RewardsDailyRate
+			if u.DailyRate == 0 {
+				u.DailyRate = fields.RewardsRateCamel
+			}
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// gammaUpdateKind maps a stream event type to a MarketUpdateKind.
+func gammaUpdateKind(types ...string) (domain.MarketUpdateKind, bool) {
+	for _, t := range types {
+		switch strings.ToLower(t) {
+		case "market_resolved", "resolved":
+			return domain.MarketUpdateResolved, true
+		case "market_closed", "closed":
+			return domain.MarketUpdateClosed, true
+		case "market_rewards", "rewards_update", "reward_rate_change":
+			return domain.MarketUpdateReward, true
+		}
+	}
+	return "", false
+}
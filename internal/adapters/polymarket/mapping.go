@@ -1,6 +1,7 @@
 package polymarket
 
 import (
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
@@ -53,6 +54,7 @@ func mapSamplingMarket(r samplingMarket) domain.Market {
 func enrichFromGamma(m *domain.Market, gm gammaMarket) {
 	m.Question = gm.Question
 	m.Slug = gm.Slug
+	m.Category = gm.Category
 
 	if v, err := gm.Volume24h.Float64(); err == nil {
 		m.Volume24h = v
@@ -62,20 +64,73 @@ func enrichFromGamma(m *domain.Market, gm gammaMarket) {
 		m.MakerBaseFee = fee
 	}
 
-	if gm.EndDateISO != "" {
-		// Polymarket usa varios formatos; intentamos los más comunes
-		for _, layout := range []string{
-			time.RFC3339,
-			"2006-01-02T15:04:05.000Z",
-			"2006-01-02T15:04:05Z",
-			"2006-01-02",
-		} {
-			if t, err := time.Parse(layout, gm.EndDateISO); err == nil {
-				m.EndDate = t.UTC()
-				break
-			}
+	if t, ok := parseGammaDate(gm.EndDateISO); ok {
+		m.EndDate = t
+	}
+}
+
+// parseGammaDate parsea una fecha de Gamma. Polymarket usa varios formatos;
+// intentamos los más comunes.
+func parseGammaDate(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05.000Z",
+		"2006-01-02T15:04:05Z",
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// mapGammaHistoricalMarket convierte un mercado histórico de Gamma a
+// domain.Market, con los precios y la liquidez que Gamma tenía guardados.
+// Devuelve false si el mercado no es binario o le faltan los tokens.
+func mapGammaHistoricalMarket(gm gammaHistoricalMarket) (domain.Market, bool) {
+	var tokenIDs, outcomes []string
+	if json.Unmarshal([]byte(gm.ClobTokenIDs), &tokenIDs) != nil || len(tokenIDs) != 2 {
+		return domain.Market{}, false
+	}
+	if json.Unmarshal([]byte(gm.Outcomes), &outcomes) != nil || len(outcomes) != 2 {
+		outcomes = []string{"Yes", "No"}
+	}
+
+	m := domain.Market{
+		ConditionID: gm.ConditionID,
+		Active:      gm.Active,
+		Closed:      gm.Closed,
+	}
+	enrichFromGamma(&m, gm.gammaMarket)
+	if t, ok := parseGammaDate(gm.StartDateISO); ok {
+		m.StartDate = t
+	}
+	for i := range m.Tokens {
+		m.Tokens[i] = domain.Token{TokenID: tokenIDs[i], Outcome: outcomes[i]}
+	}
+
+	m.BestBid, _ = gm.BestBid.Float64()
+	m.BestAsk, _ = gm.BestAsk.Float64()
+	m.Liquidity, _ = gm.LiquidityClob.Float64()
+	m.Rewards.MinSize, _ = gm.RewardsMinSize.Float64()
+	// Gamma da el max spread en centavos (3.5 = 0.035); el CLOB, como fracción.
+	if spread, err := gm.RewardsMaxSpread.Float64(); err == nil {
+		m.Rewards.MaxSpread = spread / 100
+	}
+	for _, r := range gm.ClobRewards {
+		if rate, err := r.RewardsDailyRate.Float64(); err == nil {
+			m.Rewards.DailyRate += rate
 		}
 	}
+	if m.BestBid > 0 && m.BestAsk > 0 {
+		m.Tokens[0].Price = (m.BestBid + m.BestAsk) / 2
+		m.Tokens[1].Price = 1 - m.Tokens[0].Price
+	}
+	return m, true
 }
 
 // mapOrderBooks convierte la respuesta batch de /books a un map tokenID→OrderBook.
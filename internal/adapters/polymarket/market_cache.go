@@ -0,0 +1,80 @@
+package polymarket
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// marketCache guarda la última lista de sampling markets (metadata de CLOB y
+// Gamma) durante ttl. La question, la fecha de resolución y el reward rate
+// cambian poco, así que no hace falta repaginar todo el universo cada ciclo.
+// ttl = 0 la desactiva.
+type marketCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	markets   []domain.Market
+	fetchedAt time.Time
+	// missing son los mercados sin orderbook del último aviso. Sobrevive a
+	// los refrescos para que un mercado que sigue listado sin book no fuerce
+	// un refresco en cada ciclo.
+	missing map[string]bool
+}
+
+// get devuelve una copia de la lista cacheada si sigue vigente en now.
+func (mc *marketCache) get(now time.Time) ([]domain.Market, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.ttl <= 0 || mc.markets == nil || now.Sub(mc.fetchedAt) >= mc.ttl {
+		return nil, false
+	}
+	return append([]domain.Market(nil), mc.markets...), true
+}
+
+func (mc *marketCache) put(markets []domain.Market, now time.Time) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.ttl <= 0 {
+		return
+	}
+	mc.markets = append([]domain.Market(nil), markets...)
+	mc.fetchedAt = now
+}
+
+// invalidate descarta la lista si algún mercado de missing no estaba ya
+// reportado: un refresco no lo arreglaría.
+func (mc *marketCache) invalidate(missing []string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	known := mc.missing
+	mc.missing = make(map[string]bool, len(missing))
+	fresh := 0
+	for _, id := range missing {
+		mc.missing[id] = true
+		if !known[id] {
+			fresh++
+		}
+	}
+	if fresh > 0 && mc.markets != nil {
+		slog.Debug("markets without orderbook, refreshing market list next cycle", "new_missing", fresh)
+		mc.markets = nil
+	}
+}
+
+// SetMarketCacheTTL activa el modo incremental de FetchSamplingMarkets: la
+// metadata de mercados se reutiliza durante ttl y solo los orderbooks se
+// piden en cada ciclo. ttl = 0 (por defecto) refresca siempre.
+func (c *Client) SetMarketCacheTTL(ttl time.Duration) {
+	c.marketCache.mu.Lock()
+	defer c.marketCache.mu.Unlock()
+	c.marketCache.ttl = ttl
+}
+
+// InvalidateMarkets fuerza un refresco completo en el próximo
+// FetchSamplingMarkets si hay mercados sin orderbook que no se habían
+// reportado antes. Implementa ports.MarketInvalidator.
+func (c *Client) InvalidateMarkets(missing []string) {
+	c.marketCache.invalidate(missing)
+}
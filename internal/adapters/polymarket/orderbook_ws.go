@@ -0,0 +1,357 @@
+package polymarket
+
+// orderbook_ws.go — Public CLOB market channel (WebSocket).
+//
+// Streams order-book snapshots for a set of tokens so spread stability can be
+// sampled between scanner cycles instead of only on cycle boundaries.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+const (
+	defaultMarketStreamURL = "wss://ws-subscriptions-clob.polymarket.com/ws/market"
+
+	bookStreamMaxBackoff = 60 * time.Second
+	bookStreamBuffer     = 512
+	bookStreamMaxAge     = 5 * time.Second
+)
+
+type marketSubscribe struct {
+	AssetsIDs []string `json:"assets_ids"`
+	Type      string   `json:"type"`
+}
+
+// marketMessage covers "book" snapshots and incremental "price_change" events.
+type marketMessage struct {
+	EventType    string             `json:"event_type"`
+	AssetID      string             `json:"asset_id"`
+	Timestamp    string             `json:"timestamp"`
+	Bids         []bookEntryRaw     `json:"bids"`
+	Asks         []bookEntryRaw     `json:"asks"`
+	Changes      []marketLevelDelta `json:"changes"`
+	PriceChanges []marketLevelDelta `json:"price_changes"`
+}
+
+type marketLevelDelta struct {
+	AssetID string `json:"asset_id"`
+	Price   string `json:"price"`
+	Size    string `json:"size"`
+	Side    string `json:"side"` // BUY = bid, SELL = ask
+}
+
+// localBook keeps per-price levels so price_change deltas can be applied.
+type localBook struct {
+	bids map[string]float64
+	asks map[string]float64
+}
+
+// BookSubscriber streams order-book snapshots for a dynamic set of tokens.
+type BookSubscriber struct {
+	url   string
+	books chan domain.OrderBook
+
+	mu     sync.Mutex
+	assets []string
+	resub  chan struct{}
+
+	local map[string]*localBook
+}
+
+// NewBookSubscriber creates a market channel subscriber. Call SetAssets to
+// choose the tokens and Run to connect.
+func NewBookSubscriber() *BookSubscriber {
+	return &BookSubscriber{
+		url:   defaultMarketStreamURL,
+		books: make(chan domain.OrderBook, bookStreamBuffer),
+		resub: make(chan struct{}, 1),
+		local: make(map[string]*localBook),
+	}
+}
+
+// Books returns the channel of book snapshots. It is closed when Run returns.
+func (bs *BookSubscriber) Books() <-chan domain.OrderBook {
+	return bs.books
+}
+
+// SetAssets replaces the subscribed token set. The connection is re-established
+// only when the set actually changes.
+func (bs *BookSubscriber) SetAssets(tokenIDs []string) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if sameAssets(bs.assets, tokenIDs) {
+		return
+	}
+	bs.assets = append([]string(nil), tokenIDs...)
+	select {
+	case bs.resub <- struct{}{}:
+	default:
+	}
+}
+
+func (bs *BookSubscriber) currentAssets() []string {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return append([]string(nil), bs.assets...)
+}
+
+// Run keeps the market channel connected until ctx is cancelled, reconnecting
+// with exponential backoff and whenever the asset set changes.
+func (bs *BookSubscriber) Run(ctx context.Context) error {
+	defer close(bs.books)
+
+	backoff := time.Second
+	for {
+		// The connection below subscribes to the current set, so a change
+		// signalled before it was read must not drop it straight away.
+		select {
+		case <-bs.resub:
+		default:
+		}
+		assets := bs.currentAssets()
+		if len(assets) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-bs.resub:
+				continue
+			}
+		}
+
+		start := time.Now()
+		err := bs.runOnce(ctx, assets)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == errResubscribe {
+			backoff = time.Second
+			continue
+		}
+		if time.Since(start) > bookStreamMaxBackoff {
+			backoff = time.Second
+		}
+		slog.Warn("book stream: disconnected, reconnecting", "err", err, "wait", backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, bookStreamMaxBackoff)
+	}
+}
+
+var errResubscribe = errors.New("asset set changed")
+
+// runOnce holds a single connection until it fails, the asset set changes or
+// ctx is cancelled.
+func (bs *BookSubscriber) runOnce(ctx context.Context, assets []string) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, bs.url, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(marketSubscribe{AssetsIDs: assets, Type: "market"}); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	// Snapshots from a previous connection are no longer authoritative.
+	bs.local = make(map[string]*localBook, len(assets))
+	slog.Info("book stream: connected", "assets", len(assets))
+
+	var (
+		done    = make(chan struct{})
+		resubMu sync.Mutex
+		resub   bool
+	)
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(userStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				_ = conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				conn.Close()
+				return
+			case <-bs.resub:
+				resubMu.Lock()
+				resub = true
+				resubMu.Unlock()
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteMessage(websocket.TextMessage, []byte("PING")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			resubMu.Lock()
+			changed := resub
+			resubMu.Unlock()
+			if changed {
+				return errResubscribe
+			}
+			return fmt.Errorf("read: %w", err)
+		}
+		for _, ob := range bs.handleMarketFrame(data, time.Now()) {
+			select {
+			case bs.books <- ob:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// handleMarketFrame decodes a market channel frame (single object or array),
+// updates local book state and returns the resulting snapshots. Events older
+// than bookStreamMaxAge are applied to local state but not emitted, so a
+// backlog replayed after a reconnect does not flood the consumer.
+func (bs *BookSubscriber) handleMarketFrame(data []byte, now time.Time) []domain.OrderBook {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "PONG" {
+		return nil
+	}
+
+	var msgs []marketMessage
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			slog.Debug("book stream: bad frame", "err", err)
+			return nil
+		}
+	} else {
+		var m marketMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			slog.Debug("book stream: bad frame", "err", err)
+			return nil
+		}
+		msgs = []marketMessage{m}
+	}
+
+	var out []domain.OrderBook
+	for _, m := range msgs {
+		ts := parseTimestamp(m.Timestamp)
+		fresh := ts.IsZero() || now.Sub(ts) <= bookStreamMaxAge
+
+		var touched []string
+		switch m.EventType {
+		case "book":
+			lb := &localBook{bids: levelMap(m.Bids), asks: levelMap(m.Asks)}
+			bs.local[m.AssetID] = lb
+			touched = []string{m.AssetID}
+		case "price_change":
+			deltas := m.PriceChanges
+			if len(deltas) == 0 {
+				deltas = m.Changes
+			}
+			touched = bs.applyDeltas(m.AssetID, deltas)
+		default:
+			continue
+		}
+
+		if !fresh {
+			slog.Debug("book stream: dropping stale event", "asset", m.AssetID, "age", now.Sub(ts))
+			continue
+		}
+		for _, id := range touched {
+			if lb, ok := bs.local[id]; ok {
+				out = append(out, lb.snapshot(id))
+			}
+		}
+	}
+	return out
+}
+
+// applyDeltas updates local books and returns the asset IDs it touched.
+// Deltas for assets without a prior snapshot are ignored.
+func (bs *BookSubscriber) applyDeltas(defaultAsset string, deltas []marketLevelDelta) []string {
+	var touched []string
+	seen := make(map[string]bool)
+	for _, d := range deltas {
+		asset := d.AssetID
+		if asset == "" {
+			asset = defaultAsset
+		}
+		lb, ok := bs.local[asset]
+		if !ok {
+			continue
+		}
+		levels := lb.bids
+		if strings.EqualFold(d.Side, "SELL") {
+			levels = lb.asks
+		}
+		if size := parseFloat(d.Size); size > 0 {
+			levels[d.Price] = size
+		} else {
+			delete(levels, d.Price)
+		}
+		if !seen[asset] {
+			seen[asset] = true
+			touched = append(touched, asset)
+		}
+	}
+	return touched
+}
+
+func (lb *localBook) snapshot(tokenID string) domain.OrderBook {
+	return domain.OrderBook{
+		TokenID: tokenID,
+		Bids:    mapBookEntries(levelEntries(lb.bids), false),
+		Asks:    mapBookEntries(levelEntries(lb.asks), true),
+	}
+}
+
+func levelMap(raw []bookEntryRaw) map[string]float64 {
+	m := make(map[string]float64, len(raw))
+	for _, r := range raw {
+		if size := parseFloat(r.Size); size > 0 {
+			m[r.Price] = size
+		}
+	}
+	return m
+}
+
+func levelEntries(levels map[string]float64) []bookEntryRaw {
+	raw := make([]bookEntryRaw, 0, len(levels))
+	for price, size := range levels {
+		raw = append(raw, bookEntryRaw{Price: price, Size: fmt.Sprintf("%g", size)})
+	}
+	return raw
+}
+
+func sameAssets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package polymarket_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/polymarket"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// marketServer is a fake CLOB market channel. Each connection gets the next
+// script: the frames to send after the subscription, then the server hangs
+// up (or holds the connection when hold is set).
+type marketServer struct {
+	*httptest.Server
+	subs chan []string
+}
+
+type marketScript struct {
+	frames []string
+	hold   bool
+}
+
+func newMarketServer(t *testing.T, scripts ...marketScript) *marketServer {
+	t.Helper()
+	ms := &marketServer{subs: make(chan []string, len(scripts)+1)}
+	next := make(chan marketScript, len(scripts))
+	for _, s := range scripts {
+		next <- s
+	}
+	upgrader := websocket.Upgrader{}
+	ms.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var sub struct {
+			AssetsIDs []string `json:"assets_ids"`
+		}
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		ms.subs <- sub.AssetsIDs
+
+		var script marketScript
+		select {
+		case script = <-next:
+		default:
+			script.hold = true
+		}
+		for _, f := range script.frames {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(f)); err != nil {
+				return
+			}
+		}
+		if script.hold {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(ms.Close)
+	return ms
+}
+
+func (ms *marketServer) wsURL() string {
+	return "ws" + strings.TrimPrefix(ms.URL, "http")
+}
+
+func bookFrame(asset string, ts time.Time, bid, ask string) string {
+	return fmt.Sprintf(`{"event_type": "book", "asset_id": %q, "timestamp": "%d",
+		"bids": [{"price": %q, "size": "100"}], "asks": [{"price": %q, "size": "50"}]}`,
+		asset, ts.UnixMilli(), bid, ask)
+}
+
+func nextBook(t *testing.T, books <-chan domain.OrderBook) domain.OrderBook {
+	t.Helper()
+	select {
+	case ob, ok := <-books:
+		require.True(t, ok, "stream closed")
+		return ob
+	case <-time.After(5 * time.Second):
+		t.Fatal("no book streamed")
+		return domain.OrderBook{}
+	}
+}
+
+func runSubscriber(t *testing.T, ms *marketServer, assets ...string) *polymarket.BookSubscriber {
+	t.Helper()
+	bs := polymarket.NewBookSubscriber()
+	bs.SetURL(ms.wsURL())
+	bs.SetAssets(assets)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = bs.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return bs
+}
+
+func TestBookSubscriber_AppliesDeltasAndDropsStaleEvents(t *testing.T) {
+	now := time.Now()
+	ms := newMarketServer(t, marketScript{hold: true, frames: []string{
+		bookFrame("tok", now, "0.45", "0.47"),
+		fmt.Sprintf(`{"event_type": "price_change", "asset_id": "tok", "timestamp": "%d", "price_changes": [
+			{"price": "0.46", "size": "20", "side": "BUY"},
+			{"price": "0.47", "size": "0", "side": "SELL"},
+			{"price": "0.48", "size": "30", "side": "SELL"}]}`, now.UnixMilli()),
+		fmt.Sprintf(`{"event_type": "price_change", "asset_id": "other", "timestamp": "%d",
+			"price_changes": [{"price": "0.10", "size": "5", "side": "BUY"}]}`, now.UnixMilli()),
+		bookFrame("tok", now.Add(-time.Minute), "0.30", "0.70"),
+		fmt.Sprintf(`[{"event_type": "price_change", "asset_id": "tok", "timestamp": "%d",
+			"price_changes": [{"price": "0.45", "size": "0", "side": "BUY"}]}]`, now.UnixMilli()),
+	}})
+	bs := runSubscriber(t, ms, "tok")
+
+	first := nextBook(t, bs.Books())
+	assert.Equal(t, "tok", first.TokenID)
+	assert.Equal(t, 0.45, first.BestBid())
+	assert.Equal(t, 0.47, first.BestAsk())
+
+	second := nextBook(t, bs.Books())
+	assert.Equal(t, 0.46, second.BestBid(), "the delta adds a better bid")
+	assert.Equal(t, 0.48, second.BestAsk(), "size 0 removes the level")
+
+	// The delta for a token with no snapshot is ignored and the minute-old
+	// snapshot is applied but not emitted, so the next book is the last
+	// delta on top of the stale snapshot.
+	third := nextBook(t, bs.Books())
+	assert.Equal(t, 0.30, third.BestBid())
+	assert.Equal(t, 0.70, third.BestAsk())
+}
+
+func TestBookSubscriber_ReconnectsAndResubscribes(t *testing.T) {
+	ms := newMarketServer(t,
+		marketScript{frames: []string{bookFrame("tok", time.Now(), "0.45", "0.47")}},
+		marketScript{hold: true, frames: []string{
+			// Deltas from before the reconnect apply to no snapshot: dropped.
+			fmt.Sprintf(`{"event_type": "price_change", "asset_id": "tok", "timestamp": "%d",
+				"price_changes": [{"price": "0.46", "size": "20", "side": "BUY"}]}`, time.Now().UnixMilli()),
+			bookFrame("tok", time.Now().Add(time.Second), "0.44", "0.48"),
+		}},
+		marketScript{hold: true},
+	)
+	bs := runSubscriber(t, ms, "tok")
+
+	assert.Equal(t, []string{"tok"}, <-ms.subs)
+	assert.Equal(t, 0.45, nextBook(t, bs.Books()).BestBid())
+
+	// The server hung up: the subscriber reconnects after its backoff and
+	// subscribes again with a fresh local book.
+	assert.Equal(t, []string{"tok"}, <-ms.subs)
+	assert.Equal(t, 0.44, nextBook(t, bs.Books()).BestBid())
+
+	// A new asset set reconnects at once, without waiting for a backoff.
+	start := time.Now()
+	bs.SetAssets([]string{"tok", "tok2"})
+	select {
+	case assets := <-ms.subs:
+		assert.ElementsMatch(t, []string{"tok", "tok2"}, assets)
+		assert.Less(t, time.Since(start), time.Second)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no resubscription after SetAssets")
+	}
+}
+
+func TestBookSubscriber_SameAssetsDoNotReconnect(t *testing.T) {
+	ms := newMarketServer(t, marketScript{hold: true})
+	bs := runSubscriber(t, ms, "a", "b")
+	assert.Equal(t, []string{"a", "b"}, <-ms.subs)
+
+	bs.SetAssets([]string{"b", "a"})
+	select {
+	case assets := <-ms.subs:
+		t.Fatalf("reconnected for the same asset set: %v", assets)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
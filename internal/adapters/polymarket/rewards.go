@@ -0,0 +1,82 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// rewardsMaxCursor caps the pages of GET /rewards/user read per day.
+const rewardsMaxCursor = 20
+
+// userEarning is one market's entry in GET /rewards/user.
+type userEarning struct {
+	Date         string  `json:"date"`
+	ConditionID  string  `json:"condition_id"`
+	AssetAddress string  `json:"asset_address"`
+	MakerAddress string  `json:"maker_address"`
+	Earnings     float64 `json:"earnings"`
+	AssetRate    float64 `json:"asset_rate"`
+}
+
+type userEarningsResponse struct {
+	Data       []userEarning `json:"data"`
+	NextCursor string        `json:"next_cursor"`
+}
+
+// RewardsClient reads the liquidity rewards Polymarket paid to the wallet of
+// an AuthClient. It implements ports.RewardsSource.
+type RewardsClient struct {
+	auth *AuthClient
+}
+
+// NewRewardsClient creates a rewards reader for the auth client's wallet.
+func NewRewardsClient(auth *AuthClient) *RewardsClient {
+	return &RewardsClient{auth: auth}
+}
+
+// EarningsForDay returns the rewards earned on the given UTC day, one entry
+// per market with non-zero earnings.
+func (rc *RewardsClient) EarningsForDay(ctx context.Context, date time.Time) ([]domain.LiveReward, error) {
+	if err := rc.auth.EnsureCreds(ctx); err != nil {
+		return nil, fmt.Errorf("rewards: creds: %w", err)
+	}
+
+	day := date.UTC().Truncate(24 * time.Hour)
+	q := url.Values{}
+	q.Set("date", day.Format("2006-01-02"))
+	q.Set("signature_type", "0") // EOA, as in buildSignedOrder
+
+	var rewards []domain.LiveReward
+	for page := 0; page < rewardsMaxCursor; page++ {
+		var resp userEarningsResponse
+		if err := rc.auth.doL2(ctx, http.MethodGet, "/rewards/user?"+q.Encode(), nil, &resp); err != nil {
+			return nil, fmt.Errorf("rewards: %w", err)
+		}
+		for _, e := range resp.Data {
+			if e.Earnings <= 0 {
+				continue
+			}
+			if e.MakerAddress != "" && !strings.EqualFold(e.MakerAddress, rc.auth.Address()) {
+				continue
+			}
+			rewards = append(rewards, domain.LiveReward{
+				Date:        day,
+				ConditionID: e.ConditionID,
+				Earnings:    e.Earnings,
+			})
+		}
+		if resp.NextCursor == "" || resp.NextCursor == endCursor {
+			return rewards, nil
+		}
+		q.Set("next_cursor", resp.NextCursor)
+	}
+	slog.Warn("rewards: page limit reached, earnings truncated", "pages", rewardsMaxCursor)
+	return rewards, nil
+}
@@ -15,6 +15,9 @@ const (
 	dataAPIBase    = "https://data-api.polymarket.com"
 	tradesPerPage  = 1000
 	tradesMaxPages = 3
+
+	// Límite de páginas al retroceder en el histórico (backtest por ventana).
+	tradesWindowMaxPages = 25
 )
 
 type rawDataTrade struct {
@@ -28,55 +31,115 @@ type rawDataTrade struct {
 	Status      string      `json:"status"`
 }
 
-// FetchTrades obtiene los trades recientes de un mercado usando la Data API pública.
-// Usa condition_id en lugar de token_id para obtener trades de ambos lados.
+// FetchTrades obtiene los trades recientes de un token (hasta tradesMaxPages
+// páginas). Equivale a FetchTradesSince con since cero.
 func (c *Client) FetchTrades(ctx context.Context, tokenID string) ([]domain.Trade, error) {
-	var all []domain.Trade
+	return c.FetchTradesSince(ctx, tokenID, time.Time{})
+}
+
+// FetchTradesSince obtiene los trades del token desde since (incluido). Con
+// since se le pide a la Data API solo lo posterior (parámetro since) y, por si
+// lo ignora, se pagina hacia atrás hasta pasarlo, como FetchTradesWindow. Un
+// since cero devuelve las tradesMaxPages páginas más recientes.
+func (c *Client) FetchTradesSince(ctx context.Context, tokenID string, since time.Time) ([]domain.Trade, error) {
+	if since.IsZero() {
+		return c.fetchTradesPages(ctx, tokenID, "", time.Time{}, time.Time{}, tradesMaxPages, "data-api.FetchTradesSince")
+	}
+	return c.fetchTradesPages(ctx, tokenID, fmt.Sprintf("&since=%d", since.Unix()), since, time.Time{},
+		tradesWindowMaxPages, "data-api.FetchTradesSince")
+}
 
-	for page := 0; page < tradesMaxPages; page++ {
+// FetchTradesWindow obtiene los trades de un token dentro de [from, to); un
+// to cero no pone límite por arriba.
+func (c *Client) FetchTradesWindow(ctx context.Context, tokenID string, from, to time.Time) ([]domain.Trade, error) {
+	return c.fetchTradesPages(ctx, tokenID, "", from, to, tradesWindowMaxPages, "data-api.FetchTradesWindow")
+}
+
+// fetchTradesPages pagina /trades del token dentro de [from, to) (ceros = sin
+// límite), con query añadido a cada petición. La Data API devuelve los trades
+// del más reciente al más antiguo, así que se pagina hacia atrás hasta pasar
+// from o agotar maxPages. Con offset, un trade nuevo entre dos páginas
+// desplaza el resto y repite el último de la anterior: se deduplica por ID.
+func (c *Client) fetchTradesPages(ctx context.Context, tokenID, query string, from, to time.Time, maxPages int, op string) ([]domain.Trade, error) {
+	var window []domain.Trade
+	seen := make(map[string]bool)
+
+	for page := 0; page < maxPages; page++ {
 		offset := page * tradesPerPage
-		url := fmt.Sprintf("%s/trades?asset=%s&limit=%d&offset=%d",
-			dataAPIBase, tokenID, tradesPerPage, offset)
+		url := fmt.Sprintf("%s/trades?asset=%s&limit=%d&offset=%d%s",
+			c.dataBase, tokenID, tradesPerPage, offset, query)
 
 		var resp []rawDataTrade
 		if err := c.get(ctx, c.clobLimiter, url, &resp); err != nil {
-			return nil, fmt.Errorf("data-api.FetchTrades: %w", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-
 		if len(resp) == 0 {
-			break
+			return window, nil
 		}
 
+		oldest := time.Time{}
 		for _, rt := range resp {
-			price, _ := rt.Price.Float64()
-			size, _ := rt.Size.Float64()
-			ts := parseTradeTimestamp(rt.Timestamp)
-
-			all = append(all, domain.Trade{
-				ID:        rt.ID,
-				TokenID:   rt.Asset,
-				Side:      rt.Side,
-				Price:     price,
-				Size:      size,
-				Timestamp: ts,
-			})
+			t := mapDataTrade(rt)
+			if oldest.IsZero() || t.Timestamp.Before(oldest) {
+				oldest = t.Timestamp
+			}
+			if t.Timestamp.Before(from) || (!to.IsZero() && !t.Timestamp.Before(to)) {
+				continue
+			}
+			if !firstSeen(seen, rt.ID) {
+				continue
+			}
+			window = append(window, t)
 		}
 
 		slog.Debug("fetched trades page",
 			"token", tokenID[:min(8, len(tokenID))]+"...",
 			"page", page,
 			"count", len(resp),
-			"total", len(all),
+			"total", len(window),
 		)
 
-		if len(resp) < tradesPerPage {
-			break
+		if len(resp) < tradesPerPage || oldest.Before(from) {
+			return window, nil
 		}
 	}
 
-	return all, nil
+	if !from.IsZero() {
+		slog.Warn("trade history truncated: window older than page limit",
+			"token", tokenID[:min(8, len(tokenID))]+"...",
+			"pages", maxPages,
+			"from", from.Format(time.DateOnly),
+			"trades", len(window),
+		)
+	}
+	return window, nil
 }
 
+// firstSeen marca id como visto y dice si es la primera vez. Los trades sin
+// ID no se pueden deduplicar y siempre pasan.
+func firstSeen(seen map[string]bool, id string) bool {
+	if id == "" {
+		return true
+	}
+	if seen[id] {
+		return false
+	}
+	seen[id] = true
+	return true
+}
+
+func mapDataTrade(rt rawDataTrade) domain.Trade {
+	price, _ := rt.Price.Float64()
+	size, _ := rt.Size.Float64()
+	return domain.Trade{
+		ID:        rt.ID,
+		TokenID:   rt.Asset,
+		Side:      rt.Side,
+		Price:     price,
+		Size:      size,
+		Timestamp: parseTradeTimestamp(rt.Timestamp),
+	}
+}
 
 func parseTradeTimestamp(n json.Number) time.Time {
 	s := n.String()
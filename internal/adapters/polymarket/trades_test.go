@@ -0,0 +1,41 @@
+package polymarket_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTradesSince_DropsOlderTrades(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("since"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": "new", "asset": "tok", "side": "SELL", "price": "0.45", "size": "10", "timestamp": 1700000100},
+			{"id": "edge", "asset": "tok", "side": "SELL", "price": "0.45", "size": "5", "timestamp": 1700000000},
+			{"id": "old", "asset": "tok", "side": "SELL", "price": "0.45", "size": "7", "timestamp": 1699999000}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(nil, nil)
+	c.SetDataAPIBase(srv.URL)
+
+	trades, err := c.FetchTradesSince(context.Background(), "tok", since)
+	require.NoError(t, err)
+	require.Len(t, trades, 2, "trades before since are dropped even if the API returns them")
+	assert.Equal(t, "new", trades[0].ID)
+	assert.Equal(t, "edge", trades[1].ID)
+
+	all, err := c.FetchTrades(context.Background(), "tok")
+	require.NoError(t, err)
+	assert.Len(t, all, 3, "FetchTrades is FetchTradesSince with no lower bound")
+	assert.Equal(t, []string{"1700000000", ""}, queries)
+}
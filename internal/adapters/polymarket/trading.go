@@ -3,23 +3,29 @@ package polymarket
 // trading.go — Real order execution via Polymarket CLOB API.
 //
 // Implements ports.OrderExecutor using AuthClient for L1/L2 auth.
-// All maker orders are placed as GTC (good-till-cancelled) limit bids.
+// Maker orders are placed as GTC (good-till-cancelled) limit bids; exits may
+// be sent as SELL FAK (fill-and-kill) orders that take liquidity immediately.
 
 import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/alejandrodnm/polybot/internal/adapters/onchain"
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
@@ -55,17 +61,25 @@ type clobOrderResponse struct {
 	Success        bool   `json:"success"`
 }
 
+// clobOpenOrder is an entry of GET /data/orders. Sizes are decimal share
+// counts, not micro-units; created_at is a Unix timestamp.
 type clobOpenOrder struct {
-	ID           string `json:"id"`
-	AssetID      string `json:"asset_id"`
-	Market       string `json:"market"`
-	Side         string `json:"side"`
-	OriginalSize string `json:"original_size"`
-	SizeMatched  string `json:"size_matched"`
-	Price        string `json:"price"`
-	Status       string `json:"status"`
-	CreatedAt    string `json:"created_at"`
-	Outcome      string `json:"outcome"`
+	ID           string      `json:"id"`
+	AssetID      string      `json:"asset_id"`
+	Market       string      `json:"market"`
+	Side         string      `json:"side"`
+	OriginalSize string      `json:"original_size"`
+	SizeMatched  string      `json:"size_matched"`
+	Price        string      `json:"price"`
+	Status       string      `json:"status"`
+	CreatedAt    json.Number `json:"created_at"`
+	Outcome      string      `json:"outcome"`
+}
+
+// amounts returns the order's limit price and its size and matched part in
+// shares, the unit the CLOB reports them in.
+func (o clobOpenOrder) amounts() (price, shares, matched float64) {
+	return parseFloat(o.Price), parseFloat(o.OriginalSize), parseFloat(o.SizeMatched)
 }
 
 type clobOrdersResponse struct {
@@ -73,6 +87,37 @@ type clobOrdersResponse struct {
 	NextCursor string          `json:"next_cursor"`
 }
 
+// clobTrade is an entry of GET /data/trades. Sizes are in shares.
+type clobTrade struct {
+	ID           string           `json:"id"`
+	TakerOrderID string           `json:"taker_order_id"`
+	AssetID      string           `json:"asset_id"`
+	Size         string           `json:"size"`
+	Price        string           `json:"price"`
+	Status       string           `json:"status"`
+	MatchTime    string           `json:"match_time"`
+	TraderSide   string           `json:"trader_side"` // MAKER | TAKER: our role in the trade
+	MakerOrders  []clobMakerOrder `json:"maker_orders"`
+}
+
+type clobMakerOrder struct {
+	OrderID       string `json:"order_id"`
+	MakerAddress  string `json:"maker_address"`
+	AssetID       string `json:"asset_id"`
+	MatchedAmount string `json:"matched_amount"`
+	Price         string `json:"price"`
+}
+
+type clobTradesResponse struct {
+	Data       []clobTrade `json:"data"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+type clobCancelResponse struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"`
+}
+
 type clobBalanceResponse struct {
 	Balance string `json:"balance"`
 }
@@ -81,14 +126,28 @@ type clobNegRiskResponse struct {
 	NegRisk bool `json:"neg_risk"`
 }
 
+type clobTickSizeResponse struct {
+	MinimumTickSize float64 `json:"minimum_tick_size"`
+}
+
 const (
+	cancelBatchSize = 25 // max order IDs per DELETE /orders request
+	tradesMaxCursor = 20 // max pages of GET /data/trades per call
+	ordersMaxCursor = 20 // max pages of GET /data/orders per call
+	endCursor       = "LTE="
+
 	usdcEAddress = "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"
 	ctfAddress   = "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"
+
+	// Exchanges that pull USDC.e from the wallet when our bids fill.
+	ctfExchangeAddress     = "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"
+	negRiskExchangeAddress = "0xC5d563A36AE78145C45a50134d48A1215220f80a"
 )
 
 var (
 	balanceOfABI    abi.ABI
 	balanceOfERC1155 abi.ABI
+	allowanceABI     abi.ABI
 )
 
 func init() {
@@ -109,35 +168,95 @@ func init() {
 	if err != nil {
 		panic("balanceOf erc1155 abi: " + err.Error())
 	}
+	allowanceABI, err = abi.JSON(strings.NewReader(`[{
+		"name":"allowance","type":"function",
+		"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],
+		"outputs":[{"name":"","type":"uint256"}]
+	}]`))
+	if err != nil {
+		panic("allowance abi: " + err.Error())
+	}
 }
 
 // TradingClient implements ports.OrderExecutor.
 type TradingClient struct {
 	auth      *AuthClient
-	rpcClient *ethclient.Client
+	rpcClient *onchain.RPCPool
+
+	placeRetryBase time.Duration // first PlaceOrder backoff; doubles per attempt
+	placeRetryCap  time.Duration // longest PlaceOrder backoff
 }
 
-// NewTradingClient creates a TradingClient. rpcURL is used for on-chain balance checks.
-func NewTradingClient(auth *AuthClient, rpcURL string) (*TradingClient, error) {
-	rpc, err := ethclient.Dial(rpcURL)
-	if err != nil {
-		return nil, fmt.Errorf("trading: dial rpc: %w", err)
+// NewTradingClient creates a TradingClient. rpc is used for on-chain balance
+// checks and may be shared with the merge client.
+func NewTradingClient(auth *AuthClient, rpc *onchain.RPCPool) *TradingClient {
+	return &TradingClient{
+		auth:           auth,
+		rpcClient:      rpc,
+		placeRetryBase: placeRetryBase,
+		placeRetryCap:  placeRetryCap,
+	}
+}
+
+// SetPlaceRetryWait changes the backoff between PlaceOrder attempts on a
+// transient CLOB error (by default 500ms doubling up to 5s). Values <= 0 keep
+// the current one.
+func (tc *TradingClient) SetPlaceRetryWait(base, limit time.Duration) {
+	if base > 0 {
+		tc.placeRetryBase = base
+	}
+	if limit > 0 {
+		tc.placeRetryCap = limit
 	}
-	return &TradingClient{auth: auth, rpcClient: rpc}, nil
 }
 
-// PlaceOrder signs and submits a BUY maker limit order to the CLOB.
+const (
+	placeAttempts  = 3 // POST /order attempts on a transient status
+	placeRetryBase = 500 * time.Millisecond
+	placeRetryCap  = 5 * time.Second
+)
+
+// gtdSecurityLead is the CLOB's safety margin on GTD orders: an order is
+// dropped one minute before its signed expiration, so it is signed that much
+// later than the requested expiry.
+const gtdSecurityLead = time.Minute
+
+// PlaceOrder signs and submits a limit order to the CLOB. Side defaults to BUY
+// and order type to GTC when not set on the request; a non-zero ExpiresAt
+// makes it a GTD order.
 func (tc *TradingClient) PlaceOrder(ctx context.Context, req domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
 	if err := tc.auth.EnsureCreds(ctx); err != nil {
 		return domain.PlacedOrder{}, fmt.Errorf("place order: creds: %w", err)
 	}
 
-	signed, err := tc.auth.buildSignedOrder(req.TokenID, req.Price, req.Size, req.NegRisk)
+	sideStr := "BUY"
+	if req.Side == "SELL" {
+		sideStr = "SELL"
+	}
+	orderType := req.OrderType
+	if orderType == "" {
+		orderType = "GTC"
+	}
+	var expiration int64
+	if !req.ExpiresAt.IsZero() {
+		if orderType == "GTC" {
+			orderType = "GTD"
+		}
+		if !req.ExpiresAt.After(time.Now()) {
+			return domain.PlacedOrder{}, fmt.Errorf("place order: expiration %s is in the past", req.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		expiration = req.ExpiresAt.Add(gtdSecurityLead).Unix()
+	}
+
+	shares := req.Shares
+	if shares <= 0 {
+		shares = domain.SharesAt(req.Size, req.Price)
+	}
+	signed, err := tc.auth.buildSignedOrder(req.TokenID, sideStr, req.Price, shares, req.NegRisk, expiration)
 	if err != nil {
 		return domain.PlacedOrder{}, fmt.Errorf("place order: sign: %w", err)
 	}
 
-	sideStr := "BUY"
 	body := clobOrderRequest{
 		Order: clobOrderBody{
 			Salt:          json.Number(signed.Order.Salt.String()),
@@ -155,16 +274,16 @@ func (tc *TradingClient) PlaceOrder(ctx context.Context, req domain.PlaceOrderRe
 			Signature:     "0x" + hex.EncodeToString(signed.Signature),
 		},
 		Owner:     tc.auth.creds.APIKey,
-		OrderType: "GTC",
+		OrderType: orderType,
 	}
 
-	var resp clobOrderResponse
-	if err := tc.auth.doL2(ctx, http.MethodPost, "/order", body, &resp); err != nil {
+	resp, err := tc.postOrder(ctx, body)
+	if err != nil {
 		return domain.PlacedOrder{}, fmt.Errorf("place order: post: %w", err)
 	}
 
 	if !resp.Success || resp.ErrorMsg != "" {
-		return domain.PlacedOrder{}, fmt.Errorf("place order: clob error: %s", resp.ErrorMsg)
+		return domain.PlacedOrder{}, fmt.Errorf("place order: %w: %s", domain.ErrOrderRejected, resp.ErrorMsg)
 	}
 
 	takenAmt := parseUSDC(resp.TakingAmount)
@@ -178,6 +297,57 @@ func (tc *TradingClient) PlaceOrder(ctx context.Context, req domain.PlaceOrderRe
 	}, nil
 }
 
+// postOrder sends a signed order, retrying up to placeAttempts times with
+// full-jitter exponential backoff while the CLOB answers 500, 502 or 503.
+//
+// Resending is safe only because body is signed once, before the first
+// attempt, and every retry posts it unchanged. The CLOB keys an order by its
+// EIP-712 hash, which covers the salt and every signed field (the orderID it
+// returns is that hash), and does not book a hash it already holds: if the
+// failed attempt did land, the resend cannot place a second order. Re-signing
+// here would draw a new salt, a new hash and a possible duplicate.
+//
+// 429s are already retried by doL2 (honouring Retry-After); network errors
+// and other 5xx are not, since the order may have landed. A 400 or 422 wraps
+// domain.ErrNonRetryable.
+func (tc *TradingClient) postOrder(ctx context.Context, body clobOrderRequest) (clobOrderResponse, error) {
+	for attempt := 1; ; attempt++ {
+		var resp clobOrderResponse
+		err := tc.auth.doL2(ctx, http.MethodPost, "/order", body, &resp)
+		var se *statusError
+		if err == nil || !errors.As(err, &se) {
+			return resp, err
+		}
+		switch se.Code {
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return resp, fmt.Errorf("%w: %w", domain.ErrNonRetryable, err)
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+			if attempt == placeAttempts {
+				return resp, fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+		default:
+			return resp, err
+		}
+		wait := fullJitter(attempt-1, tc.placeRetryBase, tc.placeRetryCap)
+		slog.Warn("place order: transient CLOB error, retrying",
+			"status", se.Code, "attempt", attempt, "wait", wait)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return resp, fmt.Errorf("after %d attempts: %w", attempt, ctx.Err())
+		}
+	}
+}
+
+// fullJitter is a uniformly random wait in [0, min(limit, base*2^attempt)).
+func fullJitter(attempt int, base, limit time.Duration) time.Duration {
+	backoff := min(base<<attempt, limit)
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff)
+}
+
 // CancelOrder cancels a single order by its CLOB order ID.
 func (tc *TradingClient) CancelOrder(ctx context.Context, clobOrderID string) error {
 	if err := tc.auth.EnsureCreds(ctx); err != nil {
@@ -191,6 +361,38 @@ func (tc *TradingClient) CancelOrder(ctx context.Context, clobOrderID string) er
 	return nil
 }
 
+// CancelBatch cancels the given orders using DELETE /orders, sending at most
+// cancelBatchSize IDs per request. Failures from every chunk are combined
+// into a single error so callers can log them once.
+func (tc *TradingClient) CancelBatch(ctx context.Context, clobOrderIDs []string) error {
+	if len(clobOrderIDs) == 0 {
+		return nil
+	}
+	if err := tc.auth.EnsureCreds(ctx); err != nil {
+		return fmt.Errorf("cancel batch: creds: %w", err)
+	}
+
+	var errs []error
+	for start := 0; start < len(clobOrderIDs); start += cancelBatchSize {
+		end := min(start+cancelBatchSize, len(clobOrderIDs))
+		chunk := clobOrderIDs[start:end]
+
+		var resp clobCancelResponse
+		if err := tc.auth.doL2(ctx, http.MethodDelete, "/orders", chunk, &resp); err != nil {
+			errs = append(errs, fmt.Errorf("chunk %d-%d: %w", start, end, err))
+			continue
+		}
+		for id, reason := range resp.NotCanceled {
+			errs = append(errs, fmt.Errorf("order %s: %s", id, reason))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("cancel batch: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
 // CancelAll cancels all open orders for this wallet.
 func (tc *TradingClient) CancelAll(ctx context.Context) error {
 	if err := tc.auth.EnsureCreds(ctx); err != nil {
@@ -209,19 +411,71 @@ func (tc *TradingClient) GetOpenOrders(ctx context.Context) ([]domain.LiveOrder,
 		return nil, fmt.Errorf("get orders: creds: %w", err)
 	}
 
-	var resp clobOrdersResponse
-	if err := tc.auth.doL2(ctx, http.MethodGet, "/orders", nil, &resp); err != nil {
+	resp, err := tc.openOrders(ctx)
+	if err != nil {
 		return nil, fmt.Errorf("get orders: %w", err)
 	}
 
-	orders := make([]domain.LiveOrder, 0, len(resp.Data))
-	for _, o := range resp.Data {
+	orders := make([]domain.LiveOrder, 0, len(resp))
+	for _, o := range resp {
 		lo := clobOpenOrderToLiveOrder(o)
 		orders = append(orders, lo)
 	}
 	return orders, nil
 }
 
+// openOrders fetches the raw open orders of this wallet, following the
+// cursor. A partial list is an error: callers treat a missing order as gone.
+func (tc *TradingClient) openOrders(ctx context.Context) ([]clobOpenOrder, error) {
+	q := url.Values{}
+	var orders []clobOpenOrder
+	for page := 0; page < ordersMaxCursor; page++ {
+		var resp clobOrdersResponse
+		if err := tc.auth.doL2(ctx, http.MethodGet, "/data/orders?"+q.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		orders = append(orders, resp.Data...)
+		if resp.NextCursor == "" || resp.NextCursor == endCursor {
+			return orders, nil
+		}
+		q.Set("next_cursor", resp.NextCursor)
+	}
+	return nil, fmt.Errorf("open orders: more than %d pages", ordersMaxCursor)
+}
+
+// GetTrades returns this wallet's trades matched after the given time, as
+// one LiveTrade per order of ours in each trade: the taker order when we took
+// liquidity, otherwise every maker order of this address. Failed trades are
+// skipped.
+func (tc *TradingClient) GetTrades(ctx context.Context, after time.Time) ([]domain.LiveTrade, error) {
+	if err := tc.auth.EnsureCreds(ctx); err != nil {
+		return nil, fmt.Errorf("get trades: creds: %w", err)
+	}
+
+	q := url.Values{}
+	q.Set("maker_address", tc.auth.Address())
+	if !after.IsZero() {
+		q.Set("after", strconv.FormatInt(after.Unix(), 10))
+	}
+
+	var trades []domain.LiveTrade
+	for page := 0; page < tradesMaxCursor; page++ {
+		var resp clobTradesResponse
+		if err := tc.auth.doL2(ctx, http.MethodGet, "/data/trades?"+q.Encode(), nil, &resp); err != nil {
+			return nil, fmt.Errorf("get trades: %w", err)
+		}
+		for _, t := range resp.Data {
+			trades = append(trades, clobTradeToLiveTrades(t, tc.auth.Address())...)
+		}
+		if resp.NextCursor == "" || resp.NextCursor == endCursor {
+			return trades, nil
+		}
+		q.Set("next_cursor", resp.NextCursor)
+	}
+	slog.Warn("get trades: page limit reached, history truncated", "pages", tradesMaxCursor)
+	return trades, nil
+}
+
 // GetBalance returns the on-chain USDC.e balance of the funder address.
 func (tc *TradingClient) GetBalance(ctx context.Context) (float64, error) {
 	callData, err := balanceOfABI.Pack("balanceOf", tc.auth.address)
@@ -248,6 +502,77 @@ func (tc *TradingClient) GetBalance(ctx context.Context) (float64, error) {
 	return bal, nil
 }
 
+// GetTradeableBalance returns the USDC.e balance together with the allowance
+// granted to the exchanges and the USDC still reserved by our open bids.
+func (tc *TradingClient) GetTradeableBalance(ctx context.Context) (domain.TradeableBalance, error) {
+	bal, err := tc.GetBalance(ctx)
+	if err != nil {
+		return domain.TradeableBalance{}, fmt.Errorf("tradeable balance: %w", err)
+	}
+	allowance, err := tc.exchangeAllowance(ctx)
+	if err != nil {
+		return domain.TradeableBalance{}, fmt.Errorf("tradeable balance: %w", err)
+	}
+	if err := tc.auth.EnsureCreds(ctx); err != nil {
+		return domain.TradeableBalance{}, fmt.Errorf("tradeable balance: creds: %w", err)
+	}
+	open, err := tc.openOrders(ctx)
+	if err != nil {
+		return domain.TradeableBalance{}, fmt.Errorf("tradeable balance: open orders: %w", err)
+	}
+	return domain.TradeableBalance{
+		Wallet:         bal,
+		Allowance:      allowance,
+		LockedInOrders: lockedInBids(open),
+	}, nil
+}
+
+// exchangeAllowance returns the smaller USDC.e allowance of the two
+// exchanges: a bid may route to either, so the lower one is what is safe.
+func (tc *TradingClient) exchangeAllowance(ctx context.Context) (float64, error) {
+	token := common.HexToAddress(usdcEAddress)
+	lowest := -1.0
+	for _, ex := range []string{ctfExchangeAddress, negRiskExchangeAddress} {
+		callData, err := allowanceABI.Pack("allowance", tc.auth.address, common.HexToAddress(ex))
+		if err != nil {
+			return 0, fmt.Errorf("allowance: pack: %w", err)
+		}
+		result, err := tc.rpcClient.CallContract(ctx, ethereum.CallMsg{
+			To:   &token,
+			Data: callData,
+		}, nil)
+		if err != nil {
+			return 0, fmt.Errorf("allowance: rpc call: %w", err)
+		}
+		vals, err := allowanceABI.Unpack("allowance", result)
+		if err != nil || len(vals) == 0 {
+			return 0, fmt.Errorf("allowance: unpack: %w", err)
+		}
+		raw := vals[0].(*big.Int)
+		f, _ := new(big.Float).Quo(new(big.Float).SetInt(raw), new(big.Float).SetFloat64(1e6)).Float64()
+		if lowest < 0 || f < lowest {
+			lowest = f
+		}
+	}
+	return lowest, nil
+}
+
+// lockedInBids sums the USDC backing the unmatched part of our BUY orders,
+// the unmatched shares valued at the limit price.
+func lockedInBids(orders []clobOpenOrder) float64 {
+	var locked float64
+	for _, o := range orders {
+		if !strings.EqualFold(o.Side, "BUY") {
+			continue
+		}
+		price, shares, matched := o.amounts()
+		if rest := shares - matched; rest > 0 {
+			locked += rest * price
+		}
+	}
+	return locked
+}
+
 // IsNegRisk queries the CLOB to determine if a token uses the NegRisk adapter.
 func (tc *TradingClient) IsNegRisk(ctx context.Context, tokenID string) (bool, error) {
 	url := fmt.Sprintf("%s/neg-risk?token_id=%s", tc.auth.clobBase, tokenID)
@@ -259,6 +584,20 @@ func (tc *TradingClient) IsNegRisk(ctx context.Context, tokenID string) (bool, e
 	return resp.NegRisk, nil
 }
 
+// TickSize queries the CLOB for the minimum price increment of a token.
+func (tc *TradingClient) TickSize(ctx context.Context, tokenID string) (float64, error) {
+	url := fmt.Sprintf("%s/tick-size?token_id=%s", tc.auth.clobBase, tokenID)
+
+	var resp clobTickSizeResponse
+	if err := tc.auth.get(ctx, tc.auth.clobLimiter, url, &resp); err != nil {
+		return 0, fmt.Errorf("tick size: %w", err)
+	}
+	if resp.MinimumTickSize <= 0 || resp.MinimumTickSize >= 1 {
+		return 0, fmt.Errorf("tick size: invalid value %g for token %s", resp.MinimumTickSize, tokenID)
+	}
+	return resp.MinimumTickSize, nil
+}
+
 // TokenBalance returns the on-chain ERC-1155 balance for a conditional token.
 // Returns shares (not micro-units) — e.g. 13.51 means 13.51 shares.
 func (tc *TradingClient) TokenBalance(ctx context.Context, tokenID string) (float64, error) {
@@ -297,11 +636,10 @@ func (tc *TradingClient) TokenBalance(ctx context.Context, tokenID string) (floa
 	return f, nil
 }
 
-// clobOpenOrderToLiveOrder converts a CLOB API order to our domain type.
+// clobOpenOrderToLiveOrder converts a CLOB API order to our domain type,
+// valuing its shares at the limit price as our orders' USDC sizes are.
 func clobOpenOrderToLiveOrder(o clobOpenOrder) domain.LiveOrder {
-	size := parseUSDCStr(o.OriginalSize)
-	filled := parseUSDCStr(o.SizeMatched)
-	price := parseFloat(o.Price)
+	price, shares, matched := o.amounts()
 
 	status := domain.LiveStatusOpen
 	upper := strings.ToUpper(o.Status)
@@ -323,13 +661,51 @@ func clobOpenOrderToLiveOrder(o clobOpenOrder) domain.LiveOrder {
 		TokenID:     o.AssetID,
 		Side:        side,
 		BidPrice:    price,
-		Size:        size,
-		FilledSize:  filled,
+		Size:        shares * price,
+		SizeShares:  shares,
+		FilledSize:  matched * price,
 		Status:      status,
-		PlacedAt:    parseTimestamp(o.CreatedAt),
+		PlacedAt:    parseTimestamp(o.CreatedAt.String()),
 	}
 }
 
+// clobTradeToLiveTrades extracts the legs of a trade that belong to address.
+func clobTradeToLiveTrades(t clobTrade, address string) []domain.LiveTrade {
+	if strings.EqualFold(t.Status, "FAILED") {
+		return nil
+	}
+	ts := parseTimestamp(t.MatchTime)
+
+	if strings.EqualFold(t.TraderSide, "TAKER") {
+		price := parseFloat(t.Price)
+		return []domain.LiveTrade{{
+			CLOBTradeID: t.ID,
+			CLOBOrderID: t.TakerOrderID,
+			TokenID:     t.AssetID,
+			Price:       price,
+			Size:        parseFloat(t.Size) * price,
+			Timestamp:   ts,
+		}}
+	}
+
+	var out []domain.LiveTrade
+	for _, mo := range t.MakerOrders {
+		if !strings.EqualFold(mo.MakerAddress, address) {
+			continue
+		}
+		price := parseFloat(mo.Price)
+		out = append(out, domain.LiveTrade{
+			CLOBTradeID: t.ID,
+			CLOBOrderID: mo.OrderID,
+			TokenID:     mo.AssetID,
+			Price:       price,
+			Size:        parseFloat(mo.MatchedAmount) * price,
+			Timestamp:   ts,
+		})
+	}
+	return out
+}
+
 // parseUSDC converts a micro-USDC string (e.g., "1000000") to USDC float.
 func parseUSDC(s string) float64 {
 	if s == "" {
@@ -341,10 +717,6 @@ func parseUSDC(s string) float64 {
 	return f / 1_000_000
 }
 
-func parseUSDCStr(s string) float64 {
-	return parseUSDC(s)
-}
-
 func parseFloat(s string) float64 {
 	if s == "" {
 		return 0
@@ -0,0 +1,521 @@
+package polymarket_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/onchain"
+	"github.com/alejandrodnm/polybot/internal/adapters/polymarket"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestTradingClient_GetTradesAttributesOwnOrders(t *testing.T) {
+	var auth *polymarket.AuthClient
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/data/trades":
+			assert.Equal(t, auth.Address(), r.URL.Query().Get("maker_address"))
+			assert.Equal(t, "1700000000", r.URL.Query().Get("after"))
+			if r.URL.Query().Get("next_cursor") == "" {
+				w.Write([]byte(`{"next_cursor": "MQ==", "data": [
+					{"id": "t1", "status": "MATCHED", "match_time": "1700000100", "trader_side": "MAKER",
+					 "maker_orders": [
+						{"order_id": "0xmine", "maker_address": "` + auth.Address() + `", "asset_id": "yes", "matched_amount": "10", "price": "0.45"},
+						{"order_id": "0xother", "maker_address": "0xsomeoneelse", "asset_id": "yes", "matched_amount": "5", "price": "0.45"}
+					 ]},
+					{"id": "t2", "status": "FAILED", "match_time": "1700000200", "trader_side": "TAKER",
+					 "taker_order_id": "0xfailed", "asset_id": "no", "size": "4", "price": "0.50"}
+				]}`))
+				return
+			}
+			w.Write([]byte(`{"next_cursor": "LTE=", "data": [
+				{"id": "t3", "status": "CONFIRMED", "match_time": "1700000300", "trader_side": "TAKER",
+				 "taker_order_id": "0xsell", "asset_id": "no", "size": "8", "price": "0.25"}
+			]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+
+	trades, err := tc.GetTrades(context.Background(), time.Unix(1700000000, 0))
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+
+	assert.Equal(t, "t1", trades[0].CLOBTradeID)
+	assert.Equal(t, "0xmine", trades[0].CLOBOrderID)
+	assert.InDelta(t, 0.45, trades[0].Price, 1e-9)
+	assert.InDelta(t, 4.5, trades[0].Size, 1e-9) // 10 shares × $0.45
+	assert.Equal(t, time.Unix(1700000100, 0).UTC(), trades[0].Timestamp)
+
+	assert.Equal(t, "0xsell", trades[1].CLOBOrderID)
+	assert.InDelta(t, 2.0, trades[1].Size, 1e-9)
+}
+
+func TestRewardsClient_EarningsForDay(t *testing.T) {
+	var auth *polymarket.AuthClient
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/rewards/user":
+			assert.Equal(t, "2026-03-01", r.URL.Query().Get("date"))
+			if r.URL.Query().Get("next_cursor") == "" {
+				w.Write([]byte(`{"next_cursor": "MQ==", "data": [
+					{"date": "2026-03-01", "condition_id": "0xa", "maker_address": "` + auth.Address() + `", "earnings": 1.25},
+					{"date": "2026-03-01", "condition_id": "0xb", "maker_address": "` + auth.Address() + `", "earnings": 0}
+				]}`))
+				return
+			}
+			w.Write([]byte(`{"next_cursor": "LTE=", "data": [
+				{"date": "2026-03-01", "condition_id": "0xc", "maker_address": "` + auth.Address() + `", "earnings": 0.4},
+				{"date": "2026-03-01", "condition_id": "0xd", "maker_address": "0xsomeoneelse", "earnings": 3}
+			]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	rc := polymarket.NewRewardsClient(auth)
+
+	rewards, err := rc.EarningsForDay(context.Background(), time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, rewards, 2)
+	assert.Equal(t, "0xa", rewards[0].ConditionID)
+	assert.InDelta(t, 1.25, rewards[0].Earnings, 1e-9)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), rewards[0].Date)
+	assert.Equal(t, "0xc", rewards[1].ConditionID)
+}
+
+func TestTradingClient_PlaceOrderNotRetriedOnGatewayTimeout(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			posts.Add(1)
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+
+	_, err = tc.PlaceOrder(context.Background(), domain.PlaceOrderRequest{
+		TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY",
+	})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), posts.Load(), "the order may have landed; it must not be resent")
+}
+
+func TestTradingClient_PlaceOrderRetriesTransientStatus(t *testing.T) {
+	var bodies []string
+	statuses := []int{http.StatusServiceUnavailable, http.StatusBadGateway}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) <= len(statuses) {
+				w.WriteHeader(statuses[len(bodies)-1])
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "orderID": "0xo1", "status": "live"})
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+	tc.SetPlaceRetryWait(time.Millisecond, time.Millisecond)
+
+	placed, err := tc.PlaceOrder(context.Background(), domain.PlaceOrderRequest{
+		TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0xo1", placed.CLOBOrderID)
+	require.Len(t, bodies, 3)
+	assert.Equal(t, bodies[0], bodies[2], "the same signed order is resent")
+
+	// Three transient answers in a row exhaust the attempts.
+	bodies, statuses = nil, []int{500, 500, 500}
+	_, err = tc.PlaceOrder(context.Background(), domain.PlaceOrderRequest{
+		TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY",
+	})
+	require.Error(t, err)
+	assert.Len(t, bodies, 3)
+}
+
+func TestTradingClient_PlaceOrderResendsTheSameSignedOrder(t *testing.T) {
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, b)
+			if len(bodies)%2 == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "orderID": "0xo1", "status": "live"})
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+	tc.SetPlaceRetryWait(time.Millisecond, time.Millisecond)
+	req := domain.PlaceOrderRequest{TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY"}
+
+	signed := func(body []byte) (salt, signature string) {
+		var sent struct {
+			Order struct {
+				Salt      json.Number `json:"salt"`
+				Signature string      `json:"signature"`
+			} `json:"order"`
+		}
+		require.NoError(t, json.Unmarshal(body, &sent))
+		require.NotEmpty(t, sent.Order.Salt)
+		require.NotEmpty(t, sent.Order.Signature)
+		return sent.Order.Salt.String(), sent.Order.Signature
+	}
+
+	_, err = tc.PlaceOrder(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1], "the retry posts the failed attempt's bytes")
+	salt, sig := signed(bodies[0])
+	resentSalt, resentSig := signed(bodies[1])
+	assert.Equal(t, salt, resentSalt, "same salt, same order hash")
+	assert.Equal(t, sig, resentSig)
+
+	// A new PlaceOrder is a new order: it is signed with a fresh salt.
+	_, err = tc.PlaceOrder(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, bodies, 4)
+	nextSalt, nextSig := signed(bodies[2])
+	assert.NotEqual(t, salt, nextSalt)
+	assert.NotEqual(t, sig, nextSig)
+}
+
+func TestTradingClient_PlaceOrderClassifiesFailures(t *testing.T) {
+	var posts atomic.Int32
+	status, errorMsg := http.StatusUnprocessableEntity, ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			posts.Add(1)
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": false, "errorMsg": errorMsg})
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+	req := domain.PlaceOrderRequest{TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY"}
+
+	_, err = tc.PlaceOrder(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrNonRetryable)
+	assert.Equal(t, int32(1), posts.Load(), "a malformed order is not resent")
+
+	status, errorMsg = http.StatusOK, "invalid tick size"
+	_, err = tc.PlaceOrder(context.Background(), req)
+	assert.ErrorIs(t, err, domain.ErrOrderRejected)
+	assert.ErrorContains(t, err, "invalid tick size")
+	assert.NotErrorIs(t, err, domain.ErrNonRetryable)
+}
+
+func TestTradingClient_PlaceOrderGTD(t *testing.T) {
+	var body struct {
+		Order struct {
+			Expiration string `json:"expiration"`
+		} `json:"order"`
+		OrderType string `json:"orderType"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			json.NewDecoder(r.Body).Decode(&body)
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "orderID": "0xo1", "status": "live"})
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+
+	expires := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	_, err = tc.PlaceOrder(context.Background(), domain.PlaceOrderRequest{
+		TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY", ExpiresAt: expires,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "GTD", body.OrderType)
+	assert.Equal(t, strconv.FormatInt(expires.Add(time.Minute).Unix(), 10), body.Order.Expiration,
+		"signed one minute late to cover the CLOB's security lead")
+
+	_, err = tc.PlaceOrder(context.Background(), domain.PlaceOrderRequest{
+		TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY", ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.Error(t, err, "an expiry in the past is rejected before signing")
+}
+
+// beats records the health beats per component.
+type beats map[string][]error
+
+func (b beats) Beat(component string, err error) { b[component] = append(b[component], err) }
+
+func TestClient_ReportsCLOBHealth(t *testing.T) {
+	status := http.StatusBadRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/order":
+			w.WriteHeader(status)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	got := beats{}
+	auth.SetHealth(got)
+	tc := polymarket.NewTradingClient(auth, nil)
+	req := domain.PlaceOrderRequest{TokenID: "123456", Price: 0.45, Size: 5, Side: "BUY"}
+
+	_, err = tc.PlaceOrder(context.Background(), req)
+	require.Error(t, err)
+	status = http.StatusGatewayTimeout
+	_, err = tc.PlaceOrder(context.Background(), req)
+	require.Error(t, err)
+
+	clob := got[ports.HealthCLOB]
+	require.Len(t, clob, 2)
+	assert.NoError(t, clob[0], "a 4xx means the CLOB answered")
+	assert.Error(t, clob[1])
+}
+
+func TestClient_RetriesAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"next_cursor": "LTE=", "data": []}`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv, nil).FetchSamplingMarkets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestTradingClient_GetTradeableBalance(t *testing.T) {
+	const (
+		ctfExchange     = "0x4bfb41d5b3570defd03c39a9a4d8de6bd8b8982e"
+		negRiskExchange = "0xc5d563a36ae78145c45a50134d48a1215220f80a"
+	)
+	// eth_call: balanceOf → 100 USDC; allowance → 80 on the CTF exchange and
+	// unlimited on the NegRisk one.
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+		var msg struct {
+			Data  string `json:"data"`
+			Input string `json:"input"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &msg))
+		data := strings.ToLower(msg.Input + msg.Data)
+
+		amount := new(big.Int)
+		switch {
+		case strings.HasPrefix(data, "0x70a08231"):
+			amount.SetInt64(100_000_000)
+		case strings.HasPrefix(data, "0xdd62ed3e") && strings.Contains(data, ctfExchange[2:]):
+			amount.SetInt64(80_000_000)
+		case strings.HasPrefix(data, "0xdd62ed3e") && strings.Contains(data, negRiskExchange[2:]):
+			amount.Sub(amount.Lsh(big.NewInt(1), 256), big.NewInt(1))
+		default:
+			t.Errorf("unexpected call data %s", data)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0", "id": req.ID, "result": fmt.Sprintf("0x%064x", amount),
+		})
+	}))
+	defer rpc.Close()
+
+	clob := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/data/orders":
+			w.Write([]byte(`{"limit": 100, "count": 3, "next_cursor": "LTE=", "data": [
+				{"id": "b1", "status": "LIVE", "side": "BUY", "original_size": "20", "size_matched": "5", "price": "0.40", "created_at": 1700000000},
+				{"id": "b2", "status": "LIVE", "side": "BUY", "original_size": "10", "size_matched": "0", "price": "0.50", "created_at": 1700000000},
+				{"id": "s1", "status": "LIVE", "side": "SELL", "original_size": "8", "size_matched": "0", "price": "0.30", "created_at": 1700000000}
+			]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer clob.Close()
+
+	pool, err := onchain.DialRPCPool("test-tradeable", rpc.URL)
+	require.NoError(t, err)
+	defer pool.Close()
+	auth, err := polymarket.NewAuthClient(clob.URL, clob.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, pool)
+
+	tb, err := tc.GetTradeableBalance(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, 100, tb.Wallet, 1e-9)
+	assert.InDelta(t, 80, tb.Allowance, 1e-9, "lowest exchange allowance")
+	assert.InDelta(t, 11, tb.LockedInOrders, 1e-9, "15×0.40 + 10×0.50; sells lock no USDC")
+	assert.InDelta(t, 69, tb.Available(), 1e-9)
+}
+
+// openOrdersPage is a GET /data/orders page as the CLOB serves it.
+const openOrdersPage = `{"limit": 100, "count": 2, "next_cursor": "MTAw", "data": [
+	{
+		"id": "0xb816482a5187a3d3db49cbaf6fe3ddf24f53e6c712b5a4bf5e01d0ec7b11dabc",
+		"status": "LIVE",
+		"owner": "f4f247b7-4ac7-ff29-a152-04fda0a8755a",
+		"maker_address": "0x1de0a98c6a9b3bd4cb5f5c2f0ccd25d4d2bdea6c",
+		"market": "0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af",
+		"asset_id": "52114319501245915516055106046884209969926127482827954674443846427813813222426",
+		"side": "BUY",
+		"original_size": "21.5",
+		"size_matched": "6.25",
+		"price": "0.47",
+		"outcome": "Yes",
+		"expiration": "0",
+		"order_type": "GTC",
+		"associate_trades": ["0x3f2a"],
+		"created_at": 1700000000
+	}
+]}`
+
+func TestTradingClient_GetOpenOrdersFromShares(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/data/orders":
+			if r.URL.Query().Get("next_cursor") == "" {
+				w.Write([]byte(openOrdersPage))
+				return
+			}
+			assert.Equal(t, "MTAw", r.URL.Query().Get("next_cursor"))
+			w.Write([]byte(`{"limit": 100, "count": 1, "next_cursor": "LTE=", "data": [
+				{"id": "0xsell", "status": "LIVE", "side": "SELL", "outcome": "No",
+				 "original_size": "8", "size_matched": "0", "price": "0.30", "created_at": 1700000100}
+			]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+
+	orders, err := tc.GetOpenOrders(context.Background())
+	require.NoError(t, err)
+	require.Len(t, orders, 2, "every page is read")
+
+	bid := orders[0]
+	assert.Equal(t, "0xb816482a5187a3d3db49cbaf6fe3ddf24f53e6c712b5a4bf5e01d0ec7b11dabc", bid.CLOBOrderID)
+	assert.Equal(t, "YES", bid.Side)
+	assert.Equal(t, domain.LiveStatusOpen, bid.Status)
+	assert.InDelta(t, 21.5, bid.SizeShares, 1e-9)
+	assert.InDelta(t, 21.5*0.47, bid.Size, 1e-9, "USDC, like our own orders")
+	assert.InDelta(t, 6.25*0.47, bid.FilledSize, 1e-9)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), bid.PlacedAt)
+	assert.Equal(t, "NO", orders[1].Side)
+}
+
+func TestTradingClient_TickSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/derive-api-key":
+			json.NewEncoder(w).Encode(map[string]string{"apiKey": "k", "secret": "c2VjcmV0", "passphrase": "p"})
+		case "/tick-size":
+			if r.URL.Query().Get("token_id") == "fine" {
+				w.Write([]byte(`{"minimum_tick_size": 0.001}`))
+				return
+			}
+			w.Write([]byte(`{"minimum_tick_size": 0}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	auth, err := polymarket.NewAuthClient(srv.URL, srv.URL, testPrivateKey)
+	require.NoError(t, err)
+	tc := polymarket.NewTradingClient(auth, nil)
+
+	tick, err := tc.TickSize(context.Background(), "fine")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.001, tick, 1e-12)
+
+	_, err = tc.TickSize(context.Background(), "missing")
+	assert.Error(t, err, "a zero tick is not trusted")
+}
@@ -77,6 +77,7 @@ type gammaMarket struct {
 	ConditionID  string      `json:"conditionId"`
 	Question     string      `json:"question"`
 	Slug         string      `json:"slug"`
+	Category     string      `json:"category"`
 	EndDateISO   string      `json:"endDateIso"`
 	Volume       json.Number `json:"volume"`
 	Volume24h    json.Number `json:"volume24hr"`
@@ -85,3 +86,24 @@ type gammaMarket struct {
 	Active       bool        `json:"active"`
 	Closed       bool        `json:"closed"`
 }
+
+// gammaHistoricalMarket es un mercado de GET /markets con los campos que el
+// backtest histórico necesita: tokens, precios guardados y rewards.
+// clobTokenIds y outcomes llegan como arrays JSON codificados en un string.
+type gammaHistoricalMarket struct {
+	gammaMarket
+	StartDateISO     string            `json:"startDateIso"`
+	ClobTokenIDs     string            `json:"clobTokenIds"`
+	Outcomes         string            `json:"outcomes"`
+	BestBid          json.Number       `json:"bestBid"`
+	BestAsk          json.Number       `json:"bestAsk"`
+	LiquidityClob    json.Number       `json:"liquidityClob"`
+	RewardsMinSize   json.Number       `json:"rewardsMinSize"`
+	RewardsMaxSpread json.Number       `json:"rewardsMaxSpread"`
+	ClobRewards      []gammaClobReward `json:"clobRewards"`
+}
+
+// gammaClobReward es un programa de rewards de un mercado en Gamma.
+type gammaClobReward struct {
+	RewardsDailyRate json.Number `json:"rewardsDailyRate"`
+}
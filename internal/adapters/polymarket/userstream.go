@@ -0,0 +1,217 @@
+package polymarket
+
+// userstream.go — Authenticated CLOB user channel (WebSocket).
+//
+// Pushes order and trade events for this wallet in real time so the live
+// engine can record fills as they happen instead of inferring them from
+// orders that disappear between polls.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+const (
+	defaultUserStreamURL = "wss://ws-subscriptions-clob.polymarket.com/ws/user"
+
+	userStreamPingInterval = 10 * time.Second
+	userStreamMaxBackoff   = 60 * time.Second
+	userStreamBuffer       = 256
+)
+
+type userSubscribe struct {
+	Auth    apiCredentials `json:"auth"`
+	Markets []string       `json:"markets"`
+	Type    string         `json:"type"`
+}
+
+// userMessage covers both "trade" and "order" events of the user channel.
+type userMessage struct {
+	EventType    string           `json:"event_type"`
+	ID           string           `json:"id"`
+	Type         string           `json:"type"`   // order: PLACEMENT | UPDATE | CANCELLATION
+	Status       string           `json:"status"` // trade: MATCHED | MINED | CONFIRMED | FAILED
+	TakerOrderID string           `json:"taker_order_id"`
+	Price        string           `json:"price"`
+	Size         string           `json:"size"`
+	Timestamp    string           `json:"timestamp"`
+	MakerOrders  []userMakerOrder `json:"maker_orders"`
+}
+
+type userMakerOrder struct {
+	OrderID       string `json:"order_id"`
+	MatchedAmount string `json:"matched_amount"`
+	Price         string `json:"price"`
+}
+
+// UserStream streams order events for the authenticated wallet.
+type UserStream struct {
+	auth   *AuthClient
+	url    string
+	events chan domain.LiveOrderEvent
+}
+
+// NewUserStream creates a user channel stream. Call Run to connect.
+func NewUserStream(auth *AuthClient) *UserStream {
+	return &UserStream{
+		auth:   auth,
+		url:    defaultUserStreamURL,
+		events: make(chan domain.LiveOrderEvent, userStreamBuffer),
+	}
+}
+
+// Events returns the channel of parsed order events. It is closed when Run returns.
+func (us *UserStream) Events() <-chan domain.LiveOrderEvent {
+	return us.events
+}
+
+// Run connects to the user channel and keeps the connection alive,
+// reconnecting with exponential backoff until ctx is cancelled.
+func (us *UserStream) Run(ctx context.Context) error {
+	defer close(us.events)
+
+	if err := us.auth.EnsureCreds(ctx); err != nil {
+		return fmt.Errorf("user stream: creds: %w", err)
+	}
+
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := us.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if time.Since(start) > userStreamMaxBackoff {
+			backoff = time.Second
+		}
+		slog.Warn("user stream: disconnected, reconnecting", "err", err, "wait", backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, userStreamMaxBackoff)
+	}
+}
+
+// runOnce holds a single connection until it fails or ctx is cancelled.
+func (us *UserStream) runOnce(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, us.url, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	sub := userSubscribe{Auth: *us.auth.creds, Markets: []string{}, Type: "user"}
+	if err := conn.WriteJSON(sub); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	slog.Info("user stream: connected")
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(userStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				_ = conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteMessage(websocket.TextMessage, []byte("PING")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+		for _, ev := range parseUserMessages(data) {
+			select {
+			case us.events <- ev:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// parseUserMessages decodes a user channel frame (single object or array)
+// into order events. Trades are only emitted once, on MATCHED, so later
+// MINED/CONFIRMED updates do not double-count fills.
+func parseUserMessages(data []byte) []domain.LiveOrderEvent {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "PONG" {
+		return nil
+	}
+
+	var msgs []userMessage
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			slog.Debug("user stream: bad frame", "err", err)
+			return nil
+		}
+	} else {
+		var m userMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			slog.Debug("user stream: bad frame", "err", err)
+			return nil
+		}
+		msgs = []userMessage{m}
+	}
+
+	var events []domain.LiveOrderEvent
+	for _, m := range msgs {
+		ts := parseTimestamp(m.Timestamp)
+		if ts.IsZero() {
+			ts = time.Now().UTC()
+		}
+
+		switch m.EventType {
+		case "trade":
+			if !strings.EqualFold(m.Status, "MATCHED") {
+				continue
+			}
+			// Our resting bids show up as maker orders of someone else's trade.
+			for _, mo := range m.MakerOrders {
+				price := parseFloat(mo.Price)
+				shares := parseFloat(mo.MatchedAmount)
+				events = append(events, domain.LiveOrderEvent{
+					Type:        domain.LiveEventFill,
+					CLOBOrderID: mo.OrderID,
+					CLOBTradeID: m.ID,
+					Price:       price,
+					Size:        shares * price,
+					Timestamp:   ts,
+				})
+			}
+		case "order":
+			if strings.EqualFold(m.Type, "CANCELLATION") {
+				events = append(events, domain.LiveOrderEvent{
+					Type:        domain.LiveEventCancel,
+					CLOBOrderID: m.ID,
+					Timestamp:   ts,
+				})
+			}
+		}
+	}
+	return events
+}
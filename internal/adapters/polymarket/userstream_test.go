@@ -0,0 +1,69 @@
+package polymarket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/polymarket"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestParseUserMessages_MatchedTradeFillsEachMakerOrder(t *testing.T) {
+	events := polymarket.ParseUserMessages([]byte(`{
+		"event_type": "trade", "id": "trade-1", "status": "MATCHED", "timestamp": "1700000000",
+		"taker_order_id": "their-order",
+		"maker_orders": [
+			{"order_id": "ours-yes", "matched_amount": "10", "price": "0.40"},
+			{"order_id": "ours-no", "matched_amount": "5", "price": "0.55"}
+		]
+	}`))
+
+	require.Len(t, events, 2, "one fill per maker order, never the taker's")
+	assert.Equal(t, domain.LiveOrderEvent{
+		Type:        domain.LiveEventFill,
+		CLOBOrderID: "ours-yes",
+		CLOBTradeID: "trade-1",
+		Price:       0.40,
+		Size:        4.0,
+		Timestamp:   time.Unix(1700000000, 0).UTC(),
+	}, events[0])
+	assert.Equal(t, "ours-no", events[1].CLOBOrderID)
+	assert.InDelta(t, 2.75, events[1].Size, 1e-9, "size is USDC: shares × price")
+}
+
+func TestParseUserMessages_OnlyMatchedTradesCount(t *testing.T) {
+	for _, status := range []string{"MINED", "CONFIRMED", "FAILED"} {
+		events := polymarket.ParseUserMessages([]byte(`{"event_type": "trade", "id": "trade-1", "status": "` + status + `",
+			"maker_orders": [{"order_id": "ours", "matched_amount": "10", "price": "0.40"}]}`))
+		assert.Empty(t, events, "%s repeats a trade already counted on MATCHED", status)
+	}
+}
+
+func TestParseUserMessages_Orders(t *testing.T) {
+	events := polymarket.ParseUserMessages([]byte(`[
+		{"event_type": "order", "id": "placed", "type": "PLACEMENT"},
+		{"event_type": "order", "id": "updated", "type": "UPDATE"},
+		{"event_type": "order", "id": "gone", "type": "CANCELLATION", "timestamp": "1700000000000"}
+	]`))
+
+	require.Len(t, events, 1, "placements and updates carry no state the trades do not")
+	assert.Equal(t, domain.LiveEventCancel, events[0].Type)
+	assert.Equal(t, "gone", events[0].CLOBOrderID)
+	assert.Equal(t, time.UnixMilli(1700000000000).UTC(), events[0].Timestamp)
+}
+
+func TestParseUserMessages_IgnoresNoise(t *testing.T) {
+	for _, frame := range []string{"", "PONG", "  PONG\n", "{not json", `[{"event_type": "trade"`, `{"event_type": "last_trade_price"}`} {
+		assert.Empty(t, polymarket.ParseUserMessages([]byte(frame)), "frame %q", frame)
+	}
+}
+
+func TestParseUserMessages_MissingTimestampUsesNow(t *testing.T) {
+	before := time.Now().UTC()
+	events := polymarket.ParseUserMessages([]byte(`{"event_type": "order", "id": "gone", "type": "cancellation"}`))
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Timestamp.Before(before))
+}
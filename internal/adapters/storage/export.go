@@ -0,0 +1,432 @@
+package storage
+
+// export.go — Paper and live history export for offline analysis.
+//
+// Each exported table has a fixed column list, so files keep the same shape
+// when the internal schema grows. Rows are streamed from the database to the
+// file one at a time. Timestamps are written as RFC3339 in UTC, and a
+// manifest.json next to the files records the export schema version and the
+// migrations applied to the database.
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportSchemaVersion is bumped whenever an exported column is renamed,
+// removed or changes meaning. Adding columns at the end does not bump it.
+const ExportSchemaVersion = 1
+
+// Export formats accepted by ExportHistory.
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+)
+
+// exportTable describes how a table is exported. timeColumn is the column
+// the from/to filter applies to; times lists the columns written as RFC3339.
+type exportTable struct {
+	name       string
+	timeColumn string
+	columns    []string
+	times      []string
+}
+
+var exportTables = []exportTable{
+	{
+		name:       "paper_orders",
+		timeColumn: "placed_at",
+		columns: []string{"id", "condition_id", "token_id", "side", "bid_price", "size", "filled_size",
+			"pair_id", "placed_at", "status", "filled_at", "filled_price", "question", "queue_ahead",
+			"daily_reward", "end_date", "merged_at", "close_reason", "closed_at", "level_depth", "size_dominant"},
+		times: []string{"placed_at", "filled_at", "end_date", "merged_at", "closed_at"},
+	},
+	{
+		name:       "paper_fills",
+		timeColumn: "timestamp",
+		columns:    []string{"id", "order_id", "trade_id", "price", "size", "timestamp"},
+		times:      []string{"timestamp"},
+	},
+	{
+		name:       "paper_daily",
+		timeColumn: "date",
+		columns: []string{"date", "active_positions", "complete_pairs", "partial_fills", "total_reward",
+			"total_fill_pnl", "net_pnl", "avg_partial_mins", "fills_yes", "fills_no", "orders_placed",
+			"capital_deployed", "markets_resolved", "resolution_pnl", "rotations", "merge_profit",
+			"compound_balance"},
+		times: []string{"date"},
+	},
+	{
+		name:       "live_orders",
+		timeColumn: "placed_at",
+		columns: []string{"id", "clob_order_id", "condition_id", "token_id", "side", "order_side",
+			"bid_price", "size", "size_shares", "filled_size", "merged_size", "pair_id", "placed_at", "status",
+			"filled_at", "filled_price", "question", "queue_ahead", "daily_reward", "end_date",
+			"merged_at", "expires_at", "neg_risk", "competition_at", "realized_pnl", "wallet_address",
+			"shadow", "placement_key", "remainder", "close_reason", "closed_at"},
+		times: []string{"placed_at", "filled_at", "end_date", "merged_at", "expires_at", "closed_at"},
+	},
+	{
+		name:       "live_fills",
+		timeColumn: "timestamp",
+		columns:    []string{"id", "order_id", "clob_trade_id", "price", "size", "timestamp"},
+		times:      []string{"timestamp"},
+	},
+	{
+		name:       "live_merges",
+		timeColumn: "executed_at",
+		columns: []string{"id", "condition_id", "pair_id", "tx_hash", "gas_used_pol", "gas_cost_usd",
+			"usdc_received", "spread_profit", "success", "error", "executed_at", "shadow"},
+		times: []string{"executed_at"},
+	},
+	{
+		name:       "live_redemptions",
+		timeColumn: "redeemed_at",
+		columns: []string{"id", "condition_id", "pair_id", "tx_hash", "yes_shares", "no_shares", "payout",
+			"cost_basis", "gas_cost_usd", "realized_pnl", "redeemed_at", "shadow"},
+		times: []string{"redeemed_at"},
+	},
+	{
+		name:       "live_daily",
+		timeColumn: "date",
+		columns: []string{"date", "active_positions", "complete_pairs", "partial_fills", "total_reward",
+			"total_fill_pnl", "net_pnl", "avg_partial_mins", "fills_yes", "fills_no", "orders_placed",
+			"orders_cancelled", "capital_deployed", "merges", "merge_profit", "gas_cost_usd",
+			"compound_balance", "rotations", "gas_used_pol", "pol_balance", "stranded_shares",
+			"stranded_usdc"},
+		times: []string{"date"},
+	},
+}
+
+// ExportTables returns the names of the exportable tables, in export order.
+func ExportTables() []string {
+	names := make([]string, len(exportTables))
+	for i, t := range exportTables {
+		names[i] = t.name
+	}
+	return names
+}
+
+// ExportColumns returns the exported columns of table, in file order.
+func ExportColumns(table string) ([]string, error) {
+	t, err := findExportTable(table)
+	if err != nil {
+		return nil, err
+	}
+	return t.columns, nil
+}
+
+func findExportTable(name string) (exportTable, error) {
+	for _, t := range exportTables {
+		if t.name == name {
+			return t, nil
+		}
+	}
+	return exportTable{}, fmt.Errorf("storage: unknown export table %q", name)
+}
+
+// ExportRows streams the rows of table whose time column falls in [from, to),
+// in ExportColumns order. A zero from or to leaves that side open. Values are
+// string, int64, float64 or nil (NULL); timestamps are RFC3339 strings in UTC.
+// Iteration stops at the first error, which is yielded with a nil row.
+func (s *SQLiteStorage) ExportRows(ctx context.Context, table string, from, to time.Time) iter.Seq2[[]any, error] {
+	return func(yield func([]any, error) bool) {
+		t, err := findExportTable(table)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT `+strings.Join(t.columns, ", ")+` FROM `+t.name+` ORDER BY rowid`)
+		if err != nil {
+			yield(nil, fmt.Errorf("storage.ExportRows: %s: %w", table, err))
+			return
+		}
+		defer rows.Close()
+
+		isTime := make([]bool, len(t.columns))
+		timeIdx := -1
+		for i, c := range t.columns {
+			for _, tc := range t.times {
+				isTime[i] = isTime[i] || c == tc
+			}
+			if c == t.timeColumn {
+				timeIdx = i
+			}
+		}
+
+		for rows.Next() {
+			vals := make([]any, len(t.columns))
+			ptrs := make([]any, len(vals))
+			for i := range vals {
+				ptrs[i] = &vals[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				yield(nil, fmt.Errorf("storage.ExportRows: %s: %w", table, err))
+				return
+			}
+
+			if at, ok := exportTime(vals[timeIdx]); ok {
+				if (!from.IsZero() && at.Before(from)) || (!to.IsZero() && !at.Before(to)) {
+					continue
+				}
+			}
+			for i, v := range vals {
+				vals[i] = exportValue(v, isTime[i])
+			}
+			if !yield(vals, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("storage.ExportRows: %s: %w", table, err))
+		}
+	}
+}
+
+// exportTime reads a stored timestamp: the driver returns time.Time for
+// DATETIME/DATE columns it can parse, and the raw text otherwise.
+func exportTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC(), true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", time.DateOnly} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed.UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func exportValue(v any, isTime bool) any {
+	if isTime {
+		if t, ok := exportTime(v); ok {
+			return t.Format(time.RFC3339)
+		}
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+// ExportManifest describes an export directory. It is written as manifest.json.
+type ExportManifest struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Format        string              `json:"format"`
+	ExportedAt    string              `json:"exported_at"`
+	From          string              `json:"from,omitempty"`
+	To            string              `json:"to,omitempty"`
+	Migrations    []string            `json:"migrations"`
+	Tables        []ExportedTableInfo `json:"tables"`
+}
+
+// ExportedTableInfo is one table of an ExportManifest.
+type ExportedTableInfo struct {
+	Name       string   `json:"name"`
+	File       string   `json:"file"`
+	Rows       int      `json:"rows"`
+	TimeColumn string   `json:"time_column"`
+	Columns    []string `json:"columns"`
+}
+
+// ExportHistory writes every exportable table present in the database to
+// dir as <table>.csv or <table>.json, plus manifest.json. Tables whose schema
+// was never applied (e.g. no paper trading yet) are skipped. Existing files
+// are overwritten.
+func (s *SQLiteStorage) ExportHistory(ctx context.Context, dir, format string, from, to time.Time) (ExportManifest, error) {
+	manifest := ExportManifest{
+		SchemaVersion: ExportSchemaVersion,
+		Format:        format,
+		ExportedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if !from.IsZero() {
+		manifest.From = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		manifest.To = to.UTC().Format(time.RFC3339)
+	}
+	if format != ExportCSV && format != ExportJSON {
+		return manifest, fmt.Errorf("storage.ExportHistory: unsupported format %q (use csv or json)", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return manifest, fmt.Errorf("storage.ExportHistory: %w", err)
+	}
+
+	migrations, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return manifest, fmt.Errorf("storage.ExportHistory: %w", err)
+	}
+	manifest.Migrations = migrations
+
+	for _, t := range exportTables {
+		exists, err := s.tableExists(ctx, t.name)
+		if err != nil {
+			return manifest, fmt.Errorf("storage.ExportHistory: %w", err)
+		}
+		if !exists {
+			continue
+		}
+
+		file := t.name + "." + format
+		n, err := s.exportTableFile(ctx, t, filepath.Join(dir, file), format, from, to)
+		if err != nil {
+			return manifest, fmt.Errorf("storage.ExportHistory: %s: %w", t.name, err)
+		}
+		manifest.Tables = append(manifest.Tables, ExportedTableInfo{
+			Name:       t.name,
+			File:       file,
+			Rows:       n,
+			TimeColumn: t.timeColumn,
+			Columns:    t.columns,
+		})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("storage.ExportHistory: manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), append(data, '\n'), 0o644); err != nil {
+		return manifest, fmt.Errorf("storage.ExportHistory: manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportTableFile streams one table into path and returns the rows written.
+func (s *SQLiteStorage) exportTableFile(ctx context.Context, t exportTable, path, format string, from, to time.Time) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+
+	var write func([]any) error
+	var finish func() error
+	if format == ExportCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.columns); err != nil {
+			f.Close()
+			return 0, err
+		}
+		record := make([]string, len(t.columns))
+		write = func(row []any) error {
+			for i, v := range row {
+				record[i] = csvField(v)
+			}
+			return cw.Write(record)
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		// A JSON array written element by element, keys in column order.
+		first := true
+		w.WriteString("[")
+		write = func(row []any) error {
+			if !first {
+				w.WriteString(",")
+			}
+			first = false
+			w.WriteString("\n  {")
+			for i, v := range row {
+				if i > 0 {
+					w.WriteString(", ")
+				}
+				key, _ := json.Marshal(t.columns[i])
+				val, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				w.Write(key)
+				w.WriteString(": ")
+				w.Write(val)
+			}
+			_, err := w.WriteString("}")
+			return err
+		}
+		finish = func() error {
+			_, err := w.WriteString("\n]\n")
+			return err
+		}
+	}
+
+	n := 0
+	for row, err := range s.ExportRows(ctx, t.name, from, to) {
+		if err == nil {
+			err = write(row)
+		}
+		if err != nil {
+			f.Close()
+			return n, err
+		}
+		n++
+	}
+	if err := finish(); err != nil {
+		f.Close()
+		return n, err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return n, err
+	}
+	return n, f.Close()
+}
+
+func csvField(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func (s *SQLiteStorage) tableExists(ctx context.Context, name string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?`, name).Scan(&n)
+	return n > 0, err
+}
+
+// appliedMigrations returns the IDs recorded in schema_versions, or none if
+// no schema with migrations was ever applied.
+func (s *SQLiteStorage) appliedMigrations(ctx context.Context) ([]string, error) {
+	exists, err := s.tableExists(ctx, "schema_versions")
+	if err != nil || !exists {
+		return []string{}, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM schema_versions ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,93 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHistory_CSVFiltersByDate(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	old := makeLiveOrder("old", "p0", "YES", domain.LiveStatusFilled)
+	old.PlacedAt = day.Add(-time.Hour)
+	recent := makeLiveOrder("new", "p1", "NO", domain.LiveStatusOpen)
+	recent.PlacedAt = day.Add(90 * time.Minute)
+	require.NoError(t, db.SaveLiveOrder(ctx, old))
+	require.NoError(t, db.SaveLiveOrder(ctx, recent))
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: day, NetPnL: 1.5}))
+
+	dir := t.TempDir()
+	manifest, err := db.ExportHistory(ctx, dir, storage.ExportCSV, day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, storage.ExportSchemaVersion, manifest.SchemaVersion)
+	assert.Contains(t, manifest.Migrations, "live_007_orders_merged_size")
+	names := make(map[string]int)
+	for _, tbl := range manifest.Tables {
+		names[tbl.Name] = tbl.Rows
+	}
+	assert.NotContains(t, names, "paper_orders", "paper schema was never applied")
+	assert.Equal(t, 1, names["live_orders"])
+	assert.Equal(t, 1, names["live_daily"])
+	assert.Equal(t, 0, names["live_merges"])
+
+	f, err := os.Open(filepath.Join(dir, "live_orders.csv"))
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	cols, err := storage.ExportColumns("live_orders")
+	require.NoError(t, err)
+	assert.Equal(t, cols, records[0])
+
+	row := make(map[string]string)
+	for i, c := range records[0] {
+		row[c] = records[1][i]
+	}
+	assert.Equal(t, "new", row["id"])
+	assert.Equal(t, "2026-03-02T01:30:00Z", row["placed_at"])
+	assert.Empty(t, row["filled_at"], "NULL exports as an empty field")
+
+	_, err = os.Stat(filepath.Join(dir, "manifest.json"))
+	assert.NoError(t, err)
+}
+
+func TestExportHistory_JSONKeepsColumnOrder(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Merges: 2}))
+
+	dir := t.TempDir()
+	_, err := db.ExportHistory(ctx, dir, storage.ExportJSON, time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "live_daily.json"))
+	require.NoError(t, err)
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal(data, &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "2026-03-01T00:00:00Z", rows[0]["date"])
+	assert.EqualValues(t, 2, rows[0]["merges"])
+
+	data, err = os.ReadFile(filepath.Join(dir, "paper_orders.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &rows))
+	assert.Empty(t, rows)
+
+	_, err = db.ExportHistory(ctx, dir, "parquet", time.Time{}, time.Time{})
+	assert.Error(t, err)
+}
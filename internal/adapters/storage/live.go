@@ -7,12 +7,21 @@ package storage
 //   live_fills          — detected fill events
 //   live_merges         — completed on-chain merge transactions
 //   live_daily          — daily P&L summary
-//   live_circuit_breaker— circuit breaker state
+//   live_daily_shadow   — daily P&L summary of shadow (dry-run) mode
+//   live_circuit_breaker— circuit breaker state (row 1 = real, row 2 = shadow)
+//   live_cooldowns      — per-market re-entry cooldowns after rotation
+//   live_rewards        — liquidity rewards actually paid, per day and market
+//   live_redemptions    — tokens of resolved markets redeemed on-chain
+//
+// Shadow mode writes to the same order and merge tables with shadow=1. A
+// storage obtained from ShadowLive only sees shadow rows; the regular one only
+// sees real rows, so the two never mix in engine state or stats.
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
@@ -39,10 +48,15 @@ CREATE TABLE IF NOT EXISTS live_orders (
     end_date        DATETIME,
     merged_at       DATETIME,
     neg_risk        INTEGER NOT NULL DEFAULT 0,
-    competition_at  REAL NOT NULL DEFAULT 0
+    competition_at  REAL NOT NULL DEFAULT 0,
+    order_side      TEXT NOT NULL DEFAULT 'BUY', -- BUY (entry) | SELL (unwind)
+    realized_pnl    REAL NOT NULL DEFAULT 0,
+    wallet_address  TEXT NOT NULL DEFAULT '', -- funding wallet ('' = primary)
+    shadow          INTEGER NOT NULL DEFAULT 0 -- 1 = dry-run order, never sent to the CLOB
 );
 
 CREATE INDEX IF NOT EXISTS live_orders_status ON live_orders(status);
+CREATE INDEX IF NOT EXISTS live_orders_clob ON live_orders(clob_order_id);
 CREATE INDEX IF NOT EXISTS live_orders_condition ON live_orders(condition_id);
 CREATE INDEX IF NOT EXISTS live_orders_pair ON live_orders(pair_id);
 
@@ -54,6 +68,7 @@ CREATE TABLE IF NOT EXISTS live_fills (
     size            REAL NOT NULL,
     timestamp       DATETIME NOT NULL
 );
+CREATE INDEX IF NOT EXISTS live_fills_order ON live_fills(order_id);
 
 CREATE TABLE IF NOT EXISTS live_merges (
     id              INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -66,10 +81,61 @@ CREATE TABLE IF NOT EXISTS live_merges (
     spread_profit   REAL NOT NULL DEFAULT 0,
     success         INTEGER NOT NULL DEFAULT 0,
     error           TEXT,
-    executed_at     DATETIME NOT NULL
+    executed_at     DATETIME NOT NULL,
+    shadow          INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS live_circuit_breaker (
+    id                  INTEGER PRIMARY KEY DEFAULT 1,
+    consecutive_losses  INTEGER NOT NULL DEFAULT 0,
+    max_losses          INTEGER NOT NULL DEFAULT 3,
+    cooldown_until      DATETIME,
+    cooldown_duration_s INTEGER NOT NULL DEFAULT 1800,
+    total_pnl           REAL NOT NULL DEFAULT 0,
+    max_drawdown        REAL NOT NULL DEFAULT -50,
+    triggered           INTEGER NOT NULL DEFAULT 0,
+    triggered_reason    TEXT
+);
+
+CREATE TABLE IF NOT EXISTS live_cooldowns (
+    condition_id    TEXT NOT NULL,
+    shadow          INTEGER NOT NULL DEFAULT 0,
+    until           DATETIME NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (condition_id, shadow)
 );
 
-CREATE TABLE IF NOT EXISTS live_daily (
+CREATE TABLE IF NOT EXISTS live_rewards (
+    date            DATE NOT NULL,
+    condition_id    TEXT NOT NULL,
+    earnings        REAL NOT NULL DEFAULT 0,
+    synced_at       DATETIME NOT NULL,
+    PRIMARY KEY (date, condition_id)
+);
+
+CREATE TABLE IF NOT EXISTS live_redemptions (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    condition_id    TEXT NOT NULL,
+    pair_id         TEXT NOT NULL DEFAULT '',
+    tx_hash         TEXT NOT NULL,
+    yes_shares      REAL NOT NULL DEFAULT 0,
+    no_shares       REAL NOT NULL DEFAULT 0,
+    payout          REAL NOT NULL DEFAULT 0,
+    cost_basis      REAL NOT NULL DEFAULT 0,
+    gas_cost_usd    REAL NOT NULL DEFAULT 0,
+    realized_pnl    REAL NOT NULL DEFAULT 0,
+    redeemed_at     DATETIME NOT NULL,
+    shadow          INTEGER NOT NULL DEFAULT 0
+);
+
+-- Ensure exactly one row per mode in circuit_breaker
+INSERT OR IGNORE INTO live_circuit_breaker (id) VALUES (1);
+INSERT OR IGNORE INTO live_circuit_breaker (id) VALUES (2);
+`
+
+// liveDailyDDL is shared by live_daily and live_daily_shadow.
+const liveDailyDDL = `
+CREATE TABLE IF NOT EXISTS %s (
     date                DATE PRIMARY KEY,
     active_positions    INTEGER NOT NULL DEFAULT 0,
     complete_pairs      INTEGER NOT NULL DEFAULT 0,
@@ -87,34 +153,87 @@ CREATE TABLE IF NOT EXISTS live_daily (
     merge_profit        REAL NOT NULL DEFAULT 0,
     gas_cost_usd        REAL NOT NULL DEFAULT 0,
     compound_balance    REAL NOT NULL DEFAULT 0,
-    rotations           INTEGER NOT NULL DEFAULT 0
+    rotations           INTEGER NOT NULL DEFAULT 0,
+    gas_used_pol        REAL NOT NULL DEFAULT 0,
+    pol_balance         REAL NOT NULL DEFAULT 0,
+    stranded_shares     REAL NOT NULL DEFAULT 0,
+    stranded_usdc       REAL NOT NULL DEFAULT 0
 );
-
-CREATE TABLE IF NOT EXISTS live_circuit_breaker (
-    id                  INTEGER PRIMARY KEY DEFAULT 1,
-    consecutive_losses  INTEGER NOT NULL DEFAULT 0,
-    max_losses          INTEGER NOT NULL DEFAULT 3,
-    cooldown_until      DATETIME,
-    cooldown_duration_s INTEGER NOT NULL DEFAULT 1800,
-    total_pnl           REAL NOT NULL DEFAULT 0,
-    max_drawdown        REAL NOT NULL DEFAULT -50,
-    triggered           INTEGER NOT NULL DEFAULT 0,
-    triggered_reason    TEXT
-);
-
--- Ensure exactly one row in circuit_breaker
-INSERT OR IGNORE INTO live_circuit_breaker (id) VALUES (1);
 `
 
+// liveMigrations are the schema changes made after liveSchema, in order.
+var liveMigrations = []Migration{
+	addColumn("live_001_orders_order_side", "live_orders", "order_side", "TEXT NOT NULL DEFAULT 'BUY'"),
+	addColumn("live_002_orders_realized_pnl", "live_orders", "realized_pnl", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_003_orders_wallet_address", "live_orders", "wallet_address", "TEXT NOT NULL DEFAULT ''"),
+	addColumn("live_004_orders_shadow", "live_orders", "shadow", "INTEGER NOT NULL DEFAULT 0"),
+	addColumn("live_005_merges_shadow", "live_merges", "shadow", "INTEGER NOT NULL DEFAULT 0"),
+	addColumn("live_006_orders_expires_at", "live_orders", "expires_at", "DATETIME"),
+	addColumn("live_007_orders_merged_size", "live_orders", "merged_size", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_008_orders_placement_key", "live_orders", "placement_key", "TEXT NOT NULL DEFAULT ''"),
+	addColumn("live_009_daily_gas_used_pol", "live_daily", "gas_used_pol", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_010_daily_pol_balance", "live_daily", "pol_balance", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_011_daily_shadow_gas_used_pol", "live_daily_shadow", "gas_used_pol", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_012_daily_shadow_pol_balance", "live_daily_shadow", "pol_balance", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_013_orders_remainder", "live_orders", "remainder", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_014_daily_stranded_shares", "live_daily", "stranded_shares", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_015_daily_stranded_usdc", "live_daily", "stranded_usdc", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_016_daily_shadow_stranded_shares", "live_daily_shadow", "stranded_shares", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_017_daily_shadow_stranded_usdc", "live_daily_shadow", "stranded_usdc", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_018_orders_size_shares", "live_orders", "size_shares", "REAL NOT NULL DEFAULT 0"),
+	addColumn("live_019_orders_close_reason", "live_orders", "close_reason", "TEXT"),
+	addColumn("live_020_orders_closed_at", "live_orders", "closed_at", "DATETIME"),
+}
+
 // ApplyLiveSchema creates the live trading tables if they don't exist.
 func (s *SQLiteStorage) ApplyLiveSchema(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, liveSchema)
 	if err != nil {
 		return fmt.Errorf("live schema: %w", err)
 	}
+	for _, table := range []string{"live_daily", "live_daily_shadow"} {
+		if _, err := s.db.ExecContext(ctx, fmt.Sprintf(liveDailyDDL, table)); err != nil {
+			return fmt.Errorf("live schema: %s: %w", table, err)
+		}
+	}
+	if err := NewMigrator(s.db, liveMigrations).Apply(ctx); err != nil {
+		return fmt.Errorf("live schema: %w", err)
+	}
 	return nil
 }
 
+// ShadowLive returns a storage over the same database whose live methods read
+// and write shadow (dry-run) rows only. Use it for the live engine in shadow
+// mode and to report shadow stats. Closing either storage closes both.
+func (s *SQLiteStorage) ShadowLive() *SQLiteStorage {
+	return &SQLiteStorage{
+		db:     s.db,
+		cache:  make(map[string]cachedState),
+		shadow: true,
+	}
+}
+
+// shadowFlag is the value of the shadow column this storage reads and writes.
+func (s *SQLiteStorage) shadowFlag() int {
+	return boolToInt(s.shadow)
+}
+
+// breakerRow is the live_circuit_breaker row of this storage's mode.
+func (s *SQLiteStorage) breakerRow() int {
+	if s.shadow {
+		return 2
+	}
+	return 1
+}
+
+// liveDailyTable is the daily summary table of this storage's mode.
+func (s *SQLiteStorage) liveDailyTable() string {
+	if s.shadow {
+		return "live_daily_shadow"
+	}
+	return "live_daily"
+}
+
 // ─── Orders ──────────────────────────────────────────────────────────────────
 
 // SaveLiveOrder inserts a new live order.
@@ -123,12 +242,16 @@ func (s *SQLiteStorage) SaveLiveOrder(ctx context.Context, o domain.LiveOrder) e
 		INSERT OR REPLACE INTO live_orders
 		  (id, clob_order_id, condition_id, token_id, side, bid_price, size, filled_size,
 		   pair_id, placed_at, status, filled_at, filled_price, question,
-		   queue_ahead, daily_reward, end_date, merged_at, neg_risk, competition_at)
-		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		   queue_ahead, daily_reward, end_date, merged_at, neg_risk, competition_at,
+		   order_side, realized_pnl, wallet_address, shadow, expires_at, merged_size, placement_key,
+		   remainder, size_shares, close_reason, closed_at)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
 		o.ID, o.CLOBOrderID, o.ConditionID, o.TokenID, o.Side, o.BidPrice, o.Size, o.FilledSize,
 		o.PairID, o.PlacedAt.UTC(), string(o.Status), nullTime(o.FilledAt), o.FilledPrice, o.Question,
 		o.QueueAhead, o.DailyReward, nullTimeVal(o.EndDate), nullTime(o.MergedAt),
-		boolToInt(o.NegRisk), o.CompetitionAt,
+		boolToInt(o.NegRisk), o.CompetitionAt, orderSideOrBuy(o.OrderSide), o.RealizedPnL,
+		o.WalletAddress, boolToInt(o.Shadow || s.shadow), nullTimeVal(o.ExpiresAt), o.MergedSize,
+		o.PlacementKey, o.Remainder, o.SizeShares, nullCloseReason(o.CloseReason), nullTime(o.ClosedAt),
 	)
 	return err
 }
@@ -148,6 +271,47 @@ func (s *SQLiteStorage) UpdateLiveOrderFill(ctx context.Context, localID string,
 	return err
 }
 
+// RetireLiveOrder sets a terminal status on an order the engine cancelled or
+// unwound, recording why and when.
+func (s *SQLiteStorage) RetireLiveOrder(ctx context.Context, localID string, status domain.LiveOrderStatus, reason domain.CloseReason) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE live_orders SET status=?, close_reason=?, closed_at=? WHERE id=?`,
+		string(status), string(reason), time.Now().UTC(), localID)
+	return err
+}
+
+// CloseLiveOrder sets a terminal status and the realized P&L of an order.
+func (s *SQLiteStorage) CloseLiveOrder(ctx context.Context, localID string, status domain.LiveOrderStatus, realizedPnL float64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE live_orders SET status=?, realized_pnl=? WHERE id=?`, string(status), realizedPnL, localID)
+	return err
+}
+
+// GetRealizedPnL returns the total realized P&L of closed (unwound) orders.
+func (s *SQLiteStorage) GetRealizedPnL(ctx context.Context) (float64, error) {
+	var total float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(realized_pnl), 0) FROM live_orders WHERE shadow=?`, s.shadowFlag()).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("storage.GetRealizedPnL: %w", err)
+	}
+	return total, nil
+}
+
+// GetRealizedPnLSince returns the realized P&L of unwinds closed since the
+// given time, dated by the SELL's fill (or placement when it has none).
+func (s *SQLiteStorage) GetRealizedPnLSince(ctx context.Context, since time.Time) (float64, error) {
+	var total float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(realized_pnl), 0) FROM live_orders
+		 WHERE shadow=? AND realized_pnl != 0 AND COALESCE(filled_at, placed_at) >= ?`,
+		s.shadowFlag(), since.UTC()).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("storage.GetRealizedPnLSince: %w", err)
+	}
+	return total, nil
+}
+
 // UpdateLiveOrderQueue updates the queue_ahead estimate.
 func (s *SQLiteStorage) UpdateLiveOrderQueue(ctx context.Context, localID string, queueAhead float64) error {
 	_, err := s.db.ExecContext(ctx,
@@ -163,6 +327,22 @@ func (s *SQLiteStorage) MarkLiveOrderMerged(ctx context.Context, localID string,
 	return err
 }
 
+// AddLiveOrderMerged adds USDC of an order's fill to what has already been
+// merged, after a partial merge of its pair.
+func (s *SQLiteStorage) AddLiveOrderMerged(ctx context.Context, localID string, mergedSize float64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE live_orders SET merged_size = merged_size + ? WHERE id=?`, mergedSize, localID)
+	return err
+}
+
+// SetLiveOrderRemainder records the shares of a filled leg left unhedged
+// when the rest of its pair merged.
+func (s *SQLiteStorage) SetLiveOrderRemainder(ctx context.Context, localID string, shares float64) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE live_orders SET remainder=? WHERE id=?`, shares, localID)
+	return err
+}
+
 // GetOpenLiveOrders returns all OPEN and PARTIAL live orders.
 func (s *SQLiteStorage) GetOpenLiveOrders(ctx context.Context) ([]domain.LiveOrder, error) {
 	return s.queryLiveOrders(ctx, `WHERE status IN ('OPEN','PARTIAL')`)
@@ -176,7 +356,8 @@ func (s *SQLiteStorage) GetLiveOrdersByPair(ctx context.Context, pairID string)
 // GetActiveLiveConditions returns distinct condition IDs with open/partial/filled orders.
 func (s *SQLiteStorage) GetActiveLiveConditions(ctx context.Context) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT DISTINCT condition_id FROM live_orders WHERE status IN ('OPEN','PARTIAL','FILLED')`)
+		`SELECT DISTINCT condition_id FROM live_orders WHERE status IN ('OPEN','PARTIAL','FILLED') AND shadow=?`,
+		s.shadowFlag())
 	if err != nil {
 		return nil, err
 	}
@@ -193,26 +374,43 @@ func (s *SQLiteStorage) GetActiveLiveConditions(ctx context.Context) ([]string,
 	return ids, rows.Err()
 }
 
+// GetLiveOrderByCLOBID returns the local order for a CLOB order ID, or nil if unknown.
+func (s *SQLiteStorage) GetLiveOrderByCLOBID(ctx context.Context, clobOrderID string) (*domain.LiveOrder, error) {
+	orders, err := s.queryLiveOrders(ctx, `WHERE clob_order_id=?`, clobOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLiveOrderByCLOBID: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	return &orders[0], nil
+}
+
 // GetAllLiveOrders returns all orders with a specific status.
 func (s *SQLiteStorage) GetAllLiveOrders(ctx context.Context, status string) ([]domain.LiveOrder, error) {
 	return s.queryLiveOrders(ctx, `WHERE status=?`, status)
 }
 
-// CancelLiveOrdersByCondition marks all open orders for a condition as cancelled.
-func (s *SQLiteStorage) CancelLiveOrdersByCondition(ctx context.Context, conditionID string) error {
+// CancelLiveOrdersByCondition marks all open orders for a condition as
+// cancelled, recording why and when.
+func (s *SQLiteStorage) CancelLiveOrdersByCondition(ctx context.Context, conditionID string, reason domain.CloseReason) error {
 	_, err := s.db.ExecContext(ctx,
-		`UPDATE live_orders SET status='CANCELLED' WHERE condition_id=? AND status IN ('OPEN','PARTIAL')`,
-		conditionID)
+		`UPDATE live_orders SET status='CANCELLED', close_reason=?, closed_at=?
+		 WHERE condition_id=? AND status IN ('OPEN','PARTIAL') AND shadow=?`,
+		string(reason), time.Now().UTC(), conditionID, s.shadowFlag())
 	return err
 }
 
+// queryLiveOrders runs a "WHERE ..." filter over this storage's mode only.
 func (s *SQLiteStorage) queryLiveOrders(ctx context.Context, where string, args ...any) ([]domain.LiveOrder, error) {
 	q := `SELECT id, clob_order_id, condition_id, token_id, side, bid_price, size, filled_size,
 		         pair_id, placed_at, status, filled_at, filled_price, question,
-		         queue_ahead, daily_reward, end_date, merged_at, neg_risk, competition_at
-		  FROM live_orders ` + where + ` ORDER BY placed_at ASC`
+		         queue_ahead, daily_reward, end_date, merged_at, neg_risk, competition_at,
+		         order_side, realized_pnl, wallet_address, shadow, expires_at, merged_size, placement_key,
+		         remainder, size_shares, close_reason, closed_at
+		  FROM live_orders WHERE shadow=? AND (` + strings.TrimPrefix(where, "WHERE ") + `) ORDER BY placed_at ASC`
 
-	rows, err := s.db.QueryContext(ctx, q, args...)
+	rows, err := s.db.QueryContext(ctx, q, append([]any{s.shadowFlag()}, args...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -231,15 +429,18 @@ func (s *SQLiteStorage) queryLiveOrders(ctx context.Context, where string, args
 
 func scanLiveOrder(rows *sql.Rows) (domain.LiveOrder, error) {
 	var o domain.LiveOrder
-	var filledAt, endDate, mergedAt sql.NullString
+	var filledAt, endDate, mergedAt, closeReason sql.NullString
 	var statusStr string
-	var negRiskInt int
+	var negRiskInt, shadowInt int
+	var expiresAt, closedAt sql.NullTime
 
 	err := rows.Scan(
 		&o.ID, &o.CLOBOrderID, &o.ConditionID, &o.TokenID, &o.Side,
 		&o.BidPrice, &o.Size, &o.FilledSize,
 		&o.PairID, &o.PlacedAt, &statusStr, &filledAt, &o.FilledPrice, &o.Question,
 		&o.QueueAhead, &o.DailyReward, &endDate, &mergedAt, &negRiskInt, &o.CompetitionAt,
+		&o.OrderSide, &o.RealizedPnL, &o.WalletAddress, &shadowInt, &expiresAt, &o.MergedSize,
+		&o.PlacementKey, &o.Remainder, &o.SizeShares, &closeReason, &closedAt,
 	)
 	if err != nil {
 		return o, err
@@ -247,6 +448,17 @@ func scanLiveOrder(rows *sql.Rows) (domain.LiveOrder, error) {
 
 	o.Status = domain.LiveOrderStatus(statusStr)
 	o.NegRisk = negRiskInt != 0
+	o.Shadow = shadowInt != 0
+	if expiresAt.Valid {
+		o.ExpiresAt = expiresAt.Time
+	}
+	if closeReason.Valid {
+		o.CloseReason = domain.CloseReason(closeReason.String)
+	}
+	if closedAt.Valid {
+		t := closedAt.Time
+		o.ClosedAt = &t
+	}
 
 	if filledAt.Valid && filledAt.String != "" {
 		t, _ := time.Parse(time.RFC3339, filledAt.String)
@@ -286,6 +498,18 @@ func (s *SQLiteStorage) SaveLiveFill(ctx context.Context, f domain.LiveFill) err
 	return err
 }
 
+// HasLiveFill reports whether a fill from the given CLOB trade is already
+// recorded for the order, so polled and streamed trades are not counted twice.
+func (s *SQLiteStorage) HasLiveFill(ctx context.Context, orderID, clobTradeID string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM live_fills WHERE order_id=? AND clob_trade_id=?`, orderID, clobTradeID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("storage.HasLiveFill: %w", err)
+	}
+	return n > 0, nil
+}
+
 // ─── Merges ──────────────────────────────────────────────────────────────────
 
 // SaveMergeResult persists the result of an on-chain merge.
@@ -296,19 +520,34 @@ func (s *SQLiteStorage) SaveMergeResult(ctx context.Context, r domain.MergeResul
 	}
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO live_merges
-		  (condition_id, pair_id, tx_hash, gas_used_pol, gas_cost_usd, usdc_received, spread_profit, success, error, executed_at)
-		VALUES (?,?,?,?,?,?,?,?,?,?)`,
+		  (condition_id, pair_id, tx_hash, gas_used_pol, gas_cost_usd, usdc_received, spread_profit, success, error, executed_at, shadow)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
 		r.ConditionID, r.PairID, r.TxHash, r.GasUsedPOL, r.GasCostUSD,
-		r.USDCReceived, r.SpreadProfit, successInt, r.Error, r.ExecutedAt.UTC(),
+		r.USDCReceived, r.SpreadProfit, successInt, r.Error, r.ExecutedAt.UTC(), s.shadowFlag(),
 	)
 	return err
 }
 
+// SaveRedemption records a redemption of a resolved market's tokens.
+func (s *SQLiteStorage) SaveRedemption(ctx context.Context, r domain.Redemption) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO live_redemptions
+		  (condition_id, pair_id, tx_hash, yes_shares, no_shares, payout, cost_basis, gas_cost_usd, realized_pnl, redeemed_at, shadow)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		r.ConditionID, r.PairID, r.TxHash, r.YesShares, r.NoShares, r.Payout,
+		r.CostBasis, r.GasCostUSD, r.RealizedPnL, r.RedeemedAt.UTC(), s.shadowFlag(),
+	)
+	if err != nil {
+		return fmt.Errorf("storage.SaveRedemption: %w", err)
+	}
+	return nil
+}
+
 // GetMergeResults returns all recorded merge results.
 func (s *SQLiteStorage) GetMergeResults(ctx context.Context) ([]domain.MergeResult, error) {
 	rows, err := s.db.QueryContext(ctx,
 		`SELECT condition_id, pair_id, tx_hash, gas_used_pol, gas_cost_usd, usdc_received, spread_profit, success, error, executed_at
-		 FROM live_merges ORDER BY executed_at ASC`)
+		 FROM live_merges WHERE shadow=? ORDER BY executed_at ASC`, s.shadowFlag())
 	if err != nil {
 		return nil, err
 	}
@@ -348,10 +587,10 @@ func (s *SQLiteStorage) SaveCircuitBreaker(ctx context.Context, cb domain.Circui
 		  consecutive_losses=?, max_losses=?, cooldown_until=?,
 		  cooldown_duration_s=?, total_pnl=?, max_drawdown=?,
 		  triggered=?, triggered_reason=?
-		WHERE id=1`,
+		WHERE id=?`,
 		cb.ConsecutiveLosses, cb.MaxLosses, nullTime(cooldownUntil),
 		int(cb.CooldownDuration.Seconds()), cb.TotalPnL, cb.MaxDrawdown,
-		triggeredInt, cb.TriggeredReason,
+		triggeredInt, cb.TriggeredReason, s.breakerRow(),
 	)
 	return err
 }
@@ -365,8 +604,8 @@ func (s *SQLiteStorage) LoadCircuitBreaker(ctx context.Context) (domain.CircuitB
 
 	err := s.db.QueryRowContext(ctx, `
 		SELECT consecutive_losses, max_losses, cooldown_until, cooldown_duration_s,
-		       total_pnl, max_drawdown, triggered, triggered_reason
-		FROM live_circuit_breaker WHERE id=1`).Scan(
+		       total_pnl, max_drawdown, triggered, COALESCE(triggered_reason, '')
+		FROM live_circuit_breaker WHERE id=?`, s.breakerRow()).Scan(
 		&cb.ConsecutiveLosses, &cb.MaxLosses, &cooldownUntilStr, &cooldownDurationS,
 		&cb.TotalPnL, &cb.MaxDrawdown, &triggeredInt, &cb.TriggeredReason,
 	)
@@ -388,16 +627,74 @@ func (s *SQLiteStorage) LoadCircuitBreaker(ctx context.Context) (domain.CircuitB
 	return cb, nil
 }
 
+// ─── Rotation cooldowns ──────────────────────────────────────────────────────
+
+// SaveLiveCooldown blocks re-entry into a market until the given time,
+// replacing any previous cooldown for it.
+func (s *SQLiteStorage) SaveLiveCooldown(ctx context.Context, conditionID string, until time.Time, reason string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO live_cooldowns (condition_id, shadow, until, reason) VALUES (?,?,?,?)
+		ON CONFLICT(condition_id, shadow) DO UPDATE SET until=excluded.until, reason=excluded.reason`,
+		conditionID, s.shadowFlag(), until.UTC(), reason)
+	if err != nil {
+		return fmt.Errorf("storage.SaveLiveCooldown: %w", err)
+	}
+	return nil
+}
+
+// GetLiveCooldowns returns the cooldowns still in force, by condition ID.
+func (s *SQLiteStorage) GetLiveCooldowns(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT condition_id, until FROM live_cooldowns WHERE shadow=?`, s.shadowFlag())
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLiveCooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	cooldowns := make(map[string]time.Time)
+	for rows.Next() {
+		var conditionID string
+		var until time.Time
+		if err := rows.Scan(&conditionID, &until); err != nil {
+			return nil, fmt.Errorf("storage.GetLiveCooldowns: %w", err)
+		}
+		if until.After(now) {
+			cooldowns[conditionID] = until
+		}
+	}
+	return cooldowns, rows.Err()
+}
+
+// ─── Realized rewards ────────────────────────────────────────────────────────
+
+// SaveLiveReward upserts the reward paid for a market on a day. Rewards belong
+// to the real wallets only, so a shadow storage does not record them.
+func (s *SQLiteStorage) SaveLiveReward(ctx context.Context, r domain.LiveReward) error {
+	if s.shadow {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO live_rewards (date, condition_id, earnings, synced_at) VALUES (?,?,?,?)
+		ON CONFLICT(date, condition_id) DO UPDATE SET earnings=excluded.earnings, synced_at=excluded.synced_at`,
+		r.Date.UTC().Format("2006-01-02"), r.ConditionID, r.Earnings, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("storage.SaveLiveReward: %w", err)
+	}
+	return nil
+}
+
 // ─── Daily Summary ───────────────────────────────────────────────────────────
 
 // SaveLiveDaily upserts a daily summary.
 func (s *SQLiteStorage) SaveLiveDaily(ctx context.Context, d domain.LiveDailySummary) error {
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO live_daily
+		INSERT INTO `+s.liveDailyTable()+`
 		  (date, active_positions, complete_pairs, partial_fills, total_reward, total_fill_pnl,
 		   net_pnl, avg_partial_mins, fills_yes, fills_no, orders_placed, orders_cancelled,
-		   capital_deployed, merges, merge_profit, gas_cost_usd, compound_balance, rotations)
-		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+		   capital_deployed, merges, merge_profit, gas_cost_usd, compound_balance, rotations,
+		   gas_used_pol, pol_balance, stranded_shares, stranded_usdc)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
 		ON CONFLICT(date) DO UPDATE SET
 		  active_positions=excluded.active_positions,
 		  complete_pairs=excluded.complete_pairs,
@@ -415,12 +712,17 @@ func (s *SQLiteStorage) SaveLiveDaily(ctx context.Context, d domain.LiveDailySum
 		  merge_profit=excluded.merge_profit,
 		  gas_cost_usd=excluded.gas_cost_usd,
 		  compound_balance=excluded.compound_balance,
-		  rotations=excluded.rotations`,
+		  rotations=excluded.rotations,
+		  gas_used_pol=excluded.gas_used_pol,
+		  pol_balance=excluded.pol_balance,
+		  stranded_shares=excluded.stranded_shares,
+		  stranded_usdc=excluded.stranded_usdc`,
 		d.Date.Format("2006-01-02"),
 		d.ActivePositions, d.CompletePairs, d.PartialFills, d.TotalReward,
 		d.TotalFillPnL, d.NetPnL, d.AvgPartialMins, d.FillsYes, d.FillsNo,
 		d.OrdersPlaced, d.OrdersCancelled, d.CapitalDeployed, d.Merges,
 		d.MergeProfit, d.GasCostUSD, d.CompoundBalance, d.Rotations,
+		d.GasUsedPOL, d.POLBalance, d.StrandedShares, d.StrandedUSDC,
 	)
 	return err
 }
@@ -430,8 +732,9 @@ func (s *SQLiteStorage) GetLiveDailies(ctx context.Context) ([]domain.LiveDailyS
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT date, active_positions, complete_pairs, partial_fills, total_reward, total_fill_pnl,
 		       net_pnl, avg_partial_mins, fills_yes, fills_no, orders_placed, orders_cancelled,
-		       capital_deployed, merges, merge_profit, gas_cost_usd, compound_balance, rotations
-		FROM live_daily ORDER BY date ASC`)
+		       capital_deployed, merges, merge_profit, gas_cost_usd, compound_balance, rotations,
+		       gas_used_pol, pol_balance, stranded_shares, stranded_usdc
+		FROM `+s.liveDailyTable()+` ORDER BY date ASC`)
 	if err != nil {
 		return nil, err
 	}
@@ -444,7 +747,8 @@ func (s *SQLiteStorage) GetLiveDailies(ctx context.Context) ([]domain.LiveDailyS
 		if err := rows.Scan(&dateStr, &d.ActivePositions, &d.CompletePairs, &d.PartialFills,
 			&d.TotalReward, &d.TotalFillPnL, &d.NetPnL, &d.AvgPartialMins, &d.FillsYes, &d.FillsNo,
 			&d.OrdersPlaced, &d.OrdersCancelled, &d.CapitalDeployed, &d.Merges,
-			&d.MergeProfit, &d.GasCostUSD, &d.CompoundBalance, &d.Rotations); err != nil {
+			&d.MergeProfit, &d.GasCostUSD, &d.CompoundBalance, &d.Rotations,
+			&d.GasUsedPOL, &d.POLBalance, &d.StrandedShares, &d.StrandedUSDC); err != nil {
 			return nil, err
 		}
 		d.Date, _ = time.Parse("2006-01-02", dateStr)
@@ -455,7 +759,7 @@ func (s *SQLiteStorage) GetLiveDailies(ctx context.Context) ([]domain.LiveDailyS
 
 // GetLiveStats aggregates statistics across all live trading history.
 func (s *SQLiteStorage) GetLiveStats(ctx context.Context) (domain.LiveStats, error) {
-	var stats domain.LiveStats
+	stats := domain.LiveStats{Shadow: s.shadow}
 
 	dailies, err := s.GetLiveDailies(ctx)
 	if err != nil {
@@ -472,32 +776,57 @@ func (s *SQLiteStorage) GetLiveStats(ctx context.Context) (domain.LiveStats, err
 			stats.TotalReward += d.TotalReward
 			stats.TotalMergeProfit += d.MergeProfit
 			stats.TotalGasCostUSD += d.GasCostUSD
+			stats.TotalGasUsedPOL += d.GasUsedPOL
+			if d.POLBalance > 0 {
+				stats.POLBalance = d.POLBalance
+			}
 			stats.NetPnL += d.NetPnL
 			stats.TotalRotations += d.Rotations
 		}
 		stats.CompoundBalance = dailies[len(dailies)-1].CompoundBalance
+		stats.StrandedShares = dailies[len(dailies)-1].StrandedShares
+		stats.StrandedUSDC = dailies[len(dailies)-1].StrandedUSDC
+	}
 
-		if stats.DaysRunning > 0 {
-			stats.DailyAvgPnL = stats.NetPnL / float64(stats.DaysRunning)
+	// Net P&L counts only rewards Polymarket has paid; the block-accrual
+	// estimate stays in TotalReward for comparison.
+	if !s.shadow {
+		err = s.db.QueryRowContext(ctx,
+			`SELECT COALESCE(SUM(earnings), 0) FROM live_rewards`).Scan(&stats.RealizedReward)
+		if err != nil {
+			return stats, err
 		}
+		stats.NetPnL += stats.RealizedReward
+	}
+
+	// Redemptions of resolved markets are realized P&L outside the merges.
+	err = s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(realized_pnl), 0) FROM live_redemptions WHERE shadow=?`,
+		s.shadowFlag()).Scan(&stats.Redemptions, &stats.RedemptionPnL)
+	if err != nil {
+		return stats, err
+	}
+	stats.NetPnL += stats.RedemptionPnL
+	if stats.DaysRunning > 0 {
+		stats.DailyAvgPnL = stats.NetPnL / float64(stats.DaysRunning)
 	}
 
 	// Order stats
 	err = s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM live_orders`).Scan(&stats.TotalOrders)
+		`SELECT COUNT(*) FROM live_orders WHERE shadow=?`, s.shadowFlag()).Scan(&stats.TotalOrders)
 	if err != nil {
 		return stats, err
 	}
 
 	err = s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM live_orders WHERE status='FILLED'`).Scan(&stats.TotalFills)
+		`SELECT COUNT(*) FROM live_orders WHERE status='FILLED' AND shadow=?`, s.shadowFlag()).Scan(&stats.TotalFills)
 	if err != nil {
 		return stats, err
 	}
 
 	// Merge stats
 	err = s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM live_merges WHERE success=1`).Scan(&stats.CompletePairs)
+		`SELECT COUNT(*) FROM live_merges WHERE success=1 AND shadow=?`, s.shadowFlag()).Scan(&stats.CompletePairs)
 	if err != nil {
 		return stats, err
 	}
@@ -507,13 +836,36 @@ func (s *SQLiteStorage) GetLiveStats(ctx context.Context) (domain.LiveStats, err
 		stats.FillRateReal = float64(stats.TotalFills) / float64(stats.TotalOrders)
 	}
 
+	// Rows retired before close_reason existed have it NULL and are skipped.
+	stats.CloseReasons, err = s.closeReasonCounts(ctx, `
+		SELECT close_reason, COUNT(DISTINCT pair_id) FROM live_orders
+		WHERE close_reason IS NOT NULL AND shadow=? GROUP BY close_reason`, s.shadowFlag())
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetLiveStats: close reasons: %w", err)
+	}
+
+	stats.Markets, err = s.liveMarketPnL(ctx, time.Now().UTC())
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetLiveStats: markets: %w", err)
+	}
+
+	stats.FillLatency, err = s.fillLatencies(ctx, `
+		SELECT side, placed_at, filled_at
+		FROM live_orders
+		WHERE status IN ('FILLED', 'MERGED') AND filled_at IS NOT NULL
+		  AND order_side='BUY' AND shadow=?`, s.shadowFlag())
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetLiveStats: fill latency: %w", err)
+	}
+
 	return stats, nil
 }
 
 // GetPartialPairs devuelve los pairIDs donde solo uno de los dos lados (YES/NO) está filled.
 func (s *SQLiteStorage) GetPartialPairs(ctx context.Context) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT pair_id, side FROM live_orders WHERE status IN ('FILLED','PARTIAL')`)
+		`SELECT pair_id, side FROM live_orders WHERE status IN ('FILLED','PARTIAL') AND order_side='BUY' AND shadow=?`,
+		s.shadowFlag())
 	if err != nil {
 		return nil, fmt.Errorf("storage.GetPartialPairs: query: %w", err)
 	}
@@ -561,6 +913,20 @@ func nullTimeVal(t time.Time) any {
 	return t.UTC()
 }
 
+func nullCloseReason(r domain.CloseReason) any {
+	if r == "" {
+		return nil
+	}
+	return string(r)
+}
+
+func orderSideOrBuy(side string) string {
+	if side == "" {
+		return "BUY"
+	}
+	return side
+}
+
 func boolToInt(b bool) int {
 	if b {
 		return 1
@@ -0,0 +1,327 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLiveStore(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(context.Background()))
+	return db
+}
+
+func makeLiveOrder(id, pairID, side string, status domain.LiveOrderStatus) domain.LiveOrder {
+	return domain.LiveOrder{
+		ID:          id,
+		CLOBOrderID: "0x" + id,
+		ConditionID: "0xcond",
+		TokenID:     "tok_" + side,
+		Side:        side,
+		BidPrice:    0.40,
+		Size:        5,
+		PairID:      pairID,
+		PlacedAt:    time.Now().UTC(),
+		Status:      status,
+	}
+}
+
+func TestLiveStorage_UnwindSellExcludedFromPartials(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	buy := makeLiveOrder("yes1", "pair1", "YES", domain.LiveStatusFilled)
+	buy.FilledSize = 5
+	require.NoError(t, db.SaveLiveOrder(ctx, buy))
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("no1", "pair1", "NO", domain.LiveStatusCancelled)))
+
+	sell := makeLiveOrder("sell1", "pair1", "YES", domain.LiveStatusFilled)
+	sell.OrderSide = "SELL"
+	require.NoError(t, db.SaveLiveOrder(ctx, sell))
+
+	partials, err := db.GetPartialPairs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pair1"}, partials, "the SELL must not complete the pair")
+
+	require.NoError(t, db.CloseLiveOrder(ctx, "sell1", domain.LiveStatusFlattened, -0.75))
+	require.NoError(t, db.UpdateLiveOrderStatus(ctx, "yes1", domain.LiveStatusFlattened))
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "pair1")
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	for _, o := range orders {
+		if o.ID == "sell1" {
+			assert.True(t, o.IsSell())
+			assert.InDelta(t, -0.75, o.RealizedPnL, 1e-9)
+		} else {
+			assert.False(t, o.IsSell())
+		}
+	}
+
+	pnl, err := db.GetRealizedPnL(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, -0.75, pnl, 1e-9)
+
+	partials, err = db.GetPartialPairs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, partials)
+}
+
+func TestLiveStorage_WalletAddressRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	yes := makeLiveOrder("yes1", "pair1", "YES", domain.LiveStatusOpen)
+	yes.WalletAddress = "0xAbC0000000000000000000000000000000000001"
+	require.NoError(t, db.SaveLiveOrder(ctx, yes))
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("no1", "pair1", "NO", domain.LiveStatusOpen)))
+
+	got, err := db.GetLiveOrderByCLOBID(ctx, "0xyes1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, yes.WalletAddress, got.WalletAddress)
+
+	got, err = db.GetLiveOrderByCLOBID(ctx, "0xno1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Empty(t, got.WalletAddress, "orders without a wallet belong to the primary")
+}
+
+func TestLiveStorage_ShadowRowsAreSeparate(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+	shadow := db.ShadowLive()
+
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("real1", "pair1", "YES", domain.LiveStatusOpen)))
+	require.NoError(t, shadow.SaveLiveOrder(ctx, makeLiveOrder("sh1", "pair2", "YES", domain.LiveStatusOpen)))
+	require.NoError(t, shadow.SaveLiveOrder(ctx, makeLiveOrder("sh2", "pair2", "NO", domain.LiveStatusOpen)))
+	require.NoError(t, shadow.SaveCircuitBreaker(ctx, domain.CircuitBreaker{ConsecutiveLosses: 2}))
+
+	real, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, real, 1)
+	assert.False(t, real[0].Shadow)
+
+	sh, err := shadow.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, sh, 2)
+	assert.True(t, sh[0].Shadow)
+
+	stats, err := shadow.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.True(t, stats.Shadow)
+	assert.Equal(t, 2, stats.TotalOrders)
+
+	cb, err := db.LoadCircuitBreaker(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cb.ConsecutiveLosses, "shadow breaker state does not touch the real one")
+}
+
+func TestLiveStorage_CooldownsPersistAndExpire(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	until := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.SaveLiveCooldown(ctx, "0xactive", time.Now().Add(time.Hour), "stale 4.1h"))
+	require.NoError(t, db.SaveLiveCooldown(ctx, "0xactive", until, "competition spiked 3.2x"))
+	require.NoError(t, db.SaveLiveCooldown(ctx, "0xexpired", time.Now().Add(-time.Minute), "stale"))
+	require.NoError(t, db.ShadowLive().SaveLiveCooldown(ctx, "0xshadow", until, "stale"))
+
+	cooldowns, err := db.GetLiveCooldowns(ctx)
+	require.NoError(t, err)
+	require.Len(t, cooldowns, 1, "expired and shadow cooldowns are not returned")
+	assert.True(t, cooldowns["0xactive"].Equal(until), "later save replaces the earlier one")
+}
+
+func TestLiveStorage_ExpiresAtRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	gtd := makeLiveOrder("o1", "p1", "YES", domain.LiveStatusOpen)
+	gtd.ExpiresAt = time.Now().Add(4 * time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.SaveLiveOrder(ctx, gtd))
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("o2", "p1", "NO", domain.LiveStatusOpen)))
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	byID := map[string]domain.LiveOrder{orders[0].ID: orders[0], orders[1].ID: orders[1]}
+	assert.True(t, byID["o1"].ExpiresAt.Equal(gtd.ExpiresAt))
+	assert.True(t, byID["o2"].ExpiresAt.IsZero(), "GTC orders have no expiry")
+}
+
+func TestLiveStorage_HasLiveFillByTradeID(t *testing.T) {
+	s := newLiveStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.SaveLiveFill(ctx, domain.LiveFill{
+		OrderID: "o1", CLOBTradeID: "t1", Price: 0.45, Size: 4.5, Timestamp: time.Now(),
+	}))
+
+	seen, err := s.HasLiveFill(ctx, "o1", "t1")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	seen, err = s.HasLiveFill(ctx, "o1", "t2")
+	require.NoError(t, err)
+	assert.False(t, seen, "other trade of the same order")
+
+	seen, err = s.HasLiveFill(ctx, "o2", "t1")
+	require.NoError(t, err)
+	assert.False(t, seen, "same trade, other order")
+}
+
+func TestLiveStorage_AddLiveOrderMergedAccumulates(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	o := makeLiveOrder("o1", "p1", "YES", domain.LiveStatusPartial)
+	o.FilledSize = 8
+	require.NoError(t, db.SaveLiveOrder(ctx, o))
+
+	require.NoError(t, db.AddLiveOrderMerged(ctx, "o1", 2.5))
+	require.NoError(t, db.AddLiveOrderMerged(ctx, "o1", 3))
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.InDelta(t, 5.5, orders[0].MergedSize, 1e-9)
+	assert.InDelta(t, 2.5, orders[0].UnmergedSize(), 1e-9)
+	assert.Equal(t, domain.LiveStatusPartial, orders[0].Status, "a partial merge keeps the order resting")
+}
+
+func TestLiveStorage_PendingOrderKeepsPlacementKey(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	o := makeLiveOrder("o1", "p1", "YES", domain.LiveStatusPending)
+	o.CLOBOrderID = ""
+	o.PlacementKey = domain.PlacementKey("p1", "YES", o.BidPrice)
+	require.NoError(t, db.SaveLiveOrder(ctx, o))
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open, "a pending placement is not resting yet")
+
+	pending, err := db.GetAllLiveOrders(ctx, string(domain.LiveStatusPending))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, o.PlacementKey, pending[0].PlacementKey)
+
+	o.CLOBOrderID = "0xabc"
+	o.Status = domain.LiveStatusOpen
+	require.NoError(t, db.SaveLiveOrder(ctx, o))
+
+	rec, err := db.GetLiveOrderByCLOBID(ctx, "0xabc")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, domain.LiveStatusOpen, rec.Status)
+	assert.Equal(t, o.PlacementKey, rec.PlacementKey)
+}
+
+func TestLiveStorage_RealizedRewardsCountInNetPnL(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: day, TotalReward: 2, NetPnL: 1}))
+	require.NoError(t, db.SaveLiveReward(ctx, domain.LiveReward{Date: day, ConditionID: "0xa", Earnings: 0.5}))
+	require.NoError(t, db.SaveLiveReward(ctx, domain.LiveReward{Date: day, ConditionID: "0xa", Earnings: 0.8}))
+	require.NoError(t, db.SaveLiveReward(ctx, domain.LiveReward{Date: day, ConditionID: "0xb", Earnings: 0.2}))
+	require.NoError(t, db.ShadowLive().SaveLiveReward(ctx, domain.LiveReward{Date: day, ConditionID: "0xc", Earnings: 9}))
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 2, stats.TotalReward, 1e-9, "the estimate is kept")
+	assert.InDelta(t, 1.0, stats.RealizedReward, 1e-9, "a re-synced day replaces its earlier value")
+	assert.InDelta(t, 2.0, stats.NetPnL, 1e-9)
+
+	shadow, err := db.ShadowLive().GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, shadow.RealizedReward)
+}
+
+func TestLiveStorage_DailyGasPOL(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: day, GasUsedPOL: 0.03, POLBalance: 2.0}))
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: day.AddDate(0, 0, 1), GasUsedPOL: 0.05, POLBalance: 1.95}))
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.08, stats.TotalGasUsedPOL, 1e-9)
+	assert.InDelta(t, 1.95, stats.POLBalance, 1e-9, "latest day's balance")
+}
+
+func TestLiveStorage_RemainderAndStrandedDaily(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("o1", "pair1", "YES", domain.LiveStatusFilled)))
+	require.NoError(t, db.SetLiveOrderRemainder(ctx, "o1", 7.5))
+	orders, err := db.GetLiveOrdersByPair(ctx, "pair1")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.InDelta(t, 7.5, orders[0].Remainder, 1e-9)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: day, StrandedShares: 7.5, StrandedUSDC: 3}))
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 7.5, stats.StrandedShares, 1e-9)
+	assert.InDelta(t, 3, stats.StrandedUSDC, 1e-9)
+}
+
+func TestLiveStorage_CloseReasonCountsPairs(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+
+	// Two pairs rotated as stale on one condition, one pair near end on another.
+	for _, o := range []domain.LiveOrder{
+		makeLiveOrder("a-yes", "pa", "YES", domain.LiveStatusOpen),
+		makeLiveOrder("a-no", "pa", "NO", domain.LiveStatusOpen),
+		makeLiveOrder("b-yes", "pb", "YES", domain.LiveStatusOpen),
+	} {
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+	require.NoError(t, db.CancelLiveOrdersByCondition(ctx, "0xcond", domain.CloseStale))
+
+	near := makeLiveOrder("c-yes", "pc", "YES", domain.LiveStatusOpen)
+	near.ConditionID = "0xnear"
+	require.NoError(t, db.SaveLiveOrder(ctx, near))
+	require.NoError(t, db.RetireLiveOrder(ctx, "c-yes", domain.LiveStatusCancelled, domain.CloseNearEnd))
+
+	// Cancelled before close_reason existed: no reason, not counted.
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("d-yes", "pd", "YES", domain.LiveStatusCancelled)))
+	shadow := db.ShadowLive()
+	require.NoError(t, shadow.SaveLiveOrder(ctx, makeLiveOrder("s-yes", "ps", "YES", domain.LiveStatusOpen)))
+	require.NoError(t, shadow.RetireLiveOrder(ctx, "s-yes", domain.LiveStatusCancelled, domain.CloseDryRun))
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.CloseReason]int{domain.CloseStale: 2, domain.CloseNearEnd: 1}, stats.CloseReasons)
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "pa")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, domain.CloseStale, orders[0].CloseReason)
+	require.NotNil(t, orders[0].ClosedAt)
+	assert.WithinDuration(t, time.Now(), *orders[0].ClosedAt, time.Minute)
+
+	legacy, err := db.GetLiveOrdersByPair(ctx, "pd")
+	require.NoError(t, err)
+	require.Len(t, legacy, 1)
+	assert.Empty(t, legacy[0].CloseReason)
+	assert.Nil(t, legacy[0].ClosedAt)
+}
@@ -0,0 +1,418 @@
+package storage
+
+// market_pnl.go — Per-market P&L attribution and order timelines.
+//
+// GetPaperStats and GetLiveStats fill Stats.Markets with one MarketPnL per
+// condition ever traded, worst first. The timelines dump every order, fill,
+// merge and reward of one condition for debugging a single market.
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// pnlAcc accumulates one condition's MarketPnL.
+type pnlAcc struct {
+	pnl         domain.MarketPnL
+	first, last time.Time
+	active      bool // some entry order still rests in the book
+}
+
+func (a *pnlAcc) touch(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if a.first.IsZero() || t.Before(a.first) {
+		a.first = t
+	}
+	if t.After(a.last) {
+		a.last = t
+	}
+}
+
+type pnlAccs map[string]*pnlAcc
+
+func (m pnlAccs) get(conditionID, question string) *pnlAcc {
+	a := m[conditionID]
+	if a == nil {
+		a = &pnlAcc{pnl: domain.MarketPnL{ConditionID: conditionID}}
+		m[conditionID] = a
+	}
+	if a.pnl.Question == "" {
+		a.pnl.Question = question
+	}
+	return a
+}
+
+// finish computes time in market and net P&L and sorts worst first.
+func (m pnlAccs) finish(now time.Time) []domain.MarketPnL {
+	out := make([]domain.MarketPnL, 0, len(m))
+	for _, a := range m {
+		end := a.last
+		if a.active {
+			end = now
+		}
+		if !a.first.IsZero() && end.After(a.first) {
+			a.pnl.TimeInMarket = end.Sub(a.first)
+		}
+		a.pnl.NetPnL = a.pnl.MergeProfit + a.pnl.RewardAccrued + a.pnl.RealizedPnL
+		out = append(out, a.pnl)
+	}
+	domain.SortMarketPnL(out)
+	return out
+}
+
+// pairRewardEstimate estimates the reward a pair earned while both legs
+// rested: its daily reward over the time until the first fill or merge.
+// Pairs closed without a recorded time (cancelled, expired) count as zero.
+func pairRewardEstimate(dailyReward float64, placed time.Time, ends []*time.Time, active bool, now time.Time) float64 {
+	var end time.Time
+	for _, t := range ends {
+		if t != nil && !t.IsZero() && (end.IsZero() || t.Before(end)) {
+			end = *t
+		}
+	}
+	if end.IsZero() && active {
+		end = now
+	}
+	if end.IsZero() || !end.After(placed) {
+		return 0
+	}
+	return dailyReward * end.Sub(placed).Hours() / 24
+}
+
+// pairMergeProfit is the spread captured by merging a paper pair: the
+// mergeable shares times 1 - (yes + no price).
+func pairMergeProfit(yesPrice, yesSize, noPrice, noSize float64) float64 {
+	if yesPrice <= 0 || noPrice <= 0 {
+		return 0
+	}
+	mergeable := min(yesSize/yesPrice, noSize/noPrice)
+	return mergeable * (1.0 - yesPrice - noPrice)
+}
+
+// ─── Paper ───────────────────────────────────────────────────────────────────
+
+// paperMarketPnL attributes paper results per condition from paper_orders.
+func paperMarketPnL(orders []domain.VirtualOrder, now time.Time) []domain.MarketPnL {
+	accs := make(pnlAccs)
+	pairs := make(map[string][]domain.VirtualOrder)
+	for _, o := range orders {
+		a := accs.get(o.ConditionID, o.Question)
+		a.pnl.Orders++
+		if o.FilledSize > 0 || o.FilledAt != nil {
+			a.pnl.Fills++
+		}
+		a.touch(o.PlacedAt)
+		if o.FilledAt != nil {
+			a.touch(*o.FilledAt)
+		}
+		if o.MergedAt != nil {
+			a.touch(*o.MergedAt)
+		}
+		if o.Status == domain.PaperStatusOpen || o.Status == domain.PaperStatusPartial {
+			a.active = true
+		}
+		pairs[o.PairID] = append(pairs[o.PairID], o)
+	}
+
+	for _, legs := range pairs {
+		a := accs[legs[0].ConditionID]
+		var yes, no *domain.VirtualOrder
+		merged, open := true, false
+		var ends []*time.Time
+		for i := range legs {
+			o := &legs[i]
+			if o.Side == "YES" {
+				yes = o
+			} else {
+				no = o
+			}
+			merged = merged && o.Status == domain.PaperStatusMerged
+			open = open || o.Status == domain.PaperStatusOpen || o.Status == domain.PaperStatusPartial ||
+				o.Status == domain.PaperStatusFilled
+			ends = append(ends, o.FilledAt, o.MergedAt)
+		}
+		a.pnl.RewardAccrued += pairRewardEstimate(legs[0].DailyReward, legs[0].PlacedAt, ends, open, now)
+
+		switch {
+		case merged && yes != nil && no != nil:
+			a.pnl.Merges++
+			a.pnl.MergeProfit += pairMergeProfit(fillPrice(yes.FilledPrice, yes.BidPrice), yes.Size,
+				fillPrice(no.FilledPrice, no.BidPrice), no.Size)
+		case !open:
+			a.pnl.Rotations++
+		}
+	}
+	return accs.finish(now)
+}
+
+func fillPrice(filled, bid float64) float64 {
+	if filled > 0 {
+		return filled
+	}
+	return bid
+}
+
+// GetPaperMarketTimeline returns every order, fill and merge of one
+// condition in paper trading, oldest first.
+func (s *SQLiteStorage) GetPaperMarketTimeline(ctx context.Context, conditionID string) ([]domain.MarketEvent, error) {
+	orders, err := s.queryPaperOrders(ctx, `
+		SELECT id, condition_id, token_id, side, bid_price, size,
+		       pair_id, placed_at, status, filled_at, filled_price, question,
+		       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
+		FROM paper_orders WHERE condition_id = ?`, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetPaperMarketTimeline: %w", err)
+	}
+
+	var events []domain.MarketEvent
+	side := make(map[string]string, len(orders))
+	for _, o := range orders {
+		side[o.ID] = o.Side
+		events = append(events, domain.MarketEvent{
+			At: o.PlacedAt, Kind: "PLACED", OrderID: o.ID, Side: o.Side, Price: o.BidPrice, Size: o.Size,
+			Detail: fmt.Sprintf("pair %s, queue $%.0f", shortID(o.PairID), o.QueueAhead),
+		})
+		if o.MergedAt != nil {
+			events = append(events, domain.MarketEvent{At: *o.MergedAt, Kind: "MERGED", OrderID: o.ID, Side: o.Side})
+		}
+		events = append(events, finalStatusEvent(o.ID, o.Side, string(o.Status))...)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.order_id, f.price, f.size, f.timestamp, COALESCE(f.trade_id, '')
+		FROM paper_fills f JOIN paper_orders o ON o.id = f.order_id
+		WHERE o.condition_id = ?`, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetPaperMarketTimeline: fills: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e domain.MarketEvent
+		var ts string
+		if err := rows.Scan(&e.OrderID, &e.Price, &e.Size, &ts, &e.Detail); err != nil {
+			return nil, fmt.Errorf("storage.GetPaperMarketTimeline: scan fill: %w", err)
+		}
+		e.At, _ = time.Parse(time.RFC3339, ts)
+		e.Kind = "FILL"
+		e.Side = side[e.OrderID]
+		if e.Detail != "" {
+			e.Detail = "trade " + e.Detail
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage.GetPaperMarketTimeline: %w", err)
+	}
+
+	domain.SortMarketEvents(events)
+	return events, nil
+}
+
+// ─── Live ────────────────────────────────────────────────────────────────────
+
+// liveMarketPnL attributes live results per condition from live_orders,
+// live_merges and, for real trading, the rewards Polymarket paid.
+func (s *SQLiteStorage) liveMarketPnL(ctx context.Context, now time.Time) ([]domain.MarketPnL, error) {
+	orders, err := s.queryLiveOrders(ctx, `1=1`)
+	if err != nil {
+		return nil, fmt.Errorf("orders: %w", err)
+	}
+	accs := make(pnlAccs)
+	pairs := make(map[string][]domain.LiveOrder)
+	for _, o := range orders {
+		a := accs.get(o.ConditionID, o.Question)
+		a.pnl.RealizedPnL += o.RealizedPnL
+		a.touch(o.PlacedAt)
+		if o.FilledAt != nil {
+			a.touch(*o.FilledAt)
+		}
+		if o.IsSell() {
+			continue
+		}
+		a.pnl.Orders++
+		if o.FilledSize > 0 {
+			a.pnl.Fills++
+		}
+		if o.Status == domain.LiveStatusOpen || o.Status == domain.LiveStatusPartial || o.Status == domain.LiveStatusPending {
+			a.active = true
+		}
+		pairs[o.PairID] = append(pairs[o.PairID], o)
+	}
+
+	for _, legs := range pairs {
+		a := accs[legs[0].ConditionID]
+		closed, open := true, false
+		var ends []*time.Time
+		for _, o := range legs {
+			switch o.Status {
+			case domain.LiveStatusCancelled, domain.LiveStatusExpired, domain.LiveStatusFlattened:
+			default:
+				closed = false
+			}
+			open = open || o.Status == domain.LiveStatusOpen || o.Status == domain.LiveStatusPartial
+			ends = append(ends, o.FilledAt, o.MergedAt)
+		}
+		if closed {
+			a.pnl.Rotations++
+		}
+		if s.shadow {
+			a.pnl.RewardAccrued += pairRewardEstimate(legs[0].DailyReward, legs[0].PlacedAt, ends, open, now)
+		}
+	}
+
+	merges, err := s.GetMergeResults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("merges: %w", err)
+	}
+	for _, m := range merges {
+		if !m.Success {
+			continue
+		}
+		a := accs.get(m.ConditionID, "")
+		a.pnl.Merges++
+		a.pnl.MergeProfit += m.SpreadProfit
+		a.touch(m.ExecutedAt)
+	}
+
+	if !s.shadow {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT condition_id, SUM(earnings) FROM live_rewards GROUP BY condition_id`)
+		if err != nil {
+			return nil, fmt.Errorf("rewards: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var cid string
+			var earnings float64
+			if err := rows.Scan(&cid, &earnings); err != nil {
+				return nil, fmt.Errorf("rewards: scan: %w", err)
+			}
+			accs.get(cid, "").pnl.RewardAccrued += earnings
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rewards: %w", err)
+		}
+	}
+	return accs.finish(now), nil
+}
+
+// GetLiveMarketTimeline returns every order, fill, merge and paid reward of
+// one condition in this storage's mode, oldest first.
+func (s *SQLiteStorage) GetLiveMarketTimeline(ctx context.Context, conditionID string) ([]domain.MarketEvent, error) {
+	orders, err := s.queryLiveOrders(ctx, `condition_id=?`, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLiveMarketTimeline: %w", err)
+	}
+
+	var events []domain.MarketEvent
+	side := make(map[string]string, len(orders))
+	for _, o := range orders {
+		side[o.ID] = o.Side
+		kind := "PLACED"
+		if o.IsSell() {
+			kind = "PLACED SELL"
+		}
+		events = append(events, domain.MarketEvent{
+			At: o.PlacedAt, Kind: kind, OrderID: o.ID, Side: o.Side, Price: o.BidPrice, Size: o.Size,
+			Detail: fmt.Sprintf("pair %s, clob %s", shortID(o.PairID), shortID(o.CLOBOrderID)),
+		})
+		if o.MergedAt != nil {
+			events = append(events, domain.MarketEvent{At: *o.MergedAt, Kind: "MERGED", OrderID: o.ID, Side: o.Side,
+				Size: o.MergedSize})
+		}
+		detail := ""
+		if o.RealizedPnL != 0 {
+			detail = fmt.Sprintf("realized $%.4f", o.RealizedPnL)
+		}
+		for _, e := range finalStatusEvent(o.ID, o.Side, string(o.Status)) {
+			e.Detail = detail
+			events = append(events, e)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.order_id, f.price, f.size, f.timestamp, COALESCE(f.clob_trade_id, '')
+		FROM live_fills f JOIN live_orders o ON o.id = f.order_id
+		WHERE o.condition_id = ? AND o.shadow = ?`, conditionID, s.shadowFlag())
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLiveMarketTimeline: fills: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e domain.MarketEvent
+		if err := rows.Scan(&e.OrderID, &e.Price, &e.Size, &e.At, &e.Detail); err != nil {
+			return nil, fmt.Errorf("storage.GetLiveMarketTimeline: scan fill: %w", err)
+		}
+		e.Kind = "FILL"
+		e.Side = side[e.OrderID]
+		if e.Detail != "" {
+			e.Detail = "trade " + shortID(e.Detail)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage.GetLiveMarketTimeline: %w", err)
+	}
+
+	merges, err := s.GetMergeResults(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLiveMarketTimeline: merges: %w", err)
+	}
+	for _, m := range merges {
+		if m.ConditionID != conditionID {
+			continue
+		}
+		kind, detail := "MERGE TX", fmt.Sprintf("tx %s, profit $%.4f, gas $%.4f", shortID(m.TxHash), m.SpreadProfit, m.GasCostUSD)
+		if !m.Success {
+			kind, detail = "MERGE FAILED", m.Error
+		}
+		events = append(events, domain.MarketEvent{At: m.ExecutedAt, Kind: kind, Size: m.USDCReceived, Detail: detail})
+	}
+
+	if !s.shadow {
+		rewards, err := s.db.QueryContext(ctx,
+			`SELECT date, earnings FROM live_rewards WHERE condition_id = ?`, conditionID)
+		if err != nil {
+			return nil, fmt.Errorf("storage.GetLiveMarketTimeline: rewards: %w", err)
+		}
+		defer rewards.Close()
+		for rewards.Next() {
+			var date string
+			var earnings float64
+			if err := rewards.Scan(&date, &earnings); err != nil {
+				return nil, fmt.Errorf("storage.GetLiveMarketTimeline: scan reward: %w", err)
+			}
+			at, _ := time.Parse("2006-01-02", date[:min(10, len(date))])
+			events = append(events, domain.MarketEvent{At: at, Kind: "REWARD", Detail: fmt.Sprintf("paid $%.4f", earnings)})
+		}
+		if err := rewards.Err(); err != nil {
+			return nil, fmt.Errorf("storage.GetLiveMarketTimeline: %w", err)
+		}
+	}
+
+	domain.SortMarketEvents(events)
+	return events, nil
+}
+
+// finalStatusEvent records how an order ended when the schema keeps no
+// timestamp for it (cancelled, expired, resolved, flattened).
+func finalStatusEvent(orderID, side, status string) []domain.MarketEvent {
+	switch status {
+	case "OPEN", "PARTIAL", "FILLED", "MERGED", "PENDING":
+		return nil
+	}
+	return []domain.MarketEvent{{Kind: status, OrderID: orderID, Side: side}}
+}
+
+func shortID(id string) string {
+	if len(id) > 10 {
+		return id[:10]
+	}
+	return id
+}
@@ -0,0 +1,298 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveStats_MarketsWorstFirst(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+	now := time.Now().UTC()
+
+	// 0xcond: merged pair with $0.30 net profit and $0.10 paid in rewards
+	for _, side := range []string{"YES", "NO"} {
+		o := makeLiveOrder("win-"+side, "pair-win", side, domain.LiveStatusMerged)
+		o.FilledSize = 5
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xcond", PairID: "pair-win", TxHash: "0xtx", SpreadProfit: 0.30, Success: true, ExecutedAt: now,
+	}))
+	require.NoError(t, db.SaveLiveReward(ctx, domain.LiveReward{Date: now, ConditionID: "0xcond", Earnings: 0.10}))
+
+	// 0xloss: partial flattened at a $0.50 loss, the other leg cancelled
+	yes := makeLiveOrder("loss-YES", "pair-loss", "YES", domain.LiveStatusFlattened)
+	yes.ConditionID, yes.FilledSize, yes.Question = "0xloss", 5, "Losing market"
+	no := makeLiveOrder("loss-NO", "pair-loss", "NO", domain.LiveStatusCancelled)
+	no.ConditionID = "0xloss"
+	sell := makeLiveOrder("loss-SELL", "pair-loss", "YES", domain.LiveStatusFilled)
+	sell.ConditionID, sell.OrderSide, sell.RealizedPnL = "0xloss", "SELL", -0.50
+	for _, o := range []domain.LiveOrder{yes, no, sell} {
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Markets, 2)
+
+	loss, win := stats.Markets[0], stats.Markets[1]
+	assert.Equal(t, "0xloss", loss.ConditionID)
+	assert.Equal(t, 2, loss.Orders, "the unwind sell is not an entry order")
+	assert.Equal(t, 1, loss.Fills)
+	assert.Equal(t, 1, loss.Rotations)
+	assert.InDelta(t, -0.50, loss.NetPnL, 1e-9)
+
+	assert.Equal(t, "0xcond", win.ConditionID)
+	assert.Equal(t, 1, win.Merges)
+	assert.Equal(t, 0, win.Rotations)
+	assert.InDelta(t, 0.40, win.NetPnL, 1e-9)
+}
+
+func TestLiveMarketTimeline_OrdersFillsMerges(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+	placed := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	o := makeLiveOrder("o1", "p1", "YES", domain.LiveStatusMerged)
+	o.PlacedAt = placed
+	require.NoError(t, db.SaveLiveOrder(ctx, o))
+	require.NoError(t, db.SaveLiveFill(ctx, domain.LiveFill{OrderID: "o1", CLOBTradeID: "t1", Price: 0.40, Size: 5, Timestamp: placed.Add(time.Hour)}))
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xcond", PairID: "p1", TxHash: "0xtx", Success: true, ExecutedAt: placed.Add(2 * time.Hour),
+	}))
+	require.NoError(t, db.SaveLiveOrder(ctx, makeLiveOrder("o2", "p2", "NO", domain.LiveStatusCancelled)))
+
+	events, err := db.GetLiveMarketTimeline(ctx, "0xcond")
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	assert.Equal(t, []string{"PLACED", "FILL", "MERGE TX", "PLACED", "CANCELLED"}, kinds)
+}
+
+func TestPaperStats_MarketsAttribution(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	placed := time.Now().UTC().Add(-48 * time.Hour)
+	filled := placed.Add(24 * time.Hour)
+	for _, side := range []string{"YES", "NO"} {
+		require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+			ID: "m-" + side, ConditionID: "0xc1", TokenID: "tok_" + side, Side: side, PairID: "p1",
+			BidPrice: 0.45, Size: 9, PlacedAt: placed, Status: domain.PaperStatusOpen,
+			Question: "Merged market", DailyReward: 0.20,
+		}))
+		require.NoError(t, db.MarkPaperOrderFilled(ctx, "m-"+side, filled, 0.45))
+		require.NoError(t, db.MarkPaperOrderMerged(ctx, "m-"+side, filled))
+	}
+
+	stats, err := db.GetPaperStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Markets, 1)
+	m := stats.Markets[0]
+	assert.Equal(t, 2, m.Orders)
+	assert.Equal(t, 2, m.Fills)
+	assert.Equal(t, 1, m.Merges)
+	// 20 shares per side × (1 - 0.90) = $2; one day resting at $0.20/day
+	assert.InDelta(t, 2.0, m.MergeProfit, 1e-9)
+	assert.InDelta(t, 0.20, m.RewardAccrued, 1e-6)
+	assert.Equal(t, 24*time.Hour, m.TimeInMarket)
+}
+
+func TestPaperStats_CloseReasons(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	placed := time.Now().UTC().Add(-time.Hour)
+	for _, o := range []domain.VirtualOrder{
+		{ID: "s-yes", ConditionID: "0xspread", Side: "YES", PairID: "p1", Status: domain.PaperStatusOpen},
+		{ID: "s-no", ConditionID: "0xspread", Side: "NO", PairID: "p1", Status: domain.PaperStatusOpen},
+		{ID: "n-yes", ConditionID: "0xnear", Side: "YES", PairID: "p2", Status: domain.PaperStatusOpen},
+		{ID: "old", ConditionID: "0xold", Side: "YES", PairID: "p3", Status: domain.PaperStatusExpired},
+	} {
+		o.BidPrice, o.Size, o.PlacedAt = 0.45, 10, placed
+		require.NoError(t, db.SavePaperOrder(ctx, o))
+	}
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xspread", domain.CloseSpread))
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xnear", domain.CloseNearEnd))
+
+	stats, err := db.GetPaperStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.CloseReason]int{domain.CloseSpread: 1, domain.CloseNearEnd: 1}, stats.CloseReasons,
+		"counted per pair; the pre-migration expiry has no reason")
+
+	orders, err := db.GetPaperOrdersByPair(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, domain.CloseSpread, orders[0].CloseReason)
+	require.NotNil(t, orders[0].ClosedAt)
+}
+
+func TestGetPaperCooldowns_FromCloseReasons(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	placed := time.Now().UTC().Add(-time.Hour)
+	for _, cond := range []string{"0xstale", "0xnear", "0xshutdown", "0xopen"} {
+		o := domain.VirtualOrder{ID: cond, ConditionID: cond, Side: "YES", PairID: cond,
+			BidPrice: 0.45, Size: 10, PlacedAt: placed, Status: domain.PaperStatusOpen}
+		require.NoError(t, db.SavePaperOrder(ctx, o))
+	}
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xstale", domain.CloseStale))
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xnear", domain.CloseNearEnd))
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xshutdown", domain.CloseShutdown))
+
+	cooldowns, err := db.GetPaperCooldowns(ctx, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, cooldowns, 2, "a shutdown or a still-open pair does not cool down")
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), cooldowns["0xstale"], time.Minute)
+	assert.Equal(t, domain.NoReentry, cooldowns["0xnear"])
+
+	cooldowns, err = db.GetPaperCooldowns(ctx, time.Nanosecond)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"0xnear": domain.NoReentry}, cooldowns,
+		"the rotation cooldown expires, the near-end exclusion does not")
+}
+
+func TestPaperStats_SizeDominantFillRates(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	placed := time.Now().UTC().Add(-time.Hour)
+	for _, o := range []domain.VirtualOrder{
+		{ID: "n1", Status: domain.PaperStatusFilled, FilledSize: 10},
+		{ID: "n2", Status: domain.PaperStatusOpen},
+		{ID: "d1", Status: domain.PaperStatusPartial, FilledSize: 4, LevelDepth: 5, SizeDominant: true},
+		{ID: "d2", Status: domain.PaperStatusOpen, LevelDepth: 5, SizeDominant: true},
+		{ID: "d3", Status: domain.PaperStatusOpen, LevelDepth: 5, SizeDominant: true},
+	} {
+		o.ConditionID, o.Side, o.PairID = "0xc", "YES", o.ID
+		o.BidPrice, o.Size, o.PlacedAt = 0.45, 10, placed
+		require.NoError(t, db.SavePaperOrder(ctx, o))
+	}
+
+	stats, err := db.GetPaperStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.NormalOrders)
+	assert.Equal(t, 1, stats.NormalFilled)
+	assert.Equal(t, 3, stats.DominantOrders)
+	assert.Equal(t, 1, stats.DominantFilled)
+
+	orders, err := db.GetPaperOrdersByPair(ctx, "d1")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.True(t, orders[0].SizeDominant)
+	assert.InDelta(t, 5, orders[0].LevelDepth, 1e-9)
+}
+
+func TestPaperStats_FillLatency(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	placed := time.Now().UTC().Add(-10 * time.Hour).Truncate(time.Second)
+	for i, o := range []struct {
+		side  string
+		after time.Duration
+	}{{"YES", 10 * time.Minute}, {"YES", 2 * time.Hour}, {"NO", time.Hour}, {"NO", 0}} {
+		id := fmt.Sprintf("o%d", i)
+		require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+			ID: id, ConditionID: "0xc", Side: o.side, PairID: id,
+			BidPrice: 0.45, Size: 10, PlacedAt: placed, Status: domain.PaperStatusOpen,
+		}))
+		if o.after > 0 {
+			require.NoError(t, db.MarkPaperOrderFilled(ctx, id, placed.Add(o.after), 0.45))
+		}
+	}
+
+	stats, err := db.GetPaperStats(ctx)
+	require.NoError(t, err)
+	fl := stats.FillLatency
+	assert.Equal(t, 3, fl.All.Count, "open orders have no latency")
+	assert.Equal(t, 10*time.Minute, fl.All.Min)
+	assert.Equal(t, time.Hour, fl.All.Median)
+	assert.Equal(t, 2*time.Hour, fl.All.Max)
+	assert.Equal(t, 2, fl.Yes.Count)
+	assert.Equal(t, 1, fl.No.Count)
+	assert.Equal(t, time.Hour, fl.No.P90)
+}
+
+func TestLiveStats_FillLatencySkipsSells(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(ctx))
+
+	placed := time.Now().UTC().Add(-5 * time.Hour).Truncate(time.Second)
+	filled := placed.Add(90 * time.Minute)
+	buy := domain.LiveOrder{ID: "b1", ConditionID: "0xc", Side: "NO", PairID: "p1", BidPrice: 0.5, Size: 5,
+		PlacedAt: placed, FilledAt: &filled, Status: domain.LiveStatusMerged}
+	sell := buy
+	sell.ID, sell.OrderSide, sell.Status = "s1", "SELL", domain.LiveStatusFilled
+	require.NoError(t, db.SaveLiveOrder(ctx, buy))
+	require.NoError(t, db.SaveLiveOrder(ctx, sell))
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FillLatency.All.Count)
+	assert.Equal(t, 90*time.Minute, stats.FillLatency.No.Median)
+	assert.Zero(t, stats.FillLatency.Yes.Count)
+}
+
+func TestGetHourlyFillStats_GroupsByUTCHour(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+	for i, at := range []time.Time{
+		day.Add(3*time.Hour + 5*time.Minute),
+		day.Add(3*time.Hour + 50*time.Minute),
+		day.Add(-24*time.Hour + 3*time.Hour),
+		day.Add(14 * time.Hour),
+		day.Add(-30 * 24 * time.Hour), // outside the window
+	} {
+		require.NoError(t, db.SavePaperFill(ctx, domain.PaperFill{
+			OrderID: fmt.Sprintf("o%d", i), Price: 0.45, Size: 5, Timestamp: at,
+		}))
+	}
+
+	hours, err := db.GetHourlyFillStats(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, hours, 24)
+	assert.Equal(t, 3, hours[3].TotalFills)
+	assert.InDelta(t, 3.0/7, hours[3].AvgFills, 1e-9)
+	assert.Equal(t, 1, hours[14].TotalFills)
+	assert.Zero(t, hours[0].TotalFills)
+	assert.Equal(t, 23, hours[23].Hour)
+
+	_, err = db.GetHourlyFillStats(ctx, 0)
+	assert.Error(t, err)
+}
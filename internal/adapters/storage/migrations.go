@@ -0,0 +1,157 @@
+package storage
+
+// migrations.go — Versioned schema migrations.
+//
+// Each migration runs once, inside a transaction, and is recorded in
+// schema_versions. Databases created before this table existed are detected
+// by the column a migration adds: if the column is already there (older
+// ad-hoc ALTER loops, or a CREATE TABLE that already includes it) the
+// migration is recorded as a baseline instead of being re-run.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const schemaVersionsDDL = `
+CREATE TABLE IF NOT EXISTS schema_versions (
+    id          TEXT PRIMARY KEY,
+    applied_at  DATETIME NOT NULL,
+    baseline    INTEGER NOT NULL DEFAULT 0 -- 1 = found already applied, not executed
+);`
+
+// Migration is a single schema change. Table and Column are optional: when
+// set, a database that already has Table.Column is treated as having this
+// migration applied.
+type Migration struct {
+	ID     string
+	SQL    string
+	Table  string
+	Column string
+}
+
+// Migrator applies migrations in order and records them in schema_versions.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the given ordered migrations.
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Apply runs every migration not yet recorded. It stops at the first failure,
+// leaving that migration and the ones after it pending.
+func (m *Migrator) Apply(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, schemaVersionsDDL); err != nil {
+		return fmt.Errorf("storage.Migrator: schema_versions: %w", err)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return fmt.Errorf("storage.Migrator: %w", err)
+	}
+
+	for _, mig := range m.migrations {
+		if applied[mig.ID] {
+			continue
+		}
+		if mig.Column != "" {
+			exists, err := m.hasColumn(ctx, mig.Table, mig.Column)
+			if err != nil {
+				return fmt.Errorf("storage.Migrator: %s: %w", mig.ID, err)
+			}
+			if exists {
+				if err := m.record(ctx, m.db, mig.ID, true); err != nil {
+					return fmt.Errorf("storage.Migrator: %s: %w", mig.ID, err)
+				}
+				continue
+			}
+		}
+		if err := m.run(ctx, mig); err != nil {
+			return fmt.Errorf("storage.Migrator: %s: %w", mig.ID, err)
+		}
+		slog.Info("storage: migration applied", "id", mig.ID)
+	}
+	return nil
+}
+
+func (m *Migrator) run(ctx context.Context, mig Migration) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, mig.SQL); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := m.record(ctx, tx, mig.ID, false); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (m *Migrator) record(ctx context.Context, ex execer, id string, baseline bool) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT OR IGNORE INTO schema_versions (id, applied_at, baseline) VALUES (?,?,?)`,
+		id, time.Now().UTC(), boolToInt(baseline))
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]bool, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT id FROM schema_versions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		applied[id] = true
+	}
+	return applied, rows.Err()
+}
+
+// hasColumn reports whether table has the given column.
+func (m *Migrator) hasColumn(ctx context.Context, table, column string) (bool, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if strings.EqualFold(name, column) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// addColumn builds a migration that adds a column, detected by its name on
+// databases that predate schema_versions.
+func addColumn(id, table, column, def string) Migration {
+	return Migration{
+		ID:     id,
+		SQL:    fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def),
+		Table:  table,
+		Column: column,
+	}
+}
@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openRawDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countVersions(t *testing.T, db *sql.DB, baseline int) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_versions WHERE baseline = ?`, baseline).Scan(&n))
+	return n
+}
+
+func TestMigrator_AppliesOnceInOrder(t *testing.T) {
+	ctx := context.Background()
+	db := openRawDB(t)
+	_, err := db.Exec(`CREATE TABLE t (id TEXT PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	migs := []storage.Migration{
+		{ID: "001_add_a", SQL: `ALTER TABLE t ADD COLUMN a INTEGER NOT NULL DEFAULT 0`, Table: "t", Column: "a"},
+		{ID: "002_index_a", SQL: `CREATE INDEX idx_t_a ON t(a)`},
+	}
+	require.NoError(t, storage.NewMigrator(db, migs).Apply(ctx))
+	require.NoError(t, storage.NewMigrator(db, migs).Apply(ctx), "second run is a no-op")
+
+	assert.Equal(t, 2, countVersions(t, db, 0))
+	_, err = db.Exec(`INSERT INTO t (id, a) VALUES ('x', 1)`)
+	assert.NoError(t, err)
+}
+
+func TestMigrator_DetectsExistingColumnsAsBaseline(t *testing.T) {
+	ctx := context.Background()
+	db := openRawDB(t)
+	// Database created by the old ad-hoc ALTER loop: column already present.
+	_, err := db.Exec(`CREATE TABLE t (id TEXT PRIMARY KEY, a INTEGER NOT NULL DEFAULT 0)`)
+	require.NoError(t, err)
+
+	migs := []storage.Migration{
+		{ID: "001_add_a", SQL: `ALTER TABLE t ADD COLUMN a INTEGER NOT NULL DEFAULT 0`, Table: "t", Column: "a"},
+		{ID: "002_add_b", SQL: `ALTER TABLE t ADD COLUMN b TEXT`, Table: "t", Column: "b"},
+	}
+	require.NoError(t, storage.NewMigrator(db, migs).Apply(ctx))
+
+	assert.Equal(t, 1, countVersions(t, db, 1), "001 recorded without running")
+	assert.Equal(t, 1, countVersions(t, db, 0), "002 executed")
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	ctx := context.Background()
+	db := openRawDB(t)
+
+	migs := []storage.Migration{{ID: "001_bad", SQL: `ALTER TABLE missing ADD COLUMN a INTEGER`}}
+	err := storage.NewMigrator(db, migs).Apply(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "001_bad")
+	assert.Equal(t, 0, countVersions(t, db, 0))
+}
+
+func TestApplySchemas_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.ApplyPaperSchema(ctx))
+		require.NoError(t, db.ApplyLiveSchema(ctx))
+	}
+}
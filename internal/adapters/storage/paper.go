@@ -64,34 +64,33 @@ CREATE INDEX IF NOT EXISTS idx_paper_orders_cond   ON paper_orders(condition_id)
 CREATE INDEX IF NOT EXISTS idx_paper_fills_order   ON paper_fills(order_id);
 `
 
-// migrate adds columns that may not exist in older schemas.
-const paperMigrations = `
-ALTER TABLE paper_orders ADD COLUMN daily_reward REAL NOT NULL DEFAULT 0;
-ALTER TABLE paper_orders ADD COLUMN end_date DATETIME;
-ALTER TABLE paper_daily ADD COLUMN capital_deployed REAL NOT NULL DEFAULT 0;
-ALTER TABLE paper_daily ADD COLUMN markets_resolved INTEGER NOT NULL DEFAULT 0;
-ALTER TABLE paper_daily ADD COLUMN resolution_pnl REAL NOT NULL DEFAULT 0;
-`
+// paperMigrations son los cambios de esquema posteriores a paperSchema, en orden.
+var paperMigrations = []Migration{
+	addColumn("paper_001_orders_daily_reward", "paper_orders", "daily_reward", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_002_orders_end_date", "paper_orders", "end_date", "DATETIME"),
+	addColumn("paper_003_orders_merged_at", "paper_orders", "merged_at", "DATETIME"),
+	addColumn("paper_004_orders_filled_size", "paper_orders", "filled_size", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_005_daily_capital_deployed", "paper_daily", "capital_deployed", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_006_daily_markets_resolved", "paper_daily", "markets_resolved", "INTEGER NOT NULL DEFAULT 0"),
+	addColumn("paper_007_daily_resolution_pnl", "paper_daily", "resolution_pnl", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_008_daily_rotations", "paper_daily", "rotations", "INTEGER NOT NULL DEFAULT 0"),
+	addColumn("paper_009_daily_merge_profit", "paper_daily", "merge_profit", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_010_daily_compound_balance", "paper_daily", "compound_balance", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_011_orders_queue_consumed", "paper_orders", "queue_consumed", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_012_orders_queue_checked_at", "paper_orders", "queue_checked_at", "DATETIME"),
+	addColumn("paper_013_orders_close_reason", "paper_orders", "close_reason", "TEXT"),
+	addColumn("paper_014_orders_closed_at", "paper_orders", "closed_at", "DATETIME"),
+	addColumn("paper_015_orders_level_depth", "paper_orders", "level_depth", "REAL NOT NULL DEFAULT 0"),
+	addColumn("paper_016_orders_size_dominant", "paper_orders", "size_dominant", "INTEGER NOT NULL DEFAULT 0"),
+}
 
 // ApplyPaperSchema creates paper trading tables if they don't exist.
 func (s *SQLiteStorage) ApplyPaperSchema(ctx context.Context) error {
 	if _, err := s.db.ExecContext(ctx, paperSchema); err != nil {
 		return fmt.Errorf("storage.ApplyPaperSchema: %w", err)
 	}
-	// Run migrations silently — they fail if columns already exist, which is fine
-	for _, stmt := range []string{
-		"ALTER TABLE paper_orders ADD COLUMN daily_reward REAL NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_orders ADD COLUMN end_date DATETIME",
-		"ALTER TABLE paper_orders ADD COLUMN merged_at DATETIME",
-		"ALTER TABLE paper_orders ADD COLUMN filled_size REAL NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN capital_deployed REAL NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN markets_resolved INTEGER NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN resolution_pnl REAL NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN rotations INTEGER NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN merge_profit REAL NOT NULL DEFAULT 0",
-		"ALTER TABLE paper_daily ADD COLUMN compound_balance REAL NOT NULL DEFAULT 0",
-	} {
-		s.db.ExecContext(ctx, stmt) // ignore errors (column already exists)
+	if err := NewMigrator(s.db, paperMigrations).Apply(ctx); err != nil {
+		return fmt.Errorf("storage.ApplyPaperSchema: %w", err)
 	}
 	return nil
 }
@@ -106,12 +105,14 @@ func (s *SQLiteStorage) SavePaperOrder(ctx context.Context, order domain.Virtual
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO paper_orders (id, condition_id, token_id, side, bid_price, size,
 		                          pair_id, placed_at, status, filled_at, filled_price,
-		                          question, queue_ahead, daily_reward, end_date, merged_at, filled_size)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		                          question, queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		                          queue_consumed, queue_checked_at, level_depth, size_dominant)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		order.ID, order.ConditionID, order.TokenID, order.Side, order.BidPrice,
 		order.Size, order.PairID, order.PlacedAt.UTC().Format(time.RFC3339),
 		string(order.Status), nil, order.FilledPrice, order.Question,
 		order.QueueAhead, order.DailyReward, endDate, nil, order.FilledSize,
+		order.QueueConsumed, nullRFC3339(order.QueueCheckedAt), order.LevelDepth, boolToInt(order.SizeDominant),
 	)
 	if err != nil {
 		return fmt.Errorf("storage.SavePaperOrder: %w", err)
@@ -165,6 +166,20 @@ func (s *SQLiteStorage) UpdatePaperOrderQueue(ctx context.Context, orderID strin
 	return nil
 }
 
+// UpdatePaperOrderQueueConsumed stores the SELL volume counted against an
+// open order and the timestamp of the last trade included, so the next
+// checkFills pass (or a restart) only adds newer trades.
+func (s *SQLiteStorage) UpdatePaperOrderQueueConsumed(ctx context.Context, orderID string, consumed float64, checkedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE paper_orders SET queue_consumed = ?, queue_checked_at = ?
+		WHERE id = ? AND status IN ('OPEN', 'PARTIAL')`,
+		consumed, nullRFC3339(checkedAt), orderID)
+	if err != nil {
+		return fmt.Errorf("storage.UpdatePaperOrderQueueConsumed: %w", err)
+	}
+	return nil
+}
+
 // UpdatePaperOrderPartialFill updates the filled_size and status of a partially filled order.
 func (s *SQLiteStorage) UpdatePaperOrderPartialFill(ctx context.Context, orderID string, filledSize float64, filledPrice float64) error {
 	_, err := s.db.ExecContext(ctx, `
@@ -177,12 +192,13 @@ func (s *SQLiteStorage) UpdatePaperOrderPartialFill(ctx context.Context, orderID
 	return nil
 }
 
-// ExpirePaperOrders marks all OPEN and PARTIAL orders for a condition as EXPIRED.
-func (s *SQLiteStorage) ExpirePaperOrders(ctx context.Context, conditionID string) error {
+// ExpirePaperOrders marks all OPEN and PARTIAL orders for a condition as
+// EXPIRED, recording why and when.
+func (s *SQLiteStorage) ExpirePaperOrders(ctx context.Context, conditionID string, reason domain.CloseReason) error {
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE paper_orders SET status = 'EXPIRED'
+		UPDATE paper_orders SET status = 'EXPIRED', close_reason = ?, closed_at = ?
 		WHERE condition_id = ? AND status IN ('OPEN', 'PARTIAL')`,
-		conditionID,
+		string(reason), time.Now().UTC().Format(time.RFC3339), conditionID,
 	)
 	if err != nil {
 		return fmt.Errorf("storage.ExpirePaperOrders: %w", err)
@@ -209,7 +225,8 @@ func (s *SQLiteStorage) GetOpenPaperOrders(ctx context.Context) ([]domain.Virtua
 	return s.queryPaperOrders(ctx, `
 		SELECT id, condition_id, token_id, side, bid_price, size,
 		       pair_id, placed_at, status, filled_at, filled_price, question,
-		       queue_ahead, daily_reward, end_date, merged_at, filled_size
+		       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
 		FROM paper_orders WHERE status IN ('OPEN', 'PARTIAL')
 		ORDER BY placed_at DESC`)
 }
@@ -219,11 +236,23 @@ func (s *SQLiteStorage) GetPaperOrdersByPair(ctx context.Context, pairID string)
 	return s.queryPaperOrders(ctx, `
 		SELECT id, condition_id, token_id, side, bid_price, size,
 		       pair_id, placed_at, status, filled_at, filled_price, question,
-		       queue_ahead, daily_reward, end_date, merged_at, filled_size
+		       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
 		FROM paper_orders WHERE pair_id = ?
 		ORDER BY side`, pairID)
 }
 
+// GetPaperOrdersByCondition returns every order of a condition, oldest first.
+func (s *SQLiteStorage) GetPaperOrdersByCondition(ctx context.Context, conditionID string) ([]domain.VirtualOrder, error) {
+	return s.queryPaperOrders(ctx, `
+		SELECT id, condition_id, token_id, side, bid_price, size,
+		       pair_id, placed_at, status, filled_at, filled_price, question,
+		       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
+		FROM paper_orders WHERE condition_id = ?
+		ORDER BY placed_at, side`, conditionID)
+}
+
 // GetActivePaperConditions returns distinct condition_ids with at least one OPEN or PARTIAL order.
 func (s *SQLiteStorage) GetActivePaperConditions(ctx context.Context) ([]string, error) {
 	rows, err := s.db.QueryContext(ctx, `
@@ -244,20 +273,82 @@ func (s *SQLiteStorage) GetActivePaperConditions(ctx context.Context) ([]string,
 	return ids, rows.Err()
 }
 
+// GetConditionsWithOpenOrders returns the sides of each condition that still
+// hold an OPEN, PARTIAL or FILLED order. Unlike GetActivePaperConditions it
+// includes FILLED legs waiting for their counterpart, so a condition with a
+// one-sided fill is never entered twice.
+func (s *SQLiteStorage) GetConditionsWithOpenOrders(ctx context.Context) (map[string]domain.PaperConditionSides, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT condition_id, side FROM paper_orders
+		WHERE status IN ('OPEN', 'PARTIAL', 'FILLED')`)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetConditionsWithOpenOrders: %w", err)
+	}
+	defer rows.Close()
+
+	conds := make(map[string]domain.PaperConditionSides)
+	for rows.Next() {
+		var id, side string
+		if err := rows.Scan(&id, &side); err != nil {
+			return nil, err
+		}
+		sides := conds[id]
+		switch side {
+		case "YES":
+			sides.YesOpen = true
+		case "NO":
+			sides.NoOpen = true
+		}
+		conds[id] = sides
+	}
+	return conds, rows.Err()
+}
+
+// GetPaperCooldowns returns, by condition ID, the re-entry cooldowns still in
+// force: derived from the close_reason and closed_at of the pairs rotated or
+// cancelled there (see domain.CloseReason.CooldownUntil).
+func (s *SQLiteStorage) GetPaperCooldowns(ctx context.Context, cooldown time.Duration) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT condition_id, close_reason, MAX(closed_at) FROM paper_orders
+		WHERE close_reason IS NOT NULL AND closed_at IS NOT NULL
+		GROUP BY condition_id, close_reason`)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetPaperCooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	cooldowns := make(map[string]time.Time)
+	for rows.Next() {
+		var conditionID, reason, closedAt string
+		if err := rows.Scan(&conditionID, &reason, &closedAt); err != nil {
+			return nil, fmt.Errorf("storage.GetPaperCooldowns: %w", err)
+		}
+		at, _ := time.Parse(time.RFC3339, closedAt)
+		until := domain.CloseReason(reason).CooldownUntil(at, cooldown)
+		if until.After(now) && until.After(cooldowns[conditionID]) {
+			cooldowns[conditionID] = until
+		}
+	}
+	return cooldowns, rows.Err()
+}
+
 // GetAllPaperOrders returns all paper orders, optionally filtered by status.
 func (s *SQLiteStorage) GetAllPaperOrders(ctx context.Context, status string) ([]domain.VirtualOrder, error) {
 	if status != "" {
 		return s.queryPaperOrders(ctx, `
 			SELECT id, condition_id, token_id, side, bid_price, size,
 			       pair_id, placed_at, status, filled_at, filled_price, question,
-			       queue_ahead, daily_reward, end_date, merged_at, filled_size
+			       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
 			FROM paper_orders WHERE status = ?
 			ORDER BY placed_at DESC`, status)
 	}
 	return s.queryPaperOrders(ctx, `
 		SELECT id, condition_id, token_id, side, bid_price, size,
 		       pair_id, placed_at, status, filled_at, filled_price, question,
-		       queue_ahead, daily_reward, end_date, merged_at, filled_size
+		       queue_ahead, daily_reward, end_date, merged_at, filled_size,
+		       queue_consumed, queue_checked_at, close_reason, closed_at, level_depth, size_dominant
 		FROM paper_orders ORDER BY placed_at DESC`)
 }
 
@@ -501,14 +592,7 @@ func (s *SQLiteStorage) GetPaperStats(ctx context.Context) (domain.PaperStats, e
 			if p[0] == nil || p[1] == nil {
 				continue
 			}
-			spread := 1.0 - p[0].price - p[1].price
-			yesShares := p[0].size / p[0].price
-			noShares := p[1].size / p[1].price
-			mergeable := yesShares
-			if noShares < mergeable {
-				mergeable = noShares
-			}
-			stats.TotalMergeProfit += mergeable * spread
+			stats.TotalMergeProfit += pairMergeProfit(p[0].price, p[0].size, p[1].price, p[1].size)
 			stats.TotalRotations++
 		}
 	}
@@ -541,6 +625,55 @@ func (s *SQLiteStorage) GetPaperStats(ctx context.Context) (domain.PaperStats, e
 		SELECT COUNT(DISTINCT condition_id) FROM paper_orders`).Scan(&markets)
 	stats.MarketsMonitored = markets
 
+	// Fill rate split by size dominance: an order counts as filled once any
+	// of it has filled.
+	sizeRows, err := s.db.QueryContext(ctx, `
+		SELECT size_dominant, COUNT(*),
+		       SUM(CASE WHEN filled_size > 0 OR status IN ('FILLED', 'MERGED') THEN 1 ELSE 0 END)
+		FROM paper_orders GROUP BY size_dominant`)
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetPaperStats: %w", err)
+	}
+	for sizeRows.Next() {
+		var dominant, orders, filled int
+		if err := sizeRows.Scan(&dominant, &orders, &filled); err != nil {
+			sizeRows.Close()
+			return stats, fmt.Errorf("storage.GetPaperStats: %w", err)
+		}
+		if dominant != 0 {
+			stats.DominantOrders, stats.DominantFilled = orders, filled
+		} else {
+			stats.NormalOrders, stats.NormalFilled = orders, filled
+		}
+	}
+	err = sizeRows.Err()
+	sizeRows.Close()
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetPaperStats: %w", err)
+	}
+
+	// Rows expired before close_reason existed have it NULL and are skipped.
+	stats.CloseReasons, err = s.closeReasonCounts(ctx, `
+		SELECT close_reason, COUNT(DISTINCT pair_id) FROM paper_orders
+		WHERE close_reason IS NOT NULL GROUP BY close_reason`)
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetPaperStats: %w", err)
+	}
+
+	orders, err := s.GetAllPaperOrders(ctx, "")
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetPaperStats: %w", err)
+	}
+	stats.Markets = paperMarketPnL(orders, time.Now().UTC())
+
+	stats.FillLatency, err = s.fillLatencies(ctx, `
+		SELECT side, placed_at, filled_at
+		FROM paper_orders
+		WHERE status IN ('FILLED', 'MERGED') AND filled_at IS NOT NULL`)
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetPaperStats: fill latency: %w", err)
+	}
+
 	return stats, nil
 }
 
@@ -556,17 +689,20 @@ func (s *SQLiteStorage) queryPaperOrders(ctx context.Context, query string, args
 	for rows.Next() {
 		var o domain.VirtualOrder
 		var status, placedAt string
-		var filledAt, question, endDate, mergedAt sql.NullString
+		var sizeDominant int
+		var filledAt, question, endDate, mergedAt, queueCheckedAt, closeReason, closedAt sql.NullString
 
 		if err := rows.Scan(
 			&o.ID, &o.ConditionID, &o.TokenID, &o.Side, &o.BidPrice, &o.Size,
 			&o.PairID, &placedAt, &status, &filledAt, &o.FilledPrice,
 			&question, &o.QueueAhead, &o.DailyReward, &endDate, &mergedAt, &o.FilledSize,
+			&o.QueueConsumed, &queueCheckedAt, &closeReason, &closedAt, &o.LevelDepth, &sizeDominant,
 		); err != nil {
 			return nil, fmt.Errorf("storage.queryPaperOrders: scan: %w", err)
 		}
 
 		o.Status = domain.PaperOrderStatus(status)
+		o.SizeDominant = sizeDominant != 0
 		o.PlacedAt, _ = time.Parse(time.RFC3339, placedAt)
 		if question.Valid {
 			o.Question = question.String
@@ -582,8 +718,125 @@ func (s *SQLiteStorage) queryPaperOrders(ctx context.Context, query string, args
 			t, _ := time.Parse(time.RFC3339, mergedAt.String)
 			o.MergedAt = &t
 		}
+		if queueCheckedAt.Valid {
+			o.QueueCheckedAt, _ = time.Parse(time.RFC3339, queueCheckedAt.String)
+		}
+		if closeReason.Valid {
+			o.CloseReason = domain.CloseReason(closeReason.String)
+		}
+		if closedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, closedAt.String)
+			o.ClosedAt = &t
+		}
 
 		out = append(out, o)
 	}
 	return out, rows.Err()
 }
+
+// closeReasonCounts runs a "SELECT close_reason, COUNT(...) ... GROUP BY
+// close_reason" query into a per-reason map; nil when nothing was retired.
+func (s *SQLiteStorage) closeReasonCounts(ctx context.Context, query string, args ...any) (map[domain.CloseReason]int, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts map[domain.CloseReason]int
+	for rows.Next() {
+		var reason string
+		var n int
+		if err := rows.Scan(&reason, &n); err != nil {
+			return nil, err
+		}
+		if counts == nil {
+			counts = make(map[domain.CloseReason]int)
+		}
+		counts[domain.CloseReason(reason)] = n
+	}
+	return counts, rows.Err()
+}
+
+// nullRFC3339 formats t for a nullable paper_orders DATETIME column. Keeps
+// sub-second precision: queue_checked_at is compared against trade timestamps.
+func nullRFC3339(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// fillLatencies runs a "SELECT side, placed_at, filled_at" query into the
+// distribution of fill latencies, overall and per side. The difference is
+// taken in Go: live timestamps are not in a format julianday parses.
+func (s *SQLiteStorage) fillLatencies(ctx context.Context, query string, args ...any) (domain.FillLatencyStats, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.FillLatencyStats{}, err
+	}
+	defer rows.Close()
+
+	var all, yes, no []time.Duration
+	for rows.Next() {
+		var side string
+		var placed, filled time.Time
+		if err := rows.Scan(&side, &placed, &filled); err != nil {
+			return domain.FillLatencyStats{}, err
+		}
+		d := max(filled.Sub(placed), 0).Round(time.Second)
+		all = append(all, d)
+		if side == "YES" {
+			yes = append(yes, d)
+		} else {
+			no = append(no, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.FillLatencyStats{}, err
+	}
+	return domain.FillLatencyStats{
+		All: domain.NewFillLatency(all),
+		Yes: domain.NewFillLatency(yes),
+		No:  domain.NewFillLatency(no),
+	}, nil
+}
+
+// GetHourlyFillStats groups the paper fills of the last days days by UTC
+// hour of the day. Returns all 24 hours in order, with zeros for hours
+// without fills; AvgFills is per day of the window.
+func (s *SQLiteStorage) GetHourlyFillStats(ctx context.Context, days int) ([]domain.HourlyFillStats, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("storage.GetHourlyFillStats: days must be > 0 (got %d)", days)
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days).Format(time.RFC3339)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT CAST(strftime('%H', timestamp) AS INTEGER), COUNT(*)
+		FROM paper_fills WHERE timestamp >= ?
+		GROUP BY 1`, since)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetHourlyFillStats: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.HourlyFillStats, 24)
+	for h := range out {
+		out[h].Hour = h
+	}
+	for rows.Next() {
+		var hour sql.NullInt64 // NULL for a timestamp strftime cannot parse
+		var n int
+		if err := rows.Scan(&hour, &n); err != nil {
+			return nil, fmt.Errorf("storage.GetHourlyFillStats: scan: %w", err)
+		}
+		if !hour.Valid || hour.Int64 < 0 || hour.Int64 > 23 {
+			continue
+		}
+		out[hour.Int64].TotalFills = n
+		out[hour.Int64].AvgFills = float64(n) / float64(days)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage.GetHourlyFillStats: %w", err)
+	}
+	return out, nil
+}
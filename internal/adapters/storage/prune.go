@@ -0,0 +1,152 @@
+package storage
+
+// prune.go — on-demand pruning of settled paper and live orders.
+//
+// Settled orders (merged, expired, resolved, cancelled) are only read again
+// for historical P&L, which the daily summaries already hold. A row is
+// deleted only when its day is over and has a summary, and only together
+// with the rest of its pair, so no open pair loses a leg.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pruneTarget names the tables of one order book (paper or live).
+type pruneTarget struct {
+	orders  string // orders table
+	fills   string // fills table, keyed by order_id
+	daily   string // daily summaries, keyed by date (YYYY-MM-DD)
+	scope   string // extra WHERE clause on orders ('' = none)
+	args    []any  // arguments of scope
+	settled []string
+}
+
+// PruneOldPaperOrders deletes settled paper orders (and their fills) placed
+// more than age ago whose day already has a paper_daily summary. It returns
+// the number of orders deleted.
+func (s *SQLiteStorage) PruneOldPaperOrders(ctx context.Context, age time.Duration) (int, error) {
+	n, err := s.pruneOrders(ctx, age, pruneTarget{
+		orders:  "paper_orders",
+		fills:   "paper_fills",
+		daily:   "paper_daily",
+		settled: []string{"MERGED", "EXPIRED", "RESOLVED", "CANCELLED"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("storage.PruneOldPaperOrders: %w", err)
+	}
+	return n, nil
+}
+
+// PruneLiveOrders is PruneOldPaperOrders for live_orders of this storage's
+// mode (real or shadow). FLATTENED legs count as settled too.
+func (s *SQLiteStorage) PruneLiveOrders(ctx context.Context, age time.Duration) (int, error) {
+	n, err := s.pruneOrders(ctx, age, pruneTarget{
+		orders:  "live_orders",
+		fills:   "live_fills",
+		daily:   s.liveDailyTable(),
+		scope:   "shadow = ?",
+		args:    []any{s.shadowFlag()},
+		settled: []string{"MERGED", "EXPIRED", "RESOLVED", "CANCELLED", "FLATTENED"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("storage.PruneLiveOrders: %w", err)
+	}
+	return n, nil
+}
+
+// pruneOrders deletes, in one transaction, the orders of t placed before
+// now-age whose pair is fully settled and whose placement day (UTC) is over
+// and has a daily summary.
+func (s *SQLiteStorage) pruneOrders(ctx context.Context, age time.Duration, t pruneTarget) (int, error) {
+	if age <= 0 {
+		return 0, fmt.Errorf("age must be positive, got %s", age)
+	}
+	now := time.Now().UTC()
+	cutoff := now.Add(-age)
+	today := now.Format("2006-01-02")
+
+	summarized, err := s.summarizedDays(ctx, t.daily)
+	if err != nil {
+		return 0, err
+	}
+
+	scope := "1=1"
+	if t.scope != "" {
+		scope = t.scope
+	}
+	settled := "'" + strings.Join(t.settled, "','") + "'"
+	args := append([]any{cutoff}, t.args...)
+	args = append(args, t.args...)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, placed_at FROM `+t.orders+`
+		WHERE placed_at < ? AND `+scope+`
+		  AND status IN (`+settled+`)
+		  AND pair_id NOT IN (
+		      SELECT pair_id FROM `+t.orders+`
+		      WHERE `+scope+` AND status NOT IN (`+settled+`))`, args...)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for rows.Next() {
+		var (
+			id       string
+			placedAt time.Time
+		)
+		if err := rows.Scan(&id, &placedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		day := placedAt.UTC().Format("2006-01-02")
+		if day < today && summarized[day] {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+t.fills+` WHERE order_id = ?`, id); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+t.orders+` WHERE id = ?`, id); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// summarizedDays returns the dates (YYYY-MM-DD) that have a row in table.
+func (s *SQLiteStorage) summarizedDays(ctx context.Context, table string) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT date FROM `+table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := make(map[string]bool)
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days[day.UTC().Format("2006-01-02")] = true
+	}
+	return days, rows.Err()
+}
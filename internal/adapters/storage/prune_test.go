@@ -0,0 +1,85 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestPruneOldPaperOrders_OnlySummarizedSettledPairs(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	old := time.Now().UTC().AddDate(0, 0, -40)
+	noSummary := old.AddDate(0, 0, -1)
+	save := func(id, pairID string, placed time.Time, status domain.PaperOrderStatus) {
+		require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+			ID: id, ConditionID: "0xc1", TokenID: "tok", Side: "YES", PairID: pairID,
+			BidPrice: 0.45, Size: 10, PlacedAt: placed, Status: status,
+		}))
+	}
+	save("merged", "p-merged", old, domain.PaperStatusMerged)
+	save("expired", "p-merged", old, domain.PaperStatusExpired)
+	save("half-a", "p-half", old, domain.PaperStatusExpired) // pair still holds a FILLED leg
+	save("half-b", "p-half", old, domain.PaperStatusFilled)
+	save("unsummarized", "p-nosum", noSummary, domain.PaperStatusMerged)
+	save("recent", "p-recent", time.Now().UTC(), domain.PaperStatusMerged)
+	require.NoError(t, db.SavePaperDaily(ctx, domain.PaperDailySummary{Date: old}))
+
+	n, err := db.PruneOldPaperOrders(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	left, err := db.GetAllPaperOrders(ctx, "")
+	require.NoError(t, err)
+	var ids []string
+	for _, o := range left {
+		ids = append(ids, o.ID)
+	}
+	assert.ElementsMatch(t, []string{"half-a", "half-b", "unsummarized", "recent"}, ids)
+
+	_, err = db.PruneOldPaperOrders(ctx, 0)
+	assert.Error(t, err)
+}
+
+func TestPruneLiveOrders_RespectsShadowMode(t *testing.T) {
+	ctx := context.Background()
+	db := newLiveStore(t)
+	shadow := db.ShadowLive()
+
+	old := time.Now().UTC().AddDate(0, 0, -40)
+	for _, s := range []*storage.SQLiteStorage{db, shadow} {
+		o := makeLiveOrder("x", "pair-x", "YES", domain.LiveStatusMerged)
+		if s == shadow {
+			o.ID, o.PairID = "x-shadow", "pair-x-shadow"
+		}
+		o.PlacedAt = old
+		require.NoError(t, s.SaveLiveOrder(ctx, o))
+		require.NoError(t, s.SaveLiveFill(ctx, domain.LiveFill{OrderID: o.ID, Price: 0.4, Size: 5, Timestamp: old}))
+	}
+	require.NoError(t, db.SaveLiveDaily(ctx, domain.LiveDailySummary{Date: old}))
+
+	n, err := shadow.PruneLiveOrders(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "the shadow book has no summary for that day")
+
+	n, err = db.PruneLiveOrders(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	pair, err := db.GetLiveOrdersByPair(ctx, "pair-x")
+	require.NoError(t, err)
+	assert.Empty(t, pair)
+	pair, err = shadow.GetLiveOrdersByPair(ctx, "pair-x-shadow")
+	require.NoError(t, err)
+	assert.Len(t, pair, 1)
+}
@@ -9,13 +9,18 @@ package storage
 //   - Cache en memoria: evita writes si el estado no cambió (> 5% en score,
 //     o cambio de categoría/arbitraje). En un ciclo normal con 369 mercados,
 //     la mayoría no cambia → reducción ~90% de escrituras a disco.
-//   - Prune automático al arrancar: cycles > 30d, opportunities no vistas en 14d.
+//   - `score_audits`: componentes del score de cada Gold/Silver cuando se
+//     reescribe su fila en opportunities — la serie temporal de por qué un
+//     mercado sube o baja de categoría.
+//   - Prune automático al arrancar: cycles > 30d, opportunities no vistas y
+//     score_audits de hace más de 14d.
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -58,6 +63,23 @@ CREATE TABLE IF NOT EXISTS opportunities (
     peak_combined  REAL    NOT NULL DEFAULT 0
 );
 
+-- Componentes del score en cada escritura de opportunities (solo Gold/Silver)
+CREATE TABLE IF NOT EXISTS score_audits (
+    condition_id     TEXT     NOT NULL,
+    scanned_at       DATETIME NOT NULL,
+    category         TEXT     NOT NULL,
+    combined_score   REAL     NOT NULL DEFAULT 0,
+    spread_score     REAL     NOT NULL DEFAULT 0,
+    your_share       REAL     NOT NULL DEFAULT 0,
+    break_even_fills REAL,              -- NULL = infinito (fills gratis)
+    qualifies_reward INTEGER  NOT NULL DEFAULT 0,
+    spread_total     REAL     NOT NULL DEFAULT 0,
+    competition      REAL     NOT NULL DEFAULT 0,
+    your_daily_rwd   REAL     NOT NULL DEFAULT 0,
+    fill_cost_usdc   REAL     NOT NULL DEFAULT 0,
+    PRIMARY KEY (condition_id, scanned_at)
+);
+
 CREATE INDEX IF NOT EXISTS idx_cycles_at    ON cycles(scanned_at DESC);
 CREATE INDEX IF NOT EXISTS idx_opp_cat      ON opportunities(category);
 CREATE INDEX IF NOT EXISTS idx_opp_last     ON opportunities(last_seen DESC);
@@ -82,6 +104,8 @@ type SQLiteStorage struct {
 	db    *sql.DB
 	cache map[string]cachedState // conditionID → estado guardado
 	mu    sync.Mutex
+
+	shadow bool // vista de las tablas live limitada a las filas shadow (ShadowLive)
 }
 
 // NewSQLiteStorage abre (o crea) la base de datos en la ruta dada.
@@ -169,6 +193,18 @@ func (s *SQLiteStorage) SaveScan(ctx context.Context, opportunities []domain.Opp
 	}
 	defer stmt.Close()
 
+	audit, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO score_audits
+			(condition_id, scanned_at, category, combined_score, spread_score, your_share,
+			 break_even_fills, qualifies_reward, spread_total, competition, your_daily_rwd,
+			 fill_cost_usdc)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("storage.SaveScan: prepare audit: %w", err)
+	}
+	defer audit.Close()
+
 	for _, opp := range toWrite {
 		hasArb := 0
 		if opp.Arbitrage.HasArbitrage {
@@ -204,6 +240,9 @@ func (s *SQLiteStorage) SaveScan(ctx context.Context, opportunities []domain.Opp
 		); err != nil {
 			return fmt.Errorf("storage.SaveScan: upsert %s: %w", opp.Market.ConditionID, err)
 		}
+		if err := saveScoreAudit(ctx, audit, domain.NewScoreAudit(opp, now)); err != nil {
+			return fmt.Errorf("storage.SaveScan: audit %s: %w", opp.Market.ConditionID, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -261,6 +300,170 @@ func (s *SQLiteStorage) GetHistory(ctx context.Context, from, to time.Time) ([]d
 	return opps, rows.Err()
 }
 
+// GetScoreHistory devuelve la serie temporal de componentes del score de un
+// mercado, del scan más antiguo al más reciente.
+func (s *SQLiteStorage) GetScoreHistory(ctx context.Context, conditionID string) ([]domain.ScoreAudit, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT condition_id, scanned_at, category, combined_score, spread_score, your_share,
+		       break_even_fills, qualifies_reward, spread_total, competition, your_daily_rwd,
+		       fill_cost_usdc
+		FROM score_audits
+		WHERE condition_id = ?
+		ORDER BY scanned_at ASC
+	`, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetScoreHistory: query: %w", err)
+	}
+	defer rows.Close()
+
+	var audits []domain.ScoreAudit
+	for rows.Next() {
+		var a domain.ScoreAudit
+		var catStr string
+		var breakEven sql.NullFloat64
+		var qualifies int
+		if err := rows.Scan(
+			&a.ConditionID, &a.ScannedAt, &catStr, &a.CombinedScore, &a.SpreadScore, &a.YourShare,
+			&breakEven, &qualifies, &a.SpreadTotal, &a.Competition, &a.YourDailyReward,
+			&a.FillCostUSDC,
+		); err != nil {
+			return nil, fmt.Errorf("storage.GetScoreHistory: scan row: %w", err)
+		}
+		a.Category = parseCategory(catStr)
+		a.BreakEvenFills = math.Inf(1)
+		if breakEven.Valid {
+			a.BreakEvenFills = breakEven.Float64
+		}
+		a.QualifiesReward = qualifies == 1
+		audits = append(audits, a)
+	}
+	return audits, rows.Err()
+}
+
+// statsWindow es la ventana de "esta semana" de GetOpportunityStats.
+const statsWindow = 7 * 24 * time.Hour
+
+// statsTopMarkets es cuántos mercados longevos incluye GetOpportunityStats.
+const statsTopMarkets = 5
+
+// GetOpportunityStats resume la tendencia de las oportunidades guardadas: los
+// Gold más longevos, cuántos Gold hubo en la última semana y su vida media,
+// y cuántos mercados entraron o se resolvieron esta semana.
+//
+// last_seen solo avanza cuando la fila se reescribe (cambio de categoría o
+// de score ≥5%), así que las vidas son una cota inferior.
+func (s *SQLiteStorage) GetOpportunityStats(ctx context.Context) (domain.OpportunityStats, error) {
+	var stats domain.OpportunityStats
+	markets, err := s.marketSummaries(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("storage.GetOpportunityStats: %w", err)
+	}
+
+	now := time.Now().UTC()
+	weekAgo := now.Add(-statsWindow)
+	var gold []domain.MarketSummary
+	for _, m := range markets {
+		if m.FirstSeen.After(weekAgo) {
+			stats.NewThisWeek++
+		}
+		if !m.EndDate.IsZero() && m.EndDate.After(weekAgo) && !m.EndDate.After(now) {
+			stats.ResolvedThisWeek++
+		}
+		if m.Category != domain.CategoryGold {
+			continue
+		}
+		gold = append(gold, m)
+		stats.AvgGoldLifetimeHours += m.LifetimeHours
+		if m.LastSeen.After(weekAgo) {
+			stats.GoldAppearances++
+		}
+	}
+	if len(gold) > 0 {
+		stats.AvgGoldLifetimeHours /= float64(len(gold))
+	}
+
+	stats.TopByLongevity = longestLived(gold, statsTopMarkets)
+	return stats, nil
+}
+
+// GetLongestLiveGoldMarkets devuelve los mercados Gold ordenados por
+// last_seen - first_seen, los más longevos primero. limit <= 0 = todos.
+func (s *SQLiteStorage) GetLongestLiveGoldMarkets(ctx context.Context, limit int) ([]domain.MarketSummary, error) {
+	markets, err := s.marketSummaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storage.GetLongestLiveGoldMarkets: %w", err)
+	}
+	var gold []domain.MarketSummary
+	for _, m := range markets {
+		if m.Category == domain.CategoryGold {
+			gold = append(gold, m)
+		}
+	}
+	return longestLived(gold, limit), nil
+}
+
+// marketSummaries lee todas las filas de opportunities. La vida se calcula
+// en Go: los DATETIME se guardan en el formato de time.Time, que las
+// funciones de fecha de SQLite no entienden.
+func (s *SQLiteStorage) marketSummaries(ctx context.Context) ([]domain.MarketSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT condition_id, question, slug, category, first_seen, last_seen, end_date, peak_combined
+		FROM opportunities
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	var markets []domain.MarketSummary
+	for rows.Next() {
+		var m domain.MarketSummary
+		var question, slug sql.NullString
+		var catStr string
+		var endDate sql.NullTime
+		if err := rows.Scan(&m.ConditionID, &question, &slug, &catStr,
+			&m.FirstSeen, &m.LastSeen, &endDate, &m.PeakCombined); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		m.Question, m.Slug = question.String, slug.String
+		m.Category = parseCategory(catStr)
+		if endDate.Valid {
+			m.EndDate = endDate.Time
+		}
+		m.LifetimeHours = m.LastSeen.Sub(m.FirstSeen).Hours()
+		markets = append(markets, m)
+	}
+	return markets, rows.Err()
+}
+
+// longestLived ordena por vida descendente y corta a limit (<= 0 = todos).
+func longestLived(markets []domain.MarketSummary, limit int) []domain.MarketSummary {
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].LifetimeHours > markets[j].LifetimeHours
+	})
+	if limit > 0 && len(markets) > limit {
+		markets = markets[:limit]
+	}
+	return markets
+}
+
+// CheckWritable comprueba que la base de datos acepta escrituras (disco
+// lleno, fichero de solo lectura o bloqueado por otro proceso) escribiendo
+// una fila de control. Lo usa el endpoint de salud.
+func (s *SQLiteStorage) CheckWritable(ctx context.Context) error {
+	const q = `
+		CREATE TABLE IF NOT EXISTS health_check (
+			id         INTEGER PRIMARY KEY CHECK (id = 1),
+			checked_at TEXT NOT NULL
+		);
+		INSERT INTO health_check (id, checked_at) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET checked_at = excluded.checked_at`
+	if _, err := s.db.ExecContext(ctx, q, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("storage.CheckWritable: %w", err)
+	}
+	return nil
+}
+
 // Close cierra la conexión a la base de datos.
 func (s *SQLiteStorage) Close() error {
 	return s.db.Close()
@@ -305,12 +508,41 @@ func (s *SQLiteStorage) filterChanged(opps []domain.Opportunity, _ time.Time) []
 	return toWrite
 }
 
+// saveScoreAudit inserta una fila de score_audits con el statement preparado.
+func saveScoreAudit(ctx context.Context, stmt *sql.Stmt, a domain.ScoreAudit) error {
+	var breakEven *float64
+	if !math.IsInf(a.BreakEvenFills, 0) && !math.IsNaN(a.BreakEvenFills) {
+		breakEven = &a.BreakEvenFills
+	}
+	qualifies := 0
+	if a.QualifiesReward {
+		qualifies = 1
+	}
+	_, err := stmt.ExecContext(ctx,
+		a.ConditionID, a.ScannedAt.UTC(), a.Category.String(), a.CombinedScore, a.SpreadScore, a.YourShare,
+		breakEven, qualifies, a.SpreadTotal, a.Competition, a.YourDailyReward,
+		a.FillCostUSDC,
+	)
+	return err
+}
+
+// parseCategory es la inversa de OpportunityCategory.String.
+func parseCategory(s string) domain.OpportunityCategory {
+	for _, c := range []domain.OpportunityCategory{domain.CategoryGold, domain.CategorySilver, domain.CategoryBronze} {
+		if c.String() == s {
+			return c
+		}
+	}
+	return domain.CategoryAvoid
+}
+
 // pruneOld elimina datos antiguos para mantener la DB ligera.
 func (s *SQLiteStorage) pruneOld(ctx context.Context) {
 	cutoffCycles := time.Now().UTC().Add(-retentionCycles)
 	cutoffOpps := time.Now().UTC().Add(-retentionOpps)
 	s.db.ExecContext(ctx, `DELETE FROM cycles WHERE scanned_at < ?`, cutoffCycles)
 	s.db.ExecContext(ctx, `DELETE FROM opportunities WHERE last_seen < ?`, cutoffOpps)
+	s.db.ExecContext(ctx, `DELETE FROM score_audits WHERE scanned_at < ?`, cutoffOpps)
 }
 
 // warmCache precarga la caché desde la DB al arrancar, evitando escrituras
@@ -2,6 +2,7 @@ package storage_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -81,6 +82,74 @@ func TestSQLiteStorage_SaveAndGetHistory(t *testing.T) {
 	assert.Equal(t, "0xaaa", history[0].Market.ConditionID)
 }
 
+func TestSQLiteStorage_ScoreHistory(t *testing.T) {
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	gold := makeGoldOpp("0xaaa", 1.0)
+	gold.SpreadScore = 0.25
+	gold.YourShare = 0.02
+	gold.BreakEvenFills = math.Inf(1)
+	require.NoError(t, db.SaveScan(ctx, []domain.Opportunity{gold}))
+
+	// Sin cambios relevantes: no se reescribe ni se audita
+	require.NoError(t, db.SaveScan(ctx, []domain.Opportunity{gold}))
+
+	silver := makeSilverOpp("0xaaa", 0.6)
+	silver.BreakEvenFills = 3.5
+	require.NoError(t, db.SaveScan(ctx, []domain.Opportunity{silver, makeGoldOpp("0xbbb", 2.0)}))
+
+	history, err := db.GetScoreHistory(ctx, "0xaaa")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, domain.CategoryGold, history[0].Category)
+	assert.InDelta(t, 0.25, history[0].SpreadScore, 1e-9)
+	assert.InDelta(t, 0.02, history[0].YourShare, 1e-9)
+	assert.True(t, math.IsInf(history[0].BreakEvenFills, 1), "NULL se lee como infinito")
+	assert.True(t, history[0].QualifiesReward)
+
+	assert.Equal(t, domain.CategorySilver, history[1].Category)
+	assert.InDelta(t, 0.6, history[1].CombinedScore, 1e-9)
+	assert.InDelta(t, 3.5, history[1].BreakEvenFills, 1e-9)
+	assert.True(t, history[1].ScannedAt.After(history[0].ScannedAt))
+}
+
+func TestSQLiteStorage_OpportunityStats(t *testing.T) {
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+
+	resolved := makeSilverOpp("0xccc", 0.5)
+	resolved.Market.EndDate = time.Now().Add(-24 * time.Hour)
+	require.NoError(t, db.SaveScan(ctx, []domain.Opportunity{
+		makeGoldOpp("0xaaa", 1.0), makeGoldOpp("0xbbb", 1.0), resolved,
+	}))
+
+	// Solo 0xaaa cambia lo suficiente como para reescribirse → vive más
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, db.SaveScan(ctx, []domain.Opportunity{makeGoldOpp("0xaaa", 2.0)}))
+
+	longest, err := db.GetLongestLiveGoldMarkets(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, longest, 2, "solo mercados Gold")
+	assert.Equal(t, "0xaaa", longest[0].ConditionID)
+	assert.Greater(t, longest[0].LifetimeHours, longest[1].LifetimeHours)
+	assert.InDelta(t, 2.0, longest[0].PeakCombined, 1e-9)
+
+	stats, err := db.GetOpportunityStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.GoldAppearances)
+	assert.Equal(t, 3, stats.NewThisWeek)
+	assert.Equal(t, 1, stats.ResolvedThisWeek)
+	assert.Greater(t, stats.AvgGoldLifetimeHours, 0.0)
+	require.Len(t, stats.TopByLongevity, 2)
+	assert.Equal(t, "0xaaa", stats.TopByLongevity[0].ConditionID)
+}
+
 func TestSQLiteStorage_SaveEmptySlice(t *testing.T) {
 	db, err := storage.NewSQLiteStorage(":memory:")
 	require.NoError(t, err)
@@ -90,6 +159,17 @@ func TestSQLiteStorage_SaveEmptySlice(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSQLiteStorage_CheckWritable(t *testing.T) {
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+
+	require.NoError(t, db.CheckWritable(context.Background()))
+	require.NoError(t, db.CheckWritable(context.Background()), "the control row is upserted")
+
+	db.Close()
+	assert.Error(t, db.CheckWritable(context.Background()))
+}
+
 func TestSQLiteStorage_GetHistory_EmptyRange(t *testing.T) {
 	db, err := storage.NewSQLiteStorage(":memory:")
 	require.NoError(t, err)
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"math"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
@@ -12,18 +13,68 @@ type ScannerService interface {
 	RunOnce(ctx context.Context) ([]domain.Opportunity, error)
 }
 
+// MarketAllower decide si un mercado puede operarse (lista negra/blanca,
+// ver scanner.MarketListSource). Los engines la consultan antes de colocar.
+type MarketAllower interface {
+	Allows(m domain.Market) bool
+}
+
 // QueuePosition devuelve el valor en USDC de los bids al mismo nivel de precio.
 // FIFO dentro de un nivel de precio: solo los bids al mismo precio están delante.
+// Los precios se comparan redondeados al céntimo (tick del CLOB).
 func QueuePosition(book domain.OrderBook, bidPrice float64) float64 {
+	level := centPrice(bidPrice)
 	total := 0.0
 	for _, entry := range book.Bids {
-		if abs64(entry.Price-bidPrice) < 0.001 {
+		if centPrice(entry.Price) == level {
 			total += entry.Size * entry.Price
 		}
 	}
 	return total
 }
 
+// QueuePositionFull devuelve el valor en USDC de los bids al mismo precio o
+// superior: los de precio mayor se llenan antes que nosotros, así que es la
+// estimación conservadora de la cola por delante.
+func QueuePositionFull(book domain.OrderBook, bidPrice float64) float64 {
+	level := centPrice(bidPrice)
+	total := 0.0
+	for _, entry := range book.Bids {
+		if centPrice(entry.Price) >= level {
+			total += entry.Size * entry.Price
+		}
+	}
+	return total
+}
+
+// StartBid es el bid desde el que arranca la optimización de una orden de
+// orderSize USDC: el VWAP de los primeros orderSize USDC de bids, bajado al
+// tick. Un primer nivel con poco depth no hace subir el punto de partida por
+// encima de donde está de verdad la liquidez. Sin bids, un 1% bajo el ask.
+func StartBid(book domain.OrderBook, orderSize float64) float64 {
+	vwap := book.VWAP("bid", orderSize)
+	if vwap == 0 {
+		return book.BestAsk() * 0.99
+	}
+	return math.Floor(vwap*100+1e-9) / 100
+}
+
+// QueueAhead es la cola delante de un bid a bidPrice para estimar cuándo se
+// llena. En el mejor bid (o por encima) solo cuenta su nivel; por debajo,
+// todo el depth de los niveles superiores tiene que consumirse antes
+// (QueuePositionFull).
+func QueueAhead(book domain.OrderBook, bidPrice float64) float64 {
+	if centPrice(bidPrice) >= centPrice(book.BestBid()) {
+		return QueuePosition(book, bidPrice)
+	}
+	return QueuePositionFull(book, bidPrice)
+}
+
+// centPrice normaliza un precio al céntimo.
+func centPrice(p float64) float64 {
+	return math.Round(p*100) / 100
+}
+
 // TruncateStr trunca un string a maxLen caracteres añadiendo "..." si es necesario.
 func TruncateStr(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -31,10 +82,3 @@ func TruncateStr(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
-
-func abs64(x float64) float64 {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
@@ -0,0 +1,82 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func queueBook() domain.OrderBook {
+	return domain.OrderBook{Bids: []domain.BookEntry{
+		{Price: 0.49, Size: 100},
+		{Price: 0.4700000001, Size: 100}, // mismo tick que 0.47
+		{Price: 0.47, Size: 100},
+		{Price: 0.46, Size: 100},
+	}}
+}
+
+func TestQueuePosition_SameCentLevel(t *testing.T) {
+	assert.InDelta(t, 94, engine.QueuePosition(queueBook(), 0.47), 1e-6)
+	assert.InDelta(t, 0, engine.QueuePosition(queueBook(), 0.48), 1e-6)
+}
+
+func TestQueueAhead_JoinsTopOrWaitsBehindHigherLevels(t *testing.T) {
+	assert.InDelta(t, 49, engine.QueueAhead(queueBook(), 0.49), 1e-6, "at the best bid only its level")
+	assert.InDelta(t, 0, engine.QueueAhead(queueBook(), 0.50), 1e-6, "above the book nothing is ahead")
+	assert.InDelta(t, 143, engine.QueueAhead(queueBook(), 0.47), 1e-6, "below the top the higher levels clear first")
+}
+
+func TestQueuePositionFull_IncludesHigherBids(t *testing.T) {
+	assert.InDelta(t, 143, engine.QueuePositionFull(queueBook(), 0.47), 1e-6)
+	assert.InDelta(t, 49, engine.QueuePositionFull(queueBook(), 0.48), 1e-6)
+	assert.InDelta(t, 0, engine.QueuePositionFull(queueBook(), 0.50), 1e-6)
+}
+
+func TestStartBid_VWAPOfOrderSize(t *testing.T) {
+	book := domain.OrderBook{Bids: []domain.BookEntry{{Price: 0.48, Size: 10}, {Price: 0.47, Size: 1000}}}
+
+	assert.InDelta(t, 0.47, engine.StartBid(book, 50), 1e-9, "$4.80 on top does not carry a $50 order")
+	assert.InDelta(t, 0.48, engine.StartBid(book, 4), 1e-9, "a small order fits at the top")
+	noBids := domain.OrderBook{Asks: []domain.BookEntry{{Price: 0.50, Size: 10}}}
+	assert.InDelta(t, 0.495, engine.StartBid(noBids, 50), 1e-9, "no bids: just under the ask")
+}
+
+func TestLadderConfig_Levels(t *testing.T) {
+	single := engine.LadderConfig{}.Levels()
+	assert.Equal(t, []engine.LadderLevel{{Index: 0, Offset: 0, Fraction: 1}}, single)
+
+	weighted := engine.LadderConfig{NumLevels: 3, TickSpacing: 0.01, SizeDistribution: []float64{2, 1, 1}}.Levels()
+	if assert.Len(t, weighted, 3) {
+		assert.InDelta(t, 0.5, weighted[0].Fraction, 1e-9)
+		assert.InDelta(t, 0.25, weighted[2].Fraction, 1e-9)
+		assert.InDelta(t, 0.02, weighted[2].Offset, 1e-9)
+	}
+
+	// Una distribución con otro número de niveles se ignora: partes iguales.
+	even := engine.LadderConfig{NumLevels: 2, TickSpacing: 0.01, SizeDistribution: []float64{1}}.Levels()
+	if assert.Len(t, even, 2) {
+		assert.InDelta(t, 0.5, even[0].Fraction, 1e-9)
+		assert.InDelta(t, 0.5, even[1].Fraction, 1e-9)
+	}
+}
+
+func TestLadderPairID_Group(t *testing.T) {
+	assert.Equal(t, "abc", engine.LadderPairID("abc", 0, 1))
+	assert.Equal(t, "abc", engine.PairGroup(engine.LadderPairID("abc", 0, 1)))
+	assert.Equal(t, "abc/L2", engine.LadderPairID("abc", 2, 3))
+	assert.Equal(t, "abc", engine.PairGroup("abc/L2"))
+}
+
+func TestKellyConfig_FractionAndDefaults(t *testing.T) {
+	def := engine.KellyConfig{Multiplier: 0.5, Min: 0.1, Max: 0.8, WarmupMerges: 3}
+	quarter := engine.KellyConfig{Multiplier: 0.25}.WithDefaults(def)
+
+	assert.Equal(t, engine.KellyConfig{Multiplier: 0.25, Min: 0.1, Max: 0.8, WarmupMerges: 3}, quarter)
+	assert.InDelta(t, 0.15, quarter.Fraction(0.6), 1e-9)
+	assert.InDelta(t, 0.1, quarter.Fraction(0.2), 1e-9, "floored at Min")
+	assert.InDelta(t, 0.8, quarter.Fraction(4), 1e-9, "capped at Max")
+	assert.Equal(t, def, engine.KellyConfig{Min: 0.9, Max: 0.5}.WithDefaults(def), "min > max falls back")
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// KellyConfig ajusta cómo convierte un engine su Kelly completo en la
+// fracción del bankroll a desplegar: se multiplica por Multiplier (0.5 =
+// half-Kelly, 0.25 = quarter-Kelly) y se acota a [Min, Max]. Hasta tener
+// WarmupMerges merges el engine no estima Kelly y usa su fracción por
+// defecto, también acotada.
+type KellyConfig struct {
+	Multiplier   float64
+	Min          float64
+	Max          float64
+	WarmupMerges int
+}
+
+// WithDefaults rellena con def los campos a cero (o fuera de rango) de c.
+func (c KellyConfig) WithDefaults(def KellyConfig) KellyConfig {
+	if c.Multiplier <= 0 || c.Multiplier > 1 {
+		c.Multiplier = def.Multiplier
+	}
+	if c.Min <= 0 {
+		c.Min = def.Min
+	}
+	if c.Max <= 0 {
+		c.Max = def.Max
+	}
+	if c.Min > c.Max {
+		c.Min, c.Max = def.Min, def.Max
+	}
+	if c.WarmupMerges <= 0 {
+		c.WarmupMerges = def.WarmupMerges
+	}
+	return c
+}
+
+// Fraction aplica Multiplier al Kelly completo full y lo acota a [Min, Max].
+func (c KellyConfig) Fraction(full float64) float64 {
+	return c.Clamp(full * c.Multiplier)
+}
+
+// Clamp acota f a [Min, Max].
+func (c KellyConfig) Clamp(f float64) float64 {
+	return math.Max(c.Min, math.Min(f, c.Max))
+}
+
+// FormatKelly da formato de log a un Kelly completo; NaN (sin estimación)
+// sale como "n/a".
+func FormatKelly(full float64) string {
+	if math.IsNaN(full) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", full*100)
+}
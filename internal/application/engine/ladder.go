@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ladderSep separa el pairID base del índice de nivel en los pares de un ladder.
+const ladderSep = "/L"
+
+// LadderConfig reparte el tamaño de orden de un mercado en NumLevels pares
+// YES+NO: el nivel 0 va al bid optimizado y cada nivel siguiente TickSpacing
+// más abajo en ambos lados. SizeDistribution da la fracción de OrderSize de
+// cada nivel (se normaliza; vacío = a partes iguales). NumLevels ≤ 1 = un
+// único par, como sin ladder.
+type LadderConfig struct {
+	NumLevels        int
+	TickSpacing      float64
+	SizeDistribution []float64
+}
+
+// LadderLevel es un nivel del ladder: cuánto baja el bid respecto al
+// optimizado y qué parte del tamaño de orden lleva.
+type LadderLevel struct {
+	Index    int
+	Offset   float64
+	Fraction float64
+}
+
+// Levels devuelve los niveles a colocar; siempre al menos uno.
+func (c LadderConfig) Levels() []LadderLevel {
+	n := max(c.NumLevels, 1)
+	weights := c.SizeDistribution
+	if len(weights) != n {
+		weights = nil
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	levels := make([]LadderLevel, n)
+	for i := range levels {
+		frac := 1 / float64(n)
+		if total > 0 {
+			frac = weights[i] / total
+		}
+		levels[i] = LadderLevel{Index: i, Offset: float64(i) * c.TickSpacing, Fraction: frac}
+	}
+	return levels
+}
+
+// LadderPairID es el pairID del nivel level de un par: sin ladder (un solo
+// nivel) es el propio base, así los pares de siempre no cambian.
+func LadderPairID(base string, level, numLevels int) string {
+	if numLevels <= 1 {
+		return base
+	}
+	return fmt.Sprintf("%s%s%d", base, ladderSep, level)
+}
+
+// PairGroup devuelve el pairID base que comparten todos los niveles de un
+// ladder; para un par sin ladder es el propio pairID.
+func PairGroup(pairID string) string {
+	if i := strings.LastIndex(pairID, ladderSep); i > 0 {
+		return pairID[:i]
+	}
+	return pairID
+}
@@ -0,0 +1,93 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// ReconcileBalance compares the wallets' USDC balance with the balance implied
+// by live_merges, live_orders, realized unwinds and paid rewards. It only
+// reads storage and asks each wallet for its balance; nothing is changed.
+// Drift beyond 1% of InitialCapital (at least $1) that gas does not explain
+// is flagged.
+func (le *Engine) ReconcileBalance(ctx context.Context) (domain.BalanceReconciliation, error) {
+	r := domain.BalanceReconciliation{
+		InitialCapital: le.cfg.InitialCapital,
+		Tolerance:      math.Max(balanceDriftMinUSD, le.cfg.InitialCapital*balanceDriftPct),
+	}
+
+	var lastErr error
+	for _, w := range le.wallets {
+		bal, err := w.Executor.GetBalance(ctx)
+		if err != nil {
+			slog.Warn("live: could not read wallet balance", "wallet", shortAddr(w.Address), "err", err)
+			r.SkippedWallets++
+			lastErr = err
+			continue
+		}
+		r.WalletBalance += bal
+	}
+	if r.SkippedWallets == len(le.wallets) {
+		return r, fmt.Errorf("live.ReconcileBalance: get balance: %w", lastErr)
+	}
+
+	merges, err := le.store.GetMergeResults(ctx)
+	if err != nil {
+		return r, fmt.Errorf("live.ReconcileBalance: %w", err)
+	}
+	for _, m := range merges {
+		if !m.Success {
+			r.FailedMerges++
+			continue
+		}
+		r.Merges++
+		r.MergeReceipts += m.USDCReceived
+		r.MergeProfit += m.SpreadProfit
+		r.GasSpent += m.GasCostUSD
+	}
+
+	if r.RealizedPnL, err = le.store.GetRealizedPnL(ctx); err != nil {
+		return r, fmt.Errorf("live.ReconcileBalance: %w", err)
+	}
+	stats, err := le.store.GetLiveStats(ctx)
+	if err != nil {
+		return r, fmt.Errorf("live.ReconcileBalance: %w", err)
+	}
+	r.RewardsPaid = stats.RealizedReward
+
+	open, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return r, fmt.Errorf("live.ReconcileBalance: %w", err)
+	}
+	filled, err := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusFilled))
+	if err != nil {
+		return r, fmt.Errorf("live.ReconcileBalance: %w", err)
+	}
+	for _, o := range append(open, filled...) {
+		if o.IsSell() {
+			continue
+		}
+		switch o.Status {
+		case domain.LiveStatusOpen:
+			r.RestingBids += o.Size
+		case domain.LiveStatusPartial:
+			r.RestingBids += o.Size - o.FilledSize
+			r.LockedPartial += o.UnmergedSize()
+		case domain.LiveStatusFilled:
+			r.LockedFilled += o.UnmergedSize()
+		}
+	}
+
+	if r.Flagged() {
+		slog.Warn("live: wallet balance drifts from the books",
+			"wallet", fmt.Sprintf("$%.2f", r.WalletBalance),
+			"expected", fmt.Sprintf("$%.2f", r.Expected()),
+			"unexplained", fmt.Sprintf("$%.2f", r.Unexplained()),
+		)
+	}
+	return r, nil
+}
@@ -0,0 +1,69 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// seedBooks stores one merge (+$0.90 net of $0.10 gas), one failed merge, a
+// $5 resting bid, a $5 bid that has filled $2 and a $4.50 filled leg not
+// merged yet.
+func seedBooks(t *testing.T, db *storage.SQLiteStorage) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xa", PairID: "p1", TxHash: "0x1", GasCostUSD: 0.10,
+		USDCReceived: 10, SpreadProfit: 0.90, Success: true, ExecutedAt: now,
+	}))
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xa", PairID: "p1", TxHash: "0x0", Error: "reverted", ExecutedAt: now,
+	}))
+	for _, o := range []domain.LiveOrder{
+		{ID: "open", PairID: "p2", Side: "YES", BidPrice: 0.5, Size: 5, Status: domain.LiveStatusOpen},
+		{ID: "partial", PairID: "p4", Side: "YES", BidPrice: 0.5, Size: 5, FilledSize: 2, Status: domain.LiveStatusPartial},
+		{ID: "filled", PairID: "p3", Side: "NO", BidPrice: 0.45, Size: 4.5, FilledSize: 4.5, Status: domain.LiveStatusFilled, FilledAt: &now},
+	} {
+		o.ConditionID, o.TokenID, o.PlacedAt = "0xb", "tok_"+o.Side, now
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+}
+
+func TestReconcileBalance_Breakdown(t *testing.T) {
+	le, db := newTestEngine(t, newShadowExecutor(nil, 94.50), Config{OrderSize: 5})
+	le.cfg.InitialCapital = 100
+	seedBooks(t, db)
+
+	r, err := le.ReconcileBalance(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, r.Merges)
+	assert.Equal(t, 1, r.FailedMerges)
+	assert.InDelta(t, 10, r.MergeReceipts, 1e-9)
+	assert.InDelta(t, 0.10, r.GasSpent, 1e-9)
+	assert.InDelta(t, 8, r.RestingBids, 1e-9)
+	assert.InDelta(t, 2, r.LockedPartial, 1e-9)
+	assert.InDelta(t, 4.5, r.LockedFilled, 1e-9)
+	assert.InDelta(t, 94.40, r.Expected(), 1e-9, "resting bids still hold their USDC")
+	assert.InDelta(t, 0.10, r.Delta(), 1e-9)
+	assert.InDelta(t, 0, r.Unexplained(), 1e-9, "gas paid in POL explains the delta")
+	assert.False(t, r.Flagged())
+}
+
+func TestReconcileBalance_FlagsDrift(t *testing.T) {
+	le, db := newTestEngine(t, newShadowExecutor(nil, 88), Config{OrderSize: 5})
+	le.cfg.InitialCapital = 100
+	seedBooks(t, db)
+
+	r, err := le.ReconcileBalance(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, -6.5, r.Unexplained(), 1e-9)
+	assert.True(t, r.Flagged())
+}
@@ -0,0 +1,67 @@
+package live
+
+// balancecheck.go — in-cycle balance pre-check.
+//
+// Wallet balances are read once at the start of RunOnce and decremented
+// locally as pairs are placed. When several pairs go out in one cycle the
+// estimate drifts from what the exchange holds (fees, fills, a withdrawal,
+// another process using the wallet), so before placing from a wallet whose
+// estimated headroom is down to a couple of pairs the balance is read again
+// and the estimate replaced with it.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+)
+
+const (
+	// balanceReserveUSDC is left untouched in every wallet.
+	balanceReserveUSDC = 0.5
+	// balanceRecheckPairs is how many pairs of estimated balance trigger a
+	// fresh read: below orderSize*2 per pair times this, re-check.
+	balanceRecheckPairs = 2
+	// balanceDriftWarn is the relative gap between estimate and fresh
+	// balance that is logged as a warning.
+	balanceDriftWarn = 0.10
+)
+
+// checkBalanceSufficient reports whether ws can fund one more pair of
+// orderSize per side. While the estimated balance covers balanceRecheckPairs
+// pairs it is trusted; below that the tradeable balance is fetched again,
+// compared with the estimate and stored in ws.
+func (le *Engine) checkBalanceSufficient(ctx context.Context, ws *walletState, orderSize float64) (bool, error) {
+	pair := orderSize * 2
+	if ws.freshBalance || ws.Balance >= pair*balanceRecheckPairs {
+		return ws.Balance-pair >= balanceReserveUSDC, nil
+	}
+
+	tb, err := ws.Executor.GetTradeableBalance(ctx)
+	if err != nil {
+		return false, fmt.Errorf("checkBalanceSufficient: %w", err)
+	}
+	fresh := tb.Available()
+	if drift := math.Abs(fresh - ws.Balance); drift > balanceDriftWarn*math.Abs(ws.Balance) {
+		slog.Warn("live: WARNING wallet balance differs from the cycle estimate",
+			"wallet", shortAddr(ws.Address),
+			"estimated", fmt.Sprintf("$%.2f", ws.Balance),
+			"actual", fmt.Sprintf("$%.2f", fresh),
+			"drift", fmt.Sprintf("$%.2f", drift),
+		)
+	}
+	ws.Balance = fresh
+	ws.freshBalance = true
+	return fresh-pair >= balanceReserveUSDC, nil
+}
+
+// canFundPair reports whether any wallet still has room for a pair of
+// orderSize per side after the balance reserve.
+func canFundPair(states []walletState, orderSize float64) bool {
+	for _, s := range states {
+		if s.headroom() >= orderSize*2 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package live
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBalanceSufficient_TrustsEstimateWithRoom(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 3), Config{OrderSize: 5})
+	ws := &walletState{Wallet: le.wallets[0], Balance: 100}
+
+	ok, err := le.checkBalanceSufficient(context.Background(), ws, 5)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 100.0, ws.Balance, "estimate covers two pairs, no fresh read")
+	assert.False(t, ws.freshBalance)
+}
+
+func TestCheckBalanceSufficient_RereadsWhenLow(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 8), Config{OrderSize: 5})
+	ws := &walletState{Wallet: le.wallets[0], Balance: 15}
+
+	ok, err := le.checkBalanceSufficient(context.Background(), ws, 5)
+	require.NoError(t, err)
+	assert.False(t, ok, "the wallet really holds $8, short of a $10 pair")
+	assert.Equal(t, 8.0, ws.Balance)
+	assert.True(t, ws.freshBalance)
+
+	ws.Balance = 12
+	ok, err = le.checkBalanceSufficient(context.Background(), ws, 5)
+	require.NoError(t, err)
+	assert.True(t, ok, "no second read until a placement spends the balance")
+}
+
+func TestCanFundPair(t *testing.T) {
+	states := []walletState{{Balance: 8}, {Balance: 10.5}}
+	assert.True(t, canFundPair(states, 5))
+	assert.False(t, canFundPair(states, 6))
+
+	states[1].LowGas = true
+	assert.False(t, canFundPair(states, 5))
+}
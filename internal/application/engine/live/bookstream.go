@@ -0,0 +1,98 @@
+package live
+
+import (
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// streamPair links a streamed token back to its market so a YES/NO book pair
+// can be turned into a spread sample.
+type streamPair struct {
+	ConditionID string
+	YesToken    string
+	NoToken     string
+	FeeRate     float64
+}
+
+// consumeBookUpdates records a spread sample for every streamed book update
+// until the stream closes its channel. New starts it in the background when
+// Config.BookStream is set; each cycle then points the stream at the scanned
+// markets. Samples are throttled per market to spreadStreamMinGap so bursts
+// do not flush the history window.
+func (le *Engine) consumeBookUpdates(books <-chan domain.OrderBook) {
+	for ob := range books {
+		le.applyBookUpdate(ob, time.Now())
+	}
+}
+
+// applyBookUpdate stores the book and, once both sides of the market are
+// known, appends a spread sample computed like the scanner does.
+func (le *Engine) applyBookUpdate(ob domain.OrderBook, now time.Time) {
+	le.spreadMu.Lock()
+	defer le.spreadMu.Unlock()
+
+	pair, ok := le.streamPairs[ob.TokenID]
+	if !ok {
+		return
+	}
+	le.streamBooks[ob.TokenID] = ob
+
+	yes, okYes := le.streamBooks[pair.YesToken]
+	no, okNo := le.streamBooks[pair.NoToken]
+	if !okYes || !okNo {
+		return
+	}
+	yesAsk, noAsk := yes.BestAsk(), no.BestAsk()
+	if yesAsk == 0 || noAsk == 0 {
+		return
+	}
+
+	history := le.spreadHistory[pair.ConditionID]
+	if n := len(history); n > 0 && now.Sub(history[n-1].ScannedAt) < spreadStreamMinGap {
+		return
+	}
+
+	yesBid, noBid := yes.BestBid(), no.BestBid()
+	if yesBid == 0 {
+		yesBid = yesAsk
+	}
+	if noBid == 0 {
+		noBid = noAsk
+	}
+
+	le.appendSpreadSample(pair.ConditionID, spreadSample{
+		SpreadTotal: domain.SpreadTotal(yesAsk, noAsk),
+		FillCost:    domain.FillCostPerEvent(yesBid, noBid, pair.FeeRate),
+		ScannedAt:   now,
+	})
+}
+
+// watchStreamMarkets points the book stream at the scanned markets and drops
+// cached books for tokens no longer watched. Callers must hold spreadMu.
+func (le *Engine) watchStreamMarkets(opps []domain.Opportunity) {
+	pairs := make(map[string]streamPair, 2*len(opps))
+	tokens := make([]string, 0, 2*len(opps))
+	for _, opp := range opps {
+		p := streamPair{
+			ConditionID: opp.Market.ConditionID,
+			YesToken:    opp.Market.YesToken().TokenID,
+			NoToken:     opp.Market.NoToken().TokenID,
+			FeeRate:     le.makerFeeRate(opp),
+		}
+		if p.YesToken == "" || p.NoToken == "" {
+			continue
+		}
+		pairs[p.YesToken] = p
+		pairs[p.NoToken] = p
+		tokens = append(tokens, p.YesToken, p.NoToken)
+	}
+
+	for id := range le.streamBooks {
+		if _, ok := pairs[id]; !ok {
+			delete(le.streamBooks, id)
+		}
+	}
+	le.streamPairs = pairs
+	le.bookStream.SetAssets(tokens)
+}
@@ -0,0 +1,81 @@
+package live
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// fakeBookStream records the watched tokens and hands out a test channel.
+type fakeBookStream struct {
+	mu     sync.Mutex
+	assets []string
+	books  chan domain.OrderBook
+}
+
+func (fs *fakeBookStream) SetAssets(tokenIDs []string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.assets = tokenIDs
+}
+
+func (fs *fakeBookStream) Books() <-chan domain.OrderBook { return fs.books }
+
+func (fs *fakeBookStream) watched() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.assets
+}
+
+func (le *Engine) spreadSamples(conditionID string) []spreadSample {
+	le.spreadMu.RLock()
+	defer le.spreadMu.RUnlock()
+	return append([]spreadSample(nil), le.spreadHistory[conditionID]...)
+}
+
+func streamedBook(tokenID string, bid, ask float64) domain.OrderBook {
+	return domain.OrderBook{
+		TokenID: tokenID,
+		Bids:    []domain.BookEntry{{Price: bid, Size: 100}},
+		Asks:    []domain.BookEntry{{Price: ask, Size: 100}},
+	}
+}
+
+func TestBookStream_SamplesSpreadBetweenCycles(t *testing.T) {
+	stream := &fakeBookStream{books: make(chan domain.OrderBook)}
+	le := New(nil, nil, nil, nil, nil, Config{BookStream: stream})
+	defer close(stream.books)
+
+	le.updateSpreadHistory([]domain.Opportunity{exposureOpp("0xcond")})
+	assert.ElementsMatch(t, []string{"tok_yes", "tok_no"}, stream.watched(), "each cycle watches the scanned markets")
+	require.Len(t, le.spreadSamples("0xcond"), 1, "the scan sample")
+
+	// Sends block until the engine's consumer takes the book.
+	stream.books <- streamedBook("tok_yes", 0.45, 0.47)
+	stream.books <- streamedBook("tok_other", 0.10, 0.90)
+	assert.Len(t, le.spreadSamples("0xcond"), 1, "no sample until both sides are known")
+
+	time.Sleep(spreadStreamMinGap)
+	stream.books <- streamedBook("tok_no", 0.50, 0.52)
+	stream.books <- streamedBook("tok_no", 0.50, 0.53)
+
+	require.Eventually(t, func() bool { return len(le.spreadSamples("0xcond")) == 2 }, time.Second, 10*time.Millisecond)
+	samples := le.spreadSamples("0xcond")
+	assert.InDelta(t, domain.SpreadTotal(0.47, 0.52), samples[1].SpreadTotal, 1e-9)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, le.spreadSamples("0xcond"), 2, "updates inside spreadStreamMinGap are throttled")
+}
+
+func TestBookStream_StopsWhenChannelCloses(t *testing.T) {
+	stream := &fakeBookStream{books: make(chan domain.OrderBook)}
+	le := New(nil, nil, nil, nil, nil, Config{BookStream: stream})
+	close(stream.books)
+
+	le.updateSpreadHistory([]domain.Opportunity{exposureOpp("0xcond")})
+	assert.Len(t, le.spreadSamples("0xcond"), 1, "scan samples keep working without the stream")
+}
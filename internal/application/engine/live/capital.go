@@ -4,8 +4,10 @@ import (
 	"context"
 	"log/slog"
 	"math"
+	"sort"
 	"time"
 
+	"github.com/alejandrodnm/polybot/internal/application/engine"
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
@@ -16,6 +18,9 @@ func (le *Engine) calculateDeployedCapital(ctx context.Context) (open, partial,
 		return 0, 0, 0
 	}
 	for _, o := range openOrders {
+		if o.IsSell() {
+			continue
+		}
 		switch o.Status {
 		case domain.LiveStatusOpen:
 			open += o.Size
@@ -45,6 +50,9 @@ func (le *Engine) getCompoundMetrics(ctx context.Context) (balance, totalProfit
 			rotations++
 		}
 	}
+	if realized, err := le.store.GetRealizedPnL(ctx); err == nil {
+		totalProfit += realized
+	}
 
 	filledOrders, _ := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusMerged))
 	for _, o := range filledOrders {
@@ -70,11 +78,14 @@ func (le *Engine) getCompoundMetrics(ctx context.Context) (balance, totalProfit
 	return balance, totalProfit, rotations, avgCycleHours
 }
 
-// kellyFraction computes the optimal Kelly fraction from real merge history.
-func (le *Engine) kellyFraction(ctx context.Context) float64 {
+// kellyFraction computes the Kelly fraction from real merge history. It
+// returns the fraction to deploy, cfg.Kelly applied to the full Kelly, and
+// the full Kelly itself (NaN while the history cannot estimate it).
+func (le *Engine) kellyFraction(ctx context.Context) (applied, full float64) {
+	k := le.cfg.Kelly
 	merges, err := le.store.GetMergeResults(ctx)
-	if err != nil || len(merges) < 3 {
-		return 0.5
+	if err != nil || len(merges) < k.WarmupMerges {
+		return k.Clamp(0.5), math.NaN()
 	}
 
 	wins := 0
@@ -94,25 +105,24 @@ func (le *Engine) kellyFraction(ctx context.Context) float64 {
 
 	attempted := len(merges)
 	if attempted == 0 {
-		return 0.5
+		return k.Clamp(0.5), math.NaN()
 	}
 
 	p := float64(wins) / float64(attempted)
 	q := 1.0 - p
 	if p <= 0 || q <= 0 {
-		return 0.25
+		return k.Clamp(0.25), math.NaN()
 	}
 
 	avgWin := totalWin / float64(max(wins, 1))
 	avgLoss := totalLoss / float64(max(attempted-wins, 1))
 	if avgLoss <= 0 {
-		return 0.5
+		return k.Clamp(0.5), math.NaN()
 	}
 
 	b := avgWin / avgLoss
-	kelly := (p*b - q) / b
-	kelly = kelly / 2
-	return math.Max(0.1, math.Min(kelly, 0.8))
+	full = (p*b - q) / b
+	return k.Fraction(full), full
 }
 
 // buildPositions constructs the current portfolio view with reward accrual.
@@ -122,33 +132,50 @@ func (le *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 		return nil, 0
 	}
 
+	// The levels of a ladder make one position; a side counts as filled once
+	// every level on it has filled.
 	byPair := make(map[string][]domain.LiveOrder)
 	for _, o := range openOrders {
-		byPair[o.PairID] = append(byPair[o.PairID], o)
+		if o.IsSell() {
+			continue
+		}
+		group := engine.PairGroup(o.PairID)
+		byPair[group] = append(byPair[group], o)
 	}
 
 	var positions []domain.LivePosition
 	totalReward := 0.0
 
 	for pairID, orders := range byPair {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].PairID < orders[j].PairID })
 		var yes, no *domain.LiveOrder
+		pos := domain.LivePosition{PairID: pairID, YesFilled: true, NoFilled: true}
 		for i := range orders {
-			switch orders[i].Side {
+			o := &orders[i]
+			filled := o.Status == domain.LiveStatusFilled || o.Status == domain.LiveStatusMerged
+			switch o.Side {
 			case "YES":
-				yes = &orders[i]
+				if yes == nil {
+					yes = o
+				}
+				pos.YesFilled = pos.YesFilled && filled
 			case "NO":
-				no = &orders[i]
+				if no == nil {
+					no = o
+				}
+				pos.NoFilled = pos.NoFilled && filled
+			default:
+				continue
 			}
+			pos.CapitalDeployed += o.Size
 		}
-
-		pos := domain.LivePosition{PairID: pairID}
+		pos.YesFilled = pos.YesFilled && yes != nil
+		pos.NoFilled = pos.NoFilled && no != nil
 
 		if yes != nil {
 			pos.YesOrder = yes
 			pos.ConditionID = yes.ConditionID
 			pos.Question = yes.Question
-			pos.YesFilled = yes.Status == domain.LiveStatusFilled || yes.Status == domain.LiveStatusMerged
-			pos.CapitalDeployed += yes.Size
 			pos.DailyReward = yes.DailyReward
 			pos.HoursToEnd = time.Until(yes.EndDate).Hours()
 			if pos.HoursToEnd < 0 {
@@ -162,8 +189,6 @@ func (le *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 				pos.Question = no.Question
 				pos.HoursToEnd = time.Until(no.EndDate).Hours()
 			}
-			pos.NoFilled = no.Status == domain.LiveStatusFilled || no.Status == domain.LiveStatusMerged
-			pos.CapitalDeployed += no.Size
 		}
 
 		pos.IsComplete = pos.YesFilled && pos.NoFilled
@@ -200,6 +225,7 @@ func (le *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 // saveDailySummary persists the daily live trading summary.
 func (le *Engine) saveDailySummary(ctx context.Context, result *CycleResult) {
 	_, totalMergeProfit, _, _ := le.getCompoundMetrics(ctx)
+	strandedShares, strandedUSDC := le.strandedRemainders(ctx)
 	summary := domain.LiveDailySummary{
 		Date:            time.Now().UTC().Truncate(24 * time.Hour),
 		ActivePositions: len(result.Positions),
@@ -211,6 +237,10 @@ func (le *Engine) saveDailySummary(ctx context.Context, result *CycleResult) {
 		Merges:          result.Merges,
 		MergeProfit:     result.MergeProfit,
 		GasCostUSD:      result.GasCostUSD,
+		GasUsedPOL:      le.gasUsedPOLOn(ctx, time.Now().UTC()),
+		POLBalance:      result.POLBalance,
+		StrandedShares:  strandedShares,
+		StrandedUSDC:    strandedUSDC,
 		CompoundBalance: result.CompoundBalance,
 		Rotations:       result.TotalRotations,
 	}
@@ -222,10 +252,26 @@ func (le *Engine) saveDailySummary(ctx context.Context, result *CycleResult) {
 	}
 }
 
-// velocityScore ranks opportunities for live trading.
-func velocityScore(opp domain.Opportunity) float64 {
-	yesQ := queuePositionConservative(opp.YesBook, opp.YesBook.BestBid())
-	noQ := queuePositionConservative(opp.NoBook, opp.NoBook.BestBid())
+// strandedRemainders sums the merge remainders still held unhedged.
+func (le *Engine) strandedRemainders(ctx context.Context) (shares, usdc float64) {
+	filled, err := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusFilled))
+	if err != nil {
+		return 0, 0
+	}
+	for _, o := range filled {
+		if o.Stranded() {
+			shares += o.Remainder
+			usdc += o.UnmergedSize()
+		}
+	}
+	return shares, usdc
+}
+
+// velocityScore ranks opportunities for live trading. queueMult scales the
+// visible queue as placement does (Config.QueueConservativeMult).
+func velocityScore(opp domain.Opportunity, queueMult float64) float64 {
+	yesQ := engine.QueueAhead(opp.YesBook, opp.YesBook.BestBid()) * queueMult
+	noQ := engine.QueueAhead(opp.NoBook, opp.NoBook.BestBid()) * queueMult
 	totalQueue := yesQ + noQ
 
 	profitPerPair := -opp.FillCostPerPair
@@ -0,0 +1,66 @@
+package live
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// startCooldown blocks re-entry into a rotated market for RotationCooldown,
+// so a market that rescores well right after rotation is not churned.
+func (le *Engine) startCooldown(ctx context.Context, conditionID, question, reason string) {
+	le.cooldownUntil(ctx, conditionID, question, time.Now().Add(le.cfg.RotationCooldown), reason)
+}
+
+// cooldownAfterCancel blocks re-entry into a market whose pairs were
+// cancelled for reason: for RotationCooldown, or for good when it was
+// retired near its end (see domain.CloseReason.CooldownUntil).
+func (le *Engine) cooldownAfterCancel(ctx context.Context, conditionID, question string, reason domain.CloseReason) {
+	until := reason.CooldownUntil(time.Now(), le.cfg.RotationCooldown)
+	if until.IsZero() {
+		return
+	}
+	le.cooldownUntil(ctx, conditionID, question, until, string(reason))
+}
+
+// cooldownUntil records and persists a cooldown ending at until.
+func (le *Engine) cooldownUntil(ctx context.Context, conditionID, question string, until time.Time, reason string) {
+	le.cooldowns[conditionID] = until
+	if err := le.store.SaveLiveCooldown(ctx, conditionID, until, reason); err != nil {
+		slog.Warn("live: could not persist cooldown", "market", engine.TruncateStr(question, 30), "err", err)
+	}
+}
+
+// loadCooldowns merges the persisted cooldowns into memory once per process.
+func (le *Engine) loadCooldowns(ctx context.Context) {
+	if le.cooldownsLoaded {
+		return
+	}
+	stored, err := le.store.GetLiveCooldowns(ctx)
+	if err != nil {
+		slog.Warn("live: could not load cooldowns", "err", err)
+		return
+	}
+	for conditionID, until := range stored {
+		if until.After(le.cooldowns[conditionID]) {
+			le.cooldowns[conditionID] = until
+		}
+	}
+	le.cooldownsLoaded = true
+}
+
+// inCooldown reports whether a market was rotated too recently to re-enter.
+func (le *Engine) inCooldown(conditionID string) bool {
+	until, ok := le.cooldowns[conditionID]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(le.cooldowns, conditionID)
+		return false
+	}
+	return true
+}
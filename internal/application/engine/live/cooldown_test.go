@@ -0,0 +1,40 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestCancelResolvedOrders_CoolsDownCancelledMarkets(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 100), Config{OrderSize: 5})
+
+	for _, cond := range []string{"0xnear", "0xgone"} {
+		for _, side := range []string{"YES", "NO"} {
+			require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+				ID: cond + "-" + side, CLOBOrderID: "clob-" + cond + "-" + side, ConditionID: cond,
+				TokenID: "tok_" + side, Side: side, BidPrice: 0.48, Size: 5, PairID: "pair-" + cond,
+				PlacedAt: time.Now().UTC(), Status: domain.LiveStatusOpen,
+			}))
+		}
+	}
+	near := exposureOpp("0xnear")
+	near.Market.Active = true
+	near.Market.EndDate = time.Now().Add(time.Hour)
+
+	le.cancelResolvedOrders(ctx, map[string]domain.Opportunity{"0xnear": near})
+
+	assert.Equal(t, domain.NoReentry, le.cooldowns["0xnear"], "a near-end market is excluded for good")
+	assert.WithinDuration(t, time.Now().Add(le.cfg.RotationCooldown), le.cooldowns["0xgone"], time.Minute)
+	assert.True(t, le.inCooldown("0xnear"))
+
+	stored, err := db.GetLiveCooldowns(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2, "persisted across restarts")
+}
@@ -0,0 +1,60 @@
+package live
+
+// dailyloss.go — per-day loss limit.
+//
+// The circuit breaker trips on consecutive losing merges or total drawdown,
+// but a day of small losses interleaved with small wins never trips it. The
+// daily limit sums what the day actually realized — merge profit net of gas
+// plus closed unwinds — from the database, so a restart keeps the halt, and
+// the sum starts over at 00:00 UTC.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// dailyRealizedPnL is the P&L realized since midnight UTC of now: the net
+// profit of the day's successful merges plus the unwinds closed today.
+func (le *Engine) dailyRealizedPnL(ctx context.Context, now time.Time) (float64, error) {
+	midnight := now.UTC().Truncate(24 * time.Hour)
+
+	merges, err := le.store.GetMergeResults(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var pnl float64
+	for _, r := range merges {
+		if r.Success && !r.ExecutedAt.Before(midnight) {
+			pnl += r.SpreadProfit
+		}
+	}
+
+	unwinds, err := le.store.GetRealizedPnLSince(ctx, midnight)
+	if err != nil {
+		return 0, err
+	}
+	return pnl + unwinds, nil
+}
+
+// dailyLossHalted reports whether today's realized P&L has reached
+// -DailyLossLimit, with the warning for the cycle result.
+func (le *Engine) dailyLossHalted(ctx context.Context, now time.Time) (string, bool) {
+	if le.cfg.DailyLossLimit <= 0 {
+		return "", false
+	}
+	pnl, err := le.dailyRealizedPnL(ctx, now)
+	if err != nil {
+		slog.Warn("live: daily P&L unavailable, loss limit not checked", "err", err)
+		return "", false
+	}
+	if pnl > -le.cfg.DailyLossLimit {
+		return "", false
+	}
+	slog.Warn("live: DAILY LOSS LIMIT reached — no new orders until 00:00 UTC",
+		"realized_today", fmt.Sprintf("$%.4f", pnl),
+		"limit", fmt.Sprintf("-$%.2f", le.cfg.DailyLossLimit))
+	return fmt.Sprintf("DAILY LOSS LIMIT: realized $%.2f today (limit -$%.2f) — no new orders until 00:00 UTC",
+		pnl, le.cfg.DailyLossLimit), true
+}
@@ -0,0 +1,54 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestDailyLossHalted_SumsTodayAndResetsAtMidnight(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xa", TxHash: "0x1", SpreadProfit: -10, Success: true, ExecutedAt: today.Add(-time.Hour),
+	}))
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xa", TxHash: "0x2", SpreadProfit: -3, Success: true, ExecutedAt: today.Add(time.Minute),
+	}))
+	require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+		ConditionID: "0xa", TxHash: "0x3", Success: false, ExecutedAt: today.Add(time.Minute),
+	}))
+	filledAt := today.Add(2 * time.Minute)
+	require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+		ID: "sell", ConditionID: "0xb", TokenID: "tok", Side: "YES", OrderSide: "SELL", PairID: "p",
+		BidPrice: 0.4, Size: 2, PlacedAt: today.Add(time.Minute), FilledAt: &filledAt,
+		Status: domain.LiveStatusFlattened, RealizedPnL: -2,
+	}))
+
+	pnl, err := le.dailyRealizedPnL(ctx, now)
+	require.NoError(t, err)
+	assert.InDelta(t, -5, pnl, 1e-9, "yesterday's merge and failed merges excluded")
+
+	_, halted := le.dailyLossHalted(ctx, now)
+	assert.False(t, halted, "disabled by default")
+
+	le.cfg.DailyLossLimit = 6
+	_, halted = le.dailyLossHalted(ctx, now)
+	assert.False(t, halted)
+
+	le.cfg.DailyLossLimit = 5
+	warning, halted := le.dailyLossHalted(ctx, now)
+	assert.True(t, halted)
+	assert.Contains(t, warning, "DAILY LOSS LIMIT")
+
+	_, halted = le.dailyLossHalted(ctx, today.Add(24*time.Hour))
+	assert.False(t, halted, "a new UTC day starts from zero")
+}
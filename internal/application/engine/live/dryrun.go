@@ -0,0 +1,13 @@
+package live
+
+// dryrun.go — Placement dry-run for the live engine.
+//
+// Config.DryRunPlacement runs the engine in shadow mode (shadow.go) with two
+// differences: the shadow executors size against the real wallet balances,
+// and the orders get a "DRY-" CLOB ID and a [DRY-RUN] log line. Like any
+// shadow order they are stored with shadow=1 and never fill, so they never
+// merge; rotation retires them after one cycle, without a cooldown, so the
+// next scan places fresh ones.
+
+// dryRunPrefix marks the CLOB order IDs of orders that were never sent.
+const dryRunPrefix = "DRY-"
@@ -0,0 +1,55 @@
+package live
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunPlacement_StoresWithoutSendingAndRotates(t *testing.T) {
+	ctx := context.Background()
+	// Any order that reached the real executor would fail or rest on this book.
+	wallet := newShadowExecutor(nil, 40)
+	le, db := newTestEngine(t, rejectExecutor{wallet}, Config{DryRunPlacement: true})
+	assert.True(t, le.cfg.ShadowMode, "a placement dry-run is shadow mode")
+
+	yes, yesReq := pendingLeg()
+	no, noReq := pendingLeg()
+	no.ID, no.Side, no.TokenID = "local-no", "NO", "tok_no"
+	noReq.TokenID = no.TokenID
+	require.NoError(t, le.placeLeg(ctx, le.wallets[0], &yes, yesReq))
+	require.NoError(t, le.placeLeg(ctx, le.wallets[0], &no, noReq))
+
+	assert.True(t, strings.HasPrefix(yes.CLOBOrderID, dryRunPrefix))
+	resting, err := wallet.GetOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, resting, "nothing is sent")
+
+	tb, err := le.wallets[0].Executor.GetTradeableBalance(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, tb.Wallet, "sized against the real wallet, not InitialCapital")
+	assert.Equal(t, 20.0, tb.LockedInOrders, "less the dry-run bids")
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	for _, o := range open {
+		assert.True(t, o.Shadow, "stored with shadow=1, away from real stats")
+	}
+
+	fills, err := le.syncOrderState(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, fills)
+	open, err = db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 2, "dry-run orders never fill")
+
+	assert.Equal(t, 1, le.rotateStaleOrders(ctx, nil))
+	open, err = db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+	assert.Empty(t, le.cooldowns, "dry-run rotations must not block re-entry")
+}
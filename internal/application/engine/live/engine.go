@@ -12,6 +12,7 @@ import (
 	"github.com/alejandrodnm/polybot/internal/ports"
 )
 
+// Defaults for the tunable thresholds in Config.
 const (
 	MaxMarkets             = 10
 	maxPartialHours        = 6
@@ -25,6 +26,10 @@ const (
 	maxBidTickUp           = 0.45
 	bidTickStep            = 0.01
 	minMergeProfitUSDC     = 0.05
+	gasBufferPct           = 0.10
+	gasFallbackUSD         = 0.05
+	minGasRunwayMerges     = 10
+	minPartialMergeSets    = 5
 	maxMarketConcentration = 0.15
 	queueConservativeMult  = 1.5
 	minVolume24h           = 5000
@@ -32,11 +37,26 @@ const (
 	maxSpreadPct           = 0.60
 	spreadStabilityWindow  = 3
 	spreadVarianceMax      = 0.10
+	spreadStreamWindow     = 60
+	spreadStreamMinGap     = time.Second
 	circuitBreakerLosses   = 3
 	circuitBreakerCooldown = 30 * time.Minute
-	circuitBreakerDrawdown = -0.05
+	circuitBreakerDrawdown = 0.05
+	rotationCooldown       = 2 * time.Hour
+	flattenPartialHours    = 12
+	takerAfterHours        = 1
+	unwindLossTicks        = 2
+	unwindFloorPct         = 0.50
+	repriceTicks           = 3
+	repriceQueueMult       = 3.0
+	balanceDriftMinUSD     = 1.0
+	balanceDriftPct        = 0.01
 )
 
+// defaultKelly is half-Kelly between 10% and 80% of the bankroll, estimated
+// once three merges have landed.
+var defaultKelly = engine.KellyConfig{Multiplier: 0.5, Min: 0.1, Max: 0.8, WarmupMerges: 3}
+
 // spreadSample is a snapshot of spread quality for a market at a given time.
 type spreadSample struct {
 	SpreadTotal float64
@@ -52,6 +72,144 @@ type Config struct {
 	InitialCapital float64
 	MaxExposure    float64
 	MinMergeProfit float64
+
+	// RebatedFeeRate is the maker fee of bids that qualify for liquidity
+	// rewards, which the program rebates or zero-rates; the rest pay FeeRate
+	// (or the market's own fee). See domain.Market.MakerFeeRate.
+	RebatedFeeRate float64
+
+	// MaxMarketConcentration caps the capital committed to one condition —
+	// resting bids plus filled inventory not merged yet — as a fraction of
+	// the deployable capital. One full pair always fits under the cap.
+	MaxMarketConcentration float64
+
+	// GasBufferPct is the safety margin added to the estimated merge gas when
+	// deciding whether a merge clears MinMergeProfit (0.10 = +10%). It only
+	// gates the decision: a skipped merge whose spread does not even cover the
+	// unbuffered estimate is recorded as a loss by the circuit breaker, so a
+	// larger buffer makes merges wait without tripping the breaker by itself.
+	GasBufferPct float64
+
+	// MinGasRunwayMerges is how many merges each wallet's POL must cover at
+	// the current gas price. Below it the cycle warns and places no new
+	// orders from that wallet, since their fills could not be merged.
+	MinGasRunwayMerges float64
+
+	// MinBalanceUSDC is the USDC the wallets must hold between them. Below
+	// it, or below the cost of one minimum pair, the cycle warns with the
+	// shortfall and places no new orders; PauseOnLowBalance also pauses the
+	// circuit breaker for CircuitBreakerCooldown. 0 = only the one-pair check.
+	MinBalanceUSDC    float64
+	PauseOnLowBalance bool
+
+	// DailyLossLimit stops new orders for the rest of the UTC day once the
+	// day's realized P&L (merges net of gas plus unwinds) falls to
+	// -DailyLossLimit. Existing positions are still managed. 0 = off.
+	DailyLossLimit float64
+
+	// MinPartialMergeSets is the smallest merge taken from a pair whose legs
+	// are still filling. Smaller overlaps wait, so gas is not spent on dust.
+	MinPartialMergeSets float64
+
+	// MaxPartialHours is how long a one-sided fill may wait for its
+	// counterpart before the filled side is sold back (stop-loss).
+	MaxPartialHours float64
+
+	// UnwindLossTicks is how many ticks below entry the first unwind SELL is
+	// priced; each cycle walks it down one more tick until UnwindFloorPct of
+	// the entry price.
+	UnwindLossTicks int
+	UnwindFloorPct  float64
+
+	// AllowTakerCompletion buys the missing leg of a pair at the ask (FOK)
+	// once it has been one-sided for TakerAfterHours, if the merge still
+	// clears MinMergeProfit after fees and gas at the worst ask level needed.
+	AllowTakerCompletion bool
+	TakerAfterHours      float64
+
+	// Ladder spreads each market's OrderSize over several pairs, one per
+	// price level below the optimized bid. Levels share a pairID prefix
+	// (engine.PairGroup) and are merged together.
+	Ladder engine.LadderConfig
+
+	// Kelly tunes how kellyFraction turns the full Kelly of the merge
+	// history into the deployable fraction. Zero fields use defaultKelly.
+	Kelly engine.KellyConfig
+
+	// AllowNegRisk lets the engine enter NegRisk markets and enables NegRisk
+	// adapter merges on every wallet's merger (ports.NegRiskMerger). A merger
+	// that cannot merge them keeps those markets skipped.
+	AllowNegRisk bool
+
+	// Entry gates. Zero values fall back to the package defaults.
+	MinVolume24h      float64 // skip markets trading less than this per day
+	MinAskDepthShares float64 // minimum ask depth on each side
+	MaxSpreadPct      float64 // max bid/ask spread as a fraction of the midpoint
+	SpreadVarianceMax float64 // max coefficient of variation of recent spreads
+	NearEndHours      float64 // no entries (and cancel) this close to resolution
+	MaxBidTickUp      float64 // how far above the best bid optimizeBid may go
+	// QueueConservativeMult scales the visible queue ahead of a new bid to
+	// cover hidden and late-arriving size (see CalibrateLiveConstants).
+	QueueConservativeMult float64
+
+	// Rotation and alerts.
+	StaleHours        float64       // rotate pairs with no fills after this long
+	CompetitionMult   float64       // rotate when bid competition grows by this factor
+	PartialAlertHours float64       // report partials older than this
+	RotationCooldown  time.Duration // no re-entry into a rotated market for this long
+
+	// Markets is the market blacklist/whitelist; nil allows every market.
+	// The scanner applies the same list, this guards placement regardless.
+	Markets engine.MarketAllower
+
+	// Health receives a ports.HealthLiveCycle beat per RunOnce; nil = none.
+	Health ports.HealthReporter
+
+	// BookStream streams order books (polymarket.BookSubscriber) for the
+	// scanned markets, so the spread stability gate samples between cycles
+	// instead of only at scan time. nil = scan samples only.
+	BookStream ports.BookStream
+
+	// OrderEvents streams fills and cancellations (polymarket.UserStream).
+	// Each RunOnce applies what arrived before polling the CLOB, which stays
+	// as the fallback; nil = poll only.
+	OrderEvents ports.OrderEventStream
+
+	// Reprice moves resting entry bids that fell behind the book (see
+	// reprice.go): RepriceTicks or more below the best bid, or with the queue
+	// ahead grown by RepriceQueueMult since placement.
+	Reprice          bool
+	RepriceTicks     int
+	RepriceQueueMult float64
+
+	// Circuit breaker.
+	CircuitBreakerLosses      int
+	CircuitBreakerCooldown    time.Duration
+	CircuitBreakerDrawdownPct float64 // trip at this fraction of InitialCapital lost (e.g. 0.05)
+
+	// NoCancelOnExit keeps open CLOB orders on the exchange at shutdown. By
+	// default Shutdown cancels every one of them, partial pairs included, so
+	// nothing fills after the bot stops tracking it.
+	NoCancelOnExit bool
+
+	// CancelOnExit, with NoCancelOnExit, still cancels the pairs that have not
+	// filled at all, keeping the partial ones resting across restarts.
+	CancelOnExit bool
+
+	// CancelOrphans makes Reconcile cancel CLOB orders that have no row in
+	// live_orders. Off only logs them.
+	CancelOrphans bool
+
+	// ShadowMode runs the full pipeline without sending anything: orders and
+	// merges are only logged and stored with shadow=1, against a bankroll of
+	// InitialCapital per wallet. Pair it with a shadow-scoped store so shadow
+	// and real state never mix.
+	ShadowMode bool
+
+	// DryRunPlacement is shadow mode (it implies ShadowMode) sized against
+	// the real wallet balances: each pair is stored as it would be placed,
+	// with a "DRY-" CLOB ID, and rotated out after one cycle; see dryrun.go.
+	DryRunPlacement bool
 }
 
 // CycleResult contains everything produced by one live trading cycle.
@@ -67,11 +225,17 @@ type CycleResult struct {
 	Merges          int
 	MergeProfit     float64
 	GasCostUSD      float64
+	POLBalance      float64 // POL held for gas across wallets (0 = not checked)
 	CompoundBalance float64
 	TotalRotations  int
+	Repriced        int
 	AvgCycleHours   float64
 	KellyFraction   float64
 	CircuitOpen     bool
+
+	// Exposures is the capital committed per condition after placement,
+	// largest first.
+	Exposures []domain.ConditionExposure
 }
 
 // Engine executes real trades on Polymarket.
@@ -83,13 +247,31 @@ type Engine struct {
 	store    ports.LiveStorage
 	cfg      Config
 	breaker  domain.CircuitBreaker
+	wallets  []Wallet
 
 	spreadHistory map[string][]spreadSample
 	spreadMu      sync.RWMutex
 
+	cooldowns       map[string]time.Time // condition ID → no re-entry until
+	cooldownsLoaded bool
+	rejected        map[string]bool    // condition ID → CLOB rejected an order this cycle
+	tickSizes       map[string]float64 // token ID → CLOB minimum price increment
+
+	resolved     map[string]bool      // condition ID → resolved on-chain
+	unresolvedAt map[string]time.Time // condition ID → last unresolved on-chain reading
+
+	bookStream  ports.BookStream
+	streamPairs map[string]streamPair
+	streamBooks map[string]domain.OrderBook
+
 	lastGasUpdate time.Time
 	cachedGasUSD  float64
 	lastScan      time.Time
+
+	rewardsSyncedOn time.Time // UTC day of the last successful rewards sync
+
+	reloadMu   sync.Mutex
+	pendingCfg *Config // staged by Reload, applied at the next RunOnce
 }
 
 // New creates a real-money trading engine.
@@ -101,28 +283,134 @@ func New(
 	store ports.LiveStorage,
 	cfg Config,
 ) *Engine {
-	if cfg.MaxMarkets <= 0 {
-		cfg.MaxMarkets = MaxMarkets
+	cfg = cfg.withDefaults()
+	if cfg.DryRunPlacement {
+		cfg.ShadowMode = true
 	}
-	if cfg.MinMergeProfit <= 0 {
-		cfg.MinMergeProfit = minMergeProfitUSDC
+
+	primary := Wallet{Executor: executor, Merger: merger}
+	if cfg.ShadowMode {
+		primary = shadowWallet(primary, cfg)
+		if cfg.DryRunPlacement {
+			slog.Info("live: [DRY-RUN] placement dry-run — orders are stored and logged, not sent")
+		} else {
+			slog.Info("live: SHADOW MODE — orders and merges are logged, not sent")
+		}
 	}
 
-	return &Engine{
+	le := &Engine{
 		scanner:       scanner,
 		books:         books,
-		executor:      executor,
-		merger:        merger,
+		executor:      primary.Executor,
+		merger:        primary.Merger,
 		store:         store,
 		cfg:           cfg,
+		wallets:       []Wallet{primary},
 		spreadHistory: make(map[string][]spreadSample),
+		cooldowns:     make(map[string]time.Time),
+		rejected:      make(map[string]bool),
+		tickSizes:     make(map[string]float64),
+		resolved:      make(map[string]bool),
+		unresolvedAt:  make(map[string]time.Time),
+		streamPairs:   make(map[string]streamPair),
+		streamBooks:   make(map[string]domain.OrderBook),
 		lastScan:      time.Now().Add(-5 * time.Minute),
 		breaker: domain.CircuitBreaker{
-			MaxLosses:        circuitBreakerLosses,
-			CooldownDuration: circuitBreakerCooldown,
-			MaxDrawdown:      cfg.InitialCapital * circuitBreakerDrawdown,
+			MaxLosses:        cfg.CircuitBreakerLosses,
+			CooldownDuration: cfg.CircuitBreakerCooldown,
+			MaxDrawdown:      -cfg.InitialCapital * cfg.CircuitBreakerDrawdownPct,
 		},
 	}
+	if cfg.BookStream != nil {
+		le.bookStream = cfg.BookStream
+		go le.consumeBookUpdates(cfg.BookStream.Books())
+	}
+	le.enableNegRisk()
+	return le
+}
+
+// withDefaults fills the zero thresholds with the package defaults.
+func (cfg Config) withDefaults() Config {
+	if cfg.MaxMarkets <= 0 {
+		cfg.MaxMarkets = MaxMarkets
+	}
+	cfg.Kelly = cfg.Kelly.WithDefaults(defaultKelly)
+	if cfg.MinMergeProfit <= 0 {
+		cfg.MinMergeProfit = minMergeProfitUSDC
+	}
+	if cfg.GasBufferPct <= 0 {
+		cfg.GasBufferPct = gasBufferPct
+	}
+	if cfg.MinGasRunwayMerges <= 0 {
+		cfg.MinGasRunwayMerges = minGasRunwayMerges
+	}
+	if cfg.MaxMarketConcentration <= 0 || cfg.MaxMarketConcentration > 1 {
+		cfg.MaxMarketConcentration = maxMarketConcentration
+	}
+	if cfg.MinPartialMergeSets <= 0 {
+		cfg.MinPartialMergeSets = minPartialMergeSets
+	}
+	if cfg.MaxPartialHours <= 0 {
+		cfg.MaxPartialHours = flattenPartialHours
+	}
+	if cfg.TakerAfterHours <= 0 {
+		cfg.TakerAfterHours = takerAfterHours
+	}
+	if cfg.UnwindLossTicks <= 0 {
+		cfg.UnwindLossTicks = unwindLossTicks
+	}
+	if cfg.UnwindFloorPct <= 0 || cfg.UnwindFloorPct >= 1 {
+		cfg.UnwindFloorPct = unwindFloorPct
+	}
+	if cfg.MinVolume24h <= 0 {
+		cfg.MinVolume24h = minVolume24h
+	}
+	if cfg.MinAskDepthShares <= 0 {
+		cfg.MinAskDepthShares = minAskDepthShares
+	}
+	if cfg.MaxSpreadPct <= 0 {
+		cfg.MaxSpreadPct = maxSpreadPct
+	}
+	if cfg.SpreadVarianceMax <= 0 {
+		cfg.SpreadVarianceMax = spreadVarianceMax
+	}
+	if cfg.QueueConservativeMult <= 0 {
+		cfg.QueueConservativeMult = queueConservativeMult
+	}
+	if cfg.NearEndHours <= 0 {
+		cfg.NearEndHours = nearEndHours
+	}
+	if cfg.MaxBidTickUp <= 0 || cfg.MaxBidTickUp >= 1 {
+		cfg.MaxBidTickUp = maxBidTickUp
+	}
+	if cfg.StaleHours <= 0 {
+		cfg.StaleHours = staleHours
+	}
+	if cfg.CompetitionMult <= 1 {
+		cfg.CompetitionMult = competitionMult
+	}
+	if cfg.PartialAlertHours <= 0 {
+		cfg.PartialAlertHours = maxPartialHours
+	}
+	if cfg.RepriceTicks <= 0 {
+		cfg.RepriceTicks = repriceTicks
+	}
+	if cfg.RepriceQueueMult <= 1 {
+		cfg.RepriceQueueMult = repriceQueueMult
+	}
+	if cfg.RotationCooldown <= 0 {
+		cfg.RotationCooldown = rotationCooldown
+	}
+	if cfg.CircuitBreakerLosses <= 0 {
+		cfg.CircuitBreakerLosses = circuitBreakerLosses
+	}
+	if cfg.CircuitBreakerCooldown <= 0 {
+		cfg.CircuitBreakerCooldown = circuitBreakerCooldown
+	}
+	if cfg.CircuitBreakerDrawdownPct <= 0 || cfg.CircuitBreakerDrawdownPct >= 1 {
+		cfg.CircuitBreakerDrawdownPct = circuitBreakerDrawdown
+	}
+	return cfg
 }
 
 // RestoreCircuitBreaker loads a previously saved circuit breaker state.
@@ -134,9 +422,14 @@ func (le *Engine) RestoreCircuitBreaker(cb domain.CircuitBreaker) {
 }
 
 // RunOnce executes one live trading cycle. Orchestrates: protection → scan →
-// sync → maintenance → merge → placement → reporting.
-func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
+// sync → maintenance (rotation, repricing) → merge → placement → reporting.
+func (le *Engine) RunOnce(ctx context.Context) (_ *CycleResult, err error) {
+	le.applyReload()
+	if le.cfg.Health != nil {
+		defer func() { le.cfg.Health.Beat(ports.HealthLiveCycle, err) }()
+	}
 	result := &CycleResult{}
+	clear(le.rejected)
 
 	// 1. Protection: check circuit breaker
 	if !le.breaker.IsOpen() {
@@ -150,13 +443,22 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		return result, nil
 	}
 	result.CircuitOpen = true
+	if le.breaker.TriggeredReason != "" {
+		slog.Info("live: circuit breaker cooldown over, resuming", "reason", le.breaker.TriggeredReason)
+		le.breaker.Reset()
+	}
 
 	// 2. Discovery: get balance + scan markets
-	balance, err := le.executor.GetBalance(ctx)
+	wallets, balance, err := le.loadWalletStates(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("live.RunOnce: get balance: %w", err)
 	}
-	slog.Info("live: cycle start", "balance", fmt.Sprintf("$%.2f", balance))
+	slog.Info("live: cycle start", "balance", fmt.Sprintf("$%.2f", balance), "wallets", len(wallets))
+
+	polBalance, gasWarnings := le.checkGasRunway(ctx, wallets)
+	result.POLBalance = polBalance
+	result.Warnings = append(result.Warnings, gasWarnings...)
+	lowBalance, lowWarning := le.checkLowBalance(wallets)
 
 	opps, err := le.scanner.RunOnce(ctx)
 	if err != nil {
@@ -171,13 +473,24 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 	// 3. Verification: sync state + spread history
 	le.updateSpreadHistory(opps)
 
+	streamedFills := le.drainOrderEvents(ctx)
 	newFills, err := le.syncOrderState(ctx, oppByCondition)
 	if err != nil {
 		slog.Warn("live: error syncing order state", "err", err)
 	}
-	result.NewFills = newFills
+	result.NewFills = streamedFills + newFills
+
+	// 4. Maintenance: complete or flatten stale partials + cancel resolved + rotate stale + reprice
+	if taken := le.completePartialsWithTaker(ctx, oppByCondition); taken > 0 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("TAKER completed %d partial pair(s) at the ask", taken))
+	}
+	flattened, flattenPnL := le.flattenStalePartials(ctx)
+	if flattened > 0 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("FLATTENED %d stale partial(s): realized $%.4f", flattened, flattenPnL))
+	}
 
-	// 4. Maintenance: cancel resolved + rotate stale
 	le.cancelResolvedOrders(ctx, oppByCondition)
 
 	staleRotated := le.rotateStaleOrders(ctx, oppByCondition)
@@ -186,7 +499,12 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		slog.Info("live: rotated stale orders", "pairs", staleRotated)
 	}
 
-	// 5. Merge: execute on-chain merges for complete pairs
+	result.Repriced = le.repriceOrders(ctx, oppByCondition)
+	if result.Repriced > 0 {
+		slog.Info("live: repriced orders", "orders", result.Repriced)
+	}
+
+	// 5. Merge: execute on-chain merges for complete pairs, redeem resolved markets
 	merges, mergeProfit, gasCost, err := le.mergeCompletePairs(ctx)
 	if err != nil {
 		slog.Warn("live: error merging pairs", "err", err)
@@ -195,6 +513,11 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 	result.MergeProfit = mergeProfit
 	result.GasCostUSD = gasCost
 
+	if redeemed, redeemPnL := le.redeemResolved(ctx, oppByCondition); redeemed > 0 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("REDEEMED %d resolved market(s): realized $%.4f", redeemed, redeemPnL))
+	}
+
 	// 6. Capital allocation
 	compoundBalance, totalMergeProfit, totalRotations, avgCycleHours := le.getCompoundMetrics(ctx)
 	result.CompoundBalance = compoundBalance
@@ -211,16 +534,24 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 	result.KellyFraction = kellyF
 
 	// 7. Placement pipeline: filter + place orders
-	pOut := le.runPlacementPipeline(ctx, placementInput{
-		opps:             opps,
-		activeConditions: activeConditions,
-		balance:          balance,
-		currentCapital:   currentCapital,
-		effectiveCapital: effectiveCapital,
-	})
+	pOut := placementOutput{capitalAfter: currentCapital}
+	if lowBalance {
+		result.Warnings = append(result.Warnings, lowWarning)
+	} else if warning, halted := le.dailyLossHalted(ctx, time.Now()); halted {
+		result.Warnings = append(result.Warnings, warning)
+	} else {
+		pOut = le.runPlacementPipeline(ctx, placementInput{
+			opps:             opps,
+			activeConditions: activeConditions,
+			wallets:          wallets,
+			currentCapital:   currentCapital,
+			effectiveCapital: effectiveCapital,
+		})
+	}
 	result.NewOrders = pOut.newOrders
 	result.CapitalDeployed = pOut.capitalAfter
 	result.Warnings = append(result.Warnings, pOut.warnings...)
+	result.Exposures = pOut.exposures
 
 	// 8. Reporting: build positions + alerts
 	positions, totalReward := le.buildPositions(ctx, oppByCondition)
@@ -233,9 +564,9 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		}
 		if pos.PartialSince != nil && !pos.IsComplete {
 			dur := pos.PartialDuration()
-			if dur > maxPartialHours*time.Hour {
+			if dur.Hours() > le.cfg.PartialAlertHours {
 				result.PartialAlerts = append(result.PartialAlerts,
-					fmt.Sprintf("PARTIAL >%dh: %s (%.0fh)", maxPartialHours, pos.Question, dur.Hours()))
+					fmt.Sprintf("PARTIAL >%.0fh: %s (%.0fh)", le.cfg.PartialAlertHours, pos.Question, dur.Hours()))
 			}
 		}
 		if pos.HoursToEnd > 0 && pos.HoursToEnd < 48 {
@@ -244,6 +575,7 @@ func (le *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		}
 	}
 
+	le.syncRewards(ctx)
 	le.saveDailySummary(ctx, result)
 	le.lastScan = time.Now()
 	return result, nil
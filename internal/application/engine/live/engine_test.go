@@ -0,0 +1,26 @@
+package live
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// newTestEngine builds an engine trading through exec over a fresh in-memory
+// live store. Shadow and dry-run engines get the shadow-scoped store, as
+// Config.ShadowMode asks.
+func newTestEngine(t *testing.T, exec ports.OrderExecutor, cfg Config) (*Engine, *storage.SQLiteStorage) {
+	t.Helper()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(context.Background()))
+	if cfg.ShadowMode || cfg.DryRunPlacement {
+		db = db.ShadowLive()
+	}
+	return New(nil, nil, exec, nil, db, cfg), db
+}
@@ -0,0 +1,145 @@
+package live
+
+// exposure.go — Per-condition exposure cap.
+//
+// A condition's exposure is the USDC resting in its entry bids plus the
+// filled inventory not merged yet. live_orders gives both; before placing,
+// the on-chain token balances of the candidate market are checked too, so
+// tokens still held after a rotation (or missed by the order sync) count
+// against the cap when the engine re-enters the market.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// condExposure is the capital committed to one condition.
+type condExposure struct {
+	question     string
+	resting      float64 // USDC in OPEN/PARTIAL bids not filled yet
+	held         float64 // filled USDC whose tokens are still held
+	chainChecked bool    // held already raised to the on-chain balance
+}
+
+func (e *condExposure) total() float64 {
+	return e.resting + e.held
+}
+
+// conditionExposures builds the exposure of every condition with entry
+// orders in live_orders. Merged inventory no longer counts, so a pair merged
+// earlier in the cycle frees its condition before placement.
+func (le *Engine) conditionExposures(ctx context.Context) map[string]*condExposure {
+	exp := make(map[string]*condExposure)
+	orders, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		slog.Warn("live: could not load orders for exposure", "err", err)
+		return exp
+	}
+	filled, err := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusFilled))
+	if err != nil {
+		slog.Warn("live: could not load filled orders for exposure", "err", err)
+	}
+	for _, o := range append(orders, filled...) {
+		if o.IsSell() {
+			continue
+		}
+		e := exp[o.ConditionID]
+		if e == nil {
+			e = &condExposure{question: o.Question}
+			exp[o.ConditionID] = e
+		}
+		if o.Status == domain.LiveStatusOpen || o.Status == domain.LiveStatusPartial {
+			e.resting += o.Size - o.FilledSize
+		}
+		e.held += math.Max(o.UnmergedSize(), 0)
+	}
+	return exp
+}
+
+// heldTokenValue values the tokens of opp's market held across all wallets
+// at the current best bids. Failed balance lookups count as zero.
+func (le *Engine) heldTokenValue(ctx context.Context, opp domain.Opportunity) float64 {
+	var total float64
+	for _, tok := range []struct {
+		id  string
+		bid float64
+	}{
+		{opp.Market.YesToken().TokenID, opp.YesBook.BestBid()},
+		{opp.Market.NoToken().TokenID, opp.NoBook.BestBid()},
+	} {
+		if tok.id == "" || tok.bid <= 0 {
+			continue
+		}
+		for _, w := range le.wallets {
+			shares, err := w.Executor.TokenBalance(ctx, tok.id)
+			if err != nil {
+				slog.Debug("live: token balance check failed", "token", engine.TruncateStr(tok.id, 16), "err", err)
+				continue
+			}
+			total += shares * tok.bid
+		}
+	}
+	return total
+}
+
+// exposureCap is the most USDC one condition may hold: MaxMarketConcentration
+// of the deployable capital, but never less than one full pair.
+func (le *Engine) exposureCap(effectiveCapital float64) float64 {
+	return math.Max(effectiveCapital*le.cfg.MaxMarketConcentration, 2*le.cfg.OrderSize)
+}
+
+// capToExposure shrinks orderSize so a new pair keeps opp's condition under
+// the cap. Returns false when the room left is below the minimum order.
+func (le *Engine) capToExposure(ctx context.Context, opp domain.Opportunity, exp map[string]*condExposure, effectiveCapital, orderSize float64) (float64, bool) {
+	cid := opp.Market.ConditionID
+	e := exp[cid]
+	if e == nil {
+		e = &condExposure{question: opp.Market.Question}
+		exp[cid] = e
+	}
+	if !e.chainChecked {
+		// The store's inventory and the on-chain balance are the same tokens
+		if chain := le.heldTokenValue(ctx, opp); chain > e.held {
+			e.held = chain
+		}
+		e.chainChecked = true
+	}
+
+	room := le.exposureCap(effectiveCapital) - e.total()
+	if room/2 < orderSize {
+		orderSize = room / 2
+	}
+	if orderSize < minOrderSize(opp) {
+		slog.Info("live: concentration cap reached, skipping market",
+			"market", engine.TruncateStr(opp.Market.Question, 35),
+			"exposure", fmt.Sprintf("$%.2f", e.total()),
+			"cap", fmt.Sprintf("$%.2f", le.exposureCap(effectiveCapital)),
+		)
+		return 0, false
+	}
+	return orderSize, true
+}
+
+// topExposures lists the conditions with capital committed, largest first,
+// as a fraction of effectiveCapital.
+func topExposures(exp map[string]*condExposure, effectiveCapital float64) []domain.ConditionExposure {
+	out := make([]domain.ConditionExposure, 0, len(exp))
+	for cid, e := range exp {
+		if e.total() <= 0 {
+			continue
+		}
+		ce := domain.ConditionExposure{ConditionID: cid, Question: e.question, USDC: e.total()}
+		if effectiveCapital > 0 {
+			ce.Pct = e.total() / effectiveCapital
+		}
+		out = append(out, ce)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].USDC > out[j].USDC })
+	return out
+}
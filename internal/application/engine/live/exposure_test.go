@@ -0,0 +1,155 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// holdingExecutor reports the same token balance for every token.
+type holdingExecutor struct {
+	*shadowExecutor
+	shares float64
+}
+
+func (he holdingExecutor) TokenBalance(context.Context, string) (float64, error) {
+	return he.shares, nil
+}
+
+func exposureOpp(conditionID string) domain.Opportunity {
+	book := domain.OrderBook{
+		Bids: []domain.BookEntry{{Price: 0.48, Size: 100}},
+		Asks: []domain.BookEntry{{Price: 0.50, Size: 100}},
+	}
+	return domain.Opportunity{
+		Market: domain.Market{
+			ConditionID: conditionID,
+			Question:    "Will it rain?",
+			Tokens: [2]domain.Token{
+				{TokenID: "tok_yes", Outcome: "Yes"},
+				{TokenID: "tok_no", Outcome: "No"},
+			},
+		},
+		YesBook: book,
+		NoBook:  book,
+	}
+}
+
+func saveFilledPair(t *testing.T, db *storage.SQLiteStorage, conditionID string, size float64) []domain.LiveOrder {
+	t.Helper()
+	now := time.Now().UTC()
+	var legs []domain.LiveOrder
+	for _, side := range []string{"YES", "NO"} {
+		o := domain.LiveOrder{
+			ID:          conditionID + "-" + side,
+			CLOBOrderID: "clob-" + conditionID + "-" + side,
+			ConditionID: conditionID,
+			TokenID:     "tok_" + side,
+			Side:        side,
+			BidPrice:    0.48,
+			Size:        size,
+			FilledSize:  size,
+			PairID:      "pair-" + conditionID,
+			PlacedAt:    now,
+			FilledAt:    &now,
+			Status:      domain.LiveStatusFilled,
+		}
+		require.NoError(t, db.SaveLiveOrder(context.Background(), o))
+		legs = append(legs, o)
+	}
+	return legs
+}
+
+func TestExposure_FilledInventoryBlocksReentry(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	saveFilledPair(t, db, "0xcond", 5)
+
+	exp := le.conditionExposures(ctx)
+	require.Contains(t, exp, "0xcond")
+	assert.InDelta(t, 10, exp["0xcond"].total(), 1e-9)
+
+	// Cap = max(15% of $50, one $5 pair) = $10, already held
+	_, ok := le.capToExposure(ctx, exposureOpp("0xcond"), exp, 50, 5)
+	assert.False(t, ok)
+
+	size, ok := le.capToExposure(ctx, exposureOpp("0xother"), exp, 50, 5)
+	assert.True(t, ok)
+	assert.Equal(t, 5.0, size)
+}
+
+func TestExposure_MergeMidCycleFreesCondition(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	legs := saveFilledPair(t, db, "0xcond", 5)
+
+	_, ok := le.capToExposure(ctx, exposureOpp("0xcond"), le.conditionExposures(ctx), 50, 5)
+	require.False(t, ok)
+
+	// The merge step runs before placement in the same cycle
+	for _, o := range legs {
+		require.NoError(t, db.MarkLiveOrderMerged(ctx, o.ID, time.Now()))
+	}
+
+	exp := le.conditionExposures(ctx)
+	assert.NotContains(t, exp, "0xcond")
+	size, ok := le.capToExposure(ctx, exposureOpp("0xcond"), exp, 50, 5)
+	assert.True(t, ok)
+	assert.Equal(t, 5.0, size)
+}
+
+func TestExposure_PartialMergeFreesMergedPart(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	for _, o := range saveFilledPair(t, db, "0xcond", 5) {
+		require.NoError(t, db.AddLiveOrderMerged(ctx, o.ID, 3))
+	}
+
+	exp := le.conditionExposures(ctx)
+	assert.InDelta(t, 4, exp["0xcond"].total(), 1e-9)
+
+	// $6 of room left: the pair shrinks to $3 per side
+	size, ok := le.capToExposure(ctx, exposureOpp("0xcond"), exp, 50, 5)
+	assert.True(t, ok)
+	assert.InDelta(t, 3, size, 1e-9)
+}
+
+func TestExposure_HeldTokensCountAfterRotation(t *testing.T) {
+	ctx := context.Background()
+	// No orders left in live_orders, but 15 shares of each token at $0.48
+	le, _ := newTestEngine(t, holdingExecutor{newShadowExecutor(nil, 0), 15}, Config{OrderSize: 5})
+
+	exp := le.conditionExposures(ctx)
+	_, ok := le.capToExposure(ctx, exposureOpp("0xcond"), exp, 50, 5)
+	assert.False(t, ok)
+
+	top := topExposures(exp, 50)
+	require.Len(t, top, 1)
+	assert.InDelta(t, 14.4, top[0].USDC, 1e-9)
+	assert.InDelta(t, 0.288, top[0].Pct, 1e-9)
+}
+
+func TestExposure_SumsAllPairsInCondition(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	// An older pair still held plus a re-entry resting in the book
+	saveFilledPair(t, db, "0xcond", 3)
+	for _, side := range []string{"YES", "NO"} {
+		require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+			ID: "resting-" + side, CLOBOrderID: "clob-resting-" + side, ConditionID: "0xcond",
+			TokenID: "tok_" + side, Side: side, BidPrice: 0.47, Size: 2,
+			PairID: "pair-resting", PlacedAt: time.Now().UTC(), Status: domain.LiveStatusOpen,
+		}))
+	}
+
+	exp := le.conditionExposures(ctx)
+	assert.InDelta(t, 10, exp["0xcond"].total(), 1e-9)
+	_, ok := le.capToExposure(ctx, exposureOpp("0xcond"), exp, 50, 5)
+	assert.False(t, ok, "two pairs together already fill the $10 cap")
+}
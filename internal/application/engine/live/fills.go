@@ -0,0 +1,155 @@
+package live
+
+// fills.go — Fill detection from the CLOB trade history.
+//
+// Each trade names the order it filled, with the real price and match time,
+// so syncOrderState records fills from the wallet's trades first. Diffing the
+// open-order list is only the fallback for wallets whose trades could not be
+// fetched, or for trades the history does not show yet. Fills are keyed by
+// CLOB trade ID, so a trade seen both on the user stream and in the history
+// is recorded once.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// tradesLookback widens the trade history window before the oldest open
+// order, to absorb clock skew between the bot and the CLOB.
+const tradesLookback = 5 * time.Minute
+
+// tradesSince is where the trade history for orders must start.
+func tradesSince(orders []domain.LiveOrder) time.Time {
+	var oldest time.Time
+	for _, o := range orders {
+		if !o.PlacedAt.IsZero() && (oldest.IsZero() || o.PlacedAt.Before(oldest)) {
+			oldest = o.PlacedAt
+		}
+	}
+	if oldest.IsZero() {
+		return oldest
+	}
+	return oldest.Add(-tradesLookback)
+}
+
+// applyTrades records the trades of o that have no fill yet and returns the
+// order with its updated fill state.
+func (le *Engine) applyTrades(ctx context.Context, o domain.LiveOrder, trades []domain.LiveTrade) domain.LiveOrder {
+	for _, t := range trades {
+		if o.Status == domain.LiveStatusFilled {
+			break
+		}
+		if t.Size <= 0 {
+			continue
+		}
+		seen, err := le.store.HasLiveFill(ctx, o.ID, t.CLOBTradeID)
+		if err != nil {
+			slog.Warn("live: could not check fill", "id", o.ID, "trade", t.CLOBTradeID, "err", err)
+			continue
+		}
+		if seen {
+			continue
+		}
+
+		at := t.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		updated, err := le.recordFill(ctx, o, t.CLOBTradeID, t.Price, t.Size, at)
+		if err != nil {
+			slog.Warn("live: error recording trade fill", "id", o.ID, "err", err)
+			continue
+		}
+		o = updated
+
+		slog.Info("live: trade fill",
+			"side", o.Side,
+			"market", engine.TruncateStr(o.Question, 30),
+			"price", fmt.Sprintf("$%.2f", o.FilledPrice),
+			"filled", fmt.Sprintf("$%.2f/$%.2f", o.FilledSize, o.Size),
+			"status", o.Status,
+		)
+	}
+	return o
+}
+
+// recordFill adds size USDC filled at price to o, saves the fill and returns
+// the updated order. The order is FILLED once within 0.1% of its size, with
+// the fill's own time, so the merge delay clock starts when the trade matched.
+func (le *Engine) recordFill(ctx context.Context, o domain.LiveOrder, tradeID string, price, size float64, at time.Time) (domain.LiveOrder, error) {
+	filledSize := math.Min(o.FilledSize+size, o.Size)
+	status := domain.LiveStatusPartial
+	fillTime := at.UTC()
+
+	var filledAt *time.Time
+	if filledSize >= o.Size*0.999 {
+		status = domain.LiveStatusFilled
+		filledSize = o.Size
+		filledAt = &fillTime
+	}
+
+	if price <= 0 {
+		price = o.BidPrice
+	}
+
+	if err := le.store.UpdateLiveOrderFill(ctx, o.ID, filledSize, price, status, filledAt); err != nil {
+		return o, fmt.Errorf("recordFill: update fill: %w", err)
+	}
+
+	_ = le.store.SaveLiveFill(ctx, domain.LiveFill{
+		OrderID:     o.ID,
+		CLOBTradeID: tradeID,
+		Price:       price,
+		Size:        filledSize - o.FilledSize,
+		Timestamp:   fillTime,
+	})
+
+	o.FilledSize = filledSize
+	o.FilledPrice = price
+	o.Status = status
+	if filledAt != nil {
+		o.FilledAt = filledAt
+	}
+	return o, nil
+}
+
+// settleVanished closes an order that left the CLOB after its trades were
+// applied: FILLED with the size that actually traded (the rest was cancelled
+// or expired), or CANCELLED/EXPIRED when nothing traded. Returns whether it
+// was marked FILLED.
+func (le *Engine) settleVanished(ctx context.Context, o domain.LiveOrder) bool {
+	if o.FilledSize <= 0 {
+		status := orderGone(o, time.Now())
+		slog.Info("live: order gone with no trades — marking "+string(status),
+			"side", o.Side,
+			"market", engine.TruncateStr(o.Question, 30),
+			"clob_id", o.CLOBOrderID,
+		)
+		if err := le.store.UpdateLiveOrderStatus(ctx, o.ID, status); err != nil {
+			slog.Warn("live: error updating status", "id", o.ID, "err", err)
+		}
+		return false
+	}
+
+	price := o.FilledPrice
+	if price <= 0 {
+		price = o.BidPrice
+	}
+	now := time.Now().UTC()
+	if err := le.store.UpdateLiveOrderFill(ctx, o.ID, o.FilledSize, price, domain.LiveStatusFilled, &now); err != nil {
+		slog.Warn("live: error closing partially filled order", "id", o.ID, "err", err)
+		return false
+	}
+	slog.Info("live: order gone partially filled — FILLED with traded size",
+		"side", o.Side,
+		"market", engine.TruncateStr(o.Question, 30),
+		"filled", fmt.Sprintf("$%.2f/$%.2f", o.FilledSize, o.Size),
+	)
+	return true
+}
@@ -0,0 +1,84 @@
+package live
+
+// gas.go — POL gas runway.
+//
+// Merges pay gas in POL. A wallet that runs out cannot merge, so its filled
+// pairs would sit unmerged while the engine keeps buying more. Each cycle
+// checks every wallet's POL against the gas of MinGasRunwayMerges merges at
+// the current price; a wallet below it gets a warning and no new orders
+// until it is topped up.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// checkGasRunway flags the wallets whose POL cannot pay for
+// MinGasRunwayMerges merges and returns the POL held across the wallets it
+// could read, with one warning per flagged wallet.
+func (le *Engine) checkGasRunway(ctx context.Context, wallets []walletState) (float64, []string) {
+	var (
+		total    float64
+		warnings []string
+	)
+	for i := range wallets {
+		w := &wallets[i]
+		if w.Merger == nil {
+			continue
+		}
+		perMerge, err := w.Merger.EstimateGasCostPOL(ctx)
+		if err != nil || perMerge <= 0 {
+			continue
+		}
+		pol, err := w.Merger.GetPOLBalance(ctx)
+		if err != nil {
+			slog.Warn("live: POL balance unavailable, gas runway not checked",
+				"wallet", shortAddr(w.Address), "err", err)
+			continue
+		}
+		total += pol
+
+		runway := pol / perMerge
+		slog.Debug("live: gas runway",
+			"wallet", shortAddr(w.Address),
+			"pol", fmt.Sprintf("%.4f", pol),
+			"per_merge", fmt.Sprintf("%.5f", perMerge),
+			"merges", fmt.Sprintf("%.0f", runway),
+		)
+		if runway >= le.cfg.MinGasRunwayMerges {
+			continue
+		}
+		w.LowGas = true
+		label := shortAddr(w.Address)
+		if label == "" {
+			label = "primary"
+		}
+		slog.Warn("live: LOW GAS — not placing new orders from wallet",
+			"wallet", label, "pol", fmt.Sprintf("%.4f", pol), "merges_left", fmt.Sprintf("%.1f", runway))
+		warnings = append(warnings, fmt.Sprintf(
+			"LOW GAS: wallet %s has %.4f POL (~%.0f merges, min %.0f) — top up POL; no new orders from it",
+			label, pol, runway, le.cfg.MinGasRunwayMerges))
+	}
+	return total, warnings
+}
+
+// gasUsedPOLOn sums the POL burned by the successful merges of day (UTC).
+func (le *Engine) gasUsedPOLOn(ctx context.Context, day time.Time) float64 {
+	merges, err := le.store.GetMergeResults(ctx)
+	if err != nil {
+		return 0
+	}
+	y, m, d := day.UTC().Date()
+	var pol float64
+	for _, r := range merges {
+		if !r.Success {
+			continue
+		}
+		if ry, rm, rd := r.ExecutedAt.UTC().Date(); ry == y && rm == m && rd == d {
+			pol += r.GasUsedPOL
+		}
+	}
+	return pol
+}
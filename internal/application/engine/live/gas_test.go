@@ -0,0 +1,68 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// polMerger is a merger with a fixed POL balance and gas price.
+type polMerger struct {
+	shadowMerger
+	pol, perMerge float64
+}
+
+func (pm polMerger) EstimateGasCostPOL(context.Context) (float64, error) { return pm.perMerge, nil }
+func (pm polMerger) GetPOLBalance(context.Context) (float64, error)      { return pm.pol, nil }
+
+func TestCheckGasRunway_LowPOLStopsPlacement(t *testing.T) {
+	ctx := context.Background()
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 100), Config{OrderSize: 5})
+	le.wallets[0].Merger = polMerger{pol: 0.09, perMerge: 0.01}
+
+	states, _, err := le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	pol, warnings := le.checkGasRunway(ctx, states)
+	assert.InDelta(t, 0.09, pol, 1e-9)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "LOW GAS")
+	assert.Zero(t, states[0].headroom())
+	_, ok := pickWallet(states)
+	assert.False(t, ok, "no wallet to place from")
+}
+
+func TestCheckGasRunway_EnoughPOL(t *testing.T) {
+	ctx := context.Background()
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 100), Config{OrderSize: 5})
+	le.wallets[0].Merger = polMerger{pol: 0.10, perMerge: 0.01}
+
+	states, _, err := le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	_, warnings := le.checkGasRunway(ctx, states)
+	assert.Empty(t, warnings)
+	assert.False(t, states[0].LowGas)
+	_, ok := pickWallet(states)
+	assert.True(t, ok)
+}
+
+func TestGasUsedPOLOn_SumsTheDay(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, r := range []domain.MergeResult{
+		{ConditionID: "0xa", TxHash: "0x1", GasUsedPOL: 0.02, Success: true, ExecutedAt: day},
+		{ConditionID: "0xa", TxHash: "0x2", GasUsedPOL: 0.03, Success: true, ExecutedAt: day.Add(time.Hour)},
+		{ConditionID: "0xa", TxHash: "0x3", GasUsedPOL: 0.50, Error: "reverted", ExecutedAt: day},
+		{ConditionID: "0xa", TxHash: "0x4", GasUsedPOL: 0.07, Success: true, ExecutedAt: day.AddDate(0, 0, -1)},
+	} {
+		require.NoError(t, db.SaveMergeResult(ctx, r))
+	}
+
+	assert.InDelta(t, 0.05, le.gasUsedPOLOn(ctx, day), 1e-9)
+}
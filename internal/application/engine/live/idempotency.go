@@ -0,0 +1,125 @@
+package live
+
+// idempotency.go — Guard against placing the same order twice.
+//
+// A POST /order that times out may still have reached the CLOB. Each leg is
+// stored as PENDING under a deterministic placement key before it is sent,
+// and when the POST fails the wallet's open orders are checked for a matching
+// order before the leg is given up (and before the other leg is cancelled).
+// PENDING rows left behind by a crash mid-placement are settled by Reconcile.
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// matchesPlacement reports whether a CLOB order is the one placing o creates:
+// same token, price within half a tick, size within 1%.
+func matchesPlacement(co, o domain.LiveOrder) bool {
+	return co.TokenID == o.TokenID &&
+		math.Abs(co.BidPrice-o.BidPrice) < bidTickStep/2 &&
+		math.Abs(co.Size-o.Size) <= o.Size*0.01
+}
+
+// findPlaced looks for an open CLOB order matching o that live_orders does not
+// track yet. Returns its CLOB ID, or "" when there is none.
+func (le *Engine) findPlaced(ctx context.Context, exec ports.OrderExecutor, o domain.LiveOrder) (string, error) {
+	open, err := exec.GetOpenOrders(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, co := range open {
+		if !matchesPlacement(co, o) {
+			continue
+		}
+		if rec, err := le.store.GetLiveOrderByCLOBID(ctx, co.CLOBOrderID); err == nil && rec != nil {
+			continue
+		}
+		return co.CLOBOrderID, nil
+	}
+	return "", nil
+}
+
+// placeLeg sends one leg of a pair at most once and stores it. A matching
+// untracked order already on the CLOB is adopted instead of placing another.
+// On error the leg is CANCELLED when the CLOB confirms it is absent, and left
+// PENDING for Reconcile when that cannot be checked.
+func (le *Engine) placeLeg(ctx context.Context, wallet Wallet, o *domain.LiveOrder, req domain.PlaceOrderRequest) error {
+	o.PlacementKey = domain.PlacementKey(o.PairID, o.Side, o.BidPrice)
+
+	if id, err := le.findPlaced(ctx, wallet.Executor, *o); err != nil {
+		slog.Debug("live: open-orders check before placement failed", "side", o.Side, "err", err)
+	} else if id != "" {
+		slog.Warn("live: matching order already on the CLOB, adopting it",
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "clob_id", id)
+		le.confirmLeg(ctx, o, id)
+		return nil
+	}
+
+	o.Status = domain.LiveStatusPending
+	if err := le.store.SaveLiveOrder(ctx, *o); err != nil {
+		slog.Warn("live: error saving pending order", "side", o.Side, "err", err)
+	}
+
+	placed, err := wallet.Executor.PlaceOrder(ctx, req)
+	if err == nil {
+		le.confirmLeg(ctx, o, placed.CLOBOrderID)
+		return nil
+	}
+	if errors.Is(err, domain.ErrOrderRejected) {
+		le.rejected[o.ConditionID] = true
+	}
+
+	id, findErr := le.findPlaced(ctx, wallet.Executor, *o)
+	switch {
+	case findErr != nil:
+		slog.Warn("live: placement failed and could not be verified, left PENDING",
+			"side", o.Side, "key", o.PlacementKey, "err", err, "verify_err", findErr)
+	case id != "":
+		slog.Warn("live: placement errored but the order is on the CLOB, adopting it",
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "clob_id", id, "err", err)
+		le.confirmLeg(ctx, o, id)
+		return nil
+	default:
+		o.Status = domain.LiveStatusCancelled
+		if err := le.store.UpdateLiveOrderStatus(ctx, o.ID, o.Status); err != nil {
+			slog.Warn("live: error updating failed order", "side", o.Side, "err", err)
+		}
+	}
+	return err
+}
+
+// confirmLeg stores o as OPEN under the CLOB order ID it was placed with.
+func (le *Engine) confirmLeg(ctx context.Context, o *domain.LiveOrder, clobOrderID string) {
+	o.CLOBOrderID = clobOrderID
+	o.Status = domain.LiveStatusOpen
+	if err := le.store.SaveLiveOrder(ctx, *o); err != nil {
+		slog.Warn("live: error saving "+o.Side+" order", "err", err)
+	}
+}
+
+// settlePending resolves a PENDING order found at startup: adopted when a
+// matching untracked order rests on the CLOB, otherwise treated as vanished.
+// Returns the CLOB ID it was adopted under ("" if none) and whether it was
+// marked FILLED.
+func (le *Engine) settlePending(ctx context.Context, o domain.LiveOrder, clobByID map[string]domain.LiveOrder, known map[string]bool, trades walletTrades) (string, bool) {
+	for id, co := range clobByID {
+		if known[id] || !matchesPlacement(co, o) {
+			continue
+		}
+		if rec, err := le.store.GetLiveOrderByCLOBID(ctx, id); err == nil && rec != nil {
+			continue
+		}
+		le.confirmLeg(ctx, &o, id)
+		slog.Info("live: reconcile: pending order found on the CLOB — OPEN",
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "clob_id", id)
+		return id, false
+	}
+	return "", le.reconcileVanished(ctx, o, trades)
+}
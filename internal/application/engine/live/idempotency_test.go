@@ -0,0 +1,136 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// timeoutExecutor reaches the book but loses the response, like a POST that
+// times out after the CLOB accepted it.
+type timeoutExecutor struct{ *shadowExecutor }
+
+func (te timeoutExecutor) PlaceOrder(ctx context.Context, req domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
+	_, _ = te.shadowExecutor.PlaceOrder(ctx, req)
+	return domain.PlacedOrder{}, context.DeadlineExceeded
+}
+
+// rejectExecutor fails every placement without reaching the book.
+type rejectExecutor struct{ *shadowExecutor }
+
+func (re rejectExecutor) PlaceOrder(context.Context, domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
+	return domain.PlacedOrder{}, errors.New("rejected")
+}
+
+// clobRejectExecutor fails every placement with err, as the CLOB client
+// classifies it.
+type clobRejectExecutor struct {
+	*shadowExecutor
+	err error
+}
+
+func (ce clobRejectExecutor) PlaceOrder(context.Context, domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
+	return domain.PlacedOrder{}, ce.err
+}
+
+func pendingLeg() (domain.LiveOrder, domain.PlaceOrderRequest) {
+	o := domain.LiveOrder{
+		ID:          "local-yes",
+		ConditionID: "0xcond",
+		TokenID:     "tok_yes",
+		Side:        "YES",
+		BidPrice:    0.42,
+		Size:        10,
+		PairID:      "pair-1",
+		PlacedAt:    time.Now().UTC(),
+	}
+	req := domain.PlaceOrderRequest{TokenID: o.TokenID, ConditionID: o.ConditionID, Price: o.BidPrice, Size: o.Size, Side: "BUY"}
+	return o, req
+}
+
+func TestPlaceLeg_AdoptsOrderPlacedDespiteTimeout(t *testing.T) {
+	ctx := context.Background()
+	book := newShadowExecutor(nil, 0)
+	le, db := newTestEngine(t, timeoutExecutor{book}, Config{})
+
+	o, req := pendingLeg()
+	require.NoError(t, le.placeLeg(ctx, le.wallets[0], &o, req))
+
+	resting, err := book.GetOpenOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, resting, 1, "the timed-out POST must not be sent twice")
+	assert.Equal(t, resting[0].CLOBOrderID, o.CLOBOrderID)
+
+	rec, err := db.GetLiveOrderByCLOBID(ctx, o.CLOBOrderID)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, domain.LiveStatusOpen, rec.Status)
+	assert.Equal(t, domain.PlacementKey("pair-1", "YES", 0.42), rec.PlacementKey)
+
+}
+
+func TestPlaceLeg_RejectedOrderIsCancelled(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, rejectExecutor{newShadowExecutor(nil, 0)}, Config{})
+
+	o, req := pendingLeg()
+	require.Error(t, le.placeLeg(ctx, le.wallets[0], &o, req))
+
+	cancelled, err := db.GetAllLiveOrders(ctx, string(domain.LiveStatusCancelled))
+	require.NoError(t, err)
+	require.Len(t, cancelled, 1)
+	assert.Equal(t, "local-yes", cancelled[0].ID)
+}
+
+func TestReconcile_AdoptsPendingOrderOnCLOB(t *testing.T) {
+	ctx := context.Background()
+	book := newShadowExecutor(nil, 0)
+	le, db := newTestEngine(t, book, Config{})
+
+	// Crash between the POST and saving its response: the row is PENDING
+	// while the order rests on the CLOB.
+	o, req := pendingLeg()
+	o.Status = domain.LiveStatusPending
+	o.PlacementKey = domain.PlacementKey(o.PairID, o.Side, o.BidPrice)
+	require.NoError(t, db.SaveLiveOrder(ctx, o))
+	placed, err := book.PlaceOrder(ctx, req)
+	require.NoError(t, err)
+
+	res, err := le.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.PendingAdopted)
+	assert.Zero(t, res.Orphans)
+
+	rec, err := db.GetLiveOrderByCLOBID(ctx, placed.CLOBOrderID)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "local-yes", rec.ID)
+	assert.Equal(t, domain.LiveStatusOpen, rec.Status)
+}
+
+func TestPlaceLeg_CLOBRejectionBlocksConditionForCycle(t *testing.T) {
+	ctx := context.Background()
+	exec := clobRejectExecutor{newShadowExecutor(nil, 0), domain.ErrNonRetryable}
+	le, _ := newTestEngine(t, exec, Config{})
+
+	o, req := pendingLeg()
+	require.ErrorIs(t, le.placeLeg(ctx, le.wallets[0], &o, req), domain.ErrNonRetryable)
+	assert.False(t, le.rejected["0xcond"], "a malformed order only skips this placement")
+
+	exec.err = fmt.Errorf("place order: %w: invalid tick size", domain.ErrOrderRejected)
+	le.wallets[0].Executor = exec
+	o, req = pendingLeg()
+	require.ErrorIs(t, le.placeLeg(ctx, le.wallets[0], &o, req), domain.ErrOrderRejected)
+	assert.True(t, le.rejected["0xcond"])
+
+	skip, reason := le.gateCheck(exposureOpp("0xcond"), nil, 0)
+	assert.True(t, skip)
+	assert.Equal(t, skipReasonRejected, reason)
+}
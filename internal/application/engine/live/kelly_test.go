@@ -0,0 +1,35 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestKellyFraction_UsesConfiguredMultiplierAndWarmup(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.Kelly = engine.KellyConfig{Multiplier: 0.25, WarmupMerges: 4}.WithDefaults(defaultKelly)
+
+	// Three wins of $0.30 and a $0.10 loss: p = 0.75, b = 3, full Kelly 2/3.
+	for i, profit := range []float64{0.3, 0.3, 0.3, -0.1} {
+		applied, full := le.kellyFraction(ctx)
+		assert.InDelta(t, 0.5, applied, 1e-9, "warming up after %d merges", i)
+		assert.True(t, math.IsNaN(full))
+		require.NoError(t, db.SaveMergeResult(ctx, domain.MergeResult{
+			ConditionID: "0xa", TxHash: fmt.Sprintf("0x%d", i), SpreadProfit: profit, Success: true, ExecutedAt: time.Now(),
+		}))
+	}
+
+	applied, full := le.kellyFraction(ctx)
+	assert.InDelta(t, 2.0/3, full, 1e-9)
+	assert.InDelta(t, 1.0/6, applied, 1e-9, "quarter-Kelly")
+}
@@ -0,0 +1,98 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestPlaceOrderPair_Ladder(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.Ladder = engine.LadderConfig{NumLevels: 3, TickSpacing: 0.01, SizeDistribution: []float64{5, 3, 2}}
+
+	orders, deployed, err := le.placeOrderPair(ctx, exposureOpp("0xcond"), 20, le.wallets[0])
+	require.NoError(t, err)
+	assert.Equal(t, 6, orders)
+	assert.InDelta(t, 40, deployed, 0.06, "each leg commits the whole shares its USDC buys")
+	assert.LessOrEqual(t, deployed, 40.0)
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 6)
+
+	group := engine.PairGroup(open[0].PairID)
+	yes := make(map[string]domain.LiveOrder)
+	for _, o := range open {
+		assert.Equal(t, group, engine.PairGroup(o.PairID), "levels share the pair group")
+		if o.Side == "YES" {
+			yes[o.PairID] = o
+		}
+	}
+	require.Len(t, yes, 3)
+
+	top := yes[engine.LadderPairID(group, 0, 3)]
+	for i, size := range []float64{10, 6, 4} {
+		lvl := yes[engine.LadderPairID(group, i, 3)]
+		assert.InDelta(t, size, lvl.Size, 0.01, "level %d size", i)
+		assert.Equal(t, domain.SharesAt(size, lvl.BidPrice), lvl.SizeShares, "level %d shares", i)
+		assert.InDelta(t, lvl.SizeShares*lvl.BidPrice, lvl.Size, 1e-9, "level %d size is shares × bid", i)
+		assert.InDelta(t, top.BidPrice-float64(i)*0.01, lvl.BidPrice, 1e-9, "level %d price", i)
+	}
+}
+
+func TestPlaceOrderPair_SingleLevelKeepsPairID(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+
+	orders, deployed, err := le.placeOrderPair(ctx, exposureOpp("0xcond"), 10, le.wallets[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, orders)
+	assert.InDelta(t, 20, deployed, 0.02)
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, open[0].PairID, engine.PairGroup(open[0].PairID))
+}
+
+func TestMergeCompletePairs_SumsLadderLevels(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.merger = shadowMerger{}
+	le.wallets[0].Merger = shadowMerger{}
+
+	// YES filled on level 0 and NO filled on level 1: together they hedge
+	// 10 sets even though neither level is complete on its own.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	legs := []domain.LiveOrder{
+		{ID: "l0-yes", PairID: "pair/L0", Side: "YES", BidPrice: 0.45, Size: 4.5, FilledSize: 4.5, Status: domain.LiveStatusFilled, FilledAt: &filledAt},
+		{ID: "l0-no", PairID: "pair/L0", Side: "NO", BidPrice: 0.45, Size: 4.5, Status: domain.LiveStatusOpen},
+		{ID: "l1-yes", PairID: "pair/L1", Side: "YES", BidPrice: 0.44, Size: 4.4, Status: domain.LiveStatusOpen},
+		{ID: "l1-no", PairID: "pair/L1", Side: "NO", BidPrice: 0.44, Size: 4.4, FilledSize: 4.4, Status: domain.LiveStatusFilled, FilledAt: &filledAt},
+	}
+	for _, o := range legs {
+		o.ConditionID, o.TokenID, o.PlacedAt = "0xcond", "tok_"+o.Side, filledAt
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+
+	merges, profit, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, merges)
+	assert.InDelta(t, 10-4.5-4.4-gasFallbackUSD, profit, 1e-9)
+
+	for _, pairID := range []string{"pair/L0", "pair/L1"} {
+		orders, err := db.GetLiveOrdersByPair(ctx, pairID)
+		require.NoError(t, err)
+		for _, o := range orders {
+			assert.InDelta(t, 0, o.UnmergedSize(), 1e-9, "%s fully merged", o.ID)
+			assert.NotEqual(t, domain.LiveStatusMerged, o.Status, "%s: its level is still resting", o.ID)
+		}
+	}
+}
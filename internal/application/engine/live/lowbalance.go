@@ -0,0 +1,47 @@
+package live
+
+// lowbalance.go — low USDC alert.
+//
+// When the wallets run short of USDC (gas swaps drained it, or funds were
+// withdrawn) every opportunity is skipped with skip_size and nothing says
+// why. Each cycle compares the USDC held on-chain with MinBalanceUSDC and
+// with the cost of one minimum pair, and warns with the exact shortfall.
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// minPairUSDC is the most a minimum pair can cost: minShares of YES and of
+// NO, whose bids always sum to under $1.
+const minPairUSDC = float64(minShares)
+
+// checkLowBalance reports whether the wallets hold less USDC than
+// max(MinBalanceUSDC, minPairUSDC), with the warning for the cycle result.
+// With PauseOnLowBalance it also pauses the circuit breaker.
+func (le *Engine) checkLowBalance(wallets []walletState) (bool, string) {
+	var usdc float64
+	for _, w := range wallets {
+		usdc += w.USDC
+	}
+	need := math.Max(le.cfg.MinBalanceUSDC, minPairUSDC)
+	if usdc >= need {
+		return false, ""
+	}
+	shortfall := need - usdc
+
+	slog.Warn("live: LOW USDC BALANCE — not placing new orders",
+		"usdc", fmt.Sprintf("$%.2f", usdc),
+		"needed", fmt.Sprintf("$%.2f", need),
+		"shortfall", fmt.Sprintf("$%.2f", shortfall))
+	warning := fmt.Sprintf("LOW BALANCE: wallets hold $%.2f USDC, need $%.2f — short $%.2f; top up USDC",
+		usdc, need, shortfall)
+	if le.cfg.PauseOnLowBalance {
+		le.breaker.Pause(domain.BreakerReasonBalance)
+		warning += fmt.Sprintf(" (circuit breaker paused until %s)", le.breaker.CooldownUntil.Format("15:04:05"))
+	}
+	return true, warning
+}
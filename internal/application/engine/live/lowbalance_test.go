@@ -0,0 +1,46 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestCheckLowBalance_BelowOnePair(t *testing.T) {
+	ctx := context.Background()
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 3), Config{OrderSize: 5})
+
+	states, _, err := le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	low, warning := le.checkLowBalance(states)
+	assert.True(t, low)
+	assert.Contains(t, warning, "need $5.00 — short $2.00")
+	assert.True(t, le.breaker.IsOpen(), "no pause unless configured")
+}
+
+func TestCheckLowBalance_MinBalancePausesBreaker(t *testing.T) {
+	ctx := context.Background()
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 12), Config{OrderSize: 5})
+	le.cfg.MinBalanceUSDC = 20
+	le.cfg.PauseOnLowBalance = true
+	le.breaker.CooldownDuration = time.Hour
+
+	states, _, err := le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	low, warning := le.checkLowBalance(states)
+	assert.True(t, low)
+	assert.Contains(t, warning, "short $8.00")
+	assert.False(t, le.breaker.IsOpen())
+	assert.Equal(t, domain.BreakerReasonBalance, le.breaker.TriggeredReason)
+
+	le.cfg.MinBalanceUSDC = 10
+	le.breaker.Reset()
+	low, _ = le.checkLowBalance(states)
+	assert.False(t, low)
+	assert.True(t, le.breaker.IsOpen())
+}
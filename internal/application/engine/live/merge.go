@@ -2,25 +2,52 @@ package live
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 	"github.com/alejandrodnm/polybot/internal/application/engine"
 )
 
-// mergeCompletePairs executes real on-chain merges for fully filled pairs.
+// mergeCompletePairs executes real on-chain merges for filled pairs. The
+// levels of a ladder (engine.PairGroup) are merged together: their YES and NO
+// fills are summed before deciding what can be merged. A group with every leg
+// filled merges what is left and is retired; when one side filled more than
+// the other, its extra shares stay tracked as a remainder (retireGroup). A
+// group whose legs are still filling merges the hedged overlap once it
+// reaches MinPartialMergeSets; the merged USDC is recorded per leg and the
+// unfilled remainder keeps resting.
+//
+// A group whose condition resolved on-chain is left to redeemResolved.
+//
+// Once a merger refuses a merge because gas is above its ceiling
+// (domain.GasCeilingError), every other merge this cycle is deferred too.
 func (le *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProfit, totalGas float64, err error) {
 	filledOrders, err := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusFilled))
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("mergeCompletePairs: %w", err)
 	}
+	openOrders, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("mergeCompletePairs: %w", err)
+	}
 
-	byPair := make(map[string][]domain.LiveOrder)
-	for _, o := range filledOrders {
-		byPair[o.PairID] = append(byPair[o.PairID], o)
+	byGroup := make(map[string][]domain.LiveOrder)
+	resting := make(map[string]bool)
+	for _, o := range append(filledOrders, openOrders...) {
+		if o.IsSell() {
+			continue
+		}
+		group := engine.PairGroup(o.PairID)
+		if o.Status == domain.LiveStatusOpen {
+			resting[group] = true
+			continue
+		}
+		byGroup[group] = append(byGroup[group], o)
 	}
 
 	now := time.Now().UTC()
@@ -28,55 +55,84 @@ func (le *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 
 	gasCostUSD, _ := le.merger.EstimateGasCostUSD(ctx)
 	if gasCostUSD <= 0 {
-		gasCostUSD = 0.05
+		gasCostUSD = gasFallbackUSD
 	}
+	bufferedGasUSD := gasCostUSD * (1 + le.cfg.GasBufferPct)
 
-	for _, orders := range byPair {
-		var yes, no *domain.LiveOrder
-		for i := range orders {
-			switch orders[i].Side {
+	var ceiling *domain.GasCeilingError
+	deferred := 0
+	for group, orders := range byGroup {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].PairID < orders[j].PairID })
+		var yes, no []domain.LiveOrder
+		complete := !resting[group]
+		var lastFillTime time.Time
+		for _, o := range orders {
+			switch o.Side {
 			case "YES":
-				yes = &orders[i]
+				yes = append(yes, o)
 			case "NO":
-				no = &orders[i]
+				no = append(no, o)
+			}
+			if o.Status != domain.LiveStatusFilled {
+				complete = false
+			}
+			at := o.PlacedAt
+			if o.FilledAt != nil {
+				at = *o.FilledAt
+			}
+			if at.After(lastFillTime) {
+				lastFillTime = at
 			}
 		}
-		if yes == nil || no == nil {
+		if len(yes) == 0 || len(no) == 0 {
 			continue
 		}
-
-		lastFillTime := yes.PlacedAt
-		if yes.FilledAt != nil && yes.FilledAt.After(lastFillTime) {
-			lastFillTime = *yes.FilledAt
-		}
-		if no.FilledAt != nil && no.FilledAt.After(lastFillTime) {
-			lastFillTime = *no.FilledAt
+		if le.conditionResolved(ctx, yes[0].ConditionID) {
+			// A resolved condition cannot be merged: redeemResolved redeems
+			// the held legs later this cycle.
+			slog.Warn("live: market RESOLVED with pair held — redeeming instead of merging",
+				"market", engine.TruncateStr(yes[0].Question, 30),
+				"complete", complete,
+				"sets", fmt.Sprintf("%.2f", math.Min(unmergedSets(yes), unmergedSets(no))),
+			)
+			continue
 		}
 		if now.Sub(lastFillTime) < mergeDelay {
 			continue
 		}
 
-		yesSets := yes.FilledSize / yes.BidPrice
-		noSets := no.FilledSize / no.BidPrice
-		mergeable := math.Min(yesSets, noSets)
-		if mergeable < 1 {
+		mergeable := math.Min(unmergedSets(yes), unmergedSets(no))
+		if complete && mergeable < 1 && anyMerged(orders) {
+			// Partial merges already took everything that can be merged.
+			mergedAt := time.Now().UTC()
+			for _, o := range orders {
+				_ = le.store.MarkLiveOrderMerged(ctx, o.ID, mergedAt)
+			}
+			continue
+		}
+		minSets := 1.0
+		if !complete {
+			minSets = le.cfg.MinPartialMergeSets
+		}
+		if mergeable < minSets {
 			continue
 		}
 		mergeAmountUSDC := math.Floor(mergeable)
 
-		yesCostMerged := mergeAmountUSDC * yes.BidPrice
-		noCostMerged := mergeAmountUSDC * no.BidPrice
-		capitalSpent := yesCostMerged + noCostMerged
+		yesMerged := allocateMerge(yes, mergeAmountUSDC)
+		noMerged := allocateMerge(no, mergeAmountUSDC)
+		capitalSpent := sumValues(yesMerged) + sumValues(noMerged)
 		grossReceipt := mergeAmountUSDC
 		spread := grossReceipt - capitalSpent
+		question := yes[0].Question
 
 		netProfit := spread - gasCostUSD
-		if netProfit < le.cfg.MinMergeProfit {
+		if spread-bufferedGasUSD < le.cfg.MinMergeProfit {
 			slog.Debug("live: skipping merge (not profitable after gas)",
-				"market", engine.TruncateStr(yes.Question, 30),
+				"market", engine.TruncateStr(question, 30),
 				"spread", fmt.Sprintf("$%.4f", spread),
-				"gas", fmt.Sprintf("$%.4f", gasCostUSD),
-				"net", fmt.Sprintf("$%.4f", netProfit),
+				"gas", fmt.Sprintf("$%.4f", bufferedGasUSD),
+				"net", fmt.Sprintf("$%.4f", spread-bufferedGasUSD),
 			)
 			if netProfit < 0 {
 				le.breaker.RecordLoss(netProfit)
@@ -84,22 +140,38 @@ func (le *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 			continue
 		}
 
-		mergeResult, err := le.merger.MergePositions(ctx, yes.ConditionID, mergeAmountUSDC, yes.NegRisk)
+		if ceiling != nil {
+			deferred++
+			continue
+		}
+		mergeResult, err := le.mergerFor(yes[0]).MergePositions(ctx, yes[0].ConditionID, mergeAmountUSDC, yes[0].NegRisk)
+		if errors.As(err, &ceiling) {
+			deferred++
+			continue
+		}
+		le.saveFailedMergeAttempts(ctx, group, mergeResult)
 		if err != nil {
-			slog.Warn("live: merge failed", "condition", yes.ConditionID, "err", err)
+			slog.Warn("live: merge failed", "condition", yes[0].ConditionID,
+				"attempts", len(mergeResult.FailedAttempts), "err", err)
 			continue
 		}
 
-		mergeResult.PairID = yes.PairID
+		mergeResult.PairID = group
 		mergeResult.SpreadProfit = netProfit
 
 		if err := le.store.SaveMergeResult(ctx, mergeResult); err != nil {
 			slog.Warn("live: error saving merge result", "err", err)
 		}
 
-		mergedAt := time.Now().UTC()
-		_ = le.store.MarkLiveOrderMerged(ctx, yes.ID, mergedAt)
-		_ = le.store.MarkLiveOrderMerged(ctx, no.ID, mergedAt)
+		if complete {
+			le.retireGroup(ctx, orders, yesMerged, noMerged)
+		} else {
+			for _, merged := range []map[string]float64{yesMerged, noMerged} {
+				for id, usdc := range merged {
+					_ = le.store.AddLiveOrderMerged(ctx, id, usdc)
+				}
+			}
+		}
 
 		merges++
 		totalProfit += netProfit
@@ -112,7 +184,10 @@ func (le *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 		}
 
 		slog.Info("live: MERGED pair",
-			"market", engine.TruncateStr(yes.Question, 30),
+			"market", engine.TruncateStr(question, 30),
+			"partial", !complete,
+			"levels", max(len(yes), len(no)),
+			"sets", fmt.Sprintf("%.0f", mergeAmountUSDC),
 			"usdc_in", fmt.Sprintf("$%.2f", capitalSpent),
 			"usdc_out", fmt.Sprintf("$%.2f", grossReceipt),
 			"gas", fmt.Sprintf("$%.4f", gasCostUSD),
@@ -120,5 +195,106 @@ func (le *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 		)
 	}
 
+	if deferred > 0 {
+		slog.Warn("live: merges deferred, gas above ceiling",
+			"deferred", deferred,
+			"gas_gwei", fmt.Sprintf("%.1f", ceiling.PriceGwei),
+			"max_gwei", fmt.Sprintf("%.1f", ceiling.CeilingGwei),
+		)
+	}
+
 	return merges, totalProfit, totalGas, nil
 }
+
+// retireGroup closes a fully filled group after its merge. Legs whose fill
+// was merged entirely are marked MERGED. A leg left with at least minShares
+// unmerged (one side filled more than the other) records them as its
+// Remainder and stays FILLED so flattenStalePartials sells them; a smaller
+// leftover cannot be sold on the CLOB and is retired as dust, still noted on
+// the leg.
+func (le *Engine) retireGroup(ctx context.Context, orders []domain.LiveOrder, merged ...map[string]float64) {
+	mergedAt := time.Now().UTC()
+	for _, o := range orders {
+		var usdc float64
+		for _, m := range merged {
+			usdc += m[o.ID]
+		}
+		left := o.UnmergedShares() - o.SharesFor(usdc)
+		if left > 0.01 {
+			_ = le.store.SetLiveOrderRemainder(ctx, o.ID, left)
+		}
+		if left < minShares {
+			_ = le.store.MarkLiveOrderMerged(ctx, o.ID, mergedAt)
+			continue
+		}
+		_ = le.store.AddLiveOrderMerged(ctx, o.ID, usdc)
+		slog.Warn("live: merge left unhedged shares, unwinding them",
+			"market", engine.TruncateStr(o.Question, 30),
+			"side", o.Side,
+			"shares", fmt.Sprintf("%.2f", left),
+		)
+	}
+}
+
+// unmergedSets is the number of complete sets the legs of one side can still
+// contribute to a merge, from the shares each leg actually signed.
+func unmergedSets(legs []domain.LiveOrder) float64 {
+	var sets float64
+	for _, o := range legs {
+		sets += o.UnmergedShares()
+	}
+	return sets
+}
+
+// allocateMerge takes sets from the legs of one side in order and returns the
+// USDC cost merged from each leg, by order ID.
+func allocateMerge(legs []domain.LiveOrder, sets float64) map[string]float64 {
+	merged := make(map[string]float64, len(legs))
+	for _, o := range legs {
+		if sets <= 0 {
+			break
+		}
+		take := math.Min(sets, o.UnmergedShares())
+		if take <= 0 {
+			continue
+		}
+		merged[o.ID] = o.CostOf(take)
+		sets -= take
+	}
+	return merged
+}
+
+func anyMerged(orders []domain.LiveOrder) bool {
+	for _, o := range orders {
+		if o.MergedSize > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sumValues(m map[string]float64) float64 {
+	var total float64
+	for _, v := range m {
+		total += v
+	}
+	return total
+}
+
+// saveFailedMergeAttempts persists every unsuccessful submission of a merge
+// as a success=0 row so reverts and gas problems are visible in live_merges.
+func (le *Engine) saveFailedMergeAttempts(ctx context.Context, pairID string, res domain.MergeResult) {
+	for _, a := range res.FailedAttempts {
+		failed := domain.MergeResult{
+			ConditionID: res.ConditionID,
+			PairID:      pairID,
+			TxHash:      a.TxHash,
+			Success:     false,
+			Error:       fmt.Sprintf("%s (gas %.1f gwei)", a.Error, a.GasPriceGwei),
+			ExecutedAt:  a.At,
+		}
+		if err := le.store.SaveMergeResult(ctx, failed); err != nil {
+			slog.Warn("live: error saving failed merge attempt", "err", err)
+		}
+	}
+}
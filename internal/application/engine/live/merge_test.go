@@ -0,0 +1,163 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestMergeCompletePairs_TracksAsymmetricRemainder(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.merger = shadowMerger{}
+	le.wallets[0].Merger = shadowMerger{}
+
+	// YES filled 20 shares, NO only 10: 10 sets merge and 10 YES are left.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, o := range []domain.LiveOrder{
+		{ID: "yes", Side: "YES", BidPrice: 0.45, Size: 9, FilledSize: 9},
+		{ID: "no", Side: "NO", BidPrice: 0.45, Size: 4.5, FilledSize: 4.5},
+	} {
+		o.ConditionID, o.TokenID, o.PairID, o.Question = "0xcond", "tok_"+o.Side, "pair", "Will it rain?"
+		o.Status, o.PlacedAt, o.FilledAt = domain.LiveStatusFilled, filledAt, &filledAt
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+
+	merges, _, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, merges)
+
+	legs, err := db.GetLiveOrdersByPair(ctx, "pair")
+	require.NoError(t, err)
+	byID := make(map[string]domain.LiveOrder)
+	for _, o := range legs {
+		byID[o.ID] = o
+	}
+	assert.Equal(t, domain.LiveStatusMerged, byID["no"].Status)
+	yes := byID["yes"]
+	assert.Equal(t, domain.LiveStatusFilled, yes.Status, "the remainder keeps the leg open")
+	assert.InDelta(t, 10, yes.Remainder, 1e-9)
+	assert.InDelta(t, 4.5, yes.UnmergedSize(), 1e-9)
+	assert.True(t, yes.Stranded())
+
+	shares, usdc := le.strandedRemainders(ctx)
+	assert.InDelta(t, 10, shares, 1e-9)
+	assert.InDelta(t, 4.5, usdc, 1e-9)
+
+	// The remainder is unwound without waiting for MaxPartialHours.
+	le.flattenStalePartials(ctx)
+	legs, err = db.GetLiveOrdersByPair(ctx, "pair")
+	require.NoError(t, err)
+	var sells int
+	for _, o := range legs {
+		if o.IsSell() {
+			sells++
+			assert.Equal(t, "YES", o.Side)
+		}
+	}
+	assert.Equal(t, 1, sells)
+}
+
+func TestMergeCompletePairs_SymmetricFillsLeaveNoRemainder(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.merger = shadowMerger{}
+	le.wallets[0].Merger = shadowMerger{}
+
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, side := range []string{"YES", "NO"} {
+		require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+			ID: side, ConditionID: "0xcond", TokenID: "tok_" + side, Side: side, PairID: "pair",
+			BidPrice: 0.45, Size: 4.5, FilledSize: 4.5, Status: domain.LiveStatusFilled,
+			PlacedAt: filledAt, FilledAt: &filledAt,
+		}))
+	}
+
+	merges, _, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, merges)
+
+	legs, err := db.GetLiveOrdersByPair(ctx, "pair")
+	require.NoError(t, err)
+	for _, o := range legs {
+		assert.Equal(t, domain.LiveStatusMerged, o.Status)
+		assert.Zero(t, o.Remainder)
+	}
+}
+
+func TestMergeCompletePairs_MergesSignedShares(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.merger = shadowMerger{}
+	le.wallets[0].Merger = shadowMerger{}
+
+	// $3 at 0.30 reads as 10 shares, but the CLOB signed 9.99: only 9 sets
+	// can be merged.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, o := range []domain.LiveOrder{
+		{ID: "yes", Side: "YES", BidPrice: 0.30, Size: 3, SizeShares: 9.99, FilledSize: 3},
+		{ID: "no", Side: "NO", BidPrice: 0.60, Size: 6, SizeShares: 9.99, FilledSize: 6},
+	} {
+		o.ConditionID, o.TokenID, o.PairID, o.Question = "0xcond", "tok_"+o.Side, "pair", "Will it rain?"
+		o.Status, o.PlacedAt, o.FilledAt = domain.LiveStatusFilled, filledAt, &filledAt
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+
+	merges, _, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, merges)
+
+	results, err := db.GetMergeResults(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.InDelta(t, 9, results[0].USDCReceived, 1e-9)
+}
+
+// ceilingMerger refuses every merge as if gas were above its ceiling.
+type ceilingMerger struct {
+	shadowMerger
+	calls *int
+}
+
+func (cm ceilingMerger) MergePositions(context.Context, string, float64, bool) (domain.MergeResult, error) {
+	*cm.calls++
+	return domain.MergeResult{}, fmt.Errorf("merge: %w", &domain.GasCeilingError{PriceGwei: 900, CeilingGwei: 300})
+}
+
+func TestMergeCompletePairs_DefersAllMergesAboveGasCeiling(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	var calls int
+	le.merger = ceilingMerger{calls: &calls}
+	le.wallets[0].Merger = ceilingMerger{calls: &calls}
+
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, pair := range []string{"p1", "p2"} {
+		for _, side := range []string{"YES", "NO"} {
+			require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+				ID: pair + side, ConditionID: "0x" + pair, TokenID: "tok_" + side, Side: side, PairID: pair,
+				BidPrice: 0.45, Size: 4.5, FilledSize: 4.5, Status: domain.LiveStatusFilled,
+				PlacedAt: filledAt, FilledAt: &filledAt,
+			}))
+		}
+	}
+
+	merges, _, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, merges)
+	assert.Equal(t, 1, calls, "the second pair is deferred without asking the merger")
+
+	for _, pair := range []string{"p1", "p2"} {
+		legs, err := db.GetLiveOrdersByPair(ctx, pair)
+		require.NoError(t, err)
+		for _, o := range legs {
+			assert.Equal(t, domain.LiveStatusFilled, o.Status, "deferred, not retired")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// negRiskMerger merges NegRisk markets only once enabled, like MergeClient.
+type negRiskMerger struct {
+	shadowMerger
+	enabled bool
+}
+
+func (m *negRiskMerger) EnableNegRisk(enabled bool) { m.enabled = enabled }
+
+func (m *negRiskMerger) SupportsNegRisk() bool { return m.enabled }
+
+func TestEnableNegRisk_FollowsAllowNegRisk(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  Config
+		want bool
+	}{
+		{"off", Config{}, false},
+		{"on", Config{AllowNegRisk: true}, true},
+		{"on in shadow mode", Config{AllowNegRisk: true, ShadowMode: true}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exec := newShadowExecutor(nil, 0)
+			le, _ := newTestEngine(t, exec, tc.cfg)
+			merger := &negRiskMerger{}
+			le.SetWallets([]Wallet{{Executor: exec, Merger: merger}})
+
+			assert.Equal(t, tc.want, merger.enabled)
+			assert.Equal(t, tc.want, le.negRiskEnabled(le.wallets[0]))
+		})
+	}
+}
+
+func TestEnableNegRisk_OnReload(t *testing.T) {
+	exec := newShadowExecutor(nil, 0)
+	le, _ := newTestEngine(t, exec, Config{})
+	merger := &negRiskMerger{}
+	le.SetWallets([]Wallet{{Executor: exec, Merger: merger}})
+	assert.False(t, le.negRiskEnabled(le.wallets[0]))
+
+	le.Reload(Config{AllowNegRisk: true})
+	le.applyReload()
+	assert.True(t, merger.enabled)
+	assert.True(t, le.negRiskEnabled(le.wallets[0]))
+
+	// Off again: no new NegRisk entries, but held positions can still merge.
+	le.Reload(Config{})
+	le.applyReload()
+	assert.True(t, merger.enabled)
+	assert.False(t, le.negRiskEnabled(le.wallets[0]))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
@@ -29,12 +30,7 @@ func (le *Engine) updateSpreadHistory(opps []domain.Opportunity) {
 			FillCost:    opp.FillCostPerPair,
 			ScannedAt:   now,
 		}
-		history := le.spreadHistory[cid]
-		history = append(history, sample)
-		if len(history) > spreadStabilityWindow {
-			history = history[len(history)-spreadStabilityWindow:]
-		}
-		le.spreadHistory[cid] = history
+		le.appendSpreadSample(cid, sample)
 	}
 
 	for cid, history := range le.spreadHistory {
@@ -45,6 +41,24 @@ func (le *Engine) updateSpreadHistory(opps []domain.Opportunity) {
 			delete(le.spreadHistory, cid)
 		}
 	}
+
+	if le.bookStream != nil {
+		le.watchStreamMarkets(opps)
+	}
+}
+
+// appendSpreadSample adds a sample and trims the history window.
+// Callers must hold spreadMu.
+func (le *Engine) appendSpreadSample(conditionID string, sample spreadSample) {
+	window := spreadStabilityWindow
+	if le.bookStream != nil {
+		window = spreadStreamWindow
+	}
+	history := append(le.spreadHistory[conditionID], sample)
+	if len(history) > window {
+		history = history[len(history)-window:]
+	}
+	le.spreadHistory[conditionID] = history
 }
 
 // spreadStable returns true if the market spread has been stable across recent scans.
@@ -86,7 +100,7 @@ func (le *Engine) spreadStable(conditionID string) bool {
 		variance /= float64(len(spreads))
 		cv := math.Sqrt(variance) / math.Abs(mean)
 
-		if cv > spreadVarianceMax {
+		if cv > le.cfg.SpreadVarianceMax {
 			return false
 		}
 	}
@@ -94,158 +108,257 @@ func (le *Engine) spreadStable(conditionID string) bool {
 	return true
 }
 
-// placeOrderPair places YES+NO maker bid orders for a market.
-func (le *Engine) placeOrderPair(ctx context.Context, opp domain.Opportunity, orderSize float64) error {
-	pairID := uuid.New().String()
-	now := time.Now().UTC()
+// placeOrderPair places YES+NO maker bid orders for a market from one wallet:
+// a single pair at the optimized bids, or one pair per ladder level (see
+// Config.Ladder). It returns how many orders were placed and the USDC they
+// committed; once the first level is resting a later failure only stops the
+// ladder there.
+func (le *Engine) placeOrderPair(ctx context.Context, opp domain.Opportunity, orderSize float64, wallet Wallet) (orders int, deployed float64, err error) {
+	yesTick := le.tickSize(ctx, wallet, opp.Market.YesToken().TokenID)
+	noTick := le.tickSize(ctx, wallet, opp.Market.NoToken().TokenID)
 
-	yesBid := opp.YesBook.BestBid()
-	noBid := opp.NoBook.BestBid()
-	if yesBid == 0 {
-		yesBid = opp.YesBook.BestAsk() * 0.99
-	}
-	if noBid == 0 {
-		noBid = opp.NoBook.BestAsk() * 0.99
-	}
+	yesBid := domain.FloorToTick(engine.StartBid(opp.YesBook, orderSize), yesTick)
+	noBid := domain.FloorToTick(engine.StartBid(opp.NoBook, orderSize), noTick)
 
 	origYes, origNo := yesBid, noBid
-	feeR := opp.Market.EffectiveFeeRate(le.cfg.FeeRate)
+	feeR := le.makerFeeRate(opp)
 
-	yesBid, yesQueue := le.optimizeBid(opp.YesBook, yesBid, noBid, orderSize, feeR, true)
-	noBid, noQueue := le.optimizeBid(opp.NoBook, noBid, yesBid, orderSize, feeR, false)
-	yesBid, yesQueue = le.optimizeBid(opp.YesBook, origYes, noBid, orderSize, feeR, true)
+	yesBid, yesQueue := le.optimizeBid(opp.YesBook, yesBid, noBid, orderSize, feeR, yesTick, true)
+	noBid, noQueue := le.optimizeBid(opp.NoBook, noBid, yesBid, orderSize, feeR, noTick, false)
+	yesBid, yesQueue = le.optimizeBid(opp.YesBook, origYes, noBid, orderSize, feeR, yesTick, true)
 
 	slog.Info("live: bid optimized",
 		"market", engine.TruncateStr(opp.Market.Question, 40),
-		"yesOrig", fmt.Sprintf("%.2f", origYes),
-		"yesFinal", fmt.Sprintf("%.2f", yesBid),
+		"yesOrig", fmt.Sprintf("%g", origYes),
+		"yesFinal", fmt.Sprintf("%g", yesBid),
 		"yesQueue", fmt.Sprintf("%.0f", yesQueue),
-		"noOrig", fmt.Sprintf("%.2f", origNo),
-		"noFinal", fmt.Sprintf("%.2f", noBid),
+		"noOrig", fmt.Sprintf("%g", origNo),
+		"noFinal", fmt.Sprintf("%g", noBid),
 		"noQueue", fmt.Sprintf("%.0f", noQueue),
-		"mergeCost", fmt.Sprintf("%.4f", domain.FillCostPerEvent(yesBid, noBid, opp.Market.EffectiveFeeRate(le.cfg.FeeRate))),
+		"tick", fmt.Sprintf("%g/%g", yesTick, noTick),
+		"mergeCost", fmt.Sprintf("%.4f", domain.FillCostPerEvent(yesBid, noBid, feeR)),
 	)
 
-	feeRate := opp.Market.EffectiveFeeRate(le.cfg.FeeRate)
-	for domain.FillCostPerEvent(yesBid, noBid, feeRate) > 0 {
+	for domain.FillCostPerEvent(yesBid, noBid, feeR) > 0 {
 		if yesBid > noBid {
-			yesBid -= bidTickStep
+			yesBid = domain.RoundToTick(yesBid-yesTick, yesTick)
 		} else {
-			noBid -= bidTickStep
+			noBid = domain.RoundToTick(noBid-noTick, noTick)
 		}
-		if yesBid <= 0.01 || noBid <= 0.01 {
-			return fmt.Errorf("cannot find profitable bid pair")
+		if yesBid <= yesTick || noBid <= noTick {
+			return 0, 0, fmt.Errorf("cannot find profitable bid pair")
 		}
 	}
 
-	yesTokenID := opp.Market.YesToken().TokenID
-	noTokenID := opp.Market.NoToken().TokenID
-
-	negRisk, err := le.executor.IsNegRisk(ctx, yesTokenID)
+	negRisk, err := wallet.Executor.IsNegRisk(ctx, opp.Market.YesToken().TokenID)
 	if err != nil {
 		slog.Warn("live: neg-risk check failed, assuming false", "err", err)
 		negRisk = false
 	}
-	if negRisk {
+	if negRisk && !le.negRiskEnabled(wallet) {
 		slog.Debug("live: skipping NegRisk market (merge not supported)",
 			"market", engine.TruncateStr(opp.Market.Question, 35))
-		return fmt.Errorf("NegRisk markets cannot be merged — skipping to avoid locked capital")
+		return 0, 0, fmt.Errorf("NegRisk markets cannot be merged — skipping to avoid locked capital")
 	}
 
-	yesReq := domain.PlaceOrderRequest{
-		TokenID:     yesTokenID,
-		ConditionID: opp.Market.ConditionID,
-		Price:       yesBid,
-		Size:        orderSize,
-		Side:        "BUY",
-		NegRisk:     negRisk,
+	pairID := uuid.New().String()
+	levels := le.cfg.Ladder.Levels()
+	for _, lvl := range levels {
+		// Lower bids only make the pair cheaper, so every level stays profitable.
+		levelYes, levelNo := yesBid, noBid
+		if lvl.Offset > 0 {
+			levelYes = domain.RoundToTick(yesBid-lvl.Offset, yesTick)
+			levelNo = domain.RoundToTick(noBid-lvl.Offset, noTick)
+		}
+		if levelYes <= yesTick || levelNo <= noTick {
+			break
+		}
+		size := orderSize * lvl.Fraction
+		if len(levels) > 1 && domain.SharesAt(size, math.Max(levelYes, levelNo)) < minShares {
+			slog.Debug("live: ladder level below minimum size, skipping",
+				"market", engine.TruncateStr(opp.Market.Question, 35),
+				"level", lvl.Index,
+				"size", fmt.Sprintf("$%.2f", size),
+			)
+			continue
+		}
+
+		levelPairID := engine.LadderPairID(pairID, lvl.Index, len(levels))
+		var committed float64
+		err := checkOnTick(levelYes, yesTick, levelNo, noTick)
+		if err == nil {
+			committed, err = le.placeLevel(ctx, opp, wallet, levelPairID, levelYes, levelNo, size, negRisk)
+		}
+		if err != nil {
+			if orders == 0 {
+				return 0, 0, err
+			}
+			slog.Warn("live: ladder level failed, keeping placed levels",
+				"market", engine.TruncateStr(opp.Market.Question, 35),
+				"level", lvl.Index,
+				"err", err,
+			)
+			break
+		}
+		orders += 2
+		deployed += committed
 	}
-	yesPlaced, err := le.executor.PlaceOrder(ctx, yesReq)
-	if err != nil {
-		return fmt.Errorf("place YES: %w", err)
+	if orders == 0 {
+		return 0, 0, fmt.Errorf("no ladder level reaches the minimum order size")
 	}
+	return orders, deployed, nil
+}
 
-	noReq := domain.PlaceOrderRequest{
-		TokenID:     noTokenID,
-		ConditionID: opp.Market.ConditionID,
-		Price:       noBid,
-		Size:        orderSize,
-		Side:        "BUY",
-		NegRisk:     negRisk,
-	}
-	noPlaced, err := le.executor.PlaceOrder(ctx, noReq)
-	if err != nil {
-		slog.Warn("live: NO order failed, cancelling YES", "yes_id", yesPlaced.CLOBOrderID, "err", err)
-		if cancelErr := le.executor.CancelOrder(ctx, yesPlaced.CLOBOrderID); cancelErr != nil {
-			slog.Warn("live: could not cancel YES after NO failure", "err", cancelErr)
-		}
-		return fmt.Errorf("place NO: %w", err)
+// placeLevel places one YES+NO pair at the given bids; if NO fails the YES
+// order is cancelled so no one-sided pair is left behind. orderSize is the
+// USDC budget per side: each leg buys the whole shares it affords on the
+// CLOB's increment, and the USDC committed (shares × bid, both legs) is
+// returned.
+func (le *Engine) placeLevel(ctx context.Context, opp domain.Opportunity, wallet Wallet, pairID string, yesBid, noBid, orderSize float64, negRisk bool) (float64, error) {
+	yesShares := domain.SharesAt(orderSize, yesBid)
+	noShares := domain.SharesAt(orderSize, noBid)
+	if yesShares < minShares || noShares < minShares {
+		return 0, fmt.Errorf("$%.2f buys %.2f YES / %.2f NO shares, below the CLOB minimum of %d",
+			orderSize, yesShares, noShares, minShares)
 	}
+	yesSize := yesShares * yesBid
+	noSize := noShares * noBid
+
+	now := time.Now().UTC()
+	expiresAt := le.orderExpiry(opp, now)
+	yesTokenID := opp.Market.YesToken().TokenID
+	noTokenID := opp.Market.NoToken().TokenID
 
-	conservativeYesQueue := yesQueue * queueConservativeMult
-	conservativeNoQueue := noQueue * queueConservativeMult
+	// The profitability loop may have moved a leg below the top of book, where
+	// every higher level has to clear before it.
+	conservativeYesQueue := engine.QueueAhead(opp.YesBook, yesBid) * le.cfg.QueueConservativeMult
+	conservativeNoQueue := engine.QueueAhead(opp.NoBook, noBid) * le.cfg.QueueConservativeMult
 
 	competition := opp.YesBook.BidDepthWithinUSDC(0.05) + opp.NoBook.BidDepthWithinUSDC(0.05)
 
 	yesOrder := domain.LiveOrder{
 		ID:            uuid.New().String(),
-		CLOBOrderID:   yesPlaced.CLOBOrderID,
 		ConditionID:   opp.Market.ConditionID,
 		TokenID:       yesTokenID,
 		Side:          "YES",
 		BidPrice:      yesBid,
-		Size:          orderSize,
+		Size:          yesSize,
+		SizeShares:    yesShares,
 		PairID:        pairID,
 		PlacedAt:      now,
-		Status:        domain.LiveStatusOpen,
 		Question:      opp.Market.Question,
 		QueueAhead:    conservativeYesQueue,
 		DailyReward:   opp.YourDailyReward,
 		EndDate:       opp.Market.EndDate,
 		NegRisk:       negRisk,
 		CompetitionAt: competition,
+		WalletAddress: wallet.Address,
+		Shadow:        le.cfg.ShadowMode,
+		ExpiresAt:     expiresAt,
 	}
 
 	noOrder := domain.LiveOrder{
 		ID:            uuid.New().String(),
-		CLOBOrderID:   noPlaced.CLOBOrderID,
 		ConditionID:   opp.Market.ConditionID,
 		TokenID:       noTokenID,
 		Side:          "NO",
 		BidPrice:      noBid,
-		Size:          orderSize,
+		Size:          noSize,
+		SizeShares:    noShares,
 		PairID:        pairID,
 		PlacedAt:      now,
-		Status:        domain.LiveStatusOpen,
 		Question:      opp.Market.Question,
 		QueueAhead:    conservativeNoQueue,
 		DailyReward:   opp.YourDailyReward,
 		EndDate:       opp.Market.EndDate,
 		NegRisk:       negRisk,
 		CompetitionAt: competition,
+		WalletAddress: wallet.Address,
+		Shadow:        le.cfg.ShadowMode,
+		ExpiresAt:     expiresAt,
 	}
 
-	if err := le.store.SaveLiveOrder(ctx, yesOrder); err != nil {
-		slog.Warn("live: error saving YES order", "err", err)
+	yesReq := domain.PlaceOrderRequest{
+		TokenID:     yesTokenID,
+		ConditionID: opp.Market.ConditionID,
+		Price:       yesBid,
+		Size:        yesSize,
+		Shares:      yesShares,
+		Side:        "BUY",
+		NegRisk:     negRisk,
+		ExpiresAt:   expiresAt,
 	}
-	if err := le.store.SaveLiveOrder(ctx, noOrder); err != nil {
-		slog.Warn("live: error saving NO order", "err", err)
+	if err := le.placeLeg(ctx, wallet, &yesOrder, yesReq); err != nil {
+		return 0, fmt.Errorf("place YES: %w", err)
+	}
+
+	noReq := domain.PlaceOrderRequest{
+		TokenID:     noTokenID,
+		ConditionID: opp.Market.ConditionID,
+		Price:       noBid,
+		Size:        noSize,
+		Shares:      noShares,
+		Side:        "BUY",
+		NegRisk:     negRisk,
+		ExpiresAt:   expiresAt,
+	}
+	// placeLeg only fails once the open orders show NO is not resting (or
+	// could not be checked), so YES is not cancelled for a NO that got through.
+	if err := le.placeLeg(ctx, wallet, &noOrder, noReq); err != nil {
+		slog.Warn("live: NO order failed, cancelling YES", "yes_id", yesOrder.CLOBOrderID, "err", err)
+		if cancelErr := wallet.Executor.CancelOrder(ctx, yesOrder.CLOBOrderID); cancelErr != nil {
+			slog.Warn("live: could not cancel YES after NO failure", "err", cancelErr)
+		} else if err := le.store.UpdateLiveOrderStatus(ctx, yesOrder.ID, domain.LiveStatusCancelled); err != nil {
+			slog.Warn("live: error updating cancelled YES order", "err", err)
+		}
+		return 0, fmt.Errorf("place NO: %w", err)
 	}
 
 	slog.Info("live: placed order pair",
 		"market", engine.TruncateStr(opp.Market.Question, 35),
 		"yes_price", fmt.Sprintf("$%.2f", yesBid),
 		"no_price", fmt.Sprintf("$%.2f", noBid),
-		"size", fmt.Sprintf("$%.2f", orderSize),
-		"spread_profit", fmt.Sprintf("$%.4f", (1.0-yesBid-noBid)*orderSize),
+		"shares", fmt.Sprintf("%.2f/%.2f", yesShares, noShares),
+		"size", fmt.Sprintf("$%.2f", yesSize+noSize),
+		"spread_profit", fmt.Sprintf("$%.4f", (1.0-yesBid-noBid)*math.Min(yesShares, noShares)),
 		"neg_risk", negRisk,
+		"wallet", shortAddr(wallet.Address),
+		"pair", pairID,
 	)
 
-	return nil
+	return yesSize + noSize, nil
+}
+
+// orderExpiry is when the CLOB should drop an entry order by itself (GTD):
+// after StaleHours, or NearEndHours before resolution if that comes first.
+// Rotation normally cancels earlier; the expiry covers the bot being down.
+func (le *Engine) orderExpiry(opp domain.Opportunity, now time.Time) time.Time {
+	hours := le.cfg.StaleHours
+	if left := opp.Market.HoursToResolution() - le.cfg.NearEndHours; left > 0 && left < hours {
+		hours = left
+	}
+	return now.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+// orderGone is the status of an order that left the CLOB without fills:
+// EXPIRED once its GTD expiry has passed, CANCELLED otherwise.
+func orderGone(o domain.LiveOrder, now time.Time) domain.LiveOrderStatus {
+	if !o.ExpiresAt.IsZero() && !now.Before(o.ExpiresAt) {
+		return domain.LiveStatusExpired
+	}
+	return domain.LiveStatusCancelled
+}
+
+// makerFeeRate is the fee a resting bid on opp pays per fill: the rebated
+// rate while the market qualifies for rewards, the standard one otherwise.
+func (le *Engine) makerFeeRate(opp domain.Opportunity) float64 {
+	return opp.Market.MakerFeeRate(le.cfg.FeeRate, le.cfg.RebatedFeeRate, opp.QualifiesReward)
 }
 
 // optimizeBid walks bid price upward, maximising Expected Value.
-func (le *Engine) optimizeBid(book domain.OrderBook, currentBid, counterBid, orderSize, feeRate float64, isYesSide bool) (bestBid, bestQueue float64) {
+// Candidates step up one tick at a time, tick being the market's price
+// increment.
+func (le *Engine) optimizeBid(book domain.OrderBook, currentBid, counterBid, orderSize, feeRate, tick float64, isYesSide bool) (bestBid, bestQueue float64) {
 	bestBid = currentBid
 	bestQueue = engine.QueuePosition(book, currentBid)
 
@@ -254,8 +367,8 @@ func (le *Engine) optimizeBid(book domain.OrderBook, currentBid, counterBid, ord
 	baseFillProb := fillProbability(bestQueue, orderSize)
 	bestEV := baseFillProb * baseProfit * orderSize
 
-	for tick := bidTickStep; tick <= maxBidTickUp; tick += bidTickStep {
-		candidate := math.Round((currentBid+tick)*100) / 100
+	for n := 1; float64(n)*tick <= le.cfg.MaxBidTickUp+1e-9; n++ {
+		candidate := domain.RoundToTick(currentBid+float64(n)*tick, tick)
 		if candidate >= 1.0 {
 			break
 		}
@@ -301,11 +414,9 @@ func askDepthShares(book domain.OrderBook) float64 {
 	return total
 }
 
-func queuePositionConservative(book domain.OrderBook, bidPrice float64) float64 {
-	return engine.QueuePosition(book, bidPrice) * queueConservativeMult
-}
-
-// syncOrderState polls CLOB for current order status and detects fills.
+// syncOrderState polls CLOB for current order status and detects fills. The
+// wallet's trade history is the source of truth; for wallets whose trades
+// could not be fetched, fills are inferred from the open-order diff.
 func (le *Engine) syncOrderState(ctx context.Context, oppByCondition map[string]domain.Opportunity) (newFills int, err error) {
 	openOrders, err := le.store.GetOpenLiveOrders(ctx)
 	if err != nil {
@@ -316,31 +427,75 @@ func (le *Engine) syncOrderState(ctx context.Context, oppByCondition map[string]
 		return 0, nil
 	}
 
-	clobOrders, err := le.executor.GetOpenOrders(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("syncOrderState: get clob orders: %w", err)
-	}
+	// Each wallet only sees its own orders. A wallet whose order list cannot be
+	// fetched is skipped, otherwise its orders would look like they vanished.
+	clobByID := make(map[string]domain.LiveOrder)
+	synced := make(map[string]bool, len(le.wallets))
+	tradesByOrder := make(map[string][]domain.LiveTrade)
+	tradesSynced := make(map[string]bool, len(le.wallets))
+	since := tradesSince(openOrders)
+	var lastErr error
+	for _, w := range le.wallets {
+		clobOrders, err := w.Executor.GetOpenOrders(ctx)
+		if err != nil {
+			slog.Warn("live: could not fetch open orders", "wallet", shortAddr(w.Address), "err", err)
+			lastErr = err
+			continue
+		}
+		synced[strings.ToLower(w.Address)] = true
+		for _, co := range clobOrders {
+			clobByID[co.CLOBOrderID] = co
+		}
 
-	clobByID := make(map[string]domain.LiveOrder, len(clobOrders))
-	for _, co := range clobOrders {
-		clobByID[co.CLOBOrderID] = co
+		trades, err := w.Executor.GetTrades(ctx, since)
+		if err != nil {
+			slog.Warn("live: could not fetch trades, inferring fills from open orders",
+				"wallet", shortAddr(w.Address), "err", err)
+			continue
+		}
+		tradesSynced[strings.ToLower(w.Address)] = true
+		for _, t := range trades {
+			tradesByOrder[t.CLOBOrderID] = append(tradesByOrder[t.CLOBOrderID], t)
+		}
+	}
+	if len(synced) == 0 {
+		return 0, fmt.Errorf("syncOrderState: get clob orders: %w", lastErr)
 	}
 
 	for _, local := range openOrders {
-		if local.CLOBOrderID == "" {
+		key := le.walletKey(local.WalletAddress)
+		if local.CLOBOrderID == "" || !synced[key] {
 			continue
 		}
 
+		if tradesSynced[key] {
+			wasFilled := local.Status == domain.LiveStatusFilled
+			local = le.applyTrades(ctx, local, tradesByOrder[local.CLOBOrderID])
+			if local.Status == domain.LiveStatusFilled {
+				if !wasFilled {
+					newFills++
+				}
+				continue
+			}
+		}
+
 		clobOrder, exists := clobByID[local.CLOBOrderID]
 
 		if !exists {
+			if tradesSynced[key] {
+				if le.settleVanished(ctx, local) {
+					newFills++
+				}
+				continue
+			}
 			if local.FilledSize == 0 {
-				slog.Info("live: order disappeared with no fills — marking CANCELLED (likely auto-cancel)",
+				status := orderGone(local, time.Now())
+				slog.Info("live: order disappeared with no fills — marking "+string(status),
 					"side", local.Side,
 					"market", engine.TruncateStr(local.Question, 30),
 					"clob_id", local.CLOBOrderID,
 				)
-				_ = le.store.UpdateLiveOrderStatus(ctx, local.ID, domain.LiveStatusCancelled)
+				_ = le.store.UpdateLiveOrderStatus(ctx, local.ID, status)
 				continue
 			}
 
@@ -401,3 +556,9 @@ func (le *Engine) syncOrderState(ctx context.Context, oppByCondition map[string]
 
 	return newFills, nil
 }
+
+// negRiskEnabled reports whether NegRisk markets may be traded from wallet:
+// the config must allow them and its merger must be able to merge them.
+func (le *Engine) negRiskEnabled(wallet Wallet) bool {
+	return le.cfg.AllowNegRisk && wallet.Merger.SupportsNegRisk()
+}
@@ -0,0 +1,303 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// flattenStalePartials unwinds pairs where one side filled but the counterpart
+// never did within MaxPartialHours. The unfilled order is cancelled and the
+// filled tokens are offered with a SELL limit UnwindLossTicks below entry.
+// Every following cycle the ask walks down one tick until it fills or reaches
+// the UnwindFloorPct floor. A stranded merge remainder (LiveOrder.Stranded)
+// has no counterpart left to wait for and is unwound right away. Closed
+// unwinds record their realized P&L against the pair and feed the circuit
+// breaker.
+func (le *Engine) flattenStalePartials(ctx context.Context) (flattened int, realizedPnL float64) {
+	pairIDs, err := le.store.GetPartialPairs(ctx)
+	if err != nil {
+		slog.Warn("live: error loading partial pairs", "err", err)
+		return 0, 0
+	}
+
+	threshold := time.Duration(le.cfg.MaxPartialHours * float64(time.Hour))
+	now := time.Now().UTC()
+
+	for _, pairID := range pairIDs {
+		orders, err := le.store.GetLiveOrdersByPair(ctx, pairID)
+		if err != nil {
+			continue
+		}
+
+		var filled, other, sell *domain.LiveOrder
+		for i := range orders {
+			o := &orders[i]
+			switch {
+			case o.IsSell():
+				if o.Status != domain.LiveStatusExpired && o.Status != domain.LiveStatusFlattened {
+					sell = o
+				}
+			case o.Status == domain.LiveStatusFilled:
+				filled = o
+			default:
+				other = o
+			}
+		}
+		if filled == nil {
+			continue
+		}
+
+		if sell == nil && filled.Stranded() {
+			if err := le.startUnwind(ctx, *filled, domain.LiveOrder{}); err != nil {
+				slog.Warn("live: could not start unwind of merge remainder",
+					"market", engine.TruncateStr(filled.Question, 30),
+					"side", filled.Side,
+					"err", err,
+				)
+			}
+			continue
+		}
+		if sell == nil {
+			if other == nil || (other.Status != domain.LiveStatusOpen && other.Status != domain.LiveStatusCancelled) {
+				continue
+			}
+			filledAt := filled.PlacedAt
+			if filled.FilledAt != nil {
+				filledAt = *filled.FilledAt
+			}
+			if now.Sub(filledAt) < threshold {
+				continue
+			}
+			if err := le.startUnwind(ctx, *filled, *other); err != nil {
+				slog.Warn("live: could not start unwind",
+					"market", engine.TruncateStr(filled.Question, 30),
+					"side", filled.Side,
+					"err", err,
+				)
+			}
+			continue
+		}
+
+		closed, pnl := le.advanceUnwind(ctx, *filled, *sell)
+		if !closed {
+			continue
+		}
+
+		flattened++
+		realizedPnL += pnl
+		if pnl < 0 {
+			le.breaker.RecordLoss(pnl)
+		} else {
+			le.breaker.RecordWin(pnl)
+		}
+
+		slog.Warn("live: FLATTENED stale partial",
+			"market", engine.TruncateStr(filled.Question, 30),
+			"side", filled.Side,
+			"pnl", fmt.Sprintf("$%.4f", pnl),
+		)
+	}
+
+	return flattened, realizedPnL
+}
+
+// startUnwind cancels the counterpart and places the first SELL for the filled side.
+func (le *Engine) startUnwind(ctx context.Context, filled, other domain.LiveOrder) error {
+	if other.Status == domain.LiveStatusOpen && other.CLOBOrderID != "" {
+		if err := le.executorFor(other).CancelOrder(ctx, other.CLOBOrderID); err != nil {
+			return fmt.Errorf("cancel counterpart: %w", err)
+		}
+		_ = le.store.RetireLiveOrder(ctx, other.ID, domain.LiveStatusCancelled, domain.CloseUnwind)
+	}
+
+	// The wallet may hold fewer tokens than the books (a fill not synced
+	// yet), never more of this leg's: the rest belongs to other ladder
+	// levels and pairs on the same token.
+	shares := filled.UnmergedShares()
+	if bal, err := le.executorFor(filled).TokenBalance(ctx, filled.TokenID); err == nil && bal > 0 {
+		shares = math.Min(shares, bal)
+	}
+	if shares < minShares {
+		return fmt.Errorf("position too small to unwind (%.2f shares)", shares)
+	}
+
+	tick := le.unwindTick(ctx, filled)
+	entry := entryPrice(filled)
+	price := domain.RoundToTick(entry-float64(le.cfg.UnwindLossTicks)*tick, tick)
+	price = math.Max(price, le.unwindFloor(entry, tick))
+
+	return le.placeUnwindOrder(ctx, filled, shares, price)
+}
+
+// advanceUnwind moves an existing unwind forward: closes it when the SELL has
+// sold the position, or re-prices what is still unsold one tick lower while
+// above the floor. Shares a SELL sold before it was replaced are booked on that
+// SELL, so only the remainder goes back on the book.
+func (le *Engine) advanceUnwind(ctx context.Context, filled, sell domain.LiveOrder) (closed bool, pnl float64) {
+	shares := sell.SizeShares
+	if shares <= 0 {
+		shares = sell.Size / sell.BidPrice
+	}
+	unsold := shares - soldShares(sell)
+
+	switch sell.Status {
+	case domain.LiveStatusFilled, domain.LiveStatusCancelled:
+		// Off the book: the sync has settled what it sold from trade history,
+		// and an order that vanished part-way is left with shares to sell. The
+		// wallet balance is no guide, other orders may hold the same token.
+		if domain.FloorShares(unsold)*sell.BidPrice < minOrderUSDC {
+			return true, le.closeUnwind(ctx, filled, sell)
+		}
+		le.retireUnwindOrder(ctx, filled, sell)
+		if err := le.placeUnwindOrder(ctx, filled, unsold, sell.BidPrice); err != nil {
+			slog.Warn("live: could not re-place unwind", "err", err)
+		}
+		return false, 0
+
+	case domain.LiveStatusOpen, domain.LiveStatusPartial:
+		tick := le.unwindTick(ctx, filled)
+		floor := le.unwindFloor(entryPrice(filled), tick)
+		next := domain.RoundToTick(sell.BidPrice-tick, tick)
+		if next < floor {
+			slog.Debug("live: unwind at floor, waiting for fill",
+				"market", engine.TruncateStr(sell.Question, 30),
+				"ask", fmt.Sprintf("%.2f", sell.BidPrice))
+			return false, 0
+		}
+		if err := le.executorFor(sell).CancelOrder(ctx, sell.CLOBOrderID); err != nil {
+			slog.Warn("live: could not cancel unwind for re-pricing", "err", err)
+			return false, 0
+		}
+		le.retireUnwindOrder(ctx, filled, sell)
+		if err := le.placeUnwindOrder(ctx, filled, unsold, next); err != nil {
+			slog.Warn("live: could not re-price unwind", "err", err)
+		}
+	}
+	return false, 0
+}
+
+// retireUnwindOrder takes a SELL off the unwind before it is replaced. What it
+// sold is booked on it against the cost of those shares; closeUnwind counts
+// those shares as already sold.
+func (le *Engine) retireUnwindOrder(ctx context.Context, filled, sell domain.LiveOrder) {
+	if sell.FilledSize <= 0 {
+		_ = le.store.UpdateLiveOrderStatus(ctx, sell.ID, domain.LiveStatusExpired)
+		return
+	}
+	pnl := sell.FilledSize - filled.CostOf(soldShares(sell))
+	if err := le.store.CloseLiveOrder(ctx, sell.ID, domain.LiveStatusExpired, pnl); err != nil {
+		slog.Warn("live: error booking replaced unwind order", "err", err)
+	}
+}
+
+// closeUnwind records the realized P&L on the SELL and retires both legs. The
+// P&L returned covers the whole unwind, including what replaced SELLs booked.
+func (le *Engine) closeUnwind(ctx context.Context, filled, sell domain.LiveOrder) float64 {
+	var booked, soldBefore float64
+	if orders, err := le.store.GetLiveOrdersByPair(ctx, filled.PairID); err == nil {
+		for _, o := range orders {
+			if o.IsSell() && o.ID != sell.ID && o.TokenID == filled.TokenID &&
+				o.Status == domain.LiveStatusExpired && o.FilledSize > 0 {
+				booked += o.RealizedPnL
+				soldBefore += soldShares(o)
+			}
+		}
+	}
+
+	proceeds := sell.FilledSize
+	if proceeds <= 0 && sell.Status == domain.LiveStatusFilled {
+		proceeds = sell.Size
+	}
+	pnl := proceeds - (filled.UnmergedSize() - filled.CostOf(soldBefore))
+
+	if err := le.store.CloseLiveOrder(ctx, sell.ID, domain.LiveStatusFlattened, pnl); err != nil {
+		slog.Warn("live: error closing unwind order", "err", err)
+	}
+	_ = le.store.RetireLiveOrder(ctx, filled.ID, domain.LiveStatusFlattened, domain.CloseUnwind)
+	return booked + pnl
+}
+
+// placeUnwindOrder submits a SELL limit for shares at price and tracks it in the pair.
+func (le *Engine) placeUnwindOrder(ctx context.Context, filled domain.LiveOrder, shares, price float64) error {
+	shares = domain.FloorShares(shares)
+	size := shares * price
+	if size < minOrderUSDC {
+		return fmt.Errorf("unwind notional too small ($%.4f)", size)
+	}
+
+	placed, err := le.executorFor(filled).PlaceOrder(ctx, domain.PlaceOrderRequest{
+		TokenID:     filled.TokenID,
+		ConditionID: filled.ConditionID,
+		Price:       price,
+		Size:        size,
+		Shares:      shares,
+		Side:        "SELL",
+		NegRisk:     filled.NegRisk,
+	})
+	if err != nil {
+		return fmt.Errorf("place SELL: %w", err)
+	}
+
+	order := domain.LiveOrder{
+		ID:            uuid.New().String(),
+		CLOBOrderID:   placed.CLOBOrderID,
+		ConditionID:   filled.ConditionID,
+		TokenID:       filled.TokenID,
+		Side:          filled.Side,
+		OrderSide:     "SELL",
+		BidPrice:      price,
+		Size:          size,
+		SizeShares:    shares,
+		PairID:        filled.PairID,
+		PlacedAt:      time.Now().UTC(),
+		Status:        domain.LiveStatusOpen,
+		Question:      filled.Question,
+		EndDate:       filled.EndDate,
+		NegRisk:       filled.NegRisk,
+		WalletAddress: filled.WalletAddress,
+		Shadow:        filled.Shadow,
+	}
+	if err := le.store.SaveLiveOrder(ctx, order); err != nil {
+		slog.Warn("live: error saving unwind order", "err", err)
+	}
+
+	slog.Info("live: unwind SELL placed",
+		"market", engine.TruncateStr(filled.Question, 30),
+		"side", filled.Side,
+		"entry", fmt.Sprintf("%.2f", entryPrice(filled)),
+		"ask", fmt.Sprintf("%.2f", price),
+		"shares", fmt.Sprintf("%.2f", shares),
+	)
+	return nil
+}
+
+// unwindTick is the price step of the filled leg's market: unwind SELLs are
+// priced and walked down on it, or the CLOB refuses them.
+func (le *Engine) unwindTick(ctx context.Context, filled domain.LiveOrder) float64 {
+	return le.tickSize(ctx, le.walletFor(filled.WalletAddress), filled.TokenID)
+}
+
+// unwindFloor is the lowest ask the unwind may walk down to.
+func (le *Engine) unwindFloor(entry, tick float64) float64 {
+	return domain.RoundToTick(entry*le.cfg.UnwindFloorPct, tick)
+}
+
+// soldShares is how many shares an unwind SELL has sold so far.
+func soldShares(sell domain.LiveOrder) float64 {
+	return sell.SharesFor(sell.FilledSize)
+}
+
+func entryPrice(o domain.LiveOrder) float64 {
+	if o.FilledPrice > 0 {
+		return o.FilledPrice
+	}
+	return o.BidPrice
+}
@@ -0,0 +1,216 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// saveUnwind stores a YES leg that filled 10 shares at 0.50 and the unwind
+// SELL offering them at 0.45, which has sold soldUSDC so far.
+func saveUnwind(t *testing.T, db *storage.SQLiteStorage, status domain.LiveOrderStatus, soldUSDC float64) (filled, sell domain.LiveOrder) {
+	t.Helper()
+	ctx := context.Background()
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	filled = domain.LiveOrder{
+		ID: "yes", CLOBOrderID: "clob-yes", ConditionID: "0xcond", TokenID: "tok_yes", Side: "YES",
+		PairID: "pair", Question: "Will it rain?", BidPrice: 0.50, Size: 5, SizeShares: 10,
+		FilledSize: 5, FilledPrice: 0.50, Status: domain.LiveStatusFilled, PlacedAt: filledAt, FilledAt: &filledAt,
+	}
+	sell = domain.LiveOrder{
+		ID: "sell", CLOBOrderID: "clob-sell", ConditionID: "0xcond", TokenID: "tok_yes", Side: "YES",
+		OrderSide: "SELL", PairID: "pair", Question: "Will it rain?", BidPrice: 0.45, Size: 4.5, SizeShares: 10,
+		FilledSize: soldUSDC, Status: status, PlacedAt: filledAt,
+	}
+	require.NoError(t, db.SaveLiveOrder(ctx, filled))
+	require.NoError(t, db.SaveLiveOrder(ctx, sell))
+	return filled, sell
+}
+
+// unwindSells returns the pair's SELLs by local ID, and the one still working.
+func unwindSells(t *testing.T, db *storage.SQLiteStorage) (byID map[string]domain.LiveOrder, working domain.LiveOrder) {
+	t.Helper()
+	orders, err := db.GetLiveOrdersByPair(context.Background(), "pair")
+	require.NoError(t, err)
+	byID = make(map[string]domain.LiveOrder)
+	for _, o := range orders {
+		if !o.IsSell() {
+			continue
+		}
+		byID[o.ID] = o
+		if o.Status == domain.LiveStatusOpen {
+			working = o
+		}
+	}
+	return byID, working
+}
+
+func TestAdvanceUnwind_OpenWalksDownOneTick(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	filled, sell := saveUnwind(t, db, domain.LiveStatusOpen, 0)
+
+	closed, _ := le.advanceUnwind(ctx, filled, sell)
+	assert.False(t, closed)
+
+	sells, working := unwindSells(t, db)
+	assert.Equal(t, domain.LiveStatusExpired, sells["sell"].Status)
+	assert.Zero(t, sells["sell"].RealizedPnL)
+	assert.InDelta(t, 0.44, working.BidPrice, 1e-9)
+	assert.InDelta(t, 10, working.SizeShares, 1e-9)
+}
+
+func TestAdvanceUnwind_PartialReplacesOnlyTheRemainder(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	// 4 of the 10 shares sold at 0.45.
+	filled, sell := saveUnwind(t, db, domain.LiveStatusPartial, 1.8)
+
+	closed, _ := le.advanceUnwind(ctx, filled, sell)
+	assert.False(t, closed)
+
+	sells, working := unwindSells(t, db)
+	assert.Equal(t, domain.LiveStatusExpired, sells["sell"].Status)
+	assert.InDelta(t, 1.8-4*0.50, sells["sell"].RealizedPnL, 1e-9, "proceeds so far are booked")
+	assert.InDelta(t, 0.44, working.BidPrice, 1e-9)
+	assert.InDelta(t, 6, working.SizeShares, 1e-9, "only the unsold shares go back on the book")
+
+	// The remainder fills: the unwind closes with the P&L of both SELLs.
+	working.Status, working.FilledSize = domain.LiveStatusFilled, working.Size
+	closed, pnl := le.advanceUnwind(ctx, filled, working)
+	assert.True(t, closed)
+	assert.InDelta(t, 1.8+6*0.44-5, pnl, 1e-9)
+
+	total, err := db.GetRealizedPnL(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, pnl, total, 1e-9, "each share's P&L is booked once")
+}
+
+func TestAdvanceUnwind_CancelledResellsOwnShares(t *testing.T) {
+	ctx := context.Background()
+	// The wallet holds 100 tokens: other orders' shares must not be sold.
+	le, db := newTestEngine(t, holdingExecutor{newShadowExecutor(nil, 0), 100}, Config{OrderSize: 5})
+	filled, sell := saveUnwind(t, db, domain.LiveStatusCancelled, 1.8)
+
+	closed, _ := le.advanceUnwind(ctx, filled, sell)
+	assert.False(t, closed)
+
+	sells, working := unwindSells(t, db)
+	assert.Equal(t, domain.LiveStatusExpired, sells["sell"].Status)
+	assert.InDelta(t, 1.8-4*0.50, sells["sell"].RealizedPnL, 1e-9)
+	assert.InDelta(t, 0.45, working.BidPrice, 1e-9, "re-placed at the same ask")
+	assert.InDelta(t, 6, working.SizeShares, 1e-9)
+}
+
+func TestAdvanceUnwind_CancelledAfterSellingAllCloses(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, holdingExecutor{newShadowExecutor(nil, 0), 100}, Config{OrderSize: 5})
+	filled, sell := saveUnwind(t, db, domain.LiveStatusCancelled, 4.5)
+
+	closed, pnl := le.advanceUnwind(ctx, filled, sell)
+	assert.True(t, closed)
+	assert.InDelta(t, 4.5-5, pnl, 1e-9)
+
+	sells, working := unwindSells(t, db)
+	assert.Equal(t, domain.LiveStatusFlattened, sells["sell"].Status)
+	assert.Empty(t, working.ID, "nothing re-placed")
+}
+
+// savePartialPair stores a pair whose YES leg filled 10 shares at 0.50 age ago
+// while the NO leg still rests on the book.
+func savePartialPair(t *testing.T, db *storage.SQLiteStorage, age time.Duration) {
+	t.Helper()
+	ctx := context.Background()
+	filledAt := time.Now().UTC().Add(-age)
+	require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+		ID: "yes", CLOBOrderID: "clob-yes", ConditionID: "0xcond", TokenID: "tok_yes", Side: "YES",
+		PairID: "pair", Question: "Will it rain?", BidPrice: 0.50, Size: 5, SizeShares: 10,
+		FilledSize: 5, FilledPrice: 0.50, Status: domain.LiveStatusFilled, PlacedAt: filledAt, FilledAt: &filledAt,
+	}))
+	require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+		ID: "no", CLOBOrderID: "clob-no", ConditionID: "0xcond", TokenID: "tok_no", Side: "NO",
+		PairID: "pair", Question: "Will it rain?", BidPrice: 0.45, Size: 4.5, SizeShares: 10,
+		Status: domain.LiveStatusOpen, PlacedAt: filledAt,
+	}))
+}
+
+func TestFlattenStalePartials_UnwindsStalePartial(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5, MaxPartialHours: 1})
+	savePartialPair(t, db, 2*time.Hour)
+
+	n, pnl := le.flattenStalePartials(ctx)
+	assert.Zero(t, n, "the unwind has only started")
+	assert.Zero(t, pnl)
+
+	no, err := db.GetLiveOrderByCLOBID(ctx, "clob-no")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusCancelled, no.Status)
+
+	_, working := unwindSells(t, db)
+	require.NotEmpty(t, working.ID, "a SELL was placed")
+	assert.Equal(t, "tok_yes", working.TokenID)
+	assert.InDelta(t, 0.48, working.BidPrice, 1e-9, "unwind_loss_ticks below entry")
+	assert.InDelta(t, 10, working.SizeShares, 1e-9)
+
+	// The SELL fills: the next pass flattens the pair and books the loss.
+	now := time.Now().UTC()
+	require.NoError(t, db.UpdateLiveOrderFill(ctx, working.ID, working.Size, working.BidPrice, domain.LiveStatusFilled, &now))
+	n, pnl = le.flattenStalePartials(ctx)
+	assert.Equal(t, 1, n)
+	assert.InDelta(t, 10*0.48-5, pnl, 1e-9)
+	assert.Equal(t, 1, le.breaker.ConsecutiveLosses)
+
+	sells, _ := unwindSells(t, db)
+	assert.Equal(t, domain.LiveStatusFlattened, sells[working.ID].Status)
+}
+
+func TestFlattenStalePartials_LeavesFreshPartial(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5, MaxPartialHours: 1})
+	savePartialPair(t, db, 10*time.Minute)
+
+	n, _ := le.flattenStalePartials(ctx)
+	assert.Zero(t, n)
+
+	no, err := db.GetLiveOrderByCLOBID(ctx, "clob-no")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusOpen, no.Status, "still waiting for the other leg")
+	sells, _ := unwindSells(t, db)
+	assert.Empty(t, sells)
+}
+
+func TestStartUnwind_SellsOnlyTheLegsShares(t *testing.T) {
+	ctx := context.Background()
+	// 100 YES tokens in the wallet; 10 of them are this leg's.
+	le, db := newTestEngine(t, holdingExecutor{newShadowExecutor(nil, 0), 100}, Config{OrderSize: 5, MaxPartialHours: 1})
+	savePartialPair(t, db, 2*time.Hour)
+
+	le.flattenStalePartials(ctx)
+
+	_, working := unwindSells(t, db)
+	require.NotEmpty(t, working.ID)
+	assert.InDelta(t, 10, working.SizeShares, 1e-9, "other orders' tokens are not sold")
+}
+
+func TestUnwind_PricedOnTheMarketTick(t *testing.T) {
+	ctx := context.Background()
+	exec := &fineTickExecutor{shadowExecutor: newShadowExecutor(nil, 0)}
+	le, db := newTestEngine(t, exec, Config{OrderSize: 5, MaxPartialHours: 1})
+	savePartialPair(t, db, 2*time.Hour)
+
+	le.flattenStalePartials(ctx)
+	_, working := unwindSells(t, db)
+	require.NotEmpty(t, working.ID)
+	assert.InDelta(t, 0.498, working.BidPrice, 1e-9, "two 0.001 ticks below entry")
+
+	le.flattenStalePartials(ctx)
+	_, working = unwindSells(t, db)
+	assert.InDelta(t, 0.497, working.BidPrice, 1e-9, "walked down one market tick")
+}
@@ -2,12 +2,14 @@ package live
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"sort"
 	"strings"
 
+	"github.com/alejandrodnm/polybot/internal/application/engine"
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
@@ -15,7 +17,7 @@ import (
 type placementInput struct {
 	opps             []domain.Opportunity
 	activeConditions []string
-	balance          float64
+	wallets          []walletState
 	currentCapital   float64
 	effectiveCapital float64
 }
@@ -25,15 +27,18 @@ type placementOutput struct {
 	newOrders      int
 	capitalAfter   float64
 	warnings       []string
+	exposures      []domain.ConditionExposure
 }
 
 // runPlacementPipeline evalúa oportunidades, filtra por calidad, y coloca órdenes.
 // Cada oportunidad pasa por gates de seguridad antes de ser ejecutada.
 func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) placementOutput {
 	out := placementOutput{capitalAfter: in.currentCapital}
+	le.loadCooldowns(ctx)
 
 	sort.Slice(in.opps, func(i, j int) bool {
-		return velocityScore(in.opps[i]) > velocityScore(in.opps[j])
+		return velocityScore(in.opps[i], le.cfg.QueueConservativeMult) >
+			velocityScore(in.opps[j], le.cfg.QueueConservativeMult)
 	})
 
 	le.logFillCostDistribution(in.opps)
@@ -44,11 +49,12 @@ func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) p
 	}
 
 	var stats pipelineStats
-	balance := in.balance
 	currentCapital := in.currentCapital
+	exposure := le.conditionExposures(ctx)
+	marketsPlaced := 0 // a market's ladder places several pairs
 
 	for _, opp := range in.opps {
-		skip, reason := le.gateCheck(opp, activeSet, len(in.activeConditions)+out.newOrders/2)
+		skip, reason := le.gateCheck(opp, activeSet, len(in.activeConditions)+marketsPlaced)
 		if skip {
 			stats.record(reason)
 			if reason == skipReasonMaxMarkets || reason == skipReasonBreaker {
@@ -57,7 +63,15 @@ func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) p
 			continue
 		}
 
-		orderSize, sizeOK := le.calculateOrderSize(opp, in.effectiveCapital, currentCapital, balance)
+		wi, ok := pickWallet(in.wallets)
+		if !ok {
+			stats.record(skipReasonSize)
+			out.warnings = append(out.warnings, "no wallet has free balance for new orders")
+			break
+		}
+		wallet := &in.wallets[wi]
+
+		orderSize, sizeOK := le.calculateOrderSize(opp, in.effectiveCapital, currentCapital, wallet.headroom())
 		if !sizeOK {
 			stats.record(skipReasonSize)
 			if (in.effectiveCapital-currentCapital)/2 < minOrderUSDC {
@@ -68,6 +82,20 @@ func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) p
 			continue
 		}
 
+		orderSize, sizeOK = le.capToExposure(ctx, opp, exposure, in.effectiveCapital, orderSize)
+		if !sizeOK {
+			stats.record(skipReasonConcentration)
+			continue
+		}
+
+		if ok, err := le.checkBalanceSufficient(ctx, wallet, orderSize); err != nil {
+			slog.Warn("live: balance re-check failed, using the cycle estimate",
+				"wallet", shortAddr(wallet.Address), "err", err)
+		} else if !ok {
+			stats.record(skipReasonSize)
+			continue
+		}
+
 		slog.Info("live: PLACING ORDER",
 			"market", opp.Market.Question[:min(50, len(opp.Market.Question))],
 			"fillCost", fmt.Sprintf("%.4f", opp.FillCostPerPair),
@@ -78,7 +106,17 @@ func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) p
 			"noBookAsk", fmt.Sprintf("%.2f", opp.NoBook.BestAsk()),
 		)
 
-		if err := le.placeOrderPair(ctx, opp, orderSize); err != nil {
+		placed, deployed, err := le.placeOrderPair(ctx, opp, orderSize, wallet.Wallet)
+		switch {
+		case err == nil:
+		case errors.Is(err, domain.ErrOrderRejected):
+			slog.Warn("live: CLOB rejected order pair, skipping market this cycle", "market", opp.Market.Question, "err", err)
+			stats.record(skipReasonRejected)
+			continue
+		case errors.Is(err, domain.ErrNonRetryable):
+			slog.Warn("live: order pair not accepted, skipping", "market", opp.Market.Question, "err", err)
+			continue
+		default:
 			slog.Warn("live: error placing order pair", "market", opp.Market.Question, "err", err)
 			if strings.Contains(err.Error(), "NegRisk") {
 				stats.record(skipReasonNegRisk)
@@ -87,12 +125,23 @@ func (le *Engine) runPlacementPipeline(ctx context.Context, in placementInput) p
 		}
 
 		activeSet[opp.Market.ConditionID] = true
-		out.newOrders += 2
-		currentCapital += orderSize * 2
-		balance -= orderSize * 2
+		marketsPlaced++
+		exposure[opp.Market.ConditionID].resting += deployed
+		out.newOrders += placed
+		currentCapital += deployed
+		wallet.Balance -= deployed
+		wallet.Deployed += deployed
+		wallet.freshBalance = false
+
+		if !canFundPair(in.wallets, orderSize) {
+			slog.Info("live: wallets cannot fund another pair this cycle, stopping placement",
+				"orderSize", fmt.Sprintf("$%.2f", orderSize))
+			break
+		}
 	}
 
 	out.capitalAfter = currentCapital
+	out.exposures = topExposures(exposure, in.effectiveCapital)
 	stats.log(len(in.opps), out.newOrders)
 	return out
 }
@@ -111,6 +160,11 @@ const (
 	skipReasonSpreadStab
 	skipReasonSize
 	skipReasonNegRisk
+	skipReasonCooldown
+	skipReasonConcentration
+	skipReasonMarketList
+	skipReasonCrossed
+	skipReasonRejected
 )
 
 // gateCheck aplica todos los filtros de seguridad a una oportunidad.
@@ -121,23 +175,38 @@ func (le *Engine) gateCheck(opp domain.Opportunity, activeSet map[string]bool, c
 	if activeSet[opp.Market.ConditionID] {
 		return true, skipReasonActive
 	}
+	if le.inCooldown(opp.Market.ConditionID) {
+		return true, skipReasonCooldown
+	}
+	if le.rejected[opp.Market.ConditionID] {
+		return true, skipReasonRejected
+	}
+	if le.cfg.Markets != nil && !le.cfg.Markets.Allows(opp.Market) {
+		return true, skipReasonMarketList
+	}
 	if !le.breaker.IsOpen() {
 		return true, skipReasonBreaker
 	}
-	if opp.Market.Volume24h > 0 && opp.Market.Volume24h < minVolume24h {
+	if opp.Market.Volume24h > 0 && opp.Market.Volume24h < le.cfg.MinVolume24h {
 		return true, skipReasonVolume
 	}
 
+	// Un book cruzado es un snapshot inconsistente: el bid calculado sobre él
+	// cruzaría el spread y se llenaría como taker.
+	if opp.YesBook.IsCrossed() || opp.NoBook.IsCrossed() {
+		return true, skipReasonCrossed
+	}
+
 	yesAskDepth := askDepthShares(opp.YesBook)
 	noAskDepth := askDepthShares(opp.NoBook)
-	if yesAskDepth < minAskDepthShares || noAskDepth < minAskDepthShares {
+	if yesAskDepth < le.cfg.MinAskDepthShares || noAskDepth < le.cfg.MinAskDepthShares {
 		return true, skipReasonDepth
 	}
 
 	yesMid := opp.YesBook.Midpoint()
 	noMid := opp.NoBook.Midpoint()
-	if (yesMid > 0 && opp.YesBook.Spread()/yesMid > maxSpreadPct) ||
-		(noMid > 0 && opp.NoBook.Spread()/noMid > maxSpreadPct) {
+	if (yesMid > 0 && opp.YesBook.Spread()/yesMid > le.cfg.MaxSpreadPct) ||
+		(noMid > 0 && opp.NoBook.Spread()/noMid > le.cfg.MaxSpreadPct) {
 		return true, skipReasonSpreadPct
 	}
 
@@ -146,7 +215,7 @@ func (le *Engine) gateCheck(opp domain.Opportunity, activeSet map[string]bool, c
 	}
 
 	hoursLeft := opp.Market.HoursToResolution()
-	if hoursLeft > 0 && hoursLeft < nearEndHours {
+	if hoursLeft > 0 && hoursLeft < le.cfg.NearEndHours {
 		return true, skipReasonHours
 	}
 
@@ -157,11 +226,13 @@ func (le *Engine) gateCheck(opp domain.Opportunity, activeSet map[string]bool, c
 	return false, 0
 }
 
-// calculateOrderSize determina el tamaño de la orden respetando límites de capital.
-func (le *Engine) calculateOrderSize(opp domain.Opportunity, effectiveCapital, currentCapital, balance float64) (float64, bool) {
+// calculateOrderSize determina el tamaño de la orden respetando límites de
+// capital. headroom es el USDC que la wallet aún puede comprometer, ya sin la
+// reserva (walletState.headroom).
+func (le *Engine) calculateOrderSize(opp domain.Opportunity, effectiveCapital, currentCapital, headroom float64) (float64, bool) {
 	orderSize := le.cfg.OrderSize
 	maxAffordable := (effectiveCapital - currentCapital) / 2
-	maxFromBalance := (balance - 0.5) / 2
+	maxFromBalance := headroom / 2
 	if maxFromBalance < maxAffordable {
 		maxAffordable = maxFromBalance
 	}
@@ -169,29 +240,36 @@ func (le *Engine) calculateOrderSize(opp domain.Opportunity, effectiveCapital, c
 		orderSize = maxAffordable
 	}
 
-	approxPrice := (opp.YesBook.BestBid() + opp.NoBook.BestBid()) / 2
-	if approxPrice <= 0 {
-		approxPrice = 0.50
+	return orderSize, orderSize >= minOrderSize(opp)
+}
+
+// minOrderSize es el mínimo USDC por lado: minShares al bid más caro de los
+// dos, porque cada pata compra las shares que cubre su USDC y las dos deben
+// llegar al mínimo del CLOB.
+func minOrderSize(opp domain.Opportunity) float64 {
+	price := math.Max(opp.YesBook.BestBid(), opp.NoBook.BestBid())
+	if price <= 0 {
+		price = 0.50
 	}
-	minUSDCFor5Shares := float64(minShares) * approxPrice
+	minUSDCFor5Shares := float64(minShares) * price
 	if minUSDCFor5Shares < minOrderUSDC {
 		minUSDCFor5Shares = minOrderUSDC
 	}
-
-	return orderSize, orderSize >= minUSDCFor5Shares
+	return minUSDCFor5Shares
 }
 
 // capitalAllocation calcula cuánto capital es desplegable basándose en Kelly y límites.
 func (le *Engine) capitalAllocation(ctx context.Context, totalMergeProfit float64) (effectiveCapital, kellyF float64) {
-	kellyF = le.kellyFraction(ctx)
+	kellyF, fullKelly := le.kellyFraction(ctx)
 	bankroll := le.cfg.InitialCapital + totalMergeProfit
 	effectiveCapital = math.Min(bankroll*kellyF, le.cfg.MaxExposure)
 	if effectiveCapital <= 0 {
 		effectiveCapital = le.cfg.InitialCapital * 0.5
 	}
 
-	slog.Debug("live: capital allocation",
+	slog.Info("live: capital allocation",
 		"bankroll", fmt.Sprintf("$%.2f", bankroll),
+		"full_kelly", engine.FormatKelly(fullKelly),
 		"kelly", fmt.Sprintf("%.0f%%", kellyF*100),
 		"deployable", fmt.Sprintf("$%.2f", effectiveCapital),
 	)
@@ -220,7 +298,8 @@ func (le *Engine) logFillCostDistribution(opps []domain.Opportunity) {
 
 type pipelineStats struct {
 	maxMkts, active, breaker, fillCost, hours, spread, size, negRisk int
-	volume, depth, spreadPct                                         int
+	volume, depth, spreadPct, cooldown, concentration, marketList    int
+	crossed, rejected                                                int
 }
 
 func (s *pipelineStats) record(r skipReason) {
@@ -247,6 +326,16 @@ func (s *pipelineStats) record(r skipReason) {
 		s.size++
 	case skipReasonNegRisk:
 		s.negRisk++
+	case skipReasonCooldown:
+		s.cooldown++
+	case skipReasonConcentration:
+		s.concentration++
+	case skipReasonMarketList:
+		s.marketList++
+	case skipReasonCrossed:
+		s.crossed++
+	case skipReasonRejected:
+		s.rejected++
 	}
 }
 
@@ -263,6 +352,11 @@ func (s *pipelineStats) log(totalOpps, placed int) {
 		"skip_negrisk", s.negRisk,
 		"skip_maxmkts", s.maxMkts,
 		"skip_active", s.active,
+		"skip_cooldown", s.cooldown,
+		"skip_concentration", s.concentration,
+		"skip_market_list", s.marketList,
+		"skip_crossed", s.crossed,
+		"skip_rejected", s.rejected,
 		"skip_breaker", s.breaker,
 		"placed", placed,
 	)
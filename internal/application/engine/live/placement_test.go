@@ -0,0 +1,113 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// denyList blocks the listed condition IDs.
+type denyList map[string]bool
+
+func (d denyList) Allows(m domain.Market) bool { return !d[m.ConditionID] }
+
+func TestGateCheck_MarketListBlocksPlacement(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.Markets = denyList{"0xblocked": true}
+
+	skip, reason := le.gateCheck(exposureOpp("0xblocked"), nil, 0)
+	assert.True(t, skip)
+	assert.Equal(t, skipReasonMarketList, reason)
+
+	_, reason = le.gateCheck(exposureOpp("0xok"), nil, 0)
+	assert.NotEqual(t, skipReasonMarketList, reason)
+}
+
+func TestGateCheck_RejectsCrossedBook(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+
+	opp := exposureOpp("0xcrossed")
+	opp.NoBook = domain.OrderBook{
+		Bids: []domain.BookEntry{{Price: 0.50, Size: 100}},
+		Asks: []domain.BookEntry{{Price: 0.50, Size: 100}},
+	}
+	skip, reason := le.gateCheck(opp, nil, 0)
+	assert.True(t, skip)
+	assert.Equal(t, skipReasonCrossed, reason)
+
+	_, reason = le.gateCheck(exposureOpp("0xok"), nil, 0)
+	assert.NotEqual(t, skipReasonCrossed, reason)
+}
+
+func TestOrderExpiry_CappedBeforeResolution(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.StaleHours = 4
+	le.cfg.NearEndHours = 24
+	now := time.Now()
+
+	far := exposureOpp("0xfar")
+	far.Market.EndDate = now.Add(72 * time.Hour)
+	assert.WithinDuration(t, now.Add(4*time.Hour), le.orderExpiry(far, now), time.Second,
+		"far from resolution the stale window applies")
+
+	near := exposureOpp("0xnear")
+	near.Market.EndDate = now.Add(26 * time.Hour)
+	assert.WithinDuration(t, near.Market.EndDate.Add(-24*time.Hour), le.orderExpiry(near, now), time.Second,
+		"near resolution the order expires NearEndHours before the end date")
+}
+
+func TestMakerFeeRate_RebatedOnlyWhileQualifying(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.FeeRate, le.cfg.RebatedFeeRate = 0.02, 0.001
+
+	opp := exposureOpp("0xcond")
+	opp.QualifiesReward = true
+	assert.Equal(t, 0.001, le.makerFeeRate(opp))
+
+	opp.QualifiesReward = false
+	assert.Equal(t, 0.02, le.makerFeeRate(opp), "outside the spread rules the standard fee applies")
+	opp.Market.MakerBaseFee = 0.005
+	assert.Equal(t, 0.005, le.makerFeeRate(opp), "the market's own fee beats the config default")
+}
+
+func TestCalculateOrderSize_HeadroomAlreadyNetOfReserve(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 10})
+	wallet := walletState{Balance: 8.5}
+
+	size, ok := le.calculateOrderSize(exposureOpp("0xcond"), 100, 0, wallet.headroom())
+	assert.True(t, ok)
+	assert.InDelta(t, (8.5-balanceReserveUSDC)/2, size, 1e-9, "the reserve is kept back once")
+}
+
+func TestRunPlacementPipeline_MaxMarketsCountsMarkets(t *testing.T) {
+	le, db := newTestEngine(t, newShadowExecutor(nil, 1000), Config{
+		OrderSize:  30,
+		MaxMarkets: 2,
+		Ladder:     engine.LadderConfig{NumLevels: 3, TickSpacing: 0.01},
+	})
+	require.Len(t, le.wallets, 1)
+	opps := []domain.Opportunity{exposureOpp("0xa"), exposureOpp("0xb"), exposureOpp("0xc")}
+	le.updateSpreadHistory(opps)
+
+	out := le.runPlacementPipeline(context.Background(), placementInput{
+		opps:             opps,
+		wallets:          []walletState{{Wallet: le.wallets[0], Balance: 1000, freshBalance: true}},
+		effectiveCapital: 1000,
+	})
+
+	orders, err := db.GetOpenLiveOrders(context.Background())
+	require.NoError(t, err)
+	markets := make(map[string]bool)
+	for _, o := range orders {
+		markets[o.ConditionID] = true
+	}
+	assert.Len(t, markets, 2, "MaxMarkets caps markets, not order pairs")
+	assert.Equal(t, 2*3*2, out.newOrders, "two markets, three levels, two legs")
+	assert.Len(t, orders, out.newOrders)
+}
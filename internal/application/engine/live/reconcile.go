@@ -0,0 +1,316 @@
+package live
+
+// reconcile.go — Startup reconciliation of live_orders against the CLOB.
+//
+// After a crash or a restored DB, local OPEN/PARTIAL orders may have filled
+// or been cancelled while the bot was down, and the CLOB may hold orders we
+// have no record of. Reconcile compares both sides once and repairs the local
+// state: orders still resting get their fill progress, orders that vanished
+// are settled from the wallet's trade history, PENDING placements are matched
+// against the CLOB, and unknown CLOB orders are flagged as orphans.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// ReconcileResult summarises what Reconcile changed.
+type ReconcileResult struct {
+	Checked          int // local OPEN/PARTIAL orders compared
+	StillOpen        int // resting on the CLOB, unchanged
+	FillsUpdated     int // resting, with fill progress recorded
+	MarkedFilled     int // vanished, filled while down
+	MarkedCancelled  int // vanished, nothing filled (CANCELLED or EXPIRED)
+	PendingAdopted   int // PENDING placements found resting on the CLOB
+	Orphans          int // on the CLOB but not in live_orders
+	OrphansCancelled int
+	SkippedWallets   int // wallets whose CLOB orders could not be fetched
+}
+
+// Reconcile brings live_orders in line with the CLOB and on-chain balances.
+// Call it once at startup, before the first RunOnce. Orphan CLOB orders are
+// only cancelled when Config.CancelOrphans is set.
+func (le *Engine) Reconcile(ctx context.Context) (ReconcileResult, error) {
+	var res ReconcileResult
+
+	local, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return res, fmt.Errorf("live.Reconcile: get open orders: %w", err)
+	}
+	pending, err := le.store.GetAllLiveOrders(ctx, string(domain.LiveStatusPending))
+	if err != nil {
+		slog.Warn("live: reconcile: could not load pending orders", "err", err)
+	}
+
+	clobByID := make(map[string]domain.LiveOrder)
+	clobWallet := make(map[string]Wallet)
+	synced := make(map[string]bool, len(le.wallets))
+	trades := walletTrades{
+		byOrder: make(map[string][]domain.LiveTrade),
+		synced:  make(map[string]bool, len(le.wallets)),
+	}
+	since := tradesSince(append(append([]domain.LiveOrder(nil), local...), pending...))
+	for _, w := range le.wallets {
+		orders, err := w.Executor.GetOpenOrders(ctx)
+		if err != nil {
+			slog.Warn("live: reconcile: could not fetch CLOB orders, leaving wallet as is",
+				"wallet", shortAddr(w.Address), "err", err)
+			res.SkippedWallets++
+			continue
+		}
+		synced[strings.ToLower(w.Address)] = true
+		for _, co := range orders {
+			clobByID[co.CLOBOrderID] = co
+			clobWallet[co.CLOBOrderID] = w
+		}
+
+		history, err := w.Executor.GetTrades(ctx, since)
+		if err != nil {
+			slog.Warn("live: reconcile: could not fetch trades, inferring fills from balances",
+				"wallet", shortAddr(w.Address), "err", err)
+			continue
+		}
+		trades.synced[strings.ToLower(w.Address)] = true
+		for _, t := range history {
+			trades.byOrder[t.CLOBOrderID] = append(trades.byOrder[t.CLOBOrderID], t)
+		}
+	}
+	if len(synced) == 0 && len(le.wallets) > 0 {
+		return res, fmt.Errorf("live.Reconcile: no wallet's CLOB orders could be fetched")
+	}
+
+	known := make(map[string]bool, len(local))
+	for _, o := range local {
+		known[o.CLOBOrderID] = true
+		if o.CLOBOrderID == "" || !synced[le.walletKey(o.WalletAddress)] {
+			continue
+		}
+		res.Checked++
+		if co, ok := clobByID[o.CLOBOrderID]; ok {
+			if le.reconcileResting(ctx, o, co) {
+				res.FillsUpdated++
+			} else {
+				res.StillOpen++
+			}
+			continue
+		}
+		if le.reconcileVanished(ctx, o, trades) {
+			res.MarkedFilled++
+		} else {
+			res.MarkedCancelled++
+		}
+	}
+
+	for _, o := range pending {
+		if !synced[le.walletKey(o.WalletAddress)] {
+			continue
+		}
+		res.Checked++
+		id, filled := le.settlePending(ctx, o, clobByID, known, trades)
+		switch {
+		case id != "":
+			known[id] = true
+			res.PendingAdopted++
+		case filled:
+			res.MarkedFilled++
+		default:
+			res.MarkedCancelled++
+		}
+	}
+
+	var orphans []string
+	for id := range clobByID {
+		if known[id] {
+			continue
+		}
+		// Not open locally; it may still be a known order with a stale status.
+		if rec, err := le.store.GetLiveOrderByCLOBID(ctx, id); err == nil && rec != nil {
+			slog.Warn("live: reconcile: CLOB order is resting but stored as "+string(rec.Status),
+				"market", engine.TruncateStr(rec.Question, 30), "clob_id", id)
+			continue
+		}
+		orphans = append(orphans, id)
+	}
+	res.Orphans = len(orphans)
+	if len(orphans) > 0 {
+		res.OrphansCancelled = le.handleOrphans(ctx, orphans, clobByID, clobWallet)
+	}
+
+	slog.Info("live: reconcile summary",
+		"checked", res.Checked,
+		"still_open", res.StillOpen,
+		"fills_updated", res.FillsUpdated,
+		"marked_filled", res.MarkedFilled,
+		"marked_cancelled", res.MarkedCancelled,
+		"pending_adopted", res.PendingAdopted,
+		"orphans", res.Orphans,
+		"orphans_cancelled", res.OrphansCancelled,
+		"skipped_wallets", res.SkippedWallets,
+	)
+	return res, nil
+}
+
+// reconcileResting records fill progress of an order still on the book.
+// Returns whether anything changed.
+func (le *Engine) reconcileResting(ctx context.Context, o, co domain.LiveOrder) bool {
+	if co.FilledSize <= o.FilledSize {
+		return false
+	}
+	status := domain.LiveStatusPartial
+	var filledAt *time.Time
+	if co.FilledSize >= o.Size*0.999 {
+		status = domain.LiveStatusFilled
+		now := time.Now().UTC()
+		filledAt = &now
+	}
+	if err := le.store.UpdateLiveOrderFill(ctx, o.ID, co.FilledSize, o.BidPrice, status, filledAt); err != nil {
+		slog.Warn("live: reconcile: could not update fill", "id", o.ID, "err", err)
+		return false
+	}
+	le.saveSyntheticFill(ctx, o, co.FilledSize-o.FilledSize)
+	slog.Info("live: reconcile: fill recorded",
+		"market", engine.TruncateStr(o.Question, 30),
+		"side", o.Side,
+		"filled", fmt.Sprintf("$%.2f/$%.2f", co.FilledSize, o.Size),
+	)
+	return true
+}
+
+// walletTrades is the trade history Reconcile fetched, by CLOB order ID, and
+// the wallets it could be fetched for.
+type walletTrades struct {
+	byOrder map[string][]domain.LiveTrade
+	synced  map[string]bool
+}
+
+// reconcileVanished resolves an order that is no longer on the CLOB. Its
+// trades in the wallet's history are recorded and it is settled with what
+// actually traded, as syncOrderState does. Without a history (the fetch
+// failed, or a PENDING order never got a CLOB ID) a BUY is inferred from the
+// token balance, less the shares other local orders already account for; a
+// SELL keeps its stored fills, since a missing balance proves nothing about
+// which order sold it. Returns whether the order was marked FILLED
+// (otherwise CANCELLED or EXPIRED).
+func (le *Engine) reconcileVanished(ctx context.Context, o domain.LiveOrder, trades walletTrades) bool {
+	if o.CLOBOrderID != "" && trades.synced[le.walletKey(o.WalletAddress)] {
+		o = le.applyTrades(ctx, o, trades.byOrder[o.CLOBOrderID])
+		if o.Status == domain.LiveStatusFilled {
+			return true
+		}
+		return le.settleVanished(ctx, o)
+	}
+
+	filledSize := o.FilledSize
+	if o.TokenID != "" && !o.IsSell() {
+		bal, err := le.executorFor(o).TokenBalance(ctx, o.TokenID)
+		if err != nil {
+			slog.Warn("live: reconcile: token balance unavailable, using stored fills",
+				"market", engine.TruncateStr(o.Question, 30), "err", err)
+		} else if own := bal - le.sharesHeldByOthers(ctx, o); own > 0 {
+			filledSize = min(o.Size, max(filledSize, o.CostOf(own)))
+		}
+	}
+
+	if filledSize <= 0 {
+		status := orderGone(o, time.Now())
+		if err := le.store.UpdateLiveOrderStatus(ctx, o.ID, status); err != nil {
+			slog.Warn("live: reconcile: could not update status", "id", o.ID, "err", err)
+		}
+		slog.Info("live: reconcile: order gone with no fills — "+string(status),
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "clob_id", o.CLOBOrderID)
+		return false
+	}
+
+	now := time.Now().UTC()
+	if err := le.store.UpdateLiveOrderFill(ctx, o.ID, filledSize, o.BidPrice, domain.LiveStatusFilled, &now); err != nil {
+		slog.Warn("live: reconcile: could not mark filled", "id", o.ID, "err", err)
+		return false
+	}
+	le.saveSyntheticFill(ctx, o, filledSize-o.FilledSize)
+	slog.Info("live: reconcile: order filled while down — FILLED",
+		"market", engine.TruncateStr(o.Question, 30),
+		"side", o.Side,
+		"sell", o.IsSell(),
+		"filled", fmt.Sprintf("$%.2f/$%.2f", filledSize, o.Size),
+	)
+	return true
+}
+
+// sharesHeldByOthers is how many shares of o's token the wallet holds for
+// other local orders: the unmerged fills of its other BUY legs.
+func (le *Engine) sharesHeldByOthers(ctx context.Context, o domain.LiveOrder) float64 {
+	var held float64
+	for _, status := range []domain.LiveOrderStatus{domain.LiveStatusFilled, domain.LiveStatusPartial} {
+		orders, err := le.store.GetAllLiveOrders(ctx, string(status))
+		if err != nil {
+			slog.Warn("live: reconcile: could not load held orders", "status", status, "err", err)
+			continue
+		}
+		for _, other := range orders {
+			if other.ID != o.ID && other.TokenID == o.TokenID && !other.IsSell() &&
+				le.walletKey(other.WalletAddress) == le.walletKey(o.WalletAddress) {
+				held += other.UnmergedShares()
+			}
+		}
+	}
+	return held
+}
+
+// saveSyntheticFill records a fill we did not observe. It has no CLOB trade
+// ID and is priced at the order's limit.
+func (le *Engine) saveSyntheticFill(ctx context.Context, o domain.LiveOrder, size float64) {
+	if size <= 0 {
+		return
+	}
+	fill := domain.LiveFill{
+		OrderID:   o.ID,
+		Price:     o.BidPrice,
+		Size:      size,
+		Timestamp: time.Now().UTC(),
+	}
+	if err := le.store.SaveLiveFill(ctx, fill); err != nil {
+		slog.Warn("live: reconcile: could not save fill", "id", o.ID, "err", err)
+	}
+}
+
+// handleOrphans logs CLOB orders unknown to live_orders and cancels them when
+// configured. Returns how many were cancelled.
+func (le *Engine) handleOrphans(ctx context.Context, ids []string, clobByID map[string]domain.LiveOrder, walletOf map[string]Wallet) int {
+	byWallet := make(map[string][]string)
+	for _, id := range ids {
+		co := clobByID[id]
+		w := walletOf[id]
+		slog.Warn("live: reconcile: orphan CLOB order (not in live_orders)",
+			"wallet", shortAddr(w.Address),
+			"clob_id", id,
+			"token", engine.TruncateStr(co.TokenID, 12),
+			"price", fmt.Sprintf("%.2f", co.BidPrice),
+			"size", fmt.Sprintf("$%.2f", co.Size),
+			"cancel", le.cfg.CancelOrphans,
+		)
+		byWallet[strings.ToLower(w.Address)] = append(byWallet[strings.ToLower(w.Address)], id)
+	}
+	if !le.cfg.CancelOrphans {
+		return 0
+	}
+
+	cancelled := 0
+	for _, w := range le.wallets {
+		batch := byWallet[strings.ToLower(w.Address)]
+		if len(batch) == 0 {
+			continue
+		}
+		if err := w.Executor.CancelBatch(ctx, batch); err != nil {
+			slog.Warn("live: reconcile: could not cancel orphans", "wallet", shortAddr(w.Address), "err", err)
+			continue
+		}
+		cancelled += len(batch)
+	}
+	return cancelled
+}
@@ -0,0 +1,101 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// historyExecutor is an empty CLOB with a trade history and a token balance.
+type historyExecutor struct {
+	*shadowExecutor
+	trades []domain.LiveTrade
+	err    error
+	shares float64
+}
+
+func (he historyExecutor) GetTrades(context.Context, time.Time) ([]domain.LiveTrade, error) {
+	return he.trades, he.err
+}
+
+func (he historyExecutor) TokenBalance(context.Context, string) (float64, error) {
+	return he.shares, nil
+}
+
+func TestReconcile_VanishedOrderSettledFromTradeHistory(t *testing.T) {
+	ctx := context.Background()
+	// The wallet holds 100 YES tokens, most of them for other orders.
+	exec := historyExecutor{shadowExecutor: newShadowExecutor(nil, 0), shares: 100, trades: []domain.LiveTrade{
+		{CLOBTradeID: "t1", CLOBOrderID: "clob-a", TokenID: "tok_yes", Price: 0.40, Size: 4, Timestamp: time.Now()},
+	}}
+	le, db := newTestEngine(t, exec, Config{})
+	saveRestingOrder(t, db, "a", 0)
+	saveRestingOrder(t, db, "b", 0)
+
+	res, err := le.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Checked)
+	assert.Equal(t, 1, res.MarkedFilled)
+	assert.Equal(t, 1, res.MarkedCancelled)
+
+	a, err := db.GetLiveOrderByCLOBID(ctx, "clob-a")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusFilled, a.Status)
+	assert.InDelta(t, 4, a.FilledSize, 1e-9, "only what traded, not the wallet balance")
+
+	b, err := db.GetLiveOrderByCLOBID(ctx, "clob-b")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusCancelled, b.Status, "no trade, no fill")
+}
+
+func TestReconcile_VanishedSellWithoutTradesIsNotFilled(t *testing.T) {
+	ctx := context.Background()
+	for name, err := range map[string]error{"history": nil, "no history": errors.New("trades down")} {
+		t.Run(name, func(t *testing.T) {
+			le, db := newTestEngine(t, historyExecutor{shadowExecutor: newShadowExecutor(nil, 0), err: err}, Config{})
+			require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+				ID: "sell", CLOBOrderID: "clob-sell", ConditionID: "0xcond", TokenID: "tok_yes", Side: "YES",
+				OrderSide: "SELL", PairID: "pair-1", BidPrice: 0.45, Size: 4.5, SizeShares: 10,
+				PlacedAt: time.Now().UTC().Add(-time.Hour), Status: domain.LiveStatusOpen,
+			}))
+
+			res, rerr := le.Reconcile(ctx)
+			require.NoError(t, rerr)
+			assert.Zero(t, res.MarkedFilled, "an empty balance does not prove this SELL sold")
+
+			sell, rerr := db.GetLiveOrderByCLOBID(ctx, "clob-sell")
+			require.NoError(t, rerr)
+			assert.Equal(t, domain.LiveStatusCancelled, sell.Status)
+			assert.Zero(t, sell.FilledSize)
+		})
+	}
+}
+
+func TestReconcile_WithoutHistoryCountsOnlyUnclaimedTokens(t *testing.T) {
+	ctx := context.Background()
+	// 25 YES tokens held; 10 belong to an already filled leg.
+	exec := historyExecutor{shadowExecutor: newShadowExecutor(nil, 0), err: errors.New("trades down"), shares: 25}
+	le, db := newTestEngine(t, exec, Config{})
+	saveRestingOrder(t, db, "a", 0)
+	filledAt := time.Now().UTC().Add(-2 * time.Hour)
+	require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+		ID: "held", CLOBOrderID: "clob-held", ConditionID: "0xcond", TokenID: "tok_yes", Side: "YES",
+		PairID: "pair-0", BidPrice: 0.40, Size: 4, FilledSize: 4, Status: domain.LiveStatusFilled,
+		PlacedAt: filledAt, FilledAt: &filledAt,
+	}))
+
+	res, err := le.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.MarkedFilled)
+
+	a, err := db.GetLiveOrderByCLOBID(ctx, "clob-a")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusFilled, a.Status)
+	assert.InDelta(t, 15*0.40, a.FilledSize, 1e-9)
+}
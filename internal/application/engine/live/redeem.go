@@ -0,0 +1,161 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// redeemResolved redeems the tokens still held in markets that resolved:
+// filled legs the pair never merged or sold, merge remainders, and complete
+// pairs whose market resolved before they merged. A market is a candidate
+// once it resolved on-chain (conditionResolved) or the scan no longer lists
+// it or reports it closed; it is redeemed only when its merger implements
+// ports.Redeemer and the on-chain payout has been reported. Each leg is closed as REDEEMED with its share of
+// the realized P&L (payout - cost basis - gas), and the redemption is saved
+// for the report. Returns the markets redeemed and their total P&L.
+func (le *Engine) redeemResolved(ctx context.Context, oppByCondition map[string]domain.Opportunity) (int, float64) {
+	var held []domain.LiveOrder
+	for _, st := range []domain.LiveOrderStatus{domain.LiveStatusFilled, domain.LiveStatusPartial} {
+		orders, err := le.store.GetAllLiveOrders(ctx, string(st))
+		if err != nil {
+			slog.Warn("live: error loading held orders for redemption", "err", err)
+			return 0, 0
+		}
+		held = append(held, orders...)
+	}
+
+	// One redemption per condition and wallet: it burns the wallet's tokens.
+	type redeemKey struct{ condition, wallet string }
+	groups := make(map[redeemKey][]domain.LiveOrder)
+	var keys []redeemKey
+	for _, o := range held {
+		if o.IsSell() || o.Shadow || o.UnmergedShares() <= 0 {
+			continue
+		}
+		if opp, listed := oppByCondition[o.ConditionID]; listed && !opp.Market.Closed &&
+			!le.conditionResolved(ctx, o.ConditionID) {
+			continue
+		}
+		k := redeemKey{o.ConditionID, le.walletKey(o.WalletAddress)}
+		if _, seen := groups[k]; !seen {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], o)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].condition < keys[j].condition })
+
+	redeemed, total := 0, 0.0
+	for _, k := range keys {
+		pnl, ok := le.redeemCondition(ctx, groups[k])
+		if ok {
+			redeemed++
+			total += pnl
+		}
+	}
+	return redeemed, total
+}
+
+// redeemCondition redeems the legs of one condition held by one wallet.
+func (le *Engine) redeemCondition(ctx context.Context, legs []domain.LiveOrder) (float64, bool) {
+	first := legs[0]
+	redeemer, ok := le.mergerFor(first).(ports.Redeemer)
+	if !ok {
+		return 0, false
+	}
+
+	payout, err := redeemer.ConditionPayout(ctx, first.ConditionID)
+	if err != nil {
+		slog.Warn("live: error reading payout of resolved market", "condition", first.ConditionID, "err", err)
+		return 0, false
+	}
+	if !payout.Resolved {
+		slog.Debug("live: market closed but payout not reported yet", "condition", first.ConditionID)
+		return 0, false
+	}
+
+	var yesShares, noShares, cost, received float64
+	for _, o := range legs {
+		shares := o.UnmergedShares()
+		cost += o.UnmergedSize()
+		if o.Side == "YES" {
+			yesShares += shares
+			received += shares * payout.Yes
+		} else {
+			noShares += shares
+			received += shares * payout.No
+		}
+	}
+	if !le.holdsTokens(ctx, legs) {
+		slog.Warn("live: resolved market has no tokens left on-chain, nothing to redeem",
+			"condition", first.ConditionID, "legs", len(legs))
+		return 0, false
+	}
+
+	red, err := redeemer.RedeemPositions(ctx, first.ConditionID, yesShares, noShares, first.NegRisk)
+	if err != nil {
+		var ceiling *domain.GasCeilingError
+		if errors.As(err, &ceiling) {
+			slog.Info("live: redemption deferred, gas above ceiling", "condition", first.ConditionID,
+				"gas_gwei", fmt.Sprintf("%.1f", ceiling.PriceGwei))
+		} else {
+			slog.Warn("live: redemption failed", "condition", first.ConditionID, "err", err)
+		}
+		return 0, false
+	}
+	red.PairID = engine.PairGroup(first.PairID)
+	red.Payout = received
+	red.CostBasis = cost
+	red.RealizedPnL = received - cost - red.GasCostUSD
+	if err := le.store.SaveRedemption(ctx, red); err != nil {
+		slog.Warn("live: error saving redemption", "err", err)
+	}
+
+	gasShare := red.GasCostUSD / float64(len(legs))
+	for _, o := range legs {
+		frac := payout.Yes
+		if o.Side != "YES" {
+			frac = payout.No
+		}
+		legPnL := o.UnmergedShares()*frac - o.UnmergedSize() - gasShare
+		_ = le.store.CloseLiveOrder(ctx, o.ID, domain.LiveStatusRedeemed, legPnL)
+		_ = le.store.RetireLiveOrder(ctx, o.ID, domain.LiveStatusRedeemed, domain.CloseResolved)
+	}
+	if red.RealizedPnL > 0 {
+		le.breaker.RecordWin(red.RealizedPnL)
+	} else {
+		le.breaker.RecordLoss(red.RealizedPnL)
+	}
+
+	slog.Info("live: REDEEMED resolved market",
+		"market", engine.TruncateStr(first.Question, 30),
+		"complete_pair", yesShares > 0 && noShares > 0,
+		"yes_shares", fmt.Sprintf("%.2f", yesShares),
+		"no_shares", fmt.Sprintf("%.2f", noShares),
+		"payout", fmt.Sprintf("$%.4f", received),
+		"cost", fmt.Sprintf("$%.4f", cost),
+		"gas", fmt.Sprintf("$%.4f", red.GasCostUSD),
+		"pnl", fmt.Sprintf("$%.4f", red.RealizedPnL),
+	)
+	return red.RealizedPnL, true
+}
+
+// holdsTokens reports whether the wallet still holds any of the legs'
+// tokens on-chain. Tokens sold or redeemed outside the bot leave the store
+// showing shares the redemption would not pay for. A balance that cannot be
+// read counts as held.
+func (le *Engine) holdsTokens(ctx context.Context, legs []domain.LiveOrder) bool {
+	for _, o := range legs {
+		bal, err := le.executorFor(o).TokenBalance(ctx, o.TokenID)
+		if err != nil || bal > 0 {
+			return true
+		}
+	}
+	return false
+}
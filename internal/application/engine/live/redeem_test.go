@@ -0,0 +1,182 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// redeemMerger reports fixed payouts and records the redemptions it is asked for.
+type redeemMerger struct {
+	shadowMerger
+	payouts  map[string]domain.ConditionPayout
+	redeemed *[]string
+}
+
+func (rm redeemMerger) ConditionPayout(_ context.Context, conditionID string) (domain.ConditionPayout, error) {
+	return rm.payouts[conditionID], nil
+}
+
+func (rm redeemMerger) ConditionResolved(_ context.Context, conditionID string) (bool, error) {
+	return rm.payouts[conditionID].Resolved, nil
+}
+
+func (rm redeemMerger) RedeemPositions(_ context.Context, conditionID string, yesShares, noShares float64, _ bool) (domain.Redemption, error) {
+	*rm.redeemed = append(*rm.redeemed, conditionID)
+	return domain.Redemption{
+		ConditionID: conditionID, YesShares: yesShares, NoShares: noShares,
+		TxHash: "0xredeem", GasCostUSD: 0.02, RedeemedAt: time.Now().UTC(),
+	}, nil
+}
+
+// tokenHolder reports the same on-chain balance for every token.
+type tokenHolder struct {
+	*shadowExecutor
+	shares float64
+}
+
+func (th tokenHolder) TokenBalance(context.Context, string) (float64, error) { return th.shares, nil }
+
+func TestRedeemResolved_RedeemsHeldLegOfResolvedMarket(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, tokenHolder{newShadowExecutor(nil, 0), 10}, Config{OrderSize: 5})
+	var redeemed []string
+	merger := redeemMerger{
+		payouts: map[string]domain.ConditionPayout{
+			"0xwon":     {Resolved: true, Yes: 1},
+			"0xlisted":  {},
+			"0xpending": {},
+		},
+		redeemed: &redeemed,
+	}
+	le.merger = merger
+	le.wallets[0].Merger = merger
+
+	filledAt := time.Now().UTC().Add(-24 * time.Hour)
+	for _, cond := range []string{"0xwon", "0xlisted", "0xpending"} {
+		require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+			ID: cond + "-yes", ConditionID: cond, TokenID: "tok_yes", Side: "YES", PairID: cond,
+			BidPrice: 0.45, Size: 4.5, SizeShares: 10, FilledSize: 4.5, Status: domain.LiveStatusFilled,
+			PlacedAt: filledAt, FilledAt: &filledAt, Question: "Will it rain?",
+		}))
+	}
+
+	opps := map[string]domain.Opportunity{"0xlisted": exposureOpp("0xlisted")}
+	n, pnl := le.redeemResolved(ctx, opps)
+	assert.Equal(t, 1, n)
+	assert.InDelta(t, 10-4.5-0.02, pnl, 1e-9, "10 winning shares for $4.50, less gas")
+	assert.Equal(t, []string{"0xwon"}, redeemed, "listed and unreported markets wait")
+
+	legs, err := db.GetLiveOrdersByPair(ctx, "0xwon")
+	require.NoError(t, err)
+	require.Len(t, legs, 1)
+	assert.Equal(t, domain.LiveStatusRedeemed, legs[0].Status)
+	assert.Equal(t, domain.CloseResolved, legs[0].CloseReason)
+	assert.InDelta(t, pnl, legs[0].RealizedPnL, 1e-9)
+
+	stats, err := db.GetLiveStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Redemptions)
+	assert.InDelta(t, pnl, stats.RedemptionPnL, 1e-9)
+	assert.InDelta(t, pnl, stats.NetPnL, 1e-9, "redemptions count in net P&L")
+
+	// Redeemed legs are closed: nothing left to redeem next cycle.
+	n, _ = le.redeemResolved(ctx, opps)
+	assert.Zero(t, n)
+}
+
+func TestRedeemResolved_SkipsWhenTokensAreGone(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5}) // TokenBalance 0
+	var redeemed []string
+	merger := redeemMerger{payouts: map[string]domain.ConditionPayout{"0xc": {Resolved: true, No: 1}}, redeemed: &redeemed}
+	le.merger = merger
+	le.wallets[0].Merger = merger
+
+	filledAt := time.Now().UTC().Add(-24 * time.Hour)
+	require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+		ID: "yes", ConditionID: "0xc", TokenID: "tok_yes", Side: "YES", PairID: "p",
+		BidPrice: 0.45, Size: 4.5, FilledSize: 4.5, Status: domain.LiveStatusFilled,
+		PlacedAt: filledAt, FilledAt: &filledAt,
+	}))
+
+	n, _ := le.redeemResolved(ctx, nil)
+	assert.Zero(t, n)
+	assert.Empty(t, redeemed)
+}
+
+func TestResolvedOnChain_RedeemsCompletePairInsteadOfMerging(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, tokenHolder{newShadowExecutor(nil, 0), 10}, Config{OrderSize: 5})
+	var redeemed []string
+	merger := redeemMerger{
+		payouts:  map[string]domain.ConditionPayout{"0xearly": {Resolved: true, No: 1}},
+		redeemed: &redeemed,
+	}
+	le.merger = merger
+	le.wallets[0].Merger = merger
+
+	// The market resolved before its end date and the scan still lists it;
+	// the pair filled long enough ago to be merged otherwise.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, side := range []string{"YES", "NO"} {
+		require.NoError(t, db.SaveLiveOrder(ctx, domain.LiveOrder{
+			ID: "0xearly-" + side, CLOBOrderID: "clob-" + side, ConditionID: "0xearly", TokenID: "tok_" + side,
+			Side: side, BidPrice: 0.48, Size: 4.8, SizeShares: 10, FilledSize: 4.8, PairID: "pair-0xearly",
+			PlacedAt: filledAt, FilledAt: &filledAt, Status: domain.LiveStatusFilled,
+		}))
+	}
+	opp := exposureOpp("0xearly")
+	opp.Market.Active = true
+	opp.Market.EndDate = time.Now().Add(72 * time.Hour)
+	opps := map[string]domain.Opportunity{"0xearly": opp}
+
+	merges, _, _, err := le.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, merges, "a resolved condition is not merged")
+
+	n, pnl := le.redeemResolved(ctx, opps)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"0xearly"}, redeemed)
+	assert.InDelta(t, 10-2*4.8-0.02, pnl, 1e-9, "NO pays $1 a share; both legs are cost basis")
+
+	got, err := db.GetLiveOrdersByPair(ctx, "pair-0xearly")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	for _, o := range got {
+		assert.Equal(t, domain.LiveStatusRedeemed, o.Status)
+	}
+}
+
+func TestCancelResolvedOrders_OnChainResolutionCancelsCounterpart(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, tokenHolder{newShadowExecutor(nil, 0), 10}, Config{OrderSize: 5})
+	merger := redeemMerger{payouts: map[string]domain.ConditionPayout{"0xearly": {Resolved: true, Yes: 1}}}
+	le.merger = merger
+	le.wallets[0].Merger = merger
+
+	now := time.Now().UTC()
+	for _, o := range []domain.LiveOrder{
+		{ID: "yes", Side: "YES", TokenID: "tok_yes", Status: domain.LiveStatusFilled, FilledSize: 4.8, FilledAt: &now},
+		{ID: "no", Side: "NO", TokenID: "tok_no", Status: domain.LiveStatusOpen},
+	} {
+		o.ConditionID, o.PairID, o.CLOBOrderID = "0xearly", "pair-0xearly", "clob-"+o.ID
+		o.BidPrice, o.Size, o.PlacedAt = 0.48, 4.8, now
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+	opp := exposureOpp("0xearly")
+	opp.Market.Active = true
+	opp.Market.EndDate = time.Now().Add(72 * time.Hour)
+
+	le.cancelResolvedOrders(ctx, map[string]domain.Opportunity{"0xearly": opp})
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, open, "no merge is coming, so the counterpart is not kept open")
+	assert.True(t, le.resolved["0xearly"], "the resolution is cached")
+}
@@ -0,0 +1,56 @@
+package live
+
+// reload.go — config hot-reload.
+//
+// Reload hands the engine a new Config from another goroutine (a SIGHUP
+// handler). It is staged and swapped in at the start of the next RunOnce,
+// so a cycle never sees thresholds change halfway. Everything the engine
+// was built around stays as it is: wallets, store, the shadow and dry-run
+// modes, the bankroll, the market list, the health registry and the book and
+// order event streams. In-memory
+// state — spread history, cooldowns, tick sizes — carries over.
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Reload stages cfg to replace the engine's thresholds on the next cycle.
+// A later Reload before that cycle replaces the staged one.
+func (le *Engine) Reload(cfg Config) {
+	le.reloadMu.Lock()
+	defer le.reloadMu.Unlock()
+	le.pendingCfg = &cfg
+}
+
+// applyReload swaps in the Config staged by Reload, if any.
+func (le *Engine) applyReload() {
+	le.reloadMu.Lock()
+	pending := le.pendingCfg
+	le.pendingCfg = nil
+	le.reloadMu.Unlock()
+	if pending == nil {
+		return
+	}
+
+	cfg := pending.withDefaults()
+	cfg.InitialCapital = le.cfg.InitialCapital
+	cfg.ShadowMode = le.cfg.ShadowMode
+	cfg.DryRunPlacement = le.cfg.DryRunPlacement
+	cfg.Markets = le.cfg.Markets
+	cfg.Health = le.cfg.Health
+	cfg.BookStream = le.cfg.BookStream
+	cfg.OrderEvents = le.cfg.OrderEvents
+
+	le.breaker.MaxLosses = cfg.CircuitBreakerLosses
+	le.breaker.CooldownDuration = cfg.CircuitBreakerCooldown
+	le.breaker.MaxDrawdown = -cfg.InitialCapital * cfg.CircuitBreakerDrawdownPct
+
+	slog.Info("live: config reloaded",
+		"orderSize", fmt.Sprintf("$%.2f", cfg.OrderSize),
+		"maxMarkets", cfg.MaxMarkets,
+		"maxExposure", fmt.Sprintf("$%.2f", cfg.MaxExposure),
+	)
+	le.cfg = cfg
+	le.enableNegRisk()
+}
@@ -0,0 +1,30 @@
+package live
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyReload_KeepsWiringAndState(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{OrderSize: 5})
+	le.cfg.InitialCapital = 1000
+	le.cfg.ShadowMode = true
+	le.cooldowns["0xcond"] = time.Now().Add(time.Hour)
+
+	le.Reload(Config{OrderSize: 20, MaxMarkets: 4, CircuitBreakerLosses: 7, InitialCapital: 50})
+	le.applyReload()
+
+	assert.Equal(t, 20.0, le.cfg.OrderSize)
+	assert.Equal(t, 4, le.cfg.MaxMarkets)
+	assert.Equal(t, 7, le.breaker.MaxLosses)
+	assert.Equal(t, float64(nearEndHours), le.cfg.NearEndHours, "zero thresholds fall back to defaults")
+	assert.Equal(t, 1000.0, le.cfg.InitialCapital, "the bankroll needs a restart")
+	assert.True(t, le.cfg.ShadowMode, "shadow mode needs a restart")
+	assert.True(t, le.inCooldown("0xcond"), "in-memory state survives the reload")
+
+	le.cfg.OrderSize = 5
+	le.applyReload()
+	assert.Equal(t, 5.0, le.cfg.OrderSize, "a staged config applies once")
+}
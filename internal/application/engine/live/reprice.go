@@ -0,0 +1,214 @@
+package live
+
+// reprice.go — Trailing bid re-pricing for resting entry orders.
+//
+// A bid rests at the price placeOrderPair chose until rotation cancels it.
+// When the best bid moves up, the order ends up deep in the book: it will not
+// fill but still counts as active. repriceOrders cancels such an order and
+// places it again at a fresh optimizeBid price under the same pair, as long
+// as the pair stays profitable against its counterpart's price.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// repriceMinAge keeps a freshly placed (or repriced) order resting for a
+// while before it is moved again, so a jittery book does not churn orders.
+const repriceMinAge = 10 * time.Minute
+
+// repriceOrders re-prices OPEN entry orders without fills that fell behind
+// the book. Returns how many orders were moved.
+func (le *Engine) repriceOrders(ctx context.Context, oppByCondition map[string]domain.Opportunity) int {
+	if !le.cfg.Reprice {
+		return 0
+	}
+	openOrders, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		slog.Warn("live: could not load orders for repricing", "err", err)
+		return 0
+	}
+
+	now := time.Now().UTC()
+	repriced := 0
+	for _, o := range openOrders {
+		if o.Status != domain.LiveStatusOpen || o.FilledSize > 0 || o.IsSell() || o.PairID == "" {
+			continue
+		}
+		if now.Sub(o.PlacedAt) < repriceMinAge {
+			continue
+		}
+		opp, ok := oppByCondition[o.ConditionID]
+		if !ok {
+			continue
+		}
+		isYes := o.Side == "YES"
+		book := opp.NoBook
+		if isYes {
+			book = opp.YesBook
+		}
+
+		reason := le.repriceReason(o, book)
+		if reason == "" {
+			continue
+		}
+		counter, ok := le.counterpartPrice(ctx, o)
+		if !ok {
+			continue
+		}
+		newBid, queue := le.trailingBid(book, counter, o.Size, le.makerFeeRate(opp), isYes)
+		if newBid <= o.BidPrice+bidTickStep/2 {
+			slog.Debug("live: reprice skipped — no profitable higher bid",
+				"market", engine.TruncateStr(o.Question, 30), "side", o.Side,
+				"bid", fmt.Sprintf("%.2f", o.BidPrice), "counter", fmt.Sprintf("%.2f", counter))
+			continue
+		}
+		if le.replaceOrder(ctx, o, newBid, queue, reason, now) {
+			repriced++
+		}
+	}
+	return repriced
+}
+
+// repriceReason says why o should move: its price is RepriceTicks or more
+// below the best bid, or the USDC now bidding above it has grown the queue
+// ahead beyond RepriceQueueMult of what it was at placement. "" = keep it.
+func (le *Engine) repriceReason(o domain.LiveOrder, book domain.OrderBook) string {
+	best := book.BestBid()
+	if best <= 0 {
+		return ""
+	}
+	if ticks := (best - o.BidPrice) / bidTickStep; ticks >= float64(le.cfg.RepriceTicks)-1e-9 {
+		return fmt.Sprintf("%.0f ticks below best bid", math.Round(ticks))
+	}
+
+	// QueueAhead already counts the levels that were above o at placement, so
+	// compare against the whole queue ahead now rather than adding them twice.
+	if best <= o.BidPrice+bidTickStep/2 {
+		return ""
+	}
+	ahead := engine.QueuePositionFull(book, o.BidPrice) * le.cfg.QueueConservativeMult
+	base := math.Max(o.QueueAhead, o.Size)
+	if ahead > base*le.cfg.RepriceQueueMult {
+		return fmt.Sprintf("queue ahead grew to $%.0f", ahead)
+	}
+	return ""
+}
+
+// counterpartPrice is the price the other leg of o's pair bought (or bids)
+// at, the reference for keeping the pair profitable. false when the pair has
+// no live counterpart.
+func (le *Engine) counterpartPrice(ctx context.Context, o domain.LiveOrder) (float64, bool) {
+	pair, err := le.store.GetLiveOrdersByPair(ctx, o.PairID)
+	if err != nil {
+		return 0, false
+	}
+	for _, po := range pair {
+		if po.Side == o.Side || po.IsSell() {
+			continue
+		}
+		if po.FilledSize > 0 && po.FilledPrice > 0 {
+			return po.FilledPrice, true
+		}
+		switch po.Status {
+		case domain.LiveStatusOpen, domain.LiveStatusPartial, domain.LiveStatusFilled:
+			return po.BidPrice, true
+		}
+	}
+	return 0, false
+}
+
+// trailingBid runs optimizeBid from the current best bid and walks the
+// result down until a fill no longer costs money against counterBid.
+// Returns 0 when no profitable price is left.
+func (le *Engine) trailingBid(book domain.OrderBook, counterBid, orderSize, feeRate float64, isYes bool) (float64, float64) {
+	start := book.BestBid()
+	if start <= 0 {
+		return 0, 0
+	}
+	bid, _ := le.optimizeBid(book, start, counterBid, orderSize, feeRate, bidTickStep, isYes)
+	for fillCostForSide(bid, counterBid, feeRate, isYes) > 0 {
+		bid = math.Round((bid-bidTickStep)*100) / 100
+		if bid <= 0.01 {
+			return 0, 0
+		}
+	}
+	return bid, engine.QueueAhead(book, bid)
+}
+
+// replaceOrder cancels o and places the same leg at newBid under o's pair.
+// Nothing is placed when the cancel fails. If the new leg cannot be placed,
+// a counterpart that never filled is cancelled too rather than left resting
+// alone.
+func (le *Engine) replaceOrder(ctx context.Context, o domain.LiveOrder, newBid, queue float64, reason string, now time.Time) bool {
+	if err := le.cancelOrders(ctx, []domain.LiveOrder{o}); err != nil {
+		slog.Warn("live: reprice cancel failed, keeping order",
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "err", err)
+		return false
+	}
+	if err := le.store.UpdateLiveOrderStatus(ctx, o.ID, domain.LiveStatusCancelled); err != nil {
+		slog.Warn("live: error updating repriced order", "err", err)
+	}
+
+	n := o
+	n.ID = uuid.New().String()
+	n.CLOBOrderID = ""
+	n.PlacementKey = ""
+	n.BidPrice = newBid
+	n.SizeShares = domain.SharesAt(o.Size, newBid)
+	n.Size = n.SizeShares * newBid
+	n.QueueAhead = queue * le.cfg.QueueConservativeMult
+	n.PlacedAt = now
+	req := domain.PlaceOrderRequest{
+		TokenID:     o.TokenID,
+		ConditionID: o.ConditionID,
+		Price:       newBid,
+		Size:        n.Size,
+		Shares:      n.SizeShares,
+		Side:        "BUY",
+		NegRisk:     o.NegRisk,
+		ExpiresAt:   o.ExpiresAt,
+	}
+	if err := le.placeLeg(ctx, le.walletFor(o.WalletAddress), &n, req); err != nil {
+		slog.Warn("live: re-placement failed, dropping unfilled counterpart",
+			"market", engine.TruncateStr(o.Question, 30), "side", o.Side, "err", err)
+		le.cancelUnfilledCounterpart(ctx, o)
+		return false
+	}
+
+	slog.Info("live: REPRICED order",
+		"market", engine.TruncateStr(o.Question, 30),
+		"side", o.Side,
+		"from", fmt.Sprintf("%.2f", o.BidPrice),
+		"to", fmt.Sprintf("%.2f", newBid),
+		"reason", reason,
+	)
+	return true
+}
+
+// cancelUnfilledCounterpart cancels the other leg of o's pair if it is still
+// OPEN without fills.
+func (le *Engine) cancelUnfilledCounterpart(ctx context.Context, o domain.LiveOrder) {
+	pair, err := le.store.GetLiveOrdersByPair(ctx, o.PairID)
+	if err != nil {
+		return
+	}
+	for _, po := range pair {
+		if po.Side == o.Side || po.IsSell() || po.Status != domain.LiveStatusOpen || po.FilledSize > 0 {
+			continue
+		}
+		if err := le.cancelOrders(ctx, []domain.LiveOrder{po}); err != nil {
+			slog.Warn("live: could not cancel counterpart", "side", po.Side, "err", err)
+			continue
+		}
+		_ = le.store.UpdateLiveOrderStatus(ctx, po.ID, domain.LiveStatusCancelled)
+	}
+}
@@ -0,0 +1,120 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// repriceConfig enables repricing with the default thresholds.
+var repriceConfig = Config{OrderSize: 5, Reprice: true}
+
+// restingPair places a YES/NO pair on the shadow book an hour ago.
+func restingPair(t *testing.T, le *Engine, db *storage.SQLiteStorage, yesBid, noBid float64) {
+	t.Helper()
+	ctx := context.Background()
+	placed := time.Now().UTC().Add(-time.Hour)
+	for _, leg := range []struct {
+		side string
+		bid  float64
+	}{{"YES", yesBid}, {"NO", noBid}} {
+		o := domain.LiveOrder{
+			ID: "local-" + leg.side, ConditionID: "0xcond", TokenID: "tok_" + leg.side, Side: leg.side,
+			BidPrice: leg.bid, Size: 5, PairID: "pair-1", PlacedAt: placed, Question: "Will it rain?",
+		}
+		req := domain.PlaceOrderRequest{TokenID: o.TokenID, ConditionID: o.ConditionID, Price: o.BidPrice, Size: o.Size, Side: "BUY"}
+		require.NoError(t, le.placeLeg(ctx, le.wallets[0], &o, req))
+	}
+}
+
+func repriceOpp(yesBest, noBest float64) map[string]domain.Opportunity {
+	book := func(bid float64) domain.OrderBook {
+		return domain.OrderBook{
+			Bids: []domain.BookEntry{{Price: bid, Size: 200}},
+			Asks: []domain.BookEntry{{Price: bid + 0.02, Size: 200}},
+		}
+	}
+	return map[string]domain.Opportunity{"0xcond": {
+		Market:  domain.Market{ConditionID: "0xcond", Question: "Will it rain?"},
+		YesBook: book(yesBest),
+		NoBook:  book(noBest),
+	}}
+}
+
+func TestRepriceOrders_MovesLegThatFellBehind(t *testing.T) {
+	ctx := context.Background()
+	book := newShadowExecutor(nil, 0)
+	le, db := newTestEngine(t, book, repriceConfig)
+	restingPair(t, le, db, 0.40, 0.50)
+
+	// YES best bid moved from 0.40 to 0.45; NO is still at the top.
+	assert.Equal(t, 1, le.repriceOrders(ctx, repriceOpp(0.45, 0.50)))
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	var yes domain.LiveOrder
+	for _, o := range open {
+		assert.Equal(t, "pair-1", o.PairID, "the pair is preserved")
+		if o.Side == "YES" {
+			yes = o
+		}
+	}
+	assert.NotEqual(t, "local-YES", yes.ID)
+	assert.GreaterOrEqual(t, yes.BidPrice, 0.45)
+	assert.LessOrEqual(t, domain.FillCostPerEvent(yes.BidPrice, 0.50, 0), 1e-9, "never priced into a losing pair")
+
+	resting, err := book.GetOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resting, 2, "the old YES was cancelled on the CLOB")
+}
+
+func TestRepriceOrders_DoesNotChaseIntoLoss(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), repriceConfig)
+	// YES 0.40 + NO 0.60 already sits at break-even: no higher YES is profitable.
+	restingPair(t, le, db, 0.40, 0.60)
+
+	assert.Zero(t, le.repriceOrders(ctx, repriceOpp(0.45, 0.60)))
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "pair-1")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	for _, o := range orders {
+		assert.Equal(t, domain.LiveStatusOpen, o.Status)
+	}
+}
+
+func TestRepriceOrders_LeavesRecentOrders(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), repriceConfig)
+	restingPair(t, le, db, 0.40, 0.50)
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	for _, o := range open {
+		o.PlacedAt = time.Now().UTC()
+		require.NoError(t, db.SaveLiveOrder(ctx, o))
+	}
+
+	assert.Zero(t, le.repriceOrders(ctx, repriceOpp(0.45, 0.50)))
+}
+
+func TestRepriceReason_QueueBehindTopCountedOnce(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), repriceConfig)
+	le.cfg.RepriceTicks = 5
+	le.cfg.RepriceQueueMult = 2
+	book := domain.OrderBook{Bids: []domain.BookEntry{{Price: 0.42, Size: 100}, {Price: 0.41, Size: 100}}}
+
+	o := domain.LiveOrder{BidPrice: 0.41, Size: 5, QueueAhead: engine.QueueAhead(book, 0.41) * le.cfg.QueueConservativeMult}
+	assert.Empty(t, le.repriceReason(o, book), "the depth above at placement is already in QueueAhead")
+
+	book.Bids[0].Size = 300
+	assert.Contains(t, le.repriceReason(o, book), "queue ahead grew")
+}
@@ -0,0 +1,45 @@
+package live
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// resolutionRecheck is how long an unresolved on-chain reading is trusted
+// before the condition is read again, so the cancel, merge and redeem steps
+// of one cycle share a single RPC call per condition.
+const resolutionRecheck = time.Minute
+
+// conditionResolved reports whether a condition has resolved on-chain (CTF
+// payoutDenominator set). This is ground truth: markets resolve early or get
+// extended, so the end date and the scan only hint at it. A resolution is
+// permanent and cached for good; an unresolved reading is cached for
+// resolutionRecheck. False when the merger cannot read the chain or the read
+// fails.
+func (le *Engine) conditionResolved(ctx context.Context, conditionID string) bool {
+	if le.resolved[conditionID] {
+		return true
+	}
+	if checked, ok := le.unresolvedAt[conditionID]; ok && time.Since(checked) < resolutionRecheck {
+		return false
+	}
+	reader, ok := le.merger.(ports.ResolutionReader)
+	if !ok {
+		return false
+	}
+	resolved, err := reader.ConditionResolved(ctx, conditionID)
+	if err != nil {
+		slog.Debug("live: on-chain resolution check failed", "condition", conditionID, "err", err)
+		return false
+	}
+	if resolved {
+		le.resolved[conditionID] = true
+		delete(le.unresolvedAt, conditionID)
+		return true
+	}
+	le.unresolvedAt[conditionID] = time.Now()
+	return false
+}
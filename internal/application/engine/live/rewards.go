@@ -0,0 +1,76 @@
+package live
+
+// rewards.go — Realized reward sync.
+//
+// The cycle's TotalReward is an estimate from block accrual. Polymarket pays
+// liquidity rewards once a day and reports them per market, so once per UTC
+// day the engine reads the last rewardsSyncDays days from every wallet with a
+// RewardsSource and stores them; the live stats count those in Net P&L.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// rewardsSyncDays is how many past UTC days each sync re-reads. A day is paid
+// the following day, so yesterday is read again until its payout is final.
+const rewardsSyncDays = 2
+
+// syncRewards records the rewards paid to the wallets, at most once per UTC
+// day. Shadow mode has no real payouts and never syncs. A failed sync is
+// retried on the next cycle.
+func (le *Engine) syncRewards(ctx context.Context) {
+	if le.cfg.ShadowMode {
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if le.rewardsSyncedOn.Equal(today) {
+		return
+	}
+
+	hasSource := false
+	for _, w := range le.wallets {
+		hasSource = hasSource || w.Rewards != nil
+	}
+	if !hasSource {
+		return
+	}
+
+	for d := 1; d <= rewardsSyncDays; d++ {
+		day := today.AddDate(0, 0, -d)
+		byCondition := make(map[string]float64)
+		for _, w := range le.wallets {
+			if w.Rewards == nil {
+				continue
+			}
+			rewards, err := w.Rewards.EarningsForDay(ctx, day)
+			if err != nil {
+				slog.Warn("live: error fetching rewards",
+					"wallet", shortAddr(w.Address), "date", day.Format("2006-01-02"), "err", err)
+				return
+			}
+			for _, r := range rewards {
+				byCondition[r.ConditionID] += r.Earnings
+			}
+		}
+		var total float64
+		for conditionID, earnings := range byCondition {
+			r := domain.LiveReward{Date: day, ConditionID: conditionID, Earnings: earnings}
+			if err := le.store.SaveLiveReward(ctx, r); err != nil {
+				slog.Warn("live: error saving reward", "err", err)
+				return
+			}
+			total += earnings
+		}
+		slog.Info("live: rewards synced",
+			"date", day.Format("2006-01-02"),
+			"markets", len(byCondition),
+			"earned", fmt.Sprintf("$%.4f", total),
+		)
+	}
+	le.rewardsSyncedOn = today
+}
@@ -11,24 +11,42 @@ import (
 )
 
 // cancelResolvedOrders cancels orders for markets that have resolved or are near end.
+// The on-chain resolution (conditionResolved) is checked first: a market can
+// resolve before its end date while the scan still lists it.
 // CRITICAL: if one side of a pair is already FILLED, the counterpart is kept open
-// to allow the merge to complete.
+// to allow the merge to complete — unless the market resolved on-chain, where
+// no merge is possible and the held legs are redeemed instead.
 func (le *Engine) cancelResolvedOrders(ctx context.Context, oppByCondition map[string]domain.Opportunity) {
 	conditions, err := le.store.GetActiveLiveConditions(ctx)
 	if err != nil {
 		return
 	}
 
+	var toCancel []domain.LiveOrder
+	reasons := make(map[string]domain.CloseReason) // condition → why it was cancelled
 	for _, condID := range conditions {
 		opp, exists := oppByCondition[condID]
+		resolvedOnChain := le.conditionResolved(ctx, condID)
 
 		needsCancel := false
-		if !exists {
+		if resolvedOnChain {
 			needsCancel = true
-		} else if opp.Market.HoursToResolution() > 0 && opp.Market.HoursToResolution() < nearEndHours {
+			reasons[condID] = domain.CloseResolved
+			if exists && opp.Market.Active && !opp.Market.Closed {
+				slog.Info("live: market resolved on-chain while still listed",
+					"market", engine.TruncateStr(opp.Market.Question, 30),
+					"hours_to_end", fmt.Sprintf("%.1f", opp.Market.HoursToResolution()),
+				)
+			}
+		} else if !exists {
+			needsCancel = true
+			reasons[condID] = domain.CloseResolved
+		} else if opp.Market.HoursToResolution() > 0 && opp.Market.HoursToResolution() < le.cfg.NearEndHours {
 			needsCancel = true
+			reasons[condID] = domain.CloseNearEnd
 		} else if !opp.Market.Active || opp.Market.Closed {
 			needsCancel = true
+			reasons[condID] = domain.CloseResolved
 		}
 
 		if !needsCancel {
@@ -67,7 +85,7 @@ func (le *Engine) cancelResolvedOrders(ctx context.Context, oppByCondition map[s
 					if po.TokenID == "" {
 						continue
 					}
-					bal, err := le.executor.TokenBalance(ctx, po.TokenID)
+					bal, err := le.executorFor(po).TokenBalance(ctx, po.TokenID)
 					if err != nil {
 						slog.Debug("live: on-chain balance check failed", "token", po.TokenID[:16], "err", err)
 						continue
@@ -84,7 +102,12 @@ func (le *Engine) cancelResolvedOrders(ctx context.Context, oppByCondition map[s
 				}
 			}
 
-			if hasFill {
+			if hasFill && resolvedOnChain {
+				slog.Warn("live: market resolved on-chain with fills — cancelling counterpart, held legs will be redeemed",
+					"condition", engine.TruncateStr(condID, 16),
+					"pair", engine.TruncateStr(pairID, 8),
+				)
+			} else if hasFill {
 				slog.Warn("live: market near resolution but pair has fills — keeping counterpart open",
 					"condition", condID[:16],
 					"pair", pairID[:8],
@@ -92,12 +115,22 @@ func (le *Engine) cancelResolvedOrders(ctx context.Context, oppByCondition map[s
 				continue
 			}
 
-			for _, o := range orders {
-				if err := le.executor.CancelOrder(ctx, o.CLOBOrderID); err != nil {
-					slog.Warn("live: error cancelling order", "clob_id", o.CLOBOrderID, "err", err)
-				}
-				_ = le.store.UpdateLiveOrderStatus(ctx, o.ID, domain.LiveStatusCancelled)
-			}
+			toCancel = append(toCancel, orders...)
+		}
+	}
+
+	if len(toCancel) == 0 {
+		return
+	}
+	if err := le.cancelOrders(ctx, toCancel); err != nil {
+		slog.Warn("live: error cancelling orders", "orders", len(toCancel), "err", err)
+	}
+	cooled := make(map[string]bool)
+	for _, o := range toCancel {
+		_ = le.store.RetireLiveOrder(ctx, o.ID, domain.LiveStatusCancelled, reasons[o.ConditionID])
+		if !cooled[o.ConditionID] && !le.cfg.DryRunPlacement {
+			cooled[o.ConditionID] = true
+			le.cooldownAfterCancel(ctx, o.ConditionID, o.Question, reasons[o.ConditionID])
 		}
 	}
 }
@@ -116,7 +149,9 @@ func (le *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 		}
 	}
 
-	expired := 0
+	var toCancel []domain.LiveOrder
+	var rotatedConditions []string
+	reasons := make(map[string]domain.CloseReason) // condition → why it was rotated
 	for _, orders := range byPair {
 		if len(orders) < 2 {
 			continue
@@ -147,7 +182,7 @@ func (le *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 				if po.TokenID == "" {
 					continue
 				}
-				bal, err := le.executor.TokenBalance(ctx, po.TokenID)
+				bal, err := le.executorFor(po).TokenBalance(ctx, po.TokenID)
 				if err == nil && bal > 0 {
 					hasFill = true
 					slog.Warn("live: on-chain tokens detected during rotation check",
@@ -169,15 +204,22 @@ func (le *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 		age := time.Since(oldest).Hours()
 		conditionID := orders[0].ConditionID
 		rotateReason := ""
+		var closeReason domain.CloseReason
 
-		if age >= staleHours {
+		dryRun := le.cfg.DryRunPlacement
+		if dryRun {
+			rotateReason = "dry-run (never placed)"
+			closeReason = domain.CloseDryRun
+		} else if age >= le.cfg.StaleHours {
 			rotateReason = fmt.Sprintf("stale %.1fh (no fills)", age)
+			closeReason = domain.CloseStale
 		}
 
 		if rotateReason == "" {
 			if opp, exists := oppByCondition[conditionID]; exists {
 				if opp.FillCostPerPair > 0 {
 					rotateReason = fmt.Sprintf("spread unprofitable (fillCost $%.4f)", opp.FillCostPerPair)
+					closeReason = domain.CloseSpread
 				}
 			}
 		}
@@ -186,8 +228,9 @@ func (le *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 			if opp, exists := oppByCondition[conditionID]; exists {
 				currentComp := opp.YesBook.BidDepthWithinUSDC(0.05) + opp.NoBook.BidDepthWithinUSDC(0.05)
 				originalComp := orders[0].CompetitionAt
-				if originalComp > 0 && currentComp > originalComp*competitionMult {
+				if originalComp > 0 && currentComp > originalComp*le.cfg.CompetitionMult {
 					rotateReason = fmt.Sprintf("competition spiked %.1fx", currentComp/originalComp)
+					closeReason = domain.CloseCompetition
 				}
 			}
 		}
@@ -196,20 +239,41 @@ func (le *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 			continue
 		}
 
-		for _, o := range orders {
-			if err := le.executor.CancelOrder(ctx, o.CLOBOrderID); err != nil {
-				slog.Warn("live: error cancelling stale order", "clob_id", o.CLOBOrderID, "err", err)
-			}
+		toCancel = append(toCancel, orders...)
+		rotatedConditions = append(rotatedConditions, conditionID)
+		reasons[conditionID] = closeReason
+		// A dry-run pair is re-evaluated on the next scan, not cooled down
+		if !dryRun {
+			le.startCooldown(ctx, conditionID, orders[0].Question, rotateReason)
 		}
-		_ = le.store.CancelLiveOrdersByCondition(ctx, conditionID)
 
 		slog.Info("live: ROTATED pair",
 			"reason", rotateReason,
 			"market", engine.TruncateStr(orders[0].Question, 30),
 			"age", fmt.Sprintf("%.1fh", age),
 		)
-		expired++
 	}
 
-	return expired
+	if len(toCancel) == 0 {
+		return 0
+	}
+	if err := le.cancelOrders(ctx, toCancel); err != nil {
+		slog.Warn("live: error cancelling stale orders", "orders", len(toCancel), "err", err)
+	}
+	for _, conditionID := range rotatedConditions {
+		_ = le.store.CancelLiveOrdersByCondition(ctx, conditionID, reasons[conditionID])
+	}
+
+	return len(rotatedConditions)
+}
+
+// clobOrderIDs returns the non-empty CLOB order IDs of the given orders.
+func clobOrderIDs(orders []domain.LiveOrder) []string {
+	ids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.CLOBOrderID != "" {
+			ids = append(ids, o.CLOBOrderID)
+		}
+	}
+	return ids
 }
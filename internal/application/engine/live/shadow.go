@@ -0,0 +1,267 @@
+package live
+
+// shadow.go — Dry-run ("shadow") execution for the live engine.
+//
+// In shadow mode the full RunOnce pipeline runs against real books — gates,
+// bid optimization, Kelly sizing, circuit breaker — but orders and merges go
+// to no-op executors that only log what would have been sent. Shadow orders
+// rest until the engine rotates or cancels them: fills are not simulated, so
+// fill-dependent P&L stays with paper mode.
+//
+// Placement dry-run (Config.DryRunPlacement, see dryrun.go) is shadow mode
+// with "DRY-" order IDs, sized against the real wallet balance.
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// shadowExecutor records orders in memory instead of sending them to the CLOB.
+type shadowExecutor struct {
+	inner   ports.OrderExecutor // read-only lookups (IsNegRisk); may be nil
+	balance float64
+	dryRun  bool // DRY- IDs and inner's real balance instead of balance
+
+	mu   sync.Mutex
+	open map[string]domain.LiveOrder // shadow CLOB ID → order
+}
+
+func newShadowExecutor(inner ports.OrderExecutor, balance float64) *shadowExecutor {
+	return &shadowExecutor{
+		inner:   inner,
+		balance: balance,
+		open:    make(map[string]domain.LiveOrder),
+	}
+}
+
+func (se *shadowExecutor) PlaceOrder(_ context.Context, req domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
+	id := "shadow-" + uuid.New().String()
+	if se.dryRun {
+		id = dryRunPrefix + uuid.New().String()
+	}
+	slog.Info(se.logPrefix()+": would place order",
+		"clob_id", id,
+		"side", req.Side,
+		"token", engine.TruncateStr(req.TokenID, 12),
+		"price", fmt.Sprintf("%.2f", req.Price),
+		"size", fmt.Sprintf("$%.2f", req.Size),
+		"type", req.OrderType,
+		"expires", req.ExpiresAt.Format("15:04"),
+	)
+
+	se.mu.Lock()
+	se.open[id] = domain.LiveOrder{
+		CLOBOrderID: id,
+		TokenID:     req.TokenID,
+		ConditionID: req.ConditionID,
+		BidPrice:    req.Price,
+		Size:        req.Size,
+		OrderSide:   req.Side,
+		Status:      domain.LiveStatusOpen,
+		PlacedAt:    time.Now().UTC(),
+		Shadow:      true,
+	}
+	se.mu.Unlock()
+
+	return domain.PlacedOrder{CLOBOrderID: id, Status: "live", MadeAmount: req.Size}, nil
+}
+
+func (se *shadowExecutor) CancelOrder(_ context.Context, clobOrderID string) error {
+	se.mu.Lock()
+	delete(se.open, clobOrderID)
+	se.mu.Unlock()
+	slog.Info(se.logPrefix()+": would cancel order", "clob_id", clobOrderID)
+	return nil
+}
+
+func (se *shadowExecutor) CancelBatch(_ context.Context, clobOrderIDs []string) error {
+	se.mu.Lock()
+	for _, id := range clobOrderIDs {
+		delete(se.open, id)
+	}
+	se.mu.Unlock()
+	slog.Info(se.logPrefix()+": would cancel orders", "count", len(clobOrderIDs))
+	return nil
+}
+
+func (se *shadowExecutor) CancelAll(_ context.Context) error {
+	se.mu.Lock()
+	n := len(se.open)
+	se.open = make(map[string]domain.LiveOrder)
+	se.mu.Unlock()
+	slog.Info(se.logPrefix()+": would cancel all orders", "count", n)
+	return nil
+}
+
+// GetOpenOrders returns the shadow orders placed by this process. Orders
+// restored from a previous run are unknown here and are reported as gone,
+// which the engine treats as an unfilled auto-cancel.
+func (se *shadowExecutor) GetOpenOrders(_ context.Context) ([]domain.LiveOrder, error) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	orders := make([]domain.LiveOrder, 0, len(se.open))
+	for _, o := range se.open {
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+// GetTrades is always empty: shadow orders never trade.
+func (se *shadowExecutor) GetTrades(_ context.Context, _ time.Time) ([]domain.LiveTrade, error) {
+	return nil, nil
+}
+
+// GetBalance returns the fixed shadow bankroll (Config.InitialCapital), or
+// the real wallet's balance in a placement dry-run.
+func (se *shadowExecutor) GetBalance(ctx context.Context) (float64, error) {
+	if se.dryRun && se.inner != nil {
+		return se.inner.GetBalance(ctx)
+	}
+	return se.balance, nil
+}
+
+// GetTradeableBalance reports the shadow bankroll with an unlimited
+// allowance, less the USDC resting in shadow bids. A placement dry-run
+// starts from the real wallet's tradeable balance instead.
+func (se *shadowExecutor) GetTradeableBalance(ctx context.Context) (domain.TradeableBalance, error) {
+	tb := domain.TradeableBalance{Wallet: se.balance, Allowance: se.balance}
+	if se.dryRun && se.inner != nil {
+		wallet, err := se.inner.GetTradeableBalance(ctx)
+		if err != nil {
+			return wallet, err
+		}
+		tb = wallet
+	}
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	for _, o := range se.open {
+		if !o.IsSell() {
+			tb.LockedInOrders += o.Size
+		}
+	}
+	return tb, nil
+}
+
+// logPrefix tags the would-be actions of shadow mode and placement dry-run.
+func (se *shadowExecutor) logPrefix() string {
+	if se.dryRun {
+		return "live: [DRY-RUN]"
+	}
+	return "live[shadow]"
+}
+
+func (se *shadowExecutor) IsNegRisk(ctx context.Context, tokenID string) (bool, error) {
+	if se.inner == nil {
+		return false, nil
+	}
+	return se.inner.IsNegRisk(ctx, tokenID)
+}
+
+// TickSize asks the real CLOB, so shadow bids are priced like real ones.
+func (se *shadowExecutor) TickSize(ctx context.Context, tokenID string) (float64, error) {
+	if se.inner == nil {
+		return domain.DefaultTickSize, nil
+	}
+	return se.inner.TickSize(ctx, tokenID)
+}
+
+// TokenBalance is always zero: shadow orders never acquire tokens, and the
+// real wallet's holdings must not leak into shadow state.
+func (se *shadowExecutor) TokenBalance(_ context.Context, _ string) (float64, error) {
+	return 0, nil
+}
+
+// shadowMerger logs merges instead of submitting them on-chain.
+type shadowMerger struct {
+	inner ports.MergeExecutor // gas estimates only; may be nil
+}
+
+func (sm shadowMerger) MergePositions(ctx context.Context, conditionID string, amount float64, negRisk bool) (domain.MergeResult, error) {
+	gas, _ := sm.EstimateGasCostUSD(ctx)
+	slog.Info("live[shadow]: would merge",
+		"condition", engine.TruncateStr(conditionID, 12),
+		"amount", fmt.Sprintf("$%.2f", amount),
+		"neg_risk", negRisk,
+		"est_gas", fmt.Sprintf("$%.4f", gas),
+	)
+	return domain.MergeResult{
+		ConditionID:  conditionID,
+		TxHash:       "shadow",
+		GasCostUSD:   gas,
+		USDCReceived: amount,
+		Success:      true,
+		ExecutedAt:   time.Now().UTC(),
+	}, nil
+}
+
+func (sm shadowMerger) SupportsNegRisk() bool {
+	return sm.inner != nil && sm.inner.SupportsNegRisk()
+}
+
+// EnableNegRisk passes the switch on to inner, whose SupportsNegRisk the
+// shadow merger reports.
+func (sm shadowMerger) EnableNegRisk(enabled bool) {
+	if m, ok := sm.inner.(ports.NegRiskMerger); ok {
+		m.EnableNegRisk(enabled)
+	}
+}
+
+func (sm shadowMerger) EstimateGasCostUSD(ctx context.Context) (float64, error) {
+	if sm.inner == nil {
+		return 0, nil
+	}
+	return sm.inner.EstimateGasCostUSD(ctx)
+}
+
+// EstimateGasCostPOL is zero: shadow merges burn no gas, so the gas runway
+// check never holds back shadow placement.
+func (sm shadowMerger) EstimateGasCostPOL(_ context.Context) (float64, error) {
+	return 0, nil
+}
+
+func (sm shadowMerger) GetPOLBalance(ctx context.Context) (float64, error) {
+	if sm.inner == nil {
+		return 0, nil
+	}
+	return sm.inner.GetPOLBalance(ctx)
+}
+
+func (sm shadowMerger) EnsureApprovals(_ context.Context) error {
+	return nil
+}
+
+// ConditionResolved reads the real chain through inner: a read costs no gas,
+// so shadow mode sees resolutions as they happen. False without an inner
+// ResolutionReader.
+func (sm shadowMerger) ConditionResolved(ctx context.Context, conditionID string) (bool, error) {
+	reader, ok := sm.inner.(ports.ResolutionReader)
+	if !ok {
+		return false, nil
+	}
+	return reader.ConditionResolved(ctx, conditionID)
+}
+
+// shadowWallet swaps a wallet's executor and merger for their shadow
+// counterparts, keeping the address so per-wallet accounting still works.
+// cfg sets the bankroll and whether this is a placement dry-run.
+func shadowWallet(w Wallet, cfg Config) Wallet {
+	if _, ok := w.Executor.(*shadowExecutor); !ok {
+		se := newShadowExecutor(w.Executor, cfg.InitialCapital)
+		se.dryRun = cfg.DryRunPlacement
+		w.Executor = se
+	}
+	if _, ok := w.Merger.(shadowMerger); !ok {
+		w.Merger = shadowMerger{inner: w.Merger}
+	}
+	return w
+}
@@ -0,0 +1,141 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// shutdownTimeout bounds the exit cleanup so a hung CLOB call cannot block exit.
+const shutdownTimeout = 10 * time.Second
+
+// Shutdown cancels every open CLOB order unless Config.NoCancelOnExit is set;
+// then only Config.CancelOnExit's unfilled pairs are cancelled, if enabled.
+// The run loop's context is already cancelled by then, so callers must pass a
+// fresh context (e.g. context.Background()); the cleanup is capped at 10
+// seconds either way.
+func (le *Engine) Shutdown(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+
+	switch {
+	case !le.cfg.NoCancelOnExit:
+		n, counted, err := le.CancelAllOrders(ctx)
+		if err != nil {
+			return fmt.Errorf("live.Shutdown: %w", err)
+		}
+		if !counted {
+			slog.Info("live: shutdown, cancelled all open orders", "orders", "unknown")
+			break
+		}
+		slog.Info("live: shutdown, cancelled all open orders", "orders", n)
+	case le.cfg.CancelOnExit:
+		n, err := le.CancelUnfilledOrders(ctx)
+		if err != nil {
+			return fmt.Errorf("live.Shutdown: %w", err)
+		}
+		slog.Info("live: shutdown, cancelled unfilled pairs", "orders", n)
+	default:
+		slog.Info("live: shutdown, leaving open orders on the book")
+	}
+	return nil
+}
+
+// CancelAllOrders cancels every open CLOB order of every wallet, including
+// the resting leg of partial pairs and unwind SELLs, so nothing can fill
+// after the bot stops tracking it. Local OPEN orders are then marked
+// CANCELLED; PARTIAL ones keep their status and filled size, and Reconcile
+// settles them from the trade history on the next start. If any wallet
+// fails, local state is left as is. Returns the number of CLOB orders
+// cancelled; counted is false when a wallet's open orders could not be
+// listed first, so the number is short by an unknown amount.
+func (le *Engine) CancelAllOrders(ctx context.Context) (cancelled int, counted bool, err error) {
+	var errs []error
+	counted = true
+	for _, w := range le.wallets {
+		open, err := w.Executor.GetOpenOrders(ctx)
+		if err != nil {
+			slog.Warn("live: could not count open orders before cancel", "wallet", shortAddr(w.Address), "err", err)
+			counted = false
+		}
+		if err := w.Executor.CancelAll(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("wallet %s: %w", shortAddr(w.Address), err))
+			continue
+		}
+		cancelled += len(open)
+	}
+	if len(errs) > 0 {
+		return cancelled, counted, fmt.Errorf("cancel all: %w", errors.Join(errs...))
+	}
+
+	local, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return cancelled, counted, fmt.Errorf("get open orders: %w", err)
+	}
+	for _, o := range local {
+		if o.Status != domain.LiveStatusOpen {
+			continue
+		}
+		if err := le.store.RetireLiveOrder(ctx, o.ID, domain.LiveStatusCancelled, domain.CloseShutdown); err != nil {
+			slog.Warn("live: could not persist cancelled order", "id", o.ID, "err", err)
+		}
+	}
+	return cancelled, counted, nil
+}
+
+// CancelUnfilledOrders cancels every pair whose orders are all still OPEN with
+// nothing filled, and marks them CANCELLED locally. Pairs with any fill (and
+// their unwind SELLs) are left alone so they remain mergeable after a restart.
+// Returns the number of orders cancelled.
+func (le *Engine) CancelUnfilledOrders(ctx context.Context) (int, error) {
+	open, err := le.store.GetOpenLiveOrders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get open orders: %w", err)
+	}
+
+	// Open orders alone hide a FILLED leg; judge each pair on all its orders.
+	seen := make(map[string]bool)
+	var toCancel []domain.LiveOrder
+	for _, o := range open {
+		if seen[o.PairID] {
+			continue
+		}
+		seen[o.PairID] = true
+		pair, err := le.store.GetLiveOrdersByPair(ctx, o.PairID)
+		if err != nil {
+			slog.Warn("live: could not load pair, leaving it open", "pair", o.PairID, "err", err)
+			continue
+		}
+		if pairUnfilled(pair) {
+			toCancel = append(toCancel, pair...)
+		}
+	}
+	if len(toCancel) == 0 {
+		return 0, nil
+	}
+
+	if err := le.cancelOrders(ctx, toCancel); err != nil {
+		// Keep local state as is: the orders may still be resting on the CLOB.
+		return 0, fmt.Errorf("cancel %d orders: %w", len(toCancel), err)
+	}
+	for _, o := range toCancel {
+		if err := le.store.UpdateLiveOrderStatus(ctx, o.ID, domain.LiveStatusCancelled); err != nil {
+			slog.Warn("live: could not persist cancelled order", "id", o.ID, "err", err)
+		}
+	}
+	return len(toCancel), nil
+}
+
+// pairUnfilled reports whether every order of a pair is an untouched OPEN bid.
+func pairUnfilled(orders []domain.LiveOrder) bool {
+	for _, o := range orders {
+		if o.IsSell() || o.Status != domain.LiveStatusOpen || o.FilledSize > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// unlistedExecutor cancels fine but cannot list its open orders.
+type unlistedExecutor struct {
+	*shadowExecutor
+}
+
+func (unlistedExecutor) GetOpenOrders(context.Context) ([]domain.LiveOrder, error) {
+	return nil, errors.New("orders endpoint down")
+}
+
+func TestCancelAllOrders_KeepsPartialFills(t *testing.T) {
+	ctx := context.Background()
+	exec := newShadowExecutor(nil, 0)
+	le, db := newTestEngine(t, exec, Config{})
+	saveRestingOrder(t, db, "open", 0)
+	saveRestingOrder(t, db, "partial", 4)
+	for range 2 {
+		_, err := exec.PlaceOrder(ctx, domain.PlaceOrderRequest{TokenID: "tok_yes", Price: 0.40, Size: 10})
+		require.NoError(t, err)
+	}
+
+	n, counted, err := le.CancelAllOrders(ctx)
+	require.NoError(t, err)
+	assert.True(t, counted)
+	assert.Equal(t, 2, n)
+
+	open, err := db.GetLiveOrderByCLOBID(ctx, "clob-open")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusCancelled, open.Status)
+
+	partial, err := db.GetLiveOrderByCLOBID(ctx, "clob-partial")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusPartial, partial.Status, "left for Reconcile to settle")
+	assert.InDelta(t, 4, partial.FilledSize, 1e-9)
+}
+
+func TestCancelAllOrders_UncountedWalletReportsUnknown(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, unlistedExecutor{newShadowExecutor(nil, 0)}, Config{})
+	saveRestingOrder(t, db, "open", 0)
+
+	n, counted, err := le.CancelAllOrders(ctx)
+	require.NoError(t, err, "the cancel itself went through")
+	assert.False(t, counted)
+	assert.Zero(t, n)
+
+	open, err := db.GetLiveOrderByCLOBID(ctx, "clob-open")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusCancelled, open.Status)
+}
+
+// cancelAllRecorder counts CLOB-wide cancels.
+type cancelAllRecorder struct {
+	*shadowExecutor
+	calls *int
+}
+
+func (r cancelAllRecorder) CancelAll(ctx context.Context) error {
+	*r.calls++
+	return r.shadowExecutor.CancelAll(ctx)
+}
+
+func TestShutdown_CancelsAllByDefault(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	le, db := newTestEngine(t, cancelAllRecorder{newShadowExecutor(nil, 0), &calls}, Config{CancelOnExit: true})
+	saveRestingOrder(t, db, "partial", 4)
+
+	require.NoError(t, le.Shutdown(ctx))
+	assert.Equal(t, 1, calls, "the CLOB-wide cancel ran, not the unfilled-pairs one")
+
+	partial, err := db.GetLiveOrderByCLOBID(ctx, "clob-partial")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusPartial, partial.Status)
+}
+
+func TestShutdown_NoCancelOnExitLeavesOrders(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	le, db := newTestEngine(t, cancelAllRecorder{newShadowExecutor(nil, 0), &calls}, Config{NoCancelOnExit: true})
+	saveRestingOrder(t, db, "open", 0)
+
+	require.NoError(t, le.Shutdown(ctx))
+	assert.Zero(t, calls)
+
+	open, err := db.GetLiveOrderByCLOBID(ctx, "clob-open")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusOpen, open.Status)
+}
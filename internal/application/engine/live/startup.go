@@ -0,0 +1,34 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Resume prepares the engine after a restart: it restores the persisted
+// circuit breaker and rotation cooldowns, and reconciles local open orders
+// against the CLOB (see Reconcile), so orders filled or cancelled while the
+// bot was down are not traded on stale state. Call it once, after
+// ApplyLiveSchema and before the first RunOnce.
+func (le *Engine) Resume(ctx context.Context) error {
+	cb, err := le.store.LoadCircuitBreaker(ctx)
+	if err != nil {
+		return fmt.Errorf("live.Resume: load circuit breaker: %w", err)
+	}
+	le.RestoreCircuitBreaker(cb)
+	if cb.Triggered {
+		slog.Warn("live: circuit breaker restored in triggered state",
+			"reason", cb.TriggeredReason,
+			"cooldown_until", cb.CooldownUntil,
+		)
+	}
+
+	le.loadCooldowns(ctx)
+
+	if _, err := le.Reconcile(ctx); err != nil {
+		return fmt.Errorf("live.Resume: %w", err)
+	}
+	slog.Info("live: resumed", "consecutive_losses", cb.ConsecutiveLosses)
+	return nil
+}
@@ -0,0 +1,60 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestResume_RestoresStateAndReconcilesFirst(t *testing.T) {
+	ctx := context.Background()
+	// clob-a filled while the bot was down and is no longer on the book.
+	exec := historyExecutor{shadowExecutor: newShadowExecutor(nil, 0), trades: []domain.LiveTrade{
+		{CLOBTradeID: "t1", CLOBOrderID: "clob-a", TokenID: "tok_yes", Price: 0.40, Size: 4, Timestamp: time.Now()},
+	}}
+	le, db := newTestEngine(t, exec, Config{})
+	saveRestingOrder(t, db, "a", 0)
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.SaveCircuitBreaker(ctx, domain.CircuitBreaker{
+		ConsecutiveLosses: 2, TotalPnL: -12, Triggered: true, TriggeredReason: "max drawdown",
+	}))
+	require.NoError(t, db.SaveLiveCooldown(ctx, "0xrotated", until, "rotation"))
+
+	require.NoError(t, le.Resume(ctx))
+
+	assert.True(t, le.breaker.Triggered, "a tripped breaker stays tripped across restarts")
+	assert.Equal(t, "max drawdown", le.breaker.TriggeredReason)
+	assert.Equal(t, 2, le.breaker.ConsecutiveLosses)
+	assert.InDelta(t, -12, le.breaker.TotalPnL, 1e-9)
+	assert.Equal(t, circuitBreakerLosses, le.breaker.MaxLosses, "limits come from the config, not the DB")
+
+	assert.True(t, le.inCooldown("0xrotated"))
+	assert.WithinDuration(t, until, le.cooldowns["0xrotated"], time.Second)
+
+	a, err := db.GetLiveOrderByCLOBID(ctx, "clob-a")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusFilled, a.Status, "settled before any cycle ran")
+	assert.InDelta(t, 4, a.FilledSize, 1e-9)
+
+	res, err := le.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, res.CircuitOpen, "the first cycle honours the restored breaker")
+}
+
+func TestResume_RestoresLossCooldown(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{})
+	require.NoError(t, db.SaveCircuitBreaker(ctx, domain.CircuitBreaker{
+		ConsecutiveLosses: 3, CooldownUntil: time.Now().Add(30 * time.Minute),
+	}))
+
+	require.NoError(t, le.Resume(ctx))
+	assert.False(t, le.breaker.IsOpen(), "the loss cooldown outlives the restart")
+	assert.Equal(t, 3, le.breaker.ConsecutiveLosses)
+}
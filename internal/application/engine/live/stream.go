@@ -0,0 +1,95 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// drainOrderEvents applies the order events streamed since the last cycle
+// (Config.OrderEvents) without blocking, and returns how many orders they
+// completed. It runs before syncOrderState, which stays as the fallback for
+// anything the stream missed; fills are keyed by trade ID, so a trade seen on
+// both is recorded once.
+func (le *Engine) drainOrderEvents(ctx context.Context) (newFills int) {
+	if le.cfg.OrderEvents == nil {
+		return 0
+	}
+	events := le.cfg.OrderEvents.Events()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return newFills
+			}
+			filled, err := le.applyOrderEvent(ctx, ev)
+			if err != nil {
+				slog.Warn("live: error applying order event",
+					"type", ev.Type, "clob_id", ev.CLOBOrderID, "err", err)
+			}
+			if filled {
+				newFills++
+			}
+		default:
+			return newFills
+		}
+	}
+}
+
+// applyOrderEvent records a single streamed fill or cancellation and reports
+// whether it completed the order. The fill timestamp comes from the event, so
+// the merge delay clock starts when the trade matched rather than at the
+// cycle that drained it.
+func (le *Engine) applyOrderEvent(ctx context.Context, ev domain.LiveOrderEvent) (filled bool, err error) {
+	local, err := le.store.GetLiveOrderByCLOBID(ctx, ev.CLOBOrderID)
+	if err != nil {
+		return false, fmt.Errorf("applyOrderEvent: lookup: %w", err)
+	}
+	if local == nil {
+		return false, nil
+	}
+	if local.Status != domain.LiveStatusOpen && local.Status != domain.LiveStatusPartial {
+		return false, nil
+	}
+
+	switch ev.Type {
+	case domain.LiveEventCancel:
+		if local.FilledSize > 0 {
+			return false, nil
+		}
+		return false, le.store.UpdateLiveOrderStatus(ctx, local.ID, orderGone(*local, time.Now()))
+
+	case domain.LiveEventFill:
+		if ev.Size <= 0 {
+			return false, nil
+		}
+		if ev.CLOBTradeID != "" {
+			seen, err := le.store.HasLiveFill(ctx, local.ID, ev.CLOBTradeID)
+			if err != nil {
+				return false, fmt.Errorf("applyOrderEvent: check fill: %w", err)
+			}
+			if seen {
+				return false, nil
+			}
+		}
+
+		updated, err := le.recordFill(ctx, *local, ev.CLOBTradeID, ev.Price, ev.Size, ev.Timestamp)
+		if err != nil {
+			return false, fmt.Errorf("applyOrderEvent: %w", err)
+		}
+
+		slog.Info("live: streamed fill",
+			"side", updated.Side,
+			"market", engine.TruncateStr(updated.Question, 30),
+			"price", fmt.Sprintf("$%.2f", updated.FilledPrice),
+			"filled", fmt.Sprintf("$%.2f/$%.2f", updated.FilledSize, updated.Size),
+			"status", updated.Status,
+		)
+		return updated.Status == domain.LiveStatusFilled, nil
+	}
+	return false, nil
+}
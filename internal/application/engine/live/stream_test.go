@@ -0,0 +1,102 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// chanStream is an OrderEventStream over a test channel.
+type chanStream chan domain.LiveOrderEvent
+
+func (cs chanStream) Events() <-chan domain.LiveOrderEvent { return cs }
+
+func saveRestingOrder(t *testing.T, db *storage.SQLiteStorage, id string, filled float64) {
+	t.Helper()
+	status := domain.LiveStatusOpen
+	if filled > 0 {
+		status = domain.LiveStatusPartial
+	}
+	require.NoError(t, db.SaveLiveOrder(context.Background(), domain.LiveOrder{
+		ID:          id,
+		CLOBOrderID: "clob-" + id,
+		ConditionID: "0xcond",
+		TokenID:     "tok_yes",
+		Side:        "YES",
+		BidPrice:    0.40,
+		Size:        10,
+		FilledSize:  filled,
+		PairID:      "pair-1",
+		PlacedAt:    time.Now().UTC().Add(-time.Hour),
+		Status:      status,
+	}))
+}
+
+func TestDrainOrderEvents_RecordsEachTradeOnce(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{})
+	saveRestingOrder(t, db, "yes", 0)
+
+	matched := time.Now().UTC().Add(-10 * time.Minute).Truncate(time.Second)
+	events := make(chanStream, 4)
+	le.cfg.OrderEvents = events
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventFill, CLOBOrderID: "clob-yes", CLOBTradeID: "t1", Price: 0.40, Size: 4, Timestamp: matched}
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventFill, CLOBOrderID: "clob-yes", CLOBTradeID: "t1", Price: 0.40, Size: 4, Timestamp: matched}
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventFill, CLOBOrderID: "clob-yes", CLOBTradeID: "t2", Price: 0.40, Size: 6, Timestamp: matched}
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventFill, CLOBOrderID: "clob-unknown", CLOBTradeID: "t3", Price: 0.40, Size: 6, Timestamp: matched}
+
+	assert.Equal(t, 1, le.drainOrderEvents(ctx), "one order completed")
+
+	o, err := db.GetLiveOrderByCLOBID(ctx, "clob-yes")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusFilled, o.Status)
+	assert.InDelta(t, 10, o.FilledSize, 1e-9, "the repeated trade t1 is not counted twice")
+	require.NotNil(t, o.FilledAt)
+	assert.True(t, o.FilledAt.Equal(matched), "the merge delay starts when the trade matched")
+
+	seen, err := db.HasLiveFill(ctx, "yes", "t2")
+	require.NoError(t, err)
+	assert.True(t, seen, "the trade ID is stored so the REST sync skips it")
+}
+
+func TestDrainOrderEvents_CancelOnlyClosesUnfilledOrders(t *testing.T) {
+	ctx := context.Background()
+	le, db := newTestEngine(t, newShadowExecutor(nil, 0), Config{})
+	saveRestingOrder(t, db, "open", 0)
+	saveRestingOrder(t, db, "partial", 4)
+
+	events := make(chanStream, 2)
+	le.cfg.OrderEvents = events
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventCancel, CLOBOrderID: "clob-open"}
+	events <- domain.LiveOrderEvent{Type: domain.LiveEventCancel, CLOBOrderID: "clob-partial"}
+
+	assert.Zero(t, le.drainOrderEvents(ctx))
+
+	open, err := db.GetLiveOrderByCLOBID(ctx, "clob-open")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusCancelled, open.Status)
+
+	partial, err := db.GetLiveOrderByCLOBID(ctx, "clob-partial")
+	require.NoError(t, err)
+	assert.Equal(t, domain.LiveStatusPartial, partial.Status,
+		"a partial is left to the REST sync, which settles its traded size")
+}
+
+func TestDrainOrderEvents_DoesNotBlock(t *testing.T) {
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 0), Config{})
+	assert.Zero(t, le.drainOrderEvents(context.Background()), "no stream configured")
+
+	le.cfg.OrderEvents = make(chanStream)
+	assert.Zero(t, le.drainOrderEvents(context.Background()), "an idle stream returns at once")
+
+	closed := make(chanStream)
+	close(closed)
+	le.cfg.OrderEvents = closed
+	assert.Zero(t, le.drainOrderEvents(context.Background()), "a stopped stream returns at once")
+}
@@ -0,0 +1,199 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// completePartialsWithTaker buys the missing leg of one-sided pairs at the
+// ask instead of waiting for the maker bid to fill. It only runs with
+// AllowTakerCompletion, for pairs one-sided for TakerAfterHours whose
+// counterpart is still resting unfilled, and only when buying every share at
+// the worst ask level needed keeps the merge above MinMergeProfit after fees
+// and buffered gas. The resting bid is cancelled and a FOK BUY sent; a FOK
+// that does not fill leaves the pair to flattenStalePartials.
+func (le *Engine) completePartialsWithTaker(ctx context.Context, opps map[string]domain.Opportunity) (completed int) {
+	if !le.cfg.AllowTakerCompletion {
+		return 0
+	}
+	pairIDs, err := le.store.GetPartialPairs(ctx)
+	if err != nil {
+		slog.Warn("live: error loading partial pairs", "err", err)
+		return 0
+	}
+
+	threshold := time.Duration(le.cfg.TakerAfterHours * float64(time.Hour))
+	now := time.Now().UTC()
+	var gasUSD float64
+
+	for _, pairID := range pairIDs {
+		orders, err := le.store.GetLiveOrdersByPair(ctx, pairID)
+		if err != nil {
+			continue
+		}
+		filled, other, ok := takerCandidate(orders)
+		if !ok {
+			continue
+		}
+		filledAt := filled.PlacedAt
+		if filled.FilledAt != nil {
+			filledAt = *filled.FilledAt
+		}
+		if now.Sub(filledAt) < threshold {
+			continue
+		}
+		opp, ok := opps[filled.ConditionID]
+		if !ok {
+			continue
+		}
+		book := opp.NoBook
+		if other.Side == "YES" {
+			book = opp.YesBook
+		}
+
+		shares := filled.UnmergedSize() / entryPrice(filled)
+		worst, ok := takerWorstAsk(book.Asks, shares)
+		if !ok {
+			continue
+		}
+		if gasUSD == 0 {
+			gasUSD, _ = le.merger.EstimateGasCostUSD(ctx)
+			if gasUSD <= 0 {
+				gasUSD = gasFallbackUSD
+			}
+		}
+		feeRate := opp.Market.EffectiveFeeRate(le.cfg.FeeRate)
+		spread := -domain.FillCostPerEvent(entryPrice(filled), worst, feeRate) * shares
+		net := spread - gasUSD*(1+le.cfg.GasBufferPct)
+		if net < le.cfg.MinMergeProfit {
+			slog.Debug("live: taker completion not profitable",
+				"market", engine.TruncateStr(filled.Question, 30),
+				"side", other.Side,
+				"ask", fmt.Sprintf("%.2f", worst),
+				"net", fmt.Sprintf("$%.4f", net),
+			)
+			continue
+		}
+
+		if err := le.takeMissingLeg(ctx, filled, other, shares, worst, now); err != nil {
+			slog.Warn("live: taker completion failed",
+				"market", engine.TruncateStr(filled.Question, 30),
+				"side", other.Side,
+				"err", err,
+			)
+			continue
+		}
+		completed++
+		slog.Info("live: TAKER completed pair",
+			"market", engine.TruncateStr(filled.Question, 30),
+			"side", other.Side,
+			"ask", fmt.Sprintf("%.2f", worst),
+			"shares", fmt.Sprintf("%.2f", shares),
+			"net", fmt.Sprintf("$%.4f", net),
+		)
+	}
+	return completed
+}
+
+// takerCandidate returns the filled leg and the still unfilled resting leg of
+// a pair; false when the pair is complete, unwinding or already partly filled
+// on both sides.
+func takerCandidate(orders []domain.LiveOrder) (filled, other domain.LiveOrder, ok bool) {
+	var haveFilled, haveOther bool
+	for _, o := range orders {
+		switch {
+		case o.IsSell():
+			return filled, other, false
+		case o.Status == domain.LiveStatusFilled:
+			filled, haveFilled = o, true
+		case o.Status == domain.LiveStatusOpen && o.FilledSize == 0:
+			other, haveOther = o, true
+		}
+	}
+	ok = haveFilled && haveOther && filled.Side != other.Side && filled.UnmergedSize() > 0
+	return filled, other, ok
+}
+
+// takerWorstAsk walks asks from the best price and returns the highest price
+// needed to buy shares; false when the book is not deep enough.
+func takerWorstAsk(asks []domain.BookEntry, shares float64) (float64, bool) {
+	remaining := shares
+	for _, a := range asks {
+		if a.Size <= 0 {
+			continue
+		}
+		remaining -= a.Size
+		if remaining <= 1e-9 {
+			return a.Price, true
+		}
+	}
+	return 0, false
+}
+
+// takeMissingLeg cancels the resting bid of other and buys shares at limit
+// price worst with a FOK order, recording the fill as the pair's new leg.
+func (le *Engine) takeMissingLeg(ctx context.Context, filled, other domain.LiveOrder, shares, worst float64, now time.Time) error {
+	exec := le.executorFor(other)
+	if other.CLOBOrderID != "" {
+		if err := exec.CancelOrder(ctx, other.CLOBOrderID); err != nil {
+			return fmt.Errorf("cancel resting bid: %w", err)
+		}
+	}
+	if err := le.store.UpdateLiveOrderStatus(ctx, other.ID, domain.LiveStatusCancelled); err != nil {
+		slog.Warn("live: error updating cancelled bid", "err", err)
+	}
+
+	shares = domain.FloorShares(shares)
+	size := shares * worst
+	placed, err := exec.PlaceOrder(ctx, domain.PlaceOrderRequest{
+		TokenID:     other.TokenID,
+		ConditionID: other.ConditionID,
+		Price:       worst,
+		Size:        size,
+		Shares:      shares,
+		Side:        "BUY",
+		NegRisk:     other.NegRisk,
+		OrderType:   "FOK",
+	})
+	if err != nil {
+		return fmt.Errorf("place FOK: %w", err)
+	}
+	if placed.Status != "matched" && placed.TakenAmount <= 0 {
+		return fmt.Errorf("FOK not filled (status %q)", placed.Status)
+	}
+
+	filledAt := now
+	leg := domain.LiveOrder{
+		ID:            uuid.New().String(),
+		CLOBOrderID:   placed.CLOBOrderID,
+		ConditionID:   other.ConditionID,
+		TokenID:       other.TokenID,
+		Side:          other.Side,
+		BidPrice:      worst,
+		Size:          size,
+		SizeShares:    shares,
+		FilledSize:    size,
+		FilledPrice:   worst,
+		FilledAt:      &filledAt,
+		PairID:        other.PairID,
+		PlacedAt:      now,
+		Status:        domain.LiveStatusFilled,
+		Question:      other.Question,
+		DailyReward:   other.DailyReward,
+		EndDate:       other.EndDate,
+		NegRisk:       other.NegRisk,
+		WalletAddress: other.WalletAddress,
+		Shadow:        other.Shadow,
+	}
+	if err := le.store.SaveLiveOrder(ctx, leg); err != nil {
+		return fmt.Errorf("save taker leg: %w", err)
+	}
+	return nil
+}
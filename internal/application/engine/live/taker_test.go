@@ -0,0 +1,157 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// fokExecutor fills every FOK order and records the requests it gets.
+type fokExecutor struct {
+	*shadowExecutor
+	placed    []domain.PlaceOrderRequest
+	cancelled []string
+}
+
+func (fe *fokExecutor) PlaceOrder(ctx context.Context, req domain.PlaceOrderRequest) (domain.PlacedOrder, error) {
+	fe.placed = append(fe.placed, req)
+	placed, err := fe.shadowExecutor.PlaceOrder(ctx, req)
+	if req.OrderType == "FOK" {
+		placed.Status = "matched"
+	}
+	return placed, err
+}
+
+func (fe *fokExecutor) CancelOrder(ctx context.Context, clobOrderID string) error {
+	fe.cancelled = append(fe.cancelled, clobOrderID)
+	return fe.shadowExecutor.CancelOrder(ctx, clobOrderID)
+}
+
+// saveOneSidedPair stores a YES leg filled 2h ago at 0.45 for $4.50 and a
+// NO bid still resting unfilled.
+func saveOneSidedPair(t *testing.T, db *storage.SQLiteStorage, conditionID string) {
+	t.Helper()
+	filledAt := time.Now().UTC().Add(-2 * time.Hour)
+	for _, o := range []domain.LiveOrder{
+		{
+			ID: conditionID + "-YES", CLOBOrderID: "clob-yes", ConditionID: conditionID,
+			TokenID: "tok_yes", Side: "YES", BidPrice: 0.45, Size: 4.5, FilledSize: 4.5,
+			PairID: "pair-" + conditionID, PlacedAt: filledAt, FilledAt: &filledAt,
+			Status: domain.LiveStatusFilled,
+		},
+		{
+			ID: conditionID + "-NO", CLOBOrderID: "clob-no", ConditionID: conditionID,
+			TokenID: "tok_no", Side: "NO", BidPrice: 0.45, Size: 4.5,
+			PairID: "pair-" + conditionID, PlacedAt: filledAt,
+			Status: domain.LiveStatusOpen,
+		},
+	} {
+		require.NoError(t, db.SaveLiveOrder(context.Background(), o))
+	}
+}
+
+func newTakerEngine(t *testing.T) (*Engine, *storage.SQLiteStorage, *fokExecutor) {
+	t.Helper()
+	exec := &fokExecutor{shadowExecutor: newShadowExecutor(nil, 0)}
+	le, db := newTestEngine(t, exec, Config{OrderSize: 5})
+	le.merger = shadowMerger{}
+	le.cfg.AllowTakerCompletion = true
+	return le, db, exec
+}
+
+func takerOpp(conditionID string, asks []domain.BookEntry) map[string]domain.Opportunity {
+	opp := exposureOpp(conditionID)
+	opp.NoBook.Asks = asks
+	return map[string]domain.Opportunity{conditionID: opp}
+}
+
+func TestCompletePartialsWithTaker_BuysMissingLeg(t *testing.T) {
+	ctx := context.Background()
+	le, db, exec := newTakerEngine(t)
+	saveOneSidedPair(t, db, "0xcond")
+
+	// 10 shares needed: 5 at 0.50 and the rest at 0.52 → 0.97 per pair.
+	opps := takerOpp("0xcond", []domain.BookEntry{{Price: 0.50, Size: 5}, {Price: 0.52, Size: 10}})
+	assert.Equal(t, 1, le.completePartialsWithTaker(ctx, opps))
+
+	assert.Equal(t, []string{"clob-no"}, exec.cancelled)
+	require.Len(t, exec.placed, 1)
+	req := exec.placed[0]
+	assert.Equal(t, "FOK", req.OrderType)
+	assert.Equal(t, "tok_no", req.TokenID)
+	assert.InDelta(t, 0.52, req.Price, 1e-9)
+	assert.InDelta(t, 5.2, req.Size, 1e-9)
+
+	orders, err := db.GetLiveOrdersByPair(ctx, "pair-0xcond")
+	require.NoError(t, err)
+	var filledNO, cancelledNO int
+	for _, o := range orders {
+		if o.Side != "NO" {
+			continue
+		}
+		switch o.Status {
+		case domain.LiveStatusFilled:
+			filledNO++
+			assert.InDelta(t, 0.52, o.FilledPrice, 1e-9)
+		case domain.LiveStatusCancelled:
+			cancelledNO++
+		}
+	}
+	assert.Equal(t, 1, filledNO)
+	assert.Equal(t, 1, cancelledNO)
+}
+
+func TestCompletePartialsWithTaker_Skips(t *testing.T) {
+	cases := []struct {
+		name   string
+		asks   []domain.BookEntry
+		enable bool
+	}{
+		{"disabled", []domain.BookEntry{{Price: 0.50, Size: 100}}, false},
+		{"pair cost above 1", []domain.BookEntry{{Price: 0.58, Size: 100}}, true},
+		{"book too thin", []domain.BookEntry{{Price: 0.50, Size: 4}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			le, db, exec := newTakerEngine(t)
+			le.cfg.AllowTakerCompletion = tc.enable
+			saveOneSidedPair(t, db, "0xcond")
+
+			assert.Zero(t, le.completePartialsWithTaker(ctx, takerOpp("0xcond", tc.asks)))
+			assert.Empty(t, exec.placed)
+			assert.Empty(t, exec.cancelled)
+		})
+	}
+}
+
+func TestCompletePartialsWithTaker_WaitsForThreshold(t *testing.T) {
+	le, db, exec := newTakerEngine(t)
+	le.cfg.TakerAfterHours = 3
+	saveOneSidedPair(t, db, "0xcond")
+
+	opps := takerOpp("0xcond", []domain.BookEntry{{Price: 0.50, Size: 100}})
+	assert.Zero(t, le.completePartialsWithTaker(context.Background(), opps))
+	assert.Empty(t, exec.placed)
+}
+
+func TestTakerWorstAsk(t *testing.T) {
+	asks := []domain.BookEntry{{Price: 0.50, Size: 5}, {Price: 0.51, Size: 0}, {Price: 0.53, Size: 5}}
+
+	worst, ok := takerWorstAsk(asks, 5)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.50, worst, 1e-9)
+
+	worst, ok = takerWorstAsk(asks, 8)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.53, worst, 1e-9)
+
+	_, ok = takerWorstAsk(asks, 11)
+	assert.False(t, ok)
+}
@@ -0,0 +1,38 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// tickSize is the token's minimum price increment, asked of the CLOB once
+// per token and cached for the life of the engine. When it cannot be read
+// the default 0.01 tick is used, uncached so the next placement retries:
+// cent prices are valid on every finer tick the CLOB uses.
+func (le *Engine) tickSize(ctx context.Context, wallet Wallet, tokenID string) float64 {
+	if tick, ok := le.tickSizes[tokenID]; ok {
+		return tick
+	}
+	tick, err := wallet.Executor.TickSize(ctx, tokenID)
+	if err != nil {
+		slog.Warn("live: tick size unavailable, assuming the default",
+			"token", engine.TruncateStr(tokenID, 16), "err", err)
+		return domain.DefaultTickSize
+	}
+	le.tickSizes[tokenID] = tick
+	return tick
+}
+
+// checkOnTick rejects a pair whose bids are off their market's price grid
+// before anything is signed: the CLOB would refuse them.
+func checkOnTick(yesBid, yesTick, noBid, noTick float64) error {
+	if !domain.OnTick(yesBid, yesTick) || !domain.OnTick(noBid, noTick) {
+		return fmt.Errorf("bids %g/%g are not multiples of tick %g/%g: %w",
+			yesBid, noBid, yesTick, noTick, domain.ErrNonRetryable)
+	}
+	return nil
+}
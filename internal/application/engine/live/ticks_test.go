@@ -0,0 +1,53 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// fineTickExecutor quotes a 0.001 tick and counts the lookups.
+type fineTickExecutor struct {
+	*shadowExecutor
+	lookups int
+}
+
+func (e *fineTickExecutor) TickSize(_ context.Context, _ string) (float64, error) {
+	e.lookups++
+	return 0.001, nil
+}
+
+func TestPlaceOrderPair_UsesMarketTick(t *testing.T) {
+	ctx := context.Background()
+	exec := &fineTickExecutor{shadowExecutor: newShadowExecutor(nil, 0)}
+	le, db := newTestEngine(t, exec, Config{OrderSize: 5})
+
+	opp := exposureOpp("0xcond")
+	opp.YesBook.Bids = []domain.BookEntry{{Price: 0.473, Size: 100}}
+	_, _, err := le.placeOrderPair(ctx, opp, 10, le.wallets[0])
+	require.NoError(t, err)
+
+	open, err := db.GetOpenLiveOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	for _, o := range open {
+		assert.True(t, domain.OnTick(o.BidPrice, 0.001), "%s bid %g on the 0.001 grid", o.Side, o.BidPrice)
+	}
+
+	_, _, err = le.placeOrderPair(ctx, exposureOpp("0xother"), 10, le.wallets[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, exec.lookups, "one lookup per token, then cached")
+}
+
+func TestCheckOnTick(t *testing.T) {
+	assert.NoError(t, checkOnTick(0.47, 0.01, 0.473, 0.001))
+
+	err := checkOnTick(0.473, 0.01, 0.47, 0.01)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrNonRetryable), "an off-tick bid is never sent")
+}
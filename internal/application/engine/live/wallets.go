@@ -0,0 +1,220 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// Wallet is one funded Polymarket account. Both legs of a pair are placed,
+// cancelled and merged from the same wallet, since a merge needs the YES and
+// NO tokens in a single address.
+type Wallet struct {
+	Address     string
+	Executor    ports.OrderExecutor
+	Merger      ports.MergeExecutor
+	MaxExposure float64             // per-wallet cap on deployed capital (0 = balance only)
+	Rewards     ports.RewardsSource // optional: realized rewards paid to this wallet
+}
+
+// walletState is a wallet's funds as seen at the start of a cycle.
+type walletState struct {
+	Wallet
+	Balance  float64
+	USDC     float64 // USDC.e held on-chain, resting bids included
+	Deployed float64
+	LowGas   bool // too little POL to merge; no new orders this cycle
+
+	freshBalance bool // Balance re-read since the last placement (see checkBalanceSufficient)
+}
+
+// headroom is the USDC this wallet can still commit to new orders.
+func (ws walletState) headroom() float64 {
+	if ws.LowGas {
+		return 0
+	}
+	avail := ws.Balance - balanceReserveUSDC
+	if ws.MaxExposure > 0 {
+		avail = math.Min(avail, ws.MaxExposure-ws.Deployed)
+	}
+	return math.Max(avail, 0)
+}
+
+// SetWallets replaces the funding wallets. The first wallet is the primary:
+// orders saved without a wallet address (single-wallet history) belong to it.
+// In shadow mode every wallet is wrapped in the shadow executor and merger.
+// Call before the first RunOnce.
+func (le *Engine) SetWallets(wallets []Wallet) {
+	if len(wallets) == 0 {
+		return
+	}
+	if le.cfg.ShadowMode {
+		shadowed := make([]Wallet, len(wallets))
+		for i, w := range wallets {
+			shadowed[i] = shadowWallet(w, le.cfg)
+		}
+		wallets = shadowed
+	}
+	le.wallets = wallets
+	le.executor = wallets[0].Executor
+	le.merger = wallets[0].Merger
+	le.enableNegRisk()
+}
+
+// enableNegRisk turns on NegRisk merges on every wallet's merger when
+// Config.AllowNegRisk is set. Turning the flag off on a reload is left to
+// negRiskEnabled: new NegRisk markets are no longer entered, while positions
+// already held in them can still merge.
+func (le *Engine) enableNegRisk() {
+	if !le.cfg.AllowNegRisk {
+		return
+	}
+	for _, w := range le.wallets {
+		if m, ok := w.Merger.(ports.NegRiskMerger); ok {
+			m.EnableNegRisk(true)
+		}
+	}
+}
+
+// walletFor returns the wallet an order was placed from.
+func (le *Engine) walletFor(address string) Wallet {
+	for _, w := range le.wallets {
+		if strings.EqualFold(w.Address, address) {
+			return w
+		}
+	}
+	return le.wallets[0]
+}
+
+func (le *Engine) executorFor(o domain.LiveOrder) ports.OrderExecutor {
+	return le.walletFor(o.WalletAddress).Executor
+}
+
+func (le *Engine) mergerFor(o domain.LiveOrder) ports.MergeExecutor {
+	return le.walletFor(o.WalletAddress).Merger
+}
+
+// walletKey normalises an order's wallet address to the wallet it maps to.
+func (le *Engine) walletKey(address string) string {
+	return strings.ToLower(le.walletFor(address).Address)
+}
+
+// loadWalletStates fetches every wallet's balance and deployed capital. The
+// balance is the tradeable one: capped by the exchange allowance and net of
+// the USDC our resting bids already reserve.
+// Wallets whose balance cannot be read are left out for this cycle; an error
+// is returned only when none could be read.
+func (le *Engine) loadWalletStates(ctx context.Context) ([]walletState, float64, error) {
+	deployed := make(map[string]float64, len(le.wallets))
+	if openOrders, err := le.store.GetOpenLiveOrders(ctx); err == nil {
+		for _, o := range openOrders {
+			if o.IsSell() {
+				continue
+			}
+			deployed[le.walletKey(o.WalletAddress)] += deployedAmount(o)
+		}
+	}
+
+	var (
+		states []walletState
+		total  float64
+		errs   []error
+	)
+	for _, w := range le.wallets {
+		tb, err := w.Executor.GetTradeableBalance(ctx)
+		if err != nil {
+			slog.Warn("live: wallet balance unavailable, skipping wallet this cycle",
+				"wallet", shortAddr(w.Address), "err", err)
+			errs = append(errs, err)
+			continue
+		}
+		bal := tb.Available()
+		slog.Debug("live: wallet balance",
+			"wallet", shortAddr(w.Address),
+			"usdc", fmt.Sprintf("$%.2f", tb.Wallet),
+			"allowance", fmt.Sprintf("$%.6g", tb.Allowance),
+			"locked_in_orders", fmt.Sprintf("$%.2f", tb.LockedInOrders),
+			"available", fmt.Sprintf("$%.2f", bal),
+		)
+		states = append(states, walletState{
+			Wallet:   w,
+			Balance:  bal,
+			USDC:     tb.Wallet,
+			Deployed: deployed[strings.ToLower(w.Address)],
+		})
+		total += bal
+	}
+	if len(states) == 0 {
+		return nil, 0, fmt.Errorf("loadWalletStates: %w", errors.Join(errs...))
+	}
+
+	if len(le.wallets) > 1 {
+		for _, s := range states {
+			slog.Info("live: wallet",
+				"wallet", shortAddr(s.Address),
+				"balance", fmt.Sprintf("$%.2f", s.Balance),
+				"deployed", fmt.Sprintf("$%.2f", s.Deployed),
+			)
+		}
+	}
+	return states, total, nil
+}
+
+// pickWallet returns the wallet with the most headroom, so capital spreads
+// evenly across accounts instead of filling one before touching the next.
+func pickWallet(states []walletState) (int, bool) {
+	best, bestRoom := -1, 0.0
+	for i, s := range states {
+		if room := s.headroom(); room > bestRoom {
+			best, bestRoom = i, room
+		}
+	}
+	return best, best >= 0
+}
+
+// deployedAmount is the capital an entry order currently ties up.
+func deployedAmount(o domain.LiveOrder) float64 {
+	switch o.Status {
+	case domain.LiveStatusOpen:
+		return o.Size
+	case domain.LiveStatusPartial:
+		return o.Size - o.FilledSize
+	case domain.LiveStatusFilled:
+		return o.FilledSize
+	}
+	return 0
+}
+
+// groupByWallet splits orders by the wallet that placed them.
+func (le *Engine) groupByWallet(orders []domain.LiveOrder) map[string][]domain.LiveOrder {
+	byWallet := make(map[string][]domain.LiveOrder)
+	for _, o := range orders {
+		k := le.walletKey(o.WalletAddress)
+		byWallet[k] = append(byWallet[k], o)
+	}
+	return byWallet
+}
+
+// cancelOrders cancels orders in one batch per wallet.
+func (le *Engine) cancelOrders(ctx context.Context, orders []domain.LiveOrder) error {
+	var errs []error
+	for _, group := range le.groupByWallet(orders) {
+		if err := le.executorFor(group[0]).CancelBatch(ctx, clobOrderIDs(group)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func shortAddr(addr string) string {
+	if len(addr) <= 10 {
+		return addr
+	}
+	return addr[:6] + "…" + addr[len(addr)-4:]
+}
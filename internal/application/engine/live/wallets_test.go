@@ -0,0 +1,27 @@
+package live
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWalletStates_NetsRestingBids(t *testing.T) {
+	ctx := context.Background()
+	le, _ := newTestEngine(t, newShadowExecutor(nil, 50), Config{OrderSize: 5})
+
+	states, total, err := le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.InDelta(t, 50, total, 1e-9)
+
+	_, deployed, err := le.placeOrderPair(ctx, exposureOpp("0xcond"), 10, le.wallets[0])
+	require.NoError(t, err)
+
+	states, total, err = le.loadWalletStates(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 50-deployed, states[0].Balance, 1e-9, "resting bids reserve their USDC")
+	assert.InDelta(t, 50-deployed, total, 1e-9)
+}
@@ -0,0 +1,110 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// CompareFills replays the full trade history of a condition's tokens
+// against each of its paper orders (--paper-compare) and sets the result
+// next to what checkFills recorded. The live simulation only sees trades
+// from the cycles it ran, capped at TradeLookbackHours; the replay sees the
+// whole window, from the oldest PlacedAt to the last order's ClosedAt (or
+// now while one is still open), so gaps in the paper loop show up as a
+// divergence between the two. Orders are returned oldest first.
+func (pe *Engine) CompareFills(ctx context.Context, conditionID string) ([]domain.PaperFillComparison, error) {
+	orders, err := pe.store.GetPaperOrdersByCondition(ctx, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("paper.CompareFills: get orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	byToken := make(map[string][]domain.VirtualOrder)
+	for _, o := range orders {
+		byToken[o.TokenID] = append(byToken[o.TokenID], o)
+	}
+
+	tradesByToken := make(map[string][]domain.Trade, len(byToken))
+	for tokenID, tokenOrders := range byToken {
+		from, to := now, time.Time{}
+		for _, o := range tokenOrders {
+			if o.PlacedAt.Before(from) {
+				from = o.PlacedAt
+			}
+			if end := orderEnd(o, now); end.After(to) {
+				to = end
+			}
+		}
+		if !to.Before(now) {
+			to = time.Time{} // still resting: no upper bound
+		}
+		trades, err := pe.trades.FetchTradesWindow(ctx, tokenID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("paper.CompareFills: fetch trades for %s: %w", tokenID, err)
+		}
+		sort.Slice(trades, func(i, j int) bool {
+			return trades[i].Timestamp.Before(trades[j].Timestamp)
+		})
+		tradesByToken[tokenID] = trades
+	}
+
+	out := make([]domain.PaperFillComparison, 0, len(orders))
+	for _, o := range orders {
+		trades := tradesByToken[o.TokenID]
+		filled, at := replayFill(o, trades, orderEnd(o, now), o.FillShare(pe.cfg.SizeDominantFraction))
+		predicted := o.FilledSize
+		if o.FilledAt != nil {
+			predicted = o.Size // MarkPaperOrderFilled leaves filled_size at the last partial
+		}
+		out = append(out, domain.PaperFillComparison{
+			Order:             o,
+			PredictedFilled:   predicted,
+			PredictedFillTime: o.FilledAt,
+			ActualFilled:      filled,
+			ActualFillTime:    at,
+			Trades:            len(trades),
+		})
+	}
+	return out, nil
+}
+
+// orderEnd is when order stopped resting in the book: ClosedAt if the engine
+// expired it, now otherwise. A filled order keeps going so the replay can
+// find a fill the simulation recorded too early.
+func orderEnd(o domain.VirtualOrder, now time.Time) time.Time {
+	if o.ClosedAt != nil {
+		return *o.ClosedAt
+	}
+	return now
+}
+
+// replayFill runs the checkFills queue model over trades (sorted by
+// timestamp) from a fresh queue: SELL volume at or below the bid between
+// PlacedAt and end first clears QueueAhead, then share of the rest fills the
+// order. Returns the USDC filled and, when the order filled completely, the
+// timestamp of the trade that completed it.
+func replayFill(o domain.VirtualOrder, trades []domain.Trade, end time.Time, share float64) (float64, *time.Time) {
+	var consumed, filled float64
+	for _, t := range trades {
+		if t.Timestamp.Before(o.PlacedAt) || !t.Timestamp.Before(end) {
+			continue
+		}
+		if t.Side != "SELL" || t.Price > o.BidPrice {
+			continue
+		}
+		consumed += t.Size * t.Price
+		filled = (consumed - o.QueueAhead) * share
+		if filled >= o.Size {
+			at := t.Timestamp
+			return o.Size, &at
+		}
+	}
+	return max(filled, 0), nil
+}
@@ -0,0 +1,39 @@
+package paper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+func TestCompareFills_ReplaysFullHistory(t *testing.T) {
+	ctx := context.Background()
+	placed := time.Now().UTC().Add(-2 * time.Hour)
+	db := newPaperStore(t)
+	savePaperOrder(t, db, placed)
+
+	// The paper loop only ever saw the first sell: $80 - $50 queue = $30.
+	t1, t2 := paperSell(placed.Add(time.Minute), 80), paperSell(placed.Add(time.Hour), 100)
+	_, err := New(nil, &windowTrades{trades: []domain.Trade{t1}}, db, Config{}).checkFills(ctx)
+	require.NoError(t, err)
+
+	rows, err := New(nil, &windowTrades{trades: []domain.Trade{t2, t1}}, db, Config{}).CompareFills(ctx, "0xc1")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	r := rows[0]
+	assert.InDelta(t, 30, r.PredictedFilled, 1e-9)
+	assert.Nil(t, r.PredictedFillTime)
+	assert.InDelta(t, 100, r.ActualFilled, 1e-9)
+	require.NotNil(t, r.ActualFillTime)
+	assert.Equal(t, t2.Timestamp, *r.ActualFillTime, "t2 clears the queue and the order")
+	assert.InDelta(t, 30, r.QueueModelAccuracyPct(), 1e-9)
+
+	rows, err = New(nil, &windowTrades{}, db, Config{}).CompareFills(ctx, "0xother")
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
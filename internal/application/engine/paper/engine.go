@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
@@ -12,27 +13,84 @@ import (
 	"github.com/alejandrodnm/polybot/internal/ports"
 )
 
+// Defaults for the tunable thresholds in Config.
 const (
-	DefaultMaxMarkets   = 10
-	maxPartialHours     = 6
-	nearEndHours        = 24
-	defaultCapital      = 1000
-	minOrderSize        = 10.0
-	maxBidTickUp        = 0.03
-	bidTickStep         = 0.01
-	mergeGasCost        = 0.02
-	mergeDelayMins      = 2
-	competitionMult     = 3.0
-	staleHours          = 4
-	blockMinutes        = 15
+	DefaultMaxMarkets    = 10
+	maxPartialHours      = 6
+	nearEndHours         = 24
+	defaultCapital       = 1000
+	minOrderSize         = 10.0
+	maxBidTickUp         = 0.03
+	bidTickStep          = 0.01
+	mergeGasCost         = 0.02
+	mergeDelayMins       = 2
+	competitionMult      = 3.0
+	staleHours           = 4
+	blockMinutes         = 15
+	tradeLookbackHours   = 24
+	sizeDominantFraction = 1.0
+	rotationCooldown     = 2 * time.Hour
 )
 
+// defaultKelly is half-Kelly between 25% and 100% of the bankroll, estimated
+// once five pairs have rotated through a merge.
+var defaultKelly = engine.KellyConfig{Multiplier: 0.5, Min: 0.25, Max: 1.0, WarmupMerges: 5}
+
 // Config holds paper trading-specific settings.
 type Config struct {
 	OrderSize      float64
 	MaxMarkets     int
 	FeeRate        float64
 	InitialCapital float64
+
+	// RebatedFeeRate is the maker fee of orders that qualify for liquidity
+	// rewards, which the program rebates or zero-rates; the rest pay FeeRate.
+	RebatedFeeRate float64
+
+	// Thresholds mirrored from the live engine so both can be tuned alike.
+	// Zero values fall back to the package defaults.
+	MinOrderSize      float64 // smallest virtual order worth placing
+	NearEndHours      float64 // no entries (and expire orders) this close to resolution
+	MaxBidTickUp      float64 // how far above the best bid optimizeBid may go
+	StaleHours        float64 // rotate pairs with no fills after this long
+	CompetitionMult   float64 // rotate when bid competition grows by this factor
+	PartialAlertHours float64 // report partials older than this
+	MergeGasCost      float64 // simulated gas cost per merge (USDC)
+
+	// RotationCooldown blocks re-entry into a market for this long after a
+	// pair there was rotated or cancelled; a near-end expiry blocks it for
+	// good.
+	RotationCooldown time.Duration
+
+	// TradeLookbackHours caps how far back checkFills pages the trade
+	// history: it starts at the oldest open order's PlacedAt, but never more
+	// than this many hours ago.
+	TradeLookbackHours float64
+
+	// SizeDominantFraction flags an order larger than this fraction of the
+	// depth already at its price level (1.0 = larger than the level). Such an
+	// order is filled by only fraction × depth / size of the sell volume past
+	// its queue. MaxLevelMultiple caps each leg at that multiple of the level
+	// depth before placement; 0 = no cap.
+	SizeDominantFraction float64
+	MaxLevelMultiple     float64
+
+	// Ladder spreads OrderSize over several pairs at lower price levels;
+	// the levels share a pairID prefix and merge together.
+	Ladder engine.LadderConfig
+
+	// Kelly tunes how kellyFraction turns the full Kelly of the merge
+	// history into the deployable fraction. Zero fields use defaultKelly.
+	Kelly engine.KellyConfig
+
+	// Markets is the market blacklist/whitelist; nil allows every market.
+	Markets engine.MarketAllower
+
+	// Health receives a ports.HealthPaperCycle beat per RunOnce; nil = none.
+	Health ports.HealthReporter
+
+	// ExpireOnExit makes Shutdown expire all open virtual orders.
+	ExpireOnExit bool
 }
 
 // Engine runs the paper trading simulation loop.
@@ -42,6 +100,9 @@ type Engine struct {
 	store    ports.PaperStorage
 	cfg      Config
 	lastScan time.Time
+
+	reloadMu   sync.Mutex
+	pendingCfg *Config // staged by Reload, applied at the next RunOnce
 }
 
 // New creates a paper trading engine.
@@ -51,12 +112,7 @@ func New(
 	store ports.PaperStorage,
 	cfg Config,
 ) *Engine {
-	if cfg.MaxMarkets <= 0 {
-		cfg.MaxMarkets = DefaultMaxMarkets
-	}
-	if cfg.InitialCapital <= 0 {
-		cfg.InitialCapital = defaultCapital
-	}
+	cfg = cfg.withDefaults()
 	return &Engine{
 		scanner:  scanner,
 		trades:   trades,
@@ -66,6 +122,48 @@ func New(
 	}
 }
 
+// withDefaults fills the zero thresholds with the package defaults.
+func (cfg Config) withDefaults() Config {
+	if cfg.MaxMarkets <= 0 {
+		cfg.MaxMarkets = DefaultMaxMarkets
+	}
+	if cfg.InitialCapital <= 0 {
+		cfg.InitialCapital = defaultCapital
+	}
+	if cfg.MinOrderSize <= 0 {
+		cfg.MinOrderSize = minOrderSize
+	}
+	if cfg.NearEndHours <= 0 {
+		cfg.NearEndHours = nearEndHours
+	}
+	if cfg.MaxBidTickUp <= 0 || cfg.MaxBidTickUp >= 1 {
+		cfg.MaxBidTickUp = maxBidTickUp
+	}
+	if cfg.StaleHours <= 0 {
+		cfg.StaleHours = staleHours
+	}
+	if cfg.CompetitionMult <= 1 {
+		cfg.CompetitionMult = competitionMult
+	}
+	if cfg.PartialAlertHours <= 0 {
+		cfg.PartialAlertHours = maxPartialHours
+	}
+	if cfg.MergeGasCost <= 0 {
+		cfg.MergeGasCost = mergeGasCost
+	}
+	if cfg.TradeLookbackHours <= 0 {
+		cfg.TradeLookbackHours = tradeLookbackHours
+	}
+	if cfg.SizeDominantFraction <= 0 {
+		cfg.SizeDominantFraction = sizeDominantFraction
+	}
+	if cfg.RotationCooldown <= 0 {
+		cfg.RotationCooldown = rotationCooldown
+	}
+	cfg.Kelly = cfg.Kelly.WithDefaults(defaultKelly)
+	return cfg
+}
+
 // CycleResult contains everything produced by one paper trading cycle.
 type CycleResult struct {
 	Positions       []domain.PaperPosition
@@ -77,6 +175,7 @@ type CycleResult struct {
 	CapitalDeployed float64
 	TotalReward     float64
 	MarketsResolved int
+	SkippedCooldown int // opportunities skipped: market rotated too recently
 	Merges          int
 	MergeProfit     float64
 	CompoundBalance float64
@@ -86,7 +185,11 @@ type CycleResult struct {
 }
 
 // RunOnce executes a single paper trading cycle.
-func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
+func (pe *Engine) RunOnce(ctx context.Context) (_ *CycleResult, err error) {
+	pe.applyReload()
+	if pe.cfg.Health != nil {
+		defer func() { pe.cfg.Health.Beat(ports.HealthPaperCycle, err) }()
+	}
 	result := &CycleResult{}
 
 	opps, err := pe.scanner.RunOnce(ctx)
@@ -137,17 +240,25 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		activeSet[c] = true
 	}
 
+	// A condition with a FILLED leg and no resting order (e.g. after a
+	// restart mid-cycle) is not in activeConditions but still holds capital.
+	conflicts, err := pe.store.GetConditionsWithOpenOrders(ctx)
+	if err != nil {
+		slog.Warn("paper: error getting per-condition order state", "err", err)
+	}
+
 	deployedOpen, deployedPartial, deployedFilled := pe.calculateDeployedCapital(ctx)
 	currentCapital := deployedOpen + deployedPartial + deployedFilled
 	result.CapitalDeployed = currentCapital
 
-	kellyF := pe.kellyFraction(ctx)
+	kellyF, fullKelly := pe.kellyFraction(ctx)
 	result.KellyFraction = kellyF
 	bankroll := pe.cfg.InitialCapital + totalMergeProfit
 	effectiveCapital := bankroll * kellyF
 
-	slog.Debug("paper: Kelly capital allocation",
+	slog.Info("paper: Kelly capital allocation",
 		"bankroll", fmt.Sprintf("$%.2f", bankroll),
+		"full_kelly", engine.FormatKelly(fullKelly),
 		"kelly_f", fmt.Sprintf("%.0f%%", kellyF*100),
 		"deployable", fmt.Sprintf("$%.2f", effectiveCapital),
 		"deployed", fmt.Sprintf("$%.2f", currentCapital),
@@ -157,6 +268,11 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		return compoundVelocityScore(opps[i]) > compoundVelocityScore(opps[j])
 	})
 
+	cooldowns, err := pe.store.GetPaperCooldowns(ctx, pe.cfg.RotationCooldown)
+	if err != nil {
+		slog.Warn("paper: error getting rotation cooldowns", "err", err)
+	}
+
 	newOrders := 0
 	for _, opp := range opps {
 		if len(activeConditions)+newOrders/2 >= pe.cfg.MaxMarkets {
@@ -165,6 +281,16 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		if activeSet[opp.Market.ConditionID] {
 			continue
 		}
+		if _, cooling := cooldowns[opp.Market.ConditionID]; cooling {
+			result.SkippedCooldown++
+			continue
+		}
+		if sides := conflicts[opp.Market.ConditionID]; sides.YesOpen || sides.NoOpen {
+			continue
+		}
+		if pe.cfg.Markets != nil && !pe.cfg.Markets.Allows(opp.Market) {
+			continue
+		}
 		if opp.FillCostPerPair > 0 {
 			continue
 		}
@@ -172,7 +298,7 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 			continue
 		}
 		hoursLeft := opp.Market.HoursToResolution()
-		if hoursLeft > 0 && hoursLeft < nearEndHours {
+		if hoursLeft > 0 && hoursLeft < pe.cfg.NearEndHours {
 			continue
 		}
 		if !opp.QualifiesReward {
@@ -184,7 +310,7 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		if orderSize > maxAffordable {
 			orderSize = maxAffordable
 		}
-		if orderSize < minOrderSize {
+		if orderSize < pe.cfg.MinOrderSize {
 			if currentCapital == 0 {
 				orderSize = maxAffordable
 			}
@@ -208,6 +334,12 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 	}
 	result.NewOrders = newOrders
 	result.CapitalDeployed = currentCapital
+	if result.SkippedCooldown > 0 {
+		slog.Info("paper: placement pipeline",
+			"placed", newOrders/2,
+			"skip_cooldown", result.SkippedCooldown,
+		)
+	}
 
 	positions, err := pe.buildPositions(ctx, oppByCondition)
 	if err != nil {
@@ -233,9 +365,9 @@ func (pe *Engine) RunOnce(ctx context.Context) (*CycleResult, error) {
 		}
 		if pos.PartialSince != nil && !pos.IsComplete && !pos.IsResolved {
 			dur := pos.PartialDuration()
-			if dur > maxPartialHours*time.Hour {
-				alert := fmt.Sprintf("PARTIAL >%dh: %s (%s filled %.0fh ago)",
-					maxPartialHours, pos.Question, partialSide(pos), dur.Hours())
+			if dur.Hours() > pe.cfg.PartialAlertHours {
+				alert := fmt.Sprintf("PARTIAL >%.0fh: %s (%s filled %.0fh ago)",
+					pe.cfg.PartialAlertHours, pos.Question, partialSide(pos), dur.Hours())
 				result.PartialAlerts = append(result.PartialAlerts, alert)
 				slog.Warn("paper: long partial fill", "market", pos.Question,
 					"side", partialSide(pos), "hours", dur.Hours())
@@ -0,0 +1,115 @@
+package paper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// fixedScanner returns the same opportunities every cycle.
+type fixedScanner []domain.Opportunity
+
+func (s fixedScanner) RunOnce(context.Context) ([]domain.Opportunity, error) { return s, nil }
+
+func paperOpp(conditionID string) domain.Opportunity {
+	book := domain.OrderBook{
+		Bids: []domain.BookEntry{{Price: 0.48, Size: 100}},
+		Asks: []domain.BookEntry{{Price: 0.50, Size: 100}},
+	}
+	return domain.Opportunity{
+		Market: domain.Market{
+			ConditionID: conditionID,
+			Question:    "Will it rain?",
+			Active:      true,
+			Tokens: [2]domain.Token{
+				{TokenID: conditionID + "_yes", Outcome: "Yes"},
+				{TokenID: conditionID + "_no", Outcome: "No"},
+			},
+		},
+		YesBook:         book,
+		NoBook:          book,
+		FillCostPerPair: -0.01,
+		YourDailyReward: 1,
+		QualifiesReward: true,
+	}
+}
+
+func TestRunOnce_SkipsConditionWithOneSidedFill(t *testing.T) {
+	ctx := context.Background()
+	db := newPaperStore(t)
+
+	// Restart after a one-sided fill: YES is FILLED, NO was expired, so the
+	// condition has no OPEN/PARTIAL order but still holds capital.
+	placed := time.Now().UTC().Add(-time.Hour)
+	for _, side := range []string{"YES", "NO"} {
+		require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+			ID: "o-" + side, ConditionID: "0xfilled", TokenID: "0xfilled_" + side, Side: side, PairID: "p1",
+			BidPrice: 0.48, Size: 10, PlacedAt: placed, Status: domain.PaperStatusOpen, Question: "Will it rain?",
+		}))
+	}
+	require.NoError(t, db.MarkPaperOrderFilled(ctx, "o-YES", placed, 0.48))
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xfilled", domain.CloseResolved))
+
+	conds, err := db.GetConditionsWithOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaperConditionSides{YesOpen: true}, conds["0xfilled"])
+
+	pe := New(fixedScanner{paperOpp("0xfilled"), paperOpp("0xfresh")}, &windowTrades{}, db, Config{OrderSize: 10})
+	result, err := pe.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewOrders, "only the fresh condition gets a pair")
+
+	conds, err = db.GetConditionsWithOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaperConditionSides{YesOpen: true, NoOpen: true}, conds["0xfresh"])
+	assert.Equal(t, domain.PaperConditionSides{YesOpen: true}, conds["0xfilled"])
+}
+
+func TestRunOnce_SkipsConditionsInRotationCooldown(t *testing.T) {
+	ctx := context.Background()
+	db := newPaperStore(t)
+
+	placed := time.Now().UTC().Add(-5 * time.Hour)
+	for _, cond := range []string{"0xstale", "0xnear"} {
+		for _, side := range []string{"YES", "NO"} {
+			require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+				ID: cond + "-" + side, ConditionID: cond, TokenID: cond + "_" + side, Side: side, PairID: cond,
+				BidPrice: 0.48, Size: 10, PlacedAt: placed, Status: domain.PaperStatusOpen, Question: "Will it rain?",
+			}))
+		}
+	}
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xstale", domain.CloseStale))
+	require.NoError(t, db.ExpirePaperOrders(ctx, "0xnear", domain.CloseNearEnd))
+
+	pe := New(fixedScanner{paperOpp("0xstale"), paperOpp("0xnear"), paperOpp("0xfresh")}, &windowTrades{}, db, Config{OrderSize: 10})
+	result, err := pe.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewOrders, "only the fresh condition gets a pair")
+	assert.Equal(t, 2, result.SkippedCooldown)
+
+	conds, err := db.GetConditionsWithOpenOrders(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, conds, "0xfresh")
+	assert.NotContains(t, conds, "0xstale")
+	assert.NotContains(t, conds, "0xnear")
+}
+
+func TestReload_AppliedOnNextCycle(t *testing.T) {
+	ctx := context.Background()
+	pe := New(fixedScanner{}, &windowTrades{}, newPaperStore(t), Config{OrderSize: 10, InitialCapital: 500})
+
+	pe.Reload(Config{OrderSize: 25, MaxMarkets: 3, InitialCapital: 9000})
+	assert.Equal(t, 10.0, pe.cfg.OrderSize, "staged until the next cycle")
+
+	_, err := pe.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, pe.cfg.OrderSize)
+	assert.Equal(t, 3, pe.cfg.MaxMarkets)
+	assert.Equal(t, 500.0, pe.cfg.InitialCapital, "the bankroll needs a restart")
+	assert.Equal(t, float64(staleHours), pe.cfg.StaleHours, "zero thresholds fall back to defaults")
+}
@@ -0,0 +1,37 @@
+package paper
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Reload stages cfg to replace the engine's thresholds on the next cycle;
+// safe to call from another goroutine (a SIGHUP handler). The bankroll, the
+// market list and the health registry are kept from the running config.
+func (pe *Engine) Reload(cfg Config) {
+	pe.reloadMu.Lock()
+	defer pe.reloadMu.Unlock()
+	pe.pendingCfg = &cfg
+}
+
+// applyReload swaps in the Config staged by Reload, if any.
+func (pe *Engine) applyReload() {
+	pe.reloadMu.Lock()
+	pending := pe.pendingCfg
+	pe.pendingCfg = nil
+	pe.reloadMu.Unlock()
+	if pending == nil {
+		return
+	}
+
+	cfg := pending.withDefaults()
+	cfg.InitialCapital = pe.cfg.InitialCapital
+	cfg.Markets = pe.cfg.Markets
+	cfg.Health = pe.cfg.Health
+
+	slog.Info("paper: config reloaded",
+		"orderSize", fmt.Sprintf("$%.2f", cfg.OrderSize),
+		"maxMarkets", cfg.MaxMarkets,
+	)
+	pe.cfg = cfg
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
@@ -48,15 +50,18 @@ func (pe *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 		age := time.Since(oldest).Hours()
 		conditionID := orders[0].ConditionID
 		rotateReason := ""
+		var closeReason domain.CloseReason
 
-		if age >= staleHours {
+		if age >= pe.cfg.StaleHours {
 			rotateReason = fmt.Sprintf("stale (%.1fh, no fills)", age)
+			closeReason = domain.CloseStale
 		}
 
 		if rotateReason == "" {
 			if opp, exists := oppByCondition[conditionID]; exists {
 				if opp.FillCostPerPair > 0 {
 					rotateReason = fmt.Sprintf("spread no longer profitable (fillCost $%.4f > 0)", opp.FillCostPerPair)
+					closeReason = domain.CloseSpread
 				}
 			}
 		}
@@ -65,9 +70,10 @@ func (pe *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 			if opp, exists := oppByCondition[conditionID]; exists {
 				currentComp := opp.YesBook.BidDepthWithinUSDC(0.05) + opp.NoBook.BidDepthWithinUSDC(0.05)
 				originalCompProxy := orders[0].QueueAhead + orders[1].QueueAhead
-				if originalCompProxy > 0 && currentComp > originalCompProxy*competitionMult {
+				if originalCompProxy > 0 && currentComp > originalCompProxy*pe.cfg.CompetitionMult {
 					rotateReason = fmt.Sprintf("competition spiked %.1fx (now $%.0f vs $%.0f at placement)",
 						currentComp/originalCompProxy, currentComp, originalCompProxy)
+					closeReason = domain.CloseCompetition
 				}
 			}
 		}
@@ -76,7 +82,7 @@ func (pe *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 			continue
 		}
 
-		if err := pe.store.ExpirePaperOrders(ctx, conditionID); err != nil {
+		if err := pe.store.ExpirePaperOrders(ctx, conditionID, closeReason); err != nil {
 			slog.Warn("paper: error expiring stale pair", "err", err)
 			continue
 		}
@@ -92,7 +98,9 @@ func (pe *Engine) rotateStaleOrders(ctx context.Context, oppByCondition map[stri
 	return expired
 }
 
-// mergeCompletePairs simulates CTF merges for fully filled pairs.
+// mergeCompletePairs simulates CTF merges for fully filled pairs. The levels
+// of a ladder are merged together: the filled legs of every level are summed,
+// so a YES filled on one level can merge with a NO filled on another.
 func (pe *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProfit float64, err error) {
 	filledOrders, err := pe.store.GetAllPaperOrders(ctx, string(domain.PaperStatusFilled))
 	if err != nil {
@@ -101,85 +109,88 @@ func (pe *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 
 	byPair := make(map[string][]domain.VirtualOrder)
 	for _, o := range filledOrders {
-		byPair[o.PairID] = append(byPair[o.PairID], o)
+		group := engine.PairGroup(o.PairID)
+		byPair[group] = append(byPair[group], o)
 	}
 
 	now := time.Now().UTC()
 	mergeDelay := time.Duration(mergeDelayMins) * time.Minute
 
 	for _, orders := range byPair {
-		var yes, no *domain.VirtualOrder
-		for i := range orders {
-			switch orders[i].Side {
+		var yes, no []domain.VirtualOrder
+		var lastFillTime, firstPlaced time.Time
+		for _, o := range orders {
+			switch o.Side {
 			case "YES":
-				yes = &orders[i]
+				yes = append(yes, o)
 			case "NO":
-				no = &orders[i]
+				no = append(no, o)
+			}
+			at := o.PlacedAt
+			if o.FilledAt != nil {
+				at = *o.FilledAt
+			}
+			if at.After(lastFillTime) {
+				lastFillTime = at
+			}
+			if firstPlaced.IsZero() || o.PlacedAt.Before(firstPlaced) {
+				firstPlaced = o.PlacedAt
 			}
 		}
-		if yes == nil || no == nil {
+		if len(yes) == 0 || len(no) == 0 {
 			continue
 		}
+		question := yes[0].Question
 
-		lastFillTime := yes.PlacedAt
-		if yes.FilledAt != nil && yes.FilledAt.After(lastFillTime) {
-			lastFillTime = *yes.FilledAt
-		}
-		if no.FilledAt != nil && no.FilledAt.After(lastFillTime) {
-			lastFillTime = *no.FilledAt
-		}
 		if now.Sub(lastFillTime) < mergeDelay {
 			slog.Debug("paper: merge delayed (waiting for confirmation)",
-				"market", engine.TruncateStr(yes.Question, 30),
+				"market", engine.TruncateStr(question, 30),
 				"waitRemaining", fmt.Sprintf("%.0fs", mergeDelay.Seconds()-now.Sub(lastFillTime).Seconds()),
 			)
 			continue
 		}
 
-		yesPrice := yes.FilledPrice
-		if yesPrice == 0 {
-			yesPrice = yes.BidPrice
-		}
-		noPrice := no.FilledPrice
-		if noPrice == 0 {
-			noPrice = no.BidPrice
-		}
+		yesShares, yesPrice := filledShares(yes)
+		noShares, noPrice := filledShares(no)
 
-		spread := 1.0 - yesPrice - noPrice
-		yesShares := yes.Size / yesPrice
-		noShares := no.Size / noPrice
+		// Spread net of the maker fee both legs paid on their fills.
+		spread := -domain.FillCostPerEvent(yesPrice, noPrice, pe.orderFeeRate(yes[0]))
 		mergeable := min(yesShares, noShares)
 		grossProfit := mergeable * spread
 
-		netProfit := grossProfit - mergeGasCost
+		netProfit := grossProfit - pe.cfg.MergeGasCost
 		if netProfit <= 0 {
 			slog.Info("paper: skipping merge (unprofitable after gas)",
-				"market", engine.TruncateStr(yes.Question, 30),
+				"market", engine.TruncateStr(question, 30),
 				"spread", fmt.Sprintf("$%.4f", spread),
 				"grossProfit", fmt.Sprintf("$%.4f", grossProfit),
-				"gasCost", fmt.Sprintf("$%.4f", mergeGasCost),
+				"gasCost", fmt.Sprintf("$%.4f", pe.cfg.MergeGasCost),
 			)
 			continue
 		}
 
-		if err := pe.store.MarkPaperOrderMerged(ctx, yes.ID, now); err != nil {
-			slog.Warn("paper: error marking YES as merged", "err", err)
-			continue
+		marked := true
+		for _, o := range orders {
+			if err := pe.store.MarkPaperOrderMerged(ctx, o.ID, now); err != nil {
+				slog.Warn("paper: error marking "+o.Side+" as merged", "err", err)
+				marked = false
+				break
+			}
 		}
-		if err := pe.store.MarkPaperOrderMerged(ctx, no.ID, now); err != nil {
-			slog.Warn("paper: error marking NO as merged", "err", err)
+		if !marked {
 			continue
 		}
 
-		cycleTime := now.Sub(yes.PlacedAt)
+		cycleTime := now.Sub(firstPlaced)
 		capitalUsed := mergeable * (yesPrice + noPrice)
 		slog.Info("paper: MERGED pair (compound rotation)",
-			"market", engine.TruncateStr(yes.Question, 30),
+			"market", engine.TruncateStr(question, 30),
 			"spread", fmt.Sprintf("$%.4f", spread),
 			"shares", fmt.Sprintf("%.1f", mergeable),
+			"levels", max(len(yes), len(no)),
 			"grossProfit", fmt.Sprintf("$%.4f", grossProfit),
 			"netProfit", fmt.Sprintf("$%.4f", netProfit),
-			"gasCost", fmt.Sprintf("$%.4f", mergeGasCost),
+			"gasCost", fmt.Sprintf("$%.4f", pe.cfg.MergeGasCost),
 			"capital_used", fmt.Sprintf("$%.2f", capitalUsed),
 			"cycle", fmt.Sprintf("%.1fh", cycleTime.Hours()),
 		)
@@ -191,6 +202,40 @@ func (pe *Engine) mergeCompletePairs(ctx context.Context) (merges int, totalProf
 	return merges, totalProfit, nil
 }
 
+// makerFeeRate is the fee a virtual bid pays per fill: the rebated rate
+// when its market qualifies for rewards, FeeRate otherwise.
+func (pe *Engine) makerFeeRate(qualifies bool) float64 {
+	if qualifies {
+		return pe.cfg.RebatedFeeRate
+	}
+	return pe.cfg.FeeRate
+}
+
+// orderFeeRate is the maker fee of a placed order. An order only earns a
+// DailyReward when its market qualified at placement, so that is the
+// rebated rate; the rest pay FeeRate.
+func (pe *Engine) orderFeeRate(o domain.VirtualOrder) float64 {
+	return pe.makerFeeRate(o.DailyReward > 0)
+}
+
+// filledShares sums the shares bought by the legs of one side and returns
+// them with their average price (the bid when no fill price was recorded).
+func filledShares(legs []domain.VirtualOrder) (shares, avgPrice float64) {
+	var cost float64
+	for _, o := range legs {
+		price := o.FilledPrice
+		if price == 0 {
+			price = o.BidPrice
+		}
+		shares += o.Size / price
+		cost += o.Size
+	}
+	if shares > 0 {
+		avgPrice = cost / shares
+	}
+	return shares, avgPrice
+}
+
 // getCompoundMetrics computes the compound balance and rotation stats from MERGED orders.
 func (pe *Engine) getCompoundMetrics(ctx context.Context) (balance, totalProfit float64, rotations int, avgCycleHours float64) {
 	mergedOrders, err := pe.store.GetAllPaperOrders(ctx, string(domain.PaperStatusMerged))
@@ -198,48 +243,46 @@ func (pe *Engine) getCompoundMetrics(ctx context.Context) (balance, totalProfit
 		return pe.cfg.InitialCapital, 0, 0, 0
 	}
 
-	byPair := make(map[string][]domain.VirtualOrder)
+	// One merge marks every leg it took with the same MergedAt; ladder levels
+	// merged together share the pair group.
+	byMerge := make(map[string][]domain.VirtualOrder)
 	for _, o := range mergedOrders {
-		byPair[o.PairID] = append(byPair[o.PairID], o)
+		key := engine.PairGroup(o.PairID)
+		if o.MergedAt != nil {
+			key += "|" + o.MergedAt.UTC().Format(time.RFC3339Nano)
+		}
+		byMerge[key] = append(byMerge[key], o)
 	}
 
 	var totalCycleHours float64
 
-	for _, orders := range byPair {
-		var yes, no *domain.VirtualOrder
-		for i := range orders {
-			switch orders[i].Side {
+	for _, orders := range byMerge {
+		var yes, no []domain.VirtualOrder
+		for _, o := range orders {
+			switch o.Side {
 			case "YES":
-				yes = &orders[i]
+				yes = append(yes, o)
 			case "NO":
-				no = &orders[i]
+				no = append(no, o)
 			}
 		}
-		if yes == nil || no == nil {
+		if len(yes) == 0 || len(no) == 0 {
 			continue
 		}
 
-		yesPrice := yes.FilledPrice
-		if yesPrice == 0 {
-			yesPrice = yes.BidPrice
-		}
-		noPrice := no.FilledPrice
-		if noPrice == 0 {
-			noPrice = no.BidPrice
-		}
+		yesShares, yesPrice := filledShares(yes)
+		noShares, noPrice := filledShares(no)
 
-		spread := 1.0 - yesPrice - noPrice
-		yesShares := yes.Size / yesPrice
-		noShares := no.Size / noPrice
+		spread := -domain.FillCostPerEvent(yesPrice, noPrice, pe.orderFeeRate(yes[0]))
 		mergeable := min(yesShares, noShares)
 		grossProfit := mergeable * spread
-		netProfit := grossProfit - mergeGasCost
+		netProfit := grossProfit - pe.cfg.MergeGasCost
 
 		totalProfit += netProfit
 		rotations++
 
-		if yes.MergedAt != nil {
-			totalCycleHours += yes.MergedAt.Sub(yes.PlacedAt).Hours()
+		if yes[0].MergedAt != nil {
+			totalCycleHours += yes[0].MergedAt.Sub(yes[0].PlacedAt).Hours()
 		}
 	}
 
@@ -273,22 +316,26 @@ func (pe *Engine) calculateDeployedCapital(ctx context.Context) (deployedOpen, d
 	return
 }
 
-// kellyFraction computes the optimal fraction of bankroll to deploy using Kelly Criterion.
-func (pe *Engine) kellyFraction(ctx context.Context) float64 {
+// kellyFraction computes the optimal fraction of bankroll to deploy using
+// Kelly Criterion. It returns the fraction to deploy, cfg.Kelly applied to
+// the full Kelly, and the full Kelly itself (NaN when the fraction comes
+// from the fill-rate fallback instead).
+func (pe *Engine) kellyFraction(ctx context.Context) (applied, full float64) {
+	k := pe.cfg.Kelly
 	stats, err := pe.store.GetPaperStats(ctx)
 	if err != nil || stats.TotalOrders < 50 {
-		return 1.0
+		return k.Clamp(1.0), math.NaN()
 	}
 
 	totalPairsAttempted := stats.TotalOrders / 2
 	if totalPairsAttempted == 0 {
-		return 1.0
+		return k.Clamp(1.0), math.NaN()
 	}
 
 	completed := stats.CompletePairs + stats.TotalRotations
 	p := float64(completed) / float64(totalPairsAttempted)
 
-	if stats.TotalRotations >= 5 && stats.TotalMergeProfit > 0 {
+	if stats.TotalRotations >= k.WarmupMerges && stats.TotalMergeProfit > 0 {
 		avgProfitPerRotation := stats.TotalMergeProfit / float64(stats.TotalRotations)
 		avgCapitalPerPair := 2 * pe.cfg.OrderSize
 
@@ -297,35 +344,28 @@ func (pe *Engine) kellyFraction(ctx context.Context) float64 {
 
 		if b > 0 {
 			kelly := (p*b - q) / b
-			halfKelly := kelly / 2.0
-
-			if halfKelly < 0.25 {
-				halfKelly = 0.25
-			}
-			if halfKelly > 1.0 {
-				halfKelly = 1.0
-			}
+			frac := k.Fraction(kelly)
 
 			slog.Debug("paper: Kelly computed from merge data",
 				"p", fmt.Sprintf("%.2f", p),
 				"b", fmt.Sprintf("%.4f", b),
 				"fullKelly", fmt.Sprintf("%.2f", kelly),
-				"halfKelly", fmt.Sprintf("%.2f", halfKelly),
+				"applied", fmt.Sprintf("%.2f", frac),
 			)
-			return halfKelly
+			return frac, kelly
 		}
 	}
 
 	fillRate := float64(stats.TotalFills) / float64(max(stats.DaysRunning, 1))
 	switch {
 	case fillRate > 4:
-		return 1.0
+		return k.Clamp(1.0), math.NaN()
 	case fillRate > 2:
-		return 0.9
+		return k.Clamp(0.9), math.NaN()
 	case fillRate > 1:
-		return 0.8
+		return k.Clamp(0.8), math.NaN()
 	default:
-		return 0.7
+		return k.Clamp(0.7), math.NaN()
 	}
 }
 
@@ -336,14 +376,19 @@ func (pe *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 		return nil, err
 	}
 
+	// The levels of a ladder make one position; a side counts as filled once
+	// every level on it has filled.
 	byPair := make(map[string][]domain.VirtualOrder)
 	for _, o := range allOrders {
-		byPair[o.PairID] = append(byPair[o.PairID], o)
+		group := engine.PairGroup(o.PairID)
+		byPair[group] = append(byPair[group], o)
 	}
 
 	var positions []domain.PaperPosition
 	for pairID, orders := range byPair {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].PairID < orders[j].PairID })
 		pos := domain.PaperPosition{PairID: pairID}
+		yesFilled, noFilled := true, true
 
 		for i := range orders {
 			o := &orders[i]
@@ -352,15 +397,23 @@ func (pe *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 				pos.Question = o.Question
 			}
 
+			filled := o.Status == domain.PaperStatusFilled || o.Status == domain.PaperStatusMerged
+			pos.SizeDominant = pos.SizeDominant || o.SizeDominant
 			switch o.Side {
 			case "YES":
-				pos.YesOrder = o
-				pos.YesFilled = o.Status == domain.PaperStatusFilled || o.Status == domain.PaperStatusMerged
+				if pos.YesOrder == nil {
+					pos.YesOrder = o
+				}
+				yesFilled = yesFilled && filled
 			case "NO":
-				pos.NoOrder = o
-				pos.NoFilled = o.Status == domain.PaperStatusFilled || o.Status == domain.PaperStatusMerged
+				if pos.NoOrder == nil {
+					pos.NoOrder = o
+				}
+				noFilled = noFilled && filled
 			}
 		}
+		pos.YesFilled = yesFilled && pos.YesOrder != nil
+		pos.NoFilled = noFilled && pos.NoOrder != nil
 
 		pos.IsComplete = pos.YesFilled && pos.NoFilled
 		pos.IsResolved = allResolved(orders)
@@ -377,8 +430,10 @@ func (pe *Engine) buildPositions(ctx context.Context, oppByCondition map[string]
 
 		if pos.YesOrder != nil && pos.NoOrder != nil {
 			pos.FillCostPair = domain.FillCostPerEvent(
-				pos.YesOrder.BidPrice, pos.NoOrder.BidPrice, pe.cfg.FeeRate)
-			pos.CapitalDeployed = pos.YesOrder.Size + pos.NoOrder.Size
+				pos.YesOrder.BidPrice, pos.NoOrder.BidPrice, pe.orderFeeRate(*pos.YesOrder))
+			for _, o := range orders {
+				pos.CapitalDeployed += o.Size
+			}
 		}
 
 		if pos.YesOrder != nil && pos.YesOrder.DailyReward > 0 {
@@ -555,8 +610,8 @@ func (pe *Engine) optimalOrderSize(opp domain.Opportunity) float64 {
 	if optimal > pe.cfg.OrderSize*2 {
 		optimal = pe.cfg.OrderSize * 2
 	}
-	if optimal < minOrderSize {
-		optimal = minOrderSize
+	if optimal < pe.cfg.MinOrderSize {
+		optimal = pe.cfg.MinOrderSize
 	}
 
 	if optimal != pe.cfg.OrderSize && (optimal < pe.cfg.OrderSize*0.8 || optimal > pe.cfg.OrderSize*1.2) {
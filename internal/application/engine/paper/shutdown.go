@@ -0,0 +1,29 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// Shutdown expires every OPEN/PARTIAL virtual order when Config.ExpireOnExit
+// is set, so the next run starts from clean paper state. Callers must pass a
+// fresh context: the run loop's one is already cancelled.
+func (pe *Engine) Shutdown(ctx context.Context) error {
+	if !pe.cfg.ExpireOnExit {
+		return nil
+	}
+	conditions, err := pe.store.GetActivePaperConditions(ctx)
+	if err != nil {
+		return fmt.Errorf("paper.Shutdown: %w", err)
+	}
+	for _, conditionID := range conditions {
+		if err := pe.store.ExpirePaperOrders(ctx, conditionID, domain.CloseShutdown); err != nil {
+			return fmt.Errorf("paper.Shutdown: %w", err)
+		}
+	}
+	slog.Info("paper: shutdown, expired open orders", "markets", len(conditions))
+	return nil
+}
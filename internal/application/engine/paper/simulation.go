@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"sort"
 	"time"
 
@@ -17,27 +18,23 @@ func (pe *Engine) placeVirtualOrders(ctx context.Context, opp domain.Opportunity
 	return pe.placeVirtualOrdersWithSize(ctx, opp, pe.cfg.OrderSize)
 }
 
-// placeVirtualOrdersWithSize creates a YES+NO order pair with multi-tick bid optimization.
+// placeVirtualOrdersWithSize creates a YES+NO order pair with multi-tick bid
+// optimization, or one pair per level when Config.Ladder has several.
 func (pe *Engine) placeVirtualOrdersWithSize(ctx context.Context, opp domain.Opportunity, orderSize float64) error {
 	pairID := uuid.New().String()
 	now := time.Now().UTC()
 
-	yesBid := opp.YesBook.BestBid()
-	noBid := opp.NoBook.BestBid()
-	if yesBid == 0 {
-		yesBid = opp.YesBook.BestAsk() * 0.99
-	}
-	if noBid == 0 {
-		noBid = opp.NoBook.BestAsk() * 0.99
-	}
+	yesBid := engine.StartBid(opp.YesBook, orderSize)
+	noBid := engine.StartBid(opp.NoBook, orderSize)
 
 	yesQueue := engine.QueuePosition(opp.YesBook, yesBid)
 	noQueue := engine.QueuePosition(opp.NoBook, noBid)
 
-	yesBidOpt, yesQueueOpt := pe.optimizeBid(opp.YesBook, yesBid, noBid, orderSize, pe.cfg.FeeRate, true)
-	noBidOpt, noQueueOpt := pe.optimizeBid(opp.NoBook, noBid, yesBidOpt, orderSize, pe.cfg.FeeRate, false)
+	feeRate := pe.makerFeeRate(opp.QualifiesReward)
+	yesBidOpt, yesQueueOpt := pe.optimizeBid(opp.YesBook, yesBid, noBid, orderSize, feeRate, true)
+	noBidOpt, noQueueOpt := pe.optimizeBid(opp.NoBook, noBid, yesBidOpt, orderSize, feeRate, false)
 
-	if domain.FillCostPerEvent(yesBidOpt, noBidOpt, pe.cfg.FeeRate) > 0 {
+	if domain.FillCostPerEvent(yesBidOpt, noBidOpt, feeRate) > 0 {
 		yesBidOpt = yesBid
 		noBidOpt = noBid
 		yesQueueOpt = yesQueue
@@ -46,45 +43,75 @@ func (pe *Engine) placeVirtualOrdersWithSize(ctx context.Context, opp domain.Opp
 
 	optimized := yesBidOpt != yesBid || noBidOpt != noBid
 
+	// optimizeBid ranks candidates by same-price queue; the stored queue also
+	// counts every higher bid, which fills before ours when we sit below the top.
+	yesQueueOpt = engine.QueueAhead(opp.YesBook, yesBidOpt)
+	noQueueOpt = engine.QueueAhead(opp.NoBook, noBidOpt)
+
 	bidCompetition := opp.YesBook.BidDepthWithinUSDC(0.05) + opp.NoBook.BidDepthWithinUSDC(0.05)
 
-	yesOrder := domain.VirtualOrder{
-		ID:          uuid.New().String(),
-		ConditionID: opp.Market.ConditionID,
-		TokenID:     opp.Market.YesToken().TokenID,
-		Side:        "YES",
-		BidPrice:    yesBidOpt,
-		Size:        orderSize,
-		PlacedAt:    now,
-		Status:      domain.PaperStatusOpen,
-		PairID:      pairID,
-		Question:    opp.Market.Question,
-		QueueAhead:  yesQueueOpt,
-		DailyReward: opp.YourDailyReward,
-		EndDate:     opp.Market.EndDate,
-	}
+	levels := pe.cfg.Ladder.Levels()
+	placed := 0
+	for _, lvl := range levels {
+		levelYes, levelNo := yesBidOpt, noBidOpt
+		if lvl.Offset > 0 {
+			levelYes = math.Round((yesBidOpt-lvl.Offset)*100) / 100
+			levelNo = math.Round((noBidOpt-lvl.Offset)*100) / 100
+		}
+		if levelYes <= 0.01 || levelNo <= 0.01 {
+			break
+		}
+		levelPairID := engine.LadderPairID(pairID, lvl.Index, len(levels))
+		yesDepth := engine.QueuePosition(opp.YesBook, levelYes)
+		noDepth := engine.QueuePosition(opp.NoBook, levelNo)
+		size := pe.capToLevelDepth(orderSize*lvl.Fraction, yesDepth, noDepth)
+		if size < 1 {
+			continue
+		}
 
-	noOrder := domain.VirtualOrder{
-		ID:          uuid.New().String(),
-		ConditionID: opp.Market.ConditionID,
-		TokenID:     opp.Market.NoToken().TokenID,
-		Side:        "NO",
-		BidPrice:    noBidOpt,
-		Size:        orderSize,
-		PlacedAt:    now,
-		Status:      domain.PaperStatusOpen,
-		PairID:      pairID,
-		Question:    opp.Market.Question,
-		QueueAhead:  noQueueOpt,
-		DailyReward: opp.YourDailyReward,
-		EndDate:     opp.Market.EndDate,
-	}
+		yesOrder := domain.VirtualOrder{
+			ID:          uuid.New().String(),
+			ConditionID: opp.Market.ConditionID,
+			TokenID:     opp.Market.YesToken().TokenID,
+			Side:        "YES",
+			BidPrice:    levelYes,
+			Size:        size,
+			PlacedAt:    now,
+			Status:      domain.PaperStatusOpen,
+			PairID:      levelPairID,
+			Question:    opp.Market.Question,
+			QueueAhead:  engine.QueueAhead(opp.YesBook, levelYes),
+			DailyReward: opp.YourDailyReward,
+			EndDate:     opp.Market.EndDate,
+			LevelDepth:  yesDepth,
+		}
+		yesOrder.SizeDominant = domain.IsSizeDominant(size, yesDepth, pe.cfg.SizeDominantFraction)
+
+		noOrder := domain.VirtualOrder{
+			ID:          uuid.New().String(),
+			ConditionID: opp.Market.ConditionID,
+			TokenID:     opp.Market.NoToken().TokenID,
+			Side:        "NO",
+			BidPrice:    levelNo,
+			Size:        size,
+			PlacedAt:    now,
+			Status:      domain.PaperStatusOpen,
+			PairID:      levelPairID,
+			Question:    opp.Market.Question,
+			QueueAhead:  engine.QueueAhead(opp.NoBook, levelNo),
+			DailyReward: opp.YourDailyReward,
+			EndDate:     opp.Market.EndDate,
+			LevelDepth:  noDepth,
+		}
+		noOrder.SizeDominant = domain.IsSizeDominant(size, noDepth, pe.cfg.SizeDominantFraction)
 
-	if err := pe.store.SavePaperOrder(ctx, yesOrder); err != nil {
-		return err
-	}
-	if err := pe.store.SavePaperOrder(ctx, noOrder); err != nil {
-		return err
+		if err := pe.store.SavePaperOrder(ctx, yesOrder); err != nil {
+			return err
+		}
+		if err := pe.store.SavePaperOrder(ctx, noOrder); err != nil {
+			return err
+		}
+		placed++
 	}
 
 	optLabel := ""
@@ -96,6 +123,9 @@ func (pe *Engine) placeVirtualOrdersWithSize(ctx context.Context, opp domain.Opp
 	if orderSize != pe.cfg.OrderSize {
 		sizeLabel = fmt.Sprintf(" [ADAPTIVE $%.0f]", orderSize)
 	}
+	if placed > 1 {
+		sizeLabel += fmt.Sprintf(" [LADDER %d levels]", placed)
+	}
 	slog.Info("paper: placed virtual orders"+optLabel+sizeLabel,
 		"market", engine.TruncateStr(opp.Market.Question, 40),
 		"yesBid", fmt.Sprintf("%.4f", yesBidOpt),
@@ -111,6 +141,21 @@ func (pe *Engine) placeVirtualOrdersWithSize(ctx context.Context, opp domain.Opp
 	return nil
 }
 
+// capToLevelDepth caps a leg size at MaxLevelMultiple times the depth already
+// resting at each side's price, so an order never dwarfs the level it joins.
+// Empty levels and MaxLevelMultiple 0 leave it as is.
+func (pe *Engine) capToLevelDepth(size, yesDepth, noDepth float64) float64 {
+	if pe.cfg.MaxLevelMultiple <= 0 {
+		return size
+	}
+	for _, depth := range []float64{yesDepth, noDepth} {
+		if depth > 0 {
+			size = math.Min(size, pe.cfg.MaxLevelMultiple*depth)
+		}
+	}
+	return size
+}
+
 // optimizeBid tries tick-ups on a bid and picks the best one.
 func (pe *Engine) optimizeBid(
 	book domain.OrderBook,
@@ -126,7 +171,7 @@ func (pe *Engine) optimizeBid(
 
 	bestScore := bidOptScore(bestQueue, orderSize, 0.0)
 
-	for ticks := 1; float64(ticks)*bidTickStep <= maxBidTickUp; ticks++ {
+	for ticks := 1; float64(ticks)*bidTickStep <= pe.cfg.MaxBidTickUp; ticks++ {
 		candidate := currentBid + float64(ticks)*bidTickStep
 
 		var fillCost float64
@@ -178,6 +223,7 @@ func (pe *Engine) expireResolvedAndNearEnd(ctx context.Context, oppByCondition m
 
 		shouldExpire := false
 		reason := ""
+		closeReason := domain.CloseResolved
 
 		if !order.EndDate.IsZero() && time.Now().After(order.EndDate) {
 			shouldExpire = true
@@ -193,9 +239,10 @@ func (pe *Engine) expireResolvedAndNearEnd(ctx context.Context, oppByCondition m
 
 		if !order.EndDate.IsZero() {
 			hoursLeft := time.Until(order.EndDate).Hours()
-			if hoursLeft > 0 && hoursLeft < nearEndHours {
+			if hoursLeft > 0 && hoursLeft < pe.cfg.NearEndHours {
 				shouldExpire = true
 				reason = fmt.Sprintf("NEAR END (%.0fh left)", hoursLeft)
+				closeReason = domain.CloseNearEnd
 			}
 		}
 
@@ -206,7 +253,7 @@ func (pe *Engine) expireResolvedAndNearEnd(ctx context.Context, oppByCondition m
 				"market", engine.TruncateStr(order.Question, 30),
 				"conditionID", order.ConditionID[:14]+"...",
 			)
-			if err := pe.store.ExpirePaperOrders(ctx, order.ConditionID); err != nil {
+			if err := pe.store.ExpirePaperOrders(ctx, order.ConditionID, closeReason); err != nil {
 				slog.Warn("paper: error expiring orders", "err", err)
 			}
 			resolved++
@@ -217,6 +264,9 @@ func (pe *Engine) expireResolvedAndNearEnd(ctx context.Context, oppByCondition m
 }
 
 // refreshQueues updates queueAhead for OPEN orders using current book data.
+// Orders that already have sell volume counted keep their queue: the book
+// level no longer shows what was consumed, so refreshing would reset our
+// place in line (notably after a restart).
 func (pe *Engine) refreshQueues(ctx context.Context, oppByCondition map[string]domain.Opportunity) {
 	openOrders, err := pe.store.GetOpenPaperOrders(ctx)
 	if err != nil {
@@ -224,7 +274,7 @@ func (pe *Engine) refreshQueues(ctx context.Context, oppByCondition map[string]d
 	}
 
 	for _, order := range openOrders {
-		if order.Status == domain.PaperStatusPartial {
+		if order.Status == domain.PaperStatusPartial || order.QueueConsumed > 0 {
 			continue
 		}
 
@@ -240,7 +290,7 @@ func (pe *Engine) refreshQueues(ctx context.Context, oppByCondition map[string]d
 			book = opp.NoBook
 		}
 
-		newQueue := engine.QueuePosition(book, order.BidPrice)
+		newQueue := engine.QueueAhead(book, order.BidPrice)
 		if err := pe.store.UpdatePaperOrderQueue(ctx, order.ID, newQueue); err != nil {
 			slog.Debug("paper: error updating queue", "err", err)
 		}
@@ -263,9 +313,11 @@ func (pe *Engine) checkFills(ctx context.Context) (int, error) {
 		byToken[o.TokenID] = append(byToken[o.TokenID], o)
 	}
 
+	now := time.Now().UTC()
 	totalFills := 0
 	for tokenID, orders := range byToken {
-		trades, err := pe.trades.FetchTrades(ctx, tokenID)
+		since := pe.tradesSince(orders, now)
+		trades, err := pe.trades.FetchTradesSince(ctx, tokenID, since)
 		if err != nil {
 			slog.Warn("paper: error fetching trades for fill check",
 				"token", tokenID[:min(8, len(tokenID))]+"...", "err", err)
@@ -279,6 +331,7 @@ func (pe *Engine) checkFills(ctx context.Context) (int, error) {
 					"token", tokenID[:min(8, len(tokenID))]+"...",
 					"trades", len(trades),
 					"coverage", fmt.Sprintf("%.0fm", window.Minutes()),
+					"wanted", fmt.Sprintf("%.0fm", now.Sub(since).Minutes()),
 				)
 			}
 		}
@@ -288,22 +341,15 @@ func (pe *Engine) checkFills(ctx context.Context) (int, error) {
 		})
 
 		for _, order := range orders {
-			var cumSellUSDC float64
-			var lastSellTrade *domain.Trade
-
-			for i := range trades {
-				t := &trades[i]
-				if t.Timestamp.Before(order.PlacedAt) {
-					continue
-				}
-				if t.Side != "SELL" || t.Price > order.BidPrice {
-					continue
+			cumSellUSDC, checkedAt, lastSellTrade := consumeQueue(order, trades)
+			if lastSellTrade != nil {
+				if err := pe.store.UpdatePaperOrderQueueConsumed(ctx, order.ID, cumSellUSDC, checkedAt); err != nil {
+					slog.Warn("paper: error saving queue progress", "err", err)
 				}
-				cumSellUSDC += t.Size * t.Price
-				lastSellTrade = t
 			}
 
-			effectiveFilled := cumSellUSDC - order.QueueAhead
+			// A size-dominant order only gets its share of the flow past the queue.
+			effectiveFilled := (cumSellUSDC - order.QueueAhead) * order.FillShare(pe.cfg.SizeDominantFraction)
 			if effectiveFilled <= 0 {
 				if cumSellUSDC > 0 {
 					slog.Debug("paper: sell volume hasn't reached us yet",
@@ -387,6 +433,48 @@ func (pe *Engine) checkFills(ctx context.Context) (int, error) {
 	return totalFills, nil
 }
 
+// tradesSince is where the trade history for orders must start: the oldest
+// PlacedAt, capped at TradeLookbackHours before now. Older trades cannot
+// move a queue that did not exist yet.
+func (pe *Engine) tradesSince(orders []domain.VirtualOrder, now time.Time) time.Time {
+	floor := now.Add(-time.Duration(pe.cfg.TradeLookbackHours * float64(time.Hour)))
+	since := now
+	for _, o := range orders {
+		if o.PlacedAt.Before(since) {
+			since = o.PlacedAt
+		}
+	}
+	if since.Before(floor) {
+		return floor
+	}
+	return since
+}
+
+// consumeQueue adds to order.QueueConsumed the SELL volume at or below the
+// bid not counted yet: trades after QueueCheckedAt, or since PlacedAt on the
+// first pass. trades must be sorted by timestamp. Returns the new total, the
+// timestamp of the last trade counted and that trade (nil if none was new).
+func consumeQueue(order domain.VirtualOrder, trades []domain.Trade) (float64, time.Time, *domain.Trade) {
+	consumed, checkedAt := order.QueueConsumed, order.QueueCheckedAt
+	var last *domain.Trade
+	for i := range trades {
+		t := &trades[i]
+		if t.Timestamp.Before(order.PlacedAt) {
+			continue
+		}
+		if !order.QueueCheckedAt.IsZero() && !t.Timestamp.After(order.QueueCheckedAt) {
+			continue
+		}
+		if t.Side != "SELL" || t.Price > order.BidPrice {
+			continue
+		}
+		consumed += t.Size * t.Price
+		checkedAt = t.Timestamp
+		last = t
+	}
+	return consumed, checkedAt, last
+}
+
 func tradeID(t *domain.Trade) string {
 	if t == nil {
 		return ""
@@ -0,0 +1,272 @@
+package paper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/polymarket"
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// windowTrades mimics the trades API: it only returns the trades it holds now.
+type windowTrades struct {
+	trades []domain.Trade
+}
+
+func (w *windowTrades) FetchTrades(context.Context, string) ([]domain.Trade, error) {
+	return w.trades, nil
+}
+
+func (w *windowTrades) FetchTradesSince(context.Context, string, time.Time) ([]domain.Trade, error) {
+	return w.trades, nil
+}
+
+func (w *windowTrades) FetchTradesWindow(context.Context, string, time.Time, time.Time) ([]domain.Trade, error) {
+	return w.trades, nil
+}
+
+func newPaperStore(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(context.Background()))
+	return db
+}
+
+func savePaperOrder(t *testing.T, db *storage.SQLiteStorage, placedAt time.Time) {
+	t.Helper()
+	require.NoError(t, db.SavePaperOrder(context.Background(), domain.VirtualOrder{
+		ID: "o1", ConditionID: "0xc1", TokenID: "tok_yes", Side: "YES", PairID: "p1",
+		BidPrice: 0.5, Size: 100, QueueAhead: 50, PlacedAt: placedAt,
+		Status: domain.PaperStatusOpen, Question: "Will it rain?",
+	}))
+}
+
+func paperSell(at time.Time, usdc float64) domain.Trade {
+	return domain.Trade{ID: at.Format(time.TimeOnly), TokenID: "tok_yes", Side: "SELL", Price: 0.5, Size: usdc / 0.5, Timestamp: at}
+}
+
+func filledSize(t *testing.T, db *storage.SQLiteStorage) float64 {
+	t.Helper()
+	orders, err := db.GetPaperOrdersByPair(context.Background(), "p1")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	return orders[0].FilledSize
+}
+
+func TestCheckFills_RestartInvariant(t *testing.T) {
+	ctx := context.Background()
+	placed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	t1 := paperSell(placed.Add(time.Minute), 30)
+	t2 := paperSell(placed.Add(2*time.Minute), 50)
+	t3 := paperSell(placed.Add(3*time.Minute), 30)
+
+	// Reference: a single engine sees every trade in one pass.
+	ref := newPaperStore(t)
+	savePaperOrder(t, ref, placed)
+	_, err := New(nil, &windowTrades{trades: []domain.Trade{t1, t2, t3}}, ref, Config{}).checkFills(ctx)
+	require.NoError(t, err)
+	require.InDelta(t, 60, filledSize(t, ref), 1e-9, "$110 sold - $50 queue")
+
+	// Restarted: the first process sees t1 only; after the restart t1 has
+	// left the trades window and the book shows a much deeper level.
+	db := newPaperStore(t)
+	savePaperOrder(t, db, placed)
+	_, err = New(nil, &windowTrades{trades: []domain.Trade{t1}}, db, Config{}).checkFills(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, filledSize(t, db), "$30 sold has not reached us yet")
+
+	restarted := New(nil, &windowTrades{trades: []domain.Trade{t2, t3}}, db, Config{})
+	book := domain.OrderBook{Bids: []domain.BookEntry{{Price: 0.5, Size: 2000}}}
+	restarted.refreshQueues(ctx, map[string]domain.Opportunity{"0xc1": {YesBook: book, NoBook: book}})
+	_, err = restarted.checkFills(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, filledSize(t, ref), filledSize(t, db), 1e-9)
+
+	// Seeing the same trades again adds nothing.
+	_, err = restarted.checkFills(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 60, filledSize(t, db), 1e-9)
+}
+
+func TestCheckFills_PagesTradeHistory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// Data API: $1 sells every 6s, newest first, in full pages of 1000. A
+	// trade arriving between requests shifts the offsets, so page 2 starts
+	// with the last trade of page 1.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		require.LessOrEqual(t, offset, 2000, "page 3 already reaches past the oldest order")
+		start := offset - offset/1000
+		page := make([]map[string]any, 0, 1000)
+		for i := start; i < start+1000; i++ {
+			page = append(page, map[string]any{
+				"id": fmt.Sprintf("t%d", i), "asset": "tok_yes", "side": "SELL",
+				"price": 0.5, "size": 2, "timestamp": now.Add(-time.Duration(i) * 6 * time.Second).Unix(),
+			})
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+	client := polymarket.NewClient("", "")
+	client.SetDataAPIBase(srv.URL)
+
+	// Placed 4h ago: trades t0..t2399 are after it, $2400 of sell volume.
+	db := newPaperStore(t)
+	require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+		ID: "o1", ConditionID: "0xc1", TokenID: "tok_yes", Side: "YES", PairID: "p1",
+		BidPrice: 0.5, Size: 500, QueueAhead: 2000, PlacedAt: now.Add(-4*time.Hour + 3*time.Second),
+		Status: domain.PaperStatusOpen, Question: "Will it rain?",
+	}))
+
+	_, err := New(nil, client, db, Config{}).checkFills(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 400, filledSize(t, db), 1e-9, "$2400 sold over three pages - $2000 queue, duplicates counted once")
+}
+
+func TestRefreshQueues_CountsHigherBids(t *testing.T) {
+	ctx := context.Background()
+	db := newPaperStore(t)
+	savePaperOrder(t, db, time.Now().UTC())
+	pe := New(nil, &windowTrades{}, db, Config{})
+
+	book := domain.OrderBook{Bids: []domain.BookEntry{
+		{Price: 0.51, Size: 100},
+		{Price: 0.50, Size: 100},
+		{Price: 0.49, Size: 100},
+	}}
+	pe.refreshQueues(ctx, map[string]domain.Opportunity{"0xc1": {YesBook: book}})
+
+	orders, err := db.GetPaperOrdersByPair(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.InDelta(t, 101, orders[0].QueueAhead, 1e-9, "$51 above plus $50 at our price")
+}
+
+func TestMergeCompletePairs_SumsLadderLevels(t *testing.T) {
+	ctx := context.Background()
+	db := newPaperStore(t)
+	pe := New(nil, &windowTrades{}, db, Config{MergeGasCost: 0.02})
+
+	// YES filled on level 0 and NO on level 1 merge together; the resting
+	// legs of each level stay open.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, o := range []domain.VirtualOrder{
+		{ID: "l0-yes", PairID: "p/L0", Side: "YES", BidPrice: 0.45, Size: 45, FilledSize: 45, Status: domain.PaperStatusFilled, FilledAt: &filledAt},
+		{ID: "l0-no", PairID: "p/L0", Side: "NO", BidPrice: 0.45, Size: 45, Status: domain.PaperStatusOpen},
+		{ID: "l1-yes", PairID: "p/L1", Side: "YES", BidPrice: 0.44, Size: 44, Status: domain.PaperStatusOpen},
+		{ID: "l1-no", PairID: "p/L1", Side: "NO", BidPrice: 0.44, Size: 44, FilledSize: 44, Status: domain.PaperStatusFilled, FilledAt: &filledAt},
+	} {
+		o.ConditionID, o.TokenID, o.PlacedAt = "0xc1", "tok_"+o.Side, filledAt
+		require.NoError(t, db.SavePaperOrder(ctx, o))
+	}
+
+	merges, profit, err := pe.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, merges)
+	assert.InDelta(t, 100*(1-0.45-0.44)-0.02, profit, 1e-9)
+
+	merged, err := db.GetAllPaperOrders(ctx, string(domain.PaperStatusMerged))
+	require.NoError(t, err)
+	assert.Len(t, merged, 2)
+	open, err := db.GetOpenPaperOrders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, open, 2)
+}
+
+func TestMergeCompletePairs_FeeByRewardQualification(t *testing.T) {
+	ctx := context.Background()
+	db := newPaperStore(t)
+	pe := New(nil, &windowTrades{}, db, Config{MergeGasCost: 0.02, FeeRate: 0.02, RebatedFeeRate: 0})
+
+	// An order placed while its market qualified for rewards carries the
+	// DailyReward and pays the rebated fee; the other pays FeeRate.
+	filledAt := time.Now().UTC().Add(-time.Hour)
+	for _, pair := range []struct {
+		id     string
+		reward float64
+	}{{"qualifies", 1}, {"standard", 0}} {
+		for _, side := range []string{"YES", "NO"} {
+			require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+				ID: pair.id + "-" + side, ConditionID: "0x" + pair.id, TokenID: pair.id + "_" + side, Side: side,
+				PairID: pair.id, BidPrice: 0.45, Size: 45, FilledSize: 45, DailyReward: pair.reward,
+				PlacedAt: filledAt, FilledAt: &filledAt, Status: domain.PaperStatusFilled,
+			}))
+		}
+	}
+
+	merges, profit, err := pe.mergeCompletePairs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, merges)
+	qualifying := 100*(1-0.90) - 0.02
+	standard := 100*(1-0.90*1.02) - 0.02
+	assert.InDelta(t, qualifying+standard, profit, 1e-9)
+}
+
+func TestCheckFills_SizeDominantGetsItsShare(t *testing.T) {
+	ctx := context.Background()
+	placed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db := newPaperStore(t)
+	// $100 joining a level with $40 resting: dominant, 40% of the flow reaches it.
+	require.NoError(t, db.SavePaperOrder(ctx, domain.VirtualOrder{
+		ID: "o1", ConditionID: "0xc1", TokenID: "tok_yes", Side: "YES", PairID: "p1",
+		BidPrice: 0.5, Size: 100, QueueAhead: 50, PlacedAt: placed,
+		Status: domain.PaperStatusOpen, LevelDepth: 40, SizeDominant: true,
+	}))
+
+	trades := &windowTrades{trades: []domain.Trade{paperSell(placed.Add(time.Minute), 110)}}
+	_, err := New(nil, trades, db, Config{}).checkFills(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 24, filledSize(t, db), 1e-9, "($110 sold - $50 queue) × 0.4")
+}
+
+func TestPlaceVirtualOrders_CapsAndFlagsSizeDominant(t *testing.T) {
+	ctx := context.Background()
+	book := domain.OrderBook{
+		Bids: []domain.BookEntry{{Price: 0.45, Size: 100}}, // $45 at our level
+		Asks: []domain.BookEntry{{Price: 0.50, Size: 100}},
+	}
+	opp := domain.Opportunity{
+		Market: domain.Market{ConditionID: "0xc1", Question: "Will it rain?", Tokens: [2]domain.Token{
+			{TokenID: "tok_yes", Outcome: "Yes"}, {TokenID: "tok_no", Outcome: "No"},
+		}},
+		YesBook: book, NoBook: book, YourDailyReward: 1,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		multiple float64
+		size     float64
+	}{
+		{"no cap", 0, 100},
+		{"capped at 2x the level", 2, 90},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newPaperStore(t)
+			pe := New(nil, &windowTrades{}, db, Config{MaxLevelMultiple: tc.multiple})
+			require.NoError(t, pe.placeVirtualOrdersWithSize(ctx, opp, 100))
+
+			orders, err := db.GetOpenPaperOrders(ctx)
+			require.NoError(t, err)
+			require.Len(t, orders, 2)
+			for _, o := range orders {
+				assert.InDelta(t, tc.size, o.Size, 1e-9)
+				assert.InDelta(t, 45, o.LevelDepth, 1e-9)
+				assert.True(t, o.SizeDominant)
+			}
+		})
+	}
+}
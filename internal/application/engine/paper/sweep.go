@@ -0,0 +1,117 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// SweepVariant is one paper config of a parameter sweep. Each variant needs
+// its own store (e.g. a separate SQLite file) so their orders and stats do
+// not mix.
+type SweepVariant struct {
+	Name   string
+	Config Config
+	Store  ports.PaperStorage
+}
+
+// Sweep runs several paper engines side by side (--sweep) to compare config
+// variants without running each for a week. Every cycle scans once and feeds
+// the same opportunities to all variants, which then run concurrently.
+type Sweep struct {
+	scanner  engine.ScannerService
+	names    []string
+	engines  []*Engine
+	scanners []*cycleScanner
+}
+
+// NewSweep creates a sweep over variants. trades is shared by all engines.
+func NewSweep(scanner engine.ScannerService, trades ports.TradeProvider, variants []SweepVariant) *Sweep {
+	sw := &Sweep{scanner: scanner}
+	for _, v := range variants {
+		cs := &cycleScanner{}
+		sw.names = append(sw.names, v.Name)
+		sw.scanners = append(sw.scanners, cs)
+		sw.engines = append(sw.engines, New(cs, trades, v.Store, v.Config))
+	}
+	return sw
+}
+
+// RunOnce scans once and runs one cycle of every variant. Results are in
+// variant order; a variant whose cycle failed has a nil result and its
+// error joined into the returned error.
+func (sw *Sweep) RunOnce(ctx context.Context) ([]*CycleResult, error) {
+	opps, err := sw.scanner.RunOnce(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("paper.Sweep: scan: %w", err)
+	}
+
+	results := make([]*CycleResult, len(sw.engines))
+	errs := make([]error, len(sw.engines))
+	var wg sync.WaitGroup
+	for i, pe := range sw.engines {
+		// RunOnce sorts the opportunities in place: each variant gets a copy.
+		sw.scanners[i].opps = slices.Clone(opps)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := pe.RunOnce(ctx)
+			if err != nil {
+				errs[i] = fmt.Errorf("variant %s: %w", sw.names[i], err)
+			}
+			results[i] = res
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return results, fmt.Errorf("paper.Sweep: %w", err)
+	}
+	return results, nil
+}
+
+// Report returns every variant's stats for the side-by-side comparison, in
+// variant order.
+func (sw *Sweep) Report(ctx context.Context) ([]domain.SweepResult, error) {
+	out := make([]domain.SweepResult, 0, len(sw.engines))
+	for i, pe := range sw.engines {
+		stats, err := pe.store.GetPaperStats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("paper.Sweep.Report: variant %s: %w", sw.names[i], err)
+		}
+		out = append(out, domain.SweepResult{
+			Name:       sw.names[i],
+			OrderSize:  pe.cfg.OrderSize,
+			MaxMarkets: pe.cfg.MaxMarkets,
+			StaleHours: pe.cfg.StaleHours,
+			Stats:      stats,
+		})
+	}
+	return out, nil
+}
+
+// Shutdown shuts every variant down (see Engine.Shutdown).
+func (sw *Sweep) Shutdown(ctx context.Context) error {
+	errs := make([]error, len(sw.engines))
+	for i, pe := range sw.engines {
+		if err := pe.Shutdown(ctx); err != nil {
+			errs[i] = fmt.Errorf("variant %s: %w", sw.names[i], err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cycleScanner hands a variant the opportunities of the sweep's scan.
+type cycleScanner struct {
+	opps []domain.Opportunity
+}
+
+func (cs *cycleScanner) RunOnce(context.Context) ([]domain.Opportunity, error) {
+	return cs.opps, nil
+}
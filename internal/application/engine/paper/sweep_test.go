@@ -0,0 +1,51 @@
+package paper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// countingScanner counts the scans it was asked for.
+type countingScanner struct {
+	fixedScanner
+	scans int
+}
+
+func (s *countingScanner) RunOnce(ctx context.Context) ([]domain.Opportunity, error) {
+	s.scans++
+	return s.fixedScanner.RunOnce(ctx)
+}
+
+func TestSweep_FeedsOneScanToEveryVariant(t *testing.T) {
+	ctx := context.Background()
+	scan := &countingScanner{fixedScanner: fixedScanner{paperOpp("0xa"), paperOpp("0xb"), paperOpp("0xc")}}
+	narrow, wide := newPaperStore(t), newPaperStore(t)
+
+	sw := NewSweep(scan, &windowTrades{}, []SweepVariant{
+		{Name: "narrow", Config: Config{OrderSize: 10, MaxMarkets: 1}, Store: narrow},
+		{Name: "wide", Config: Config{OrderSize: 25, MaxMarkets: 3}, Store: wide},
+	})
+	results, err := sw.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, scan.scans, "variants share the cycle's scan")
+	require.Len(t, results, 2)
+	assert.Equal(t, 2, results[0].NewOrders)
+	assert.Equal(t, 6, results[1].NewOrders)
+
+	conds, err := narrow.GetActivePaperConditions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, conds, 1, "each variant writes only to its own store")
+
+	report, err := sw.Report(ctx)
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+	assert.Equal(t, "wide", report[1].Name)
+	assert.Equal(t, 25.0, report[1].OrderSize)
+	assert.Equal(t, 3, report[1].MaxMarkets)
+	assert.Equal(t, 6, report[1].Stats.TotalOrders)
+}
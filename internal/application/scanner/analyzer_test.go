@@ -149,3 +149,84 @@ func TestFilter_Apply_Basic(t *testing.T) {
 	result := f.Apply([]domain.Opportunity{passing, lowScore, noQualify})
 	require.Len(t, result, 1)
 }
+
+func TestFilterByKeywords(t *testing.T) {
+	election := domain.Opportunity{Market: domain.Market{Question: "Will the US Election be held in November?"}}
+	sports := domain.Opportunity{Market: domain.Market{Question: "Will the Lakers win the NBA title?"}}
+
+	tests := []struct {
+		name       string
+		include    []string
+		exclude    []string
+		wantElect  bool
+		wantSports bool
+	}{
+		{name: "empty lists pass everything", wantElect: true, wantSports: true},
+		{name: "include only", include: []string{"election"}, wantElect: true, wantSports: false},
+		{name: "exclude only", exclude: []string{"NBA"}, wantElect: true, wantSports: false},
+		{name: "exclude wins over include", include: []string{"will"}, exclude: []string{"us election"}, wantElect: false, wantSports: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := FilterConfig{IncludeKeywords: tt.include, ExcludeKeywords: tt.exclude}
+			assert.Equal(t, tt.wantElect, filterByKeywords(election, f))
+			assert.Equal(t, tt.wantSports, filterByKeywords(sports, f))
+		})
+	}
+}
+
+func TestFilter_Apply_ExcludeKeywordsIgnoresScore(t *testing.T) {
+	cfg := DefaultFilterConfig()
+	cfg.RequireQualifies = false
+	cfg.ExcludeKeywords = []string{"nfl"}
+	f := NewFilter(cfg)
+
+	best := domain.Opportunity{Market: domain.Market{Question: "NFL: Chiefs vs Bills"}, YourDailyReward: 100}
+	other := domain.Opportunity{Market: domain.Market{Question: "Fed rate cut in March?"}, YourDailyReward: 0.1}
+
+	result := f.Apply([]domain.Opportunity{best, other})
+	require.Len(t, result, 1)
+	assert.Equal(t, "Fed rate cut in March?", result[0].Market.Question)
+}
+
+func TestFilter_Apply_SpreadOverrides(t *testing.T) {
+	cfg := DefaultFilterConfig()
+	cfg.MaxSpreadTotal = 0.10
+	cfg.SpreadOverrides = map[string]float64{
+		"sports":   0.03,
+		"nba-":     0.05,
+		"politics": 0.08,
+	}
+	f := NewFilter(cfg)
+
+	opp := func(category, slug string, spread float64) domain.Opportunity {
+		return domain.Opportunity{
+			Market:          domain.Market{Category: category, Slug: slug},
+			SpreadTotal:     spread,
+			QualifiesReward: true,
+		}
+	}
+	tests := []struct {
+		name string
+		opp  domain.Opportunity
+		want bool
+	}{
+		{"category override rejects", opp("Sports", "nfl-chiefs-bills", 0.04), false},
+		{"longer slug prefix wins over category", opp("Sports", "nba-lakers-celtics", 0.04), true},
+		{"slug prefix still caps", opp("Sports", "nba-lakers-celtics", 0.06), false},
+		{"no override falls back to global", opp("Crypto", "btc-100k", 0.09), true},
+		{"global still rejects", opp("Crypto", "btc-100k", 0.11), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, len(f.Apply([]domain.Opportunity{tt.opp})) == 1)
+		})
+	}
+
+	// Un override más laxo que el MaxSpread del reward hace que el mercado califique.
+	loose := opp("Politics", "us-election", 0.07)
+	loose.QualifiesReward = false
+	result := f.Apply([]domain.Opportunity{loose})
+	require.Len(t, result, 1)
+	assert.True(t, result[0].QualifiesReward)
+}
@@ -0,0 +1,284 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+const (
+	defaultBacktestMarkets = 10
+	// Multiplicador de cola del modelo si BacktestConfig.QueueMult es 0
+	// (live.queue_conservative_mult por defecto).
+	defaultBacktestQueueMult = 1.5
+)
+
+// BacktestConfig define la ventana histórica a reproducir.
+type BacktestConfig struct {
+	From       time.Time
+	To         time.Time // cero = ahora
+	OrderSize  float64   // USDC por lado
+	MaxMarkets int       // top N oportunidades actuales a reproducir (0 = 10)
+	QueueMult  float64   // multiplicador de cola del modelo a contrastar (0 = 1.5)
+	// Rewards, si no es nil, da los payouts reales con los que se contrasta
+	// el reward estimado (ver CompareActualRewards).
+	Rewards ports.RewardsSource
+}
+
+// Backtest reproduce los trades históricos de la ventana [From, To) sobre las
+// mejores oportunidades actuales. Los books históricos no están disponibles,
+// así que el precio de nuestro bid y la cola delante se toman del book actual:
+// el resultado mide cuánto flujo vendedor habría llegado a ese nivel cada día.
+func (s *Scanner) Backtest(ctx context.Context, trades ports.TradeProvider, cfg BacktestConfig) (*domain.BacktestResult, error) {
+	if cfg.To.IsZero() {
+		cfg.To = time.Now().UTC()
+	}
+	if !cfg.To.After(cfg.From) {
+		return nil, fmt.Errorf("scanner.Backtest: invalid window %s → %s", cfg.From.Format(time.DateOnly), cfg.To.Format(time.DateOnly))
+	}
+	if cfg.MaxMarkets <= 0 {
+		cfg.MaxMarkets = defaultBacktestMarkets
+	}
+
+	opps, err := s.cycle(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("scanner.Backtest: %w", err)
+	}
+	if len(opps) > cfg.MaxMarkets {
+		opps = opps[:cfg.MaxMarkets]
+	}
+
+	byToken := make(map[string][]domain.Trade, 2*len(opps))
+	for _, opp := range opps {
+		for _, tok := range []string{opp.Market.YesToken().TokenID, opp.Market.NoToken().TokenID} {
+			t, err := trades.FetchTradesWindow(ctx, tok, cfg.From, cfg.To)
+			if err != nil {
+				slog.Warn("backtest: error fetching trades", "market", engine.TruncateStr(opp.Market.Question, 30), "err", err)
+				continue
+			}
+			byToken[tok] = t
+		}
+	}
+
+	result := SimulateBacktest(opps, byToken, cfg)
+	if cfg.Rewards != nil {
+		CompareActualRewards(ctx, cfg.Rewards, &result, time.Now().UTC())
+	}
+	return &result, nil
+}
+
+// CompareActualRewards consulta el payout real de cada día ya cerrado de la
+// ventana y lo compara con el reward que el backtest estimó para los mismos
+// mercados. El endpoint de earnings solo reporta lo cobrado por nuestra
+// wallet, así que la comparación tiene sentido para mercados donde se cotizó
+// de verdad. Los días que fallan se omiten de ambos lados.
+func CompareActualRewards(ctx context.Context, src ports.RewardsSource, result *domain.BacktestResult, now time.Time) {
+	byCond := make(map[string]*domain.BacktestMarket, len(result.Markets))
+	for i := range result.Markets {
+		byCond[result.Markets[i].ConditionID] = &result.Markets[i]
+	}
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	for _, d := range result.Days {
+		if !d.Date.Before(today) {
+			continue // el payout del día aún no se ha distribuido
+		}
+		earnings, err := src.EarningsForDay(ctx, d.Date)
+		if err != nil {
+			slog.Warn("backtest: error fetching rewards", "date", d.Date.Format(time.DateOnly), "err", err)
+			continue
+		}
+		result.RewardDays++
+		for _, bm := range byCond {
+			bm.EstimatedReward += bm.DailyReward[d.Date]
+		}
+		for _, e := range earnings {
+			if bm, ok := byCond[e.ConditionID]; ok {
+				bm.ActualReward += e.Earnings
+			}
+		}
+	}
+
+	result.EstimatedReward, result.ActualReward = 0, 0
+	for _, bm := range result.Markets {
+		result.EstimatedReward += bm.EstimatedReward
+		result.ActualReward += bm.ActualReward
+	}
+}
+
+// SimulateBacktest reparte los trades de cada mercado por día UTC y simula
+// los fills de un par de bids (YES+NO) en el mejor bid actual. Cada día se
+// asume que las órdenes se recolocan al final de la cola de su nivel. Un
+// mercado que se resuelve dentro de la ventana deja de acumular fills y
+// reward en su fecha de resolución.
+//
+// Además contrasta el modelo de cola de los engines con la reproducción: los
+// fills/día predichos son orderSize/(orderSize + cola×QueueMult) por el
+// volumen vendedor diario a nuestro bid, en órdenes de orderSize.
+func SimulateBacktest(opps []domain.Opportunity, trades map[string][]domain.Trade, cfg BacktestConfig) domain.BacktestResult {
+	result := domain.BacktestResult{From: cfg.From, To: cfg.To, OrderSize: cfg.OrderSize}
+	queueMult := cfg.QueueMult
+	if queueMult <= 0 {
+		queueMult = defaultBacktestQueueMult
+	}
+	days := make(map[time.Time]*domain.BacktestDay)
+
+	for _, opp := range opps {
+		m := opp.Market
+		yesTok, noTok := m.YesToken().TokenID, m.NoToken().TokenID
+		yesBid, noBid := backtestBid(opp.YesBook), backtestBid(opp.NoBook)
+		yesQueue := engine.QueuePosition(opp.YesBook, yesBid)
+		noQueue := engine.QueuePosition(opp.NoBook, noBid)
+
+		fillCost := domain.FillCostUSDC(cfg.OrderSize, yesBid, noBid, opp.FillCostPerPair)
+		dailyReward := domain.EstimateYourDailyReward(cfg.OrderSize, opp.Competition,
+			m.Rewards.DailyRate, opp.SpreadTotal, m.Rewards.MaxSpread)
+
+		bm := domain.BacktestMarket{
+			ConditionID: m.ConditionID,
+			Question:    m.Question,
+			Trades:      len(trades[yesTok]) + len(trades[noTok]),
+			DailyReward: make(map[time.Time]float64),
+		}
+
+		end := cfg.To
+		if !m.EndDate.IsZero() && m.EndDate.After(cfg.From) && m.EndDate.Before(cfg.To) {
+			resolved := m.EndDate
+			bm.ResolvedAt = &resolved
+			end = resolved
+		}
+
+		for day := cfg.From.UTC().Truncate(24 * time.Hour); day.Before(end); day = day.Add(24 * time.Hour) {
+			start := maxTime(day, cfg.From)
+			stop := minTime(day.Add(24*time.Hour), end)
+			if !stop.After(start) {
+				continue
+			}
+
+			yesFills := simulateDayFills(trades[yesTok], yesBid, yesQueue, cfg.OrderSize, start, stop)
+			noFills := simulateDayFills(trades[noTok], noBid, noQueue, cfg.OrderSize, start, stop)
+			reward := dailyReward * stop.Sub(start).Hours() / 24
+			cost := float64(max(yesFills, noFills)) * fillCost
+
+			d, ok := days[day]
+			if !ok {
+				d = &domain.BacktestDay{Date: day}
+				days[day] = d
+			}
+			d.Markets++
+			d.FillsYES += yesFills
+			d.FillsNO += noFills
+			d.CompletePairs += min(yesFills, noFills)
+			d.Reward += reward
+			d.FillCost += cost
+			d.PnL += reward - cost
+
+			bm.FillsYES += yesFills
+			bm.FillsNO += noFills
+			bm.PnL += reward - cost
+			bm.DailyReward[day] += reward
+		}
+
+		if activeDays := end.Sub(cfg.From).Hours() / 24; activeDays > 0 {
+			bm.FillsBothPerDay = float64(bm.FillsYES+bm.FillsNO) / activeDays
+			bm.PredictedFillsPerDay = (predictedFills(trades[yesTok], yesBid, yesQueue*queueMult, cfg.OrderSize, cfg.From, end) +
+				predictedFills(trades[noTok], noBid, noQueue*queueMult, cfg.OrderSize, cfg.From, end)) / activeDays
+			bm.PredictionError = domain.QueueModelError(bm.PredictedFillsPerDay, bm.FillsBothPerDay)
+			result.PredictedFillsPerDay += bm.PredictedFillsPerDay
+			result.FillsBothPerDay += bm.FillsBothPerDay
+		}
+
+		result.Markets = append(result.Markets, bm)
+	}
+
+	result.OverallQueueModelError = domain.QueueModelError(result.PredictedFillsPerDay, result.FillsBothPerDay)
+	addBacktestDays(&result, days)
+	return result
+}
+
+// addBacktestDays añade los días al resultado en orden cronológico y suma los totales.
+func addBacktestDays(result *domain.BacktestResult, days map[time.Time]*domain.BacktestDay) {
+	for _, d := range days {
+		result.Days = append(result.Days, *d)
+		result.TotalFills += d.Fills()
+		result.CompletePairs += d.CompletePairs
+		result.TotalReward += d.Reward
+		result.TotalFillCost += d.FillCost
+		result.NetPnL += d.PnL
+	}
+	sort.Slice(result.Days, func(i, j int) bool {
+		return result.Days[i].Date.Before(result.Days[j].Date)
+	})
+}
+
+// predictedFills es lo que el modelo de cola espera de [start, stop): la
+// probabilidad de fill orderSize/(orderSize + queue) por las órdenes de
+// orderSize que caben en el volumen vendedor a precio ≤ bid.
+func predictedFills(trades []domain.Trade, bid, queue, orderSize float64, start, stop time.Time) float64 {
+	if bid <= 0 || orderSize <= 0 {
+		return 0
+	}
+	sellUSDC := sellVolume(trades, bid, start, stop)
+	return orderSize / (orderSize + queue) * sellUSDC / orderSize
+}
+
+// sellVolume suma los USDC de los SELL agresivos a precio ≤ bid dentro de
+// [start, stop).
+func sellVolume(trades []domain.Trade, bid float64, start, stop time.Time) float64 {
+	var sellUSDC float64
+	for _, t := range trades {
+		if t.Timestamp.Before(start) || !t.Timestamp.Before(stop) {
+			continue
+		}
+		if t.Side != "SELL" || t.Price > bid {
+			continue
+		}
+		sellUSDC += t.Size * t.Price
+	}
+	return sellUSDC
+}
+
+// simulateDayFills cuenta cuántas veces se habría llenado una orden de
+// orderSize USDC en bid, con queue USDC delante, usando los SELL agresivos
+// a precio ≤ bid dentro de [start, stop). Un fill parcial cuenta como fill
+// (estimación conservadora del coste).
+func simulateDayFills(trades []domain.Trade, bid, queue, orderSize float64, start, stop time.Time) int {
+	if bid <= 0 || orderSize <= 0 {
+		return 0
+	}
+	reached := sellVolume(trades, bid, start, stop) - queue
+	if reached <= 0 {
+		return 0
+	}
+	return int(math.Ceil(reached / orderSize))
+}
+
+// backtestBid es el precio al que se colocaría el bid: el mejor bid actual,
+// o un tick bajo el ask si el lado de compra está vacío.
+func backtestBid(book domain.OrderBook) float64 {
+	if bid := book.BestBid(); bid > 0 {
+		return bid
+	}
+	return book.BestAsk() * 0.99
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
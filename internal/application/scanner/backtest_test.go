@@ -0,0 +1,138 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sellTrade(tokenID string, price, size float64, at time.Time) domain.Trade {
+	return domain.Trade{TokenID: tokenID, Side: "SELL", Price: price, Size: size, Timestamp: at}
+}
+
+func TestSimulateBacktest_DailyBreakdownStopsAtResolution(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * 24 * time.Hour)
+
+	market := makeMarket("0xc1", "yes1", "no1", 50, 0.05)
+	market.EndDate = from.Add(36 * time.Hour) // resolves mid-day 2
+	books := makeBooks("yes1", "no1")
+	opp := domain.Opportunity{
+		Market:          market,
+		YesBook:         books["yes1"],
+		NoBook:          books["no1"],
+		SpreadTotal:     0.01,
+		FillCostPerPair: -0.01,
+		Competition:     1000,
+	}
+
+	// Queue ahead on YES at 0.70 = $105; $210 of sells leaves $105 for us → 2 fills (partial counts).
+	trades := map[string][]domain.Trade{
+		"yes1": {
+			sellTrade("yes1", 0.70, 300, from.Add(2*time.Hour)),
+			sellTrade("yes1", 0.75, 300, from.Add(3*time.Hour)),   // above our bid: ignored
+			sellTrade("yes1", 0.70, 1000, from.Add(48*time.Hour)), // after resolution
+		},
+		"no1": {
+			sellTrade("no1", 0.27, 500, from.Add(30*time.Hour)),
+		},
+	}
+
+	res := scanner.SimulateBacktest([]domain.Opportunity{opp}, trades, scanner.BacktestConfig{
+		From: from, To: to, OrderSize: 100,
+	})
+
+	require.Len(t, res.Days, 2, "no days after resolution")
+	assert.Equal(t, 2, res.Days[0].FillsYES)
+	assert.Equal(t, 0, res.Days[0].FillsNO)
+	assert.Equal(t, 0, res.Days[1].FillsYES)
+	assert.Equal(t, 2, res.Days[1].FillsNO)
+	assert.Equal(t, 0, res.CompletePairs)
+
+	require.Len(t, res.Markets, 1)
+	require.NotNil(t, res.Markets[0].ResolvedAt)
+	assert.Equal(t, 4, res.TotalFills)
+	assert.Greater(t, res.Days[0].Reward, res.Days[1].Reward, "day 2 only accrues until resolution")
+	assert.InDelta(t, res.TotalReward-res.TotalFillCost, res.NetPnL, 1e-9)
+}
+
+func TestSimulateBacktest_QueueModelError(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2 * 24 * time.Hour)
+
+	market := makeMarket("0xc1", "yes1", "no1", 50, 0.05)
+	books := makeBooks("yes1", "no1")
+	opp := domain.Opportunity{Market: market, YesBook: books["yes1"], NoBook: books["no1"], Competition: 1000}
+	trades := map[string][]domain.Trade{
+		"yes1": {sellTrade("yes1", 0.70, 300, from.Add(2*time.Hour))},
+	}
+
+	// $210 sold behind a $105 queue: the replay gets 2 fills in 2 days, the
+	// model 100/(100+105) × $210 / $100 ≈ 1.02.
+	res := scanner.SimulateBacktest([]domain.Opportunity{opp}, trades, scanner.BacktestConfig{
+		From: from, To: to, OrderSize: 100, QueueMult: 1,
+	})
+
+	require.Len(t, res.Markets, 1)
+	m := res.Markets[0]
+	assert.InDelta(t, 1.0, m.FillsBothPerDay, 1e-9)
+	assert.InDelta(t, 210.0/205/2, m.PredictedFillsPerDay, 1e-9)
+	assert.InDelta(t, 210.0/205/2-1, m.PredictionError, 1e-9)
+	assert.InDelta(t, m.PredictionError, res.OverallQueueModelError, 1e-9)
+
+	conservative := scanner.SimulateBacktest([]domain.Opportunity{opp}, trades, scanner.BacktestConfig{
+		From: from, To: to, OrderSize: 100,
+	})
+	assert.Less(t, conservative.PredictedFillsPerDay, res.PredictedFillsPerDay, "default 1.5× queue predicts fewer fills")
+}
+
+type stubRewards struct {
+	byDay map[time.Time][]domain.LiveReward
+	calls int
+}
+
+func (s *stubRewards) EarningsForDay(_ context.Context, date time.Time) ([]domain.LiveReward, error) {
+	s.calls++
+	return s.byDay[date], nil
+}
+
+func TestCompareActualRewards_OnlyPaidDays(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * 24 * time.Hour)
+
+	market := makeMarket("0xc1", "yes1", "no1", 50, 0.05)
+	books := makeBooks("yes1", "no1")
+	opp := domain.Opportunity{
+		Market:      market,
+		YesBook:     books["yes1"],
+		NoBook:      books["no1"],
+		SpreadTotal: 0.01,
+		Competition: 1000,
+	}
+	res := scanner.SimulateBacktest([]domain.Opportunity{opp}, nil, scanner.BacktestConfig{
+		From: from, To: to, OrderSize: 100,
+	})
+	require.Len(t, res.Days, 3)
+
+	day2 := from.Add(24 * time.Hour)
+	src := &stubRewards{byDay: map[time.Time][]domain.LiveReward{
+		from: {{Date: from, ConditionID: "0xc1", Earnings: 0.5}, {Date: from, ConditionID: "0xother", Earnings: 9}},
+		day2: {{Date: day2, ConditionID: "0xc1", Earnings: 0.25}},
+	}}
+
+	// The third day is still "today": its payout is not out yet.
+	scanner.CompareActualRewards(context.Background(), src, &res, from.Add(50*time.Hour))
+
+	assert.Equal(t, 2, src.calls)
+	assert.Equal(t, 2, res.RewardDays)
+	assert.InDelta(t, 0.75, res.ActualReward, 1e-9, "other markets' earnings are ignored")
+	assert.InDelta(t, res.Days[0].Reward+res.Days[1].Reward, res.EstimatedReward, 1e-9)
+	assert.InDelta(t, res.ActualReward-res.EstimatedReward, res.RewardDiscrepancy(), 1e-9)
+	require.Len(t, res.Markets, 1)
+	assert.InDelta(t, 0.75, res.Markets[0].ActualReward, 1e-9)
+}
@@ -0,0 +1,240 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+const (
+	calibrationWindow     = 30 * 24 * time.Hour
+	minCalibrationSamples = 10
+	// QueueAhead se guarda ya multiplicado por el multiplicador vigente al
+	// colocar; se asume el de por defecto (live.queue_conservative_mult).
+	calibrationStoredQueueMult = 1.5
+	// Pares mínimos de un mercado para medir la variación de su spread
+	// (spreadStabilityWindow del live engine).
+	calibrationMinPairs = 3
+)
+
+// CalibrateLiveConstants ajusta queue_conservative_mult y spread_variance_max
+// con las órdenes live de los últimos 30 días. No aplica nada: devuelve los
+// valores sugeridos.
+//
+// Cola: una orden llenada consumió toda la cola delante más su propio tamaño.
+// Se ajusta por mínimos cuadrados horas = (α·QueueAhead + β·Size) sobre las
+// órdenes BUY llenadas; α/β dice cuánto pesa de verdad cada USDC de cola
+// guardada frente a uno propio, y el multiplicador óptimo es el guardado por
+// esa proporción.
+//
+// Varianza: cada par colocado es una muestra del spread (YES bid + NO bid) de
+// su mercado. Por mercado con al menos 3 pares se calcula el coeficiente de
+// variación y si algún par se quedó con una sola pata llena; el umbral
+// sugerido es el que mejor separa los mercados limpios de los cojos.
+func CalibrateLiveConstants(ctx context.Context, store ports.LiveStorage) (domain.LiveCalibration, error) {
+	return calibrateLive(ctx, store, time.Now().UTC())
+}
+
+func calibrateLive(ctx context.Context, store ports.LiveStorage, now time.Time) (domain.LiveCalibration, error) {
+	since := now.Add(-calibrationWindow)
+	var orders []domain.LiveOrder
+	for _, st := range []domain.LiveOrderStatus{
+		domain.LiveStatusFilled, domain.LiveStatusMerged, domain.LiveStatusPartial,
+		domain.LiveStatusOpen, domain.LiveStatusCancelled, domain.LiveStatusExpired,
+		domain.LiveStatusFlattened,
+	} {
+		got, err := store.GetAllLiveOrders(ctx, string(st))
+		if err != nil {
+			return domain.LiveCalibration{}, fmt.Errorf("scanner.CalibrateLiveConstants: %w", err)
+		}
+		for _, o := range got {
+			if !o.IsSell() && !o.PlacedAt.Before(since) {
+				orders = append(orders, o)
+			}
+		}
+	}
+
+	model, err := fitFillRate(orders)
+	if err != nil {
+		return domain.LiveCalibration{}, fmt.Errorf("scanner.CalibrateLiveConstants: %w", err)
+	}
+	varianceMax, markets := fitVarianceMax(orders)
+	return domain.LiveCalibration{
+		OptimalQueueMult:   calibrationStoredQueueMult * model.QueueScale,
+		OptimalVarianceMax: varianceMax,
+		FillRateModel:      model,
+		VarianceMarkets:    markets,
+	}, nil
+}
+
+// fitFillRate ajusta horas = α·QueueAhead + β·Size sin término independiente
+// sobre las órdenes llenadas del todo.
+func fitFillRate(orders []domain.LiveOrder) (domain.FillRateModel, error) {
+	var (
+		qq, qs, ss, qh, sh float64
+		hours              []float64
+		xs                 [][2]float64
+	)
+	for _, o := range orders {
+		if o.FilledAt == nil || o.FilledSize < o.Size-0.01 || o.Size <= 0 {
+			continue
+		}
+		h := o.FilledAt.Sub(o.PlacedAt).Hours()
+		if h <= 0 {
+			continue
+		}
+		q, s := o.QueueAhead, o.Size
+		qq += q * q
+		qs += q * s
+		ss += s * s
+		qh += q * h
+		sh += s * h
+		hours = append(hours, h)
+		xs = append(xs, [2]float64{q, s})
+	}
+	if len(hours) < minCalibrationSamples {
+		return domain.FillRateModel{}, fmt.Errorf("%d filled orders in the last 30 days, need %d", len(hours), minCalibrationSamples)
+	}
+
+	det := qq*ss - qs*qs
+	if math.Abs(det) < 1e-9*qq*ss {
+		return domain.FillRateModel{}, fmt.Errorf("queue ahead does not vary across the %d filled orders", len(hours))
+	}
+	alpha := (qh*ss - sh*qs) / det
+	beta := (sh*qq - qh*qs) / det
+	if beta <= 0 || alpha < 0 {
+		return domain.FillRateModel{}, fmt.Errorf("fill times do not grow with queue and size (α=%.4f β=%.4f)", alpha, beta)
+	}
+
+	var mean float64
+	for _, h := range hours {
+		mean += h
+	}
+	mean /= float64(len(hours))
+	var ssRes, ssTot float64
+	for i, h := range hours {
+		pred := alpha*xs[i][0] + beta*xs[i][1]
+		ssRes += (h - pred) * (h - pred)
+		ssTot += (h - mean) * (h - mean)
+	}
+	r2 := 0.0
+	if ssTot > 0 {
+		r2 = 1 - ssRes/ssTot
+	}
+
+	return domain.FillRateModel{
+		USDCPerHour: 1 / beta,
+		QueueScale:  alpha / beta,
+		R2:          r2,
+		Samples:     len(hours),
+	}, nil
+}
+
+// marketSpread resume los pares de un mercado: el spread de cada par al
+// colocarlo y si alguno se quedó con una sola pata llena.
+type marketSpread struct {
+	spreads  []float64
+	oneSided bool
+}
+
+// fitVarianceMax elige el coeficiente de variación que mejor separa los
+// mercados sin pares cojos (cv ≤ umbral) de los que los tuvieron (cv >
+// umbral). En empate gana el umbral más alto, el que menos mercados deja
+// fuera. Devuelve 0 si no hay mercados de las dos clases.
+func fitVarianceMax(orders []domain.LiveOrder) (float64, int) {
+	type leg struct {
+		bid    float64
+		filled bool
+	}
+	pairs := make(map[string]map[string]leg)
+	condOf := make(map[string]string)
+	for _, o := range orders {
+		if pairs[o.PairID] == nil {
+			pairs[o.PairID] = make(map[string]leg)
+		}
+		pairs[o.PairID][o.Side] = leg{bid: o.BidPrice, filled: o.FilledSize > 0}
+		condOf[o.PairID] = o.ConditionID
+	}
+
+	markets := make(map[string]*marketSpread)
+	for pairID, legs := range pairs {
+		yes, okY := legs["YES"]
+		no, okN := legs["NO"]
+		if !okY || !okN {
+			continue
+		}
+		m := markets[condOf[pairID]]
+		if m == nil {
+			m = &marketSpread{}
+			markets[condOf[pairID]] = m
+		}
+		m.spreads = append(m.spreads, yes.bid+no.bid)
+		if yes.filled != no.filled {
+			m.oneSided = true
+		}
+	}
+
+	type sample struct {
+		cv       float64
+		oneSided bool
+	}
+	var samples []sample
+	clean, lopsided := 0, 0
+	for _, m := range markets {
+		if len(m.spreads) < calibrationMinPairs {
+			continue
+		}
+		samples = append(samples, sample{cv: coefVariation(m.spreads), oneSided: m.oneSided})
+		if m.oneSided {
+			lopsided++
+		} else {
+			clean++
+		}
+	}
+	if clean == 0 || lopsided == 0 {
+		return 0, len(samples)
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].cv < samples[j].cv })
+
+	// Con el umbral en samples[i].cv quedan dentro los i+1 primeros: aciertan
+	// los limpios de dentro y los cojos de fuera.
+	best, bestHits := 0.0, -1
+	cleanIn, lopsidedIn := 0, 0
+	for i, s := range samples {
+		if s.oneSided {
+			lopsidedIn++
+		} else {
+			cleanIn++
+		}
+		if i+1 < len(samples) && samples[i+1].cv == s.cv {
+			continue
+		}
+		if hits := cleanIn + (lopsided - lopsidedIn); hits >= bestHits {
+			best, bestHits = s.cv, hits
+		}
+	}
+	return best, len(samples)
+}
+
+// coefVariation es la desviación típica entre la media, como spreadStable.
+func coefVariation(xs []float64) float64 {
+	var mean float64
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	if mean == 0 {
+		return 0
+	}
+	var variance float64
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	variance /= float64(len(xs))
+	return math.Sqrt(variance) / math.Abs(mean)
+}
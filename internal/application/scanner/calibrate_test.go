@@ -0,0 +1,88 @@
+package scanner_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// calibrationStore devuelve un storage live en memoria.
+func calibrationStore(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyLiveSchema(context.Background()))
+	return db
+}
+
+// savePair guarda un par YES+NO cuyas patas llenadas tardan lo que predice
+// horas = (2·QueueAhead + Size) / 10.
+func savePair(t *testing.T, db *storage.SQLiteStorage, cond string, n int, yesBid, noBid, queue, size float64, fillYes, fillNo bool) {
+	t.Helper()
+	placed := time.Now().UTC().Add(-48 * time.Hour)
+	pairID := fmt.Sprintf("%s-p%d", cond, n)
+	for _, leg := range []struct {
+		side   string
+		bid    float64
+		filled bool
+	}{{"YES", yesBid, fillYes}, {"NO", noBid, fillNo}} {
+		o := domain.LiveOrder{
+			ID:          pairID + "-" + leg.side,
+			ConditionID: cond,
+			Side:        leg.side,
+			BidPrice:    leg.bid,
+			Size:        size,
+			PairID:      pairID,
+			PlacedAt:    placed,
+			Status:      domain.LiveStatusCancelled,
+			QueueAhead:  queue,
+		}
+		if leg.filled {
+			at := placed.Add(time.Duration((2*queue + size) / 10 * float64(time.Hour)))
+			o.Status = domain.LiveStatusFilled
+			o.FilledSize = size
+			o.FilledAt = &at
+		}
+		require.NoError(t, db.SaveLiveOrder(context.Background(), o))
+	}
+}
+
+func TestCalibrateLiveConstants_FitsQueueMultAndVariance(t *testing.T) {
+	db := calibrationStore(t)
+	for i := 0; i < 6; i++ {
+		savePair(t, db, "c-fit", i, 0.45, 0.45, float64(10+15*i), float64(5+3*(i%3)), true, true)
+	}
+	savePair(t, db, "c-calm", 0, 0.45, 0.45, 20, 5, true, true)
+	savePair(t, db, "c-calm", 1, 0.46, 0.46, 20, 5, true, true)
+	savePair(t, db, "c-calm", 2, 0.45, 0.46, 20, 5, true, true)
+	savePair(t, db, "c-jumpy", 0, 0.35, 0.35, 20, 5, true, true)
+	savePair(t, db, "c-jumpy", 1, 0.48, 0.47, 20, 5, true, false)
+	savePair(t, db, "c-jumpy", 2, 0.40, 0.40, 20, 5, true, true)
+
+	cal, err := scanner.CalibrateLiveConstants(context.Background(), db)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 2.0, cal.FillRateModel.QueueScale, 1e-6)
+	assert.InDelta(t, 10.0, cal.FillRateModel.USDCPerHour, 1e-6)
+	assert.InDelta(t, 1.0, cal.FillRateModel.R2, 1e-6)
+	assert.InDelta(t, 3.0, cal.OptimalQueueMult, 1e-6, "stored queues used 1.5, and they take twice their size")
+	assert.Equal(t, 3, cal.VarianceMarkets)
+	assert.Greater(t, cal.OptimalVarianceMax, 0.0, "c-calm is clean with a small spread variation")
+	assert.Less(t, cal.OptimalVarianceMax, 0.05, "c-jumpy had a one-legged pair and must fall outside")
+}
+
+func TestCalibrateLiveConstants_TooFewFills(t *testing.T) {
+	db := calibrationStore(t)
+	savePair(t, db, "c1", 0, 0.45, 0.45, 20, 5, true, true)
+
+	_, err := scanner.CalibrateLiveConstants(context.Background(), db)
+	assert.Error(t, err)
+}
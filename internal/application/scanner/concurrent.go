@@ -67,6 +67,14 @@ func analyzeMarketsConcurrent(
 			slog.Debug("missing books for market", "condition_id", market.ConditionID)
 			continue
 		}
+		if yesBook.IsCrossed() || noBook.IsCrossed() {
+			slog.Debug("crossed book, market not eligible",
+				"condition_id", market.ConditionID,
+				"yes_bid", yesBook.BestBid(), "yes_ask", yesBook.BestAsk(),
+				"no_bid", noBook.BestBid(), "no_ask", noBook.BestAsk(),
+			)
+			continue
+		}
 		workCh <- work{market: market, yesBook: yesBook, noBook: noBook}
 		queued++
 	}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// exportColumns es la cabecera del CSV de oportunidades.
+var exportColumns = []string{
+	"condition_id",
+	"question",
+	"category",
+	"your_daily_reward",
+	"fill_cost_per_pair",
+	"spread_total",
+	"competition",
+	"hours_to_resolution",
+	"combined_score",
+	"verdict",
+}
+
+// ExportOpportunities escribe las oportunidades de un ciclo en path. El formato
+// se elige por extensión: .csv (una fila por oportunidad con los campos
+// puntuados) o .json (la oportunidad completa, incluidos Arbitrage y
+// Market.Rewards). El fichero se sobreescribe.
+func ExportOpportunities(opps []domain.Opportunity, path string) error {
+	var write func(*os.File, []domain.Opportunity) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		write = writeOpportunitiesCSV
+	case ".json":
+		write = writeOpportunitiesJSON
+	default:
+		return fmt.Errorf("scanner.ExportOpportunities: unsupported extension %q (use .csv or .json)", filepath.Ext(path))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("scanner.ExportOpportunities: %w", err)
+	}
+	if err := write(f, opps); err != nil {
+		f.Close()
+		return fmt.Errorf("scanner.ExportOpportunities: write %q: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("scanner.ExportOpportunities: close %q: %w", path, err)
+	}
+	return nil
+}
+
+func writeOpportunitiesCSV(f *os.File, opps []domain.Opportunity) error {
+	w := csv.NewWriter(f)
+	if err := w.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, o := range opps {
+		row := []string{
+			o.Market.ConditionID,
+			o.Market.Question,
+			o.Category.String(),
+			formatExportFloat(o.YourDailyReward),
+			formatExportFloat(o.FillCostPerPair),
+			formatExportFloat(o.SpreadTotal),
+			formatExportFloat(o.Competition),
+			formatExportFloat(o.Market.HoursToResolution()),
+			formatExportFloat(o.CombinedScore),
+			o.Verdict(),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// exportOpportunity añade a la oportunidad los campos derivados y sustituye
+// BreakEvenFills por un puntero: encoding/json no admite ±Inf (fills gratis),
+// que se exporta como null.
+type exportOpportunity struct {
+	domain.Opportunity
+	BreakEvenFills    *float64
+	Category          string
+	Verdict           string
+	HoursToResolution float64
+}
+
+func writeOpportunitiesJSON(f *os.File, opps []domain.Opportunity) error {
+	out := make([]exportOpportunity, 0, len(opps))
+	for _, o := range opps {
+		e := exportOpportunity{
+			Opportunity:       o,
+			Category:          o.Category.String(),
+			Verdict:           o.Verdict(),
+			HoursToResolution: o.Market.HoursToResolution(),
+		}
+		if !math.IsInf(o.BreakEvenFills, 0) && !math.IsNaN(o.BreakEvenFills) {
+			v := o.BreakEvenFills
+			e.BreakEvenFills = &v
+		}
+		out = append(out, e)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// formatExportFloat escribe ±Inf como "inf"/"-inf" para que el CSV sea legible
+// por pandas y similares.
+func formatExportFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
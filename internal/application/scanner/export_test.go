@@ -0,0 +1,72 @@
+package scanner_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportOpps() []domain.Opportunity {
+	market := makeMarket("0xc1", "yes1", "no1", 50, 0.05)
+	market.Question = "Will it rain, tomorrow?"
+	return []domain.Opportunity{{
+		Market:          market,
+		SpreadTotal:     0.02,
+		Competition:     1500,
+		YourDailyReward: 1.25,
+		FillCostPerPair: -0.01,
+		BreakEvenFills:  math.Inf(1),
+		CombinedScore:   1.25,
+		Category:        domain.CategoryGold,
+		Arbitrage:       domain.ArbitrageResult{SumBestAsk: 1.01},
+	}}
+}
+
+func TestExportOpportunities_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.csv")
+	require.NoError(t, scanner.ExportOpportunities(exportOpps(), path))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "condition_id", rows[0][0])
+	assert.Equal(t, "0xc1", rows[1][0])
+	assert.Equal(t, "Will it rain, tomorrow?", rows[1][1], "commas are quoted")
+	assert.Equal(t, "1.25", rows[1][3])
+	assert.Equal(t, "FILLS=PROFIT", rows[1][9])
+}
+
+func TestExportOpportunities_JSONIncludesNestedFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.json")
+	require.NoError(t, scanner.ExportOpportunities(exportOpps(), path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var out []map[string]any
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	require.Len(t, out, 1)
+	assert.Nil(t, out[0]["BreakEvenFills"], "Inf is exported as null")
+	assert.Equal(t, "FILLS=PROFIT", out[0]["Verdict"])
+	arb := out[0]["Arbitrage"].(map[string]any)
+	assert.InDelta(t, 1.01, arb["SumBestAsk"], 1e-9)
+	rewards := out[0]["Market"].(map[string]any)["Rewards"].(map[string]any)
+	assert.InDelta(t, 50.0, rewards["DailyRate"], 1e-9)
+}
+
+func TestExportOpportunities_RejectsUnknownExtension(t *testing.T) {
+	err := scanner.ExportOpportunities(exportOpps(), filepath.Join(t.TempDir(), "scan.txt"))
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"strings"
+
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
@@ -12,6 +14,11 @@ type FilterConfig struct {
 	MinRewardScore float64
 	// MaxSpreadTotal descarta mercados cuyo spread supera este valor.
 	MaxSpreadTotal float64
+	// SpreadOverrides fija el spread máximo por tipo de mercado: la clave es
+	// una categoría de Gamma o un prefijo de slug. Sustituye a MaxSpreadTotal
+	// y al MaxSpread del reward para decidir si el mercado califica; gana la
+	// coincidencia más específica (prefijo de slug más largo, luego categoría).
+	SpreadOverrides map[string]float64
 	// MaxCompetition descarta mercados con demasiados LPs compitiendo (USDC en book).
 	MaxCompetition float64
 	// RequireQualifies si true, solo incluye mercados que califican para el reward.
@@ -20,6 +27,12 @@ type FilterConfig struct {
 	MinHoursToResolution float64
 	// OnlyFillsProfit si true, descarta mercados donde un fill te cuesta dinero (FillCostUSDC > 0).
 	OnlyFillsProfit bool
+	// Markets es la lista negra/blanca de mercados (nil = sin lista).
+	Markets *MarketListSource
+	// IncludeKeywords si no está vacío, solo pasan mercados cuya pregunta contiene alguna.
+	IncludeKeywords []string
+	// ExcludeKeywords descarta mercados cuya pregunta contiene alguna (gana sobre Include).
+	ExcludeKeywords []string
 }
 
 // DefaultFilterConfig devuelve una configuración de filtrado conservadora.
@@ -49,6 +62,7 @@ func NewFilter(cfg FilterConfig) *Filter {
 func (f *Filter) Apply(opps []domain.Opportunity) []domain.Opportunity {
 	result := make([]domain.Opportunity, 0, len(opps))
 	for _, opp := range opps {
+		opp = f.applySpreadOverride(opp)
 		if f.passes(opp) {
 			result = append(result, opp)
 		}
@@ -58,6 +72,12 @@ func (f *Filter) Apply(opps []domain.Opportunity) []domain.Opportunity {
 
 // passes devuelve true si la oportunidad supera todos los criterios.
 func (f *Filter) passes(opp domain.Opportunity) bool {
+	if !f.cfg.Markets.Allows(opp.Market) {
+		return false
+	}
+	if !filterByKeywords(opp, f.cfg) {
+		return false
+	}
 	if f.cfg.RequireQualifies && !opp.QualifiesReward {
 		return false
 	}
@@ -67,7 +87,11 @@ func (f *Filter) passes(opp domain.Opportunity) bool {
 	if f.cfg.MinRewardScore > 0 && opp.RewardScore < f.cfg.MinRewardScore {
 		return false
 	}
-	if f.cfg.MaxSpreadTotal > 0 && opp.SpreadTotal > f.cfg.MaxSpreadTotal {
+	maxSpread := f.cfg.MaxSpreadTotal
+	if override, ok := f.spreadOverride(opp.Market); ok {
+		maxSpread = override
+	}
+	if maxSpread > 0 && opp.SpreadTotal > maxSpread {
 		return false
 	}
 	if f.cfg.MaxCompetition > 0 && opp.Competition > f.cfg.MaxCompetition {
@@ -86,3 +110,63 @@ func (f *Filter) passes(opp domain.Opportunity) bool {
 	}
 	return true
 }
+
+// applySpreadOverride recalcula QualifiesReward con el umbral de
+// SpreadOverrides que aplique al mercado; sin override se deja el de la
+// estrategia (Rewards.MaxSpread).
+func (f *Filter) applySpreadOverride(opp domain.Opportunity) domain.Opportunity {
+	if override, ok := f.spreadOverride(opp.Market); ok {
+		opp.QualifiesReward = opp.SpreadTotal <= override
+	}
+	return opp
+}
+
+// spreadOverride devuelve el umbral más específico de SpreadOverrides para
+// el mercado (claves sin distinguir mayúsculas): un prefijo de slug gana a la
+// categoría y, entre prefijos, el más largo. A igual especificidad gana el
+// umbral más estricto.
+func (f *Filter) spreadOverride(m domain.Market) (float64, bool) {
+	category := strings.ToLower(m.Category)
+	slug := strings.ToLower(m.Slug)
+	best, bestRank := 0.0, -1
+	for key, maxSpread := range f.cfg.SpreadOverrides {
+		k := strings.ToLower(strings.TrimSpace(key))
+		rank := -1
+		switch {
+		case k == "":
+		case slug != "" && strings.HasPrefix(slug, k):
+			rank = len(k)
+		case k == category:
+			rank = 0
+		}
+		if rank < 0 {
+			continue
+		}
+		if rank > bestRank || (rank == bestRank && maxSpread < best) {
+			best, bestRank = maxSpread, rank
+		}
+	}
+	return best, bestRank >= 0
+}
+
+// filterByKeywords aplica IncludeKeywords y ExcludeKeywords sobre la pregunta
+// del mercado (substring sin distinguir mayúsculas). Exclude gana.
+func filterByKeywords(opp domain.Opportunity, f FilterConfig) bool {
+	question := strings.ToLower(opp.Market.Question)
+	if containsAnyKeyword(question, f.ExcludeKeywords) {
+		return false
+	}
+	return len(f.IncludeKeywords) == 0 || containsAnyKeyword(question, f.IncludeKeywords)
+}
+
+// containsAnyKeyword indica si s (ya en minúsculas) contiene alguna keyword.
+// Las keywords vacías se ignoran.
+func containsAnyKeyword(s string, keywords []string) bool {
+	for _, k := range keywords {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k != "" && strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package scanner
+
+// historical.go — Backtest sobre mercados pasados (--backtest-dates).
+//
+// Backtest reproduce trades reales sobre las oportunidades de hoy. Este modo
+// va al revés: toma los mercados que estuvieron abiertos en la ventana, con
+// el bid/ask que Gamma guardó, y los pasa por el mismo análisis que el scanner
+// (strategy → filtro → ranking). El P&L usa el escenario del ranking, reward
+// menos 1 fill/día, ya que no hay books ni trades históricos.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+const defaultHistoricalMarkets = 50
+
+// ParseBacktestDates interpreta el valor de --backtest-dates,
+// "from=2024-01-01,to=2024-01-31". La ventana incluye el día to completo.
+func ParseBacktestDates(spec string) (from, to time.Time, err error) {
+	for _, part := range strings.Split(spec, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return from, to, fmt.Errorf("scanner.ParseBacktestDates: %q: want key=YYYY-MM-DD", part)
+		}
+		day, err := time.Parse(time.DateOnly, strings.TrimSpace(val))
+		if err != nil {
+			return from, to, fmt.Errorf("scanner.ParseBacktestDates: %s: %w", key, err)
+		}
+		switch strings.TrimSpace(key) {
+		case "from":
+			from = day
+		case "to":
+			to = day.Add(24 * time.Hour)
+		default:
+			return from, to, fmt.Errorf("scanner.ParseBacktestDates: unknown key %q", key)
+		}
+	}
+	if from.IsZero() || to.IsZero() || !to.After(from) {
+		return from, to, fmt.Errorf("scanner.ParseBacktestDates: %q: need from <= to", spec)
+	}
+	return from, to, nil
+}
+
+// HistoricalBacktest analiza los mercados abiertos en [From, To) con los
+// precios guardados por Gamma y simula su P&L diario con SimulateHistorical.
+// MaxMarkets limita cuántos de los mejor rankeados se simulan (0 = 50).
+func (s *Scanner) HistoricalBacktest(ctx context.Context, markets ports.HistoricalMarketProvider, cfg BacktestConfig) (*domain.BacktestResult, error) {
+	if cfg.To.IsZero() {
+		cfg.To = time.Now().UTC()
+	}
+	if !cfg.To.After(cfg.From) {
+		return nil, fmt.Errorf("scanner.HistoricalBacktest: invalid window %s → %s", cfg.From.Format(time.DateOnly), cfg.To.Format(time.DateOnly))
+	}
+	if cfg.MaxMarkets <= 0 {
+		cfg.MaxMarkets = defaultHistoricalMarkets
+	}
+
+	history, err := markets.FetchHistoricalMarkets(ctx, cfg.From, cfg.To)
+	if err != nil {
+		return nil, fmt.Errorf("scanner.HistoricalBacktest: %w", err)
+	}
+
+	books := make(map[string]domain.OrderBook, 2*len(history))
+	for _, m := range history {
+		yes, no, ok := historicalBooks(m)
+		if !ok {
+			continue
+		}
+		books[yes.TokenID] = yes
+		books[no.TokenID] = no
+	}
+
+	opps := analyzeMarketsConcurrent(ctx, s.analyzer, history, books, s.cfg.AnalysisWorkers)
+	opps = rank(s.currentFilter().Apply(opps), s.cfg.RankFunc)
+	if len(opps) > cfg.MaxMarkets {
+		opps = opps[:cfg.MaxMarkets]
+	}
+
+	result := SimulateHistorical(opps, cfg)
+	return &result, nil
+}
+
+// historicalBooks reconstruye los books YES/NO desde el bid/ask YES que Gamma
+// guardó: el NO es el espejo (bid NO = 1 - ask YES). Sin depth histórico, la
+// liquidez del mercado se reparte entre los cuatro niveles como aproximación
+// de la competencia.
+func historicalBooks(m domain.Market) (yes, no domain.OrderBook, ok bool) {
+	bid, ask := m.BestBid, m.BestAsk
+	if bid <= 0 || ask <= 0 || ask >= 1 || bid >= ask {
+		return yes, no, false
+	}
+	levelUSDC := m.Liquidity / 4
+	level := func(price float64) []domain.BookEntry {
+		return []domain.BookEntry{{Price: price, Size: levelUSDC / price}}
+	}
+
+	yes = domain.OrderBook{TokenID: m.YesToken().TokenID, Bids: level(bid), Asks: level(ask)}
+	no = domain.OrderBook{TokenID: m.NoToken().TokenID, Bids: level(1 - ask), Asks: level(1 - bid)}
+	return yes, no, true
+}
+
+// SimulateHistorical reparte el P&L estimado de cada oportunidad por día UTC
+// mientras el mercado estuvo abierto dentro de la ventana: el reward diario
+// para OrderSize menos el coste de un fill al día, como en el ranking.
+func SimulateHistorical(opps []domain.Opportunity, cfg BacktestConfig) domain.BacktestResult {
+	result := domain.BacktestResult{From: cfg.From, To: cfg.To, OrderSize: cfg.OrderSize, Historical: true}
+	days := make(map[time.Time]*domain.BacktestDay)
+
+	for _, opp := range opps {
+		m := opp.Market
+		dailyReward := domain.EstimateYourDailyReward(cfg.OrderSize, opp.Competition,
+			m.Rewards.DailyRate, opp.SpreadTotal, m.Rewards.MaxSpread)
+		fillCost := domain.FillCostUSDC(cfg.OrderSize, backtestBid(opp.YesBook), backtestBid(opp.NoBook), opp.FillCostPerPair)
+
+		bm := domain.BacktestMarket{ConditionID: m.ConditionID, Question: m.Question, DailyReward: make(map[time.Time]float64)}
+
+		open := maxTime(cfg.From, m.StartDate)
+		end := cfg.To
+		if !m.EndDate.IsZero() && m.EndDate.Before(cfg.To) {
+			resolved := m.EndDate
+			bm.ResolvedAt = &resolved
+			end = resolved
+		}
+
+		for day := open.UTC().Truncate(24 * time.Hour); day.Before(end); day = day.Add(24 * time.Hour) {
+			start := maxTime(day, open)
+			stop := minTime(day.Add(24*time.Hour), end)
+			if !stop.After(start) {
+				continue
+			}
+			frac := stop.Sub(start).Hours() / 24
+			reward := dailyReward * frac
+			cost := fillCost * frac
+
+			d, ok := days[day]
+			if !ok {
+				d = &domain.BacktestDay{Date: day}
+				days[day] = d
+			}
+			d.Markets++
+			d.Reward += reward
+			d.FillCost += cost
+			d.PnL += reward - cost
+
+			bm.PnL += reward - cost
+			bm.DailyReward[day] += reward
+		}
+
+		result.Markets = append(result.Markets, bm)
+	}
+
+	addBacktestDays(&result, days)
+	return result
+}
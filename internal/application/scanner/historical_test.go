@@ -0,0 +1,65 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHistoricalProvider struct {
+	markets  []domain.Market
+	from, to time.Time
+}
+
+func (m *mockHistoricalProvider) FetchHistoricalMarkets(_ context.Context, from, to time.Time) ([]domain.Market, error) {
+	m.from, m.to = from, to
+	return m.markets, nil
+}
+
+func TestScanner_HistoricalBacktestUsesStoredPrices(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4 * 24 * time.Hour)
+
+	resolved := makeMarket("0xc1", "yes1", "no1", 50, 0.04)
+	resolved.BestBid, resolved.BestAsk, resolved.Liquidity = 0.70, 0.72, 1000
+	resolved.StartDate = from.Add(-30 * 24 * time.Hour)
+	resolved.EndDate = from.Add(36 * time.Hour) // resolves mid-day 2
+
+	noPrices := makeMarket("0xc2", "yes2", "no2", 50, 0.04)
+
+	hp := &mockHistoricalProvider{markets: []domain.Market{resolved, noPrices}}
+	s := newTestScanner(&mockMarketProvider{}, &mockBookProvider{}, &mockNotifier{}, &mockStorage{})
+
+	res, err := s.HistoricalBacktest(context.Background(), hp, scanner.BacktestConfig{From: from, To: to, OrderSize: 100})
+	require.NoError(t, err)
+	assert.Equal(t, from, hp.from)
+	assert.Equal(t, to, hp.to)
+
+	assert.True(t, res.Historical)
+	require.Len(t, res.Markets, 1, "markets without stored prices cannot be analyzed")
+	assert.Equal(t, "0xc1", res.Markets[0].ConditionID)
+	require.NotNil(t, res.Markets[0].ResolvedAt)
+
+	require.Len(t, res.Days, 2, "no days after resolution")
+	assert.Greater(t, res.Days[0].Reward, 0.0)
+	assert.InDelta(t, res.Days[0].Reward/2, res.Days[1].Reward, 1e-9, "day 2 only accrues until noon")
+	assert.Zero(t, res.TotalFills)
+	assert.InDelta(t, res.TotalReward-res.TotalFillCost, res.NetPnL, 1e-9)
+}
+
+func TestParseBacktestDates(t *testing.T) {
+	from, to, err := scanner.ParseBacktestDates("from=2024-01-01,to=2024-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), to, "the to day is included")
+
+	for _, bad := range []string{"", "from=2024-01-01", "from=2024-01-31,to=2024-01-01", "from=01/01/2024,to=2024-01-31", "since=2024-01-01,to=2024-01-31"} {
+		_, _, err := scanner.ParseBacktestDates(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// adaptiveWindow es cuántos ciclos recientes recuerda el scanner.
+	adaptiveWindow = 5
+	// adaptiveIdleCycles es cuántos ciclos seguidos sin fills alargan el intervalo.
+	adaptiveIdleCycles = 3
+)
+
+// AdaptiveIntervalConfig ajusta el intervalo entre ciclos según la actividad:
+// con fills recientes, posiciones live abiertas o un scan con muchos Gold se
+// escanea hacia MinInterval para cazar la pata contraria y mergear antes; sin
+// actividad el intervalo crece hasta MaxInterval para ahorrar llamadas a la
+// API. MinInterval = 0 desactiva el ajuste.
+type AdaptiveIntervalConfig struct {
+	MinInterval       time.Duration
+	MaxInterval       time.Duration
+	FilledBoostFactor float64 // divisor del intervalo con actividad (≤ 1 = saltar a MinInterval)
+	IdleScaleUp       float64 // multiplicador por ciclo ocioso (≤ 1 = no crecer)
+	GoldBusy          int     // Gold del último scan que cuentan como actividad (0 = ignorar)
+}
+
+// Enabled indica si el intervalo adaptativo está configurado.
+func (c AdaptiveIntervalConfig) Enabled() bool {
+	return c.MinInterval > 0
+}
+
+// fillHistory es un ring buffer con los fills de los últimos ciclos.
+type fillHistory struct {
+	fills [adaptiveWindow]int
+	next  int
+	count int
+}
+
+func (h *fillHistory) add(n int) {
+	h.fills[h.next] = n
+	h.next = (h.next + 1) % adaptiveWindow
+	h.count = min(h.count+1, adaptiveWindow)
+}
+
+// recent devuelve los fills de los últimos n ciclos, el más reciente primero.
+func (h *fillHistory) recent(n int) []int {
+	n = min(n, h.count)
+	out := make([]int, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, h.fills[(h.next-i+adaptiveWindow)%adaptiveWindow])
+	}
+	return out
+}
+
+// RecordFills registra los fills (NewFills) del ciclo que acaba de terminar
+// en paper o live. NextInterval decide con los últimos ciclos registrados.
+func (s *Scanner) RecordFills(n int) {
+	s.fillHistory.add(n)
+}
+
+// RecordActivePositions registra cuántas posiciones live siguen abiertas tras
+// el ciclo; mientras haya alguna el intervalo se acorta como con fills.
+func (s *Scanner) RecordActivePositions(n int) {
+	s.activePositions = n
+}
+
+// NextInterval devuelve cuánto esperar hasta el próximo ciclo. Si algún ciclo
+// reciente tuvo fills, hay posiciones live abiertas o el último scan encontró
+// al menos GoldBusy Gold, se acorta hacia MinInterval; si no, tras tres ciclos
+// sin actividad el intervalo actual se multiplica por IdleScaleUp hasta
+// MaxInterval. Sin configuración adaptativa devuelve ScanInterval. Cada
+// llamada loguea el intervalo elegido y el motivo.
+func (s *Scanner) NextInterval() time.Duration {
+	ac := s.cfg.Adaptive
+	if !ac.Enabled() {
+		return s.cfg.ScanInterval
+	}
+	maxInterval := max(ac.MaxInterval, ac.MinInterval)
+	if s.interval == 0 {
+		s.interval = min(max(s.cfg.ScanInterval, ac.MinInterval), maxInterval)
+	}
+
+	prev := s.interval
+	reason := "steady"
+	switch {
+	case anyFills(s.fillHistory.recent(adaptiveWindow)):
+		reason = "fills"
+	case s.activePositions > 0:
+		reason = "positions"
+	case ac.GoldBusy > 0 && s.lastGold >= ac.GoldBusy:
+		reason = "gold"
+	case max(s.fillHistory.count, s.cycles) >= adaptiveIdleCycles && ac.IdleScaleUp > 1:
+		reason = "idle"
+		s.interval = min(time.Duration(float64(s.interval)*ac.IdleScaleUp), maxInterval)
+	}
+	if reason != "steady" && reason != "idle" {
+		if ac.FilledBoostFactor > 1 {
+			s.interval = max(time.Duration(float64(s.interval)/ac.FilledBoostFactor), ac.MinInterval)
+		} else {
+			s.interval = ac.MinInterval
+		}
+	}
+
+	slog.Info("scan interval", "interval", s.interval, "previous", prev, "reason", reason,
+		"positions", s.activePositions, "gold", s.lastGold)
+	return s.interval
+}
+
+func anyFills(fills []int) bool {
+	for _, n := range fills {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
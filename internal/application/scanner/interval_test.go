@@ -0,0 +1,117 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/domain/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdaptiveScanner(ac scanner.AdaptiveIntervalConfig) *scanner.Scanner {
+	return scanner.New(scanner.Config{ScanInterval: time.Minute, Adaptive: ac}, nil, nil, nil, nil, nil)
+}
+
+func TestNextInterval_StaticWhenDisabled(t *testing.T) {
+	s := newAdaptiveScanner(scanner.AdaptiveIntervalConfig{})
+	for i := 0; i < 4; i++ {
+		s.RecordFills(0)
+	}
+	assert.Equal(t, time.Minute, s.NextInterval())
+}
+
+func TestNextInterval_IdleScalesUpThenFillsReset(t *testing.T) {
+	s := newAdaptiveScanner(scanner.AdaptiveIntervalConfig{
+		MinInterval: 15 * time.Second,
+		MaxInterval: 3 * time.Minute,
+		IdleScaleUp: 2,
+	})
+
+	s.RecordFills(0)
+	s.RecordFills(0)
+	assert.Equal(t, time.Minute, s.NextInterval(), "fewer than 3 idle cycles keep the interval")
+
+	s.RecordFills(0)
+	assert.Equal(t, 2*time.Minute, s.NextInterval())
+	s.RecordFills(0)
+	assert.Equal(t, 3*time.Minute, s.NextInterval(), "capped at MaxInterval")
+
+	s.RecordFills(2)
+	assert.Equal(t, 15*time.Second, s.NextInterval())
+
+	// The fill stays in the 5-cycle window for four more quiet cycles.
+	for i := 0; i < 4; i++ {
+		s.RecordFills(0)
+		assert.Equal(t, 15*time.Second, s.NextInterval())
+	}
+	s.RecordFills(0)
+	assert.Equal(t, 30*time.Second, s.NextInterval())
+}
+
+func TestNextInterval_FilledBoostFactor(t *testing.T) {
+	s := newAdaptiveScanner(scanner.AdaptiveIntervalConfig{
+		MinInterval:       10 * time.Second,
+		MaxInterval:       5 * time.Minute,
+		FilledBoostFactor: 4,
+	})
+	s.RecordFills(1)
+	assert.Equal(t, 15*time.Second, s.NextInterval())
+	assert.Equal(t, 10*time.Second, s.NextInterval(), "floored at MinInterval")
+}
+
+func TestNextInterval_ActivePositionsShorten(t *testing.T) {
+	s := newAdaptiveScanner(scanner.AdaptiveIntervalConfig{
+		MinInterval: 15 * time.Second,
+		MaxInterval: 3 * time.Minute,
+		IdleScaleUp: 2,
+	})
+	for i := 0; i < 3; i++ {
+		s.RecordFills(0)
+	}
+	s.RecordActivePositions(2)
+	assert.Equal(t, 15*time.Second, s.NextInterval(), "open positions count as activity")
+
+	s.RecordActivePositions(0)
+	assert.Equal(t, 30*time.Second, s.NextInterval())
+}
+
+func TestNextInterval_GoldBusyShortensAndIdleScansLengthen(t *testing.T) {
+	// Bids summing 0.98 < 1 make the market Gold (see NearArb_IsGold).
+	market := makeMarket("0xabc", "yes1", "no1", 25, 0.04)
+	book := func(id string) domain.OrderBook {
+		return domain.OrderBook{TokenID: id,
+			Bids: []domain.BookEntry{{Price: 0.49, Size: 100}},
+			Asks: []domain.BookEntry{{Price: 0.50, Size: 100}}}
+	}
+	mp := &mockMarketProvider{markets: []domain.Market{market}}
+	bp := &mockBookProvider{books: map[string]domain.OrderBook{"yes1": book("yes1"), "no1": book("no1")}}
+	strat := strategy.NewRewardFarming(strategy.RewardFarmingConfig{OrderSize: 100, FeeRate: 0.001, FillsPerDay: 1.0, GoldMinReward: 0.01})
+	s := scanner.New(scanner.Config{
+		ScanInterval: time.Minute,
+		Filter:       scanner.FilterConfig{RequireQualifies: true},
+		Adaptive: scanner.AdaptiveIntervalConfig{
+			MinInterval: 10 * time.Second,
+			MaxInterval: 5 * time.Minute,
+			IdleScaleUp: 2,
+			GoldBusy:    1,
+		},
+	}, mp, bp, nil, &mockNotifier{}, strat)
+
+	opps, err := s.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, opps, 1)
+	require.Equal(t, domain.CategoryGold, opps[0].Category)
+	assert.Equal(t, 10*time.Second, s.NextInterval())
+
+	// Without Gold, three scans with no fills recorded are enough to grow.
+	mp.markets = nil
+	for i := 0; i < 3; i++ {
+		_, err := s.RunOnce(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 20*time.Second, s.NextInterval())
+}
@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// marketMatchFile es el formato de cada sección del fichero de listas.
+type marketMatchFile struct {
+	Slugs        []string `yaml:"slugs"`
+	ConditionIDs []string `yaml:"condition_ids"`
+	Questions    []string `yaml:"questions"`
+}
+
+func (f marketMatchFile) toDomain() domain.MarketMatch {
+	return domain.MarketMatch{Slugs: f.Slugs, ConditionIDs: f.ConditionIDs, Questions: f.Questions}
+}
+
+// MarketListSource es la lista negra/blanca de mercados compartida por el
+// scanner y los engines. Combina las reglas del config con las de un fichero
+// YAML opcional (secciones exclude/include) que se relee cuando cambia, así
+// que editarlo surte efecto en el siguiente ciclo sin reiniciar.
+type MarketListSource struct {
+	exclude domain.MarketMatch
+	include domain.MarketMatch
+	file    string
+
+	mu      sync.RWMutex
+	list    *domain.MarketList
+	modTime time.Time
+}
+
+// NewMarketListSource compila las reglas del config y carga file si no está
+// vacío. Devuelve error si algún patrón es inválido o el fichero no se lee.
+func NewMarketListSource(exclude, include domain.MarketMatch, file string) (*MarketListSource, error) {
+	s := &MarketListSource{exclude: exclude, include: include, file: file}
+	if file == "" {
+		list, err := domain.NewMarketList(exclude, include)
+		s.list = list
+		if err != nil {
+			return s, fmt.Errorf("scanner.NewMarketListSource: %w", err)
+		}
+		return s, nil
+	}
+	if err := s.load(); err != nil {
+		return s, fmt.Errorf("scanner.NewMarketListSource: %w", err)
+	}
+	return s, nil
+}
+
+// Reload relee el fichero si su fecha de modificación cambió. Si el fichero
+// nuevo es inválido se mantiene la lista anterior.
+func (s *MarketListSource) Reload() {
+	if s == nil || s.file == "" {
+		return
+	}
+	info, err := os.Stat(s.file)
+	if err != nil {
+		slog.Warn("market list: stat failed, keeping previous list", "file", s.file, "err", err)
+		return
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return
+	}
+	if err := s.load(); err != nil {
+		slog.Warn("market list: reload failed, keeping previous list", "file", s.file, "err", err)
+		return
+	}
+	slog.Info("market list reloaded", "file", s.file)
+}
+
+// load lee el fichero y sustituye la lista. Con patrones inválidos la lista
+// se sustituye solo en la carga inicial (sin lista previa).
+func (s *MarketListSource) load() error {
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return fmt.Errorf("stat %q: %w", s.file, err)
+	}
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		return fmt.Errorf("read %q: %w", s.file, err)
+	}
+	var f struct {
+		Exclude marketMatchFile `yaml:"exclude"`
+		Include marketMatchFile `yaml:"include"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("parse %q: %w", s.file, err)
+	}
+	list, err := domain.NewMarketList(
+		s.exclude.Merge(f.Exclude.toDomain()),
+		s.include.Merge(f.Include.toDomain()),
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if s.list == nil {
+			s.list = list
+		}
+		return fmt.Errorf("%q: %w", s.file, err)
+	}
+	s.list = list
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// Allows indica si m puede operarse. Un source nil lo permite todo.
+func (s *MarketListSource) Allows(m domain.Market) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list.Allows(m)
+}
+
+// allowedMarkets descarta los mercados que la lista no permite.
+func allowedMarkets(s *MarketListSource, markets []domain.Market) []domain.Market {
+	if s == nil {
+		return markets
+	}
+	out := make([]domain.Market, 0, len(markets))
+	for _, m := range markets {
+		if s.Allows(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
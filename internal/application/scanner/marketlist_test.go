@@ -0,0 +1,68 @@
+package scanner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketList_ExcludeBySlugConditionAndQuestion(t *testing.T) {
+	src, err := scanner.NewMarketListSource(domain.MarketMatch{
+		Slugs:        []string{"lakers-vs-celtics"},
+		ConditionIDs: []string{"0xDEAD"},
+		Questions:    []string{`\bbitcoin\b.*above`},
+	}, domain.MarketMatch{}, "")
+	require.NoError(t, err)
+
+	assert.False(t, src.Allows(domain.Market{Slug: "lakers-vs-celtics"}))
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xdead"}))
+	assert.False(t, src.Allows(domain.Market{Question: "Will BITCOIN close above $100k?"}))
+	assert.True(t, src.Allows(domain.Market{ConditionID: "0xok", Question: "Will it rain?"}))
+}
+
+func TestMarketList_IncludeOnlyAndExcludeWins(t *testing.T) {
+	src, err := scanner.NewMarketListSource(
+		domain.MarketMatch{ConditionIDs: []string{"0xb"}},
+		domain.MarketMatch{Questions: []string{"election"}},
+		"")
+	require.NoError(t, err)
+
+	assert.True(t, src.Allows(domain.Market{ConditionID: "0xa", Question: "Who wins the election?"}))
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xb", Question: "Election turnout above 60%?"}))
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xc", Question: "Will it rain?"}))
+}
+
+func TestMarketList_InvalidPattern(t *testing.T) {
+	_, err := scanner.NewMarketListSource(domain.MarketMatch{Questions: []string{"("}}, domain.MarketMatch{}, "")
+	assert.Error(t, err)
+}
+
+func TestMarketList_ReloadsFileWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("exclude:\n  condition_ids: [\"0xa\"]\n"), 0o644))
+
+	src, err := scanner.NewMarketListSource(domain.MarketMatch{}, domain.MarketMatch{}, path)
+	require.NoError(t, err)
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xa"}))
+	assert.True(t, src.Allows(domain.Market{ConditionID: "0xb"}))
+
+	require.NoError(t, os.WriteFile(path, []byte("exclude:\n  condition_ids: [\"0xb\"]\n"), 0o644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	src.Reload()
+	assert.True(t, src.Allows(domain.Market{ConditionID: "0xa"}))
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xb"}))
+
+	// Un fichero roto no borra la lista vigente.
+	require.NoError(t, os.WriteFile(path, []byte("exclude:\n  questions: [\"(\"]\n"), 0o644))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	src.Reload()
+	assert.False(t, src.Allows(domain.Market{ConditionID: "0xb"}))
+}
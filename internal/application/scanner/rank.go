@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// RankFunc ordena las oportunidades de RunOnce: devuelve true si a va antes que b.
+type RankFunc func(a, b domain.Opportunity) bool
+
+// Nombres de los rankings disponibles (flag --rank-by, scanner.rank_by).
+const (
+	RankVelocity  = "velocity"
+	RankReward    = "reward"
+	RankFillCost  = "fill-cost"
+	RankBreakEven = "breakeven"
+)
+
+// RankByVelocityScore es el ranking por defecto: primero la categoría (Gold <
+// Silver < Bronze) y luego CombinedScore, el P&L esperado con un fill al día.
+func RankByVelocityScore(a, b domain.Opportunity) bool {
+	if a.Category != b.Category {
+		return a.Category < b.Category
+	}
+	return a.CombinedScore > b.CombinedScore
+}
+
+// RankByDailyReward prioriza el mayor reward diario propio estimado.
+func RankByDailyReward(a, b domain.Opportunity) bool {
+	return a.YourDailyReward > b.YourDailyReward
+}
+
+// RankByFillCostAsc prioriza el menor coste por par lleno (negativo = el fill
+// deja dinero).
+func RankByFillCostAsc(a, b domain.Opportunity) bool {
+	return a.FillCostPerPair < b.FillCostPerPair
+}
+
+// RankByBreakEvenFillsDesc prioriza los mercados que aguantan más fills al
+// día antes de que su coste se coma el reward.
+func RankByBreakEvenFillsDesc(a, b domain.Opportunity) bool {
+	return a.BreakEvenFills > b.BreakEvenFills
+}
+
+// ParseRankBy devuelve el RankFunc de un nombre; vacío = RankByVelocityScore.
+func ParseRankBy(name string) (RankFunc, error) {
+	switch name {
+	case "", RankVelocity:
+		return RankByVelocityScore, nil
+	case RankReward:
+		return RankByDailyReward, nil
+	case RankFillCost:
+		return RankByFillCostAsc, nil
+	case RankBreakEven:
+		return RankByBreakEvenFillsDesc, nil
+	}
+	return nil, fmt.Errorf("scanner.ParseRankBy: unknown ranking %q (velocity|reward|fill-cost|breakeven)", name)
+}
+
+// rank ordena opps con less (nil = RankByVelocityScore). Es estable para que
+// los empates conserven el orden del análisis.
+func rank(opps []domain.Opportunity, less RankFunc) []domain.Opportunity {
+	if less == nil {
+		less = RankByVelocityScore
+	}
+	sort.SliceStable(opps, func(i, j int) bool {
+		return less(opps[i], opps[j])
+	})
+	return opps
+}
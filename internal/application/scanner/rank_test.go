@@ -0,0 +1,71 @@
+package scanner_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/domain/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRankBy(t *testing.T) {
+	opps := []domain.Opportunity{
+		{Market: domain.Market{ConditionID: "a"}, Category: domain.CategorySilver, CombinedScore: 5, YourDailyReward: 1, FillCostPerPair: 0.01, BreakEvenFills: 2},
+		{Market: domain.Market{ConditionID: "b"}, Category: domain.CategoryGold, CombinedScore: 1, YourDailyReward: 3, FillCostPerPair: 0.02, BreakEvenFills: 1},
+		{Market: domain.Market{ConditionID: "c"}, Category: domain.CategorySilver, CombinedScore: 2, YourDailyReward: 2, FillCostPerPair: -0.01, BreakEvenFills: 9},
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"", []string{"b", "a", "c"}},
+		{scanner.RankVelocity, []string{"b", "a", "c"}},
+		{scanner.RankReward, []string{"b", "c", "a"}},
+		{scanner.RankFillCost, []string{"c", "a", "b"}},
+		{scanner.RankBreakEven, []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			less, err := scanner.ParseRankBy(tt.name)
+			require.NoError(t, err)
+			ranked := append([]domain.Opportunity(nil), opps...)
+			sort.SliceStable(ranked, func(i, j int) bool { return less(ranked[i], ranked[j]) })
+			var got []string
+			for _, o := range ranked {
+				got = append(got, o.Market.ConditionID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := scanner.ParseRankBy("queue")
+	assert.Error(t, err)
+}
+
+func TestScanner_RunOnce_UsesRankFunc(t *testing.T) {
+	mp := &mockMarketProvider{markets: []domain.Market{
+		makeMarket("0xa", "yes1", "no1", 25.5, 0.04),
+		makeMarket("0xb", "yes2", "no2", 25.5, 0.04),
+	}}
+	books := makeBooks("yes1", "no1")
+	for id, b := range makeBooks("yes2", "no2") {
+		books[id] = b
+	}
+	cfg := scanner.Config{
+		Filter: scanner.FilterConfig{RequireQualifies: true},
+		RankFunc: func(a, b domain.Opportunity) bool {
+			return a.Market.ConditionID > b.Market.ConditionID
+		},
+	}
+	strat := strategy.NewRewardFarming(strategy.RewardFarmingConfig{OrderSize: 100})
+	s := scanner.New(cfg, mp, &mockBookProvider{books: books}, nil, &mockNotifier{}, strat)
+
+	opps, err := s.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, opps, 2)
+	assert.Equal(t, "0xb", opps[0].Market.ConditionID)
+}
@@ -0,0 +1,149 @@
+package scanner
+
+// replay.go — Grabación y replay de ciclos (scanner.record_dir, --replay).
+//
+// Recorder envuelve el scanner y guarda las oportunidades de cada ciclo
+// (books, rewards, scores) en un JSON por ciclo. Replay lee esos ficheros en
+// orden y los sirve como engine.ScannerService, así PaperEngine o LiveEngine
+// en dry-run repiten offline las decisiones sobre el mismo scan. Lo que el
+// engine lee fuera del scan (trades, reloj, storage) no se graba.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// recordNameLayout ordena los ficheros por nombre en el orden de los ciclos.
+const recordNameLayout = "20060102T150405.000Z"
+
+// ErrReplayDone lo devuelve Replay.RunOnce cuando ya sirvió todos los ciclos.
+var ErrReplayDone = errors.New("replay: no more recorded cycles")
+
+// recordedCycle es el contenido de cada fichero grabado.
+type recordedCycle struct {
+	ScannedAt     time.Time
+	Opportunities []recordedOpportunity
+}
+
+// recordedOpportunity guarda BreakEvenFills como puntero: encoding/json no
+// admite +Inf (fills gratis), que se graba como null.
+type recordedOpportunity struct {
+	domain.Opportunity
+	BreakEvenFills *float64
+}
+
+// Recorder es un engine.ScannerService que graba cada ciclo de src en dir.
+type Recorder struct {
+	src engine.ScannerService
+	dir string
+	seq int // desempata ciclos grabados en el mismo milisegundo
+}
+
+// NewRecorder crea el directorio si no existe.
+func NewRecorder(src engine.ScannerService, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("scanner.NewRecorder: %w", err)
+	}
+	return &Recorder{src: src, dir: dir}, nil
+}
+
+// RunOnce escanea y graba el resultado. Un fallo al grabar solo se registra:
+// no debe parar el engine.
+func (r *Recorder) RunOnce(ctx context.Context) ([]domain.Opportunity, error) {
+	opps, err := r.src.RunOnce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.write(opps, time.Now().UTC()); err != nil {
+		slog.Warn("scanner: record cycle", "dir", r.dir, "err", err)
+	}
+	return opps, nil
+}
+
+func (r *Recorder) write(opps []domain.Opportunity, at time.Time) error {
+	rec := recordedCycle{ScannedAt: at, Opportunities: make([]recordedOpportunity, 0, len(opps))}
+	for _, o := range opps {
+		ro := recordedOpportunity{Opportunity: o}
+		if !math.IsInf(o.BreakEvenFills, 0) && !math.IsNaN(o.BreakEvenFills) {
+			v := o.BreakEvenFills
+			ro.BreakEvenFills = &v
+		}
+		rec.Opportunities = append(rec.Opportunities, ro)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	r.seq++
+	path := filepath.Join(r.dir, fmt.Sprintf("%s-%06d.json", at.Format(recordNameLayout), r.seq))
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Replay es un engine.ScannerService que sirve, ciclo a ciclo, los ficheros
+// grabados por Recorder en orden cronológico.
+type Replay struct {
+	files []string
+	next  int
+}
+
+// NewReplay lista los ciclos grabados en dir.
+func NewReplay(dir string) (*Replay, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("scanner.NewReplay: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("scanner.NewReplay: no recorded cycles in %q", dir)
+	}
+	sort.Strings(files)
+	return &Replay{files: files}, nil
+}
+
+// RunOnce devuelve el siguiente ciclo grabado, o ErrReplayDone al terminar.
+func (r *Replay) RunOnce(_ context.Context) ([]domain.Opportunity, error) {
+	if r.next >= len(r.files) {
+		return nil, ErrReplayDone
+	}
+	path := r.files[r.next]
+	r.next++
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("scanner.Replay: %w", err)
+	}
+	var rec recordedCycle
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("scanner.Replay: %s: %w", filepath.Base(path), err)
+	}
+	opps := make([]domain.Opportunity, 0, len(rec.Opportunities))
+	for _, ro := range rec.Opportunities {
+		o := ro.Opportunity
+		o.BreakEvenFills = math.Inf(1)
+		if ro.BreakEvenFills != nil {
+			o.BreakEvenFills = *ro.BreakEvenFills
+		}
+		opps = append(opps, o)
+	}
+	slog.Debug("replay cycle", "file", filepath.Base(path), "scanned_at", rec.ScannedAt, "opportunities", len(opps))
+	return opps, nil
+}
+
+// Cycle devuelve el fichero del último ciclo servido y su posición (1..n) de
+// n, para seguir las decisiones paso a paso. Antes del primero devuelve "".
+func (r *Replay) Cycle() (file string, pos, n int) {
+	if r.next == 0 {
+		return "", 0, len(r.files)
+	}
+	return filepath.Base(r.files[r.next-1]), r.next, len(r.files)
+}
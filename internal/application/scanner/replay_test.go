@@ -0,0 +1,73 @@
+package scanner_test
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cycleSource devuelve un ciclo distinto en cada llamada.
+type cycleSource struct {
+	cycles [][]domain.Opportunity
+}
+
+func (c *cycleSource) RunOnce(context.Context) ([]domain.Opportunity, error) {
+	opps := c.cycles[0]
+	c.cycles = c.cycles[1:]
+	return opps, nil
+}
+
+func TestRecorderReplay_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cycles")
+	first := exportOpps()
+	first[0].ScannedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first[0].YesBook = makeBooks("yes1", "no1")["yes1"]
+	second := exportOpps()
+	second[0].Market.ConditionID = "0xc2"
+	second[0].BreakEvenFills = 4.5
+
+	rec, err := scanner.NewRecorder(&cycleSource{cycles: [][]domain.Opportunity{first, second}}, dir)
+	require.NoError(t, err)
+	for range 2 {
+		_, err := rec.RunOnce(context.Background())
+		require.NoError(t, err)
+	}
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	rp, err := scanner.NewReplay(dir)
+	require.NoError(t, err)
+	file, pos, n := rp.Cycle()
+	assert.Equal(t, "", file)
+	assert.Equal(t, 0, pos)
+	assert.Equal(t, 2, n)
+
+	got, err := rp.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+	assert.True(t, math.IsInf(got[0].BreakEvenFills, 1), "null vuelve a ser +Inf")
+
+	got, err = rp.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, second, got)
+	file, pos, _ = rp.Cycle()
+	assert.Equal(t, files[1].Name(), file)
+	assert.Equal(t, 2, pos)
+
+	_, err = rp.RunOnce(context.Background())
+	assert.ErrorIs(t, err, scanner.ErrReplayDone)
+}
+
+func TestNewReplay_EmptyDir(t *testing.T) {
+	_, err := scanner.NewReplay(t.TempDir())
+	assert.Error(t, err)
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"sort"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
@@ -17,6 +18,8 @@ type Config struct {
 	Filter          FilterConfig
 	AnalysisWorkers int // goroutines para análisis paralelo (0 = NumCPU*2)
 	DryRun          bool
+	Adaptive        AdaptiveIntervalConfig // intervalo entre ciclos de Run, paper y live (ver NextInterval)
+	RankFunc        RankFunc               // orden de las oportunidades (nil = RankByVelocityScore)
 }
 
 // Scanner es el orquestador principal del loop de escaneo.
@@ -29,6 +32,16 @@ type Scanner struct {
 	analyzer        *Analyzer
 	filter          *Filter
 	previousGoldIDs map[string]bool // Gold markets del ciclo anterior para alertas
+	fillHistory     fillHistory     // fills de los últimos ciclos (RecordFills)
+	activePositions int             // posiciones live abiertas (RecordActivePositions)
+	lastGold        int             // Gold del último scan
+	cycles          int             // scans completados
+	interval        time.Duration   // último intervalo devuelto por NextInterval
+	health          ports.HealthReporter
+
+	mu     sync.RWMutex         // protege last, lastAt (los lee la API HTTP) y filter (SetFilter al recargar config)
+	last   []domain.Opportunity // oportunidades del último ciclo correcto
+	lastAt time.Time
 }
 
 // New crea un Scanner con todas las dependencias inyectadas.
@@ -53,11 +66,28 @@ func New(
 	}
 }
 
-// SetFilter replaces the scanner's filter (used by live engine to widen criteria).
+// SetHealth hace que cada ciclo de scan reporte su resultado como latido
+// ports.HealthScan.
+func (s *Scanner) SetHealth(h ports.HealthReporter) {
+	s.health = h
+}
+
+// SetFilter replaces the scanner's filter (used by live engine to widen
+// criteria, and on config reload). Safe to call while Run is scanning: the
+// next cycle uses the new filter.
 func (s *Scanner) SetFilter(f *Filter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.filter = f
 }
 
+// currentFilter devuelve el filtro vigente.
+func (s *Scanner) currentFilter() *Filter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.filter
+}
+
 // Run ejecuta el loop de escaneo hasta que el contexto se cancele.
 // Si cfg.DryRun está activo, solo ejecuta un ciclo.
 func (s *Scanner) Run(ctx context.Context) error {
@@ -78,18 +108,19 @@ func (s *Scanner) Run(ctx context.Context) error {
 		return nil
 	}
 
-	ticker := time.NewTicker(s.cfg.ScanInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(s.NextInterval())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("scanner stopped")
 			return nil
-		case <-ticker.C:
+		case <-timer.C:
 			if err := s.runCycle(ctx); err != nil {
 				slog.Error("scan cycle failed", "err", err)
 			}
+			timer.Reset(s.NextInterval())
 		}
 	}
 }
@@ -132,26 +163,82 @@ func (s *Scanner) runCycle(ctx context.Context) error {
 }
 
 // cycle hace fetch → concurrent analyze → filter → rank y devuelve las oportunidades.
-func (s *Scanner) cycle(ctx context.Context) ([]domain.Opportunity, error) {
+func (s *Scanner) cycle(ctx context.Context) (_ []domain.Opportunity, err error) {
+	if s.health != nil {
+		defer func() { s.health.Beat(ports.HealthScan, err) }()
+	}
+	start := time.Now()
 	markets, err := s.markets.FetchSamplingMarkets(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("scanner.cycle: fetch markets: %w", err)
 	}
+	marketsDone := time.Now()
+
+	// La lista de mercados se aplica antes de pedir books: los excluidos no
+	// cuestan llamadas a la API.
+	s.cfg.Filter.Markets.Reload()
+	markets = allowedMarkets(s.cfg.Filter.Markets, markets)
 
 	tokenIDs := extractTokenIDs(markets)
 	books, err := s.books.FetchOrderBooks(ctx, tokenIDs)
 	if err != nil {
 		return nil, fmt.Errorf("scanner.cycle: fetch books: %w", err)
 	}
+	booksDone := time.Now()
+	s.invalidateMissingMarkets(markets, books)
 
 	// Análisis paralelo: reduce tiempo de ciclo de ~20s a ~3-5s
 	opps := analyzeMarketsConcurrent(ctx, s.analyzer, markets, books, s.cfg.AnalysisWorkers)
 
-	filtered := s.filter.Apply(opps)
-	ranked := rankByScore(filtered)
+	filtered := s.currentFilter().Apply(opps)
+	ranked := rank(filtered, s.cfg.RankFunc)
+	s.lastGold, _ = countCategories(ranked)
+	s.cycles++
+	s.storeLast(ranked, start)
+
+	slog.Debug("scan cycle timing",
+		"markets", len(markets),
+		"fetch_markets", marketsDone.Sub(start).Round(time.Millisecond),
+		"fetch_books", booksDone.Sub(marketsDone).Round(time.Millisecond),
+		"analyze", time.Since(booksDone).Round(time.Millisecond),
+	)
 	return ranked, nil
 }
 
+// storeLast guarda una copia del ranking: el engine paper lo reordena in situ.
+func (s *Scanner) storeLast(opps []domain.Opportunity, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = slices.Clone(opps)
+	s.lastAt = at
+}
+
+// LastOpportunities devuelve las oportunidades del último ciclo correcto y
+// cuándo empezó. Es seguro llamarlo desde otra goroutine (httpapi); antes
+// del primer ciclo devuelve nil y el tiempo cero.
+func (s *Scanner) LastOpportunities() ([]domain.Opportunity, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.last), s.lastAt
+}
+
+// invalidateMissingMarkets avisa al provider, si cachea la lista de mercados,
+// de los que ya no tienen orderbook (cerrados o retirados de rewards desde el
+// último refresco).
+func (s *Scanner) invalidateMissingMarkets(markets []domain.Market, books map[string]domain.OrderBook) {
+	inv, ok := s.markets.(ports.MarketInvalidator)
+	if !ok {
+		return
+	}
+	var missing []string
+	for _, m := range markets {
+		if _, _, ok := getBooksForMarket(m, books); !ok {
+			missing = append(missing, m.ConditionID)
+		}
+	}
+	inv.InvalidateMarkets(missing)
+}
+
 // emitGoldAlerts registra alertas para mercados Gold nuevos (no vistos en el ciclo anterior).
 // Si además hay true arbitrage (gap > 0), la alerta usa nivel ERROR para máxima visibilidad.
 func (s *Scanner) emitGoldAlerts(opps []domain.Opportunity) {
@@ -213,19 +300,6 @@ func getBooksForMarket(m domain.Market, books map[string]domain.OrderBook) (yes,
 	return yes, no, okYes && okNo
 }
 
-// rankByScore ordena por CombinedScore descendente:
-// Gold primero (arb + reward), luego Silver (reward puro), etc.
-func rankByScore(opps []domain.Opportunity) []domain.Opportunity {
-	sort.Slice(opps, func(i, j int) bool {
-		// Primero por categoría (Gold < Silver < Bronze), luego por CombinedScore
-		if opps[i].Category != opps[j].Category {
-			return opps[i].Category < opps[j].Category
-		}
-		return opps[i].CombinedScore > opps[j].CombinedScore
-	})
-	return opps
-}
-
 // countCategories cuenta oportunidades Gold y Silver.
 func countCategories(opps []domain.Opportunity) (gold, silver int) {
 	for _, o := range opps {
@@ -25,6 +25,16 @@ func (m *mockMarketProvider) FetchSamplingMarkets(_ context.Context) ([]domain.M
 	return m.markets, m.err
 }
 
+// cachingMarketProvider records the markets the scanner reports as missing.
+type cachingMarketProvider struct {
+	mockMarketProvider
+	missing [][]string
+}
+
+func (m *cachingMarketProvider) InvalidateMarkets(missing []string) {
+	m.missing = append(m.missing, missing)
+}
+
 type mockBookProvider struct {
 	books map[string]domain.OrderBook
 	err   error
@@ -150,6 +160,52 @@ func TestScanner_RunOnce_FiltersHighSpread(t *testing.T) {
 	assert.Empty(t, opps, "debe filtrar mercado con spread > maxSpread")
 }
 
+func TestScanner_RunOnce_SkipsExcludedMarkets(t *testing.T) {
+	markets := []domain.Market{
+		makeMarket("0xabc", "yes1", "no1", 25.5, 0.04),
+		makeMarket("0xdef", "yes2", "no2", 25.5, 0.04),
+	}
+	books := makeBooks("yes1", "no1")
+	for k, v := range makeBooks("yes2", "no2") {
+		books[k] = v
+	}
+	list, err := scanner.NewMarketListSource(domain.MarketMatch{ConditionIDs: []string{"0xdef"}}, domain.MarketMatch{}, "")
+	require.NoError(t, err)
+
+	strat := strategy.NewRewardFarming(strategy.RewardFarmingConfig{OrderSize: 100, FeeRate: 0.02, FillsPerDay: 2.0, GoldMinReward: 0.01})
+	s := scanner.New(scanner.Config{Filter: scanner.FilterConfig{RequireQualifies: true, Markets: list}},
+		&mockMarketProvider{markets: markets}, &mockBookProvider{books: books}, nil, &mockNotifier{}, strat)
+	opps, err := s.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, opps, 1)
+	assert.Equal(t, "0xabc", opps[0].Market.ConditionID)
+}
+
+func TestScanner_RunOnce_SkipsCrossedBooks(t *testing.T) {
+	markets := []domain.Market{
+		makeMarket("0xabc", "yes1", "no1", 25.5, 0.04),
+		makeMarket("0xdef", "yes2", "no2", 25.5, 0.04),
+	}
+	books := makeBooks("yes1", "no1")
+	for k, v := range makeBooks("yes2", "no2") {
+		books[k] = v
+	}
+	// YES de 0xdef con bid por encima del ask: snapshot cruzado.
+	books["yes2"] = domain.OrderBook{
+		TokenID: "yes2",
+		Bids:    []domain.BookEntry{{Price: 0.73, Size: 150}},
+		Asks:    []domain.BookEntry{{Price: 0.72, Size: 200}},
+	}
+
+	s := newTestScanner(&mockMarketProvider{markets: markets}, &mockBookProvider{books: books}, &mockNotifier{}, nil)
+	opps, err := s.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, opps, 1)
+	assert.Equal(t, "0xabc", opps[0].Market.ConditionID)
+}
+
 func TestScanner_RunOnce_MarketProviderError(t *testing.T) {
 	mp := &mockMarketProvider{err: errors.New("API down")}
 	bp := &mockBookProvider{}
@@ -171,6 +227,33 @@ func TestScanner_RunOnce_BookProviderError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestScanner_LastOpportunities_KeepsLastSuccessfulCycle(t *testing.T) {
+	market := makeMarket("0xabc", "yes1", "no1", 25.5, 0.04)
+	mp := &mockMarketProvider{markets: []domain.Market{market}}
+	bp := &mockBookProvider{books: makeBooks("yes1", "no1")}
+	s := newTestScanner(mp, bp, &mockNotifier{}, nil)
+
+	last, at := s.LastOpportunities()
+	assert.Empty(t, last)
+	assert.True(t, at.IsZero())
+
+	opps, err := s.RunOnce(context.Background())
+	require.NoError(t, err)
+	opps[0].Market.ConditionID = "mutated by caller"
+
+	last, at = s.LastOpportunities()
+	require.Len(t, last, 1)
+	assert.Equal(t, "0xabc", last[0].Market.ConditionID, "cache is a copy")
+	assert.False(t, at.IsZero())
+
+	// Un ciclo fallido no borra el último resultado.
+	mp.err = errors.New("API down")
+	_, err = s.RunOnce(context.Background())
+	require.Error(t, err)
+	last, _ = s.LastOpportunities()
+	assert.Len(t, last, 1)
+}
+
 func TestScanner_RunOnce_RankedByCombinedScore(t *testing.T) {
 	// El mercado con mayor CombinedScore va primero.
 	// Sin arbitraje, CombinedScore ≈ YourDailyReward → m2 (pool=100) antes que m1 (pool=10).
@@ -198,3 +281,18 @@ func TestScanner_RunOnce_RankedByCombinedScore(t *testing.T) {
 	assert.GreaterOrEqual(t, opps[0].YourDailyReward, opps[1].YourDailyReward,
 		"m2 con pool mayor debe ir antes")
 }
+
+func TestScanner_RunOnce_ReportsMarketsWithoutBooks(t *testing.T) {
+	mp := &cachingMarketProvider{mockMarketProvider: mockMarketProvider{markets: []domain.Market{
+		makeMarket("0xabc", "yes1", "no1", 25.5, 0.04),
+		makeMarket("0xgone", "yes9", "no9", 25.5, 0.04),
+	}}}
+	bp := &mockBookProvider{books: makeBooks("yes1", "no1")}
+
+	opps, err := newTestScanner(mp, bp, &mockNotifier{}, &mockStorage{}).RunOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, opps, 1)
+	require.Len(t, mp.missing, 1)
+	assert.Equal(t, []string{"0xgone"}, mp.missing[0])
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// WatchMarketUpdates consume los cambios de estado de stream hasta que el
+// canal se cierre o se cancele ctx; se lanza en su propia goroutine junto a
+// Run o al loop de paper.
+//
+// Un mercado resuelto o cerrado expira en el momento sus órdenes paper
+// abiertas (paper.ExpirePaperOrders) en vez de esperar al siguiente
+// expireResolvedAndNearEnd: un fill entre la resolución y el siguiente scan
+// sería una pata sin pareja. Un cambio de reward invalida la caché de
+// mercados para que el siguiente scan lo recoja. paper puede ser nil.
+func (s *Scanner) WatchMarketUpdates(ctx context.Context, stream ports.MarketUpdateStream, paper ports.PaperStorage) {
+	updates := stream.Updates()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			s.applyMarketUpdate(ctx, u, paper)
+		}
+	}
+}
+
+func (s *Scanner) applyMarketUpdate(ctx context.Context, u domain.MarketUpdate, paper ports.PaperStorage) {
+	switch u.Kind {
+	case domain.MarketUpdateResolved, domain.MarketUpdateClosed:
+		slog.Info("market update: expiring paper orders",
+			"kind", u.Kind, "condition", u.ConditionID, "winner", u.WinningOutcome)
+		if paper != nil {
+			if err := paper.ExpirePaperOrders(ctx, u.ConditionID, domain.CloseResolved); err != nil {
+				slog.Warn("market update: error expiring paper orders", "condition", u.ConditionID, "err", err)
+			}
+		}
+		if inv, ok := s.markets.(ports.MarketInvalidator); ok {
+			inv.InvalidateMarkets([]string{u.ConditionID})
+		}
+	case domain.MarketUpdateReward:
+		slog.Info("market update: reward rate changed",
+			"condition", u.ConditionID, "daily_rate", u.DailyRate)
+		if inv, ok := s.markets.(ports.MarketInvalidator); ok {
+			inv.InvalidateMarkets([]string{u.ConditionID})
+		}
+	}
+}
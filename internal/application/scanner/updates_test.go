@@ -0,0 +1,50 @@
+package scanner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/adapters/storage"
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chanUpdateStream chan domain.MarketUpdate
+
+func (c chanUpdateStream) Updates() <-chan domain.MarketUpdate { return c }
+
+func TestWatchMarketUpdates_ExpiresResolvedPaperOrders(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.NewSQLiteStorage(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.ApplyPaperSchema(ctx))
+
+	now := time.Now().UTC()
+	for _, o := range []domain.VirtualOrder{
+		{ID: "r-yes", ConditionID: "0xresolved", Side: "YES", PairID: "p1"},
+		{ID: "r-no", ConditionID: "0xresolved", Side: "NO", PairID: "p1"},
+		{ID: "k-yes", ConditionID: "0xkept", Side: "YES", PairID: "p2"},
+	} {
+		o.BidPrice, o.Size, o.PlacedAt, o.Status = 0.45, 10, now, domain.PaperStatusOpen
+		require.NoError(t, db.SavePaperOrder(ctx, o))
+	}
+
+	mp := &cachingMarketProvider{}
+	s := scanner.New(scanner.Config{}, mp, nil, nil, nil, nil)
+	stream := make(chanUpdateStream, 2)
+	stream <- domain.MarketUpdate{ConditionID: "0xresolved", Kind: domain.MarketUpdateResolved, At: now}
+	stream <- domain.MarketUpdate{ConditionID: "0xkept", Kind: domain.MarketUpdateReward, DailyRate: 50, At: now}
+	close(stream)
+
+	s.WatchMarketUpdates(ctx, stream, db)
+
+	open, err := db.GetOpenPaperOrders(ctx)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, "k-yes", open[0].ID, "only the resolved market is expired")
+	assert.Equal(t, [][]string{{"0xresolved"}, {"0xkept"}}, mp.missing, "both updates refresh the market cache")
+}
@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+// BacktestDay agrega los fills simulados y el P&L de un día UTC del backtest.
+type BacktestDay struct {
+	Date          time.Time
+	Markets       int     // mercados activos (sin resolver) ese día
+	FillsYES      int     // fills simulados en el lado YES
+	FillsNO       int     // fills simulados en el lado NO
+	CompletePairs int     // min(FillsYES, FillsNO) sumado por mercado
+	Reward        float64 // reward estimado acumulado ese día
+	FillCost      float64 // coste de fills ese día
+	PnL           float64 // Reward - FillCost
+}
+
+// Fills devuelve el total de fills del día (ambos lados).
+func (d BacktestDay) Fills() int {
+	return d.FillsYES + d.FillsNO
+}
+
+// BacktestMarket resume el comportamiento de un mercado durante la ventana.
+type BacktestMarket struct {
+	ConditionID string
+	Question    string
+	ResolvedAt  *time.Time // no nil si el mercado se resolvió dentro de la ventana
+	Trades      int        // trades históricos usados en la simulación
+	FillsYES    int
+	FillsNO     int
+	PnL         float64
+
+	// DailyReward es el reward estimado por día UTC en que el mercado estuvo abierto.
+	DailyReward map[time.Time]float64
+	// EstimatedReward y ActualReward cubren solo los días con payout consultado.
+	EstimatedReward float64
+	ActualReward    float64
+
+	// Modelo de cola: fills/día (YES+NO) que predice orderSize/(orderSize +
+	// cola) sobre el volumen vendedor frente a los que salen de reproducir los
+	// trades. PredictionError es (predichos - reales) / reales; 0 sin fills reales.
+	PredictedFillsPerDay float64
+	FillsBothPerDay      float64
+	PredictionError      float64
+}
+
+// BacktestResult es el resultado de reproducir trades históricos en una ventana.
+type BacktestResult struct {
+	From      time.Time
+	To        time.Time
+	OrderSize float64
+	// Historical indica un backtest sobre mercados pasados con los precios
+	// guardados por Gamma: no hay trades, el coste asume 1 fill/día.
+	Historical bool
+	Days       []BacktestDay
+	Markets    []BacktestMarket
+
+	TotalFills    int
+	CompletePairs int
+	TotalReward   float64
+	TotalFillCost float64
+	NetPnL        float64
+
+	// RewardDays cuenta los días cuyo payout real se consultó (0 = sin datos).
+	// EstimatedReward es el reward del modelo en esos mismos días y
+	// ActualReward lo que Polymarket pagó por los mercados reproducidos.
+	RewardDays      int
+	EstimatedReward float64
+	ActualReward    float64
+
+	// Suma de fills/día predichos y reales de todos los mercados, y el error
+	// relativo del modelo de cola sobre ese total: positivo si sobreestima.
+	PredictedFillsPerDay   float64
+	FillsBothPerDay        float64
+	OverallQueueModelError float64
+}
+
+// WindowDays devuelve la duración de la ventana en días.
+func (r BacktestResult) WindowDays() float64 {
+	return r.To.Sub(r.From).Hours() / 24
+}
+
+// RewardDiscrepancy devuelve ActualReward - EstimatedReward: negativo si el
+// modelo sobreestima lo que paga el pool.
+func (r BacktestResult) RewardDiscrepancy() float64 {
+	return r.ActualReward - r.EstimatedReward
+}
+
+// QueueModelError devuelve (predicted - actual) / actual, o 0 si no hubo
+// fills reales con los que comparar.
+func QueueModelError(predicted, actual float64) float64 {
+	if actual <= 0 {
+		return 0
+	}
+	return (predicted - actual) / actual
+}
+
+// ProfitableDays cuenta los días con P&L positivo.
+func (r BacktestResult) ProfitableDays() int {
+	n := 0
+	for _, d := range r.Days {
+		if d.PnL > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Verdict resume la ventana completa: PROFITABLE si el P&L neto es positivo y
+// al menos la mitad de los días fueron rentables, MIXED si solo se cumple una
+// de las dos condiciones, LOSING en otro caso.
+func (r BacktestResult) Verdict() string {
+	if len(r.Days) == 0 {
+		return "NO DATA"
+	}
+	netOK := r.NetPnL > 0
+	daysOK := r.ProfitableDays()*2 >= len(r.Days)
+	switch {
+	case netOK && daysOK:
+		return "PROFITABLE"
+	case netOK || daysOK:
+		return "MIXED"
+	default:
+		return "LOSING"
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "math"
+
+// BalanceReconciliation compares the USDC the wallets actually hold with the
+// balance the live books imply, to catch accounting drift.
+type BalanceReconciliation struct {
+	WalletBalance  float64 // USDC reported by the wallets
+	SkippedWallets int     // wallets whose balance could not be read
+	InitialCapital float64
+
+	MergeReceipts float64 // USDC received from successful merges
+	Merges        int
+	MergeProfit   float64 // merge spread net of the estimated gas
+	GasSpent      float64 // gas of successful merges; paid in POL, not USDC
+	FailedMerges  int     // failed merge submissions recorded in live_merges
+	RealizedPnL   float64 // result of selling back one-sided fills
+	RewardsPaid   float64 // maker rewards paid by Polymarket
+
+	RestingBids   float64 // unmatched USDC of OPEN and PARTIAL bids; still in the wallet
+	LockedPartial float64 // PARTIAL bids: fills whose tokens are not merged yet
+	LockedFilled  float64 // FILLED legs whose tokens are not merged yet
+
+	Tolerance float64 // unexplained drift above this is flagged
+}
+
+// Locked is the USDC spent on inventory not merged back yet. Resting bids are
+// not locked: their USDC stays in the wallet until they fill.
+func (r BalanceReconciliation) Locked() float64 {
+	return r.LockedPartial + r.LockedFilled
+}
+
+// Expected is the USDC the books say the wallets should hold.
+func (r BalanceReconciliation) Expected() float64 {
+	return r.InitialCapital + r.MergeProfit + r.RealizedPnL + r.RewardsPaid - r.Locked()
+}
+
+// Delta is the wallet balance minus the expected balance.
+func (r BalanceReconciliation) Delta() float64 {
+	return r.WalletBalance - r.Expected()
+}
+
+// Unexplained is the part of Delta that gas does not account for: merge
+// profit is booked net of gas, but gas is paid in POL, so the wallet holds
+// GasSpent more USDC than the books.
+func (r BalanceReconciliation) Unexplained() float64 {
+	return r.Delta() - r.GasSpent
+}
+
+// Flagged reports a drift larger than Tolerance.
+func (r BalanceReconciliation) Flagged() bool {
+	return math.Abs(r.Unexplained()) > r.Tolerance
+}
+
+// TradeableBalance splits a wallet's USDC into what new orders can really use:
+// the exchange can only pull up to the allowance, and USDC backing our resting
+// bids stays in the wallet until they fill or are cancelled.
+type TradeableBalance struct {
+	Wallet         float64 // USDC.e held by the wallet
+	Allowance      float64 // USDC.e the exchange contracts may spend
+	LockedInOrders float64 // unmatched USDC of our open BUY orders
+}
+
+// Available is the USDC free for new orders.
+func (b TradeableBalance) Available() float64 {
+	return math.Max(math.Min(b.Wallet, b.Allowance)-b.LockedInOrders, 0)
+}
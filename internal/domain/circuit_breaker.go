@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+// Circuit breaker trip reasons, shown in reports and the status API.
+const (
+	BreakerReasonLosses   = "consecutive losses"
+	BreakerReasonDrawdown = "max drawdown exceeded"
+	BreakerReasonBalance  = "low USDC balance"
+)
+
+// CircuitBreaker tracks realized results and pauses trading after a run of
+// losses (temporary cooldown) or once cumulative P&L falls below MaxDrawdown
+// (hard stop until Reset).
+type CircuitBreaker struct {
+	ConsecutiveLosses int
+	MaxLosses         int // losses in a row that start a cooldown; 0 = disabled
+	CooldownUntil     time.Time
+	CooldownDuration  time.Duration
+	TotalPnL          float64
+	MaxDrawdown       float64 // negative dollar amount threshold; 0 = disabled
+	Triggered         bool
+	TriggeredReason   string
+}
+
+// IsOpen returns true if trading is allowed: the breaker has not tripped on
+// drawdown and no loss cooldown is running.
+func (cb *CircuitBreaker) IsOpen() bool {
+	return cb.isOpenAt(time.Now())
+}
+
+func (cb *CircuitBreaker) isOpenAt(now time.Time) bool {
+	if cb.Triggered {
+		return false
+	}
+	return !now.Before(cb.CooldownUntil)
+}
+
+// RecordLoss records a negative result and may trip the breaker: MaxLosses
+// in a row start a cooldown, and TotalPnL below MaxDrawdown stops trading.
+func (cb *CircuitBreaker) RecordLoss(loss float64) {
+	cb.recordLossAt(loss, time.Now())
+}
+
+func (cb *CircuitBreaker) recordLossAt(loss float64, now time.Time) {
+	cb.ConsecutiveLosses++
+	cb.TotalPnL += loss
+	if cb.MaxLosses > 0 && cb.ConsecutiveLosses >= cb.MaxLosses {
+		cb.CooldownUntil = now.Add(cb.CooldownDuration)
+		cb.ConsecutiveLosses = 0
+		cb.TriggeredReason = BreakerReasonLosses
+	}
+	if cb.MaxDrawdown < 0 && cb.TotalPnL < cb.MaxDrawdown {
+		cb.Triggered = true
+		cb.TriggeredReason = BreakerReasonDrawdown
+	}
+}
+
+// Pause starts a cooldown of CooldownDuration for reason, as a loss streak
+// does, without touching the loss counters or TotalPnL.
+func (cb *CircuitBreaker) Pause(reason string) {
+	cb.pauseAt(reason, time.Now())
+}
+
+func (cb *CircuitBreaker) pauseAt(reason string, now time.Time) {
+	cb.CooldownUntil = now.Add(cb.CooldownDuration)
+	cb.TriggeredReason = reason
+}
+
+// RecordWin resets the consecutive loss counter and adds profit to TotalPnL.
+func (cb *CircuitBreaker) RecordWin(profit float64) {
+	cb.ConsecutiveLosses = 0
+	cb.TotalPnL += profit
+}
+
+// Reset clears the trip state (drawdown stop, cooldown and loss streak) so
+// trading can resume. TotalPnL is kept: a further loss while it is still
+// below MaxDrawdown trips the breaker again.
+func (cb *CircuitBreaker) Reset() {
+	cb.ConsecutiveLosses = 0
+	cb.CooldownUntil = time.Time{}
+	cb.Triggered = false
+	cb.TriggeredReason = ""
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_Trips(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		results    []float64
+		wantOpen   bool
+		wantReason string
+		wantStreak int
+	}{
+		{name: "losses below the limit", results: []float64{-1, -1}, wantOpen: true, wantStreak: 2},
+		{name: "consecutive losses start a cooldown", results: []float64{-1, -1, -1}, wantOpen: false, wantReason: BreakerReasonLosses},
+		{name: "a win resets the streak", results: []float64{-1, -1, 0.5, -1}, wantOpen: true, wantStreak: 1},
+		{name: "drawdown stops trading", results: []float64{-6, 0.5, -5}, wantOpen: false, wantReason: BreakerReasonDrawdown, wantStreak: 1},
+		{name: "drawdown wins over a cooldown", results: []float64{-4, -4, -4}, wantOpen: false, wantReason: BreakerReasonDrawdown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := CircuitBreaker{MaxLosses: 3, CooldownDuration: 30 * time.Minute, MaxDrawdown: -10}
+			for _, r := range tt.results {
+				if r < 0 {
+					cb.recordLossAt(r, now)
+				} else {
+					cb.RecordWin(r)
+				}
+			}
+			assert.Equal(t, tt.wantOpen, cb.isOpenAt(now))
+			assert.Equal(t, tt.wantReason, cb.TriggeredReason)
+			assert.Equal(t, tt.wantStreak, cb.ConsecutiveLosses)
+		})
+	}
+}
+
+func TestCircuitBreaker_CooldownExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		after    time.Duration
+		wantOpen bool
+	}{
+		{name: "during cooldown", after: 29 * time.Minute, wantOpen: false},
+		{name: "cooldown just ended", after: 30 * time.Minute, wantOpen: true},
+		{name: "long after cooldown", after: 2 * time.Hour, wantOpen: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := CircuitBreaker{MaxLosses: 1, CooldownDuration: 30 * time.Minute}
+			cb.recordLossAt(-1, now)
+			assert.Equal(t, tt.wantOpen, cb.isOpenAt(now.Add(tt.after)))
+		})
+	}
+}
+
+func TestCircuitBreaker_DrawdownIgnoresCooldownExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cb := CircuitBreaker{MaxLosses: 3, CooldownDuration: time.Minute, MaxDrawdown: -5}
+	cb.recordLossAt(-6, now)
+	assert.False(t, cb.isOpenAt(now.Add(24*time.Hour)), "a drawdown stop needs Reset")
+
+	cb.Reset()
+	assert.True(t, cb.isOpenAt(now))
+	assert.Empty(t, cb.TriggeredReason)
+	assert.InDelta(t, -6, cb.TotalPnL, 1e-9, "Reset keeps the tracked P&L")
+
+	cb.recordLossAt(-0.1, now)
+	assert.False(t, cb.isOpenAt(now), "still below the drawdown after Reset")
+}
+
+func TestCircuitBreaker_ZeroLimitsDisabled(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var cb CircuitBreaker
+	cb.recordLossAt(-1, now)
+	assert.True(t, cb.isOpenAt(now))
+}
+
+func TestCircuitBreaker_PauseKeepsLossCounters(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cb := CircuitBreaker{MaxLosses: 3, CooldownDuration: 30 * time.Minute}
+	cb.recordLossAt(-1, now)
+
+	cb.pauseAt(BreakerReasonBalance, now)
+	assert.False(t, cb.isOpenAt(now.Add(29*time.Minute)))
+	assert.True(t, cb.isOpenAt(now.Add(30*time.Minute)))
+	assert.Equal(t, BreakerReasonBalance, cb.TriggeredReason)
+	assert.Equal(t, 1, cb.ConsecutiveLosses)
+	assert.InDelta(t, -1, cb.TotalPnL, 1e-9)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CloseReason records why an engine retired an order before it filled or
+// merged. It is stored with the order (close_reason) so the reports can say
+// why pairs were killed, not just how many.
+type CloseReason string
+
+const (
+	CloseStale       CloseReason = "stale"       // no fills after StaleHours
+	CloseSpread      CloseReason = "spread"      // fill cost turned positive
+	CloseCompetition CloseReason = "competition" // bid depth spiked past CompetitionMult
+	CloseNearEnd     CloseReason = "near_end"    // too close to resolution
+	CloseResolved    CloseReason = "resolved"    // market resolved, closed or delisted
+	CloseUnwind      CloseReason = "unwind"      // one-legged pair flattened by a SELL
+	CloseDryRun      CloseReason = "dry_run"     // dry-run placement, never sent
+	CloseShutdown    CloseReason = "shutdown"    // cancelled on exit
+)
+
+// NoReentry is the cooldown end of a market excluded for good.
+var NoReentry = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// CooldownUntil returns until when a market whose pair was retired for r at
+// closedAt must not be re-entered. Rotations and cancellations block it for
+// cooldown; a market retired near its end only gets closer to it, so it is
+// excluded for good (NoReentry). Zero when r does not block re-entry.
+func (r CloseReason) CooldownUntil(closedAt time.Time, cooldown time.Duration) time.Time {
+	switch r {
+	case CloseNearEnd:
+		return NoReentry
+	case CloseStale, CloseSpread, CloseCompetition, CloseResolved:
+		return closedAt.Add(cooldown)
+	}
+	return time.Time{}
+}
+
+// FormatCloseReasons renders per-reason pair counts as "stale 12, spread 5",
+// most frequent first. Empty when there are none.
+func FormatCloseReasons(counts map[CloseReason]int) string {
+	reasons := make([]CloseReason, 0, len(counts))
+	for r, n := range counts {
+		if n > 0 {
+			reasons = append(reasons, r)
+		}
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	parts := make([]string, len(reasons))
+	for i, r := range reasons {
+		parts[i] = fmt.Sprintf("%s %d", r, counts[r])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// FillLatency es la distribución del tiempo de placed_at a filled_at de las
+// órdenes de entrada que se llenaron (FILLED o MERGED), para ajustar los
+// umbrales de rotación. Los percentiles son de rango más cercano.
+type FillLatency struct {
+	Count  int
+	Min    time.Duration
+	Median time.Duration
+	P90    time.Duration
+	Max    time.Duration
+}
+
+// FillLatencyStats separa la distribución por lado.
+type FillLatencyStats struct {
+	All FillLatency
+	Yes FillLatency
+	No  FillLatency
+}
+
+// NewFillLatency resume durations (en cualquier orden). Vacío si no hay.
+func NewFillLatency(durations []time.Duration) FillLatency {
+	if len(durations) == 0 {
+		return FillLatency{}
+	}
+	d := append([]time.Duration(nil), durations...)
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	rank := func(p float64) time.Duration {
+		return d[max(0, int(math.Ceil(p*float64(len(d))))-1)]
+	}
+	return FillLatency{
+		Count:  len(d),
+		Min:    d[0],
+		Median: rank(0.5),
+		P90:    rank(0.9),
+		Max:    d[len(d)-1],
+	}
+}
@@ -1,6 +1,13 @@
 package domain
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
 
 // LiveOrderStatus represents the lifecycle of a real order on Polymarket CLOB.
 type LiveOrderStatus string
@@ -12,6 +19,9 @@ const (
 	LiveStatusCancelled LiveOrderStatus = "CANCELLED"
 	LiveStatusExpired   LiveOrderStatus = "EXPIRED"
 	LiveStatusMerged    LiveOrderStatus = "MERGED"
+	LiveStatusFlattened LiveOrderStatus = "FLATTENED" // filled leg sold back after a stale partial
+	LiveStatusPending   LiveOrderStatus = "PENDING"   // saved before the POST; CLOB outcome not yet known
+	LiveStatusRedeemed  LiveOrderStatus = "REDEEMED"  // held through resolution and redeemed on-chain
 )
 
 // LiveOrder is a real order placed on Polymarket CLOB.
@@ -22,7 +32,8 @@ type LiveOrder struct {
 	TokenID       string
 	Side          string          // "YES" or "NO"
 	BidPrice      float64
-	Size          float64         // USDC total
+	Size          float64         // USDC total: SizeShares × BidPrice
+	SizeShares    float64         // token shares signed on the CLOB; 0 for orders placed before it
 	FilledSize    float64         // USDC filled so far
 	PlacedAt      time.Time
 	Status        LiveOrderStatus
@@ -36,6 +47,130 @@ type LiveOrder struct {
 	MergedAt      *time.Time
 	NegRisk       bool            // whether the market uses NegRisk adapter
 	CompetitionAt float64         // competition level at placement (for stale detection)
+	OrderSide     string          // "BUY" (entry bid) or "SELL" (unwind of a filled leg)
+	RealizedPnL   float64         // set when an unwind closes the position
+	WalletAddress string          // funding wallet; "" = primary (single-wallet setups)
+	Shadow        bool            // dry-run order: logged and stored, never sent to the CLOB
+	ExpiresAt     time.Time       // GTD expiry on the CLOB; zero = GTC
+	MergedSize    float64         // USDC of FilledSize already merged by partial merges
+	PlacementKey  string          // idempotency key from PlacementKey; "" for orders placed before it
+	Remainder     float64         // shares left unhedged when the rest of the pair merged
+	CloseReason   CloseReason     // why the engine cancelled or unwound it; "" if not recorded
+	ClosedAt      *time.Time
+}
+
+// IsSell reports whether the order unwinds a filled leg instead of entering a pair.
+func (o LiveOrder) IsSell() bool {
+	return o.OrderSide == "SELL"
+}
+
+// PlacementKey is the client-side idempotency key of one leg of a pair:
+// the same pair, side and price always give the same key, so a retried or
+// recovered placement can be recognised instead of sent twice.
+func PlacementKey(pairID, side string, price float64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%.4f", pairID, side, price)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// UnmergedSize is the USDC filled whose tokens are still held (not merged).
+func (o LiveOrder) UnmergedSize() float64 {
+	return o.FilledSize - o.MergedSize
+}
+
+// SharesFor converts usdc of this order's cost into token shares. It uses
+// the signed SizeShares when known: re-deriving usdc / BidPrice overstates
+// what the CLOB floored the order to.
+func (o LiveOrder) SharesFor(usdc float64) float64 {
+	if o.SizeShares > 0 && o.Size > 0 {
+		return usdc * o.SizeShares / o.Size
+	}
+	if o.BidPrice <= 0 {
+		return 0
+	}
+	return usdc / o.BidPrice
+}
+
+// CostOf is the USDC cost of shares of this order, the inverse of SharesFor.
+func (o LiveOrder) CostOf(shares float64) float64 {
+	if o.SizeShares > 0 && o.Size > 0 {
+		return shares * o.Size / o.SizeShares
+	}
+	return shares * o.BidPrice
+}
+
+// UnmergedShares is the token shares filled and not yet merged.
+func (o LiveOrder) UnmergedShares() float64 {
+	return o.SharesFor(o.UnmergedSize())
+}
+
+// ShareIncrement is the smallest share amount the CLOB signs (2 decimals).
+const ShareIncrement = 0.01
+
+// SharesAt is how many shares usdc buys at price, floored to ShareIncrement
+// the way the CLOB signs them. Their cost, shares × price, never exceeds usdc.
+func SharesAt(usdc, price float64) float64 {
+	if usdc <= 0 || price <= 0 {
+		return 0
+	}
+	return FloorShares(usdc / price)
+}
+
+// FloorShares floors shares to ShareIncrement, so a SELL never signs more
+// than the balance it was read from.
+func FloorShares(shares float64) float64 {
+	// The epsilon keeps 1.50/0.30 = 4.9999… from flooring a whole increment away.
+	return math.Floor(shares/ShareIncrement+1e-6) * ShareIncrement
+}
+
+// DefaultTickSize is the price increment most CLOB markets trade on, and the
+// one assumed when a market's tick size is unknown.
+const DefaultTickSize = 0.01
+
+// RoundToTick rounds price to the nearest multiple of tick.
+func RoundToTick(price, tick float64) float64 {
+	if tick <= 0 {
+		tick = DefaultTickSize
+	}
+	return cleanTick(math.Round(price/tick) * tick)
+}
+
+// FloorToTick rounds price down to a multiple of tick.
+func FloorToTick(price, tick float64) float64 {
+	if tick <= 0 {
+		tick = DefaultTickSize
+	}
+	return cleanTick(math.Floor(price/tick+1e-6) * tick)
+}
+
+// OnTick reports whether price is a whole multiple of tick, the only prices
+// the CLOB accepts.
+func OnTick(price, tick float64) bool {
+	if tick <= 0 {
+		return false
+	}
+	n := price / tick
+	return math.Abs(n-math.Round(n)) < 1e-6
+}
+
+// cleanTick drops the float noise a tick multiplication leaves behind
+// (0.47000000000000003 → 0.47).
+func cleanTick(p float64) float64 {
+	return math.Round(p*1e6) / 1e6
+}
+
+// Stranded reports a filled leg whose pair merged without it: its Remainder
+// shares are held unhedged until the unwind sells them.
+func (o LiveOrder) Stranded() bool {
+	return o.Remainder > 0 && o.Status == LiveStatusFilled && !o.IsSell()
+}
+
+// ConditionExposure is the capital committed to one market: USDC resting in
+// entry bids plus filled inventory whose tokens are still held.
+type ConditionExposure struct {
+	ConditionID string
+	Question    string
+	USDC        float64
+	Pct         float64 // fraction of the deployable capital
 }
 
 // LiveFill is a real fill event detected from CLOB.
@@ -48,6 +183,35 @@ type LiveFill struct {
 	Timestamp   time.Time
 }
 
+// LiveTrade is a trade from the wallet's CLOB trade history, attributed to
+// the order of ours that took part in it.
+type LiveTrade struct {
+	CLOBTradeID string
+	CLOBOrderID string
+	TokenID     string
+	Price       float64
+	Size        float64 // USDC (shares × price)
+	Timestamp   time.Time
+}
+
+// LiveOrderEventType classifies events pushed by the CLOB user channel.
+type LiveOrderEventType string
+
+const (
+	LiveEventFill   LiveOrderEventType = "FILL"
+	LiveEventCancel LiveOrderEventType = "CANCEL"
+)
+
+// LiveOrderEvent is a real-time order update streamed from the CLOB.
+type LiveOrderEvent struct {
+	Type        LiveOrderEventType
+	CLOBOrderID string
+	CLOBTradeID string
+	Price       float64
+	Size        float64 // USDC matched by this event (fills only)
+	Timestamp   time.Time
+}
+
 // MergeResult represents the result of an on-chain CTF merge.
 type MergeResult struct {
 	ConditionID  string
@@ -60,6 +224,28 @@ type MergeResult struct {
 	Success      bool
 	Error        string
 	ExecutedAt   time.Time
+
+	FailedAttempts []MergeAttempt // submissions that reverted or failed to send
+}
+
+// MergeAttempt is a single unsuccessful on-chain submission of a merge.
+type MergeAttempt struct {
+	TxHash       string
+	GasPriceGwei float64
+	Error        string
+	At           time.Time
+}
+
+// GasCeilingError is returned by a merge executor that refuses to send a
+// transaction while the gas price is above its configured ceiling. The merge
+// is deferred, not failed: nothing was submitted.
+type GasCeilingError struct {
+	PriceGwei   float64
+	CeilingGwei float64
+}
+
+func (e *GasCeilingError) Error() string {
+	return fmt.Sprintf("gas price %.1f gwei above ceiling %.1f gwei", e.PriceGwei, e.CeilingGwei)
 }
 
 // LivePosition is the current state of a real position in a market.
@@ -93,50 +279,6 @@ func (p LivePosition) PartialDuration() time.Duration {
 	return time.Since(*p.PartialSince)
 }
 
-// CircuitBreaker tracks consecutive losses and enforces trading pauses.
-type CircuitBreaker struct {
-	ConsecutiveLosses int
-	MaxLosses         int
-	CooldownUntil     time.Time
-	CooldownDuration  time.Duration
-	TotalPnL          float64
-	MaxDrawdown       float64 // negative dollar amount threshold
-	Triggered         bool
-	TriggeredReason   string
-}
-
-// IsOpen returns true if trading is allowed (circuit not triggered).
-func (cb *CircuitBreaker) IsOpen() bool {
-	if cb.Triggered {
-		return false
-	}
-	if time.Now().Before(cb.CooldownUntil) {
-		return false
-	}
-	return true
-}
-
-// RecordLoss records a negative merge result and may trip the breaker.
-func (cb *CircuitBreaker) RecordLoss(loss float64) {
-	cb.ConsecutiveLosses++
-	cb.TotalPnL += loss
-	if cb.ConsecutiveLosses >= cb.MaxLosses {
-		cb.CooldownUntil = time.Now().Add(cb.CooldownDuration)
-		cb.ConsecutiveLosses = 0
-		cb.TriggeredReason = "consecutive losses"
-	}
-	if cb.TotalPnL < cb.MaxDrawdown {
-		cb.Triggered = true
-		cb.TriggeredReason = "max drawdown exceeded"
-	}
-}
-
-// RecordWin resets consecutive loss counter.
-func (cb *CircuitBreaker) RecordWin(profit float64) {
-	cb.ConsecutiveLosses = 0
-	cb.TotalPnL += profit
-}
-
 // LiveDailySummary is the daily snapshot for live trading.
 type LiveDailySummary struct {
 	Date            time.Time
@@ -155,10 +297,47 @@ type LiveDailySummary struct {
 	Merges          int
 	MergeProfit     float64
 	GasCostUSD      float64
+	GasUsedPOL      float64 // POL burned by the day's successful merges
+	POLBalance      float64 // POL held for gas at the day's last cycle
+	StrandedShares  float64 // unhedged merge remainders held at the day's last cycle
+	StrandedUSDC    float64 // cost of those remainders
 	CompoundBalance float64
 	Rotations       int
 }
 
+// LiveReward is the liquidity reward Polymarket paid for one market on one
+// UTC day, as reported by the rewards API.
+type LiveReward struct {
+	Date        time.Time
+	ConditionID string
+	Earnings    float64 // USDC
+}
+
+// ConditionPayout is how a condition paid out on-chain: the fraction of $1
+// each YES and NO token redeems for. Resolved is false until the oracle has
+// reported (CTF payoutDenominator is 0 until then).
+type ConditionPayout struct {
+	Resolved bool
+	Yes      float64
+	No       float64
+}
+
+// Redemption records tokens of a resolved market redeemed on-chain for
+// USDC.e. A redemption closes whatever the pair still held: one filled leg,
+// or a remainder left by a merge.
+type Redemption struct {
+	ConditionID string
+	PairID      string
+	YesShares   float64
+	NoShares    float64
+	Payout      float64 // USDC received: shares × their side's payout fraction
+	CostBasis   float64 // USDC paid for the shares redeemed
+	GasCostUSD  float64
+	RealizedPnL float64 // Payout - CostBasis - GasCostUSD
+	TxHash      string
+	RedeemedAt  time.Time
+}
+
 // LiveStats aggregates statistics for the live trading run.
 type LiveStats struct {
 	StartDate         time.Time
@@ -169,19 +348,44 @@ type LiveStats struct {
 	CompletePairs     int
 	PartialFills      int
 	AvgPartialMins    float64
-	TotalReward       float64
+	TotalReward       float64 // estimated from block accrual
+	RealizedReward    float64 // paid by Polymarket, from the rewards API (0 until synced)
 	TotalMergeProfit  float64
 	TotalGasCostUSD   float64
+	TotalGasUsedPOL   float64
+	Redemptions       int     // resolved markets redeemed on-chain
+	RedemptionPnL     float64 // realized P&L of those redemptions, included in NetPnL
+	POLBalance        float64 // latest recorded POL balance for gas
+	StrandedShares    float64 // unhedged merge remainders at the latest daily summary
+	StrandedUSDC      float64
 	NetPnL            float64
 	DailyAvgPnL       float64
 	FillRateReal      float64
 	MarketsMonitored  int
 	TotalRotations    int
+	CloseReasons      map[CloseReason]int // pairs cancelled or unwound, per reason
 	CompoundBalance   float64
 	CompoundGrowth    float64
 	AvgCycleHours     float64
+	FillLatency       FillLatencyStats // placement to fill of filled entry orders
 	InitialCapital    float64
+	Shadow            bool // stats of shadow (dry-run) mode, not real trading
 	Dailies           []LiveDailySummary
+	Markets           []MarketPnL // per-condition attribution, worst first
+}
+
+// GasRunway estimates how many merges, and days of merging, POLBalance still
+// pays for at the average gas per merge so far. ok is false until there is
+// merge history to average.
+func (s LiveStats) GasRunway() (merges, days float64, ok bool) {
+	if s.CompletePairs == 0 || s.TotalGasUsedPOL <= 0 {
+		return 0, 0, false
+	}
+	merges = s.POLBalance / (s.TotalGasUsedPOL / float64(s.CompletePairs))
+	if s.DaysRunning > 0 {
+		days = s.POLBalance / (s.TotalGasUsedPOL / float64(s.DaysRunning))
+	}
+	return merges, days, true
 }
 
 // PlaceOrderRequest is sent to the CLOB order executor.
@@ -189,9 +393,12 @@ type PlaceOrderRequest struct {
 	TokenID     string
 	ConditionID string
 	Price       float64
-	Size        float64
-	Side        string  // "BUY" (maker bid)
+	Size        float64 // USDC notional
+	Shares      float64 // shares to sign; 0 = SharesAt(Size, Price)
+	Side        string  // "BUY" (maker bid) or "SELL" (exit)
 	NegRisk     bool
+	OrderType   string  // "GTC" (default), "GTD", "FAK" for immediate taker exits or "FOK" for taker completion
+	ExpiresAt   time.Time // optional: non-zero places a GTD order that the CLOB drops at this time
 }
 
 // PlacedOrder is the response from the CLOB after placing an order.
@@ -201,3 +408,43 @@ type PlacedOrder struct {
 	TakenAmount float64 // immediately filled (taker portion)
 	MadeAmount  float64 // resting in book (maker portion)
 }
+
+// Placement errors an order executor wraps so the engine can tell a request
+// the CLOB refused outright from a transient failure.
+var (
+	// ErrNonRetryable marks a malformed order (HTTP 400/422): resending the
+	// same request fails the same way.
+	ErrNonRetryable = errors.New("order not retryable")
+	// ErrOrderRejected marks an order the CLOB parsed and rejected with an
+	// error message (e.g. invalid tick size, market not accepting orders).
+	ErrOrderRejected = errors.New("order rejected by CLOB")
+)
+
+// FillRateModel predicts how long a resting bid takes to fill: every USDC of
+// stored QueueAhead is weighted QueueScale times a USDC of the order's own
+// size, and the book trades through them at USDCPerHour.
+//
+//	hours = (QueueScale*QueueAhead + Size) / USDCPerHour
+type FillRateModel struct {
+	USDCPerHour float64
+	QueueScale  float64
+	R2          float64 // share of the fill-time variance the model explains
+	Samples     int     // filled orders the model was fitted on
+}
+
+// Hours is the predicted fill time of an order of size USDC behind queueAhead.
+func (m FillRateModel) Hours(queueAhead, size float64) float64 {
+	if m.USDCPerHour <= 0 {
+		return 0
+	}
+	return (m.QueueScale*queueAhead + size) / m.USDCPerHour
+}
+
+// LiveCalibration holds the live constants suggested by the observed fills.
+// OptimalVarianceMax is zero when no market had enough pairs to tune it.
+type LiveCalibration struct {
+	OptimalQueueMult   float64
+	OptimalVarianceMax float64
+	FillRateModel      FillRateModel
+	VarianceMarkets    int // markets the variance threshold was chosen from
+}
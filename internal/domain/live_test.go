@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlacementKey_Deterministic(t *testing.T) {
+	key := PlacementKey("pair-1", "YES", 0.42)
+	assert.Equal(t, key, PlacementKey("pair-1", "YES", 0.42))
+	assert.Len(t, key, 32)
+
+	assert.NotEqual(t, key, PlacementKey("pair-1", "NO", 0.42))
+	assert.NotEqual(t, key, PlacementKey("pair-1", "YES", 0.43))
+	assert.NotEqual(t, key, PlacementKey("pair-2", "YES", 0.42))
+}
+
+func TestSharesAt_FloorsToIncrement(t *testing.T) {
+	assert.InDelta(t, 33.33, SharesAt(1, 0.03), 1e-9)
+	assert.InDelta(t, 5, SharesAt(1.5, 0.30), 1e-9, "1.5/0.3 is 4.999… in floating point")
+	assert.InDelta(t, 22.22, SharesAt(10, 0.45), 1e-9)
+	assert.LessOrEqual(t, SharesAt(10, 0.45)*0.45, 10.0)
+	assert.Zero(t, SharesAt(10, 0))
+}
+
+func TestTickRounding(t *testing.T) {
+	assert.Equal(t, 0.47, RoundToTick(0.4749, 0.01))
+	assert.Equal(t, 0.475, RoundToTick(0.4749, 0.001))
+	assert.Equal(t, 0.47, FloorToTick(0.4799, 0.01))
+	assert.Equal(t, 0.48, FloorToTick(0.48, 0.01), "an exact tick is not floored away")
+	assert.Equal(t, 0.47, RoundToTick(0.4749, 0), "unknown tick falls back to the default")
+
+	assert.True(t, OnTick(0.47, 0.01))
+	assert.True(t, OnTick(0.473, 0.001))
+	assert.False(t, OnTick(0.473, 0.01))
+	assert.False(t, OnTick(0.47, 0))
+}
+
+func TestLiveOrder_SharesForUsesSignedShares(t *testing.T) {
+	o := LiveOrder{BidPrice: 0.30, Size: 3, SizeShares: 9.99, FilledSize: 3}
+	assert.InDelta(t, 9.99, o.UnmergedShares(), 1e-9)
+	assert.InDelta(t, 3, o.CostOf(9.99), 1e-9)
+
+	legacy := LiveOrder{BidPrice: 0.30, Size: 3, FilledSize: 3}
+	assert.InDelta(t, 10, legacy.UnmergedShares(), 1e-9)
+}
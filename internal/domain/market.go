@@ -8,9 +8,14 @@ type Market struct {
 	QuestionID  string
 	Question    string    // enriquecido desde Gamma
 	Slug        string    // enriquecido desde Gamma
+	Category    string    // categoría de Gamma (p.ej. "Sports"), enriquecido desde Gamma
 	EndDate     time.Time // fecha de resolución, enriquecido desde Gamma
 	Volume24h   float64   // volumen últimas 24h en USDC, enriquecido desde Gamma
 	MakerBaseFee float64  // fee real del mercado (0 = usar default de config)
+	StartDate   time.Time // apertura del mercado (solo mercados históricos de Gamma)
+	BestBid     float64   // mejor bid YES guardado por Gamma (solo históricos)
+	BestAsk     float64   // mejor ask YES guardado por Gamma (solo históricos)
+	Liquidity   float64   // liquidez del CLOB en USDC según Gamma (solo históricos)
 	Tokens      [2]Token
 	Rewards     RewardConfig
 	Active      bool
@@ -61,6 +66,17 @@ func (m Market) EffectiveFeeRate(defaultFeeRate float64) float64 {
 	return defaultFeeRate
 }
 
+// MakerFeeRate devuelve el fee de una orden maker en el mercado. El programa
+// de rewards rebaja (o deja a cero) el fee de las órdenes que cumplen sus
+// reglas de spread: si qualifies se usa rebatedFeeRate; si no, el fee
+// estándar de EffectiveFeeRate.
+func (m Market) MakerFeeRate(defaultFeeRate, rebatedFeeRate float64, qualifies bool) float64 {
+	if qualifies {
+		return rebatedFeeRate
+	}
+	return m.EffectiveFeeRate(defaultFeeRate)
+}
+
 // YesToken devuelve el token YES del mercado.
 func (m Market) YesToken() Token {
 	for _, t := range m.Tokens {
@@ -97,3 +113,22 @@ func TruncateQuestion(question, conditionID string, maxLen int) string {
 	}
 	return q
 }
+
+// MarketUpdateKind clasifica un cambio de estado de mercado recibido en tiempo real.
+type MarketUpdateKind string
+
+const (
+	MarketUpdateResolved MarketUpdateKind = "RESOLVED" // el mercado resolvió
+	MarketUpdateClosed   MarketUpdateKind = "CLOSED"   // dejó de aceptar órdenes
+	MarketUpdateReward   MarketUpdateKind = "REWARD"   // cambió el reward diario
+)
+
+// MarketUpdate es un cambio de estado de un mercado empujado por el stream
+// de Gamma, antes de que el siguiente scan lo vea por REST.
+type MarketUpdate struct {
+	ConditionID    string
+	Kind           MarketUpdateKind
+	DailyRate      float64 // nuevo reward diario en USDC (solo MarketUpdateReward)
+	WinningOutcome string  // "Yes" | "No" (solo MarketUpdateResolved, si se conoce)
+	At             time.Time
+}
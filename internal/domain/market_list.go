@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MarketMatch describe un conjunto de mercados por slug, condition ID o
+// regex sobre la pregunta (sin distinguir mayúsculas).
+type MarketMatch struct {
+	Slugs        []string
+	ConditionIDs []string
+	Questions    []string // expresiones regulares
+}
+
+// IsEmpty indica si no hay ninguna regla.
+func (m MarketMatch) IsEmpty() bool {
+	return len(m.Slugs) == 0 && len(m.ConditionIDs) == 0 && len(m.Questions) == 0
+}
+
+// Merge devuelve las reglas de m y o juntas.
+func (m MarketMatch) Merge(o MarketMatch) MarketMatch {
+	return MarketMatch{
+		Slugs:        append(append([]string(nil), m.Slugs...), o.Slugs...),
+		ConditionIDs: append(append([]string(nil), m.ConditionIDs...), o.ConditionIDs...),
+		Questions:    append(append([]string(nil), m.Questions...), o.Questions...),
+	}
+}
+
+// marketMatcher es un MarketMatch compilado.
+type marketMatcher struct {
+	slugs      map[string]bool
+	conditions map[string]bool
+	questions  []*regexp.Regexp
+}
+
+func compileMarketMatch(m MarketMatch) (marketMatcher, error) {
+	mm := marketMatcher{
+		slugs:      make(map[string]bool, len(m.Slugs)),
+		conditions: make(map[string]bool, len(m.ConditionIDs)),
+	}
+	for _, s := range m.Slugs {
+		mm.slugs[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+	for _, c := range m.ConditionIDs {
+		mm.conditions[strings.ToLower(strings.TrimSpace(c))] = true
+	}
+	var errs []error
+	for _, q := range m.Questions {
+		re, err := regexp.Compile("(?i)" + q)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("question pattern %q: %w", q, err))
+			continue
+		}
+		mm.questions = append(mm.questions, re)
+	}
+	return mm, errors.Join(errs...)
+}
+
+func (mm marketMatcher) empty() bool {
+	return len(mm.slugs) == 0 && len(mm.conditions) == 0 && len(mm.questions) == 0
+}
+
+func (mm marketMatcher) matches(m Market) bool {
+	if m.Slug != "" && mm.slugs[strings.ToLower(m.Slug)] {
+		return true
+	}
+	if mm.conditions[strings.ToLower(m.ConditionID)] {
+		return true
+	}
+	for _, re := range mm.questions {
+		if re.MatchString(m.Question) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarketList es una lista negra y, opcionalmente, una lista blanca de
+// mercados. Exclude gana sobre Include; con Include vacío pasan todos los
+// mercados no excluidos.
+type MarketList struct {
+	exclude marketMatcher
+	include marketMatcher
+}
+
+// NewMarketList compila las reglas. Un patrón inválido devuelve error junto
+// con la lista del resto de reglas, que sigue siendo usable.
+func NewMarketList(exclude, include MarketMatch) (*MarketList, error) {
+	ex, exErr := compileMarketMatch(exclude)
+	in, inErr := compileMarketMatch(include)
+	return &MarketList{exclude: ex, include: in}, errors.Join(exErr, inErr)
+}
+
+// Allows indica si m puede operarse. Una lista nil lo permite todo.
+func (l *MarketList) Allows(m Market) bool {
+	if l == nil {
+		return true
+	}
+	if l.exclude.matches(m) {
+		return false
+	}
+	return l.include.empty() || l.include.matches(m)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// MarketPnL atribuye los resultados de paper o live a un mercado (condition),
+// para ver qué mercados arrastran el P&L agregado.
+type MarketPnL struct {
+	ConditionID   string
+	Question      string
+	Orders        int           // órdenes de entrada colocadas
+	Fills         int           // órdenes de entrada con fill (total o parcial)
+	Merges        int           // pares mergeados
+	Rotations     int           // pares cerrados sin merge (rotados, expirados o deshechos)
+	MergeProfit   float64       // spread capturado en merges (live: neto de gas)
+	RewardAccrued float64       // live: pagado por Polymarket; paper/shadow: estimado por tiempo en book
+	RealizedPnL   float64       // live: resultado de vender la pata llena de un parcial
+	TimeInMarket  time.Duration // de la primera orden a la última actividad
+	NetPnL        float64
+}
+
+// SortMarketPnL ordena de peor a mejor NetPnL.
+func SortMarketPnL(markets []MarketPnL) {
+	sort.SliceStable(markets, func(i, j int) bool { return markets[i].NetPnL < markets[j].NetPnL })
+}
+
+// MarketEvent es una entrada del timeline de órdenes y fills de un mercado.
+type MarketEvent struct {
+	At      time.Time // cero = momento no registrado (p. ej. cancelaciones)
+	Kind    string    // PLACED | FILL | MERGED | REWARD | estado final de la orden
+	OrderID string
+	Side    string
+	Price   float64
+	Size    float64
+	Detail  string
+}
+
+// SortMarketEvents ordena cronológicamente; los eventos sin hora van al final.
+func SortMarketEvents(events []MarketEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].At, events[j].At
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
@@ -17,10 +17,12 @@ type Opportunity struct {
 	Arbitrage ArbitrageResult
 
 	// --- Tu reward puro (sin costes) ---
-	Competition     float64 // USDC dentro del max_spread (ambos tokens)
-	YourShare       float64 // orderSize / (orderSize + competition)
-	SpreadScore     float64 // ((maxSpread - spread) / maxSpread)²
-	YourDailyReward float64 // reward bruto diario estimado para ti
+	Competition      float64       // USDC dentro del max_spread (ambos tokens)
+	CompetitionScore float64       // score de los bids ya en book, ponderado por distancia al mid
+	RewardLevels     []RewardLevel // desglose por nivel del score (--validate)
+	YourShare        float64       // yourScore / (yourScore + competitionScore)
+	SpreadScore      float64       // ((maxSpread - spread) / maxSpread)²
+	YourDailyReward  float64       // reward bruto diario estimado para ti
 
 	// --- Costes reales de fill ---
 	FillCostPerPair float64 // coste por share pair: (yesP + noP)(1+fee) - 1.0
@@ -39,6 +41,11 @@ type Opportunity struct {
 	// --- Legacy ---
 	RewardScore  float64
 	NetProfitEst float64 // deprecated, usar PnL escenarios
+
+	// Estimación plana anterior (orderSize / (orderSize + competition)),
+	// se mantiene una release para comparar con el scoring por nivel.
+	LegacyYourShare       float64
+	LegacyYourDailyReward float64
 }
 
 // IsArbitrage devuelve true si hay arbitraje neto rentable (tras fees).
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// MarketSummary resume la vida de un mercado en la tabla de oportunidades.
+type MarketSummary struct {
+	ConditionID   string
+	Question      string
+	Slug          string
+	Category      OpportunityCategory
+	FirstSeen     time.Time
+	LastSeen      time.Time
+	EndDate       time.Time // cero si el mercado no tiene fecha de resolución
+	PeakCombined  float64
+	LifetimeHours float64 // last_seen - first_seen
+}
+
+// OpportunityStats son las métricas de tendencia del scanner (--scan-stats).
+type OpportunityStats struct {
+	TopByLongevity       []MarketSummary // los Gold que más tiempo llevan siéndolo
+	GoldAppearances      int             // mercados Gold vistos en los últimos 7 días
+	AvgGoldLifetimeHours float64
+	NewThisWeek          int // Gold/Silver vistos por primera vez en los últimos 7 días
+	ResolvedThisWeek     int // Gold/Silver cuya fecha de resolución cayó en los últimos 7 días
+}
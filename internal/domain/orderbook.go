@@ -1,6 +1,9 @@
 package domain
 
-import "strconv"
+import (
+	"math"
+	"strconv"
+)
 
 // OrderBook representa el libro de órdenes de un token.
 type OrderBook struct {
@@ -53,6 +56,16 @@ func (ob OrderBook) Spread() float64 {
 	return ask - bid
 }
 
+// IsCrossed indica si el book está cruzado o bloqueado (best bid >= best ask).
+// Es un snapshot inconsistente (o un mercado en plena resolución): un bid
+// colocado ahí cruzaría el spread y se llenaría como taker. Un book con un
+// lado vacío no está cruzado.
+func (ob OrderBook) IsCrossed() bool {
+	bid := ob.BestBid()
+	ask := ob.BestAsk()
+	return bid > 0 && ask > 0 && bid >= ask
+}
+
 // DepthWithin calcula el volumen total de órdenes (bids + asks) en unidades de token
 // dentro de un spread dado respecto al midpoint.
 func (ob OrderBook) DepthWithin(maxSpread float64) float64 {
@@ -113,6 +126,46 @@ func (ob OrderBook) BidDepthWithinUSDC(maxSpread float64) float64 {
 	return total
 }
 
+// VWAP devuelve el precio medio ponderado por volumen de los primeros depth
+// USDC del lado side ("bid" o "ask"), recorriendo los niveles desde el mejor
+// precio. Si el lado tiene menos de depth, promedia lo que haya; con
+// depth <= 0 devuelve el mejor precio. 0 si el lado está vacío o side no es
+// válido.
+func (ob OrderBook) VWAP(side string, depth float64) float64 {
+	var levels []BookEntry
+	switch side {
+	case "bid":
+		levels = ob.Bids
+	case "ask":
+		levels = ob.Asks
+	default:
+		return 0
+	}
+	if len(levels) == 0 {
+		return 0
+	}
+	if depth <= 0 {
+		return levels[0].Price
+	}
+
+	var usdc, shares float64
+	for _, l := range levels {
+		if l.Price <= 0 || l.Size <= 0 {
+			continue
+		}
+		take := math.Min(l.Size*l.Price, depth-usdc)
+		usdc += take
+		shares += take / l.Price
+		if usdc >= depth {
+			break
+		}
+	}
+	if shares == 0 {
+		return 0
+	}
+	return usdc / shares
+}
+
 // ParsePrice convierte un string de precio a float64.
 // Usado en el mapping de la API.
 func ParsePrice(s string) float64 {
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBook_IsCrossed(t *testing.T) {
+	book := func(bid, ask float64) OrderBook {
+		var ob OrderBook
+		if bid > 0 {
+			ob.Bids = []BookEntry{{Price: bid, Size: 10}}
+		}
+		if ask > 0 {
+			ob.Asks = []BookEntry{{Price: ask, Size: 10}}
+		}
+		return ob
+	}
+
+	assert.False(t, book(0.48, 0.50).IsCrossed(), "normal spread")
+	assert.True(t, book(0.50, 0.50).IsCrossed(), "locked: bid == ask")
+	assert.True(t, book(0.52, 0.50).IsCrossed(), "crossed: bid > ask")
+	assert.False(t, book(0.52, 0).IsCrossed(), "no asks")
+	assert.False(t, book(0, 0.50).IsCrossed(), "no bids")
+}
+
+func TestOrderBook_VWAP(t *testing.T) {
+	// $4.80 on top at 0.48, $470 behind it at 0.47.
+	ob := OrderBook{
+		Bids: []BookEntry{{Price: 0.48, Size: 10}, {Price: 0.47, Size: 1000}},
+		Asks: []BookEntry{{Price: 0.52, Size: 100}, {Price: 0.55, Size: 100}},
+	}
+
+	assert.InDelta(t, 0.48, ob.VWAP("bid", 4), 1e-9, "fits in the top level")
+	assert.InDelta(t, 50/(10+45.2/0.47), ob.VWAP("bid", 50), 1e-9, "thin top pulls toward the next level")
+	assert.InDelta(t, 474.8/1010, ob.VWAP("bid", 10000), 1e-9, "deeper than the book: averages all of it")
+	assert.InDelta(t, 104/(100+52/0.55), ob.VWAP("ask", 104), 1e-9)
+	assert.InDelta(t, 0.48, ob.VWAP("bid", 0), 1e-9, "no depth: best price")
+	assert.Zero(t, OrderBook{}.VWAP("bid", 50), "empty side")
+	assert.Zero(t, ob.VWAP("YES", 50), "unknown side")
+}
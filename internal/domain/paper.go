@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // PaperOrderStatus represents the lifecycle of a virtual order.
 type PaperOrderStatus string
@@ -34,6 +37,47 @@ type VirtualOrder struct {
 	DailyReward  float64    // estimated daily reward at placement time
 	EndDate      time.Time
 	MergedAt     *time.Time // when the pair was merged (compound rotation)
+
+	// QueueConsumed is the SELL volume (USDC) at or below BidPrice seen since
+	// PlacedAt, up to QueueCheckedAt. It is persisted so a restart resumes the
+	// simulated place in line instead of re-deriving it from the trades API.
+	QueueConsumed  float64
+	QueueCheckedAt time.Time // timestamp of the last trade counted in QueueConsumed
+
+	// LevelDepth is the USDC already resting at BidPrice when the order was
+	// placed. SizeDominant marks an order that dwarfs it (see IsSizeDominant):
+	// competitors re-quote around such a bid, so only part of the sell volume
+	// past the queue reaches it (FillShare).
+	LevelDepth   float64
+	SizeDominant bool
+
+	// CloseReason and ClosedAt are set when the engine expires the order
+	// before it merged; empty for orders retired before they were recorded.
+	CloseReason CloseReason
+	ClosedAt    *time.Time
+}
+
+// IsSizeDominant reports whether an order of size USDC exceeds fraction of
+// the depth already at its price level. A bid alone on an empty level is the
+// top of the book, not a dominant one.
+func IsSizeDominant(size, levelDepth, fraction float64) bool {
+	return levelDepth > 0 && size > fraction*levelDepth
+}
+
+// FillShare is the fraction of the sell volume past the queue that fills the
+// order: 1 normally, fraction × LevelDepth / Size when it is SizeDominant.
+func (o VirtualOrder) FillShare(fraction float64) float64 {
+	if !o.SizeDominant || o.Size <= 0 || o.LevelDepth <= 0 {
+		return 1
+	}
+	return math.Min(fraction*o.LevelDepth/o.Size, 1)
+}
+
+// PaperConditionSides tells which sides of a condition still hold a paper
+// order that is resting or filled but not yet merged or resolved.
+type PaperConditionSides struct {
+	YesOpen bool
+	NoOpen  bool
 }
 
 // PaperFill records when a real trade would have filled a virtual order.
@@ -46,6 +90,29 @@ type PaperFill struct {
 	Timestamp time.Time
 }
 
+// PaperFillComparison sets what the paper engine recorded for one order
+// against a replay of the full trade history over the order's lifetime
+// (--paper-compare). Fill times are nil when the order never filled.
+type PaperFillComparison struct {
+	Order VirtualOrder
+
+	PredictedFilled   float64 // USDC filled as recorded by the engine
+	PredictedFillTime *time.Time
+	ActualFilled      float64    // USDC the replay fills
+	ActualFillTime    *time.Time // trade that cleared the queue and the order
+	Trades            int        // trades replayed for the order's token
+}
+
+// QueueModelAccuracyPct is 100 when the recorded fill matches the replay and
+// falls with the gap relative to the larger of the two.
+func (c PaperFillComparison) QueueModelAccuracyPct() float64 {
+	hi := math.Max(c.PredictedFilled, c.ActualFilled)
+	if hi <= 0 {
+		return 100
+	}
+	return 100 * (1 - math.Abs(c.PredictedFilled-c.ActualFilled)/hi)
+}
+
 // PaperPosition is the current state of a simulated position in a market.
 type PaperPosition struct {
 	ConditionID      string
@@ -68,16 +135,31 @@ type PaperPosition struct {
 	MergeProfit      float64    // profit from merging YES+NO → $1
 	MergeReturn      float64    // total USDC returned from merge
 	CycleHours       float64    // time from placement to merge completion
+	SizeDominant     bool       // a leg dwarfed the depth at its price level
 }
 
-// PartialDuration returns how long the position has been partially filled.
+// PartialDuration returns how long the position has been partially filled:
+// the time since PartialSince while exactly one side is filled, 0 otherwise.
 func (p PaperPosition) PartialDuration() time.Duration {
-	if p.PartialSince == nil || p.IsComplete {
+	if p.PartialSince == nil || p.YesFilled == p.NoFilled {
 		return 0
 	}
 	return time.Since(*p.PartialSince)
 }
 
+// TotalCapitalAtRisk returns the USDC still resting unfilled on the pair's
+// orders: the part of each OPEN or PARTIAL leg not filled yet.
+func (p PaperPosition) TotalCapitalAtRisk() float64 {
+	var total float64
+	for _, o := range []*VirtualOrder{p.YesOrder, p.NoOrder} {
+		if o == nil || (o.Status != PaperStatusOpen && o.Status != PaperStatusPartial) {
+			continue
+		}
+		total += math.Max(o.Size-o.FilledSize, 0)
+	}
+	return total
+}
+
 // PaperDailySummary is the daily snapshot for the paper trading dashboard.
 type PaperDailySummary struct {
 	Date             time.Time
@@ -120,10 +202,109 @@ type PaperStats struct {
 	ResolutionPnL    float64
 	MaxCapital       float64
 	TotalRotations   int
+	CloseReasons     map[CloseReason]int // pairs expired before merging, per reason
+	// Orders placed and orders with any fill, split by whether the order was
+	// size-dominant at placement, to see if the book-impact model matters.
+	NormalOrders     int
+	NormalFilled     int
+	DominantOrders   int
+	DominantFilled   int
 	TotalMergeProfit float64
 	CompoundBalance  float64
 	CompoundGrowth   float64 // multiplier vs initial capital
 	AvgCycleHours    float64
+	FillLatency      FillLatencyStats // placement to fill of filled entry orders
 	InitialCapital   float64
 	Dailies          []PaperDailySummary
+	Markets          []MarketPnL // per-condition attribution, worst first
+}
+
+// MaxDrawdown is the largest fall of the cumulative daily P&L (net plus
+// resolution P&L) from an earlier peak, as a positive USDC amount. 0 when
+// it never fell.
+func (s PaperStats) MaxDrawdown() float64 {
+	var cum, peak, dd float64
+	for _, d := range s.Dailies {
+		cum += d.NetPnL + d.ResolutionPnL
+		peak = math.Max(peak, cum)
+		dd = math.Max(dd, peak-cum)
+	}
+	return dd
+}
+
+// SweepResult is one config variant of a paper parameter sweep (--sweep)
+// with the stats of its own store. All variants see the same scans.
+type SweepResult struct {
+	Name       string
+	OrderSize  float64
+	MaxMarkets int
+	StaleHours float64
+	Stats      PaperStats
+}
+
+// MinProjectionDays is how much history a compound projection needs; shorter
+// runs are dominated by noise.
+const MinProjectionDays = 7
+
+// ProjectionHorizons are the horizons, in days, shown in reports.
+var ProjectionHorizons = []int{30, 90, 365}
+
+// Projection is the compounded balance expected after Days.
+type Projection struct {
+	Days             int
+	ProjectedBalance float64
+	ProjectedAPR     float64 // return over Days, annualized without compounding (0.25 = 25%)
+}
+
+// CompoundProjection projects balance forward as B_t = B_0 × (1 + r)^t with
+// r = dailyPnL / balance, i.e. every day's P&L is reinvested at the same
+// rate. It assumes fill rates and rewards stay constant. Returns nil when the
+// balance is not positive or the daily loss would wipe it out.
+func CompoundProjection(balance, dailyPnL float64, horizons []int) []Projection {
+	if balance <= 0 {
+		return nil
+	}
+	r := dailyPnL / balance
+	if r <= -1 {
+		return nil
+	}
+	out := make([]Projection, 0, len(horizons))
+	for _, days := range horizons {
+		if days <= 0 {
+			continue
+		}
+		bt := balance * math.Pow(1+r, float64(days))
+		out = append(out, Projection{
+			Days:             days,
+			ProjectedBalance: bt,
+			ProjectedAPR:     (bt/balance - 1) * 365 / float64(days),
+		})
+	}
+	return out
+}
+
+// HourlyFillStats counts paper fills in one UTC hour of the day over a
+// window of days (--fill-heatmap).
+type HourlyFillStats struct {
+	Hour       int     // 0-23, UTC
+	AvgFills   float64 // TotalFills / days in the window
+	TotalFills int
+}
+
+// PeakAndQuietHours returns the hours with the most and the fewest fills;
+// ties go to the first one listed. ok is false when there were no fills.
+func PeakAndQuietHours(hours []HourlyFillStats) (peak, quiet HourlyFillStats, ok bool) {
+	if len(hours) == 0 {
+		return peak, quiet, false
+	}
+	peak, quiet = hours[0], hours[0]
+	for _, h := range hours {
+		if h.TotalFills > peak.TotalFills {
+			peak = h
+		}
+		if h.TotalFills < quiet.TotalFills {
+			quiet = h
+		}
+	}
+	return peak, quiet, peak.TotalFills > 0
 }
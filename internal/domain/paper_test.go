@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompoundProjection_CompoundsDailyReturn(t *testing.T) {
+	got := CompoundProjection(1000, 10, []int{30, 365})
+	require.Len(t, got, 2)
+
+	assert.Equal(t, 30, got[0].Days)
+	assert.InDelta(t, 1000*math.Pow(1.01, 30), got[0].ProjectedBalance, 1e-6)
+	assert.InDelta(t, (math.Pow(1.01, 30)-1)*365/30, got[0].ProjectedAPR, 1e-9)
+	assert.InDelta(t, 1000*math.Pow(1.01, 365), got[1].ProjectedBalance, 1e-6)
+}
+
+func TestCompoundProjection_NoBalance(t *testing.T) {
+	assert.Nil(t, CompoundProjection(0, 10, ProjectionHorizons))
+	assert.Nil(t, CompoundProjection(100, -100, ProjectionHorizons), "a daily loss of the whole balance")
+}
+
+func TestVirtualOrder_FillShare(t *testing.T) {
+	assert.False(t, IsSizeDominant(100, 0, 1), "alone on an empty level")
+	assert.False(t, IsSizeDominant(40, 40, 1))
+	assert.True(t, IsSizeDominant(100, 40, 1))
+	assert.True(t, IsSizeDominant(30, 40, 0.5))
+
+	o := VirtualOrder{Size: 100, LevelDepth: 40, SizeDominant: true}
+	assert.InDelta(t, 0.4, o.FillShare(1), 1e-9)
+	assert.InDelta(t, 0.8, o.FillShare(2), 1e-9)
+	assert.InDelta(t, 1, o.FillShare(5), 1e-9, "never more than the whole flow")
+	o.SizeDominant = false
+	assert.InDelta(t, 1, o.FillShare(1), 1e-9)
+}
+
+func TestPaperStats_MaxDrawdown(t *testing.T) {
+	stats := PaperStats{Dailies: []PaperDailySummary{
+		{NetPnL: 5},
+		{NetPnL: -2, ResolutionPnL: -4}, // peak 5 → -1
+		{NetPnL: 3},                     // recovers to 2
+		{NetPnL: -1},
+	}}
+	assert.InDelta(t, 6.0, stats.MaxDrawdown(), 1e-9)
+	assert.Zero(t, PaperStats{Dailies: []PaperDailySummary{{NetPnL: 1}, {NetPnL: 2}}}.MaxDrawdown())
+}
+
+func TestNewFillLatency_NearestRank(t *testing.T) {
+	var ds []time.Duration
+	for i := 10; i >= 1; i-- {
+		ds = append(ds, time.Duration(i)*time.Minute)
+	}
+	fl := NewFillLatency(ds)
+	assert.Equal(t, 10, fl.Count)
+	assert.Equal(t, time.Minute, fl.Min)
+	assert.Equal(t, 5*time.Minute, fl.Median)
+	assert.Equal(t, 9*time.Minute, fl.P90)
+	assert.Equal(t, 10*time.Minute, fl.Max)
+	assert.Equal(t, 10*time.Minute, ds[0], "the input is not reordered")
+
+	assert.Equal(t, FillLatency{}, NewFillLatency(nil))
+}
+
+func TestPaperPosition_PartialDuration(t *testing.T) {
+	since := time.Now().Add(-90 * time.Minute)
+	tests := []struct {
+		name      string
+		pos       PaperPosition
+		wantAbout time.Duration
+	}{
+		{"nil PartialSince", PaperPosition{YesFilled: true}, 0},
+		{"both sides unfilled", PaperPosition{PartialSince: &since}, 0},
+		{"both sides filled", PaperPosition{PartialSince: &since, YesFilled: true, NoFilled: true, IsComplete: true}, 0},
+		{"only YES filled", PaperPosition{PartialSince: &since, YesFilled: true}, 90 * time.Minute},
+		{"only NO filled", PaperPosition{PartialSince: &since, NoFilled: true}, 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.pos.PartialDuration()
+			if tt.wantAbout == 0 {
+				assert.Zero(t, got)
+				return
+			}
+			assert.InDelta(t, tt.wantAbout.Seconds(), got.Seconds(), 5)
+		})
+	}
+}
+
+func TestPaperPosition_TotalCapitalAtRisk(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  PaperPosition
+		want float64
+	}{
+		{"no orders", PaperPosition{}, 0},
+		{"both resting", PaperPosition{
+			YesOrder: &VirtualOrder{Size: 10, Status: PaperStatusOpen},
+			NoOrder:  &VirtualOrder{Size: 12, Status: PaperStatusOpen},
+		}, 22},
+		{"partial counts the unfilled rest", PaperPosition{
+			YesOrder: &VirtualOrder{Size: 10, FilledSize: 4, Status: PaperStatusPartial},
+			NoOrder:  &VirtualOrder{Size: 10, Status: PaperStatusOpen},
+		}, 16},
+		{"filled and expired legs hold nothing resting", PaperPosition{
+			YesOrder: &VirtualOrder{Size: 10, FilledSize: 10, Status: PaperStatusFilled},
+			NoOrder:  &VirtualOrder{Size: 10, Status: PaperStatusExpired},
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.pos.TotalCapitalAtRisk(), 1e-9)
+		})
+	}
+}
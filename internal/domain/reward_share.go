@@ -0,0 +1,95 @@
+package domain
+
+// reward_share.go — Reparto del pool de rewards ponderado por distancia al mid.
+//
+// Polymarket no reparte el pool por USDC en book: cada orden puntúa
+// S(v, s) = ((v - s) / v)² × size, con v = max_spread y s = distancia de la
+// orden al midpoint. Una orden pegada al mid vale mucho más que una en el
+// borde del max_spread, así que el reparto plano por depth (Competition)
+// infravalora la cola en mercados anchos y sobrevalora la nuestra.
+
+import "math"
+
+// rewardScoreExponent es el exponente de la función de scoring documentada.
+const rewardScoreExponent = 2
+
+// RewardLevel es la contribución al score de un nivel de bids.
+type RewardLevel struct {
+	Side     string  // "YES" o "NO"
+	Price    float64 // precio del nivel
+	Distance float64 // midpoint - precio
+	Size     float64 // shares de la competencia en el nivel
+	Weight   float64 // S(v, s) sin el size
+	Score    float64 // Weight × Size
+	Ours     bool    // nivel donde colocaríamos nuestra orden
+}
+
+// RewardShare es nuestra parte estimada del pool según el scoring por nivel.
+type RewardShare struct {
+	Levels          []RewardLevel
+	CompetitorScore float64 // suma de scores de la competencia (ambos tokens)
+	YourScore       float64 // score de nuestras dos órdenes
+	Share           float64 // YourScore / (YourScore + CompetitorScore)
+}
+
+// OrderScoreWeight devuelve S(v, s) para una orden a distancia s del mid.
+// Fuera del max_spread la orden no puntúa.
+func OrderScoreWeight(maxSpread, distance float64) float64 {
+	if maxSpread <= 0 || distance < 0 || distance >= maxSpread {
+		return 0
+	}
+	return math.Pow((maxSpread-distance)/maxSpread, rewardScoreExponent)
+}
+
+// BidRewardLevels puntúa cada nivel de bids del book dentro del max_spread.
+// Solo los bids compiten por el reward en reward farming.
+func BidRewardLevels(book OrderBook, maxSpread float64, side string) []RewardLevel {
+	mid := book.Midpoint()
+	if mid == 0 {
+		return nil
+	}
+	var levels []RewardLevel
+	for _, b := range book.Bids {
+		w := OrderScoreWeight(maxSpread, mid-b.Price)
+		if w == 0 {
+			continue
+		}
+		levels = append(levels, RewardLevel{
+			Side:     side,
+			Price:    b.Price,
+			Distance: mid - b.Price,
+			Size:     b.Size,
+			Weight:   w,
+			Score:    w * b.Size,
+		})
+	}
+	return levels
+}
+
+// EstimateRewardShare calcula nuestra parte del pool si ponemos orderSize
+// USDC en el best bid de cada token, como hacen los engines. El reparto es
+// nuestro score frente a la suma de scores de los bids ya en el book.
+func EstimateRewardShare(yesBook, noBook OrderBook, maxSpread, orderSize float64) RewardShare {
+	var rs RewardShare
+	for _, sb := range []struct {
+		side string
+		book OrderBook
+	}{{"YES", yesBook}, {"NO", noBook}} {
+		levels := BidRewardLevels(sb.book, maxSpread, sb.side)
+		bid := sb.book.BestBid()
+		for i := range levels {
+			rs.CompetitorScore += levels[i].Score
+			if levels[i].Price == bid {
+				levels[i].Ours = true
+			}
+		}
+		if bid > 0 && orderSize > 0 {
+			rs.YourScore += OrderScoreWeight(maxSpread, sb.book.Midpoint()-bid) * orderSize / bid
+		}
+		rs.Levels = append(rs.Levels, levels...)
+	}
+	if total := rs.YourScore + rs.CompetitorScore; total > 0 {
+		rs.Share = rs.YourScore / total
+	}
+	return rs
+}
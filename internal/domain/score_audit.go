@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// ScoreAudit es una foto de los componentes del score de un mercado en un
+// scan: permite ver por qué bajó de Gold a Silver o por qué se rotó.
+type ScoreAudit struct {
+	ConditionID     string
+	ScannedAt       time.Time
+	Category        OpportunityCategory
+	CombinedScore   float64
+	SpreadScore     float64 // ((maxSpread - spread) / maxSpread)²
+	YourShare       float64 // orderSize / (orderSize + competition)
+	BreakEvenFills  float64 // +Inf = fills gratis
+	QualifiesReward bool
+	SpreadTotal     float64
+	Competition     float64
+	YourDailyReward float64
+	FillCostUSDC    float64
+}
+
+// NewScoreAudit extrae los componentes del score de una oportunidad.
+func NewScoreAudit(o Opportunity, scannedAt time.Time) ScoreAudit {
+	return ScoreAudit{
+		ConditionID:     o.Market.ConditionID,
+		ScannedAt:       scannedAt,
+		Category:        o.Category,
+		CombinedScore:   o.CombinedScore,
+		SpreadScore:     o.SpreadScore,
+		YourShare:       o.YourShare,
+		BreakEvenFills:  o.BreakEvenFills,
+		QualifiesReward: o.QualifiesReward,
+		SpreadTotal:     o.SpreadTotal,
+		Competition:     o.Competition,
+		YourDailyReward: o.YourDailyReward,
+		FillCostUSDC:    o.FillCostUSDC,
+	}
+}
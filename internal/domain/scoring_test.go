@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRewardScore_ValidInputs(t *testing.T) {
@@ -195,6 +196,20 @@ func TestMarket_EffectiveFeeRate(t *testing.T) {
 	assert.Equal(t, 0.02, m2.EffectiveFeeRate(0.02))
 }
 
+func TestMarket_MakerFeeRate(t *testing.T) {
+	// The reward program rebates the maker fee only for orders inside its
+	// spread rules; anything else pays the market's standard rate.
+	m := Market{MakerBaseFee: 0.005}
+	assert.Equal(t, 0.0, m.MakerFeeRate(0.02, 0, true))
+	assert.Equal(t, 0.001, m.MakerFeeRate(0.02, 0.001, true))
+	assert.Equal(t, 0.005, m.MakerFeeRate(0.02, 0, false))
+	assert.Equal(t, 0.02, Market{}.MakerFeeRate(0.02, 0, false))
+
+	// A fill that breaks even at the rebated rate loses money at the standard one.
+	assert.InDelta(t, 0.0, FillCostPerEvent(0.5, 0.5, m.MakerFeeRate(0.02, 0, true)), 1e-12)
+	assert.Greater(t, FillCostPerEvent(0.5, 0.5, m.MakerFeeRate(0.02, 0, false)), 0.0)
+}
+
 func TestParsePrice(t *testing.T) {
 	assert.Equal(t, 0.72, ParsePrice("0.72"))
 	assert.Equal(t, 0.0, ParsePrice(""))
@@ -261,3 +276,47 @@ func TestComputeCombinedScore_NoArb_EqualsReward(t *testing.T) {
 	combined := ComputeCombinedScore(1.0, arb, 100, 2.0)
 	assert.InDelta(t, 1.0, combined, 0.001) // sin true arb = solo reward
 }
+
+// --- EstimateRewardShare (scoring por nivel) ---
+
+func TestOrderScoreWeight_Quadratic(t *testing.T) {
+	assert.InDelta(t, 1.0, OrderScoreWeight(0.04, 0), 1e-9)
+	assert.InDelta(t, 0.25, OrderScoreWeight(0.04, 0.02), 1e-9)
+	assert.Equal(t, 0.0, OrderScoreWeight(0.04, 0.04))
+	assert.Equal(t, 0.0, OrderScoreWeight(0, 0.01))
+}
+
+func TestEstimateRewardShare_FarLiquidityWeighsLess(t *testing.T) {
+	// Mid YES = 0.50. Toda la competencia está a 3¢, cerca del borde (v = 4¢).
+	yes := OrderBook{
+		Bids: []BookEntry{{Price: 0.49, Size: 100}, {Price: 0.47, Size: 10_000}},
+		Asks: []BookEntry{{Price: 0.51, Size: 100}},
+	}
+	no := OrderBook{
+		Bids: []BookEntry{{Price: 0.49, Size: 100}},
+		Asks: []BookEntry{{Price: 0.51, Size: 100}},
+	}
+
+	rs := EstimateRewardShare(yes, no, 0.04, 100)
+
+	require.Len(t, rs.Levels, 3)
+	assert.True(t, rs.Levels[0].Ours)
+	assert.False(t, rs.Levels[1].Ours)
+	assert.InDelta(t, 0.0625*10_000, rs.Levels[1].Score, 1e-6)
+
+	// 100 USDC a 0.49 → 204 shares por lado con peso 0.5625
+	yourScore := 2 * 0.5625 * 100 / 0.49
+	assert.InDelta(t, yourScore, rs.YourScore, 1e-6)
+	assert.InDelta(t, 0.5625*200+625, rs.CompetitorScore, 1e-6)
+	assert.InDelta(t, yourScore/(yourScore+rs.CompetitorScore), rs.Share, 1e-9)
+
+	// El reparto plano por USDC daría mucha menos cuota
+	flat := 100 / (100 + yes.BidDepthWithinUSDC(0.04) + no.BidDepthWithinUSDC(0.04))
+	assert.Greater(t, rs.Share, flat)
+}
+
+func TestEstimateRewardShare_EmptyBook(t *testing.T) {
+	rs := EstimateRewardShare(OrderBook{}, OrderBook{}, 0.04, 100)
+	assert.Empty(t, rs.Levels)
+	assert.Equal(t, 0.0, rs.Share)
+}
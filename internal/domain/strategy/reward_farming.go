@@ -57,15 +57,23 @@ func (s *RewardFarming) Analyze(_ context.Context, market domain.Market, yesBook
 	competition := yesBook.DepthWithinUSDC(market.Rewards.MaxSpread) +
 		noBook.DepthWithinUSDC(market.Rewards.MaxSpread)
 
-	yourDailyReward := domain.EstimateYourDailyReward(
+	legacyReward := domain.EstimateYourDailyReward(
 		s.orderSize, competition,
 		market.Rewards.DailyRate,
 		spreadTotal, market.Rewards.MaxSpread,
 	)
 
-	yourShare := 0.0
+	legacyShare := 0.0
 	if competition > 0 {
-		yourShare = s.orderSize / (s.orderSize + competition)
+		legacyShare = s.orderSize / (s.orderSize + competition)
+	}
+
+	// El reward real reparte el pool por score ponderado por nivel
+	share := domain.EstimateRewardShare(yesBook, noBook, market.Rewards.MaxSpread, s.orderSize)
+	yourShare := share.Share
+	yourDailyReward := 0.0
+	if qualifies {
+		yourDailyReward = market.Rewards.DailyRate * yourShare
 	}
 	spreadScore := domain.ComputeSpreadScore(spreadTotal, market.Rewards.MaxSpread)
 
@@ -91,26 +99,31 @@ func (s *RewardFarming) Analyze(_ context.Context, market domain.Market, yesBook
 	legacyScore := domain.RewardScore(s.orderSize, spreadTotal, market.Rewards.DailyRate)
 
 	return domain.Opportunity{
-		Market:          market,
-		YesBook:         yesBook,
-		NoBook:          noBook,
-		ScannedAt:       time.Now(),
-		SpreadTotal:     spreadTotal,
-		QualifiesReward: qualifies,
-		Arbitrage:       arb,
-		Competition:     competition,
-		YourShare:       yourShare,
-		SpreadScore:     spreadScore,
-		YourDailyReward: yourDailyReward,
-		FillCostPerPair: fillCostPair,
-		FillCostUSDC:    fillCostUSD,
-		BreakEvenFills:  breakEven,
-		PnLNoFills:      pnl0,
-		PnL1Fill:        pnl1,
-		PnL3Fills:       pnl3,
-		CombinedScore:   combined,
-		Category:        category,
-		NetProfitEst:    pnl1,
-		RewardScore:     legacyScore,
+		Market:           market,
+		YesBook:          yesBook,
+		NoBook:           noBook,
+		ScannedAt:        time.Now(),
+		SpreadTotal:      spreadTotal,
+		QualifiesReward:  qualifies,
+		Arbitrage:        arb,
+		Competition:      competition,
+		CompetitionScore: share.CompetitorScore,
+		RewardLevels:     share.Levels,
+		YourShare:        yourShare,
+		SpreadScore:      spreadScore,
+		YourDailyReward:  yourDailyReward,
+		FillCostPerPair:  fillCostPair,
+		FillCostUSDC:     fillCostUSD,
+		BreakEvenFills:   breakEven,
+		PnLNoFills:       pnl0,
+		PnL1Fill:         pnl1,
+		PnL3Fills:        pnl3,
+		CombinedScore:    combined,
+		Category:         category,
+		NetProfitEst:     pnl1,
+		RewardScore:      legacyScore,
+
+		LegacyYourShare:       legacyShare,
+		LegacyYourDailyReward: legacyReward,
 	}, nil
 }
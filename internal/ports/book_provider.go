@@ -12,3 +12,12 @@ type BookProvider interface {
 	// Internamente agrupa los IDs en batches de máx 20 para minimizar requests.
 	FetchOrderBooks(ctx context.Context, tokenIDs []string) (map[string]domain.OrderBook, error)
 }
+
+// BookStream entrega snapshots de orderbook en tiempo real para un conjunto
+// dinámico de tokens (p.ej. el canal market del WebSocket del CLOB).
+type BookStream interface {
+	// SetAssets reemplaza el conjunto de tokens suscritos.
+	SetAssets(tokenIDs []string)
+	// Books devuelve el canal de snapshots; se cierra al terminar el stream.
+	Books() <-chan domain.OrderBook
+}
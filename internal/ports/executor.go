@@ -2,30 +2,49 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
 // OrderExecutor places, cancels, and monitors real orders on Polymarket CLOB.
 type OrderExecutor interface {
-	// PlaceOrder signs and submits a limit maker order to the CLOB.
+	// PlaceOrder signs and submits a limit order to the CLOB: a maker BUY bid,
+	// or a SELL used to exit a position.
 	PlaceOrder(ctx context.Context, req domain.PlaceOrderRequest) (domain.PlacedOrder, error)
 
 	// CancelOrder cancels a specific order by its CLOB order ID.
 	CancelOrder(ctx context.Context, clobOrderID string) error
 
+	// CancelBatch cancels several orders by CLOB order ID in as few requests
+	// as possible. The returned error lists every order that failed.
+	CancelBatch(ctx context.Context, clobOrderIDs []string) error
+
 	// CancelAll cancels all open orders for this wallet.
 	CancelAll(ctx context.Context) error
 
 	// GetOpenOrders returns all currently open/partial orders from the CLOB.
 	GetOpenOrders(ctx context.Context) ([]domain.LiveOrder, error)
 
+	// GetTrades returns this wallet's trades matched after the given time, one
+	// per order of ours involved. It is the ground truth for fills.
+	GetTrades(ctx context.Context, after time.Time) ([]domain.LiveTrade, error)
+
 	// GetBalance returns the available USDC.e balance in the CLOB.
 	GetBalance(ctx context.Context) (float64, error)
 
+	// GetTradeableBalance returns the USDC.e balance broken down into the
+	// exchange allowance and the USDC reserved by open BUY orders, so sizing
+	// can use what is actually available to trade.
+	GetTradeableBalance(ctx context.Context) (domain.TradeableBalance, error)
+
 	// IsNegRisk returns true if the given token/market uses the NegRisk adapter.
 	IsNegRisk(ctx context.Context, tokenID string) (bool, error)
 
+	// TickSize returns the minimum price increment of the token's market.
+	// Bids off this grid are rejected by the CLOB.
+	TickSize(ctx context.Context, tokenID string) (float64, error)
+
 	// TokenBalance returns the on-chain ERC-1155 balance (in shares) for a token.
 	// This is the ground truth — if > 0, the order was filled regardless of DB state.
 	TokenBalance(ctx context.Context, tokenID string) (float64, error)
@@ -39,10 +58,61 @@ type MergeExecutor interface {
 	// negRisk indicates if the market uses the NegRisk adapter.
 	MergePositions(ctx context.Context, conditionID string, amount float64, negRisk bool) (domain.MergeResult, error)
 
+	// SupportsNegRisk reports whether MergePositions can merge NegRisk markets
+	// (via the NegRisk adapter). When false, NegRisk markets must not be entered.
+	SupportsNegRisk() bool
+
 	// EstimateGasCostUSD returns the current estimated gas cost in USD for a merge tx.
 	EstimateGasCostUSD(ctx context.Context) (float64, error)
 
+	// EstimateGasCostPOL returns the same estimate in POL, the token gas is paid in.
+	EstimateGasCostPOL(ctx context.Context) (float64, error)
+
+	// GetPOLBalance returns the wallet's native POL balance available for gas.
+	GetPOLBalance(ctx context.Context) (float64, error)
+
 	// EnsureApprovals verifies and sets ERC1155 setApprovalForAll on all three
 	// Polymarket exchange contracts. Should be called on startup.
 	EnsureApprovals(ctx context.Context) error
 }
+
+// ResolutionReader reads whether a condition has resolved on-chain. A
+// MergeExecutor that implements it lets the live engine treat resolution as
+// a fact instead of inferring it from the market's end date or its absence
+// from the scan.
+type ResolutionReader interface {
+	// ConditionResolved reports whether the oracle has reported the
+	// condition: its CTF payoutDenominator is nonzero.
+	ConditionResolved(ctx context.Context, conditionID string) (bool, error)
+}
+
+// NegRiskMerger is a MergeExecutor whose NegRisk adapter merges are switched
+// on by configuration. The live engine enables them on every wallet's merger
+// when Config.AllowNegRisk is set.
+type NegRiskMerger interface {
+	EnableNegRisk(enabled bool)
+}
+
+// Redeemer redeems the tokens of resolved markets. A MergeExecutor that also
+// implements it lets the live engine close positions left in markets that
+// resolved before they could be merged or sold.
+type Redeemer interface {
+	ResolutionReader
+
+	// ConditionPayout reads the on-chain payout of a condition; Resolved is
+	// false until the oracle has reported.
+	ConditionPayout(ctx context.Context, conditionID string) (domain.ConditionPayout, error)
+
+	// RedeemPositions redeems yesShares and noShares of a resolved condition
+	// for USDC.e and returns the transaction and its gas. The engine fills in
+	// the payout and cost basis.
+	RedeemPositions(ctx context.Context, conditionID string, yesShares, noShares float64, negRisk bool) (domain.Redemption, error)
+}
+
+// OrderEventStream pushes fills and cancellations of this wallet's orders in
+// real time (the CLOB user channel).
+type OrderEventStream interface {
+	// Events returns the channel of order events; it is closed when the
+	// stream stops.
+	Events() <-chan domain.LiveOrderEvent
+}
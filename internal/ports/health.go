@@ -0,0 +1,18 @@
+package ports
+
+// Componentes que reportan latidos al HealthReporter.
+const (
+	HealthScan       = "scan"        // ciclo del scanner
+	HealthPaperCycle = "paper_cycle" // RunOnce del paper engine
+	HealthLiveCycle  = "live_cycle"  // RunOnce del live engine
+	HealthCLOB       = "clob"        // última llamada al CLOB
+	HealthRPC        = "rpc"         // último block number pedido al RPC de Polygon
+)
+
+// HealthReporter recibe los latidos del scanner, los engines y los clientes
+// para el endpoint de salud. Debe ser seguro para uso concurrente.
+type HealthReporter interface {
+	// Beat registra el resultado de la última operación de component
+	// (err nil = éxito).
+	Beat(component string, err error)
+}
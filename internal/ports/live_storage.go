@@ -15,29 +15,52 @@ type LiveStorage interface {
 	SaveLiveOrder(ctx context.Context, order domain.LiveOrder) error
 	UpdateLiveOrderStatus(ctx context.Context, localID string, status domain.LiveOrderStatus) error
 	UpdateLiveOrderFill(ctx context.Context, localID string, filledSize, filledPrice float64, status domain.LiveOrderStatus, filledAt *time.Time) error
+	CloseLiveOrder(ctx context.Context, localID string, status domain.LiveOrderStatus, realizedPnL float64) error
+	RetireLiveOrder(ctx context.Context, localID string, status domain.LiveOrderStatus, reason domain.CloseReason) error
 	UpdateLiveOrderQueue(ctx context.Context, localID string, queueAhead float64) error
 	MarkLiveOrderMerged(ctx context.Context, localID string, mergedAt time.Time) error
+	AddLiveOrderMerged(ctx context.Context, localID string, mergedSize float64) error
+	SetLiveOrderRemainder(ctx context.Context, localID string, shares float64) error
 	GetOpenLiveOrders(ctx context.Context) ([]domain.LiveOrder, error)
+	GetLiveOrderByCLOBID(ctx context.Context, clobOrderID string) (*domain.LiveOrder, error)
 	GetLiveOrdersByPair(ctx context.Context, pairID string) ([]domain.LiveOrder, error)
 	GetActiveLiveConditions(ctx context.Context) ([]string, error)
 	GetAllLiveOrders(ctx context.Context, status string) ([]domain.LiveOrder, error)
-	CancelLiveOrdersByCondition(ctx context.Context, conditionID string) error
+	CancelLiveOrdersByCondition(ctx context.Context, conditionID string, reason domain.CloseReason) error
 
 	// Fills
 	SaveLiveFill(ctx context.Context, fill domain.LiveFill) error
+	HasLiveFill(ctx context.Context, orderID, clobTradeID string) (bool, error)
 
 	// Merges
 	SaveMergeResult(ctx context.Context, result domain.MergeResult) error
 	GetMergeResults(ctx context.Context) ([]domain.MergeResult, error)
 
+	// Redemptions of resolved markets' tokens
+	SaveRedemption(ctx context.Context, r domain.Redemption) error
+
 	// Circuit breaker persistence
 	SaveCircuitBreaker(ctx context.Context, cb domain.CircuitBreaker) error
 	LoadCircuitBreaker(ctx context.Context) (domain.CircuitBreaker, error)
 
+	// Rotation cooldowns: markets that may not be re-entered until a given time.
+	SaveLiveCooldown(ctx context.Context, conditionID string, until time.Time, reason string) error
+	GetLiveCooldowns(ctx context.Context) (map[string]time.Time, error)
+
+	// Realized rewards: Polymarket payouts per (date, condition), replacing the
+	// previous value for the same key.
+	SaveLiveReward(ctx context.Context, r domain.LiveReward) error
+
 	// Daily summaries and stats
 	SaveLiveDaily(ctx context.Context, d domain.LiveDailySummary) error
 	GetLiveDailies(ctx context.Context) ([]domain.LiveDailySummary, error)
 	GetLiveStats(ctx context.Context) (domain.LiveStats, error)
+	GetRealizedPnL(ctx context.Context) (float64, error)
+	GetRealizedPnLSince(ctx context.Context, since time.Time) (float64, error)
+
+	// GetLiveMarketTimeline returns every order, fill, merge and paid reward
+	// of one condition, oldest first (--report-market).
+	GetLiveMarketTimeline(ctx context.Context, conditionID string) ([]domain.MarketEvent, error)
 
 	// GetPartialPairs devuelve los pairIDs donde solo un lado (YES o NO) está filled.
 	GetPartialPairs(ctx context.Context) ([]string, error)
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
@@ -13,3 +14,25 @@ type MarketProvider interface {
 	// Pagina automáticamente hasta obtener todos los resultados.
 	FetchSamplingMarkets(ctx context.Context) ([]domain.Market, error)
 }
+
+// MarketInvalidator lo implementan los MarketProvider que cachean la lista
+// de mercados entre ciclos. El scanner le pasa los mercados que ya no tienen
+// orderbook para que fuerce un refresco completo.
+type MarketInvalidator interface {
+	InvalidateMarkets(missing []string)
+}
+
+// HistoricalMarketProvider obtiene mercados pasados con los precios que la
+// API tenía guardados, para reproducir el scanner sobre fechas anteriores.
+type HistoricalMarketProvider interface {
+	// FetchHistoricalMarkets devuelve los mercados con rewards abiertos en
+	// algún momento de [from, to). Pagina automáticamente.
+	FetchHistoricalMarkets(ctx context.Context, from, to time.Time) ([]domain.Market, error)
+}
+
+// MarketUpdateStream entrega cambios de estado de mercados (resolución,
+// cierre, reward) en tiempo real.
+type MarketUpdateStream interface {
+	// Updates devuelve el canal de cambios; se cierra al terminar el stream.
+	Updates() <-chan domain.MarketUpdate
+}
@@ -16,16 +16,29 @@ type PaperStorage interface {
 	MarkPaperOrderResolved(ctx context.Context, orderID string) error
 	MarkPaperOrderMerged(ctx context.Context, orderID string, mergedAt time.Time) error
 	UpdatePaperOrderQueue(ctx context.Context, orderID string, queueAhead float64) error
+	UpdatePaperOrderQueueConsumed(ctx context.Context, orderID string, consumed float64, checkedAt time.Time) error
 	UpdatePaperOrderPartialFill(ctx context.Context, orderID string, filledSize float64, filledPrice float64) error
-	ExpirePaperOrders(ctx context.Context, conditionID string) error
+	ExpirePaperOrders(ctx context.Context, conditionID string, reason domain.CloseReason) error
 	GetOpenPaperOrders(ctx context.Context) ([]domain.VirtualOrder, error) // returns OPEN and PARTIAL
 	GetPaperOrdersByPair(ctx context.Context, pairID string) ([]domain.VirtualOrder, error)
+	GetPaperOrdersByCondition(ctx context.Context, conditionID string) ([]domain.VirtualOrder, error)
 	GetActivePaperConditions(ctx context.Context) ([]string, error)
+	// GetConditionsWithOpenOrders returns, per condition, the sides that
+	// still hold an OPEN, PARTIAL or unmerged FILLED order.
+	GetConditionsWithOpenOrders(ctx context.Context) (map[string]domain.PaperConditionSides, error)
 	GetAllPaperOrders(ctx context.Context, status string) ([]domain.VirtualOrder, error)
+	// GetPaperCooldowns returns the re-entry cooldowns still in force by
+	// condition: cooldown after a rotation or cancellation, for good after
+	// a near-end expiry.
+	GetPaperCooldowns(ctx context.Context, cooldown time.Duration) (map[string]time.Time, error)
 
 	SavePaperFill(ctx context.Context, fill domain.PaperFill) error
 
 	SavePaperDaily(ctx context.Context, d domain.PaperDailySummary) error
 	GetPaperDailies(ctx context.Context) ([]domain.PaperDailySummary, error)
 	GetPaperStats(ctx context.Context) (domain.PaperStats, error)
+
+	// GetPaperMarketTimeline returns every order, fill and merge of one
+	// condition, oldest first (--report-market).
+	GetPaperMarketTimeline(ctx context.Context, conditionID string) ([]domain.MarketEvent, error)
 }
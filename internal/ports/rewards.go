@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/alejandrodnm/polybot/internal/domain"
+)
+
+// RewardsSource reports the liquidity rewards Polymarket actually paid to a
+// wallet, as opposed to the engine's block-accrual estimate.
+type RewardsSource interface {
+	// EarningsForDay returns the rewards earned on the given UTC day, one
+	// entry per market. Days not yet paid out return no entries.
+	EarningsForDay(ctx context.Context, date time.Time) ([]domain.LiveReward, error)
+}
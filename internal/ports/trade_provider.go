@@ -2,11 +2,23 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/alejandrodnm/polybot/internal/domain"
 )
 
 // TradeProvider obtiene trades históricos de un token.
 type TradeProvider interface {
+	// FetchTrades obtiene los trades recientes del token; equivale a
+	// FetchTradesSince con since cero.
 	FetchTrades(ctx context.Context, tokenID string) ([]domain.Trade, error)
+
+	// FetchTradesSince obtiene los trades del token desde since (incluido),
+	// sin repetir IDs. Un since cero devuelve solo las páginas más recientes.
+	FetchTradesSince(ctx context.Context, tokenID string, since time.Time) ([]domain.Trade, error)
+
+	// FetchTradesWindow obtiene los trades del token dentro de [from, to),
+	// paginando hacia atrás en el histórico y sin repetir IDs. Un to cero no
+	// pone límite por arriba.
+	FetchTradesWindow(ctx context.Context, tokenID string, from, to time.Time) ([]domain.Trade, error)
 }
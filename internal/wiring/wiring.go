@@ -0,0 +1,218 @@
+// Package wiring traduce config.Config a las configuraciones de los engines,
+// el scanner y los adapters. Lo usa cmd/polybot al construir las
+// dependencias; config solo expone los valores del YAML.
+package wiring
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alejandrodnm/polybot/config"
+	"github.com/alejandrodnm/polybot/internal/adapters/onchain"
+	"github.com/alejandrodnm/polybot/internal/application/engine"
+	"github.com/alejandrodnm/polybot/internal/application/engine/live"
+	"github.com/alejandrodnm/polybot/internal/application/engine/paper"
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/domain"
+	"github.com/alejandrodnm/polybot/internal/ports"
+)
+
+// FilterConfig devuelve la configuración de filtrado del scanner.
+func FilterConfig(s config.ScannerConfig) scanner.FilterConfig {
+	return scanner.FilterConfig{
+		MinYourDailyReward:   s.MinYourDailyReward,
+		MinRewardScore:       s.MinRewardScore,
+		MaxSpreadTotal:       s.MaxSpreadTotal,
+		SpreadOverrides:      s.SpreadOverrides,
+		MaxCompetition:       s.MaxCompetition,
+		RequireQualifies:     s.RequireQualifies,
+		MinHoursToResolution: s.MinHoursToResolution,
+		OnlyFillsProfit:      s.OnlyFillsProfit,
+		IncludeKeywords:      s.IncludeKeywords,
+		ExcludeKeywords:      s.ExcludeKeywords,
+	}
+}
+
+// AdaptiveInterval devuelve la configuración del intervalo adaptativo del scanner.
+func AdaptiveInterval(s config.ScannerConfig) scanner.AdaptiveIntervalConfig {
+	return scanner.AdaptiveIntervalConfig{
+		MinInterval:       time.Duration(s.AdaptiveMinSeconds) * time.Second,
+		MaxInterval:       time.Duration(s.AdaptiveMaxSeconds) * time.Second,
+		FilledBoostFactor: s.AdaptiveFilledBoost,
+		IdleScaleUp:       s.AdaptiveIdleScaleUp,
+		GoldBusy:          s.AdaptiveGoldBusy,
+	}
+}
+
+// RankFunc devuelve el ranking de oportunidades de scanner.rank_by.
+func RankFunc(s config.ScannerConfig) (scanner.RankFunc, error) {
+	f, err := scanner.ParseRankBy(s.RankBy)
+	if err != nil {
+		return nil, fmt.Errorf("wiring.RankFunc: %w", err)
+	}
+	return f, nil
+}
+
+// Ladder devuelve el ladder de órdenes que comparten los engines paper y live.
+func Ladder(ld config.LadderConfig) engine.LadderConfig {
+	return engine.LadderConfig{
+		NumLevels:        ld.NumLevels,
+		TickSpacing:      ld.TickSpacing,
+		SizeDistribution: ld.SizeDistribution,
+	}
+}
+
+// MarketList construye la lista negra/blanca de mercados. El binario pasa la
+// misma instancia a scanner.FilterConfig.Markets y a los Config de paper y
+// live, de modo que una recarga del fichero afecta a los tres.
+func MarketList(s config.ScannerConfig) (*scanner.MarketListSource, error) {
+	return scanner.NewMarketListSource(marketMatch(s.ExcludeMarkets), marketMatch(s.IncludeMarkets), s.MarketListFile)
+}
+
+// LiveEngineConfig devuelve la configuración del live engine. feeRate y
+// rebatedFeeRate vienen del scanner (fee_rate_default, fee_rate_rebated).
+func LiveEngineConfig(l config.LiveConfig, feeRate, rebatedFeeRate float64) live.Config {
+	return live.Config{
+		OrderSize:                 l.OrderSize,
+		MaxMarkets:                l.MaxMarkets,
+		FeeRate:                   feeRate,
+		RebatedFeeRate:            rebatedFeeRate,
+		InitialCapital:            l.InitialCapital,
+		MaxExposure:               l.MaxExposure,
+		MaxMarketConcentration:    l.MaxMarketConcentration,
+		MinMergeProfit:            l.MinMergeProfit,
+		GasBufferPct:              l.GasBufferPct,
+		MinGasRunwayMerges:        l.MinGasRunwayMerges,
+		DailyLossLimit:            l.DailyLossLimit,
+		MinBalanceUSDC:            l.MinBalanceUSDC,
+		PauseOnLowBalance:         l.PauseOnLowBalance,
+		MinPartialMergeSets:       l.MinPartialMergeSets,
+		MaxPartialHours:           l.MaxPartialHours,
+		UnwindLossTicks:           l.UnwindLossTicks,
+		UnwindFloorPct:            l.UnwindFloorPct,
+		AllowTakerCompletion:      l.AllowTakerCompletion,
+		TakerAfterHours:           l.TakerAfterHours,
+		AllowNegRisk:              l.AllowNegRisk,
+		NoCancelOnExit:            l.NoCancelOnExit,
+		CancelOnExit:              l.CancelOnExit,
+		CancelOrphans:             l.CancelOrphans,
+		ShadowMode:                l.ShadowMode,
+		DryRunPlacement:           l.DryRunPlacement,
+		MinVolume24h:              l.MinVolume24h,
+		MinAskDepthShares:         l.MinAskDepthShares,
+		MaxSpreadPct:              l.MaxSpreadPct,
+		SpreadVarianceMax:         l.SpreadVarianceMax,
+		QueueConservativeMult:     l.QueueConservativeMult,
+		NearEndHours:              l.NearEndHours,
+		MaxBidTickUp:              l.MaxBidTickUp,
+		StaleHours:                l.StaleHours,
+		CompetitionMult:           l.CompetitionMult,
+		PartialAlertHours:         l.PartialAlertHours,
+		CircuitBreakerLosses:      l.CircuitBreakerLosses,
+		CircuitBreakerCooldown:    time.Duration(l.CircuitBreakerCooldownMins) * time.Minute,
+		RotationCooldown:          time.Duration(l.RotationCooldownMins) * time.Minute,
+		Reprice:                   l.Reprice,
+		RepriceTicks:              l.RepriceTicks,
+		RepriceQueueMult:          l.RepriceQueueMult,
+		CircuitBreakerDrawdownPct: l.CircuitBreakerDrawdownPct,
+		Kelly:                     kelly(l.Kelly),
+	}
+}
+
+// MergeRetryPolicy devuelve los reintentos de merge (merge_max_attempts,
+// merge_gas_bump). El binario la pasa a (*onchain.MergeClient).SetRetryPolicy
+// de cada wallet; el backoff y el timeout de pendiente quedan en sus defaults.
+func MergeRetryPolicy(l config.LiveConfig) onchain.MergeRetryPolicy {
+	return onchain.MergeRetryPolicy{
+		MaxAttempts: l.MergeMaxAttempts,
+		GasBump:     l.MergeGasBump,
+	}
+}
+
+// PaperEngineConfig devuelve la configuración del paper engine. El tamaño de
+// orden y los fees (estándar y rebajado) vienen del scanner.
+func PaperEngineConfig(p config.PaperConfig, orderSize, feeRate, rebatedFeeRate float64) paper.Config {
+	return paper.Config{
+		OrderSize:            orderSize,
+		MaxMarkets:           p.MaxMarkets,
+		FeeRate:              feeRate,
+		RebatedFeeRate:       rebatedFeeRate,
+		InitialCapital:       p.InitialCapital,
+		MinOrderSize:         p.MinOrderSize,
+		NearEndHours:         p.NearEndHours,
+		MaxBidTickUp:         p.MaxBidTickUp,
+		StaleHours:           p.StaleHours,
+		CompetitionMult:      p.CompetitionMult,
+		PartialAlertHours:    p.PartialAlertHours,
+		MergeGasCost:         p.MergeGasCost,
+		RotationCooldown:     time.Duration(p.RotationCooldownMins) * time.Minute,
+		TradeLookbackHours:   p.TradeLookbackHours,
+		SizeDominantFraction: p.SizeDominantFraction,
+		MaxLevelMultiple:     p.MaxLevelMultiple,
+		Kelly:                kelly(p.Kelly),
+		ExpireOnExit:         p.ExpireOnExit == nil || *p.ExpireOnExit,
+	}
+}
+
+// SweepVariants devuelve la configuración del paper engine de cada variante
+// de --sweep, en orden. El store de cada una lo abre el caller.
+func SweepVariants(p config.PaperConfig, orderSize, feeRate, rebatedFeeRate float64) []paper.SweepVariant {
+	out := make([]paper.SweepVariant, 0, len(p.Sweep))
+	for _, v := range p.Sweep {
+		cfg := PaperEngineConfig(p, orderSize, feeRate, rebatedFeeRate)
+		if v.OrderSize > 0 {
+			cfg.OrderSize = v.OrderSize
+		}
+		if v.MaxMarkets > 0 {
+			cfg.MaxMarkets = v.MaxMarkets
+		}
+		if v.StaleHours > 0 {
+			cfg.StaleHours = v.StaleHours
+		}
+		out = append(out, paper.SweepVariant{Name: v.Name, Config: cfg})
+	}
+	return out
+}
+
+// PriceOracle devuelve la configuración del oracle de precios POL/USD. La API
+// key de CoinMarketCap se lee del entorno.
+func PriceOracle(o config.OracleConfig) onchain.OracleConfig {
+	return onchain.OracleConfig{
+		Providers:        o.Providers,
+		CoinMarketCapKey: os.Getenv(o.CoinMarketCapKeyEnv),
+		UniswapPool:      o.UniswapPool,
+		ChainlinkFeed:    o.ChainlinkFeed,
+		TWAPWindow:       time.Duration(o.TWAPMinutes) * time.Minute,
+	}
+}
+
+// HealthMaxAges devuelve los umbrales por componente para
+// httpapi.HealthConfig. El umbral de ciclo se aplica solo al engine en marcha
+// (live o paper).
+func HealthMaxAges(h config.HealthConfig, live bool) map[string]time.Duration {
+	cycle := ports.HealthPaperCycle
+	if live {
+		cycle = ports.HealthLiveCycle
+	}
+	ages := make(map[string]time.Duration, 4)
+	for name, secs := range map[string]int{
+		ports.HealthScan: h.MaxScanAgeSeconds,
+		cycle:            h.MaxCycleAgeSeconds,
+		ports.HealthCLOB: h.MaxCLOBAgeSeconds,
+		ports.HealthRPC:  h.MaxRPCAgeSeconds,
+	} {
+		if secs > 0 {
+			ages[name] = time.Duration(secs) * time.Second
+		}
+	}
+	return ages
+}
+
+func kelly(k config.KellyConfig) engine.KellyConfig {
+	return engine.KellyConfig{Multiplier: k.Multiplier, Min: k.Min, Max: k.Max, WarmupMerges: k.WarmupMerges}
+}
+
+func marketMatch(m config.MarketMatchConfig) domain.MarketMatch {
+	return domain.MarketMatch{Slugs: m.Slugs, ConditionIDs: m.ConditionIDs, Questions: m.Questions}
+}
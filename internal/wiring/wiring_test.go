@@ -0,0 +1,69 @@
+package wiring_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alejandrodnm/polybot/config"
+	"github.com/alejandrodnm/polybot/internal/adapters/onchain"
+	"github.com/alejandrodnm/polybot/internal/application/scanner"
+	"github.com/alejandrodnm/polybot/internal/wiring"
+)
+
+// config validates rank_by and oracle.providers against its own name lists;
+// these tests keep them in step with scanner and onchain.
+
+func TestRankByNames_MatchScanner(t *testing.T) {
+	for _, name := range config.RankByNames {
+		_, err := wiring.RankFunc(config.ScannerConfig{RankBy: name})
+		assert.NoError(t, err, "rank_by %q", name)
+	}
+	for _, name := range []string{scanner.RankVelocity, scanner.RankReward, scanner.RankFillCost, scanner.RankBreakEven} {
+		assert.Contains(t, config.RankByNames, name)
+	}
+}
+
+func TestOracleProviderNames_MatchOnchain(t *testing.T) {
+	for _, name := range config.OracleProviderNames {
+		_, err := onchain.NewPriceOracle(nil, onchain.OracleConfig{Providers: []string{name}})
+		if err != nil {
+			assert.NotContains(t, err.Error(), "unknown provider", "provider %q", name)
+		}
+	}
+	assert.ElementsMatch(t, config.OracleProviderNames, []string{
+		onchain.ProviderChainlink, onchain.ProviderCoinGecko, onchain.ProviderCoinMarketCap, onchain.ProviderUniswap,
+	})
+}
+
+func TestLoad_DefaultOracleProvidersMatchOnchain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("scanner:\n  interval_seconds: 60\n"), 0o600))
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, onchain.DefaultOracleConfig().Providers, cfg.Oracle.Providers)
+}
+
+func TestSweepVariants_OverrideThePaperConfig(t *testing.T) {
+	p := config.PaperConfig{
+		MaxMarkets:           10,
+		StaleHours:           4,
+		RotationCooldownMins: 120,
+		Sweep:                []config.SweepVariantConfig{{Name: "small", OrderSize: 20}, {Name: "wide", MaxMarkets: 30}},
+	}
+
+	got := wiring.SweepVariants(p, 50, 0.001, 0)
+	require.Len(t, got, 2)
+	assert.Equal(t, "small", got[0].Name)
+	assert.InDelta(t, 20, got[0].Config.OrderSize, 1e-9)
+	assert.Equal(t, 10, got[0].Config.MaxMarkets)
+	assert.InDelta(t, 50, got[1].Config.OrderSize, 1e-9)
+	assert.Equal(t, 30, got[1].Config.MaxMarkets)
+	assert.Equal(t, 2*time.Hour, got[1].Config.RotationCooldown)
+	assert.True(t, got[1].Config.ExpireOnExit, "an unset expire_on_exit expires paper orders")
+}